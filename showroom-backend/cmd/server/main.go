@@ -17,9 +17,12 @@ import (
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/handlers/admin"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/handlers/auth"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/handlers/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/notify"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/implementations"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/routes"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/scheduler"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services"
 	masterService "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services/master"
 	productService "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services/products"
@@ -34,6 +37,9 @@ func main() {
 
 	// Load configuration
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
 
 	// Initialize database
 	if err := initializeDatabase(cfg); err != nil {
@@ -47,6 +53,11 @@ func main() {
 	// Setup routes
 	router := dependencies.router.SetupRoutes()
 
+	// Start background jobs; they can still be triggered manually when disabled
+	if cfg.Jobs.Enabled {
+		dependencies.jobScheduler.Start()
+	}
+
 	// Configure server
 	server := &http.Server{
 		Addr:         fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port),
@@ -83,6 +94,11 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	// Wait for running background jobs
+	if err := dependencies.jobScheduler.Stop(ctx); err != nil {
+		log.Printf("Background jobs did not stop cleanly: %v", err)
+	}
+
 	log.Println("Server exited")
 }
 
@@ -104,7 +120,32 @@ type Dependencies struct {
 	goodsReceiptDetailRepo      interfaces.GoodsReceiptDetailRepository
 	stockMovementRepo           interfaces.StockMovementRepository
 	stockAdjustmentRepo         interfaces.StockAdjustmentRepository
-	supplierPaymentRepo         interfaces.SupplierPaymentRepository
+	supplierInvoiceRepo         interfaces.SupplierInvoiceRepository
+	paymentVoucherRepo          interfaces.PaymentVoucherRepository
+	purchaseReturnRepo          interfaces.PurchaseReturnRepository
+	supplierDebitNoteRepo       interfaces.SupplierDebitNoteRepository
+	landedCostRepo              interfaces.LandedCostRepository
+	blanketOrderRepo            interfaces.BlanketOrderRepository
+	supplierPriceListRepo       interfaces.SupplierPriceListRepository
+	supplierScorecardRepo       interfaces.SupplierScorecardRepository
+	paymentTermRepo             interfaces.PaymentTermRepository
+	jobRunRepo                  interfaces.JobRunRepository
+	paymentRunRepo              interfaces.PaymentRunRepository
+	bankStatementRepo           interfaces.BankStatementRepository
+	glAccountRepo               interfaces.GLAccountRepository
+	journalRepo                 interfaces.JournalRepository
+	taxCodeRepo                 interfaces.TaxCodeRepository
+	salesInvoiceRepo            interfaces.SalesInvoiceRepository
+	exchangeRateRepo            interfaces.ExchangeRateRepository
+	customerReceiptRepo         interfaces.CustomerReceiptRepository
+	rolePermissionRepo          interfaces.RolePermissionRepository
+	twoFactorRepo               interfaces.TwoFactorRepository
+	loginChallengeRepo          interfaces.LoginChallengeRepository
+	loginAttemptRepo            interfaces.LoginAttemptRepository
+	passwordResetRepo           interfaces.PasswordResetRepository
+	apiKeyRepo                  interfaces.APIKeyRepository
+	signingKeyRepo              interfaces.SigningKeyRepository
+	auditLogRepo                interfaces.AuditLogRepository
 	
 	// Services
 	authService                 *services.AuthService
@@ -120,7 +161,29 @@ type Dependencies struct {
 	stockService                *productService.StockService
 	goodsReceiptService         *productService.GoodsReceiptService
 	stockAdjustmentService      *productService.StockAdjustmentService
-	supplierPaymentService      *productService.SupplierPaymentService
+	supplierInvoiceService      *productService.SupplierInvoiceService
+	paymentVoucherService       *productService.PaymentVoucherService
+	purchaseReturnService       *productService.PurchaseReturnService
+	landedCostService           *productService.LandedCostService
+	supplierPriceListService    *productService.SupplierPriceListService
+	supplierScorecardService    *productService.SupplierScorecardService
+	paymentTermService          *productService.PaymentTermService
+	paymentRunService           *productService.PaymentRunService
+	bankReconciliationService   *productService.BankReconciliationService
+	generalLedgerService        *productService.GeneralLedgerService
+	taxService                  *productService.TaxService
+	salesInvoiceService         *productService.SalesInvoiceService
+	exchangeRateService         *productService.ExchangeRateService
+	customerAccountService      *productService.CustomerAccountService
+	permissionService           *services.PermissionService
+	twoFactorService            *services.TwoFactorService
+	loginAttemptService         *services.LoginAttemptService
+	passwordResetService        *services.PasswordResetService
+	passwordService             *services.PasswordService
+	apiKeyService               *services.APIKeyService
+	signingKeyService           *services.SigningKeyService
+	auditService                *services.AuditService
+	sessionService              *services.SessionService
 	
 	// Handlers
 	authHandler                 *auth.Handler
@@ -135,10 +198,33 @@ type Dependencies struct {
 	goodsReceiptHandler         *products.GoodsReceiptHandler
 	stockMovementHandler        *products.StockMovementHandler
 	stockAdjustmentHandler      *products.StockAdjustmentHandler
-	supplierPaymentHandler      *products.SupplierPaymentHandler
+	supplierInvoiceHandler      *products.SupplierInvoiceHandler
+	paymentVoucherHandler       *products.PaymentVoucherHandler
+	purchaseReturnHandler       *products.PurchaseReturnHandler
+	landedCostHandler           *products.LandedCostHandler
+	supplierPriceListHandler    *products.SupplierPriceListHandler
+	supplierScorecardHandler    *products.SupplierScorecardHandler
+	paymentTermHandler          *products.PaymentTermHandler
+	jobHandler                  *admin.JobHandler
+	paymentRunHandler           *products.PaymentRunHandler
+	bankStatementHandler        *products.BankStatementHandler
+	generalLedgerHandler        *products.GeneralLedgerHandler
+	taxHandler                  *products.TaxHandler
+	salesInvoiceHandler         *products.SalesInvoiceHandler
+	exchangeRateHandler         *products.ExchangeRateHandler
+	customerAccountHandler      *products.CustomerAccountHandler
+	roleHandler                 *admin.RoleHandler
+	twoFactorHandler            *auth.TwoFactorHandler
+	loginAttemptHandler         *admin.LoginAttemptHandler
+	passwordResetHandler        *auth.PasswordResetHandler
+	passwordPolicyHandler       *auth.PasswordPolicyHandler
+	apiKeyHandler               *admin.APIKeyHandler
+	auditHandler                *admin.AuditHandler
+	sessionHandler              *auth.SessionHandler
 	
 	// Utils
 	jwtManager                  *utils.JWTManager
+	jobScheduler                *scheduler.Scheduler
 	router                      *routes.Router
 }
 
@@ -169,6 +255,7 @@ func initializeDependencies(cfg *config.Config) *Dependencies {
 	// Initialize repositories
 	userRepo := implementations.NewUserRepository(db)
 	sessionRepo := implementations.NewUserSessionRepository(db)
+	refreshTokenRepo := implementations.NewRefreshTokenRepository(db)
 	customerRepo := implementations.NewCustomerRepository(db)
 	supplierRepo := implementations.NewSupplierRepository(db)
 	vehicleBrandRepo := implementations.NewVehicleBrandRepository(db)
@@ -184,14 +271,91 @@ func initializeDependencies(cfg *config.Config) *Dependencies {
 	goodsReceiptDetailRepo := implementations.NewGoodsReceiptDetailRepository(db)
 	stockMovementRepo := implementations.NewStockMovementRepository(db)
 	stockAdjustmentRepo := implementations.NewStockAdjustmentRepository(db)
-	supplierPaymentRepo := implementations.NewSupplierPaymentRepository(db)
+	supplierInvoiceRepo := implementations.NewSupplierInvoiceRepository(db)
+	paymentVoucherRepo := implementations.NewPaymentVoucherRepository(db)
+	purchaseReturnRepo := implementations.NewPurchaseReturnRepository(db)
+	supplierDebitNoteRepo := implementations.NewSupplierDebitNoteRepository(db)
+	landedCostRepo := implementations.NewLandedCostRepository(db)
+	blanketOrderRepo := implementations.NewBlanketOrderRepository(db)
+	supplierPriceListRepo := implementations.NewSupplierPriceListRepository(db)
+	supplierScorecardRepo := implementations.NewSupplierScorecardRepository(db)
+	paymentTermRepo := implementations.NewPaymentTermRepository(db)
+	jobRunRepo := implementations.NewJobRunRepository(db)
+	paymentRunRepo := implementations.NewPaymentRunRepository(db)
+	bankStatementRepo := implementations.NewBankStatementRepository(db)
+	glAccountRepo := implementations.NewGLAccountRepository(db)
+	journalRepo := implementations.NewJournalRepository(db)
+	taxCodeRepo := implementations.NewTaxCodeRepository(db)
+	salesInvoiceRepo := implementations.NewSalesInvoiceRepository(db)
+	exchangeRateRepo := implementations.NewExchangeRateRepository(db)
+	customerReceiptRepo := implementations.NewCustomerReceiptRepository(db)
+	rolePermissionRepo := implementations.NewRolePermissionRepository(db)
+	twoFactorRepo := implementations.NewTwoFactorRepository(db)
+	loginChallengeRepo := implementations.NewLoginChallengeRepository(db)
+	loginAttemptRepo := implementations.NewLoginAttemptRepository(db)
+	passwordResetRepo := implementations.NewPasswordResetRepository(db)
+	apiKeyRepo := implementations.NewAPIKeyRepository(db)
+	signingKeyRepo := implementations.NewSigningKeyRepository(db)
+	auditLogRepo := implementations.NewAuditLogRepository(db)
 
-	// Initialize JWT manager
-	jwtManager := utils.NewJWTManager(cfg.JWT.SecretKey, cfg.JWT.GetExpiration())
+	// Initialize JWT manager. HS256 signs with the shared secret; RS256 and EdDSA sign with
+	// rotating keys stored in the database.
+	var signingKeyService *services.SigningKeyService
+	var jwtManager *utils.JWTManager
+	if cfg.JWT.SigningAlgorithm == utils.AlgorithmHS256 {
+		jwtManager = utils.NewJWTManager(cfg.JWT.SecretKey, cfg.JWT.GetAccessTokenExpiration())
+	} else {
+		signingKeyService = services.NewSigningKeyService(signingKeyRepo, cfg.JWT.SigningAlgorithm, cfg.JWT.SecretKey, cfg.JWT.GetAccessTokenExpiration(), cfg.JWT.GetKeyRotationInterval())
+		jwtManager = utils.NewKeySourceJWTManager(signingKeyService, cfg.JWT.GetAccessTokenExpiration())
+		if err := signingKeyService.EnsureKey(context.Background()); err != nil {
+			log.Fatalf("Failed to create signing key: %v", err)
+		}
+		if err := jwtManager.Reload(context.Background()); err != nil {
+			log.Fatalf("Failed to load signing keys: %v", err)
+		}
+	}
 
 	// Initialize services
-	authService := services.NewAuthService(userRepo, sessionRepo, jwtManager)
-	userService := services.NewUserService(userRepo, sessionRepo)
+	twoFactorPolicy, err := user.NewTwoFactorPolicy(cfg.Auth.GetTwoFactorRequiredRoles())
+	if err != nil {
+		log.Fatalf("Invalid two-factor policy: %v", err)
+	}
+	twoFactorService := services.NewTwoFactorService(twoFactorRepo, userRepo, twoFactorPolicy, cfg.Auth.TwoFactorIssuer)
+	loginAttemptService := services.NewLoginAttemptService(loginAttemptRepo, userRepo, user.LoginThrottlePolicy{
+		MaxFailures:   cfg.Auth.LoginMaxFailures,
+		Lockout:       time.Duration(cfg.Auth.LoginLockoutMinutes) * time.Minute,
+		IPMaxFailures: cfg.Auth.LoginIPMaxFailures,
+		IPWindow:      time.Duration(cfg.Auth.LoginIPWindowMinutes) * time.Minute,
+	})
+	notifier, err := notify.New(cfg.Notifier)
+	if err != nil {
+		log.Fatalf("Invalid notifier configuration: %v", err)
+	}
+	passwordService := services.NewPasswordService(userRepo, sessionRepo, utils.PasswordPolicy{
+		MinLength:     cfg.Password.MinLength,
+		RequireUpper:  cfg.Password.RequireUpper,
+		RequireLower:  cfg.Password.RequireLower,
+		RequireDigit:  cfg.Password.RequireDigit,
+		RequireSymbol: cfg.Password.RequireSymbol,
+		RejectCommon:  cfg.Password.RejectCommon,
+		HistoryCount:  cfg.Password.HistoryCount,
+		MaxAgeDays:    cfg.Password.MaxAgeDays,
+	})
+	sessionRoleLimits, err := cfg.Auth.GetSessionRoleLimits()
+	if err != nil {
+		log.Fatalf("Invalid session policy: %v", err)
+	}
+	sessionPolicy, err := user.NewSessionPolicy(cfg.Auth.GetSessionIdleTimeout(), cfg.Auth.SessionMaxConcurrent, sessionRoleLimits)
+	if err != nil {
+		log.Fatalf("Invalid session policy: %v", err)
+	}
+	sessionService := services.NewSessionService(sessionRepo, sessionPolicy)
+	passwordResetService := services.NewPasswordResetService(userRepo, sessionRepo, passwordResetRepo, loginAttemptService, passwordService, notifier, cfg.Auth.PasswordResetURL, cfg.Auth.GetPasswordResetTokenLifetime())
+	authService := services.NewAuthService(userRepo, sessionRepo, refreshTokenRepo, loginChallengeRepo, twoFactorService, loginAttemptService, passwordService, sessionService, jwtManager, cfg.JWT.GetExpiration())
+	userService := services.NewUserService(userRepo, sessionRepo, passwordService)
+	permissionService := services.NewPermissionService(rolePermissionRepo)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo, userRepo, permissionService)
+	auditService := services.NewAuditService(auditLogRepo)
 	customerService := masterService.NewCustomerService(customerRepo)
 	supplierService := masterService.NewSupplierService(supplierRepo)
 	vehicleBrandService := masterService.NewVehicleBrandService(vehicleBrandRepo)
@@ -207,6 +371,10 @@ func initializeDependencies(cfg *config.Config) *Dependencies {
 		productRepo,
 		goodsReceiptRepo,
 		stockMovementRepo,
+		blanketOrderRepo,
+		supplierPriceListRepo,
+		taxCodeRepo,
+		exchangeRateRepo,
 	)
 	stockService := productService.NewStockService(
 		stockMovementRepo,
@@ -216,19 +384,100 @@ func initializeDependencies(cfg *config.Config) *Dependencies {
 	goodsReceiptService := productService.NewGoodsReceiptService(
 		goodsReceiptRepo,
 		goodsReceiptDetailRepo,
+		purchaseOrderRepo,
 		purchaseOrderDetailRepo,
 		stockMovementRepo,
 		productRepo,
+		landedCostRepo,
+		journalRepo,
+		exchangeRateRepo,
 	)
 	stockAdjustmentService := productService.NewStockAdjustmentService(
 		stockAdjustmentRepo,
 		stockMovementRepo,
 		productRepo,
 	)
-	supplierPaymentService := productService.NewSupplierPaymentService(
-		supplierPaymentRepo,
+	supplierInvoiceService := productService.NewSupplierInvoiceService(
+		supplierInvoiceRepo,
 		purchaseOrderRepo,
+		supplierRepo,
+		paymentTermRepo,
+		exchangeRateRepo,
 	)
+	paymentVoucherService := productService.NewPaymentVoucherService(
+		paymentVoucherRepo,
+		supplierInvoiceRepo,
+		supplierRepo,
+		exchangeRateRepo,
+	)
+	purchaseReturnService := productService.NewPurchaseReturnService(
+		purchaseReturnRepo,
+		supplierDebitNoteRepo,
+		goodsReceiptRepo,
+		goodsReceiptDetailRepo,
+		purchaseOrderRepo,
+		stockMovementRepo,
+		productRepo,
+	)
+	landedCostService := productService.NewLandedCostService(
+		landedCostRepo,
+		goodsReceiptRepo,
+		goodsReceiptDetailRepo,
+		stockMovementRepo,
+		productRepo,
+	)
+	supplierPriceListService := productService.NewSupplierPriceListService(
+		supplierPriceListRepo,
+		supplierRepo,
+		productRepo,
+	)
+	supplierScorecardService := productService.NewSupplierScorecardService(
+		supplierScorecardRepo,
+		supplierRepo,
+	)
+	paymentTermService := productService.NewPaymentTermService(paymentTermRepo)
+	bankFileLayout, err := utils.ParseFixedWidthLayout(cfg.BankFile.FixedWidthLayout)
+	if err != nil {
+		log.Fatalf("Invalid bank file layout: %v", err)
+	}
+	paymentRunService := productService.NewPaymentRunService(
+		paymentRunRepo,
+		supplierRepo,
+		cfg.BankFile.Currency,
+		bankFileLayout,
+	)
+	bankReconciliationService := productService.NewBankReconciliationService(bankStatementRepo)
+	generalLedgerService := productService.NewGeneralLedgerService(glAccountRepo, journalRepo)
+	taxService := productService.NewTaxService(taxCodeRepo, salesInvoiceRepo)
+	salesInvoiceService := productService.NewSalesInvoiceService(
+		salesInvoiceRepo,
+		customerRepo,
+		productRepo,
+		taxCodeRepo,
+		paymentTermRepo,
+	)
+	exchangeRateService := productService.NewExchangeRateService(exchangeRateRepo)
+	customerAccountService := productService.NewCustomerAccountService(customerReceiptRepo, salesInvoiceRepo, customerRepo)
+
+	// Initialize background job scheduler
+	jobLocation, err := time.LoadLocation(cfg.Database.Timezone)
+	if err != nil {
+		log.Printf("Unknown timezone %s for background jobs, using local time", cfg.Database.Timezone)
+		jobLocation = time.Local
+	}
+	jobScheduler := scheduler.NewScheduler(db, jobRunRepo, jobLocation)
+	if err := scheduler.RegisterDefaultJobs(
+		jobScheduler,
+		cfg.Jobs,
+		cfg.JWT.GetExpiration(),
+		cfg.Auth.GetSessionIdleTimeout(),
+		supplierInvoiceService,
+		sessionRepo,
+		signingKeyService,
+		jwtManager,
+	); err != nil {
+		log.Fatalf("Failed to register background jobs: %v", err)
+	}
 
 	// Initialize handlers
 	authHandler := auth.NewHandler(authService)
@@ -245,7 +494,29 @@ func initializeDependencies(cfg *config.Config) *Dependencies {
 	goodsReceiptHandler := products.NewGoodsReceiptHandler(goodsReceiptService)
 	stockMovementHandler := products.NewStockMovementHandler(stockService)
 	stockAdjustmentHandler := products.NewStockAdjustmentHandler(stockAdjustmentService)
-	supplierPaymentHandler := products.NewSupplierPaymentHandler(supplierPaymentService)
+	supplierInvoiceHandler := products.NewSupplierInvoiceHandler(supplierInvoiceService)
+	paymentVoucherHandler := products.NewPaymentVoucherHandler(paymentVoucherService)
+	purchaseReturnHandler := products.NewPurchaseReturnHandler(purchaseReturnService)
+	landedCostHandler := products.NewLandedCostHandler(landedCostService)
+	supplierPriceListHandler := products.NewSupplierPriceListHandler(supplierPriceListService)
+	supplierScorecardHandler := products.NewSupplierScorecardHandler(supplierScorecardService)
+	paymentTermHandler := products.NewPaymentTermHandler(paymentTermService)
+	jobHandler := admin.NewJobHandler(jobScheduler)
+	paymentRunHandler := products.NewPaymentRunHandler(paymentRunService)
+	bankStatementHandler := products.NewBankStatementHandler(bankReconciliationService)
+	generalLedgerHandler := products.NewGeneralLedgerHandler(generalLedgerService)
+	taxHandler := products.NewTaxHandler(taxService)
+	salesInvoiceHandler := products.NewSalesInvoiceHandler(salesInvoiceService)
+	exchangeRateHandler := products.NewExchangeRateHandler(exchangeRateService)
+	customerAccountHandler := products.NewCustomerAccountHandler(customerAccountService)
+	roleHandler := admin.NewRoleHandler(permissionService)
+	twoFactorHandler := auth.NewTwoFactorHandler(twoFactorService)
+	loginAttemptHandler := admin.NewLoginAttemptHandler(loginAttemptService)
+	passwordResetHandler := auth.NewPasswordResetHandler(passwordResetService)
+	passwordPolicyHandler := auth.NewPasswordPolicyHandler(passwordService)
+	apiKeyHandler := admin.NewAPIKeyHandler(apiKeyService)
+	auditHandler := admin.NewAuditHandler(auditService)
+	sessionHandler := auth.NewSessionHandler(sessionService)
 
 	// Initialize router
 	router := routes.NewRouter(
@@ -261,7 +532,31 @@ func initializeDependencies(cfg *config.Config) *Dependencies {
 		goodsReceiptHandler,
 		stockMovementHandler,
 		stockAdjustmentHandler,
-		supplierPaymentHandler,
+		supplierInvoiceHandler,
+		paymentVoucherHandler,
+		purchaseReturnHandler,
+		landedCostHandler,
+		supplierPriceListHandler,
+		supplierScorecardHandler,
+		paymentTermHandler,
+		jobHandler,
+		paymentRunHandler,
+		bankStatementHandler,
+		generalLedgerHandler,
+		taxHandler,
+		salesInvoiceHandler,
+		exchangeRateHandler,
+		customerAccountHandler,
+		roleHandler,
+		twoFactorHandler,
+		loginAttemptHandler,
+		passwordResetHandler,
+		passwordPolicyHandler,
+		apiKeyHandler,
+		auditHandler,
+		sessionHandler,
+		permissionService,
+		apiKeyService,
 		jwtManager,
 		sessionRepo,
 		cfg,
@@ -283,7 +578,32 @@ func initializeDependencies(cfg *config.Config) *Dependencies {
 		goodsReceiptDetailRepo:     goodsReceiptDetailRepo,
 		stockMovementRepo:          stockMovementRepo,
 		stockAdjustmentRepo:        stockAdjustmentRepo,
-		supplierPaymentRepo:        supplierPaymentRepo,
+		supplierInvoiceRepo:        supplierInvoiceRepo,
+		paymentVoucherRepo:         paymentVoucherRepo,
+		purchaseReturnRepo:         purchaseReturnRepo,
+		supplierDebitNoteRepo:      supplierDebitNoteRepo,
+		landedCostRepo:             landedCostRepo,
+		blanketOrderRepo:           blanketOrderRepo,
+		supplierPriceListRepo:      supplierPriceListRepo,
+		supplierScorecardRepo:      supplierScorecardRepo,
+		paymentTermRepo:            paymentTermRepo,
+		jobRunRepo:                 jobRunRepo,
+		paymentRunRepo:             paymentRunRepo,
+		bankStatementRepo:          bankStatementRepo,
+		glAccountRepo:              glAccountRepo,
+		journalRepo:                journalRepo,
+		taxCodeRepo:                taxCodeRepo,
+		salesInvoiceRepo:           salesInvoiceRepo,
+		exchangeRateRepo:           exchangeRateRepo,
+		customerReceiptRepo:        customerReceiptRepo,
+		rolePermissionRepo:         rolePermissionRepo,
+		twoFactorRepo:              twoFactorRepo,
+		loginChallengeRepo:         loginChallengeRepo,
+		loginAttemptRepo:           loginAttemptRepo,
+		passwordResetRepo:          passwordResetRepo,
+		apiKeyRepo:                 apiKeyRepo,
+		signingKeyRepo:             signingKeyRepo,
+		auditLogRepo:               auditLogRepo,
 		authService:                authService,
 		userService:                userService,
 		customerService:            customerService,
@@ -297,7 +617,29 @@ func initializeDependencies(cfg *config.Config) *Dependencies {
 		stockService:               stockService,
 		goodsReceiptService:        goodsReceiptService,
 		stockAdjustmentService:     stockAdjustmentService,
-		supplierPaymentService:     supplierPaymentService,
+		supplierInvoiceService:     supplierInvoiceService,
+		paymentVoucherService:      paymentVoucherService,
+		purchaseReturnService:      purchaseReturnService,
+		landedCostService:          landedCostService,
+		supplierPriceListService:   supplierPriceListService,
+		supplierScorecardService:   supplierScorecardService,
+		paymentTermService:         paymentTermService,
+		paymentRunService:          paymentRunService,
+		bankReconciliationService:  bankReconciliationService,
+		generalLedgerService:       generalLedgerService,
+		taxService:                 taxService,
+		salesInvoiceService:        salesInvoiceService,
+		exchangeRateService:        exchangeRateService,
+		customerAccountService:     customerAccountService,
+		permissionService:          permissionService,
+		twoFactorService:           twoFactorService,
+		loginAttemptService:        loginAttemptService,
+		passwordResetService:       passwordResetService,
+		passwordService:            passwordService,
+		apiKeyService:              apiKeyService,
+		signingKeyService:          signingKeyService,
+		auditService:               auditService,
+		sessionService:             sessionService,
 		authHandler:                authHandler,
 		adminHandler:               adminHandler,
 		customerHandler:            customerHandler,
@@ -310,8 +652,31 @@ func initializeDependencies(cfg *config.Config) *Dependencies {
 		goodsReceiptHandler:        goodsReceiptHandler,
 		stockMovementHandler:       stockMovementHandler,
 		stockAdjustmentHandler:     stockAdjustmentHandler,
-		supplierPaymentHandler:     supplierPaymentHandler,
+		supplierInvoiceHandler:     supplierInvoiceHandler,
+		paymentVoucherHandler:      paymentVoucherHandler,
+		purchaseReturnHandler:      purchaseReturnHandler,
+		landedCostHandler:          landedCostHandler,
+		supplierPriceListHandler:   supplierPriceListHandler,
+		supplierScorecardHandler:   supplierScorecardHandler,
+		paymentTermHandler:         paymentTermHandler,
+		jobHandler:                 jobHandler,
+		paymentRunHandler:          paymentRunHandler,
+		bankStatementHandler:       bankStatementHandler,
+		generalLedgerHandler:       generalLedgerHandler,
+		taxHandler:                 taxHandler,
+		salesInvoiceHandler:        salesInvoiceHandler,
+		exchangeRateHandler:        exchangeRateHandler,
+		customerAccountHandler:     customerAccountHandler,
+		roleHandler:                roleHandler,
+		twoFactorHandler:           twoFactorHandler,
+		loginAttemptHandler:        loginAttemptHandler,
+		passwordResetHandler:       passwordResetHandler,
+		passwordPolicyHandler:      passwordPolicyHandler,
+		apiKeyHandler:              apiKeyHandler,
+		auditHandler:               auditHandler,
+		sessionHandler:             sessionHandler,
 		jwtManager:                 jwtManager,
+		jobScheduler:               jobScheduler,
 		router:                     router,
 	}
 }
\ No newline at end of file
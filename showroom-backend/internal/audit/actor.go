@@ -0,0 +1,44 @@
+// Package audit carries the actor of a request down to the database, where triggers record every
+// change to audited tables in the audit log together with who made it.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Actor identifies who makes a change. Changes made without an actor, such as those of
+// background jobs and migrations, are recorded as made by the system.
+type Actor struct {
+	UserID    int    `json:"user_id,omitempty"`
+	SessionID int    `json:"session_id,omitempty"`
+	APIKeyID  int    `json:"api_key_id,omitempty"`
+	IPAddress string `json:"ip_address,omitempty"`
+}
+
+type actorKey struct{}
+
+// WithActor returns a context whose database changes are recorded as made by actor
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor of a context, if any
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
+
+// setting encodes the actor of a context for the app.audit_actor database setting, empty when
+// there is none
+func setting(ctx context.Context) string {
+	actor, ok := ActorFromContext(ctx)
+	if !ok || actor == (Actor{}) {
+		return ""
+	}
+	data, err := json.Marshal(actor)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
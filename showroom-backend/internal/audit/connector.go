@@ -0,0 +1,155 @@
+package audit
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/lib/pq"
+)
+
+// setActorQuery stores the actor on the database session for the audit triggers. It is a
+// session setting rather than a transaction one so that statements outside transactions see it.
+const setActorQuery = "SELECT set_config('app.audit_actor', $1, false)"
+
+// NewConnector creates a postgres connector whose connections tell the database the actor of
+// each statement's context
+func NewConnector(dsn string) (driver.Connector, error) {
+	base, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return WrapConnector(base), nil
+}
+
+// WrapConnector wraps a connector so that its connections set app.audit_actor to the actor of
+// each statement's context before running it. The setting is only sent when it differs from the
+// one the connection already has.
+func WrapConnector(base driver.Connector) driver.Connector {
+	return &connector{base: base}
+}
+
+type connector struct {
+	base driver.Connector
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.base.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &auditConn{Conn: conn}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return c.base.Driver()
+}
+
+// auditConn is a connection that remembers the actor setting it last sent. A connection is
+// only used by one goroutine at a time, so it needs no locking.
+type auditConn struct {
+	driver.Conn
+	actor string
+	known bool
+}
+
+// setActor sends the actor of ctx to the database unless the connection already has it
+func (c *auditConn) setActor(ctx context.Context) error {
+	actor := setting(ctx)
+	if c.known && c.actor == actor {
+		return nil
+	}
+
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return driver.ErrSkip
+	}
+	if _, err := execer.ExecContext(ctx, setActorQuery, []driver.NamedValue{{Ordinal: 1, Value: actor}}); err != nil {
+		c.known = false
+		return err
+	}
+	c.actor, c.known = actor, true
+	return nil
+}
+
+func (c *auditConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := c.setActor(ctx); err != nil {
+		return nil, err
+	}
+	return execer.ExecContext(ctx, query, args)
+}
+
+func (c *auditConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := c.setActor(ctx); err != nil {
+		return nil, err
+	}
+	return queryer.QueryContext(ctx, query, args)
+}
+
+func (c *auditConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := c.setActor(ctx); err != nil && err != driver.ErrSkip {
+		return nil, err
+	}
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *auditConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := c.setActor(ctx); err != nil && err != driver.ErrSkip {
+		return nil, err
+	}
+
+	var tx driver.Tx
+	var err error
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = beginner.BeginTx(ctx, opts)
+	} else {
+		tx, err = c.Conn.Begin()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &auditTx{Tx: tx, conn: c}, nil
+}
+
+func (c *auditConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *auditConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *auditConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// auditTx forgets the connection's actor setting on rollback, since a setting changed inside
+// the transaction is rolled back with it
+type auditTx struct {
+	driver.Tx
+	conn *auditConn
+}
+
+func (t *auditTx) Rollback() error {
+	t.conn.known = false
+	return t.Tx.Rollback()
+}
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -10,7 +13,12 @@ type Config struct {
 	Database DatabaseConfig
 	Server   ServerConfig
 	JWT      JWTConfig
+	Auth     AuthConfig
+	Password PasswordConfig
+	Notifier NotifierConfig
 	App      AppConfig
+	Jobs     JobsConfig
+	BankFile BankFileConfig
 }
 
 type DatabaseConfig struct {
@@ -29,9 +37,82 @@ type ServerConfig struct {
 	Env  string
 }
 
+// defaultJWTSecretKey is the JWT secret used when none is configured, which production refuses
+const defaultJWTSecretKey = "default-secret-key"
+
+// JWTConfig holds the token settings. Access tokens are short-lived; a login session and its
+// refresh tokens last ExpirationHour hours. Access tokens are signed with SigningAlgorithm:
+// RS256 or EdDSA keys are rotated every KeyRotationDays and stored encrypted with SecretKey,
+// while HS256 signs with SecretKey itself.
 type JWTConfig struct {
-	SecretKey      string
-	ExpirationHour int
+	SecretKey          string
+	ExpirationHour     int
+	AccessTokenMinutes int
+	SigningAlgorithm   string
+	KeyRotationDays    int
+}
+
+// AuthConfig holds the login policy. TwoFactorRequiredRoles is a comma separated list of the
+// roles that must use two-factor authentication. LoginMaxFailures consecutive failed logins lock
+// an account for LoginLockoutMinutes; LoginIPMaxFailures failures from one IP address within
+// LoginIPWindowMinutes block that address. Password reset links open PasswordResetURL and stay
+// valid for PasswordResetTokenMinutes. Sessions end after SessionIdleMinutes without requests,
+// and a user may hold SessionMaxConcurrent active sessions; SessionRoleLimits overrides that per
+// role as a comma separated list of role=limit. Zero disables a limit.
+type AuthConfig struct {
+	TwoFactorIssuer           string
+	TwoFactorRequiredRoles    string
+	LoginMaxFailures          int
+	LoginLockoutMinutes       int
+	LoginIPMaxFailures        int
+	LoginIPWindowMinutes      int
+	PasswordResetURL          string
+	PasswordResetTokenMinutes int
+	SessionIdleMinutes        int
+	SessionMaxConcurrent      int
+	SessionRoleLimits         string
+}
+
+// PasswordConfig holds the password policy. HistoryCount recent passwords cannot be reused and
+// passwords must be changed after MaxAgeDays; zero disables either.
+type PasswordConfig struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	RejectCommon  bool
+	HistoryCount  int
+	MaxAgeDays    int
+}
+
+// NotifierConfig selects how emails are delivered: "smtp" sends them through the SMTP server,
+// "file" appends them to FilePath for development and tests
+type NotifierConfig struct {
+	Driver       string
+	FilePath     string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+}
+
+// JobsConfig holds the cron schedules of the background jobs
+type JobsConfig struct {
+	Enabled                bool
+	OverdueSchedule        string
+	PenaltySchedule        string
+	SessionCleanupSchedule string
+	APAgingSchedule        string
+	KeyRotationSchedule    string
+	PenaltyDailyRate       float64
+}
+
+// BankFileConfig holds the layout of the bulk transfer files generated for payment runs
+type BankFileConfig struct {
+	Currency         string
+	FixedWidthLayout string
 }
 
 type AppConfig struct {
@@ -57,21 +138,136 @@ func Load() *Config {
 			Env:  getEnv("APP_ENV", "development"),
 		},
 		JWT: JWTConfig{
-			SecretKey:      getEnv("JWT_SECRET_KEY", "default-secret-key"),
-			ExpirationHour: getEnvAsInt("JWT_EXPIRATION_HOUR", 24),
+			SecretKey:          getEnv("JWT_SECRET_KEY", defaultJWTSecretKey),
+			ExpirationHour:     getEnvAsInt("JWT_EXPIRATION_HOUR", 24),
+			AccessTokenMinutes: getEnvAsInt("JWT_ACCESS_TOKEN_MINUTES", 15),
+			SigningAlgorithm:   getEnv("JWT_SIGNING_ALGORITHM", "RS256"),
+			KeyRotationDays:    getEnvAsInt("JWT_KEY_ROTATION_DAYS", 30),
+		},
+		Auth: AuthConfig{
+			TwoFactorIssuer:           getEnv("TWO_FACTOR_ISSUER", "Showroom"),
+			TwoFactorRequiredRoles:    getEnv("TWO_FACTOR_REQUIRED_ROLES", ""),
+			LoginMaxFailures:          getEnvAsInt("LOGIN_MAX_FAILURES", 5),
+			LoginLockoutMinutes:       getEnvAsInt("LOGIN_LOCKOUT_MINUTES", 15),
+			LoginIPMaxFailures:        getEnvAsInt("LOGIN_IP_MAX_FAILURES", 20),
+			LoginIPWindowMinutes:      getEnvAsInt("LOGIN_IP_WINDOW_MINUTES", 15),
+			PasswordResetURL:          getEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+			PasswordResetTokenMinutes: getEnvAsInt("PASSWORD_RESET_TOKEN_MINUTES", 30),
+			SessionIdleMinutes:        getEnvAsInt("SESSION_IDLE_TIMEOUT_MINUTES", 30),
+			SessionMaxConcurrent:      getEnvAsInt("SESSION_MAX_CONCURRENT", 5),
+			SessionRoleLimits:         getEnv("SESSION_ROLE_LIMITS", ""),
+		},
+		Password: PasswordConfig{
+			MinLength:     getEnvAsInt("PASSWORD_MIN_LENGTH", 8),
+			RequireUpper:  getEnvAsBool("PASSWORD_REQUIRE_UPPER", true),
+			RequireLower:  getEnvAsBool("PASSWORD_REQUIRE_LOWER", true),
+			RequireDigit:  getEnvAsBool("PASSWORD_REQUIRE_DIGIT", true),
+			RequireSymbol: getEnvAsBool("PASSWORD_REQUIRE_SYMBOL", false),
+			RejectCommon:  getEnvAsBool("PASSWORD_REJECT_COMMON", true),
+			HistoryCount:  getEnvAsInt("PASSWORD_HISTORY_COUNT", 5),
+			MaxAgeDays:    getEnvAsInt("PASSWORD_MAX_AGE_DAYS", 90),
+		},
+		Notifier: NotifierConfig{
+			Driver:       getEnv("NOTIFIER_DRIVER", "file"),
+			FilePath:     getEnv("NOTIFIER_FILE_PATH", "./tmp/mail.log"),
+			SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			SMTPFrom:     getEnv("SMTP_FROM", "no-reply@showroom.local"),
 		},
 		App: AppConfig{
 			Name:     getEnv("APP_NAME", "Showroom Management System"),
 			Version:  getEnv("APP_VERSION", "1.0.0"),
 			LogLevel: getEnv("LOG_LEVEL", "info"),
 		},
+		Jobs: JobsConfig{
+			Enabled:                getEnvAsBool("JOBS_ENABLED", true),
+			OverdueSchedule:        getEnv("JOBS_OVERDUE_SCHEDULE", "0 1 * * *"),
+			PenaltySchedule:        getEnv("JOBS_PENALTY_SCHEDULE", "30 1 * * *"),
+			SessionCleanupSchedule: getEnv("JOBS_SESSION_CLEANUP_SCHEDULE", "0 * * * *"),
+			APAgingSchedule:        getEnv("JOBS_AP_AGING_SCHEDULE", "0 6 * * 1"),
+			KeyRotationSchedule:    getEnv("JOBS_JWT_KEY_ROTATION_SCHEDULE", "0 2 * * *"),
+			PenaltyDailyRate:       getEnvAsFloat("SUPPLIER_PENALTY_DAILY_RATE", 0),
+		},
+		BankFile: BankFileConfig{
+			Currency:         getEnv("BANK_FILE_CURRENCY", "IDR"),
+			FixedWidthLayout: getEnv("BANK_FILE_FIXED_WIDTH_LAYOUT", "bank_account:34,supplier_name:35,amount_cents:17:right:0,currency:3,reference:20,payment_date:8"),
+		},
 	}
 }
 
+// Validate reports configuration the server must not start with
+func (c *Config) Validate() error {
+	switch c.JWT.SigningAlgorithm {
+	case "HS256", "RS256", "EdDSA":
+	default:
+		return fmt.Errorf("JWT_SIGNING_ALGORITHM must be HS256, RS256 or EdDSA, got %q", c.JWT.SigningAlgorithm)
+	}
+
+	if c.Server.Env == "production" && c.JWT.SecretKey == defaultJWTSecretKey {
+		return errors.New("JWT_SECRET_KEY must be set in production")
+	}
+
+	if _, err := c.Auth.GetSessionRoleLimits(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (j *JWTConfig) GetExpiration() time.Duration {
 	return time.Duration(j.ExpirationHour) * time.Hour
 }
 
+// GetAccessTokenExpiration returns how long an access token is valid
+func (j *JWTConfig) GetAccessTokenExpiration() time.Duration {
+	return time.Duration(j.AccessTokenMinutes) * time.Minute
+}
+
+// GetKeyRotationInterval returns how long a signing key signs tokens before it is rotated
+func (j *JWTConfig) GetKeyRotationInterval() time.Duration {
+	return time.Duration(j.KeyRotationDays) * 24 * time.Hour
+}
+
+// GetTwoFactorRequiredRoles returns the roles that must use two-factor authentication
+func (a *AuthConfig) GetTwoFactorRequiredRoles() []string {
+	var roles []string
+	for _, role := range strings.Split(a.TwoFactorRequiredRoles, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// GetPasswordResetTokenLifetime returns how long a password reset token is valid
+func (a *AuthConfig) GetPasswordResetTokenLifetime() time.Duration {
+	return time.Duration(a.PasswordResetTokenMinutes) * time.Minute
+}
+
+// GetSessionIdleTimeout returns how long a session may go without requests before it ends
+func (a *AuthConfig) GetSessionIdleTimeout() time.Duration {
+	return time.Duration(a.SessionIdleMinutes) * time.Minute
+}
+
+// GetSessionRoleLimits returns the per-role concurrent session limits, keyed by role name
+func (a *AuthConfig) GetSessionRoleLimits() (map[string]int, error) {
+	limits := map[string]int{}
+	for _, entry := range strings.Split(a.SessionRoleLimits, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		role, value, found := strings.Cut(entry, "=")
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if !found || err != nil {
+			return nil, fmt.Errorf("SESSION_ROLE_LIMITS entries must be role=limit, got %q", entry)
+		}
+		limits[strings.TrimSpace(role)] = limit
+	}
+	return limits, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -86,4 +282,21 @@ func getEnvAsInt(key string, defaultValue int) int {
 		}
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
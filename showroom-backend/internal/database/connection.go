@@ -6,6 +6,7 @@ import (
 	"log"
 
 	_ "github.com/lib/pq"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/audit"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/config"
 )
 
@@ -25,11 +26,12 @@ func Connect(cfg *config.Config) error {
 		cfg.Database.Timezone,
 	)
 
-	var err error
-	DB, err = sql.Open("postgres", dsn)
+	// Connections pass the actor of each request to the audit log triggers
+	connector, err := audit.NewConnector(dsn)
 	if err != nil {
 		return fmt.Errorf("failed to open database connection: %w", err)
 	}
+	DB = sql.OpenDB(connector)
 
 	// Set connection pool settings
 	DB.SetMaxOpenConns(25)
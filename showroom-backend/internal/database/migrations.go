@@ -28,6 +28,128 @@ func RunMigrations(db *sql.DB) error {
 		createStockAdjustmentsTable,
 		createSupplierPaymentsTable,
 		createPhase3Indexes,
+		// Purchase returns & supplier debit notes
+		alterSupplierPaymentsAddCreditAmount,
+		createPurchaseReturnsTable,
+		createPurchaseReturnDetailsTable,
+		createSupplierDebitNotesTable,
+		createSupplierDebitNoteApplicationsTable,
+		createPurchaseReturnIndexes,
+		// Landed costs
+		alterStockMovementsReferenceTypeLandedCost,
+		createLandedCostVouchersTable,
+		createLandedCostVoucherReceiptsTable,
+		createLandedCostAllocationsTable,
+		createLandedCostIndexes,
+		// Blanket order releases
+		createBlanketOrderTermsTable,
+		createBlanketOrderReleasesTable,
+		createBlanketOrderIndexes,
+		// Supplier price lists
+		createSupplierPriceListsTable,
+		createSupplierPriceBreaksTable,
+		createSupplierPriceHistoryTable,
+		createSupplierPriceListIndexes,
+		// Supplier invoices & payment vouchers
+		createSupplierInvoicesTable,
+		createSupplierPaymentVouchersTable,
+		createSupplierPaymentAllocationsTable,
+		migrateSupplierPaymentsToInvoices,
+		alterDebitNoteApplicationsToInvoices,
+		createSupplierInvoiceIndexes,
+		// Structured payment terms
+		createPaymentTermsTable,
+		seedPaymentTerms,
+		alterSuppliersAddPaymentTermID,
+		alterSupplierInvoicesAddPaymentTerms,
+		createPaymentTermIndexes,
+
+		// Scheduled background jobs
+		createScheduledJobRunsTable,
+		alterSupplierInvoicesAddPenalty,
+
+		// Supplier payment runs
+		createPaymentRunsTable,
+		createPaymentRunLinesTable,
+
+		// Bank statement reconciliation
+		createBankStatementsTable,
+		createBankStatementLinesTable,
+		// General ledger
+		createGLAccountsTable,
+		seedGLAccounts,
+		createGLPostingRulesTable,
+		seedGLPostingRules,
+		createJournalEntriesTable,
+		createJournalLinesTable,
+		createAccountingPeriodsTable,
+
+		// Tax
+		createTaxCodesTable,
+		seedTaxCodes,
+		alterSupplierInvoicesAddTax,
+		alterPurchaseOrdersAddTax,
+		createSalesInvoicesTable,
+		createSalesInvoiceLinesTable,
+		alterJournalEntriesSourceTypeSalesInvoice,
+		seedTaxGLAccounts,
+
+		// Multi-currency
+		createExchangeRatesTable,
+		alterPurchasingDocumentsAddCurrency,
+		alterSupplierPaymentAllocationsAddFXGainLoss,
+		seedFXGLAccounts,
+		// Customer receivables
+		alterCustomersAddCredit,
+		alterSalesInvoicesAddPaymentTerms,
+		createCustomerReceiptsTable,
+		createCustomerReceiptAllocationsTable,
+		alterJournalEntriesSourceTypeCustomerReceipt,
+		// Role-based access control
+		createRolePermissionsTable,
+		// Refresh tokens
+		createUserRefreshTokensTable,
+		// Two-factor authentication
+		createUserTwoFactorTable,
+		createUserRecoveryCodesTable,
+		createUserLoginChallengesTable,
+		// Brute-force protection
+		createLoginAttemptsTable,
+		createUserLoginLocksTable,
+		// Password reset
+		createPasswordResetTokensTable,
+		// Password policy
+		alterUsersAddPasswordPolicy,
+		alterUserSessionsAddPasswordChangeRequired,
+		createPasswordHistoryTable,
+		// API keys
+		createAPIKeysTable,
+
+		// Token signing keys
+		createJWTSigningKeysTable,
+
+		// Audit log
+		createAuditLogTable,
+		createAuditLogAppendOnlyTrigger,
+		createAuditRecordChangeFunction,
+		createAuditTriggers,
+
+		// Session limits
+		alterUserSessionsAddLastActivity,
+
+		// Landed cost journal
+		alterJournalEntriesSourceTypeLandedCost,
+		seedLandedCostGLAccounts,
+
+		// Password reset throttling
+		alterLoginAttemptsOutcomePasswordReset,
+
+		// Bank reconciliation of customer receipts
+		alterBankStatementLinesAddReceipt,
+
+		// Purchase price variance
+		seedPurchasePriceVarianceGLAccounts,
+		alterScheduledJobRunsAddScheduledAt,
 	}
 
 	for i, migration := range migrations {
@@ -496,4 +618,1271 @@ CREATE INDEX IF NOT EXISTS idx_supplier_payments_payment_date ON supplier_paymen
 CREATE INDEX IF NOT EXISTS idx_supplier_payments_due_date ON supplier_payments(due_date);
 CREATE INDEX IF NOT EXISTS idx_supplier_payments_method ON supplier_payments(payment_method);
 CREATE INDEX IF NOT EXISTS idx_supplier_payments_processed_by ON supplier_payments(processed_by);
-CREATE INDEX IF NOT EXISTS idx_supplier_payments_invoice_number ON supplier_payments(invoice_number);`
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_supplier_payments_invoice_number ON supplier_payments(invoice_number);`
+
+// Purchase returns & supplier debit notes
+
+const alterSupplierPaymentsAddCreditAmount = `
+ALTER TABLE supplier_payments ADD COLUMN IF NOT EXISTS credit_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (credit_amount >= 0);`
+
+const createPurchaseReturnsTable = `
+CREATE TABLE IF NOT EXISTS purchase_returns (
+    return_id SERIAL PRIMARY KEY,
+    return_number VARCHAR(20) UNIQUE NOT NULL,
+    supplier_id INTEGER NOT NULL REFERENCES suppliers(supplier_id),
+    receipt_id INTEGER REFERENCES goods_receipts(receipt_id),
+    po_id INTEGER REFERENCES purchase_orders_parts(po_id),
+    return_source VARCHAR(20) NOT NULL CHECK (return_source IN ('receipt','stock')),
+    return_date TIMESTAMP NOT NULL DEFAULT NOW(),
+    return_status VARCHAR(20) NOT NULL CHECK (return_status IN ('draft','approved','shipped','completed','cancelled')) DEFAULT 'draft',
+    shipment_status VARCHAR(20) NOT NULL CHECK (shipment_status IN ('pending','in_transit','delivered','refused')) DEFAULT 'pending',
+    carrier_name VARCHAR(100),
+    tracking_number VARCHAR(100),
+    shipped_at TIMESTAMP,
+    delivered_at TIMESTAMP,
+    total_return_value DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (total_return_value >= 0),
+    return_reason VARCHAR(255) NOT NULL,
+    return_notes TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(user_id),
+    approved_by INTEGER REFERENCES users(user_id),
+    approved_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);`
+
+const createPurchaseReturnDetailsTable = `
+CREATE TABLE IF NOT EXISTS purchase_return_details (
+    return_detail_id SERIAL PRIMARY KEY,
+    return_id INTEGER NOT NULL REFERENCES purchase_returns(return_id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products_spare_parts(product_id),
+    receipt_detail_id INTEGER REFERENCES goods_receipt_details(receipt_detail_id),
+    quantity_returned INTEGER NOT NULL CHECK (quantity_returned > 0),
+    unit_cost DECIMAL(15,2) NOT NULL CHECK (unit_cost >= 0),
+    total_cost DECIMAL(15,2) NOT NULL CHECK (total_cost >= 0),
+    return_reason TEXT
+);`
+
+const createSupplierDebitNotesTable = `
+CREATE TABLE IF NOT EXISTS supplier_debit_notes (
+    debit_note_id SERIAL PRIMARY KEY,
+    debit_note_number VARCHAR(20) UNIQUE NOT NULL,
+    supplier_id INTEGER NOT NULL REFERENCES suppliers(supplier_id),
+    return_id INTEGER REFERENCES purchase_returns(return_id),
+    debit_note_date TIMESTAMP NOT NULL DEFAULT NOW(),
+    debit_amount DECIMAL(15,2) NOT NULL CHECK (debit_amount >= 0),
+    applied_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (applied_amount >= 0),
+    debit_note_status VARCHAR(20) NOT NULL CHECK (debit_note_status IN ('open','partial','applied','cancelled')) DEFAULT 'open',
+    notes TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(user_id),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);`
+
+const createSupplierDebitNoteApplicationsTable = `
+CREATE TABLE IF NOT EXISTS supplier_debit_note_applications (
+    application_id SERIAL PRIMARY KEY,
+    debit_note_id INTEGER NOT NULL REFERENCES supplier_debit_notes(debit_note_id) ON DELETE CASCADE,
+    payment_id INTEGER NOT NULL REFERENCES supplier_payments(payment_id),
+    applied_amount DECIMAL(15,2) NOT NULL CHECK (applied_amount > 0),
+    applied_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    applied_by INTEGER NOT NULL REFERENCES users(user_id)
+);`
+
+const createPurchaseReturnIndexes = `
+-- Purchase returns table indexes
+CREATE INDEX IF NOT EXISTS idx_purchase_returns_number ON purchase_returns(return_number);
+CREATE INDEX IF NOT EXISTS idx_purchase_returns_supplier_id ON purchase_returns(supplier_id);
+CREATE INDEX IF NOT EXISTS idx_purchase_returns_receipt_id ON purchase_returns(receipt_id);
+CREATE INDEX IF NOT EXISTS idx_purchase_returns_status ON purchase_returns(return_status);
+CREATE INDEX IF NOT EXISTS idx_purchase_returns_shipment_status ON purchase_returns(shipment_status);
+CREATE INDEX IF NOT EXISTS idx_purchase_returns_return_date ON purchase_returns(return_date);
+
+-- Purchase return details table indexes
+CREATE INDEX IF NOT EXISTS idx_purchase_return_details_return_id ON purchase_return_details(return_id);
+CREATE INDEX IF NOT EXISTS idx_purchase_return_details_product_id ON purchase_return_details(product_id);
+CREATE INDEX IF NOT EXISTS idx_purchase_return_details_receipt_detail_id ON purchase_return_details(receipt_detail_id);
+
+-- Supplier debit notes table indexes
+CREATE INDEX IF NOT EXISTS idx_supplier_debit_notes_number ON supplier_debit_notes(debit_note_number);
+CREATE INDEX IF NOT EXISTS idx_supplier_debit_notes_supplier_id ON supplier_debit_notes(supplier_id);
+CREATE INDEX IF NOT EXISTS idx_supplier_debit_notes_return_id ON supplier_debit_notes(return_id);
+CREATE INDEX IF NOT EXISTS idx_supplier_debit_notes_status ON supplier_debit_notes(debit_note_status);
+
+-- Supplier debit note applications table indexes
+CREATE INDEX IF NOT EXISTS idx_supplier_debit_note_applications_debit_note_id ON supplier_debit_note_applications(debit_note_id);
+CREATE INDEX IF NOT EXISTS idx_supplier_debit_note_applications_payment_id ON supplier_debit_note_applications(payment_id);`
+
+// Landed costs
+const alterStockMovementsReferenceTypeLandedCost = `
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS stock_movements_reference_type_check;
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_reference_type_check
+    CHECK (reference_type IN ('purchase','sales','repair','adjustment','transfer','return','landed_cost'));`
+
+const createLandedCostVouchersTable = `
+CREATE TABLE IF NOT EXISTS landed_cost_vouchers (
+    voucher_id SERIAL PRIMARY KEY,
+    voucher_number VARCHAR(20) UNIQUE NOT NULL,
+    supplier_id INTEGER REFERENCES suppliers(supplier_id),
+    reference_number VARCHAR(100),
+    voucher_date TIMESTAMP NOT NULL DEFAULT NOW(),
+    allocation_method VARCHAR(20) NOT NULL CHECK (allocation_method IN ('value','quantity','weight')),
+    freight_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (freight_amount >= 0),
+    duty_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (duty_amount >= 0),
+    insurance_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (insurance_amount >= 0),
+    handling_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (handling_amount >= 0),
+    total_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (total_amount >= 0),
+    voucher_status VARCHAR(20) NOT NULL CHECK (voucher_status IN ('draft','posted','cancelled')) DEFAULT 'draft',
+    notes TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(user_id),
+    posted_by INTEGER REFERENCES users(user_id),
+    posted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);`
+
+const createLandedCostVoucherReceiptsTable = `
+CREATE TABLE IF NOT EXISTS landed_cost_voucher_receipts (
+    voucher_id INTEGER NOT NULL REFERENCES landed_cost_vouchers(voucher_id) ON DELETE CASCADE,
+    receipt_id INTEGER NOT NULL REFERENCES goods_receipts(receipt_id),
+    PRIMARY KEY (voucher_id, receipt_id)
+);`
+
+const createLandedCostAllocationsTable = `
+CREATE TABLE IF NOT EXISTS landed_cost_allocations (
+    allocation_id SERIAL PRIMARY KEY,
+    voucher_id INTEGER NOT NULL REFERENCES landed_cost_vouchers(voucher_id) ON DELETE CASCADE,
+    receipt_id INTEGER NOT NULL REFERENCES goods_receipts(receipt_id),
+    receipt_detail_id INTEGER NOT NULL REFERENCES goods_receipt_details(receipt_detail_id),
+    product_id INTEGER NOT NULL REFERENCES products_spare_parts(product_id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_cost DECIMAL(15,2) NOT NULL DEFAULT 0,
+    unit_weight DECIMAL(10,3) NOT NULL DEFAULT 0,
+    basis_amount DECIMAL(15,4) NOT NULL DEFAULT 0,
+    allocated_amount DECIMAL(15,2) NOT NULL CHECK (allocated_amount >= 0),
+    unit_landed_cost DECIMAL(15,4) NOT NULL CHECK (unit_landed_cost >= 0)
+);`
+
+const createLandedCostIndexes = `
+-- Landed cost vouchers table indexes
+CREATE INDEX IF NOT EXISTS idx_landed_cost_vouchers_number ON landed_cost_vouchers(voucher_number);
+CREATE INDEX IF NOT EXISTS idx_landed_cost_vouchers_supplier_id ON landed_cost_vouchers(supplier_id);
+CREATE INDEX IF NOT EXISTS idx_landed_cost_vouchers_status ON landed_cost_vouchers(voucher_status);
+CREATE INDEX IF NOT EXISTS idx_landed_cost_vouchers_voucher_date ON landed_cost_vouchers(voucher_date);
+
+-- Landed cost voucher receipts table indexes
+CREATE INDEX IF NOT EXISTS idx_landed_cost_voucher_receipts_receipt_id ON landed_cost_voucher_receipts(receipt_id);
+
+-- Landed cost allocations table indexes
+CREATE INDEX IF NOT EXISTS idx_landed_cost_allocations_voucher_id ON landed_cost_allocations(voucher_id);
+CREATE INDEX IF NOT EXISTS idx_landed_cost_allocations_receipt_id ON landed_cost_allocations(receipt_id);
+CREATE INDEX IF NOT EXISTS idx_landed_cost_allocations_receipt_detail_id ON landed_cost_allocations(receipt_detail_id);`
+
+// Blanket order releases
+const createBlanketOrderTermsTable = `
+CREATE TABLE IF NOT EXISTS blanket_order_terms (
+    po_id INTEGER PRIMARY KEY REFERENCES purchase_orders_parts(po_id) ON DELETE CASCADE,
+    agreed_quantity INTEGER CHECK (agreed_quantity > 0),
+    agreed_value DECIMAL(15,2) CHECK (agreed_value > 0),
+    valid_from TIMESTAMP NOT NULL,
+    valid_to TIMESTAMP NOT NULL,
+    consumed_quantity INTEGER NOT NULL DEFAULT 0 CHECK (consumed_quantity >= 0),
+    consumed_value DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (consumed_value >= 0),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    CHECK (agreed_quantity IS NOT NULL OR agreed_value IS NOT NULL),
+    CHECK (valid_to > valid_from)
+);`
+
+const createBlanketOrderReleasesTable = `
+CREATE TABLE IF NOT EXISTS blanket_order_releases (
+    release_id SERIAL PRIMARY KEY,
+    blanket_po_id INTEGER NOT NULL REFERENCES purchase_orders_parts(po_id),
+    release_po_id INTEGER UNIQUE NOT NULL REFERENCES purchase_orders_parts(po_id),
+    release_quantity INTEGER NOT NULL CHECK (release_quantity > 0),
+    release_value DECIMAL(15,2) NOT NULL CHECK (release_value >= 0),
+    release_status VARCHAR(20) NOT NULL CHECK (release_status IN ('active','cancelled')) DEFAULT 'active',
+    released_by INTEGER NOT NULL REFERENCES users(user_id),
+    created_at TIMESTAMP DEFAULT NOW()
+);`
+
+const createBlanketOrderIndexes = `
+-- Blanket order releases table indexes
+CREATE INDEX IF NOT EXISTS idx_blanket_order_releases_blanket_po_id ON blanket_order_releases(blanket_po_id);
+CREATE INDEX IF NOT EXISTS idx_blanket_order_releases_status ON blanket_order_releases(release_status);`
+
+// Supplier price lists
+const createSupplierPriceListsTable = `
+CREATE TABLE IF NOT EXISTS supplier_price_lists (
+    price_list_id SERIAL PRIMARY KEY,
+    supplier_id INTEGER NOT NULL REFERENCES suppliers(supplier_id),
+    product_id INTEGER NOT NULL REFERENCES products_spare_parts(product_id),
+    currency VARCHAR(3) NOT NULL DEFAULT 'IDR',
+    unit_price DECIMAL(15,2) NOT NULL CHECK (unit_price > 0),
+    minimum_order_quantity INTEGER NOT NULL DEFAULT 0 CHECK (minimum_order_quantity >= 0),
+    valid_from TIMESTAMP NOT NULL,
+    valid_to TIMESTAMP,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    notes TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(user_id),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    CHECK (valid_to IS NULL OR valid_to >= valid_from)
+);`
+
+const createSupplierPriceBreaksTable = `
+CREATE TABLE IF NOT EXISTS supplier_price_breaks (
+    price_break_id SERIAL PRIMARY KEY,
+    price_list_id INTEGER NOT NULL REFERENCES supplier_price_lists(price_list_id) ON DELETE CASCADE,
+    min_quantity INTEGER NOT NULL CHECK (min_quantity > 0),
+    unit_price DECIMAL(15,2) NOT NULL CHECK (unit_price > 0),
+    UNIQUE (price_list_id, min_quantity)
+);`
+
+const createSupplierPriceHistoryTable = `
+CREATE TABLE IF NOT EXISTS supplier_price_history (
+    history_id SERIAL PRIMARY KEY,
+    supplier_id INTEGER NOT NULL REFERENCES suppliers(supplier_id),
+    product_id INTEGER NOT NULL REFERENCES products_spare_parts(product_id),
+    currency VARCHAR(3) NOT NULL DEFAULT 'IDR',
+    unit_price DECIMAL(15,2) NOT NULL CHECK (unit_price >= 0),
+    quantity INTEGER,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('price_list','purchase_order')),
+    reference_id INTEGER NOT NULL,
+    recorded_at TIMESTAMP NOT NULL DEFAULT NOW()
+);`
+
+const createSupplierPriceListIndexes = `
+-- Supplier price lists table indexes
+CREATE INDEX IF NOT EXISTS idx_supplier_price_lists_supplier_product ON supplier_price_lists(supplier_id, product_id);
+CREATE INDEX IF NOT EXISTS idx_supplier_price_lists_product_id ON supplier_price_lists(product_id);
+CREATE INDEX IF NOT EXISTS idx_supplier_price_lists_validity ON supplier_price_lists(valid_from, valid_to);
+
+-- Supplier price breaks table indexes
+CREATE INDEX IF NOT EXISTS idx_supplier_price_breaks_price_list_id ON supplier_price_breaks(price_list_id);
+
+-- Supplier price history table indexes
+CREATE INDEX IF NOT EXISTS idx_supplier_price_history_supplier_product ON supplier_price_history(supplier_id, product_id);
+CREATE INDEX IF NOT EXISTS idx_supplier_price_history_recorded_at ON supplier_price_history(recorded_at);`
+
+
+// Supplier invoices & payment vouchers
+
+const createSupplierInvoicesTable = `
+CREATE TABLE IF NOT EXISTS supplier_invoices (
+    invoice_id SERIAL PRIMARY KEY,
+    supplier_id INTEGER NOT NULL REFERENCES suppliers(supplier_id),
+    po_id INTEGER REFERENCES purchase_orders_parts(po_id),
+    invoice_number VARCHAR(100) NOT NULL,
+    invoice_date TIMESTAMP NOT NULL,
+    due_date TIMESTAMP NOT NULL,
+    invoice_amount DECIMAL(15,2) NOT NULL CHECK (invoice_amount >= 0),
+    credit_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (credit_amount >= 0),
+    invoice_status VARCHAR(20) NOT NULL CHECK (invoice_status IN ('pending','partial','paid','overdue','disputed')) DEFAULT 'pending',
+    notes TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(user_id),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);`
+
+const createSupplierPaymentVouchersTable = `
+CREATE TABLE IF NOT EXISTS supplier_payment_vouchers (
+    voucher_id SERIAL PRIMARY KEY,
+    voucher_number VARCHAR(20) UNIQUE NOT NULL,
+    supplier_id INTEGER NOT NULL REFERENCES suppliers(supplier_id),
+    payment_date TIMESTAMP NOT NULL DEFAULT NOW(),
+    payment_method VARCHAR(20) NOT NULL CHECK (payment_method IN ('cash','transfer','check','credit')) DEFAULT 'transfer',
+    payment_reference VARCHAR(100),
+    amount DECIMAL(15,2) NOT NULL CHECK (amount >= 0),
+    voucher_status VARCHAR(20) NOT NULL CHECK (voucher_status IN ('posted','void')) DEFAULT 'posted',
+    notes TEXT,
+    processed_by INTEGER NOT NULL REFERENCES users(user_id),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);`
+
+const createSupplierPaymentAllocationsTable = `
+CREATE TABLE IF NOT EXISTS supplier_payment_allocations (
+    allocation_id SERIAL PRIMARY KEY,
+    voucher_id INTEGER NOT NULL REFERENCES supplier_payment_vouchers(voucher_id) ON DELETE CASCADE,
+    invoice_id INTEGER NOT NULL REFERENCES supplier_invoices(invoice_id),
+    allocated_amount DECIMAL(15,2) NOT NULL CHECK (allocated_amount >= 0),
+    discount_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (discount_amount >= 0),
+    created_at TIMESTAMP DEFAULT NOW(),
+    CHECK (allocated_amount + discount_amount > 0)
+);`
+
+// migrateSupplierPaymentsToInvoices splits each legacy supplier_payments row into an invoice and,
+// when something was paid, a payment voucher allocated to it. IDs are kept so existing references
+// to payment IDs resolve to the matching invoice.
+const migrateSupplierPaymentsToInvoices = `
+INSERT INTO supplier_invoices (
+    invoice_id, supplier_id, po_id, invoice_number, invoice_date, due_date,
+    invoice_amount, credit_amount, invoice_status, notes, created_by, created_at, updated_at
+)
+SELECT sp.payment_id, sp.supplier_id, sp.po_id, sp.invoice_number, sp.invoice_date, sp.due_date,
+       sp.invoice_amount, sp.credit_amount, sp.payment_status, sp.payment_notes, sp.processed_by,
+       sp.created_at, sp.updated_at
+FROM supplier_payments sp
+WHERE NOT EXISTS (SELECT 1 FROM supplier_invoices si WHERE si.invoice_id = sp.payment_id);
+
+INSERT INTO supplier_payment_vouchers (
+    voucher_id, voucher_number, supplier_id, payment_date, payment_method,
+    payment_reference, amount, processed_by, created_at, updated_at
+)
+SELECT sp.payment_id, sp.payment_number, sp.supplier_id, sp.payment_date, sp.payment_method,
+       sp.payment_reference, sp.payment_amount, sp.processed_by, sp.created_at, sp.updated_at
+FROM supplier_payments sp
+WHERE (sp.payment_amount > 0 OR sp.discount_taken > 0)
+AND NOT EXISTS (SELECT 1 FROM supplier_payment_vouchers pv WHERE pv.voucher_id = sp.payment_id);
+
+INSERT INTO supplier_payment_allocations (voucher_id, invoice_id, allocated_amount, discount_amount, created_at)
+SELECT sp.payment_id, sp.payment_id, sp.payment_amount, sp.discount_taken, sp.updated_at
+FROM supplier_payments sp
+WHERE (sp.payment_amount > 0 OR sp.discount_taken > 0)
+AND NOT EXISTS (SELECT 1 FROM supplier_payment_allocations pa WHERE pa.voucher_id = sp.payment_id);
+
+SELECT setval(pg_get_serial_sequence('supplier_invoices', 'invoice_id'),
+    COALESCE((SELECT MAX(invoice_id) FROM supplier_invoices), 0) + 1, false);
+SELECT setval(pg_get_serial_sequence('supplier_payment_vouchers', 'voucher_id'),
+    COALESCE((SELECT MAX(voucher_id) FROM supplier_payment_vouchers), 0) + 1, false);`
+
+const alterDebitNoteApplicationsToInvoices = `
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'supplier_debit_note_applications' AND column_name = 'payment_id'
+    ) THEN
+        ALTER TABLE supplier_debit_note_applications DROP CONSTRAINT IF EXISTS supplier_debit_note_applications_payment_id_fkey;
+        ALTER TABLE supplier_debit_note_applications RENAME COLUMN payment_id TO invoice_id;
+        ALTER TABLE supplier_debit_note_applications
+            ADD CONSTRAINT supplier_debit_note_applications_invoice_id_fkey
+            FOREIGN KEY (invoice_id) REFERENCES supplier_invoices(invoice_id);
+    END IF;
+END $$;`
+
+const createSupplierInvoiceIndexes = `
+-- Supplier invoices table indexes
+CREATE INDEX IF NOT EXISTS idx_supplier_invoices_supplier_id ON supplier_invoices(supplier_id);
+CREATE INDEX IF NOT EXISTS idx_supplier_invoices_po_id ON supplier_invoices(po_id);
+CREATE INDEX IF NOT EXISTS idx_supplier_invoices_number ON supplier_invoices(supplier_id, invoice_number);
+CREATE INDEX IF NOT EXISTS idx_supplier_invoices_status ON supplier_invoices(invoice_status);
+CREATE INDEX IF NOT EXISTS idx_supplier_invoices_due_date ON supplier_invoices(due_date);
+
+-- Supplier payment vouchers table indexes
+CREATE INDEX IF NOT EXISTS idx_supplier_payment_vouchers_number ON supplier_payment_vouchers(voucher_number);
+CREATE INDEX IF NOT EXISTS idx_supplier_payment_vouchers_supplier_id ON supplier_payment_vouchers(supplier_id);
+CREATE INDEX IF NOT EXISTS idx_supplier_payment_vouchers_payment_date ON supplier_payment_vouchers(payment_date);
+CREATE INDEX IF NOT EXISTS idx_supplier_payment_vouchers_status ON supplier_payment_vouchers(voucher_status);
+
+-- Supplier payment allocations table indexes
+CREATE INDEX IF NOT EXISTS idx_supplier_payment_allocations_voucher_id ON supplier_payment_allocations(voucher_id);
+CREATE INDEX IF NOT EXISTS idx_supplier_payment_allocations_invoice_id ON supplier_payment_allocations(invoice_id);`
+
+// Structured payment terms
+
+const createPaymentTermsTable = `
+CREATE TABLE IF NOT EXISTS payment_terms (
+    term_id SERIAL PRIMARY KEY,
+    term_code VARCHAR(30) UNIQUE NOT NULL,
+    term_name VARCHAR(100) NOT NULL,
+    net_days INTEGER NOT NULL CHECK (net_days >= 0),
+    discount_percent DECIMAL(5,2) NOT NULL DEFAULT 0 CHECK (discount_percent >= 0 AND discount_percent < 100),
+    discount_days INTEGER NOT NULL DEFAULT 0 CHECK (discount_days >= 0),
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    CHECK (discount_days <= net_days)
+);`
+
+const seedPaymentTerms = `
+INSERT INTO payment_terms (term_code, term_name, net_days, discount_percent, discount_days) VALUES
+    ('COD', 'Cash on delivery', 0, 0, 0),
+    ('NET30', 'Net 30', 30, 0, 0),
+    ('NET60', 'Net 60', 60, 0, 0),
+    ('2/10NET30', '2/10 net 30', 30, 2, 10)
+ON CONFLICT (term_code) DO NOTHING;`
+
+// alterSuppliersAddPaymentTermID gives suppliers a structured default term and links
+// existing free-text terms that spell out one of the term codes (e.g. "Net 30").
+const alterSuppliersAddPaymentTermID = `
+ALTER TABLE suppliers ADD COLUMN IF NOT EXISTS payment_term_id INTEGER REFERENCES payment_terms(term_id);
+
+UPDATE suppliers s
+SET payment_term_id = pt.term_id
+FROM payment_terms pt
+WHERE s.payment_term_id IS NULL
+AND UPPER(REGEXP_REPLACE(COALESCE(s.payment_terms, ''), '[^A-Za-z0-9/]', '', 'g')) = pt.term_code;`
+
+const alterSupplierInvoicesAddPaymentTerms = `
+ALTER TABLE supplier_invoices ADD COLUMN IF NOT EXISTS payment_term_id INTEGER REFERENCES payment_terms(term_id);
+ALTER TABLE supplier_invoices ADD COLUMN IF NOT EXISTS discount_percent DECIMAL(5,2) NOT NULL DEFAULT 0;
+ALTER TABLE supplier_invoices ADD COLUMN IF NOT EXISTS discount_due_date TIMESTAMP;`
+
+const createPaymentTermIndexes = `
+CREATE INDEX IF NOT EXISTS idx_suppliers_payment_term_id ON suppliers(payment_term_id);
+CREATE INDEX IF NOT EXISTS idx_supplier_invoices_payment_term_id ON supplier_invoices(payment_term_id);
+CREATE INDEX IF NOT EXISTS idx_supplier_invoices_discount_due_date ON supplier_invoices(discount_due_date);`
+
+// Scheduled background jobs
+
+const createScheduledJobRunsTable = `
+CREATE TABLE IF NOT EXISTS scheduled_job_runs (
+    run_id SERIAL PRIMARY KEY,
+    job_name VARCHAR(100) NOT NULL,
+    run_trigger VARCHAR(20) NOT NULL CHECK (run_trigger IN ('schedule','manual')),
+    triggered_by INTEGER REFERENCES users(user_id),
+    run_status VARCHAR(20) NOT NULL CHECK (run_status IN ('running','succeeded','failed')) DEFAULT 'running',
+    started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP,
+    duration_ms BIGINT,
+    result TEXT,
+    error_message TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_job_runs_job_name ON scheduled_job_runs(job_name, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_scheduled_job_runs_status ON scheduled_job_runs(run_status);`
+
+const alterSupplierInvoicesAddPenalty = `
+ALTER TABLE supplier_invoices ADD COLUMN IF NOT EXISTS penalty_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (penalty_amount >= 0);`
+
+// Supplier payment runs
+
+const createPaymentRunsTable = `
+CREATE TABLE IF NOT EXISTS supplier_payment_runs (
+    run_id SERIAL PRIMARY KEY,
+    run_number VARCHAR(50) UNIQUE NOT NULL,
+    payment_date TIMESTAMP NOT NULL,
+    due_before TIMESTAMP NOT NULL,
+    supplier_id INTEGER REFERENCES suppliers(supplier_id),
+    priority VARCHAR(20) NOT NULL CHECK (priority IN ('due_date','discount','amount')) DEFAULT 'due_date',
+    max_amount DECIMAL(15,2) CHECK (max_amount > 0),
+    run_status VARCHAR(20) NOT NULL CHECK (run_status IN ('draft','approved','executed','cancelled')) DEFAULT 'draft',
+    notes TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(user_id),
+    approved_by INTEGER REFERENCES users(user_id),
+    approved_at TIMESTAMP,
+    executed_by INTEGER REFERENCES users(user_id),
+    executed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_supplier_payment_runs_status ON supplier_payment_runs(run_status);
+CREATE INDEX IF NOT EXISTS idx_supplier_payment_runs_payment_date ON supplier_payment_runs(payment_date);`
+
+const createPaymentRunLinesTable = `
+CREATE TABLE IF NOT EXISTS supplier_payment_run_lines (
+    line_id SERIAL PRIMARY KEY,
+    run_id INTEGER NOT NULL REFERENCES supplier_payment_runs(run_id) ON DELETE CASCADE,
+    invoice_id INTEGER NOT NULL REFERENCES supplier_invoices(invoice_id),
+    supplier_id INTEGER NOT NULL REFERENCES suppliers(supplier_id),
+    amount DECIMAL(15,2) NOT NULL CHECK (amount >= 0),
+    discount_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (discount_amount >= 0),
+    bank_account VARCHAR(100),
+    line_status VARCHAR(20) NOT NULL CHECK (line_status IN ('pending','paid','failed')) DEFAULT 'pending',
+    voucher_id INTEGER REFERENCES supplier_payment_vouchers(voucher_id),
+    failure_reason TEXT,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(run_id, invoice_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_supplier_payment_run_lines_run_id ON supplier_payment_run_lines(run_id);
+CREATE INDEX IF NOT EXISTS idx_supplier_payment_run_lines_invoice_id ON supplier_payment_run_lines(invoice_id);`
+
+// Bank statement reconciliation
+
+const createBankStatementsTable = `
+CREATE TABLE IF NOT EXISTS bank_statements (
+    statement_id SERIAL PRIMARY KEY,
+    bank_account VARCHAR(100) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    imported_by INTEGER NOT NULL REFERENCES users(user_id),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_bank_statements_bank_account ON bank_statements(bank_account);`
+
+const createBankStatementLinesTable = `
+CREATE TABLE IF NOT EXISTS bank_statement_lines (
+    line_id SERIAL PRIMARY KEY,
+    statement_id INTEGER NOT NULL REFERENCES bank_statements(statement_id) ON DELETE CASCADE,
+    bank_account VARCHAR(100) NOT NULL,
+    line_number INTEGER NOT NULL,
+    transaction_date DATE NOT NULL,
+    description TEXT NOT NULL,
+    reference VARCHAR(255),
+    amount DECIMAL(15,2) NOT NULL,
+    balance DECIMAL(15,2),
+    fingerprint CHAR(64) NOT NULL,
+    match_status VARCHAR(20) NOT NULL CHECK (match_status IN ('unmatched','matched')) DEFAULT 'unmatched',
+    match_method VARCHAR(20) CHECK (match_method IN ('auto','manual')),
+    voucher_id INTEGER UNIQUE REFERENCES supplier_payment_vouchers(voucher_id),
+    matched_by INTEGER REFERENCES users(user_id),
+    matched_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(bank_account, fingerprint)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bank_statement_lines_statement_id ON bank_statement_lines(statement_id);
+CREATE INDEX IF NOT EXISTS idx_bank_statement_lines_date ON bank_statement_lines(bank_account, transaction_date);
+CREATE INDEX IF NOT EXISTS idx_bank_statement_lines_status ON bank_statement_lines(match_status);`
+
+// General ledger
+
+const createGLAccountsTable = `
+CREATE TABLE IF NOT EXISTS gl_accounts (
+    account_id SERIAL PRIMARY KEY,
+    account_code VARCHAR(20) UNIQUE NOT NULL,
+    account_name VARCHAR(100) NOT NULL,
+    account_type VARCHAR(20) NOT NULL CHECK (account_type IN ('asset','liability','equity','revenue','expense')),
+    description TEXT,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);`
+
+const seedGLAccounts = `
+INSERT INTO gl_accounts (account_code, account_name, account_type) VALUES
+    ('1100', 'Cash on hand', 'asset'),
+    ('1110', 'Bank', 'asset'),
+    ('1300', 'Inventory', 'asset'),
+    ('2100', 'Accounts payable', 'liability'),
+    ('2150', 'Goods received not invoiced', 'liability'),
+    ('3100', 'Owner equity', 'equity'),
+    ('3200', 'Retained earnings', 'equity'),
+    ('4100', 'Sales revenue', 'revenue'),
+    ('4900', 'Purchase discounts', 'revenue'),
+    ('5100', 'Cost of goods sold', 'expense'),
+    ('5300', 'Inventory adjustments', 'expense')
+ON CONFLICT (account_code) DO NOTHING;`
+
+const createGLPostingRulesTable = `
+CREATE TABLE IF NOT EXISTS gl_posting_rules (
+    rule_key VARCHAR(50) PRIMARY KEY,
+    account_id INTEGER NOT NULL REFERENCES gl_accounts(account_id),
+    updated_at TIMESTAMP DEFAULT NOW()
+);`
+
+const seedGLPostingRules = `
+INSERT INTO gl_posting_rules (rule_key, account_id)
+SELECT rules.rule_key, a.account_id
+FROM (VALUES
+    ('cash', '1100'),
+    ('bank', '1110'),
+    ('inventory', '1300'),
+    ('accounts_payable', '2100'),
+    ('goods_received_not_invoiced', '2150'),
+    ('purchase_discount', '4900'),
+    ('inventory_adjustment', '5300')
+) AS rules(rule_key, account_code)
+JOIN gl_accounts a ON a.account_code = rules.account_code
+ON CONFLICT (rule_key) DO NOTHING;`
+
+const createJournalEntriesTable = `
+CREATE TABLE IF NOT EXISTS journal_entries (
+    journal_id SERIAL PRIMARY KEY,
+    journal_number VARCHAR(50) UNIQUE NOT NULL,
+    entry_date DATE NOT NULL,
+    source_type VARCHAR(30) NOT NULL CHECK (source_type IN ('manual','goods_receipt','supplier_invoice','supplier_debit_note','payment_voucher','stock_adjustment')),
+    source_id INTEGER,
+    source_number VARCHAR(50),
+    description VARCHAR(255) NOT NULL,
+    journal_status VARCHAR(20) NOT NULL CHECK (journal_status IN ('posted','reversed')) DEFAULT 'posted',
+    reversal_of_id INTEGER UNIQUE REFERENCES journal_entries(journal_id),
+    posted_by INTEGER REFERENCES users(user_id),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_journal_entries_entry_date ON journal_entries(entry_date);
+CREATE INDEX IF NOT EXISTS idx_journal_entries_source ON journal_entries(source_type, source_id);`
+
+const createJournalLinesTable = `
+CREATE TABLE IF NOT EXISTS journal_lines (
+    line_id SERIAL PRIMARY KEY,
+    journal_id INTEGER NOT NULL REFERENCES journal_entries(journal_id),
+    account_id INTEGER NOT NULL REFERENCES gl_accounts(account_id),
+    debit DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (debit >= 0),
+    credit DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (credit >= 0),
+    memo TEXT,
+    CHECK ((debit > 0 AND credit = 0) OR (credit > 0 AND debit = 0))
+);
+
+CREATE INDEX IF NOT EXISTS idx_journal_lines_journal_id ON journal_lines(journal_id);
+CREATE INDEX IF NOT EXISTS idx_journal_lines_account_id ON journal_lines(account_id);`
+
+const createAccountingPeriodsTable = `
+CREATE TABLE IF NOT EXISTS accounting_periods (
+    period_start DATE PRIMARY KEY CHECK (EXTRACT(DAY FROM period_start) = 1),
+    period_status VARCHAR(20) NOT NULL CHECK (period_status IN ('open','closed')) DEFAULT 'open',
+    closed_by INTEGER REFERENCES users(user_id),
+    closed_at TIMESTAMP
+);`
+
+// Tax
+
+const createTaxCodesTable = `
+CREATE TABLE IF NOT EXISTS tax_codes (
+    tax_code_id SERIAL PRIMARY KEY,
+    code VARCHAR(20) UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    rate DECIMAL(5,2) NOT NULL CHECK (rate >= 0 AND rate <= 100),
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);`
+
+const seedTaxCodes = `
+INSERT INTO tax_codes (code, name, rate) VALUES
+    ('PPN11', 'PPN 11%', 11),
+    ('PPN12', 'PPN 12%', 12),
+    ('PPN0', 'PPN 0% (zero rated)', 0),
+    ('NONPPN', 'Not subject to PPN', 0)
+ON CONFLICT (code) DO NOTHING;`
+
+const alterSupplierInvoicesAddTax = `
+ALTER TABLE supplier_invoices ADD COLUMN IF NOT EXISTS tax_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (tax_amount >= 0);
+ALTER TABLE supplier_invoices ADD COLUMN IF NOT EXISTS tax_invoice_number VARCHAR(30);`
+
+const alterPurchaseOrdersAddTax = `
+ALTER TABLE purchase_orders_parts ADD COLUMN IF NOT EXISTS prices_include_tax BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE purchase_order_details ADD COLUMN IF NOT EXISTS tax_code_id INTEGER REFERENCES tax_codes(tax_code_id);
+ALTER TABLE purchase_order_details ADD COLUMN IF NOT EXISTS tax_rate DECIMAL(5,2) NOT NULL DEFAULT 0;
+ALTER TABLE purchase_order_details ADD COLUMN IF NOT EXISTS tax_amount DECIMAL(15,2) NOT NULL DEFAULT 0;`
+
+const createSalesInvoicesTable = `
+CREATE TABLE IF NOT EXISTS sales_invoices (
+    invoice_id SERIAL PRIMARY KEY,
+    invoice_number VARCHAR(50) UNIQUE NOT NULL,
+    customer_id INTEGER NOT NULL REFERENCES customers(customer_id),
+    invoice_date DATE NOT NULL,
+    prices_include_tax BOOLEAN NOT NULL DEFAULT FALSE,
+    subtotal DECIMAL(15,2) NOT NULL CHECK (subtotal >= 0),
+    tax_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (tax_amount >= 0),
+    total_amount DECIMAL(15,2) NOT NULL CHECK (total_amount >= 0),
+    tax_invoice_number VARCHAR(30) UNIQUE,
+    invoice_status VARCHAR(20) NOT NULL CHECK (invoice_status IN ('issued','void')) DEFAULT 'issued',
+    notes TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(user_id),
+    voided_by INTEGER REFERENCES users(user_id),
+    voided_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_sales_invoices_customer_id ON sales_invoices(customer_id);
+CREATE INDEX IF NOT EXISTS idx_sales_invoices_date ON sales_invoices(invoice_date);`
+
+const createSalesInvoiceLinesTable = `
+CREATE TABLE IF NOT EXISTS sales_invoice_lines (
+    line_id SERIAL PRIMARY KEY,
+    invoice_id INTEGER NOT NULL REFERENCES sales_invoices(invoice_id),
+    product_id INTEGER NOT NULL REFERENCES products_spare_parts(product_id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_price DECIMAL(15,2) NOT NULL CHECK (unit_price >= 0),
+    discount_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (discount_amount >= 0),
+    tax_code_id INTEGER REFERENCES tax_codes(tax_code_id),
+    tax_rate DECIMAL(5,2) NOT NULL DEFAULT 0,
+    tax_base DECIMAL(15,2) NOT NULL,
+    tax_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    line_total DECIMAL(15,2) NOT NULL,
+    unit_cost DECIMAL(15,2) NOT NULL CHECK (unit_cost >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sales_invoice_lines_invoice_id ON sales_invoice_lines(invoice_id);`
+
+const alterJournalEntriesSourceTypeSalesInvoice = `
+ALTER TABLE journal_entries DROP CONSTRAINT IF EXISTS journal_entries_source_type_check;
+ALTER TABLE journal_entries ADD CONSTRAINT journal_entries_source_type_check
+    CHECK (source_type IN ('manual','goods_receipt','supplier_invoice','supplier_debit_note','payment_voucher','stock_adjustment','sales_invoice'));`
+
+const seedTaxGLAccounts = `
+INSERT INTO gl_accounts (account_code, account_name, account_type) VALUES
+    ('1200', 'Accounts receivable', 'asset'),
+    ('1400', 'VAT input', 'asset'),
+    ('2200', 'VAT output', 'liability')
+ON CONFLICT (account_code) DO NOTHING;
+
+INSERT INTO gl_posting_rules (rule_key, account_id)
+SELECT rules.rule_key, a.account_id
+FROM (VALUES
+    ('accounts_receivable', '1200'),
+    ('vat_input', '1400'),
+    ('vat_output', '2200'),
+    ('sales_revenue', '4100'),
+    ('cost_of_goods_sold', '5100')
+) AS rules(rule_key, account_code)
+JOIN gl_accounts a ON a.account_code = rules.account_code
+ON CONFLICT (rule_key) DO NOTHING;`
+
+// Multi-currency
+
+const createExchangeRatesTable = `
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    rate_id SERIAL PRIMARY KEY,
+    currency_code VARCHAR(3) NOT NULL,
+    rate_date DATE NOT NULL,
+    rate DECIMAL(18,6) NOT NULL CHECK (rate > 0),
+    source VARCHAR(20) NOT NULL CHECK (source IN ('manual','import')) DEFAULT 'manual',
+    created_by INTEGER REFERENCES users(user_id),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (currency_code, rate_date)
+);`
+
+// alterPurchasingDocumentsAddCurrency gives every purchasing document a currency and the rate it was
+// booked at. Existing documents are in the base currency.
+const alterPurchasingDocumentsAddCurrency = `
+ALTER TABLE purchase_orders_parts ADD COLUMN IF NOT EXISTS currency_code VARCHAR(3) NOT NULL DEFAULT 'IDR';
+ALTER TABLE purchase_orders_parts ADD COLUMN IF NOT EXISTS exchange_rate DECIMAL(18,6) NOT NULL DEFAULT 1 CHECK (exchange_rate > 0);
+ALTER TABLE goods_receipts ADD COLUMN IF NOT EXISTS currency_code VARCHAR(3) NOT NULL DEFAULT 'IDR';
+ALTER TABLE goods_receipts ADD COLUMN IF NOT EXISTS exchange_rate DECIMAL(18,6) NOT NULL DEFAULT 1 CHECK (exchange_rate > 0);
+ALTER TABLE supplier_invoices ADD COLUMN IF NOT EXISTS currency_code VARCHAR(3) NOT NULL DEFAULT 'IDR';
+ALTER TABLE supplier_invoices ADD COLUMN IF NOT EXISTS exchange_rate DECIMAL(18,6) NOT NULL DEFAULT 1 CHECK (exchange_rate > 0);
+ALTER TABLE supplier_payment_vouchers ADD COLUMN IF NOT EXISTS currency_code VARCHAR(3) NOT NULL DEFAULT 'IDR';
+ALTER TABLE supplier_payment_vouchers ADD COLUMN IF NOT EXISTS exchange_rate DECIMAL(18,6) NOT NULL DEFAULT 1 CHECK (exchange_rate > 0);
+ALTER TABLE supplier_debit_notes ADD COLUMN IF NOT EXISTS currency_code VARCHAR(3) NOT NULL DEFAULT 'IDR';
+ALTER TABLE supplier_debit_notes ADD COLUMN IF NOT EXISTS exchange_rate DECIMAL(18,6) NOT NULL DEFAULT 1 CHECK (exchange_rate > 0);
+CREATE INDEX IF NOT EXISTS idx_supplier_invoices_currency_code ON supplier_invoices(currency_code);`
+
+const alterSupplierPaymentAllocationsAddFXGainLoss = `
+ALTER TABLE supplier_payment_allocations ADD COLUMN IF NOT EXISTS fx_gain_loss DECIMAL(15,2) NOT NULL DEFAULT 0;`
+
+const seedFXGLAccounts = `
+INSERT INTO gl_accounts (account_code, account_name, account_type) VALUES
+    ('7100', 'Realized exchange gain/loss', 'expense')
+ON CONFLICT (account_code) DO NOTHING;
+
+INSERT INTO gl_posting_rules (rule_key, account_id)
+SELECT rules.rule_key, a.account_id
+FROM (VALUES
+    ('realized_fx_gain_loss', '7100')
+) AS rules(rule_key, account_code)
+JOIN gl_accounts a ON a.account_code = rules.account_code
+ON CONFLICT (rule_key) DO NOTHING;`
+
+// Customer receivables
+
+const alterCustomersAddCredit = `
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS credit_limit DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (credit_limit >= 0);
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS payment_term_id INTEGER REFERENCES payment_terms(term_id);`
+
+// alterSalesInvoicesAddPaymentTerms records whether a sale was paid in cash or on credit and when it
+// falls due. Existing invoices are cash sales due on their invoice date.
+const alterSalesInvoicesAddPaymentTerms = `
+ALTER TABLE sales_invoices ADD COLUMN IF NOT EXISTS payment_type VARCHAR(10) NOT NULL CHECK (payment_type IN ('cash','credit')) DEFAULT 'cash';
+ALTER TABLE sales_invoices ADD COLUMN IF NOT EXISTS due_date DATE;
+UPDATE sales_invoices SET due_date = invoice_date WHERE due_date IS NULL;
+ALTER TABLE sales_invoices ALTER COLUMN due_date SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_sales_invoices_due_date ON sales_invoices(due_date);`
+
+const createCustomerReceiptsTable = `
+CREATE TABLE IF NOT EXISTS customer_receipts (
+    receipt_id SERIAL PRIMARY KEY,
+    receipt_number VARCHAR(50) UNIQUE NOT NULL,
+    customer_id INTEGER NOT NULL REFERENCES customers(customer_id),
+    receipt_date DATE NOT NULL,
+    payment_method VARCHAR(20) NOT NULL CHECK (payment_method IN ('cash','transfer','check')),
+    payment_reference VARCHAR(100),
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    receipt_status VARCHAR(10) NOT NULL CHECK (receipt_status IN ('posted','void')) DEFAULT 'posted',
+    notes TEXT,
+    received_by INTEGER NOT NULL REFERENCES users(user_id),
+    voided_by INTEGER REFERENCES users(user_id),
+    voided_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_receipts_customer_id ON customer_receipts(customer_id);
+CREATE INDEX IF NOT EXISTS idx_customer_receipts_receipt_date ON customer_receipts(receipt_date);`
+
+const createCustomerReceiptAllocationsTable = `
+CREATE TABLE IF NOT EXISTS customer_receipt_allocations (
+    allocation_id SERIAL PRIMARY KEY,
+    receipt_id INTEGER NOT NULL REFERENCES customer_receipts(receipt_id) ON DELETE CASCADE,
+    invoice_id INTEGER NOT NULL REFERENCES sales_invoices(invoice_id),
+    allocated_amount DECIMAL(15,2) NOT NULL CHECK (allocated_amount > 0),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_receipt_allocations_receipt_id ON customer_receipt_allocations(receipt_id);
+CREATE INDEX IF NOT EXISTS idx_customer_receipt_allocations_invoice_id ON customer_receipt_allocations(invoice_id);`
+
+const alterJournalEntriesSourceTypeCustomerReceipt = `
+ALTER TABLE journal_entries DROP CONSTRAINT IF EXISTS journal_entries_source_type_check;
+ALTER TABLE journal_entries ADD CONSTRAINT journal_entries_source_type_check
+    CHECK (source_type IN ('manual','goods_receipt','supplier_invoice','supplier_debit_note','payment_voucher','stock_adjustment','sales_invoice','customer_receipt'));`
+
+// Role-based access control
+
+// createRolePermissionsTable stores the permissions granted to each role. Permission codes are
+// defined in code and the admin role implicitly holds all of them. The other roles get their
+// default grants only when the table is created, so later edits survive restarts.
+const createRolePermissionsTable = `
+DO $$
+BEGIN
+IF to_regclass('role_permissions') IS NULL THEN
+    CREATE TABLE role_permissions (
+        role VARCHAR(20) NOT NULL CHECK (role IN ('admin','sales','cashier','mechanic','manager')),
+        permission_code VARCHAR(60) NOT NULL,
+        granted_by INTEGER REFERENCES users(user_id),
+        created_at TIMESTAMP DEFAULT NOW(),
+        PRIMARY KEY (role, permission_code)
+    );
+
+    INSERT INTO role_permissions (role, permission_code)
+    SELECT 'manager', code FROM unnest(ARRAY[
+        'job.manage', 'customer.read', 'customer.write', 'supplier.read', 'supplier.write',
+        'vehicle.read', 'vehicle.write', 'product.read', 'product.write', 'purchase_order.read',
+        'purchase_order.write', 'purchase_order.approve', 'goods_receipt.read', 'goods_receipt.write',
+        'purchase_return.read', 'purchase_return.write', 'purchase_return.approve', 'landed_cost.read',
+        'landed_cost.write', 'price_list.read', 'price_list.write', 'stock.read', 'stock.move',
+        'stock.adjust', 'stock.approve_adjustment', 'payment_term.read', 'payment_term.write',
+        'supplier_invoice.read', 'supplier_invoice.write', 'supplier_payment.read',
+        'supplier_payment.write', 'payment_run.read', 'payment_run.write', 'payment_run.approve',
+        'bank_statement.read', 'bank_statement.reconcile', 'exchange_rate.read', 'exchange_rate.write',
+        'gl.read', 'gl.post', 'gl.manage', 'tax.read', 'tax.manage', 'sales_invoice.read',
+        'sales_invoice.create', 'sales_invoice.void', 'customer_receipt.read',
+        'customer_receipt.write', 'customer_account.read'
+    ]) AS code
+    ON CONFLICT DO NOTHING;
+
+    INSERT INTO role_permissions (role, permission_code)
+    SELECT 'sales', code FROM unnest(ARRAY[
+        'customer.read', 'customer.write', 'vehicle.read', 'product.read', 'stock.read',
+        'payment_term.read', 'tax.read', 'sales_invoice.read', 'sales_invoice.create',
+        'customer_receipt.read', 'customer_account.read'
+    ]) AS code
+    ON CONFLICT DO NOTHING;
+
+    INSERT INTO role_permissions (role, permission_code)
+    SELECT 'cashier', code FROM unnest(ARRAY[
+        'customer.read', 'product.read', 'payment_term.read', 'tax.read', 'sales_invoice.read',
+        'sales_invoice.create', 'customer_receipt.read', 'customer_receipt.write',
+        'customer_account.read'
+    ]) AS code
+    ON CONFLICT DO NOTHING;
+
+    INSERT INTO role_permissions (role, permission_code)
+    SELECT 'mechanic', code FROM unnest(ARRAY[
+        'vehicle.read', 'product.read', 'stock.read', 'stock.move'
+    ]) AS code
+    ON CONFLICT DO NOTHING;
+END IF;
+END $$;`
+
+
+// Refresh tokens
+
+// createUserRefreshTokensTable stores the SHA-256 hash of every refresh token issued for a
+// session. A token is exchanged once; rotated_at marks it used so a replay can be detected.
+const createUserRefreshTokensTable = `
+CREATE TABLE IF NOT EXISTS user_refresh_tokens (
+    token_id SERIAL PRIMARY KEY,
+    session_id INTEGER NOT NULL REFERENCES user_sessions(session_id) ON DELETE CASCADE,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    issued_at TIMESTAMP DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    rotated_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_refresh_tokens_session_id ON user_refresh_tokens(session_id);`
+
+// Two-factor authentication
+
+// createUserTwoFactorTable stores the TOTP secret of each enrolled user. enabled_at stays NULL
+// until the user confirms a code; last_used_step stops a code from being accepted twice.
+const createUserTwoFactorTable = `
+CREATE TABLE IF NOT EXISTS user_two_factor (
+    user_id INTEGER PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL,
+    enabled_at TIMESTAMP,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW()
+);`
+
+const createUserRecoveryCodesTable = `
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    code_id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    code_hash VARCHAR(255) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_recovery_codes_user_id ON user_recovery_codes(user_id);`
+
+// createUserLoginChallengesTable stores logins waiting for a second factor, keyed by the SHA-256
+// hash of the challenge token handed to the client
+const createUserLoginChallengesTable = `
+CREATE TABLE IF NOT EXISTS user_login_challenges (
+    challenge_id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    enrolling BOOLEAN NOT NULL DEFAULT FALSE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_login_challenges_user_id ON user_login_challenges(user_id);`
+
+// Brute-force protection
+
+// createLoginAttemptsTable logs every login attempt. The username is kept as typed, so attempts
+// on usernames that do not exist are logged too.
+const createLoginAttemptsTable = `
+CREATE TABLE IF NOT EXISTS login_attempts (
+    attempt_id BIGSERIAL PRIMARY KEY,
+    username VARCHAR(100) NOT NULL,
+    user_id INTEGER REFERENCES users(user_id) ON DELETE SET NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    user_agent TEXT NOT NULL DEFAULT '',
+    outcome VARCHAR(30) NOT NULL CHECK (outcome IN ('success','failed','two_factor_required','two_factor_failed','inactive','locked','throttled')),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_ip_created ON login_attempts(ip_address, created_at);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_user_created ON login_attempts(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_created ON login_attempts(created_at);`
+
+// createUserLoginLocksTable counts the consecutive failed logins of each user and holds the
+// lock they lead to. A row exists only while a user has failures since their last login.
+const createUserLoginLocksTable = `
+CREATE TABLE IF NOT EXISTS user_login_locks (
+    user_id INTEGER PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMP,
+    locked_until TIMESTAMP
+);`
+
+// Password reset
+
+// createPasswordResetTokensTable holds the one-time tokens emailed for self-service password
+// resets. Only the SHA-256 hash of each token is stored.
+const createPasswordResetTokensTable = `
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    token_id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    ip_address VARCHAR(45),
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_created ON password_reset_tokens(user_id, created_at);`
+
+// Password policy
+
+// alterUsersAddPasswordPolicy tracks when each password was set, for the maximum password age,
+// and whether an administrator has forced the user to choose a new one
+const alterUsersAddPasswordPolicy = `
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP NOT NULL DEFAULT NOW();
+ALTER TABLE users ADD COLUMN IF NOT EXISTS must_change_password BOOLEAN NOT NULL DEFAULT FALSE;`
+
+// alterUserSessionsAddPasswordChangeRequired marks sessions that may only be used to change the
+// password, because it expired or a change was forced
+const alterUserSessionsAddPasswordChangeRequired = `
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS password_change_required BOOLEAN NOT NULL DEFAULT FALSE;`
+
+// createPasswordHistoryTable keeps the hashes of the passwords users had before their current
+// one, so recent passwords cannot be reused
+const createPasswordHistoryTable = `
+CREATE TABLE IF NOT EXISTS password_history (
+    history_id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_history_user_created ON password_history(user_id, created_at);`
+
+// API keys
+
+// createAPIKeysTable holds the keys integrations use instead of a login session. Only the
+// SHA-256 hash of each key is stored; the prefix is the part of the key shown in lists.
+const createAPIKeysTable = `
+CREATE TABLE IF NOT EXISTS api_keys (
+    key_id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) UNIQUE NOT NULL,
+    key_hash CHAR(64) UNIQUE NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    allowed_ips TEXT[] NOT NULL DEFAULT '{}',
+    created_by INTEGER NOT NULL REFERENCES users(user_id),
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    last_used_ip VARCHAR(45),
+    revoked_at TIMESTAMP,
+    revoked_by INTEGER REFERENCES users(user_id),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_created_by ON api_keys(created_by);`
+
+// Token signing keys
+
+// createJWTSigningKeysTable holds the keys access tokens are signed with. Private keys are
+// encrypted with JWT_SECRET_KEY. The key without rotated_at signs new tokens.
+const createJWTSigningKeysTable = `
+CREATE TABLE IF NOT EXISTS jwt_signing_keys (
+    kid VARCHAR(32) PRIMARY KEY,
+    algorithm VARCHAR(10) NOT NULL CHECK (algorithm IN ('RS256', 'EdDSA')),
+    private_key TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    rotated_at TIMESTAMP,
+    retires_at TIMESTAMP
+);`
+
+// Audit log
+
+// createAuditLogTable holds every change to the audited tables. Rows are written by the
+// audit_changes triggers, never by the application, and cannot be changed or deleted.
+const createAuditLogTable = `
+CREATE TABLE IF NOT EXISTS audit_log (
+    audit_id BIGSERIAL PRIMARY KEY,
+    occurred_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    user_id INTEGER,
+    session_id INTEGER,
+    api_key_id INTEGER,
+    ip_address VARCHAR(45),
+    entity VARCHAR(63) NOT NULL,
+    entity_id TEXT NOT NULL,
+    action VARCHAR(10) NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    before_data JSONB,
+    after_data JSONB
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_user_id ON audit_log(user_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_occurred_at ON audit_log(occurred_at);`
+
+const createAuditLogAppendOnlyTrigger = `
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only, % is not allowed', TG_OP;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON audit_log;
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();`
+
+// createAuditRecordChangeFunction records a row change in the audit log. The trigger argument
+// lists the key columns of the table. Creates keep the new row and deletes the old one; updates
+// keep only the changed columns, and are skipped when only bookkeeping columns changed. Secrets
+// are redacted. The actor comes from the app.audit_actor setting of the database session.
+const createAuditRecordChangeFunction = `
+CREATE OR REPLACE FUNCTION audit_record_change() RETURNS trigger AS $$
+DECLARE
+    actor JSONB := NULLIF(current_setting('app.audit_actor', true), '')::JSONB;
+    redacted_columns TEXT[] := ARRAY['password_hash', 'secret', 'key_hash', 'private_key', 'token_hash', 'code_hash'];
+    ignored_columns TEXT[] := ARRAY['updated_at', 'last_used_at', 'last_used_ip', 'last_used_step'];
+    old_row JSONB;
+    new_row JSONB;
+    before_row JSONB;
+    after_row JSONB;
+    changed_entity_id TEXT;
+    redacted_column TEXT;
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        old_row := to_jsonb(OLD);
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        new_row := to_jsonb(NEW);
+    END IF;
+
+    IF TG_OP = 'UPDATE' THEN
+        SELECT jsonb_object_agg(n.key, o.value), jsonb_object_agg(n.key, n.value)
+        INTO before_row, after_row
+        FROM jsonb_each(new_row) n
+        JOIN jsonb_each(old_row) o ON o.key = n.key
+        WHERE n.value IS DISTINCT FROM o.value AND NOT n.key = ANY(ignored_columns);
+
+        IF before_row IS NULL THEN
+            RETURN NULL;
+        END IF;
+    ELSE
+        before_row := old_row;
+        after_row := new_row;
+    END IF;
+
+    FOREACH redacted_column IN ARRAY redacted_columns LOOP
+        IF before_row ? redacted_column THEN
+            before_row := jsonb_set(before_row, ARRAY[redacted_column], '"[redacted]"');
+        END IF;
+        IF after_row ? redacted_column THEN
+            after_row := jsonb_set(after_row, ARRAY[redacted_column], '"[redacted]"');
+        END IF;
+    END LOOP;
+
+    SELECT string_agg(COALESCE(new_row, old_row) ->> k.key_column, ':' ORDER BY k.key_position)
+    INTO changed_entity_id
+    FROM unnest(string_to_array(TG_ARGV[0], ',')) WITH ORDINALITY AS k(key_column, key_position);
+
+    INSERT INTO audit_log (user_id, session_id, api_key_id, ip_address, entity, entity_id, action, before_data, after_data)
+    VALUES (
+        (actor ->> 'user_id')::INTEGER,
+        (actor ->> 'session_id')::INTEGER,
+        (actor ->> 'api_key_id')::INTEGER,
+        actor ->> 'ip_address',
+        TG_TABLE_NAME,
+        changed_entity_id,
+        CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END,
+        before_row,
+        after_row
+    );
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;`
+
+// createAuditTriggers adds the audit_changes trigger to the business and access control
+// tables with their key columns. Logs, sessions and one-time tokens are not audited.
+const createAuditTriggers = `
+DO $$
+DECLARE
+    audited RECORD;
+BEGIN
+    FOR audited IN SELECT * FROM (VALUES
+        ('users', 'user_id'),
+        ('role_permissions', 'role,permission_code'),
+        ('user_two_factor', 'user_id'),
+        ('api_keys', 'key_id'),
+        ('customers', 'customer_id'),
+        ('suppliers', 'supplier_id'),
+        ('vehicle_brands', 'brand_id'),
+        ('vehicle_categories', 'category_id'),
+        ('vehicle_models', 'model_id'),
+        ('product_categories', 'category_id'),
+        ('products_spare_parts', 'product_id'),
+        ('purchase_orders_parts', 'po_id'),
+        ('purchase_order_details', 'po_detail_id'),
+        ('blanket_order_terms', 'po_id'),
+        ('blanket_order_releases', 'release_id'),
+        ('goods_receipts', 'receipt_id'),
+        ('goods_receipt_details', 'receipt_detail_id'),
+        ('stock_adjustments', 'adjustment_id'),
+        ('purchase_returns', 'return_id'),
+        ('purchase_return_details', 'return_detail_id'),
+        ('supplier_debit_notes', 'debit_note_id'),
+        ('supplier_debit_note_applications', 'application_id'),
+        ('landed_cost_vouchers', 'voucher_id'),
+        ('landed_cost_voucher_receipts', 'voucher_id,receipt_id'),
+        ('landed_cost_allocations', 'allocation_id'),
+        ('supplier_price_lists', 'price_list_id'),
+        ('supplier_price_breaks', 'price_break_id'),
+        ('supplier_invoices', 'invoice_id'),
+        ('supplier_payments', 'payment_id'),
+        ('supplier_payment_vouchers', 'voucher_id'),
+        ('supplier_payment_allocations', 'allocation_id'),
+        ('supplier_payment_runs', 'run_id'),
+        ('supplier_payment_run_lines', 'line_id'),
+        ('payment_terms', 'term_id'),
+        ('bank_statements', 'statement_id'),
+        ('bank_statement_lines', 'line_id'),
+        ('gl_accounts', 'account_id'),
+        ('gl_posting_rules', 'rule_key'),
+        ('journal_entries', 'journal_id'),
+        ('journal_lines', 'line_id'),
+        ('accounting_periods', 'period_start'),
+        ('tax_codes', 'tax_code_id'),
+        ('exchange_rates', 'rate_id'),
+        ('sales_invoices', 'invoice_id'),
+        ('sales_invoice_lines', 'line_id'),
+        ('customer_receipts', 'receipt_id'),
+        ('customer_receipt_allocations', 'allocation_id')
+    ) AS t(table_name, key_columns)
+    LOOP
+        EXECUTE format('DROP TRIGGER IF EXISTS audit_changes ON %I', audited.table_name);
+        EXECUTE format(
+            'CREATE TRIGGER audit_changes AFTER INSERT OR UPDATE OR DELETE ON %I '
+            'FOR EACH ROW EXECUTE FUNCTION audit_record_change(%L)',
+            audited.table_name, audited.key_columns
+        );
+    END LOOP;
+END $$;`
+
+// alterUserSessionsAddLastActivity tracks when each session last made a request, for the idle
+// timeout
+const alterUserSessionsAddLastActivity = `
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS last_activity_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_active_activity ON user_sessions(is_active, last_activity_at);`
+
+
+// Landed cost journal
+
+const alterJournalEntriesSourceTypeLandedCost = `
+ALTER TABLE journal_entries DROP CONSTRAINT IF EXISTS journal_entries_source_type_check;
+ALTER TABLE journal_entries ADD CONSTRAINT journal_entries_source_type_check
+    CHECK (source_type IN ('manual','goods_receipt','supplier_invoice','supplier_debit_note','payment_voucher','stock_adjustment','sales_invoice','customer_receipt','landed_cost'));`
+
+const seedLandedCostGLAccounts = `
+INSERT INTO gl_accounts (account_code, account_name, account_type) VALUES
+    ('2160', 'Accrued landed costs', 'liability')
+ON CONFLICT (account_code) DO NOTHING;
+
+INSERT INTO gl_posting_rules (rule_key, account_id)
+SELECT rules.rule_key, a.account_id
+FROM (VALUES
+    ('accrued_landed_cost', '2160')
+) AS rules(rule_key, account_code)
+JOIN gl_accounts a ON a.account_code = rules.account_code
+ON CONFLICT (rule_key) DO NOTHING;`
+
+// alterLoginAttemptsOutcomePasswordReset logs forgot password requests with the login attempts, so
+// they are throttled per username and IP address
+const alterLoginAttemptsOutcomePasswordReset = `
+ALTER TABLE login_attempts DROP CONSTRAINT IF EXISTS login_attempts_outcome_check;
+ALTER TABLE login_attempts ADD CONSTRAINT login_attempts_outcome_check
+    CHECK (outcome IN ('success','failed','two_factor_required','two_factor_failed','inactive','locked','throttled','password_reset'));
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_username_created ON login_attempts(username, created_at);`
+
+// Bank reconciliation of customer receipts
+
+// alterBankStatementLinesAddReceipt lets incoming bank transactions be matched to customer
+// receipts; a line is matched to a payment voucher or a receipt, never both
+const alterBankStatementLinesAddReceipt = `
+ALTER TABLE bank_statement_lines ADD COLUMN IF NOT EXISTS receipt_id INTEGER UNIQUE REFERENCES customer_receipts(receipt_id);
+
+ALTER TABLE bank_statement_lines DROP CONSTRAINT IF EXISTS bank_statement_lines_match_target_check;
+ALTER TABLE bank_statement_lines ADD CONSTRAINT bank_statement_lines_match_target_check
+    CHECK (voucher_id IS NULL OR receipt_id IS NULL);`
+
+// Purchase price variance
+
+const seedPurchasePriceVarianceGLAccounts = `
+INSERT INTO gl_accounts (account_code, account_name, account_type) VALUES
+    ('5200', 'Purchase price variance', 'expense')
+ON CONFLICT (account_code) DO NOTHING;
+
+INSERT INTO gl_posting_rules (rule_key, account_id)
+SELECT rules.rule_key, a.account_id
+FROM (VALUES
+    ('purchase_price_variance', '5200')
+) AS rules(rule_key, account_code)
+JOIN gl_accounts a ON a.account_code = rules.account_code
+ON CONFLICT (rule_key) DO NOTHING;`
+
+// Scheduled job tick claims
+
+// alterScheduledJobRunsAddScheduledAt records the schedule tick a run was started for, so each
+// tick runs once across instances; manual runs leave it NULL
+const alterScheduledJobRunsAddScheduledAt = `
+ALTER TABLE scheduled_job_runs ADD COLUMN IF NOT EXISTS scheduled_at TIMESTAMP;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_scheduled_job_runs_tick ON scheduled_job_runs(job_name, scheduled_at);`
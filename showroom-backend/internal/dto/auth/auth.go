@@ -22,6 +22,40 @@ type LoginResponse struct {
 	User      UserInfo            `json:"user"`
 	Message   string              `json:"message"`
 	SessionID int                 `json:"session_id"`
+
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+
+	// TwoFactorRequired is always false here; it tells a session apart from a LoginChallengeResponse
+	TwoFactorRequired bool `json:"two_factor_required"`
+	// RecoveryCodes is set only when the login just completed a two-factor enrollment
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+	// PasswordChangeRequired means the session can only be used to change the password, because
+	// it has expired or an administrator forced a change
+	PasswordChangeRequired bool `json:"password_change_required"`
+}
+
+// LoginChallengeResponse is returned by login instead of a session when a second factor is
+// needed. EnrollmentRequired means the user's role requires two-factor authentication and it
+// has to be set up through /auth/login/enroll first.
+type LoginChallengeResponse struct {
+	TwoFactorRequired  bool      `json:"two_factor_required"`
+	EnrollmentRequired bool      `json:"enrollment_required"`
+	ChallengeToken     string    `json:"challenge_token"`
+	ExpiresIn          int       `json:"expires_in"`
+	ExpiresAt          time.Time `json:"expires_at"`
+	Message            string    `json:"message"`
+}
+
+// LoginVerifyRequest represents the second login step with a TOTP or recovery code
+type LoginVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// LoginEnrollRequest represents a request to set up two-factor authentication during login
+type LoginEnrollRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
 }
 
 // UserInfo represents basic user information in responses
@@ -40,7 +74,13 @@ type LogoutResponse struct {
 	Message string `json:"message"`
 }
 
-// RefreshTokenResponse represents the refresh token response payload
+// RefreshTokenRequest represents the refresh token request payload
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// RefreshTokenResponse represents the refresh token response payload. The refresh token in the
+// request can no longer be used; the client must keep the one returned here.
 type RefreshTokenResponse struct {
 	Token     string    `json:"token"`
 	TokenType string    `json:"token_type"`
@@ -49,6 +89,9 @@ type RefreshTokenResponse struct {
 	User      UserInfo  `json:"user"`
 	Message   string    `json:"message"`
 	SessionID int       `json:"session_id"`
+
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
 }
 
 // ChangePasswordRequest represents the change password request payload
@@ -63,6 +106,19 @@ type ChangePasswordResponse struct {
 	Message string `json:"message"`
 }
 
+// ForgotPasswordRequest represents the forgot password request payload
+type ForgotPasswordRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// ResetPasswordRequest represents the reset password request payload. Token is the one-time
+// token from the password reset email.
+type ResetPasswordRequest struct {
+	Token           string `json:"token" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6,max=100"`
+	ConfirmPassword string `json:"confirm_password" binding:"required"`
+}
+
 // ProfileResponse represents the profile response payload
 type ProfileResponse struct {
 	User     user.User          `json:"user"`
@@ -0,0 +1,115 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/middleware"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services"
+)
+
+// APIKeyHandler handles API key HTTP requests
+type APIKeyHandler struct {
+	apiKeyService *services.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeyService *services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateAPIKey handles creating an API key that acts for the current user
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req user.APIKeyCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	role, _ := middleware.GetCurrentRole(c)
+	key, err := h.apiKeyService.CreateKey(c.Request.Context(), &req, middleware.GetCurrentUserID(c), role)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to create API key", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.NewSuccessResponse(
+		"API key created successfully. Store the key now, it will not be shown again", key,
+	))
+}
+
+// GetAPIKeys handles listing API keys with pagination
+func (h *APIKeyHandler) GetAPIKeys(c *gin.Context) {
+	var params user.APIKeyFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	keys, err := h.apiKeyService.ListKeys(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to list API keys", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"API keys retrieved successfully", keys,
+	))
+}
+
+// GetAPIKey handles getting an API key by ID
+func (h *APIKeyHandler) GetAPIKey(c *gin.Context) {
+	id, err := parseIntParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid API key ID", "API key ID must be a valid integer",
+		))
+		return
+	}
+
+	key, err := h.apiKeyService.GetKey(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"API key not found", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"API key retrieved successfully", key,
+	))
+}
+
+// RevokeAPIKey handles revoking an API key
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := parseIntParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid API key ID", "API key ID must be a valid integer",
+		))
+		return
+	}
+
+	if err := h.apiKeyService.RevokeKey(c.Request.Context(), id, middleware.GetCurrentUserID(c)); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to revoke API key", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"API key revoked successfully", nil,
+	))
+}
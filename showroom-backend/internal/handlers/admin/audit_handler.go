@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/audit"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services"
+)
+
+// AuditHandler handles audit log HTTP requests
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// GetAuditLogs handles listing the audit log with pagination
+func (h *AuditHandler) GetAuditLogs(c *gin.Context) {
+	var params audit.AuditLogFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	entries, err := h.auditService.ListEntries(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to list audit log", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Audit log retrieved successfully", entries,
+	))
+}
+
+// GetAuditLog handles getting an audit log entry by ID
+func (h *AuditHandler) GetAuditLog(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid audit log ID", "Audit log ID must be a valid integer",
+		))
+		return
+	}
+
+	entry, err := h.auditService.GetEntry(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Failed to get audit log entry", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Audit log entry retrieved successfully", entry,
+	))
+}
@@ -0,0 +1,100 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/middleware"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/jobs"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/scheduler"
+)
+
+// JobHandler handles background job HTTP requests
+type JobHandler struct {
+	scheduler *scheduler.Scheduler
+}
+
+// NewJobHandler creates a new background job handler
+func NewJobHandler(jobScheduler *scheduler.Scheduler) *JobHandler {
+	return &JobHandler{
+		scheduler: jobScheduler,
+	}
+}
+
+// ListJobs handles listing the background jobs with their schedules and latest runs
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	jobList, err := h.scheduler.Jobs(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to list background jobs", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Background jobs retrieved successfully", jobList,
+	))
+}
+
+// ListJobRuns handles listing the background job run history with pagination
+func (h *JobHandler) ListJobRuns(c *gin.Context) {
+	var params jobs.JobRunFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	// Set default pagination if not provided
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+
+	runs, err := h.scheduler.ListRuns(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to list background job runs", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Background job runs retrieved successfully", runs,
+	))
+}
+
+// TriggerJob handles running a background job immediately
+func (h *JobHandler) TriggerJob(c *gin.Context) {
+	triggeredBy := middleware.GetCurrentUserID(c)
+	if triggeredBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	run, err := h.scheduler.Trigger(c.Request.Context(), c.Param("name"), triggeredBy)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, scheduler.ErrJobNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, scheduler.ErrJobRunning):
+			status = http.StatusConflict
+		}
+		c.JSON(status, common.NewErrorResponse(
+			"Failed to trigger background job", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, common.NewSuccessResponse(
+		"Background job started", run,
+	))
+}
@@ -0,0 +1,90 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services"
+)
+
+// LoginAttemptHandler handles the login attempts log and account lockout HTTP requests
+type LoginAttemptHandler struct {
+	loginAttemptService *services.LoginAttemptService
+}
+
+// NewLoginAttemptHandler creates a new login attempt handler
+func NewLoginAttemptHandler(loginAttemptService *services.LoginAttemptService) *LoginAttemptHandler {
+	return &LoginAttemptHandler{
+		loginAttemptService: loginAttemptService,
+	}
+}
+
+// ListAttempts handles listing the login attempts log with pagination
+func (h *LoginAttemptHandler) ListAttempts(c *gin.Context) {
+	var params user.LoginAttemptFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	attempts, err := h.loginAttemptService.ListAttempts(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to list login attempts", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Login attempts retrieved successfully", attempts,
+	))
+}
+
+// GetLock handles getting the failed logins and lock of a user
+func (h *LoginAttemptHandler) GetLock(c *gin.Context) {
+	id, err := parseIntParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid user ID", "User ID must be a valid integer",
+		))
+		return
+	}
+
+	lock, err := h.loginAttemptService.GetLock(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Failed to get login lock", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Login lock retrieved successfully", lock,
+	))
+}
+
+// Unlock handles clearing the failed logins and lock of a user
+func (h *LoginAttemptHandler) Unlock(c *gin.Context) {
+	id, err := parseIntParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid user ID", "User ID must be a valid integer",
+		))
+		return
+	}
+
+	if err := h.loginAttemptService.Unlock(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to unlock user", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"User unlocked successfully", nil,
+	))
+}
@@ -150,7 +150,7 @@ func (h *PurchaseOrderHandler) AddLineItem(c *gin.Context) {
 		return
 	}
 
-	detail, err := h.poService.AddLineItem(c.Request.Context(), id, &req)
+	result, err := h.poService.AddLineItem(c.Request.Context(), id, &req)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
 			"Line item addition failed", err.Error(),
@@ -159,7 +159,39 @@ func (h *PurchaseOrderHandler) AddLineItem(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusCreated, common.NewSuccessResponse(
-		"Line item added successfully", detail,
+		"Line item added successfully", result,
+	))
+}
+
+// BulkAddLineItems handles adding several line items to a purchase order at once
+func (h *PurchaseOrderHandler) BulkAddLineItems(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid ID", "Purchase order ID must be a valid integer",
+		))
+		return
+	}
+
+	var reqs []products.PurchaseOrderDetailCreateRequest
+	if err := c.ShouldBindJSON(&reqs); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	results, err := h.poService.AddLineItems(c.Request.Context(), id, reqs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Line item addition failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.NewSuccessResponse(
+		"Line items added successfully", results,
 	))
 }
 
@@ -355,4 +387,67 @@ func (h *PurchaseOrderHandler) ProcessReceiptItem(c *gin.Context) {
 	c.JSON(http.StatusOK, common.NewSuccessResponse(
 		"Receipt item processed successfully", nil,
 	))
-}
\ No newline at end of file
+}
+// CreateBlanketRelease handles drawing a release order against a blanket or contract purchase order
+func (h *PurchaseOrderHandler) CreateBlanketRelease(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid ID", "Purchase order ID must be a valid integer",
+		))
+		return
+	}
+
+	var req products.BlanketReleaseCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	createdBy := middleware.GetCurrentUserID(c)
+	if createdBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "Creator user ID not found",
+		))
+		return
+	}
+
+	release, err := h.poService.CreateBlanketRelease(c.Request.Context(), id, &req, createdBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Blanket release failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.NewSuccessResponse(
+		"Release order created successfully", release,
+	))
+}
+
+// GetBlanketBalance handles retrieving the consumed and remaining balance of a blanket order
+func (h *PurchaseOrderHandler) GetBlanketBalance(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid ID", "Purchase order ID must be a valid integer",
+		))
+		return
+	}
+
+	balance, err := h.poService.GetBlanketBalance(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to get blanket balance", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Blanket balance retrieved successfully", balance,
+	))
+}
@@ -0,0 +1,115 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/middleware"
+	commonModels "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services"
+)
+
+// RoleHandler handles role and permission HTTP requests
+type RoleHandler struct {
+	permissionService *services.PermissionService
+}
+
+// NewRoleHandler creates a new role handler
+func NewRoleHandler(permissionService *services.PermissionService) *RoleHandler {
+	return &RoleHandler{
+		permissionService: permissionService,
+	}
+}
+
+// ListPermissions handles listing every permission that can be granted
+func (h *RoleHandler) ListPermissions(c *gin.Context) {
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Permissions retrieved successfully", h.permissionService.ListPermissions(),
+	))
+}
+
+// ListRoles handles listing the roles with their permissions
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.permissionService.ListRoles(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to list roles", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Roles retrieved successfully", roles,
+	))
+}
+
+// GetRole handles getting the permissions of a role
+func (h *RoleHandler) GetRole(c *gin.Context) {
+	role, err := h.permissionService.GetRolePermissions(c.Request.Context(), commonModels.UserRole(c.Param("role")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to get role", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Role retrieved successfully", role,
+	))
+}
+
+// UpdateRolePermissions handles replacing the permissions granted to a role
+func (h *RoleHandler) UpdateRolePermissions(c *gin.Context) {
+	var req user.RolePermissionsUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	grantedBy := middleware.GetCurrentUserID(c)
+	if grantedBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	role, err := h.permissionService.UpdateRolePermissions(c.Request.Context(), commonModels.UserRole(c.Param("role")), &req, grantedBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to update role permissions", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Role permissions updated successfully", role,
+	))
+}
+
+// MyPermissions handles getting the permissions of the current user's role
+func (h *RoleHandler) MyPermissions(c *gin.Context) {
+	claims := middleware.GetCurrentUser(c)
+	if claims == nil {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	role, err := h.permissionService.GetRolePermissions(c.Request.Context(), claims.Role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to get permissions", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Permissions retrieved successfully", role,
+	))
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/auth"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/middleware"
+	userModels "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
 )
@@ -26,14 +28,17 @@ func NewHandler(authService *services.AuthService) *Handler {
 
 // Login handles user login
 // @Summary User login
-// @Description Authenticate user and return JWT token
+// @Description Authenticate user and return JWT token. Users with two-factor authentication get a
+// @Description challenge token instead, to be answered at /auth/login/verify.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body auth.LoginRequest true "Login request"
 // @Success 200 {object} common.APIResponse{data=auth.LoginResponse}
+// @Success 202 {object} common.APIResponse{data=auth.LoginChallengeResponse}
 // @Failure 400 {object} common.ErrorResponse
 // @Failure 401 {object} common.ErrorResponse
+// @Failure 429 {object} common.ErrorResponse
 // @Router /auth/login [post]
 func (h *Handler) Login(c *gin.Context) {
 	var req auth.LoginRequest
@@ -49,19 +54,92 @@ func (h *Handler) Login(c *gin.Context) {
 	userAgent := utils.GetUserAgent(c.Request)
 
 	// Authenticate user
-	response, err := h.authService.Login(c.Request.Context(), &req, ipAddress, userAgent)
+	response, challenge, err := h.authService.Login(c.Request.Context(), &req, ipAddress, userAgent)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
-			"Login failed", err.Error(),
+		respondLoginFailed(c, err)
+		return
+	}
+
+	if challenge != nil {
+		c.JSON(http.StatusAccepted, common.NewSuccessResponse(
+			challenge.Message, challenge,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Login successful", response,
+	))
+}
+
+// VerifyLogin handles the second login step
+// @Summary Verify login
+// @Description Answer a login challenge with a TOTP or recovery code and create the session
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body auth.LoginVerifyRequest true "Login verify request"
+// @Success 200 {object} common.APIResponse{data=auth.LoginResponse}
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 429 {object} common.ErrorResponse
+// @Router /auth/login/verify [post]
+func (h *Handler) VerifyLogin(c *gin.Context) {
+	var req auth.LoginVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
 		))
 		return
 	}
 
+	ipAddress := utils.GetIPAddress(c.Request)
+	userAgent := utils.GetUserAgent(c.Request)
+
+	response, err := h.authService.VerifyLogin(c.Request.Context(), &req, ipAddress, userAgent)
+	if err != nil {
+		respondLoginFailed(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, common.NewSuccessResponse(
 		"Login successful", response,
 	))
 }
 
+// EnrollLogin handles setting up two-factor authentication during login
+// @Summary Enroll two-factor during login
+// @Description Get a TOTP secret for a user whose role requires two-factor authentication
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body auth.LoginEnrollRequest true "Login enroll request"
+// @Success 200 {object} common.APIResponse{data=user.TwoFactorEnrollment}
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Router /auth/login/enroll [post]
+func (h *Handler) EnrollLogin(c *gin.Context) {
+	var req auth.LoginEnrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	enrollment, err := h.authService.EnrollLogin(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Two-factor enrollment failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Two-factor enrollment started", enrollment,
+	))
+}
+
 // Logout handles user logout
 // @Summary User logout
 // @Description Logout user and invalidate session
@@ -209,35 +287,26 @@ func (h *Handler) ChangePassword(c *gin.Context) {
 
 // RefreshToken handles token refresh
 // @Summary Refresh token
-// @Description Refresh JWT token
+// @Description Exchange a refresh token for a new access token and a new refresh token. The
+// @Description access token may already have expired; reusing a refresh token revokes the session.
 // @Tags auth
-// @Security BearerAuth
+// @Accept json
 // @Produce json
+// @Param request body auth.RefreshTokenRequest true "Refresh token request"
 // @Success 200 {object} common.APIResponse{data=auth.RefreshTokenResponse}
+// @Failure 400 {object} common.ErrorResponse
 // @Failure 401 {object} common.ErrorResponse
 // @Router /auth/refresh [post]
 func (h *Handler) RefreshToken(c *gin.Context) {
-	// Get current token from Authorization header
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
-			"Token required", "Authorization header is required",
-		))
-		return
-	}
-
-	// Extract token
-	tokenParts := c.Request.Header.Get("Authorization")
-	if len(tokenParts) < 7 || tokenParts[:7] != "Bearer " {
-		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
-			"Invalid token format", "Token must be in Bearer format",
+	var req auth.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
 		))
 		return
 	}
-	oldToken := tokenParts[7:]
 
-	// Refresh token
-	response, err := h.authService.RefreshToken(c.Request.Context(), oldToken)
+	response, err := h.authService.RefreshToken(c.Request.Context(), req.RefreshToken)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
 			"Token refresh failed", err.Error(),
@@ -250,6 +319,23 @@ func (h *Handler) RefreshToken(c *gin.Context) {
 	))
 }
 
+// respondLoginFailed responds to a refused login, with 429 and a Retry-After header while
+// earlier failures are being throttled
+func respondLoginFailed(c *gin.Context, err error) {
+	var blocked *userModels.LoginBlockedError
+	if errors.As(err, &blocked) {
+		c.Header("Retry-After", strconv.Itoa(blocked.RetryAfterSeconds()))
+		c.JSON(http.StatusTooManyRequests, common.NewErrorResponse(
+			"Login failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+		"Login failed", err.Error(),
+	))
+}
+
 // parseIntParam parses integer parameter from URL
 func parseIntParam(c *gin.Context, param string) (int, error) {
 	value := c.Param(param)
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services"
+)
+
+// PasswordPolicyHandler handles password policy HTTP requests
+type PasswordPolicyHandler struct {
+	passwordService *services.PasswordService
+}
+
+// NewPasswordPolicyHandler creates a new password policy handler
+func NewPasswordPolicyHandler(passwordService *services.PasswordService) *PasswordPolicyHandler {
+	return &PasswordPolicyHandler{
+		passwordService: passwordService,
+	}
+}
+
+// Policy handles getting the rules new passwords must follow
+// @Summary Password policy
+// @Description Get the rules new passwords must follow
+// @Tags auth
+// @Produce json
+// @Success 200 {object} common.APIResponse{data=utils.PasswordPolicy}
+// @Router /auth/password-policy [get]
+func (h *PasswordPolicyHandler) Policy(c *gin.Context) {
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Password policy retrieved successfully", h.passwordService.GetPolicy(),
+	))
+}
+
+// ForceChange handles forcing a user to change their password
+func (h *PasswordPolicyHandler) ForceChange(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid user ID", "User ID must be a valid integer",
+		))
+		return
+	}
+
+	if err := h.passwordService.ForceChange(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to force password change", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"User must change their password at the next request", nil,
+	))
+}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/auth"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	userModels "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
+)
+
+// PasswordResetHandler handles self-service password reset HTTP requests
+type PasswordResetHandler struct {
+	passwordResetService *services.PasswordResetService
+}
+
+// NewPasswordResetHandler creates a new password reset handler
+func NewPasswordResetHandler(passwordResetService *services.PasswordResetService) *PasswordResetHandler {
+	return &PasswordResetHandler{
+		passwordResetService: passwordResetService,
+	}
+}
+
+// ForgotPassword handles requesting a password reset email
+// @Summary Forgot password
+// @Description Email a one-time password reset link. The response is the same whether or not the
+// @Description username exists.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body auth.ForgotPasswordRequest true "Forgot password request"
+// @Success 200 {object} common.APIResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 429 {object} common.ErrorResponse
+// @Router /auth/forgot-password [post]
+func (h *PasswordResetHandler) ForgotPassword(c *gin.Context) {
+	var req auth.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	err := h.passwordResetService.ForgotPassword(c.Request.Context(), &req, utils.GetIPAddress(c.Request), utils.GetUserAgent(c.Request))
+	if err != nil {
+		var blocked *userModels.LoginBlockedError
+		if errors.As(err, &blocked) {
+			c.Header("Retry-After", strconv.Itoa(blocked.RetryAfterSeconds()))
+			c.JSON(http.StatusTooManyRequests, common.NewErrorResponse(
+				"Password reset failed",
+				fmt.Sprintf("too many password reset requests, try again in %d seconds", blocked.RetryAfterSeconds()),
+			))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Password reset failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"If the account exists, a password reset link has been sent to its email address", nil,
+	))
+}
+
+// ResetPassword handles setting a new password with a reset token
+// @Summary Reset password
+// @Description Set a new password with the token from a password reset email. All sessions of
+// @Description the user are revoked.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body auth.ResetPasswordRequest true "Reset password request"
+// @Success 200 {object} common.APIResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Router /auth/reset-password [post]
+func (h *PasswordResetHandler) ResetPassword(c *gin.Context) {
+	var req auth.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	if err := h.passwordResetService.ResetPassword(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Password reset failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Password reset successfully, please log in with your new password", nil,
+	))
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/middleware"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services"
+)
+
+// SessionHandler handles the HTTP requests of users managing their own sessions
+type SessionHandler struct {
+	sessionService *services.SessionService
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(sessionService *services.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+// ListSessions handles listing the devices the current user is logged in on
+// @Summary List active sessions
+// @Description List the active sessions of the current user with their device and last activity
+// @Tags auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} common.APIResponse{data=[]user.UserSession}
+// @Failure 401 {object} common.ErrorResponse
+// @Router /auth/sessions [get]
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	sessions, err := h.sessionService.ListDevices(c.Request.Context(), userID, middleware.GetCurrentSessionID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to list sessions", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Sessions retrieved successfully", sessions,
+	))
+}
+
+// EndSession handles logging the current user out of one of their devices
+// @Summary End a session
+// @Description Log the current user out of one of their sessions
+// @Tags auth
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Session ID"
+// @Success 200 {object} common.APIResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Router /auth/sessions/{id} [delete]
+func (h *SessionHandler) EndSession(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid session ID", "Session ID must be a valid integer",
+		))
+		return
+	}
+
+	if err := h.sessionService.EndDevice(c.Request.Context(), userID, id); err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Failed to end session", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Session ended successfully", nil,
+	))
+}
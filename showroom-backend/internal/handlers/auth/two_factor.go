@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/middleware"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services"
+)
+
+// TwoFactorHandler handles two-factor authentication HTTP requests
+type TwoFactorHandler struct {
+	twoFactorService *services.TwoFactorService
+}
+
+// NewTwoFactorHandler creates a new two-factor handler
+func NewTwoFactorHandler(twoFactorService *services.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+	}
+}
+
+// Status handles getting the two-factor state of the current user
+func (h *TwoFactorHandler) Status(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	status, err := h.twoFactorService.GetStatus(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to get two-factor status", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Two-factor status retrieved successfully", status,
+	))
+}
+
+// Enroll handles generating a TOTP secret for the current user
+func (h *TwoFactorHandler) Enroll(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	enrollment, err := h.twoFactorService.BeginEnrollment(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to start two-factor enrollment", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Two-factor enrollment started", enrollment,
+	))
+}
+
+// Confirm handles enabling two-factor authentication with a code from the authenticator app
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	userID, req, ok := bindTwoFactorCode(c)
+	if !ok {
+		return
+	}
+
+	codes, err := h.twoFactorService.ConfirmEnrollment(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to enable two-factor authentication", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Two-factor authentication enabled successfully", codes,
+	))
+}
+
+// Disable handles turning off two-factor authentication for the current user
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	userID, req, ok := bindTwoFactorCode(c)
+	if !ok {
+		return
+	}
+
+	if err := h.twoFactorService.Disable(c.Request.Context(), userID, req.Code); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to disable two-factor authentication", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Two-factor authentication disabled successfully", nil,
+	))
+}
+
+// RegenerateRecoveryCodes handles replacing the recovery codes of the current user
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, req, ok := bindTwoFactorCode(c)
+	if !ok {
+		return
+	}
+
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to regenerate recovery codes", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Recovery codes regenerated successfully", codes,
+	))
+}
+
+// ResetUser handles removing the two-factor enrollment of another user
+func (h *TwoFactorHandler) ResetUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid user ID", "User ID must be a valid integer",
+		))
+		return
+	}
+
+	if err := h.twoFactorService.Reset(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to reset two-factor authentication", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Two-factor authentication reset successfully", nil,
+	))
+}
+
+// bindTwoFactorCode reads the current user and the code in the request body, responding with an
+// error when either is missing
+func bindTwoFactorCode(c *gin.Context) (int, *user.TwoFactorCodeRequest, bool) {
+	var req user.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return 0, nil, false
+	}
+
+	userID := middleware.GetCurrentUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return 0, nil, false
+	}
+
+	return userID, &req, true
+}
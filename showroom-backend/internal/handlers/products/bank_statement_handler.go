@@ -0,0 +1,309 @@
+package products
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/middleware"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	productService "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services/products"
+)
+
+// maxBankStatementFileSize limits the size of an uploaded bank statement
+const maxBankStatementFileSize = 5 << 20
+
+// BankStatementHandler handles bank statement import and reconciliation HTTP requests
+type BankStatementHandler struct {
+	reconciliationService *productService.BankReconciliationService
+}
+
+// NewBankStatementHandler creates a new bank statement handler
+func NewBankStatementHandler(reconciliationService *productService.BankReconciliationService) *BankStatementHandler {
+	return &BankStatementHandler{
+		reconciliationService: reconciliationService,
+	}
+}
+
+// ImportStatement handles uploading a CSV bank statement. The multipart form carries the
+// statement as "file", the "bank_account" it belongs to and an optional JSON column "mapping".
+func (h *BankStatementHandler) ImportStatement(c *gin.Context) {
+	importedBy := middleware.GetCurrentUserID(c)
+	if importedBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", "file is required",
+		))
+		return
+	}
+
+	if fileHeader.Size > maxBankStatementFileSize {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Bank statement import failed", fmt.Sprintf("file exceeds the %d MB limit", maxBankStatementFileSize>>20),
+		))
+		return
+	}
+
+	mapping := products.DefaultBankStatementColumnMapping()
+	if raw := c.PostForm("mapping"); raw != "" {
+		mapping = products.BankStatementColumnMapping{}
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+				"Validation failed", "Invalid column mapping", err.Error(),
+			))
+			return
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Bank statement import failed", err.Error(),
+		))
+		return
+	}
+	defer file.Close()
+
+	statement, err := h.reconciliationService.ImportStatement(
+		c.Request.Context(), c.PostForm("bank_account"), fileHeader.Filename, file, mapping, importedBy,
+	)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Bank statement import failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.NewSuccessResponse(
+		"Bank statement imported successfully", statement,
+	))
+}
+
+// GetStatement handles getting a bank statement with its lines
+func (h *BankStatementHandler) GetStatement(c *gin.Context) {
+	id, ok := parseBankStatementID(c)
+	if !ok {
+		return
+	}
+
+	var params products.BankStatementLineFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	statement, err := h.reconciliationService.GetStatement(c.Request.Context(), id, &params)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Bank statement not found", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Bank statement retrieved successfully", statement,
+	))
+}
+
+// ListStatements handles listing imported bank statements with pagination
+func (h *BankStatementHandler) ListStatements(c *gin.Context) {
+	var params products.BankStatementFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	// Set default pagination if not provided
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+
+	statements, err := h.reconciliationService.ListStatements(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to list bank statements", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Bank statements retrieved successfully", statements,
+	))
+}
+
+// DeleteStatement handles removing a bank statement without matched lines
+func (h *BankStatementHandler) DeleteStatement(c *gin.Context) {
+	id, ok := parseBankStatementID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.reconciliationService.DeleteStatement(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to delete bank statement", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Bank statement deleted successfully", nil,
+	))
+}
+
+// AutoMatch handles matching the unmatched lines of a statement to payments
+func (h *BankStatementHandler) AutoMatch(c *gin.Context) {
+	id, ok := parseBankStatementID(c)
+	if !ok {
+		return
+	}
+
+	var req products.BankAutoMatchRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+				"Validation failed", "Invalid request data", err.Error(),
+			))
+			return
+		}
+	}
+
+	matchedBy := middleware.GetCurrentUserID(c)
+	if matchedBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	result, err := h.reconciliationService.AutoMatch(c.Request.Context(), id, &req, matchedBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to match bank statement", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Bank statement matched successfully", result,
+	))
+}
+
+// MatchLine handles manually matching a statement line to a payment voucher or customer receipt
+func (h *BankStatementHandler) MatchLine(c *gin.Context) {
+	lineID, ok := parseBankStatementLineID(c)
+	if !ok {
+		return
+	}
+
+	var req products.BankManualMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	matchedBy := middleware.GetCurrentUserID(c)
+	if matchedBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	line, err := h.reconciliationService.MatchLine(c.Request.Context(), lineID, &req, matchedBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to match bank statement line", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Bank statement line matched successfully", line,
+	))
+}
+
+// UnmatchLine handles clearing the payment or receipt matched to a statement line
+func (h *BankStatementHandler) UnmatchLine(c *gin.Context) {
+	lineID, ok := parseBankStatementLineID(c)
+	if !ok {
+		return
+	}
+
+	line, err := h.reconciliationService.UnmatchLine(c.Request.Context(), lineID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to unmatch bank statement line", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Bank statement line unmatched successfully", line,
+	))
+}
+
+// GetReconciliation handles the reconciliation summary per bank account for a period
+func (h *BankStatementHandler) GetReconciliation(c *gin.Context) {
+	var params products.BankReconciliationParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	report, err := h.reconciliationService.GetReconciliation(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to get bank reconciliation", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Bank reconciliation retrieved successfully", report,
+	))
+}
+
+// parseBankStatementID reads the statement ID path parameter, responding with 400 when it is not a number
+func parseBankStatementID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid bank statement ID", "Bank statement ID must be a valid number",
+		))
+		return 0, false
+	}
+	return id, true
+}
+
+// parseBankStatementLineID reads the line ID path parameter, responding with 400 when it is not a number
+func parseBankStatementLineID(c *gin.Context) (int, bool) {
+	lineID, err := strconv.Atoi(c.Param("lineId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid line ID", "Line ID must be a valid number",
+		))
+		return 0, false
+	}
+	return lineID, true
+}
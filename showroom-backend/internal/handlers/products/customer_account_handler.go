@@ -0,0 +1,254 @@
+package products
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/middleware"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	productService "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
+)
+
+// CustomerAccountHandler handles customer receipt, credit status and statement HTTP requests
+type CustomerAccountHandler struct {
+	accountService *productService.CustomerAccountService
+}
+
+// NewCustomerAccountHandler creates a new customer account handler
+func NewCustomerAccountHandler(accountService *productService.CustomerAccountService) *CustomerAccountHandler {
+	return &CustomerAccountHandler{
+		accountService: accountService,
+	}
+}
+
+// CreateReceipt handles recording money received from a customer
+func (h *CustomerAccountHandler) CreateReceipt(c *gin.Context) {
+	var req products.CustomerReceiptCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	receivedBy := middleware.GetCurrentUserID(c)
+	if receivedBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	receipt, err := h.accountService.CreateReceipt(c.Request.Context(), &req, receivedBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to create customer receipt", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.NewSuccessResponse(
+		"Customer receipt created successfully", receipt,
+	))
+}
+
+// GetReceipt handles getting a customer receipt with its allocations
+func (h *CustomerAccountHandler) GetReceipt(c *gin.Context) {
+	id, ok := parseCustomerReceiptID(c)
+	if !ok {
+		return
+	}
+
+	receipt, err := h.accountService.GetReceipt(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Customer receipt not found", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Customer receipt retrieved successfully", receipt,
+	))
+}
+
+// ListReceipts handles listing customer receipts with pagination
+func (h *CustomerAccountHandler) ListReceipts(c *gin.Context) {
+	var params products.CustomerReceiptFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	// Set default pagination if not provided
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+
+	receipts, err := h.accountService.ListReceipts(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to list customer receipts", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Customer receipts retrieved successfully", receipts,
+	))
+}
+
+// AllocateReceipt handles allocating the unallocated amount of a receipt to sales invoices
+func (h *CustomerAccountHandler) AllocateReceipt(c *gin.Context) {
+	id, ok := parseCustomerReceiptID(c)
+	if !ok {
+		return
+	}
+
+	var req products.CustomerReceiptAllocateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	receipt, err := h.accountService.AllocateReceipt(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to allocate customer receipt", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Customer receipt allocated successfully", receipt,
+	))
+}
+
+// VoidReceipt handles voiding a customer receipt
+func (h *CustomerAccountHandler) VoidReceipt(c *gin.Context) {
+	id, ok := parseCustomerReceiptID(c)
+	if !ok {
+		return
+	}
+
+	voidedBy := middleware.GetCurrentUserID(c)
+	if voidedBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	receipt, err := h.accountService.VoidReceipt(c.Request.Context(), id, voidedBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to void customer receipt", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Customer receipt voided successfully", receipt,
+	))
+}
+
+// GetCreditStatus handles getting the open receivables of a customer against their credit limit
+func (h *CustomerAccountHandler) GetCreditStatus(c *gin.Context) {
+	customerID, ok := parseCustomerAccountID(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.accountService.GetCreditStatus(c.Request.Context(), customerID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Customer not found", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Customer credit status retrieved successfully", status,
+	))
+}
+
+// GetStatement handles getting the statement of account of a customer as JSON or a PDF download
+func (h *CustomerAccountHandler) GetStatement(c *gin.Context) {
+	customerID, ok := parseCustomerAccountID(c)
+	if !ok {
+		return
+	}
+
+	var params products.CustomerStatementParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	statement, err := h.accountService.GetStatement(c.Request.Context(), customerID, &params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to build customer statement", err.Error(),
+		))
+		return
+	}
+
+	if params.Format != products.ReportFormatPDF {
+		c.JSON(http.StatusOK, common.NewSuccessResponse(
+			"Customer statement retrieved successfully", statement,
+		))
+		return
+	}
+
+	title := fmt.Sprintf("Statement of account %s %s", statement.CustomerCode, statement.PeriodEnd.Format("2006-01"))
+	var content bytes.Buffer
+	if err := utils.WriteTextPDF(&content, title, statement.Text()); err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to render customer statement", err.Error(),
+		))
+		return
+	}
+
+	filename := fmt.Sprintf("statement_%s_%s_%s.pdf", statement.CustomerCode,
+		statement.PeriodStart.Format("20060102"), statement.PeriodEnd.Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, "application/pdf", content.Bytes())
+}
+
+// parseCustomerReceiptID reads the customer receipt ID path parameter, responding with 400 when it is not a number
+func parseCustomerReceiptID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid customer receipt ID", "Customer receipt ID must be a valid number",
+		))
+		return 0, false
+	}
+	return id, true
+}
+
+// parseCustomerAccountID reads the customer ID path parameter, responding with 400 when it is not a number
+func parseCustomerAccountID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid customer ID", "Customer ID must be a valid number",
+		))
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,159 @@
+package products
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/middleware"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	productService "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services/products"
+)
+
+// maxExchangeRateFileSize limits the size of an uploaded exchange rate file
+const maxExchangeRateFileSize = 2 << 20
+
+// ExchangeRateHandler handles exchange rate HTTP requests
+type ExchangeRateHandler struct {
+	exchangeRateService *productService.ExchangeRateService
+}
+
+// NewExchangeRateHandler creates a new exchange rate handler
+func NewExchangeRateHandler(exchangeRateService *productService.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		exchangeRateService: exchangeRateService,
+	}
+}
+
+// CreateRate handles entering the rate of a currency on a date
+func (h *ExchangeRateHandler) CreateRate(c *gin.Context) {
+	var req products.ExchangeRateCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	createdBy := middleware.GetCurrentUserID(c)
+	if createdBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	rate, err := h.exchangeRateService.CreateRate(c.Request.Context(), &req, createdBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to save exchange rate", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.NewSuccessResponse(
+		"Exchange rate saved successfully", rate,
+	))
+}
+
+// ListRates handles listing exchange rates
+func (h *ExchangeRateHandler) ListRates(c *gin.Context) {
+	var params products.ExchangeRateFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	// Set default pagination if not provided
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+
+	rates, err := h.exchangeRateService.ListRates(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to list exchange rates", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Exchange rates retrieved successfully", rates,
+	))
+}
+
+// LookupRate handles getting the rate of a currency in effect on a date
+func (h *ExchangeRateHandler) LookupRate(c *gin.Context) {
+	var params products.ExchangeRateLookupParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	rate, err := h.exchangeRateService.GetRateOn(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Exchange rate not found", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Exchange rate retrieved successfully", rate,
+	))
+}
+
+// ImportRates handles importing dated exchange rates from an uploaded CSV file
+func (h *ExchangeRateHandler) ImportRates(c *gin.Context) {
+	importedBy := middleware.GetCurrentUserID(c)
+	if importedBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", "file is required",
+		))
+		return
+	}
+
+	if fileHeader.Size > maxExchangeRateFileSize {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Exchange rate import failed", fmt.Sprintf("file exceeds the %d MB limit", maxExchangeRateFileSize>>20),
+		))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Exchange rate import failed", err.Error(),
+		))
+		return
+	}
+	defer file.Close()
+
+	result, err := h.exchangeRateService.ImportCSV(c.Request.Context(), file, importedBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Exchange rate import failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Exchange rates imported successfully", result,
+	))
+}
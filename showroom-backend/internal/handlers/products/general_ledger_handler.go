@@ -0,0 +1,418 @@
+package products
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/middleware"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	productService "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services/products"
+)
+
+// GeneralLedgerHandler handles chart of accounts, journal entry and accounting period HTTP requests
+type GeneralLedgerHandler struct {
+	ledgerService *productService.GeneralLedgerService
+}
+
+// NewGeneralLedgerHandler creates a new general ledger handler
+func NewGeneralLedgerHandler(ledgerService *productService.GeneralLedgerService) *GeneralLedgerHandler {
+	return &GeneralLedgerHandler{
+		ledgerService: ledgerService,
+	}
+}
+
+// CreateAccount handles adding an account to the chart of accounts
+func (h *GeneralLedgerHandler) CreateAccount(c *gin.Context) {
+	var req products.GLAccountCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	account, err := h.ledgerService.CreateAccount(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to create account", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.NewSuccessResponse(
+		"Account created successfully", account,
+	))
+}
+
+// GetAccount handles getting an account by ID
+func (h *GeneralLedgerHandler) GetAccount(c *gin.Context) {
+	id, ok := parseGLAccountID(c)
+	if !ok {
+		return
+	}
+
+	account, err := h.ledgerService.GetAccount(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Account not found", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Account retrieved successfully", account,
+	))
+}
+
+// UpdateAccount handles updating an account
+func (h *GeneralLedgerHandler) UpdateAccount(c *gin.Context) {
+	id, ok := parseGLAccountID(c)
+	if !ok {
+		return
+	}
+
+	var req products.GLAccountUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	account, err := h.ledgerService.UpdateAccount(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to update account", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Account updated successfully", account,
+	))
+}
+
+// ListAccounts handles listing the chart of accounts with pagination
+func (h *GeneralLedgerHandler) ListAccounts(c *gin.Context) {
+	var params products.GLAccountFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	// Set default pagination if not provided
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+
+	accounts, err := h.ledgerService.ListAccounts(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to list accounts", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Accounts retrieved successfully", accounts,
+	))
+}
+
+// GetPostingRules handles listing the accounts automatic postings book to
+func (h *GeneralLedgerHandler) GetPostingRules(c *gin.Context) {
+	rules, err := h.ledgerService.GetPostingRules(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to get posting rules", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Posting rules retrieved successfully", rules,
+	))
+}
+
+// UpdatePostingRule handles pointing a posting rule at another account
+func (h *GeneralLedgerHandler) UpdatePostingRule(c *gin.Context) {
+	var req products.GLPostingRuleUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	rules, err := h.ledgerService.UpdatePostingRule(c.Request.Context(), products.PostingRule(c.Param("rule")), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to update posting rule", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Posting rule updated successfully", rules,
+	))
+}
+
+// PostJournal handles posting a manual journal entry
+func (h *GeneralLedgerHandler) PostJournal(c *gin.Context) {
+	var req products.JournalEntryCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	postedBy := middleware.GetCurrentUserID(c)
+	if postedBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	entry, err := h.ledgerService.PostJournal(c.Request.Context(), &req, postedBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to post journal entry", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.NewSuccessResponse(
+		"Journal entry posted successfully", entry,
+	))
+}
+
+// GetJournal handles getting a journal entry with its lines
+func (h *GeneralLedgerHandler) GetJournal(c *gin.Context) {
+	id, ok := parseJournalID(c)
+	if !ok {
+		return
+	}
+
+	entry, err := h.ledgerService.GetJournal(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Journal entry not found", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Journal entry retrieved successfully", entry,
+	))
+}
+
+// ListJournals handles listing journal entries with pagination
+func (h *GeneralLedgerHandler) ListJournals(c *gin.Context) {
+	var params products.JournalEntryFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	// Set default pagination if not provided
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+
+	journals, err := h.ledgerService.ListJournals(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to list journal entries", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Journal entries retrieved successfully", journals,
+	))
+}
+
+// ReverseJournal handles reversing a posted journal entry
+func (h *GeneralLedgerHandler) ReverseJournal(c *gin.Context) {
+	id, ok := parseJournalID(c)
+	if !ok {
+		return
+	}
+
+	var req products.JournalReverseRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+				"Validation failed", "Invalid request data", err.Error(),
+			))
+			return
+		}
+	}
+
+	postedBy := middleware.GetCurrentUserID(c)
+	if postedBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	reversal, err := h.ledgerService.ReverseJournal(c.Request.Context(), id, &req, postedBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to reverse journal entry", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.NewSuccessResponse(
+		"Journal entry reversed successfully", reversal,
+	))
+}
+
+// GetTrialBalance handles the trial balance for a date range
+func (h *GeneralLedgerHandler) GetTrialBalance(c *gin.Context) {
+	var params products.TrialBalanceParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	trialBalance, err := h.ledgerService.GetTrialBalance(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to get trial balance", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Trial balance retrieved successfully", trialBalance,
+	))
+}
+
+// ListPeriods handles listing the accounting periods of a year
+func (h *GeneralLedgerHandler) ListPeriods(c *gin.Context) {
+	year := 0
+	if raw := c.Query("year"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+				"Invalid year", "Year must be a valid number",
+			))
+			return
+		}
+		year = parsed
+	}
+
+	periods, err := h.ledgerService.ListPeriods(c.Request.Context(), year)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to list accounting periods", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Accounting periods retrieved successfully", periods,
+	))
+}
+
+// ClosePeriod handles closing an accounting period
+func (h *GeneralLedgerHandler) ClosePeriod(c *gin.Context) {
+	var req products.AccountingPeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	closedBy := middleware.GetCurrentUserID(c)
+	if closedBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	if err := h.ledgerService.ClosePeriod(c.Request.Context(), &req, closedBy); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to close accounting period", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Accounting period closed successfully", nil,
+	))
+}
+
+// ReopenPeriod handles reopening a closed accounting period
+func (h *GeneralLedgerHandler) ReopenPeriod(c *gin.Context) {
+	var req products.AccountingPeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	reopenedBy := middleware.GetCurrentUserID(c)
+	if reopenedBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	if err := h.ledgerService.ReopenPeriod(c.Request.Context(), &req, reopenedBy); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to reopen accounting period", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Accounting period reopened successfully", nil,
+	))
+}
+
+// parseGLAccountID reads the account ID path parameter, responding with 400 when it is not a number
+func parseGLAccountID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid account ID", "Account ID must be a valid number",
+		))
+		return 0, false
+	}
+	return id, true
+}
+
+// parseJournalID reads the journal entry ID path parameter, responding with 400 when it is not a number
+func parseJournalID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid journal entry ID", "Journal entry ID must be a valid number",
+		))
+		return 0, false
+	}
+	return id, true
+}
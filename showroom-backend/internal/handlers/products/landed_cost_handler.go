@@ -0,0 +1,190 @@
+package products
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/middleware"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	productService "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services/products"
+)
+
+// LandedCostHandler handles landed cost voucher HTTP requests
+type LandedCostHandler struct {
+	landedCostService *productService.LandedCostService
+}
+
+// NewLandedCostHandler creates a new landed cost handler
+func NewLandedCostHandler(landedCostService *productService.LandedCostService) *LandedCostHandler {
+	return &LandedCostHandler{
+		landedCostService: landedCostService,
+	}
+}
+
+// CreateVoucher handles creating a new landed cost voucher
+func (h *LandedCostHandler) CreateVoucher(c *gin.Context) {
+	var req products.LandedCostVoucherCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	createdBy := middleware.GetCurrentUserID(c)
+	if createdBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "Creator user ID not found",
+		))
+		return
+	}
+
+	voucher, err := h.landedCostService.CreateVoucher(c.Request.Context(), &req, createdBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Landed cost voucher creation failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.NewSuccessResponse(
+		"Landed cost voucher created successfully", voucher,
+	))
+}
+
+// GetVoucher handles getting a specific landed cost voucher
+func (h *LandedCostHandler) GetVoucher(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid voucher ID", "Voucher ID must be a valid number",
+		))
+		return
+	}
+
+	voucher, err := h.landedCostService.GetVoucher(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Landed cost voucher not found", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Landed cost voucher retrieved successfully", voucher,
+	))
+}
+
+// ListVouchers handles listing landed cost vouchers with pagination
+func (h *LandedCostHandler) ListVouchers(c *gin.Context) {
+	var params products.LandedCostVoucherFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	// Set default pagination if not provided
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+
+	vouchers, err := h.landedCostService.ListVouchers(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to list landed cost vouchers", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Landed cost vouchers retrieved successfully", vouchers,
+	))
+}
+
+// GetAllocations handles getting the allocation of a landed cost voucher over its receipt lines
+func (h *LandedCostHandler) GetAllocations(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid voucher ID", "Voucher ID must be a valid number",
+		))
+		return
+	}
+
+	allocations, err := h.landedCostService.GetAllocations(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to get landed cost allocations", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Landed cost allocations retrieved successfully", allocations,
+	))
+}
+
+// PostVoucher handles posting a landed cost voucher into inventory cost
+func (h *LandedCostHandler) PostVoucher(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid voucher ID", "Voucher ID must be a valid number",
+		))
+		return
+	}
+
+	postedBy := middleware.GetCurrentUserID(c)
+	if postedBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "Poster user ID not found",
+		))
+		return
+	}
+
+	voucher, err := h.landedCostService.PostVoucher(c.Request.Context(), id, postedBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to post landed cost voucher", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Landed cost voucher posted successfully", voucher,
+	))
+}
+
+// CancelVoucher handles cancelling a draft landed cost voucher
+func (h *LandedCostHandler) CancelVoucher(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid voucher ID", "Voucher ID must be a valid number",
+		))
+		return
+	}
+
+	err = h.landedCostService.CancelVoucher(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to cancel landed cost voucher", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Landed cost voucher cancelled successfully", nil,
+	))
+}
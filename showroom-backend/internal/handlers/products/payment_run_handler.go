@@ -0,0 +1,283 @@
+package products
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/middleware"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	productService "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services/products"
+)
+
+// PaymentRunHandler handles supplier payment run HTTP requests
+type PaymentRunHandler struct {
+	runService *productService.PaymentRunService
+}
+
+// NewPaymentRunHandler creates a new payment run handler
+func NewPaymentRunHandler(runService *productService.PaymentRunService) *PaymentRunHandler {
+	return &PaymentRunHandler{
+		runService: runService,
+	}
+}
+
+// CreateRun handles selecting due supplier invoices into a draft payment run
+func (h *PaymentRunHandler) CreateRun(c *gin.Context) {
+	var req products.PaymentRunCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	createdBy := middleware.GetCurrentUserID(c)
+	if createdBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "Creator user ID not found",
+		))
+		return
+	}
+
+	run, err := h.runService.CreateRun(c.Request.Context(), &req, createdBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Payment run creation failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.NewSuccessResponse(
+		"Payment run created successfully", run,
+	))
+}
+
+// GetRun handles getting a specific payment run with its lines
+func (h *PaymentRunHandler) GetRun(c *gin.Context) {
+	id, ok := parsePaymentRunID(c)
+	if !ok {
+		return
+	}
+
+	run, err := h.runService.GetRun(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Payment run not found", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Payment run retrieved successfully", run,
+	))
+}
+
+// ListRuns handles listing payment runs with pagination
+func (h *PaymentRunHandler) ListRuns(c *gin.Context) {
+	var params products.PaymentRunFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	// Set default pagination if not provided
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+
+	runs, err := h.runService.ListRuns(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to list payment runs", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Payment runs retrieved successfully", runs,
+	))
+}
+
+// RemoveLine handles removing an invoice from a draft payment run
+func (h *PaymentRunHandler) RemoveLine(c *gin.Context) {
+	id, ok := parsePaymentRunID(c)
+	if !ok {
+		return
+	}
+
+	lineID, err := strconv.Atoi(c.Param("lineId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid line ID", "Line ID must be a valid number",
+		))
+		return
+	}
+
+	run, err := h.runService.RemoveLine(c.Request.Context(), id, lineID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to remove payment run line", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Payment run line removed successfully", run,
+	))
+}
+
+// ApproveRun handles approving a reviewed payment run
+func (h *PaymentRunHandler) ApproveRun(c *gin.Context) {
+	id, ok := parsePaymentRunID(c)
+	if !ok {
+		return
+	}
+
+	approvedBy := middleware.GetCurrentUserID(c)
+	if approvedBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "Approver user ID not found",
+		))
+		return
+	}
+
+	run, err := h.runService.ApproveRun(c.Request.Context(), id, approvedBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to approve payment run", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Payment run approved successfully", run,
+	))
+}
+
+// CancelRun handles cancelling a payment run that has not been executed
+func (h *PaymentRunHandler) CancelRun(c *gin.Context) {
+	id, ok := parsePaymentRunID(c)
+	if !ok {
+		return
+	}
+
+	run, err := h.runService.CancelRun(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to cancel payment run", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Payment run cancelled successfully", run,
+	))
+}
+
+// DownloadBankFile handles downloading the bulk transfer file of a payment run as CSV or fixed-width text
+func (h *PaymentRunHandler) DownloadBankFile(c *gin.Context) {
+	id, ok := parsePaymentRunID(c)
+	if !ok {
+		return
+	}
+
+	filename, content, err := h.runService.GenerateBankFile(c.Request.Context(), id, c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to generate bank file", err.Error(),
+		))
+		return
+	}
+
+	contentType := "text/plain"
+	if c.Query("format") == "" || c.Query("format") == products.BankFileFormatCSV {
+		contentType = "text/csv"
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, contentType, content)
+}
+
+// ExecuteRun handles posting the payments of an approved payment run
+func (h *PaymentRunHandler) ExecuteRun(c *gin.Context) {
+	id, ok := parsePaymentRunID(c)
+	if !ok {
+		return
+	}
+
+	executedBy := middleware.GetCurrentUserID(c)
+	if executedBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	run, err := h.runService.ExecuteRun(c.Request.Context(), id, executedBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to execute payment run", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Payment run executed successfully", run,
+	))
+}
+
+// FailLine handles recording a transfer rejected by the bank and reopening its invoice
+func (h *PaymentRunHandler) FailLine(c *gin.Context) {
+	id, ok := parsePaymentRunID(c)
+	if !ok {
+		return
+	}
+
+	lineID, err := strconv.Atoi(c.Param("lineId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid line ID", "Line ID must be a valid number",
+		))
+		return
+	}
+
+	var req products.PaymentRunFailLineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	run, err := h.runService.FailLine(c.Request.Context(), id, lineID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to mark payment run line as failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Payment run line marked as failed", run,
+	))
+}
+
+// parsePaymentRunID reads the run ID path parameter, responding with 400 when it is not a number
+func parsePaymentRunID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid payment run ID", "Payment run ID must be a valid number",
+		))
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,156 @@
+package products
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	productService "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services/products"
+)
+
+// PaymentTermHandler handles payment term HTTP requests
+type PaymentTermHandler struct {
+	paymentTermService *productService.PaymentTermService
+}
+
+// NewPaymentTermHandler creates a new payment term handler
+func NewPaymentTermHandler(paymentTermService *productService.PaymentTermService) *PaymentTermHandler {
+	return &PaymentTermHandler{
+		paymentTermService: paymentTermService,
+	}
+}
+
+// CreatePaymentTerm handles creating new payment terms
+func (h *PaymentTermHandler) CreatePaymentTerm(c *gin.Context) {
+	var req products.PaymentTermCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	term, err := h.paymentTermService.CreatePaymentTerm(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Payment term creation failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.NewSuccessResponse(
+		"Payment term created successfully", term,
+	))
+}
+
+// GetPaymentTerm handles getting specific payment terms
+func (h *PaymentTermHandler) GetPaymentTerm(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid payment term ID", "Payment term ID must be a valid number",
+		))
+		return
+	}
+
+	term, err := h.paymentTermService.GetPaymentTerm(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Payment term not found", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Payment term retrieved successfully", term,
+	))
+}
+
+// ListPaymentTerms handles listing payment terms with pagination
+func (h *PaymentTermHandler) ListPaymentTerms(c *gin.Context) {
+	var params products.PaymentTermFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	// Set default pagination if not provided
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+
+	terms, err := h.paymentTermService.ListPaymentTerms(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to list payment terms", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Payment terms retrieved successfully", terms,
+	))
+}
+
+// UpdatePaymentTerm handles updating payment terms
+func (h *PaymentTermHandler) UpdatePaymentTerm(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid payment term ID", "Payment term ID must be a valid number",
+		))
+		return
+	}
+
+	var req products.PaymentTermUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	term, err := h.paymentTermService.UpdatePaymentTerm(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Payment term update failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Payment term updated successfully", term,
+	))
+}
+
+// DeletePaymentTerm handles deleting unused payment terms
+func (h *PaymentTermHandler) DeletePaymentTerm(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid payment term ID", "Payment term ID must be a valid number",
+		))
+		return
+	}
+
+	if err := h.paymentTermService.DeletePaymentTerm(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Payment term deletion failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Payment term deleted successfully", nil,
+	))
+}
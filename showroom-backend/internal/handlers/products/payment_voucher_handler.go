@@ -0,0 +1,198 @@
+package products
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/middleware"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	productService "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services/products"
+)
+
+// PaymentVoucherHandler handles supplier payment voucher HTTP requests
+type PaymentVoucherHandler struct {
+	voucherService *productService.PaymentVoucherService
+}
+
+// NewPaymentVoucherHandler creates a new payment voucher handler
+func NewPaymentVoucherHandler(voucherService *productService.PaymentVoucherService) *PaymentVoucherHandler {
+	return &PaymentVoucherHandler{
+		voucherService: voucherService,
+	}
+}
+
+// CreateVoucher handles recording a supplier payment with its invoice allocations
+func (h *PaymentVoucherHandler) CreateVoucher(c *gin.Context) {
+	var req products.PaymentVoucherCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	processedBy := middleware.GetCurrentUserID(c)
+	if processedBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "Processor user ID not found",
+		))
+		return
+	}
+
+	voucher, err := h.voucherService.CreateVoucher(c.Request.Context(), &req, processedBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Supplier payment creation failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.NewSuccessResponse(
+		"Supplier payment created successfully", voucher,
+	))
+}
+
+// GetVoucher handles getting a specific payment voucher
+func (h *PaymentVoucherHandler) GetVoucher(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid voucher ID", "Voucher ID must be a valid number",
+		))
+		return
+	}
+
+	voucher, err := h.voucherService.GetVoucher(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Supplier payment not found", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier payment retrieved successfully", voucher,
+	))
+}
+
+// ListVouchers handles listing payment vouchers with pagination
+func (h *PaymentVoucherHandler) ListVouchers(c *gin.Context) {
+	var params products.PaymentVoucherFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	// Set default pagination if not provided
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+
+	vouchers, err := h.voucherService.ListVouchers(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to list supplier payments", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier payments retrieved successfully", vouchers,
+	))
+}
+
+// UpdateVoucher handles updating the payment details of a voucher
+func (h *PaymentVoucherHandler) UpdateVoucher(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid voucher ID", "Voucher ID must be a valid number",
+		))
+		return
+	}
+
+	var req products.PaymentVoucherUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	voucher, err := h.voucherService.UpdateVoucher(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to update supplier payment", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier payment updated successfully", voucher,
+	))
+}
+
+// AllocateVoucher handles allocating the unallocated amount of a voucher to invoices
+func (h *PaymentVoucherHandler) AllocateVoucher(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid voucher ID", "Voucher ID must be a valid number",
+		))
+		return
+	}
+
+	var req products.PaymentVoucherAllocateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	voucher, err := h.voucherService.AllocateVoucher(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to allocate supplier payment", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier payment allocated successfully", voucher,
+	))
+}
+
+// VoidVoucher handles voiding a payment voucher
+func (h *PaymentVoucherHandler) VoidVoucher(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid voucher ID", "Voucher ID must be a valid number",
+		))
+		return
+	}
+
+	voucher, err := h.voucherService.VoidVoucher(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to void supplier payment", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier payment voided successfully", voucher,
+	))
+}
@@ -22,49 +22,6 @@ func NewPurchaseOrderDetailHandler(poDetailRepo interfaces.PurchaseOrderDetailRe
 	}
 }
 
-// CreatePODetail handles adding a line item to a purchase order
-func (h *PurchaseOrderDetailHandler) CreatePODetail(c *gin.Context) {
-	poIDStr := c.Param("id")
-	poID, err := strconv.Atoi(poIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
-			"Invalid PO ID", "PO ID must be a valid number",
-		))
-		return
-	}
-
-	var req products.PurchaseOrderDetailCreateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
-			"Validation failed", "Invalid request data", err.Error(),
-		))
-		return
-	}
-
-	// Create detail model
-	detail := &products.PurchaseOrderDetail{
-		POID:            poID,
-		ProductID:       req.ProductID,
-		ItemDescription: req.ItemDescription,
-		QuantityOrdered: req.QuantityOrdered,
-		UnitCost:        req.UnitCost,
-		ExpectedDate:    req.ExpectedDate,
-		ItemNotes:       req.ItemNotes,
-	}
-
-	createdDetail, err := h.poDetailRepo.Create(c.Request.Context(), detail)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
-			"Failed to create PO detail", err.Error(),
-		))
-		return
-	}
-
-	c.JSON(http.StatusCreated, common.NewSuccessResponse(
-		"PO detail created successfully", createdDetail,
-	))
-}
-
 // GetPODetails handles getting details for a purchase order
 func (h *PurchaseOrderDetailHandler) GetPODetails(c *gin.Context) {
 	poIDStr := c.Param("id")
@@ -279,50 +236,3 @@ func (h *PurchaseOrderDetailHandler) GetPendingReceiptItems(c *gin.Context) {
 		"Pending receipt items retrieved successfully", items,
 	))
 }
-
-// BulkCreatePODetails handles creating multiple PO details at once
-func (h *PurchaseOrderDetailHandler) BulkCreatePODetails(c *gin.Context) {
-	poIDStr := c.Param("id")
-	poID, err := strconv.Atoi(poIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
-			"Invalid PO ID", "PO ID must be a valid number",
-		))
-		return
-	}
-
-	var requests []products.PurchaseOrderDetailCreateRequest
-	if err := c.ShouldBindJSON(&requests); err != nil {
-		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
-			"Validation failed", "Invalid request data", err.Error(),
-		))
-		return
-	}
-
-	// Convert requests to detail models
-	var details []products.PurchaseOrderDetail
-	for _, req := range requests {
-		detail := products.PurchaseOrderDetail{
-			POID:            poID,
-			ProductID:       req.ProductID,
-			ItemDescription: req.ItemDescription,
-			QuantityOrdered: req.QuantityOrdered,
-			UnitCost:        req.UnitCost,
-			ExpectedDate:    req.ExpectedDate,
-			ItemNotes:       req.ItemNotes,
-		}
-		details = append(details, detail)
-	}
-
-	err = h.poDetailRepo.BulkCreate(c.Request.Context(), details)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
-			"Failed to create PO details", err.Error(),
-		))
-		return
-	}
-
-	c.JSON(http.StatusCreated, common.NewSuccessResponse(
-		"PO details created successfully", nil,
-	))
-}
\ No newline at end of file
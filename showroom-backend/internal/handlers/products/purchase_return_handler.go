@@ -0,0 +1,373 @@
+package products
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/middleware"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	productService "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services/products"
+)
+
+// PurchaseReturnHandler handles purchase return and supplier debit note HTTP requests
+type PurchaseReturnHandler struct {
+	purchaseReturnService *productService.PurchaseReturnService
+}
+
+// NewPurchaseReturnHandler creates a new purchase return handler
+func NewPurchaseReturnHandler(purchaseReturnService *productService.PurchaseReturnService) *PurchaseReturnHandler {
+	return &PurchaseReturnHandler{
+		purchaseReturnService: purchaseReturnService,
+	}
+}
+
+// CreatePurchaseReturn handles creating a return to vendor from stock
+func (h *PurchaseReturnHandler) CreatePurchaseReturn(c *gin.Context) {
+	var req products.PurchaseReturnCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	createdBy := middleware.GetCurrentUserID(c)
+	if createdBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "Creator user ID not found",
+		))
+		return
+	}
+
+	purchaseReturn, err := h.purchaseReturnService.CreateReturnFromStock(c.Request.Context(), &req, createdBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Purchase return creation failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.NewSuccessResponse(
+		"Purchase return created successfully", purchaseReturn,
+	))
+}
+
+// CreateReturnFromReceipt handles creating a return to vendor from rejected goods receipt lines
+func (h *PurchaseReturnHandler) CreateReturnFromReceipt(c *gin.Context) {
+	idStr := c.Param("id")
+	receiptID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid receipt ID", "Receipt ID must be a valid number",
+		))
+		return
+	}
+
+	var req products.PurchaseReturnFromReceiptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	createdBy := middleware.GetCurrentUserID(c)
+	if createdBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "Creator user ID not found",
+		))
+		return
+	}
+
+	purchaseReturn, err := h.purchaseReturnService.CreateReturnFromReceipt(c.Request.Context(), receiptID, &req, createdBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Purchase return creation failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.NewSuccessResponse(
+		"Purchase return created successfully", purchaseReturn,
+	))
+}
+
+// GetPurchaseReturn handles getting a specific purchase return
+func (h *PurchaseReturnHandler) GetPurchaseReturn(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid return ID", "Return ID must be a valid number",
+		))
+		return
+	}
+
+	purchaseReturn, err := h.purchaseReturnService.GetPurchaseReturn(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Purchase return not found", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Purchase return retrieved successfully", purchaseReturn,
+	))
+}
+
+// GetReturnDetails handles getting the line items of a purchase return
+func (h *PurchaseReturnHandler) GetReturnDetails(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid return ID", "Return ID must be a valid number",
+		))
+		return
+	}
+
+	details, err := h.purchaseReturnService.GetReturnDetails(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to get purchase return details", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Purchase return details retrieved successfully", details,
+	))
+}
+
+// ListPurchaseReturns handles listing purchase returns with pagination
+func (h *PurchaseReturnHandler) ListPurchaseReturns(c *gin.Context) {
+	var params products.PurchaseReturnFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	// Set default pagination if not provided
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+
+	returns, err := h.purchaseReturnService.ListPurchaseReturns(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to list purchase returns", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Purchase returns retrieved successfully", returns,
+	))
+}
+
+// ApprovePurchaseReturn handles approving a purchase return and issuing its debit note
+func (h *PurchaseReturnHandler) ApprovePurchaseReturn(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid return ID", "Return ID must be a valid number",
+		))
+		return
+	}
+
+	approvedBy := middleware.GetCurrentUserID(c)
+	if approvedBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "Approver user ID not found",
+		))
+		return
+	}
+
+	debitNote, err := h.purchaseReturnService.ApprovePurchaseReturn(c.Request.Context(), id, approvedBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to approve purchase return", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Purchase return approved successfully", debitNote,
+	))
+}
+
+// UpdateShipment handles updating the shipment status of a purchase return
+func (h *PurchaseReturnHandler) UpdateShipment(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid return ID", "Return ID must be a valid number",
+		))
+		return
+	}
+
+	var req products.PurchaseReturnShipmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	purchaseReturn, err := h.purchaseReturnService.UpdateShipment(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to update shipment", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Shipment updated successfully", purchaseReturn,
+	))
+}
+
+// CancelPurchaseReturn handles cancelling a draft purchase return
+func (h *PurchaseReturnHandler) CancelPurchaseReturn(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid return ID", "Return ID must be a valid number",
+		))
+		return
+	}
+
+	err = h.purchaseReturnService.CancelPurchaseReturn(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to cancel purchase return", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Purchase return cancelled successfully", nil,
+	))
+}
+
+// ListDebitNotes handles listing supplier debit notes with pagination
+func (h *PurchaseReturnHandler) ListDebitNotes(c *gin.Context) {
+	var params products.SupplierDebitNoteFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	// Set default pagination if not provided
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+
+	debitNotes, err := h.purchaseReturnService.ListDebitNotes(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to list supplier debit notes", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier debit notes retrieved successfully", debitNotes,
+	))
+}
+
+// GetDebitNote handles getting a specific supplier debit note
+func (h *PurchaseReturnHandler) GetDebitNote(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid debit note ID", "Debit note ID must be a valid number",
+		))
+		return
+	}
+
+	debitNote, err := h.purchaseReturnService.GetDebitNote(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Supplier debit note not found", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier debit note retrieved successfully", debitNote,
+	))
+}
+
+// GetDebitNoteApplications handles getting the invoice applications of a debit note
+func (h *PurchaseReturnHandler) GetDebitNoteApplications(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid debit note ID", "Debit note ID must be a valid number",
+		))
+		return
+	}
+
+	applications, err := h.purchaseReturnService.GetDebitNoteApplications(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to get debit note applications", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Debit note applications retrieved successfully", applications,
+	))
+}
+
+// ApplyDebitNote handles applying the remaining balance of a debit note to outstanding invoices
+func (h *PurchaseReturnHandler) ApplyDebitNote(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid debit note ID", "Debit note ID must be a valid number",
+		))
+		return
+	}
+
+	appliedBy := middleware.GetCurrentUserID(c)
+	if appliedBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	debitNote, err := h.purchaseReturnService.ApplyDebitNote(c.Request.Context(), id, appliedBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to apply debit note", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Debit note applied successfully", debitNote,
+	))
+}
@@ -0,0 +1,174 @@
+package products
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/middleware"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	productService "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services/products"
+)
+
+// SalesInvoiceHandler handles sales invoice HTTP requests
+type SalesInvoiceHandler struct {
+	invoiceService *productService.SalesInvoiceService
+}
+
+// NewSalesInvoiceHandler creates a new sales invoice handler
+func NewSalesInvoiceHandler(invoiceService *productService.SalesInvoiceService) *SalesInvoiceHandler {
+	return &SalesInvoiceHandler{
+		invoiceService: invoiceService,
+	}
+}
+
+// CreateInvoice handles issuing a sales invoice
+func (h *SalesInvoiceHandler) CreateInvoice(c *gin.Context) {
+	var req products.SalesInvoiceCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	createdBy := middleware.GetCurrentUserID(c)
+	if createdBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	invoice, err := h.invoiceService.CreateInvoice(c.Request.Context(), &req, createdBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to create sales invoice", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.NewSuccessResponse(
+		"Sales invoice created successfully", invoice,
+	))
+}
+
+// GetInvoice handles getting a sales invoice with its lines
+func (h *SalesInvoiceHandler) GetInvoice(c *gin.Context) {
+	id, ok := parseSalesInvoiceID(c)
+	if !ok {
+		return
+	}
+
+	invoice, err := h.invoiceService.GetInvoice(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Sales invoice not found", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Sales invoice retrieved successfully", invoice,
+	))
+}
+
+// ListInvoices handles listing sales invoices with pagination
+func (h *SalesInvoiceHandler) ListInvoices(c *gin.Context) {
+	var params products.SalesInvoiceFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	// Set default pagination if not provided
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+
+	invoices, err := h.invoiceService.ListInvoices(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to list sales invoices", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Sales invoices retrieved successfully", invoices,
+	))
+}
+
+// SetTaxInvoiceNumber handles recording the tax invoice number of a sales invoice
+func (h *SalesInvoiceHandler) SetTaxInvoiceNumber(c *gin.Context) {
+	id, ok := parseSalesInvoiceID(c)
+	if !ok {
+		return
+	}
+
+	var req products.SalesInvoiceTaxNumberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	invoice, err := h.invoiceService.SetTaxInvoiceNumber(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to set tax invoice number", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Tax invoice number recorded successfully", invoice,
+	))
+}
+
+// VoidInvoice handles voiding a sales invoice
+func (h *SalesInvoiceHandler) VoidInvoice(c *gin.Context) {
+	id, ok := parseSalesInvoiceID(c)
+	if !ok {
+		return
+	}
+
+	voidedBy := middleware.GetCurrentUserID(c)
+	if voidedBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "User ID not found",
+		))
+		return
+	}
+
+	invoice, err := h.invoiceService.VoidInvoice(c.Request.Context(), id, voidedBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to void sales invoice", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Sales invoice voided successfully", invoice,
+	))
+}
+
+// parseSalesInvoiceID reads the sales invoice ID path parameter, responding with 400 when it is not a number
+func parseSalesInvoiceID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid sales invoice ID", "Sales invoice ID must be a valid number",
+		))
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,444 @@
+package products
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/middleware"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	productService "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
+)
+
+// SupplierInvoiceHandler handles supplier invoice HTTP requests
+type SupplierInvoiceHandler struct {
+	invoiceService *productService.SupplierInvoiceService
+}
+
+// NewSupplierInvoiceHandler creates a new supplier invoice handler
+func NewSupplierInvoiceHandler(invoiceService *productService.SupplierInvoiceService) *SupplierInvoiceHandler {
+	return &SupplierInvoiceHandler{
+		invoiceService: invoiceService,
+	}
+}
+
+// CreateInvoice handles recording a supplier invoice
+func (h *SupplierInvoiceHandler) CreateInvoice(c *gin.Context) {
+	var req products.SupplierInvoiceCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	createdBy := middleware.GetCurrentUserID(c)
+	if createdBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "Creator user ID not found",
+		))
+		return
+	}
+
+	invoice, err := h.invoiceService.CreateInvoice(c.Request.Context(), &req, createdBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Supplier invoice creation failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.NewSuccessResponse(
+		"Supplier invoice created successfully", invoice,
+	))
+}
+
+// GetInvoice handles getting a specific supplier invoice
+func (h *SupplierInvoiceHandler) GetInvoice(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid invoice ID", "Invoice ID must be a valid number",
+		))
+		return
+	}
+
+	invoice, err := h.invoiceService.GetInvoice(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Supplier invoice not found", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier invoice retrieved successfully", invoice,
+	))
+}
+
+// GetInvoiceAllocations handles getting the payment installments of a supplier invoice
+func (h *SupplierInvoiceHandler) GetInvoiceAllocations(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid invoice ID", "Invoice ID must be a valid number",
+		))
+		return
+	}
+
+	allocations, err := h.invoiceService.GetInvoiceAllocations(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Supplier invoice not found", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier invoice allocations retrieved successfully", allocations,
+	))
+}
+
+// ListInvoices handles listing supplier invoices with pagination
+func (h *SupplierInvoiceHandler) ListInvoices(c *gin.Context) {
+	var params products.SupplierInvoiceFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	// Set default pagination if not provided
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+
+	invoices, err := h.invoiceService.ListInvoices(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to list supplier invoices", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier invoices retrieved successfully", invoices,
+	))
+}
+
+// GetOverdueInvoices handles getting overdue supplier invoices
+func (h *SupplierInvoiceHandler) GetOverdueInvoices(c *gin.Context) {
+	var params products.SupplierInvoiceFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	// Set default pagination if not provided
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+
+	invoices, err := h.invoiceService.GetOverdueInvoices(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to get overdue supplier invoices", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Overdue supplier invoices retrieved successfully", invoices,
+	))
+}
+
+// UpdateInvoice handles updating a supplier invoice
+func (h *SupplierInvoiceHandler) UpdateInvoice(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid invoice ID", "Invoice ID must be a valid number",
+		))
+		return
+	}
+
+	var req products.SupplierInvoiceUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	invoice, err := h.invoiceService.UpdateInvoice(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to update supplier invoice", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier invoice updated successfully", invoice,
+	))
+}
+
+// DeleteInvoice handles deleting a supplier invoice
+func (h *SupplierInvoiceHandler) DeleteInvoice(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid invoice ID", "Invoice ID must be a valid number",
+		))
+		return
+	}
+
+	if err := h.invoiceService.DeleteInvoice(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to delete supplier invoice", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier invoice deleted successfully", nil,
+	))
+}
+
+// UpdateInvoiceStatus handles disputing a supplier invoice or resolving a dispute
+func (h *SupplierInvoiceHandler) UpdateInvoiceStatus(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid invoice ID", "Invoice ID must be a valid number",
+		))
+		return
+	}
+
+	var req UpdateInvoiceStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	if err := h.invoiceService.UpdateInvoiceStatus(c.Request.Context(), id, req.Status); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to update supplier invoice status", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier invoice status updated successfully", nil,
+	))
+}
+
+// GetInvoiceSummary handles getting the supplier invoice summary
+func (h *SupplierInvoiceHandler) GetInvoiceSummary(c *gin.Context) {
+	var supplierID *int
+
+	// Check if supplier ID is provided in query parameters
+	supplierIDStr := c.Query("supplier_id")
+	if supplierIDStr != "" {
+		id, err := strconv.Atoi(supplierIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+				"Invalid supplier ID", "Supplier ID must be a valid number",
+			))
+			return
+		}
+		supplierID = &id
+	}
+
+	summary, err := h.invoiceService.GetInvoiceSummary(c.Request.Context(), supplierID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to get supplier invoice summary", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier invoice summary retrieved successfully", summary,
+	))
+}
+
+// UpdateOverdueInvoices handles refreshing the overdue status of open supplier invoices
+func (h *SupplierInvoiceHandler) UpdateOverdueInvoices(c *gin.Context) {
+	updated, err := h.invoiceService.UpdateOverdueInvoices(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to update overdue supplier invoices", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Overdue supplier invoices updated successfully", gin.H{"updated": updated},
+	))
+}
+
+// GetAgingReport handles the accounts payable aging report as JSON, CSV or XLSX
+func (h *SupplierInvoiceHandler) GetAgingReport(c *gin.Context) {
+	var params products.APAgingParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	report, err := h.invoiceService.GetAgingReport(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to get accounts payable aging report", err.Error(),
+		))
+		return
+	}
+
+	h.writeAgingReport(c, params.Format, "ap-aging", report)
+}
+
+// GetSupplierAging handles drilling down into the aged invoices of one supplier
+func (h *SupplierInvoiceHandler) GetSupplierAging(c *gin.Context) {
+	idStr := c.Param("id")
+	supplierID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid supplier ID", "Supplier ID must be a valid number",
+		))
+		return
+	}
+
+	var params products.APAgingParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	report, err := h.invoiceService.GetSupplierAging(c.Request.Context(), supplierID, &params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to get supplier aging", err.Error(),
+		))
+		return
+	}
+
+	h.writeAgingReport(c, params.Format, fmt.Sprintf("ap-aging-supplier-%d", supplierID), report)
+}
+
+// writeAgingReport responds with the aging report as JSON or as a CSV or XLSX download
+func (h *SupplierInvoiceHandler) writeAgingReport(c *gin.Context, format, filename string, report *products.APAgingReport) {
+	if format == "" || format == products.ReportFormatJSON {
+		c.JSON(http.StatusOK, common.NewSuccessResponse(
+			"Accounts payable aging report retrieved successfully", report,
+		))
+		return
+	}
+
+	header, rows := report.Table()
+	filename = fmt.Sprintf("%s-%s.%s", filename, report.AsOfDate.Format("20060102"), format)
+
+	var buf bytes.Buffer
+	var contentType string
+	var err error
+	switch format {
+	case products.ReportFormatCSV:
+		contentType = "text/csv"
+		err = utils.WriteCSV(&buf, header, rows)
+	case products.ReportFormatXLSX:
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		err = utils.WriteXLSX(&buf, "AP Aging", header, rows)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to export accounts payable aging report", err.Error(),
+		))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// GetDiscountOpportunities handles listing invoices to pay now to capture early payment discounts
+func (h *SupplierInvoiceHandler) GetDiscountOpportunities(c *gin.Context) {
+	var params products.EarlyPaymentDiscountParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	report, err := h.invoiceService.GetDiscountOpportunities(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to get early payment discount opportunities", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Early payment discount opportunities retrieved successfully", report,
+	))
+}
+
+// CalculatePaymentTerms handles calculating payment terms
+func (h *SupplierInvoiceHandler) CalculatePaymentTerms(c *gin.Context) {
+	var req PaymentTermsCalculationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	terms, err := h.invoiceService.CalculatePaymentTerms(c.Request.Context(), req.InvoiceDate, req.TermsDays, req.PaymentTermID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to calculate payment terms", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Payment terms calculated successfully", terms,
+	))
+}
+
+// UpdateInvoiceStatusRequest represents a request to update a supplier invoice status
+type UpdateInvoiceStatusRequest struct {
+	Status products.PaymentStatus `json:"status" binding:"required"`
+}
+
+// PaymentTermsCalculationRequest represents a request to calculate payment terms
+// Stored payment terms take precedence over terms_days.
+type PaymentTermsCalculationRequest struct {
+	InvoiceDate   time.Time `json:"invoice_date" binding:"required"`
+	TermsDays     int       `json:"terms_days" binding:"omitempty,min=0"`
+	PaymentTermID *int      `json:"payment_term_id,omitempty" binding:"omitempty,min=1"`
+}
@@ -0,0 +1,219 @@
+package products
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/middleware"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	productService "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services/products"
+)
+
+// SupplierPriceListHandler handles supplier price list HTTP requests
+type SupplierPriceListHandler struct {
+	priceListService *productService.SupplierPriceListService
+}
+
+// NewSupplierPriceListHandler creates a new supplier price list handler
+func NewSupplierPriceListHandler(priceListService *productService.SupplierPriceListService) *SupplierPriceListHandler {
+	return &SupplierPriceListHandler{
+		priceListService: priceListService,
+	}
+}
+
+// CreatePriceList handles creating a new supplier price list entry
+func (h *SupplierPriceListHandler) CreatePriceList(c *gin.Context) {
+	var req products.SupplierPriceListCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	createdBy := middleware.GetCurrentUserID(c)
+	if createdBy == 0 {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Invalid user", "Creator user ID not found",
+		))
+		return
+	}
+
+	priceList, err := h.priceListService.CreatePriceList(c.Request.Context(), &req, createdBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Supplier price list creation failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.NewSuccessResponse(
+		"Supplier price list created successfully", priceList,
+	))
+}
+
+// GetPriceList handles getting a specific supplier price list entry
+func (h *SupplierPriceListHandler) GetPriceList(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid price list ID", "Price list ID must be a valid number",
+		))
+		return
+	}
+
+	priceList, err := h.priceListService.GetPriceList(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Supplier price list not found", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier price list retrieved successfully", priceList,
+	))
+}
+
+// ListPriceLists handles listing supplier price list entries with pagination
+func (h *SupplierPriceListHandler) ListPriceLists(c *gin.Context) {
+	var params products.SupplierPriceListFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	// Set default pagination if not provided
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+
+	priceLists, err := h.priceListService.ListPriceLists(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to list supplier price lists", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier price lists retrieved successfully", priceLists,
+	))
+}
+
+// UpdatePriceList handles updating a supplier price list entry
+func (h *SupplierPriceListHandler) UpdatePriceList(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid price list ID", "Price list ID must be a valid number",
+		))
+		return
+	}
+
+	var req products.SupplierPriceListUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	priceList, err := h.priceListService.UpdatePriceList(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Supplier price list update failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier price list updated successfully", priceList,
+	))
+}
+
+// DeletePriceList handles deleting a supplier price list entry
+func (h *SupplierPriceListHandler) DeletePriceList(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid price list ID", "Price list ID must be a valid number",
+		))
+		return
+	}
+
+	if err := h.priceListService.DeletePriceList(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Supplier price list deletion failed", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier price list deleted successfully", nil,
+	))
+}
+
+// LookupPrice handles resolving the effective supplier price for an order quantity
+func (h *SupplierPriceListHandler) LookupPrice(c *gin.Context) {
+	var params products.SupplierPriceLookupParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	lookup, err := h.priceListService.LookupPrice(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Supplier price not found", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier price retrieved successfully", lookup,
+	))
+}
+
+// GetPriceHistory handles getting the quoted and paid price history of a supplier
+func (h *SupplierPriceListHandler) GetPriceHistory(c *gin.Context) {
+	var params products.SupplierPriceHistoryFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	// Set default pagination if not provided
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+
+	history, err := h.priceListService.GetPriceHistory(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to get supplier price history", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier price history retrieved successfully", history,
+	))
+}
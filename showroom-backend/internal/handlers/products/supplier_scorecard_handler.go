@@ -0,0 +1,78 @@
+package products
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	productService "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services/products"
+)
+
+// SupplierScorecardHandler handles supplier scorecard HTTP requests
+type SupplierScorecardHandler struct {
+	scorecardService *productService.SupplierScorecardService
+}
+
+// NewSupplierScorecardHandler creates a new supplier scorecard handler
+func NewSupplierScorecardHandler(scorecardService *productService.SupplierScorecardService) *SupplierScorecardHandler {
+	return &SupplierScorecardHandler{
+		scorecardService: scorecardService,
+	}
+}
+
+// GetScorecard handles getting the performance scorecard of a supplier
+func (h *SupplierScorecardHandler) GetScorecard(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid supplier ID", "Supplier ID must be a valid number",
+		))
+		return
+	}
+
+	var params products.SupplierScorecardParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	scorecard, err := h.scorecardService.GetScorecard(c.Request.Context(), id, &params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to get supplier scorecard", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier scorecard retrieved successfully", scorecard,
+	))
+}
+
+// CompareSuppliers handles ranking supplier scorecards against each other
+func (h *SupplierScorecardHandler) CompareSuppliers(c *gin.Context) {
+	var params products.SupplierScorecardCompareParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	comparison, err := h.scorecardService.CompareSuppliers(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to compare suppliers", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Supplier comparison retrieved successfully", comparison,
+	))
+}
@@ -0,0 +1,183 @@
+package products
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	productService "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services/products"
+)
+
+// TaxHandler handles tax code, VAT report and e-Faktur export HTTP requests
+type TaxHandler struct {
+	taxService *productService.TaxService
+}
+
+// NewTaxHandler creates a new tax handler
+func NewTaxHandler(taxService *productService.TaxService) *TaxHandler {
+	return &TaxHandler{
+		taxService: taxService,
+	}
+}
+
+// CreateTaxCode handles creating a new tax code
+func (h *TaxHandler) CreateTaxCode(c *gin.Context) {
+	var req products.TaxCodeCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	taxCode, err := h.taxService.CreateTaxCode(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to create tax code", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.NewSuccessResponse(
+		"Tax code created successfully", taxCode,
+	))
+}
+
+// GetTaxCode handles getting a tax code by ID
+func (h *TaxHandler) GetTaxCode(c *gin.Context) {
+	id, ok := parseTaxCodeID(c)
+	if !ok {
+		return
+	}
+
+	taxCode, err := h.taxService.GetTaxCode(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, common.NewErrorResponse(
+			"Tax code not found", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Tax code retrieved successfully", taxCode,
+	))
+}
+
+// UpdateTaxCode handles updating a tax code
+func (h *TaxHandler) UpdateTaxCode(c *gin.Context) {
+	id, ok := parseTaxCodeID(c)
+	if !ok {
+		return
+	}
+
+	var req products.TaxCodeUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid request data", err.Error(),
+		))
+		return
+	}
+
+	taxCode, err := h.taxService.UpdateTaxCode(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to update tax code", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Tax code updated successfully", taxCode,
+	))
+}
+
+// ListTaxCodes handles listing tax codes with pagination
+func (h *TaxHandler) ListTaxCodes(c *gin.Context) {
+	var params products.TaxCodeFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	// Set default pagination if not provided
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+
+	taxCodes, err := h.taxService.ListTaxCodes(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to list tax codes", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"Tax codes retrieved successfully", taxCodes,
+	))
+}
+
+// GetVATReport handles getting the input and output VAT of a month
+func (h *TaxHandler) GetVATReport(c *gin.Context) {
+	var req products.AccountingPeriodRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	report, err := h.taxService.GetVATReport(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to get VAT report", err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.NewSuccessResponse(
+		"VAT report retrieved successfully", report,
+	))
+}
+
+// ExportEFaktur handles downloading the output tax invoices of a month as an e-Faktur import CSV
+func (h *TaxHandler) ExportEFaktur(c *gin.Context) {
+	var req products.AccountingPeriodRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.NewValidationErrorResponse(
+			"Validation failed", "Invalid query parameters", err.Error(),
+		))
+		return
+	}
+
+	filename, content, err := h.taxService.ExportEFaktur(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Failed to export e-Faktur", err.Error(),
+		))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, "text/csv", content)
+}
+
+// parseTaxCodeID reads the tax code ID path parameter, responding with 400 when it is not a number
+func parseTaxCodeID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.NewErrorResponse(
+			"Invalid tax code ID", "Tax code ID must be a valid number",
+		))
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/audit"
+)
+
+// AuditContextMiddleware puts the client's IP address on the request context as the audit
+// actor, so that changes made by unauthenticated requests such as logins record where they came
+// from. AuthMiddleware adds the user once the request is authenticated.
+func AuditContextMiddleware() gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		setAuditActor(c, audit.Actor{IPAddress: c.ClientIP()})
+		c.Next()
+	})
+}
+
+// setAuditActor sets the actor the audit log records for the changes made by a request
+func setAuditActor(c *gin.Context, actor audit.Actor) {
+	c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), actor))
+}
@@ -1,19 +1,44 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/audit"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/auth"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	commonModels "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
 )
 
-// AuthMiddleware creates an authentication middleware
-func AuthMiddleware(jwtManager *utils.JWTManager, sessionRepo interfaces.UserSessionRepository) gin.HandlerFunc {
+// APIKeyHeader is the header integrations send their API key in
+const APIKeyHeader = "X-API-Key"
+
+// sessionActivityInterval limits how often a session's last activity is written, so that bursts
+// of requests do not each update it
+const sessionActivityInterval = time.Minute
+
+// APIKeyAuthenticator resolves an API key sent from an IP address to the key and the user it
+// acts for
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key, ipAddress string) (*user.APIKey, *user.User, error)
+}
+
+// AuthMiddleware creates an authentication middleware. Requests authenticate with a bearer
+// token or, when apiKeys is not nil, with an API key in the X-API-Key header. Bearer tokens are
+// refused once their session has made no request for idleTimeout; zero disables the timeout.
+func AuthMiddleware(jwtManager *utils.JWTManager, sessionRepo interfaces.UserSessionRepository, apiKeys APIKeyAuthenticator, idleTimeout time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if key := c.GetHeader(APIKeyHeader); key != "" {
+			authenticateAPIKey(c, apiKeys, key)
+			return
+		}
+
 		// Get token from Authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -62,22 +87,81 @@ func AuthMiddleware(jwtManager *utils.JWTManager, sessionRepo interfaces.UserSes
 			return
 		}
 
+		// Refuse sessions left idle, and record the activity of the others
+		now := time.Now()
+		if session.IsIdle(idleTimeout, now) {
+			c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+				"Session expired", user.ErrSessionIdle.Error(),
+			))
+			c.Abort()
+			return
+		}
+		if now.Sub(session.LastActivityAt) >= sessionActivityInterval {
+			// Activity tracking is best effort; a failed update only makes the session look older
+			_ = sessionRepo.Touch(c.Request.Context(), session.SessionID)
+		}
+
 		// Set user context
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("email", claims.Email)
 		c.Set("role", claims.Role)
 		c.Set("session_id", claims.SessionID)
+		c.Set("password_change_required", session.PasswordChangeRequired)
 		c.Set("claims", claims)
+		setAuditActor(c, audit.Actor{UserID: claims.UserID, SessionID: claims.SessionID, IPAddress: c.ClientIP()})
 
 		c.Next()
 	}
 }
 
+// authenticateAPIKey authenticates a request by API key and sets the context of the user the
+// key acts for, with the key's scopes
+func authenticateAPIKey(c *gin.Context, apiKeys APIKeyAuthenticator, key string) {
+	if apiKeys == nil {
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Authentication failed", "API keys are not accepted for this endpoint",
+		))
+		c.Abort()
+		return
+	}
+
+	apiKey, owner, err := apiKeys.Authenticate(c.Request.Context(), key, c.ClientIP())
+	if err != nil {
+		message := user.ErrAPIKeyInvalid.Error()
+		if err == user.ErrAPIKeyRevoked || err == user.ErrAPIKeyExpired || err == user.ErrAPIKeyIPNotAllowed {
+			message = err.Error()
+		}
+		c.JSON(http.StatusUnauthorized, common.NewErrorResponse(
+			"Authentication failed", message,
+		))
+		c.Abort()
+		return
+	}
+
+	c.Set("user_id", owner.UserID)
+	c.Set("username", owner.Username)
+	c.Set("email", owner.Email)
+	c.Set("role", owner.Role)
+	c.Set("session_id", 0)
+	c.Set("password_change_required", false)
+	c.Set("api_key_id", apiKey.KeyID)
+	c.Set("api_key_scopes", apiKey.Scopes)
+	c.Set("claims", &auth.TokenClaims{
+		UserID:   owner.UserID,
+		Username: owner.Username,
+		Email:    owner.Email,
+		Role:     owner.Role,
+	})
+	setAuditActor(c, audit.Actor{UserID: owner.UserID, APIKeyID: apiKey.KeyID, IPAddress: c.ClientIP()})
+
+	c.Next()
+}
+
 // RequireRole creates a role-based authorization middleware
 func RequireRole(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userRole, exists := c.Get("role")
+		roleStr, exists := GetCurrentRole(c)
 		if !exists {
 			c.JSON(http.StatusForbidden, common.NewErrorResponse(
 				"Access denied", "User role not found",
@@ -86,7 +170,6 @@ func RequireRole(roles ...string) gin.HandlerFunc {
 			return
 		}
 
-		roleStr := userRole.(string)
 		for _, role := range roles {
 			if roleStr == role {
 				c.Next()
@@ -101,6 +184,79 @@ func RequireRole(roles ...string) gin.HandlerFunc {
 	}
 }
 
+// RequireCurrentPassword creates a middleware that refuses sessions limited to changing the
+// password, because it has expired or an administrator forced a change
+func RequireCurrentPassword() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetBool("password_change_required") {
+			c.JSON(http.StatusForbidden, common.NewErrorResponse(
+				"Password change required", "Change your password at /api/v1/auth/change-password to continue",
+			))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// PermissionChecker reports whether a role has been granted a permission
+type PermissionChecker interface {
+	HasPermission(ctx context.Context, role, permission string) (bool, error)
+}
+
+// RequirePermission creates a permission-based authorization middleware. The role of the
+// authenticated user must have been granted the permission and, for requests made with an API
+// key, the key must have it as a scope.
+func RequirePermission(checker PermissionChecker, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if scopes, isAPIKey := c.Get("api_key_scopes"); isAPIKey && !hasScope(scopes.([]string), permission) {
+			c.JSON(http.StatusForbidden, common.NewErrorResponse(
+				"Access denied", "API key is missing scope "+permission,
+			))
+			c.Abort()
+			return
+		}
+
+		userRole, exists := GetCurrentRole(c)
+		if !exists {
+			c.JSON(http.StatusForbidden, common.NewErrorResponse(
+				"Access denied", "User role not found",
+			))
+			c.Abort()
+			return
+		}
+
+		allowed, err := checker.HasPermission(c.Request.Context(), userRole, permission)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+				"Authorization failed", "Unable to check permissions",
+			))
+			c.Abort()
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, common.NewErrorResponse(
+				"Access denied", "Missing permission "+permission,
+			))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// hasScope reports whether a permission is among an API key's scopes
+func hasScope(scopes []string, permission string) bool {
+	for _, scope := range scopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
+
 // GetCurrentUser retrieves the current user from context
 func GetCurrentUser(c *gin.Context) *auth.TokenClaims {
 	if claims, exists := c.Get("claims"); exists {
@@ -117,10 +273,35 @@ func GetCurrentUserID(c *gin.Context) int {
 	return 0
 }
 
+// GetCurrentRole retrieves the current user role from context
+func GetCurrentRole(c *gin.Context) (string, bool) {
+	userRole, exists := c.Get("role")
+	if !exists {
+		return "", false
+	}
+	switch role := userRole.(type) {
+	case commonModels.UserRole:
+		return role.String(), true
+	case string:
+		return role, true
+	default:
+		return "", false
+	}
+}
+
 // GetCurrentSessionID retrieves the current session ID from context
 func GetCurrentSessionID(c *gin.Context) int {
 	if sessionID, exists := c.Get("session_id"); exists {
 		return sessionID.(int)
 	}
 	return 0
+}
+
+// GetCurrentAPIKeyID retrieves the ID of the API key the request was made with, or 0 when it
+// was made with a bearer token
+func GetCurrentAPIKeyID(c *gin.Context) int {
+	if keyID, exists := c.Get("api_key_id"); exists {
+		return keyID.(int)
+	}
+	return 0
 }
\ No newline at end of file
@@ -0,0 +1,57 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+)
+
+// Action is the kind of change an audit log entry records
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// IsValid checks if the action is valid
+func (a Action) IsValid() bool {
+	switch a {
+	case ActionCreate, ActionUpdate, ActionDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// AuditLogEntry represents a change to a row of an audited table. Entity is the table and
+// EntityID the row's key. Creates carry the new row in After and deletes the old row in Before;
+// updates carry only the changed columns, with their old values in Before and new ones in After.
+// Entries without a user were made by the system, e.g. background jobs.
+type AuditLogEntry struct {
+	AuditID    int64           `json:"audit_id" db:"audit_id"`
+	OccurredAt time.Time       `json:"occurred_at" db:"occurred_at"`
+	UserID     *int            `json:"user_id" db:"user_id"`
+	Username   *string         `json:"username" db:"username"`
+	SessionID  *int            `json:"session_id" db:"session_id"`
+	APIKeyID   *int            `json:"api_key_id" db:"api_key_id"`
+	IPAddress  *string         `json:"ip_address" db:"ip_address"`
+	Entity     string          `json:"entity" db:"entity"`
+	EntityID   string          `json:"entity_id" db:"entity_id"`
+	Action     Action          `json:"action" db:"action"`
+	Before     json.RawMessage `json:"before" db:"before_data"`
+	After      json.RawMessage `json:"after" db:"after_data"`
+}
+
+// AuditLogFilterParams represents filtering parameters for audit log queries
+type AuditLogFilterParams struct {
+	Entity   string     `json:"entity,omitempty" form:"entity"`
+	EntityID string     `json:"entity_id,omitempty" form:"entity_id"`
+	UserID   *int       `json:"user_id,omitempty" form:"user_id"`
+	Action   *Action    `json:"action,omitempty" form:"action"`
+	DateFrom *time.Time `json:"date_from,omitempty" form:"date_from"`
+	DateTo   *time.Time `json:"date_to,omitempty" form:"date_to"`
+	common.PaginationParams
+}
@@ -0,0 +1,150 @@
+package jobs
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+)
+
+// JobRunStatus represents the outcome of a background job run
+type JobRunStatus string
+
+const (
+	JobRunStatusRunning   JobRunStatus = "running"
+	JobRunStatusSucceeded JobRunStatus = "succeeded"
+	JobRunStatusFailed    JobRunStatus = "failed"
+)
+
+// IsValid checks if the job run status is valid
+func (s JobRunStatus) IsValid() bool {
+	switch s {
+	case JobRunStatusRunning, JobRunStatusSucceeded, JobRunStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the job run status
+func (s JobRunStatus) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for JobRunStatus
+func (s JobRunStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for JobRunStatus
+func (s *JobRunStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*s = JobRunStatus(str)
+	case []byte:
+		*s = JobRunStatus(str)
+	default:
+		return fmt.Errorf("cannot scan %T into JobRunStatus", value)
+	}
+	return nil
+}
+
+// JobTrigger represents what started a background job run
+type JobTrigger string
+
+const (
+	JobTriggerSchedule JobTrigger = "schedule"
+	JobTriggerManual   JobTrigger = "manual"
+)
+
+// IsValid checks if the job trigger is valid
+func (t JobTrigger) IsValid() bool {
+	switch t {
+	case JobTriggerSchedule, JobTriggerManual:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the job trigger
+func (t JobTrigger) String() string {
+	return string(t)
+}
+
+// Value implements the driver.Valuer interface for JobTrigger
+func (t JobTrigger) Value() (driver.Value, error) {
+	return string(t), nil
+}
+
+// Scan implements the sql.Scanner interface for JobTrigger
+func (t *JobTrigger) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*t = JobTrigger(str)
+	case []byte:
+		*t = JobTrigger(str)
+	default:
+		return fmt.Errorf("cannot scan %T into JobTrigger", value)
+	}
+	return nil
+}
+
+// JobRun is one execution of a background job
+type JobRun struct {
+	RunID        int          `json:"run_id" db:"run_id"`
+	JobName      string       `json:"job_name" db:"job_name"`
+	Trigger      JobTrigger   `json:"trigger" db:"run_trigger"`
+	TriggeredBy  *int         `json:"triggered_by,omitempty" db:"triggered_by"`
+	Status       JobRunStatus `json:"status" db:"run_status"`
+	ScheduledAt  *time.Time   `json:"scheduled_at,omitempty" db:"scheduled_at"`
+	StartedAt    time.Time    `json:"started_at" db:"started_at"`
+	FinishedAt   *time.Time   `json:"finished_at,omitempty" db:"finished_at"`
+	DurationMs   *int64       `json:"duration_ms,omitempty" db:"duration_ms"`
+	Result       *string      `json:"result,omitempty" db:"result"`
+	ErrorMessage *string      `json:"error_message,omitempty" db:"error_message"`
+}
+
+// Finish records the outcome of the run at the given time
+func (r *JobRun) Finish(at time.Time, result string, err error) {
+	duration := at.Sub(r.StartedAt).Milliseconds()
+	r.FinishedAt = &at
+	r.DurationMs = &duration
+	if result != "" {
+		r.Result = &result
+	}
+	if err != nil {
+		message := err.Error()
+		r.ErrorMessage = &message
+		r.Status = JobRunStatusFailed
+		return
+	}
+	r.Status = JobRunStatusSucceeded
+}
+
+// JobInfo describes a registered background job and its schedule
+type JobInfo struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Schedule    string    `json:"schedule"`
+	NextRunAt   time.Time `json:"next_run_at"`
+	IsRunning   bool      `json:"is_running"`
+	LastRun     *JobRun   `json:"last_run,omitempty"`
+}
+
+// JobRunFilterParams represents filtering parameters for job run history queries
+type JobRunFilterParams struct {
+	JobName  string        `json:"job_name,omitempty" form:"job_name"`
+	Status   *JobRunStatus `json:"status,omitempty" form:"status"`
+	Trigger  *JobTrigger   `json:"trigger,omitempty" form:"trigger"`
+	DateFrom *time.Time    `json:"date_from,omitempty" form:"date_from" time_format:"2006-01-02"`
+	DateTo   *time.Time    `json:"date_to,omitempty" form:"date_to" time_format:"2006-01-02"`
+	common.PaginationParams
+}
@@ -29,7 +29,8 @@ func (ct CustomerType) String() string {
 	return string(ct)
 }
 
-// Customer represents a customer in the system
+// Customer represents a customer in the system. Customers with a credit limit can buy on credit
+// up to that amount of open receivables, due on their payment term.
 type Customer struct {
 	CustomerID   int          `json:"customer_id" db:"customer_id"`
 	CustomerCode string       `json:"customer_code" db:"customer_code"`
@@ -43,6 +44,8 @@ type Customer struct {
 	TaxNumber    *string      `json:"tax_number,omitempty" db:"tax_number"`
 	ContactPerson *string     `json:"contact_person,omitempty" db:"contact_person"`
 	Notes        *string      `json:"notes,omitempty" db:"notes"`
+	CreditLimit  float64      `json:"credit_limit" db:"credit_limit"`
+	PaymentTermID *int        `json:"payment_term_id,omitempty" db:"payment_term_id"`
 	IsActive     bool         `json:"is_active" db:"is_active"`
 	CreatedAt    time.Time    `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time    `json:"updated_at" db:"updated_at"`
@@ -74,6 +77,8 @@ type CustomerCreateRequest struct {
 	TaxNumber     *string      `json:"tax_number,omitempty" binding:"omitempty,max=30"`
 	ContactPerson *string      `json:"contact_person,omitempty" binding:"omitempty,max=255"`
 	Notes         *string      `json:"notes,omitempty"`
+	CreditLimit   float64      `json:"credit_limit" binding:"min=0"`
+	PaymentTermID *int         `json:"payment_term_id,omitempty" binding:"omitempty,min=1"`
 }
 
 // CustomerUpdateRequest represents a request to update a customer
@@ -88,6 +93,8 @@ type CustomerUpdateRequest struct {
 	TaxNumber     *string       `json:"tax_number,omitempty" binding:"omitempty,max=30"`
 	ContactPerson *string       `json:"contact_person,omitempty" binding:"omitempty,max=255"`
 	Notes         *string       `json:"notes,omitempty"`
+	CreditLimit   *float64      `json:"credit_limit,omitempty" binding:"omitempty,min=0"`
+	PaymentTermID *int          `json:"payment_term_id,omitempty" binding:"omitempty,min=1"`
 	IsActive      *bool         `json:"is_active,omitempty"`
 }
 
@@ -45,6 +45,7 @@ type Supplier struct {
 	ContactPerson string      `json:"contact_person" db:"contact_person"`
 	BankAccount  *string      `json:"bank_account,omitempty" db:"bank_account"`
 	PaymentTerms *string      `json:"payment_terms,omitempty" db:"payment_terms"`
+	PaymentTermID *int        `json:"payment_term_id,omitempty" db:"payment_term_id"`
 	Notes        *string      `json:"notes,omitempty" db:"notes"`
 	IsActive     bool         `json:"is_active" db:"is_active"`
 	CreatedAt    time.Time    `json:"created_at" db:"created_at"`
@@ -79,6 +80,7 @@ type SupplierCreateRequest struct {
 	ContactPerson string       `json:"contact_person" binding:"required,max=255"`
 	BankAccount   *string      `json:"bank_account,omitempty" binding:"omitempty,max=100"`
 	PaymentTerms  *string      `json:"payment_terms,omitempty" binding:"omitempty,max=255"`
+	PaymentTermID *int         `json:"payment_term_id,omitempty" binding:"omitempty,min=1"`
 	Notes         *string      `json:"notes,omitempty"`
 }
 
@@ -95,6 +97,7 @@ type SupplierUpdateRequest struct {
 	ContactPerson *string       `json:"contact_person,omitempty" binding:"omitempty,max=255"`
 	BankAccount   *string       `json:"bank_account,omitempty" binding:"omitempty,max=100"`
 	PaymentTerms  *string       `json:"payment_terms,omitempty" binding:"omitempty,max=255"`
+	PaymentTermID *int          `json:"payment_term_id,omitempty" binding:"omitempty,min=1"`
 	Notes         *string       `json:"notes,omitempty"`
 	IsActive      *bool         `json:"is_active,omitempty"`
 }
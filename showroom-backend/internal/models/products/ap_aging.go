@@ -0,0 +1,252 @@
+package products
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// AgingBucket represents a days-past-due range of the accounts payable aging report
+type AgingBucket string
+
+const (
+	AgingBucketCurrent AgingBucket = "current"
+	AgingBucket1To30   AgingBucket = "1_30"
+	AgingBucket31To60  AgingBucket = "31_60"
+	AgingBucket61To90  AgingBucket = "61_90"
+	AgingBucketOver90  AgingBucket = "over_90"
+)
+
+// AgingBuckets lists the aging buckets in report order
+var AgingBuckets = []AgingBucket{
+	AgingBucketCurrent,
+	AgingBucket1To30,
+	AgingBucket31To60,
+	AgingBucket61To90,
+	AgingBucketOver90,
+}
+
+// Label returns the column heading of the bucket
+func (b AgingBucket) Label() string {
+	switch b {
+	case AgingBucketCurrent:
+		return "Current"
+	case AgingBucket1To30:
+		return "1-30"
+	case AgingBucket31To60:
+		return "31-60"
+	case AgingBucket61To90:
+		return "61-90"
+	case AgingBucketOver90:
+		return "90+"
+	}
+	return string(b)
+}
+
+// AgingBucketFor returns the bucket for the number of days an invoice is past its due date
+func AgingBucketFor(daysPastDue int) AgingBucket {
+	switch {
+	case daysPastDue <= 0:
+		return AgingBucketCurrent
+	case daysPastDue <= 30:
+		return AgingBucket1To30
+	case daysPastDue <= 60:
+		return AgingBucket31To60
+	case daysPastDue <= 90:
+		return AgingBucket61To90
+	default:
+		return AgingBucketOver90
+	}
+}
+
+// Export formats of the accounts payable aging report
+const (
+	ReportFormatJSON = "json"
+	ReportFormatCSV  = "csv"
+	ReportFormatXLSX = "xlsx"
+)
+
+// APAgingParams represents the filters of the accounts payable aging report.
+// AsOfDate defaults to today; earlier dates age invoices using only the payments
+// and credits recorded up to that date.
+type APAgingParams struct {
+	AsOfDate        *time.Time `json:"as_of_date,omitempty" form:"as_of_date" time_format:"2006-01-02"`
+	SupplierID      *int       `json:"supplier_id,omitempty" form:"supplier_id"`
+	IncludeInvoices bool       `json:"include_invoices,omitempty" form:"include_invoices"`
+	Format          string     `json:"format,omitempty" form:"format"`
+}
+
+// Validate checks the export format
+func (p *APAgingParams) Validate() error {
+	switch p.Format {
+	case "", ReportFormatJSON, ReportFormatCSV, ReportFormatXLSX:
+		return nil
+	}
+	return fmt.Errorf("invalid report format: %s", p.Format)
+}
+
+// APAgingBucketTotals holds outstanding amounts per aging bucket
+type APAgingBucketTotals struct {
+	Current    float64 `json:"current"`
+	Days1To30  float64 `json:"days_1_30"`
+	Days31To60 float64 `json:"days_31_60"`
+	Days61To90 float64 `json:"days_61_90"`
+	Over90     float64 `json:"over_90"`
+	Total      float64 `json:"total"`
+}
+
+// Add adds an outstanding amount to its bucket and the total
+func (t *APAgingBucketTotals) Add(bucket AgingBucket, amount float64) {
+	switch bucket {
+	case AgingBucketCurrent:
+		t.Current += amount
+	case AgingBucket1To30:
+		t.Days1To30 += amount
+	case AgingBucket31To60:
+		t.Days31To60 += amount
+	case AgingBucket61To90:
+		t.Days61To90 += amount
+	case AgingBucketOver90:
+		t.Over90 += amount
+	}
+	t.Total += amount
+}
+
+// Amounts returns the bucket amounts in report order followed by the total
+func (t *APAgingBucketTotals) Amounts() []float64 {
+	return []float64{t.Current, t.Days1To30, t.Days31To60, t.Days61To90, t.Over90, t.Total}
+}
+
+// APAgingInvoice is an open supplier invoice aged as of the report date. BaseOutstandingAmount
+// is the outstanding amount converted to the base currency at the invoice's exchange rate.
+type APAgingInvoice struct {
+	InvoiceID             int         `json:"invoice_id" db:"invoice_id"`
+	InvoiceNumber         string      `json:"invoice_number" db:"invoice_number"`
+	SupplierID            int         `json:"supplier_id" db:"supplier_id"`
+	SupplierCode          string      `json:"supplier_code" db:"supplier_code"`
+	SupplierName          string      `json:"supplier_name" db:"supplier_name"`
+	POID                  *int        `json:"po_id,omitempty" db:"po_id"`
+	InvoiceDate           time.Time   `json:"invoice_date" db:"invoice_date"`
+	DueDate               time.Time   `json:"due_date" db:"due_date"`
+	CurrencyCode          string      `json:"currency_code" db:"currency_code"`
+	ExchangeRate          float64     `json:"exchange_rate" db:"exchange_rate"`
+	InvoiceAmount         float64     `json:"invoice_amount" db:"invoice_amount"`
+	OutstandingAmount     float64     `json:"outstanding_amount" db:"outstanding_amount"`
+	BaseOutstandingAmount float64     `json:"base_outstanding_amount"`
+	DaysPastDue           int         `json:"days_past_due"`
+	Bucket                AgingBucket `json:"bucket"`
+}
+
+// Age sets the days past due and bucket of the invoice as of the given date
+func (i *APAgingInvoice) Age(asOf time.Time) {
+	i.DaysPastDue = daysBetween(i.DueDate, asOf)
+	i.Bucket = AgingBucketFor(i.DaysPastDue)
+}
+
+// APAgingSupplierRow summarises the aged payables of one supplier
+type APAgingSupplierRow struct {
+	SupplierID   int    `json:"supplier_id"`
+	SupplierCode string `json:"supplier_code"`
+	SupplierName string `json:"supplier_name"`
+	InvoiceCount int    `json:"invoice_count"`
+	APAgingBucketTotals
+	Invoices []APAgingInvoice `json:"invoices,omitempty"`
+}
+
+// APAgingReport is the accounts payable aging report as of a date
+type APAgingReport struct {
+	AsOfDate  time.Time            `json:"as_of_date"`
+	Suppliers []APAgingSupplierRow `json:"suppliers"`
+	Totals    APAgingBucketTotals  `json:"totals"`
+}
+
+// NewAPAgingReport ages open invoices as of a date and groups them per supplier. Bucket amounts
+// are in the base currency. Invoices are kept on the supplier rows only when includeInvoices is set.
+func NewAPAgingReport(asOf time.Time, invoices []APAgingInvoice, includeInvoices bool) *APAgingReport {
+	report := &APAgingReport{AsOfDate: asOf, Suppliers: []APAgingSupplierRow{}}
+
+	rows := make(map[int]*APAgingSupplierRow)
+	var order []int
+	for _, invoice := range invoices {
+		invoice.Age(asOf)
+		invoice.BaseOutstandingAmount = ToBaseCurrency(invoice.OutstandingAmount, invoice.ExchangeRate)
+
+		row, ok := rows[invoice.SupplierID]
+		if !ok {
+			row = &APAgingSupplierRow{
+				SupplierID:   invoice.SupplierID,
+				SupplierCode: invoice.SupplierCode,
+				SupplierName: invoice.SupplierName,
+			}
+			rows[invoice.SupplierID] = row
+			order = append(order, invoice.SupplierID)
+		}
+
+		row.InvoiceCount++
+		row.Add(invoice.Bucket, invoice.BaseOutstandingAmount)
+		report.Totals.Add(invoice.Bucket, invoice.BaseOutstandingAmount)
+		if includeInvoices {
+			row.Invoices = append(row.Invoices, invoice)
+		}
+	}
+
+	for _, supplierID := range order {
+		report.Suppliers = append(report.Suppliers, *rows[supplierID])
+	}
+	sort.SliceStable(report.Suppliers, func(i, j int) bool {
+		return report.Suppliers[i].SupplierName < report.Suppliers[j].SupplierName
+	})
+
+	return report
+}
+
+// Table flattens the report into a header and rows for CSV and spreadsheet exports.
+// Supplier rows are followed by their invoices when the report includes them.
+func (r *APAgingReport) Table() ([]string, [][]interface{}) {
+	header := []string{"Supplier Code", "Supplier Name", "Invoice Number", "Invoice Date", "Due Date", "Days Past Due"}
+	for _, bucket := range AgingBuckets {
+		header = append(header, bucket.Label())
+	}
+	header = append(header, "Total")
+
+	var rows [][]interface{}
+	for _, supplier := range r.Suppliers {
+		row := []interface{}{supplier.SupplierCode, supplier.SupplierName, "", "", "", ""}
+		for _, amount := range supplier.Amounts() {
+			row = append(row, amount)
+		}
+		rows = append(rows, row)
+
+		for _, invoice := range supplier.Invoices {
+			row := []interface{}{
+				supplier.SupplierCode,
+				supplier.SupplierName,
+				invoice.InvoiceNumber,
+				invoice.InvoiceDate.Format("2006-01-02"),
+				invoice.DueDate.Format("2006-01-02"),
+				invoice.DaysPastDue,
+			}
+			var totals APAgingBucketTotals
+			totals.Add(invoice.Bucket, invoice.BaseOutstandingAmount)
+			for _, amount := range totals.Amounts() {
+				row = append(row, amount)
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	totalRow := []interface{}{"", "Total", "", "", "", ""}
+	for _, amount := range r.Totals.Amounts() {
+		totalRow = append(totalRow, amount)
+	}
+	rows = append(rows, totalRow)
+
+	return header, rows
+}
+
+// daysBetween returns the number of calendar days from one date to another
+func daysBetween(from, to time.Time) int {
+	fromDay := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDay := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toDay.Sub(fromDay).Hours() / 24)
+}
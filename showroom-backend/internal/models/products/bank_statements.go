@@ -0,0 +1,586 @@
+package products
+
+import (
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+)
+
+// BankMatchStatus represents whether a bank statement line has been reconciled
+type BankMatchStatus string
+
+const (
+	BankMatchStatusUnmatched BankMatchStatus = "unmatched"
+	BankMatchStatusMatched   BankMatchStatus = "matched"
+)
+
+// IsValid checks if the bank match status is valid
+func (s BankMatchStatus) IsValid() bool {
+	switch s {
+	case BankMatchStatusUnmatched, BankMatchStatusMatched:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the bank match status
+func (s BankMatchStatus) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for BankMatchStatus
+func (s BankMatchStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for BankMatchStatus
+func (s *BankMatchStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*s = BankMatchStatus(str)
+	case []byte:
+		*s = BankMatchStatus(str)
+	default:
+		return fmt.Errorf("cannot scan %T into BankMatchStatus", value)
+	}
+	return nil
+}
+
+// BankMatchMethod records how a statement line was matched
+type BankMatchMethod string
+
+const (
+	BankMatchMethodAuto   BankMatchMethod = "auto"
+	BankMatchMethodManual BankMatchMethod = "manual"
+)
+
+// IsValid checks if the bank match method is valid
+func (m BankMatchMethod) IsValid() bool {
+	switch m {
+	case BankMatchMethodAuto, BankMatchMethodManual:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the bank match method
+func (m BankMatchMethod) String() string {
+	return string(m)
+}
+
+// Value implements the driver.Valuer interface for BankMatchMethod
+func (m BankMatchMethod) Value() (driver.Value, error) {
+	return string(m), nil
+}
+
+// Scan implements the sql.Scanner interface for BankMatchMethod
+func (m *BankMatchMethod) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*m = BankMatchMethod(str)
+	case []byte:
+		*m = BankMatchMethod(str)
+	default:
+		return fmt.Errorf("cannot scan %T into BankMatchMethod", value)
+	}
+	return nil
+}
+
+// DefaultBankMatchWindowDays is how many days a statement line may be booked before or after the payment date
+const DefaultBankMatchWindowDays = 3
+
+// BankStatement is one imported bank statement file of a bank account
+type BankStatement struct {
+	StatementID  int        `json:"statement_id" db:"statement_id"`
+	BankAccount  string     `json:"bank_account" db:"bank_account"`
+	FileName     string     `json:"file_name" db:"file_name"`
+	PeriodStart  *time.Time `json:"period_start,omitempty" db:"period_start"`
+	PeriodEnd    *time.Time `json:"period_end,omitempty" db:"period_end"`
+	LineCount    int        `json:"line_count" db:"line_count"`
+	MatchedCount int        `json:"matched_count" db:"matched_count"`
+	TotalDebit   float64    `json:"total_debit" db:"total_debit"`
+	TotalCredit  float64    `json:"total_credit" db:"total_credit"`
+	ImportedBy   int        `json:"imported_by" db:"imported_by"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+
+	// Related data
+	Lines        []BankStatementLine `json:"lines,omitempty" db:"-"`
+	SkippedLines int                 `json:"skipped_lines,omitempty" db:"-"`
+}
+
+// BankStatementLine is one transaction on a bank statement. Money leaving the account is negative.
+// Debit lines are matched to supplier payment vouchers and credit lines to customer receipts.
+type BankStatementLine struct {
+	LineID          int              `json:"line_id" db:"line_id"`
+	StatementID     int              `json:"statement_id" db:"statement_id"`
+	BankAccount     string           `json:"bank_account" db:"bank_account"`
+	LineNumber      int              `json:"line_number" db:"line_number"`
+	TransactionDate time.Time        `json:"transaction_date" db:"transaction_date"`
+	Description     string           `json:"description" db:"description"`
+	Reference       *string          `json:"reference,omitempty" db:"reference"`
+	Amount          float64          `json:"amount" db:"amount"`
+	Balance         *float64         `json:"balance,omitempty" db:"balance"`
+	Fingerprint     string           `json:"-" db:"fingerprint"`
+	MatchStatus     BankMatchStatus  `json:"match_status" db:"match_status"`
+	MatchMethod     *BankMatchMethod `json:"match_method,omitempty" db:"match_method"`
+	VoucherID       *int             `json:"voucher_id,omitempty" db:"voucher_id"`
+	ReceiptID       *int             `json:"receipt_id,omitempty" db:"receipt_id"`
+	MatchedBy       *int             `json:"matched_by,omitempty" db:"matched_by"`
+	MatchedAt       *time.Time       `json:"matched_at,omitempty" db:"matched_at"`
+
+	// Related data
+	VoucherNumber *string `json:"voucher_number,omitempty" db:"voucher_number"`
+	ReceiptNumber *string `json:"receipt_number,omitempty" db:"receipt_number"`
+}
+
+// BankStatementColumnMapping names the CSV header of each statement field. Amounts come either
+// from one signed Amount column or from separate Debit and Credit columns.
+type BankStatementColumnMapping struct {
+	Date         string `json:"date"`
+	Description  string `json:"description"`
+	Reference    string `json:"reference,omitempty"`
+	Amount       string `json:"amount,omitempty"`
+	Debit        string `json:"debit,omitempty"`
+	Credit       string `json:"credit,omitempty"`
+	Balance      string `json:"balance,omitempty"`
+	DateFormat   string `json:"date_format,omitempty"`
+	Delimiter    string `json:"delimiter,omitempty"`
+	DecimalComma bool   `json:"decimal_comma,omitempty"`
+	SkipRows     int    `json:"skip_rows,omitempty"`
+}
+
+// DefaultBankStatementColumnMapping returns the mapping used when an import does not provide one
+func DefaultBankStatementColumnMapping() BankStatementColumnMapping {
+	return BankStatementColumnMapping{
+		Date:        "date",
+		Description: "description",
+		Reference:   "reference",
+		Amount:      "amount",
+		DateFormat:  "2006-01-02",
+		Delimiter:   ",",
+	}
+}
+
+// BankStatementLineFilterParams represents filtering parameters for the lines of a statement
+type BankStatementLineFilterParams struct {
+	MatchStatus *BankMatchStatus `json:"match_status,omitempty" form:"match_status"`
+}
+
+// BankStatementFilterParams represents filtering parameters for bank statement queries
+type BankStatementFilterParams struct {
+	BankAccount string     `json:"bank_account,omitempty" form:"bank_account"`
+	DateFrom    *time.Time `json:"date_from,omitempty" form:"date_from" time_format:"2006-01-02"`
+	DateTo      *time.Time `json:"date_to,omitempty" form:"date_to" time_format:"2006-01-02"`
+	common.PaginationParams
+}
+
+// BankAutoMatchRequest represents a request to match the unmatched lines of a statement to payments and receipts
+type BankAutoMatchRequest struct {
+	DateWindowDays *int `json:"date_window_days,omitempty" binding:"omitempty,min=0,max=31"`
+}
+
+// BankManualMatchRequest represents a request to match a statement line to a payment voucher
+// or, for incoming transactions, to a customer receipt. Exactly one of the two is given.
+type BankManualMatchRequest struct {
+	VoucherID int `json:"voucher_id,omitempty" binding:"omitempty,min=1"`
+	ReceiptID int `json:"receipt_id,omitempty" binding:"omitempty,min=1"`
+}
+
+// BankAutoMatchResult summarizes an auto-match pass over a statement
+type BankAutoMatchResult struct {
+	StatementID    int `json:"statement_id"`
+	MatchedLines   int `json:"matched_lines"`
+	UnmatchedLines int `json:"unmatched_lines"`
+}
+
+// BankMatchCandidate is a posted supplier payment or customer receipt that can still be matched
+// to a statement line. Exactly one of VoucherID and ReceiptID is set.
+type BankMatchCandidate struct {
+	VoucherID        int       `json:"voucher_id,omitempty"`
+	ReceiptID        int       `json:"receipt_id,omitempty"`
+	DocumentNumber   string    `json:"document_number"`
+	PaymentDate      time.Time `json:"payment_date"`
+	PaymentReference *string   `json:"payment_reference,omitempty"`
+	// Amount is the payment in the base currency, the amount that left or entered the bank account
+	Amount float64 `json:"amount"`
+}
+
+// IsReceipt reports whether the candidate is a customer receipt, matched to credit lines
+func (c BankMatchCandidate) IsReceipt() bool {
+	return c.ReceiptID != 0
+}
+
+// Match pairs the candidate with a statement line
+func (c BankMatchCandidate) Match(lineID int) BankStatementMatch {
+	return BankStatementMatch{LineID: lineID, VoucherID: c.VoucherID, ReceiptID: c.ReceiptID}
+}
+
+// BankStatementMatch pairs a statement line with the payment or receipt it reconciles
+type BankStatementMatch struct {
+	LineID    int `json:"line_id"`
+	VoucherID int `json:"voucher_id,omitempty"`
+	ReceiptID int `json:"receipt_id,omitempty"`
+}
+
+// BankReconciliationParams selects the bank account and period of a reconciliation summary
+type BankReconciliationParams struct {
+	BankAccount string     `json:"bank_account,omitempty" form:"bank_account"`
+	DateFrom    *time.Time `json:"date_from,omitempty" form:"date_from" time_format:"2006-01-02"`
+	DateTo      *time.Time `json:"date_to,omitempty" form:"date_to" time_format:"2006-01-02"`
+}
+
+// BankAccountReconciliation summarizes the statement lines of one bank account in a period
+type BankAccountReconciliation struct {
+	BankAccount     string  `json:"bank_account"`
+	StatementLines  int     `json:"statement_lines"`
+	TotalDebit      float64 `json:"total_debit"`
+	TotalCredit     float64 `json:"total_credit"`
+	MatchedLines    int     `json:"matched_lines"`
+	MatchedAmount   float64 `json:"matched_amount"`
+	UnmatchedLines  int     `json:"unmatched_lines"`
+	UnmatchedDebit  float64 `json:"unmatched_debit"`
+	UnmatchedCredit float64 `json:"unmatched_credit"`
+}
+
+// BankReconciliationReport summarizes reconciliation per bank account, with the posted non-cash
+// payments and customer receipts of the period that no statement line accounts for yet
+type BankReconciliationReport struct {
+	DateFrom                  *time.Time                  `json:"date_from,omitempty"`
+	DateTo                    *time.Time                  `json:"date_to,omitempty"`
+	Accounts                  []BankAccountReconciliation `json:"accounts"`
+	UnreconciledPayments      int                         `json:"unreconciled_payments"`
+	UnreconciledPaymentAmount float64                     `json:"unreconciled_payment_amount"`
+	UnreconciledReceipts      int                         `json:"unreconciled_receipts"`
+	UnreconciledReceiptAmount float64                     `json:"unreconciled_receipt_amount"`
+}
+
+// BankUnreconciledTotals counts the posted documents of a period not matched to a statement line
+type BankUnreconciledTotals struct {
+	Count  int
+	Amount float64
+}
+
+// ParseBankStatementCSV reads statement lines from a CSV file using the column mapping.
+// Blank rows are skipped and errors name the row they come from.
+func ParseBankStatementCSV(r io.Reader, mapping BankStatementColumnMapping) ([]BankStatementLine, error) {
+	if mapping.DateFormat == "" {
+		mapping.DateFormat = "2006-01-02"
+	}
+	if mapping.Date == "" || mapping.Description == "" {
+		return nil, fmt.Errorf("column mapping needs date and description columns")
+	}
+	if mapping.Amount == "" && mapping.Debit == "" && mapping.Credit == "" {
+		return nil, fmt.Errorf("column mapping needs an amount column or debit and credit columns")
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if mapping.Delimiter != "" {
+		delimiter := []rune(mapping.Delimiter)
+		if len(delimiter) != 1 {
+			return nil, fmt.Errorf("delimiter must be a single character")
+		}
+		reader.Comma = delimiter[0]
+	}
+
+	for i := 0; i < mapping.SkipRows; i++ {
+		if _, err := reader.Read(); err != nil {
+			return nil, fmt.Errorf("failed to skip row %d: %w", i+1, err)
+		}
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header row: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+
+	column := func(name string) (int, error) {
+		if name == "" {
+			return -1, nil
+		}
+		index, ok := columns[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return -1, fmt.Errorf("column %q not found in header", name)
+		}
+		return index, nil
+	}
+
+	// Resolve in a fixed order so the first missing column reported is always the same
+	indexes := make(map[string]int)
+	for _, field := range [][2]string{
+		{"date", mapping.Date}, {"description", mapping.Description}, {"reference", mapping.Reference},
+		{"amount", mapping.Amount}, {"debit", mapping.Debit}, {"credit", mapping.Credit}, {"balance", mapping.Balance},
+	} {
+		if indexes[field[0]], err = column(field[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	var lines []BankStatementLine
+	occurrences := make(map[string]int)
+	rowNumber := mapping.SkipRows + 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		rowNumber++
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", rowNumber, err)
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		value := func(field string) string {
+			index := indexes[field]
+			if index < 0 || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		line := BankStatementLine{
+			LineNumber:  len(lines) + 1,
+			Description: value("description"),
+			MatchStatus: BankMatchStatusUnmatched,
+		}
+
+		if line.TransactionDate, err = time.Parse(mapping.DateFormat, value("date")); err != nil {
+			return nil, fmt.Errorf("row %d: invalid date %q", rowNumber, value("date"))
+		}
+
+		if mapping.Amount != "" {
+			if line.Amount, err = parseStatementAmount(value("amount"), mapping.DecimalComma); err != nil {
+				return nil, fmt.Errorf("row %d: %w", rowNumber, err)
+			}
+		} else {
+			debit, err := parseStatementAmount(value("debit"), mapping.DecimalComma)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %w", rowNumber, err)
+			}
+			credit, err := parseStatementAmount(value("credit"), mapping.DecimalComma)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %w", rowNumber, err)
+			}
+			line.Amount = math.Round((credit-math.Abs(debit))*100) / 100
+		}
+
+		if reference := value("reference"); reference != "" {
+			line.Reference = &reference
+		}
+		if balance := value("balance"); balance != "" {
+			parsed, err := parseStatementAmount(balance, mapping.DecimalComma)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %w", rowNumber, err)
+			}
+			line.Balance = &parsed
+		}
+
+		line.Fingerprint = statementLineFingerprint(line, occurrences)
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("bank statement has no transactions")
+	}
+	return lines, nil
+}
+
+// parseStatementAmount parses amounts such as "1,250.50", "(1,250.50)", "1.250,50" with decimal
+// commas, or "1,250.50 DB" where a DB/DR suffix marks a debit
+func parseStatementAmount(value string, decimalComma bool) (float64, error) {
+	raw := value
+	value = strings.ReplaceAll(strings.TrimSpace(value), " ", "")
+	if value == "" {
+		return 0, nil
+	}
+
+	negative := false
+	upper := strings.ToUpper(value)
+	switch {
+	case strings.HasSuffix(upper, "DB"), strings.HasSuffix(upper, "DR"):
+		negative = true
+		value = value[:len(value)-2]
+	case strings.HasSuffix(upper, "CR"):
+		value = value[:len(value)-2]
+	}
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = value[1 : len(value)-1]
+	}
+
+	if decimalComma {
+		value = strings.ReplaceAll(value, ".", "")
+		value = strings.ReplaceAll(value, ",", ".")
+	} else {
+		value = strings.ReplaceAll(value, ",", "")
+	}
+
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", raw)
+	}
+	if negative {
+		amount = -math.Abs(amount)
+	}
+	return math.Round(amount*100) / 100, nil
+}
+
+// statementLineFingerprint identifies a transaction so importing an overlapping statement again
+// skips the lines already stored. Identical transactions in one file are told apart by occurrence.
+func statementLineFingerprint(line BankStatementLine, occurrences map[string]int) string {
+	reference, balance := "", ""
+	if line.Reference != nil {
+		reference = *line.Reference
+	}
+	if line.Balance != nil {
+		balance = fmt.Sprintf("%.2f", *line.Balance)
+	}
+
+	key := strings.Join([]string{
+		line.TransactionDate.Format("2006-01-02"),
+		fmt.Sprintf("%.2f", line.Amount),
+		strings.ToLower(line.Description),
+		strings.ToLower(reference),
+		balance,
+	}, "|")
+	occurrences[key]++
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d", key, occurrences[key])))
+	return hex.EncodeToString(sum[:])
+}
+
+// MatchBankStatementLines pairs unmatched debit lines with supplier payments and credit lines with
+// customer receipts of the same amount dated within windowDays of the transaction date. A candidate
+// whose document number or reference appears in the line description or reference is preferred, then
+// the closest date. Lines with several equally good candidates are left for manual matching, and each
+// candidate is matched at most once.
+func MatchBankStatementLines(lines []BankStatementLine, candidates []BankMatchCandidate, windowDays int) []BankStatementMatch {
+	type scored struct {
+		candidate BankMatchCandidate
+		score     int
+	}
+
+	used := make(map[BankStatementMatch]bool)
+	var matches []BankStatementMatch
+
+	// Lines carrying a payment reference claim their payment first
+	ordered := make([]BankStatementLine, 0, len(lines))
+	for _, line := range lines {
+		if line.MatchStatus == BankMatchStatusUnmatched && line.Amount != 0 {
+			ordered = append(ordered, line)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return bestCandidateScore(ordered[i], candidates, windowDays) > bestCandidateScore(ordered[j], candidates, windowDays)
+	})
+
+	for _, line := range ordered {
+		var best []scored
+		for _, candidate := range candidates {
+			if used[candidate.Match(0)] {
+				continue
+			}
+			score, ok := bankMatchScore(line, candidate, windowDays)
+			if !ok {
+				continue
+			}
+			switch {
+			case len(best) == 0 || score > best[0].score:
+				best = []scored{{candidate, score}}
+			case score == best[0].score:
+				best = append(best, scored{candidate, score})
+			}
+		}
+
+		if len(best) == 1 {
+			used[best[0].candidate.Match(0)] = true
+			matches = append(matches, best[0].candidate.Match(line.LineID))
+		}
+	}
+
+	return matches
+}
+
+func bestCandidateScore(line BankStatementLine, candidates []BankMatchCandidate, windowDays int) int {
+	best := -1
+	for _, candidate := range candidates {
+		if score, ok := bankMatchScore(line, candidate, windowDays); ok && score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+// bankMatchScore rates a candidate payment or receipt for a statement line. A reference hit
+// outweighs any date distance inside the window; closer dates score higher.
+func bankMatchScore(line BankStatementLine, candidate BankMatchCandidate, windowDays int) (int, bool) {
+	if (line.Amount > 0) != candidate.IsReceipt() {
+		return 0, false
+	}
+	if math.Abs(math.Abs(line.Amount)-candidate.Amount) > 0.005 {
+		return 0, false
+	}
+
+	days := daysBetween(candidate.PaymentDate, line.TransactionDate)
+	if days < 0 {
+		days = -days
+	}
+	if days > windowDays {
+		return 0, false
+	}
+
+	score := windowDays - days
+	text := strings.ToLower(line.Description)
+	if line.Reference != nil {
+		text += " " + strings.ToLower(*line.Reference)
+	}
+	if strings.Contains(text, strings.ToLower(candidate.DocumentNumber)) ||
+		(candidate.PaymentReference != nil && *candidate.PaymentReference != "" &&
+			strings.Contains(text, strings.ToLower(*candidate.PaymentReference))) {
+		score += 1000
+	}
+
+	return score, true
+}
+
+// NewBankReconciliationReport orders the per-account summaries by bank account
+func NewBankReconciliationReport(params *BankReconciliationParams, accounts []BankAccountReconciliation, payments, receipts BankUnreconciledTotals) *BankReconciliationReport {
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].BankAccount < accounts[j].BankAccount })
+	if accounts == nil {
+		accounts = []BankAccountReconciliation{}
+	}
+	return &BankReconciliationReport{
+		DateFrom:                  params.DateFrom,
+		DateTo:                    params.DateTo,
+		Accounts:                  accounts,
+		UnreconciledPayments:      payments.Count,
+		UnreconciledPaymentAmount: payments.Amount,
+		UnreconciledReceipts:      receipts.Count,
+		UnreconciledReceiptAmount: receipts.Amount,
+	}
+}
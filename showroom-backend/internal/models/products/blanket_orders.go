@@ -0,0 +1,195 @@
+package products
+
+import (
+	"fmt"
+	"time"
+)
+
+// BlanketOrderTerms holds the agreed quantity, value and validity of a blanket or contract purchase order.
+// The fixed prices are the unit costs of the blanket order's own line items.
+type BlanketOrderTerms struct {
+	POID             int       `json:"po_id" db:"po_id"`
+	AgreedQuantity   *int      `json:"agreed_quantity,omitempty" db:"agreed_quantity"`
+	AgreedValue      *float64  `json:"agreed_value,omitempty" db:"agreed_value"`
+	ValidFrom        time.Time `json:"valid_from" db:"valid_from"`
+	ValidTo          time.Time `json:"valid_to" db:"valid_to"`
+	ConsumedQuantity int       `json:"consumed_quantity" db:"consumed_quantity"`
+	ConsumedValue    float64   `json:"consumed_value" db:"consumed_value"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// BlanketOrderTermsRequest represents the blanket terms supplied when creating a blanket or contract purchase order
+type BlanketOrderTermsRequest struct {
+	AgreedQuantity *int      `json:"agreed_quantity,omitempty" binding:"omitempty,min=1"`
+	AgreedValue    *float64  `json:"agreed_value,omitempty" binding:"omitempty,gt=0"`
+	ValidFrom      time.Time `json:"valid_from" binding:"required"`
+	ValidTo        time.Time `json:"valid_to" binding:"required"`
+}
+
+// BlanketReleaseStatus represents the status of a release drawn against a blanket order
+type BlanketReleaseStatus string
+
+const (
+	BlanketReleaseStatusActive    BlanketReleaseStatus = "active"
+	BlanketReleaseStatusCancelled BlanketReleaseStatus = "cancelled"
+)
+
+// BlanketRelease links a release purchase order to the blanket order it was drawn against
+type BlanketRelease struct {
+	ReleaseID       int                  `json:"release_id" db:"release_id"`
+	BlanketPOID     int                  `json:"blanket_po_id" db:"blanket_po_id"`
+	ReleasePOID     int                  `json:"release_po_id" db:"release_po_id"`
+	ReleaseQuantity int                  `json:"release_quantity" db:"release_quantity"`
+	ReleaseValue    float64              `json:"release_value" db:"release_value"`
+	ReleaseStatus   BlanketReleaseStatus `json:"release_status" db:"release_status"`
+	ReleasedBy      int                  `json:"released_by" db:"released_by"`
+	CreatedAt       time.Time            `json:"created_at" db:"created_at"`
+
+	// Related data
+	ReleasePONumber string   `json:"release_po_number,omitempty" db:"release_po_number"`
+	ReleasePOStatus POStatus `json:"release_po_status,omitempty" db:"release_po_status"`
+}
+
+// BlanketReleaseCreateRequest represents a request to draw a release order against a blanket order
+type BlanketReleaseCreateRequest struct {
+	RequiredDate         *time.Time                  `json:"required_date,omitempty"`
+	ExpectedDeliveryDate *time.Time                  `json:"expected_delivery_date,omitempty"`
+	DeliveryAddress      *string                     `json:"delivery_address,omitempty" binding:"omitempty,max=500"`
+	PONotes              *string                     `json:"po_notes,omitempty"`
+	Items                []BlanketReleaseItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// BlanketReleaseItemRequest represents one product released from a blanket order
+type BlanketReleaseItemRequest struct {
+	ProductID int     `json:"product_id" binding:"required,min=1"`
+	Quantity  int     `json:"quantity" binding:"required,min=1"`
+	ItemNotes *string `json:"item_notes,omitempty"`
+}
+
+// BlanketLineBalance shows the agreed, released and remaining quantity of one blanket order line
+type BlanketLineBalance struct {
+	PODetailID        int     `json:"po_detail_id" db:"po_detail_id"`
+	ProductID         int     `json:"product_id" db:"product_id"`
+	ItemDescription   *string `json:"item_description,omitempty" db:"item_description"`
+	UnitCost          float64 `json:"unit_cost" db:"unit_cost"`
+	TaxCodeID         *int    `json:"tax_code_id,omitempty" db:"tax_code_id"`
+	TaxRate           float64 `json:"tax_rate" db:"tax_rate"`
+	AgreedQuantity    int     `json:"agreed_quantity" db:"agreed_quantity"`
+	ReleasedQuantity  int     `json:"released_quantity" db:"released_quantity"`
+	RemainingQuantity int     `json:"remaining_quantity" db:"remaining_quantity"`
+}
+
+// BlanketOrderBalance summarises consumption of a blanket order
+type BlanketOrderBalance struct {
+	POID              int                  `json:"po_id"`
+	PONumber          string               `json:"po_number"`
+	Terms             *BlanketOrderTerms   `json:"terms"`
+	RemainingQuantity *int                 `json:"remaining_quantity,omitempty"`
+	RemainingValue    *float64             `json:"remaining_value,omitempty"`
+	IsExpired         bool                 `json:"is_expired"`
+	IsExhausted       bool                 `json:"is_exhausted"`
+	Lines             []BlanketLineBalance `json:"lines"`
+	Releases          []BlanketRelease     `json:"releases"`
+}
+
+// IsBlanketType checks if the PO type is drawn down through release orders
+func (t POType) IsBlanketType() bool {
+	return t == POTypeBlanket || t == POTypeContract
+}
+
+// Validate checks the blanket terms request
+func (r *BlanketOrderTermsRequest) Validate() error {
+	if r.AgreedQuantity == nil && r.AgreedValue == nil {
+		return fmt.Errorf("blanket terms require an agreed quantity or an agreed value")
+	}
+	if !r.ValidTo.After(r.ValidFrom) {
+		return fmt.Errorf("blanket validity end must be after its start")
+	}
+	return nil
+}
+
+// GetRemainingQuantity returns the quantity still available, or nil when no quantity was agreed
+func (t *BlanketOrderTerms) GetRemainingQuantity() *int {
+	if t.AgreedQuantity == nil {
+		return nil
+	}
+	remaining := *t.AgreedQuantity - t.ConsumedQuantity
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &remaining
+}
+
+// GetRemainingValue returns the value still available, or nil when no value was agreed
+func (t *BlanketOrderTerms) GetRemainingValue() *float64 {
+	if t.AgreedValue == nil {
+		return nil
+	}
+	remaining := *t.AgreedValue - t.ConsumedValue
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &remaining
+}
+
+// IsExpired checks if the blanket order is outside its validity window at the given time.
+// Validity covers whole days, so releases are allowed until the end of the last valid day.
+func (t *BlanketOrderTerms) IsExpired(at time.Time) bool {
+	return daysBetween(t.ValidFrom, at) < 0 || daysBetween(at, t.ValidTo) < 0
+}
+
+// IsExhausted checks if the agreed quantity or value has been fully consumed
+func (t *BlanketOrderTerms) IsExhausted() bool {
+	if remaining := t.GetRemainingQuantity(); remaining != nil && *remaining <= 0 {
+		return true
+	}
+	if remaining := t.GetRemainingValue(); remaining != nil && *remaining <= 0 {
+		return true
+	}
+	return false
+}
+
+// CheckBlanketReleaseLines checks that every product of a release is on the blanket order and that
+// the release draws no more than the remaining quantity of its blanket line
+func CheckBlanketReleaseLines(lines []BlanketLineBalance, details []PurchaseOrderDetail) error {
+	remaining := make(map[int]int, len(lines))
+	for _, line := range lines {
+		remaining[line.ProductID] = line.RemainingQuantity
+	}
+
+	requested := make(map[int]int)
+	for _, detail := range details {
+		available, ok := remaining[detail.ProductID]
+		if !ok {
+			return fmt.Errorf("product %d is not on the blanket order", detail.ProductID)
+		}
+
+		requested[detail.ProductID] += detail.QuantityOrdered
+		if requested[detail.ProductID] > available {
+			return fmt.Errorf("release quantity for product %d exceeds remaining blanket line quantity %d",
+				detail.ProductID, available)
+		}
+	}
+	return nil
+}
+
+// CanRelease checks if a release of the given quantity and value fits in the blanket order at the given time
+func (t *BlanketOrderTerms) CanRelease(quantity int, value float64, at time.Time) error {
+	if daysBetween(t.ValidFrom, at) < 0 {
+		return fmt.Errorf("blanket order is not valid until %s", t.ValidFrom.Format("2006-01-02"))
+	}
+	if daysBetween(at, t.ValidTo) < 0 {
+		return fmt.Errorf("blanket order expired on %s", t.ValidTo.Format("2006-01-02"))
+	}
+	if t.IsExhausted() {
+		return fmt.Errorf("blanket order is exhausted")
+	}
+	if remaining := t.GetRemainingQuantity(); remaining != nil && quantity > *remaining {
+		return fmt.Errorf("release quantity %d exceeds remaining blanket quantity %d", quantity, *remaining)
+	}
+	if remaining := t.GetRemainingValue(); remaining != nil && value > *remaining {
+		return fmt.Errorf("release value %.2f exceeds remaining blanket value %.2f", value, *remaining)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package products
+
+import (
+	"database/sql/driver"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+)
+
+// BaseCurrency is the currency of the general ledger, stock valuation and reports.
+// Documents in other currencies are converted at the exchange rate they carry.
+const BaseCurrency = "IDR"
+
+// ExchangeRateSource records how an exchange rate was entered
+type ExchangeRateSource string
+
+const (
+	ExchangeRateSourceManual ExchangeRateSource = "manual"
+	ExchangeRateSourceImport ExchangeRateSource = "import"
+)
+
+// IsValid checks if the exchange rate source is valid
+func (s ExchangeRateSource) IsValid() bool {
+	switch s {
+	case ExchangeRateSourceManual, ExchangeRateSourceImport:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the exchange rate source
+func (s ExchangeRateSource) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for ExchangeRateSource
+func (s ExchangeRateSource) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for ExchangeRateSource
+func (s *ExchangeRateSource) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*s = ExchangeRateSource(str)
+	case []byte:
+		*s = ExchangeRateSource(str)
+	default:
+		return fmt.Errorf("cannot scan %T into ExchangeRateSource", value)
+	}
+	return nil
+}
+
+// ExchangeRate is the value of one unit of a foreign currency in the base currency from RateDate
+// until the next dated rate of that currency
+type ExchangeRate struct {
+	RateID       int                `json:"rate_id" db:"rate_id"`
+	CurrencyCode string             `json:"currency_code" db:"currency_code"`
+	RateDate     time.Time          `json:"rate_date" db:"rate_date"`
+	Rate         float64            `json:"rate" db:"rate"`
+	Source       ExchangeRateSource `json:"source" db:"source"`
+	CreatedBy    int                `json:"created_by" db:"created_by"`
+	CreatedAt    time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// ExchangeRateCreateRequest represents a request to enter the rate of a currency on a date.
+// Entering a rate for a date that already has one replaces it.
+type ExchangeRateCreateRequest struct {
+	CurrencyCode string    `json:"currency_code" binding:"required,len=3"`
+	RateDate     time.Time `json:"rate_date" binding:"required"`
+	Rate         float64   `json:"rate" binding:"required,gt=0"`
+}
+
+// ExchangeRateFilterParams represents filtering parameters for exchange rate queries
+type ExchangeRateFilterParams struct {
+	CurrencyCode string     `json:"currency_code,omitempty" form:"currency_code"`
+	DateFrom     *time.Time `json:"date_from,omitempty" form:"date_from" time_format:"2006-01-02"`
+	DateTo       *time.Time `json:"date_to,omitempty" form:"date_to" time_format:"2006-01-02"`
+	common.PaginationParams
+}
+
+// ExchangeRateLookupParams asks for the rate of a currency in effect on a date, today by default
+type ExchangeRateLookupParams struct {
+	CurrencyCode string     `json:"currency_code" form:"currency_code" binding:"required,len=3"`
+	Date         *time.Time `json:"date,omitempty" form:"date" time_format:"2006-01-02"`
+}
+
+// ExchangeRateImportResult summarises an exchange rate CSV import
+type ExchangeRateImportResult struct {
+	Imported   int      `json:"imported"`
+	Currencies []string `json:"currencies"`
+}
+
+// NormalizeCurrencyCode upper-cases an ISO 4217 currency code. An empty code means the base currency.
+func NormalizeCurrencyCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return BaseCurrency, nil
+	}
+	if len(code) != 3 {
+		return "", fmt.Errorf("invalid currency code %q", code)
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return "", fmt.Errorf("invalid currency code %q", code)
+		}
+	}
+	return code, nil
+}
+
+// ToBaseCurrency converts a document amount at the document's exchange rate, rounded to cents.
+// Documents without a rate are already in the base currency.
+func ToBaseCurrency(amount, rate float64) float64 {
+	if rate <= 0 {
+		return roundCents(amount)
+	}
+	return roundCents(amount * rate)
+}
+
+// ExchangeDifference returns the realized exchange gain, or loss when negative, of settling a foreign
+// currency amount booked at bookedRate with a settlement made at settledRate
+func ExchangeDifference(amount, bookedRate, settledRate float64) float64 {
+	return roundCents(ToBaseCurrency(amount, bookedRate) - ToBaseCurrency(amount, settledRate))
+}
+
+// ParseExchangeRateCSV reads dated rates from a CSV file with currency_code, rate_date (YYYY-MM-DD)
+// and rate columns. Blank rows are skipped and errors name the row they come from.
+func ParseExchangeRateCSV(r io.Reader) ([]ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header row: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range []string{"currency_code", "rate_date", "rate"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("column %q not found in header", name)
+		}
+	}
+
+	var rates []ExchangeRate
+	rowNumber := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		rowNumber++
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", rowNumber, err)
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		value := func(name string) string {
+			index := columns[name]
+			if index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		rate := ExchangeRate{Source: ExchangeRateSourceImport}
+		if rate.CurrencyCode, err = NormalizeCurrencyCode(value("currency_code")); err != nil || value("currency_code") == "" {
+			return nil, fmt.Errorf("row %d: invalid currency code %q", rowNumber, value("currency_code"))
+		}
+		if rate.CurrencyCode == BaseCurrency {
+			return nil, fmt.Errorf("row %d: %s is the base currency", rowNumber, BaseCurrency)
+		}
+		if rate.RateDate, err = time.Parse("2006-01-02", value("rate_date")); err != nil {
+			return nil, fmt.Errorf("row %d: invalid date %q", rowNumber, value("rate_date"))
+		}
+		if rate.Rate, err = strconv.ParseFloat(strings.ReplaceAll(value("rate"), ",", ""), 64); err != nil || rate.Rate <= 0 {
+			return nil, fmt.Errorf("row %d: invalid rate %q", rowNumber, value("rate"))
+		}
+
+		rates = append(rates, rate)
+	}
+
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("file contains no exchange rates")
+	}
+
+	return rates, nil
+}
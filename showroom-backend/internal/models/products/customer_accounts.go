@@ -0,0 +1,434 @@
+package products
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+)
+
+// CustomerReceiptStatus represents the status of a customer receipt
+type CustomerReceiptStatus string
+
+const (
+	CustomerReceiptStatusPosted CustomerReceiptStatus = "posted"
+	CustomerReceiptStatusVoid   CustomerReceiptStatus = "void"
+)
+
+// IsValid checks if the customer receipt status is valid
+func (s CustomerReceiptStatus) IsValid() bool {
+	switch s {
+	case CustomerReceiptStatusPosted, CustomerReceiptStatusVoid:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the customer receipt status
+func (s CustomerReceiptStatus) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for CustomerReceiptStatus
+func (s CustomerReceiptStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for CustomerReceiptStatus
+func (s *CustomerReceiptStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*s = CustomerReceiptStatus(str)
+	case []byte:
+		*s = CustomerReceiptStatus(str)
+	default:
+		return fmt.Errorf("cannot scan %T into CustomerReceiptStatus", value)
+	}
+	return nil
+}
+
+// CustomerReceipt represents money received from a customer. A receipt can be allocated across
+// several sales invoices and an invoice can be settled by several receipts.
+type CustomerReceipt struct {
+	ReceiptID         int                   `json:"receipt_id" db:"receipt_id"`
+	ReceiptNumber     string                `json:"receipt_number" db:"receipt_number"`
+	CustomerID        int                   `json:"customer_id" db:"customer_id"`
+	CustomerName      string                `json:"customer_name" db:"customer_name"`
+	ReceiptDate       time.Time             `json:"receipt_date" db:"receipt_date"`
+	PaymentMethod     PaymentMethod         `json:"payment_method" db:"payment_method"`
+	PaymentReference  *string               `json:"payment_reference,omitempty" db:"payment_reference"`
+	Amount            float64               `json:"amount" db:"amount"`
+	AllocatedAmount   float64               `json:"allocated_amount" db:"allocated_amount"`
+	UnallocatedAmount float64               `json:"unallocated_amount" db:"unallocated_amount"`
+	ReceiptStatus     CustomerReceiptStatus `json:"receipt_status" db:"receipt_status"`
+	Notes             *string               `json:"notes,omitempty" db:"notes"`
+	ReceivedBy        int                   `json:"received_by" db:"received_by"`
+	VoidedBy          *int                  `json:"voided_by,omitempty" db:"voided_by"`
+	VoidedAt          *time.Time            `json:"voided_at,omitempty" db:"voided_at"`
+	CreatedAt         time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time             `json:"updated_at" db:"updated_at"`
+
+	// Related data
+	Allocations []CustomerReceiptAllocation `json:"allocations,omitempty" db:"-"`
+}
+
+// CustomerReceiptAllocation records how much of a customer receipt settles a sales invoice
+type CustomerReceiptAllocation struct {
+	AllocationID    int       `json:"allocation_id" db:"allocation_id"`
+	ReceiptID       int       `json:"receipt_id" db:"receipt_id"`
+	InvoiceID       int       `json:"invoice_id" db:"invoice_id"`
+	InvoiceNumber   string    `json:"invoice_number" db:"invoice_number"`
+	AllocatedAmount float64   `json:"allocated_amount" db:"allocated_amount"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
+
+// CustomerReceiptAllocationRequest represents an amount of a receipt applied to one sales invoice
+type CustomerReceiptAllocationRequest struct {
+	InvoiceID int     `json:"invoice_id" binding:"required,min=1"`
+	Amount    float64 `json:"amount" binding:"required,gt=0"`
+}
+
+// CustomerReceiptCreateRequest represents a request to record money received from a customer.
+// With AutoAllocate set and no allocations given, the receipt settles the customer's open
+// invoices oldest due date first.
+type CustomerReceiptCreateRequest struct {
+	CustomerID       int                                `json:"customer_id" binding:"required,min=1"`
+	ReceiptDate      *time.Time                         `json:"receipt_date,omitempty"`
+	PaymentMethod    PaymentMethod                      `json:"payment_method" binding:"required"`
+	PaymentReference *string                            `json:"payment_reference,omitempty" binding:"omitempty,max=100"`
+	Amount           float64                            `json:"amount" binding:"required,gt=0"`
+	Notes            *string                            `json:"notes,omitempty"`
+	AutoAllocate     bool                               `json:"auto_allocate,omitempty"`
+	Allocations      []CustomerReceiptAllocationRequest `json:"allocations,omitempty" binding:"omitempty,dive"`
+}
+
+// CustomerReceiptAllocateRequest represents a request to allocate the unallocated part of a receipt
+type CustomerReceiptAllocateRequest struct {
+	Allocations []CustomerReceiptAllocationRequest `json:"allocations" binding:"required,min=1,dive"`
+}
+
+// CustomerReceiptFilterParams represents filtering parameters for customer receipt queries
+type CustomerReceiptFilterParams struct {
+	CustomerID    *int                   `json:"customer_id,omitempty" form:"customer_id"`
+	InvoiceID     *int                   `json:"invoice_id,omitempty" form:"invoice_id"`
+	PaymentMethod *PaymentMethod         `json:"payment_method,omitempty" form:"payment_method"`
+	ReceiptStatus *CustomerReceiptStatus `json:"receipt_status,omitempty" form:"receipt_status"`
+	DateFrom      *time.Time             `json:"date_from,omitempty" form:"date_from"`
+	DateTo        *time.Time             `json:"date_to,omitempty" form:"date_to"`
+	Search        string                 `json:"search,omitempty" form:"search"`
+	common.PaginationParams
+}
+
+// IsVoid checks if the receipt has been voided
+func (r *CustomerReceipt) IsVoid() bool {
+	return r.ReceiptStatus == CustomerReceiptStatusVoid
+}
+
+// ValidateAllocations checks allocations against the unallocated amount of the receipt and the
+// outstanding balance of each invoice. Invoices must be keyed by invoice ID.
+func (r *CustomerReceipt) ValidateAllocations(allocations []CustomerReceiptAllocationRequest, invoices map[int]*SalesInvoice) error {
+	if r.IsVoid() {
+		return fmt.Errorf("cannot allocate a void customer receipt")
+	}
+
+	var total float64
+	requested := make(map[int]float64, len(allocations))
+	for _, allocation := range allocations {
+		if allocation.Amount <= 0 {
+			return fmt.Errorf("allocation to invoice %d must have an amount", allocation.InvoiceID)
+		}
+
+		invoice, ok := invoices[allocation.InvoiceID]
+		if !ok {
+			return fmt.Errorf("sales invoice %d not found", allocation.InvoiceID)
+		}
+		if invoice.CustomerID != r.CustomerID {
+			return fmt.Errorf("sales invoice %s does not belong to the receipt customer", invoice.InvoiceNumber)
+		}
+		if invoice.InvoiceStatus != SalesInvoiceStatusIssued {
+			return fmt.Errorf("sales invoice %s is %s", invoice.InvoiceNumber, invoice.InvoiceStatus)
+		}
+
+		requested[allocation.InvoiceID] += allocation.Amount
+		if roundCents(requested[allocation.InvoiceID]) > invoice.OutstandingAmount {
+			return fmt.Errorf("allocation of %.2f exceeds the outstanding %.2f of sales invoice %s",
+				requested[allocation.InvoiceID], invoice.OutstandingAmount, invoice.InvoiceNumber)
+		}
+		total += allocation.Amount
+	}
+
+	unallocated := roundCents(r.Amount - r.AllocatedAmount)
+	if roundCents(total) > unallocated {
+		return fmt.Errorf("allocations of %.2f exceed the unallocated receipt amount of %.2f", total, unallocated)
+	}
+
+	return nil
+}
+
+// AutoAllocate spreads the unallocated amount of the receipt over open invoices, settling the
+// oldest due date first. Invoices of other customers or without an outstanding balance are skipped.
+func (r *CustomerReceipt) AutoAllocate(openInvoices []SalesInvoice) []CustomerReceiptAllocationRequest {
+	invoices := make([]SalesInvoice, 0, len(openInvoices))
+	for _, invoice := range openInvoices {
+		if invoice.CustomerID == r.CustomerID && invoice.InvoiceStatus == SalesInvoiceStatusIssued && invoice.OutstandingAmount > 0 {
+			invoices = append(invoices, invoice)
+		}
+	}
+	sort.SliceStable(invoices, func(i, j int) bool {
+		if !invoices[i].DueDate.Equal(invoices[j].DueDate) {
+			return invoices[i].DueDate.Before(invoices[j].DueDate)
+		}
+		return invoices[i].InvoiceID < invoices[j].InvoiceID
+	})
+
+	allocations := []CustomerReceiptAllocationRequest{}
+	remaining := roundCents(r.Amount - r.AllocatedAmount)
+	for _, invoice := range invoices {
+		if remaining <= 0 {
+			break
+		}
+		amount := invoice.OutstandingAmount
+		if amount > remaining {
+			amount = remaining
+		}
+		allocations = append(allocations, CustomerReceiptAllocationRequest{InvoiceID: invoice.InvoiceID, Amount: amount})
+		remaining = roundCents(remaining - amount)
+	}
+	return allocations
+}
+
+// CheckCreditLimit returns an error when a credit sale of amount would take the open receivables
+// of a customer above their credit limit. Customers without a credit limit cannot buy on credit.
+func CheckCreditLimit(customerCode string, creditLimit, outstanding, amount float64) error {
+	if creditLimit <= 0 {
+		return fmt.Errorf("customer %s has no credit limit, credit sales are not allowed", customerCode)
+	}
+	if roundCents(outstanding+amount) > roundCents(creditLimit) {
+		return fmt.Errorf("credit limit of customer %s exceeded: outstanding %.2f plus %.2f is above the limit of %.2f",
+			customerCode, outstanding, amount, creditLimit)
+	}
+	return nil
+}
+
+// CustomerCreditStatus summarises the receivables of a customer against their credit limit
+type CustomerCreditStatus struct {
+	CustomerID        int                 `json:"customer_id"`
+	CustomerCode      string              `json:"customer_code"`
+	CustomerName      string              `json:"customer_name"`
+	CreditLimit       float64             `json:"credit_limit"`
+	PaymentTermID     *int                `json:"payment_term_id,omitempty"`
+	OutstandingAmount float64             `json:"outstanding_amount"`
+	OverdueAmount     float64             `json:"overdue_amount"`
+	AvailableCredit   float64             `json:"available_credit"`
+	Aging             APAgingBucketTotals `json:"aging"`
+	OpenInvoices      []SalesInvoice      `json:"open_invoices"`
+}
+
+// NewCustomerCreditStatus totals and ages the open invoices of a customer as of a date.
+// Available credit never goes below zero.
+func NewCustomerCreditStatus(customerID int, customerCode, customerName string, creditLimit float64, paymentTermID *int,
+	openInvoices []SalesInvoice, asOf time.Time) *CustomerCreditStatus {
+	status := &CustomerCreditStatus{
+		CustomerID:    customerID,
+		CustomerCode:  customerCode,
+		CustomerName:  customerName,
+		CreditLimit:   creditLimit,
+		PaymentTermID: paymentTermID,
+		OpenInvoices:  []SalesInvoice{},
+	}
+
+	for _, invoice := range openInvoices {
+		if invoice.OutstandingAmount <= 0 {
+			continue
+		}
+		status.OutstandingAmount += invoice.OutstandingAmount
+		if invoice.IsOverdue(asOf) {
+			status.OverdueAmount += invoice.OutstandingAmount
+		}
+		status.Aging.Add(AgingBucketFor(daysBetween(invoice.DueDate, asOf)), invoice.OutstandingAmount)
+		status.OpenInvoices = append(status.OpenInvoices, invoice)
+	}
+
+	status.OutstandingAmount = roundCents(status.OutstandingAmount)
+	status.OverdueAmount = roundCents(status.OverdueAmount)
+	if available := roundCents(creditLimit - status.OutstandingAmount); available > 0 {
+		status.AvailableCredit = available
+	}
+	return status
+}
+
+// Document types of customer statement lines
+const (
+	StatementDocumentInvoice = "invoice"
+	StatementDocumentReceipt = "receipt"
+)
+
+// CustomerStatementParams represents the period of a customer statement of account. The period
+// defaults to the previous calendar month; Format is json or pdf.
+type CustomerStatementParams struct {
+	DateFrom *time.Time `json:"date_from,omitempty" form:"date_from" time_format:"2006-01-02"`
+	DateTo   *time.Time `json:"date_to,omitempty" form:"date_to" time_format:"2006-01-02"`
+	Format   string     `json:"format,omitempty" form:"format"`
+}
+
+// ReportFormatPDF exports a report as a printable PDF document
+const ReportFormatPDF = "pdf"
+
+// Period returns the statement period, defaulting to the calendar month before today
+func (p *CustomerStatementParams) Period(today time.Time) (time.Time, time.Time, error) {
+	switch p.Format {
+	case "", ReportFormatJSON, ReportFormatPDF:
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid report format: %s", p.Format)
+	}
+
+	thisMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+	from, to := thisMonth.AddDate(0, -1, 0), thisMonth.AddDate(0, 0, -1)
+	if p.DateFrom != nil {
+		from = *p.DateFrom
+	}
+	if p.DateTo != nil {
+		to = *p.DateTo
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("date_to cannot be before date_from")
+	}
+	return from, to, nil
+}
+
+// CustomerStatementLine is an invoice or receipt on a customer statement with the running balance after it
+type CustomerStatementLine struct {
+	Date           time.Time  `json:"date"`
+	DocumentType   string     `json:"document_type"`
+	DocumentNumber string     `json:"document_number"`
+	Reference      *string    `json:"reference,omitempty"`
+	DueDate        *time.Time `json:"due_date,omitempty"`
+	Debit          float64    `json:"debit"`
+	Credit         float64    `json:"credit"`
+	Balance        float64    `json:"balance"`
+}
+
+// CustomerStatement is a customer statement of account: the balance brought forward, the invoices
+// and receipts of the period and the aging of what is still open at the end of the period
+type CustomerStatement struct {
+	CustomerID      int                     `json:"customer_id"`
+	CustomerCode    string                  `json:"customer_code"`
+	CustomerName    string                  `json:"customer_name"`
+	CustomerAddress string                  `json:"customer_address"`
+	CreditLimit     float64                 `json:"credit_limit"`
+	PeriodStart     time.Time               `json:"period_start"`
+	PeriodEnd       time.Time               `json:"period_end"`
+	OpeningBalance  float64                 `json:"opening_balance"`
+	TotalInvoiced   float64                 `json:"total_invoiced"`
+	TotalReceived   float64                 `json:"total_received"`
+	ClosingBalance  float64                 `json:"closing_balance"`
+	Lines           []CustomerStatementLine `json:"lines"`
+	Aging           APAgingBucketTotals     `json:"aging"`
+}
+
+// NewCustomerStatement builds a statement from the opening balance, the period's lines and the
+// invoices open at the end of the period. Lines are ordered by date, invoices before receipts.
+func NewCustomerStatement(customerID int, customerCode, customerName, customerAddress string, creditLimit float64,
+	periodStart, periodEnd time.Time, openingBalance float64, lines []CustomerStatementLine, openInvoices []SalesInvoice) *CustomerStatement {
+	statement := &CustomerStatement{
+		CustomerID:      customerID,
+		CustomerCode:    customerCode,
+		CustomerName:    customerName,
+		CustomerAddress: customerAddress,
+		CreditLimit:     creditLimit,
+		PeriodStart:     periodStart,
+		PeriodEnd:       periodEnd,
+		OpeningBalance:  roundCents(openingBalance),
+		Lines:           []CustomerStatementLine{},
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		if !lines[i].Date.Equal(lines[j].Date) {
+			return lines[i].Date.Before(lines[j].Date)
+		}
+		return lines[i].DocumentType == StatementDocumentInvoice && lines[j].DocumentType != StatementDocumentInvoice
+	})
+
+	balance := statement.OpeningBalance
+	for _, line := range lines {
+		balance = roundCents(balance + line.Debit - line.Credit)
+		line.Balance = balance
+		statement.TotalInvoiced += line.Debit
+		statement.TotalReceived += line.Credit
+		statement.Lines = append(statement.Lines, line)
+	}
+	statement.TotalInvoiced = roundCents(statement.TotalInvoiced)
+	statement.TotalReceived = roundCents(statement.TotalReceived)
+	statement.ClosingBalance = balance
+
+	for _, invoice := range openInvoices {
+		if invoice.OutstandingAmount > 0 {
+			statement.Aging.Add(AgingBucketFor(daysBetween(invoice.DueDate, periodEnd)), invoice.OutstandingAmount)
+		}
+	}
+
+	return statement
+}
+
+// Text lays the statement out as fixed-width lines for printing
+func (s *CustomerStatement) Text() []string {
+	const date = "2006-01-02"
+	amount := func(value float64) string {
+		if value == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%.2f", value)
+	}
+	rule := strings.Repeat("-", 94)
+
+	lines := []string{
+		"STATEMENT OF ACCOUNT",
+		"",
+		fmt.Sprintf("Customer : %s - %s", s.CustomerCode, s.CustomerName),
+		fmt.Sprintf("Address  : %s", s.CustomerAddress),
+		fmt.Sprintf("Period   : %s to %s", s.PeriodStart.Format(date), s.PeriodEnd.Format(date)),
+		fmt.Sprintf("Credit limit : %.2f", s.CreditLimit),
+		"",
+		fmt.Sprintf("%-10s  %-8s  %-20s  %-10s  %12s  %12s  %12s", "Date", "Type", "Document", "Due", "Debit", "Credit", "Balance"),
+		rule,
+		fmt.Sprintf("%-10s  %-8s  %-20s  %-10s  %12s  %12s  %12.2f", s.PeriodStart.Format(date), "", "Balance brought forward", "", "", "", s.OpeningBalance),
+	}
+
+	for _, line := range s.Lines {
+		due := ""
+		if line.DueDate != nil {
+			due = line.DueDate.Format(date)
+		}
+		lines = append(lines, fmt.Sprintf("%-10s  %-8s  %-20s  %-10s  %12s  %12s  %12.2f",
+			line.Date.Format(date), line.DocumentType, line.DocumentNumber, due,
+			amount(line.Debit), amount(line.Credit), line.Balance))
+	}
+
+	lines = append(lines,
+		rule,
+		fmt.Sprintf("%-54s  %12.2f  %12.2f  %12.2f", "Totals for the period", s.TotalInvoiced, s.TotalReceived, s.ClosingBalance),
+		"",
+		fmt.Sprintf("Balance due as of %s: %.2f", s.PeriodEnd.Format(date), s.ClosingBalance),
+		"",
+	)
+
+	var header, values string
+	for i, value := range s.Aging.Amounts() {
+		label := "Total"
+		if i < len(AgingBuckets) {
+			label = AgingBuckets[i].Label()
+		}
+		header += fmt.Sprintf("%14s", label)
+		values += fmt.Sprintf("%14.2f", value)
+	}
+	lines = append(lines, "Aging of open invoices (days past due)", header, values)
+
+	return lines
+}
@@ -62,6 +62,8 @@ type GoodsReceipt struct {
 	ReceivedBy            int           `json:"received_by" db:"received_by"`
 	SupplierDeliveryNote  *string       `json:"supplier_delivery_note,omitempty" db:"supplier_delivery_note"`
 	SupplierInvoiceNumber *string       `json:"supplier_invoice_number,omitempty" db:"supplier_invoice_number"`
+	CurrencyCode          string        `json:"currency_code" db:"currency_code"`
+	ExchangeRate          float64       `json:"exchange_rate" db:"exchange_rate"`
 	TotalReceivedValue    float64       `json:"total_received_value" db:"total_received_value"`
 	ReceiptStatus         ReceiptStatus `json:"receipt_status" db:"receipt_status"`
 	ReceiptNotes          *string       `json:"receipt_notes,omitempty" db:"receipt_notes"`
@@ -217,6 +219,12 @@ func (grd *GoodsReceiptDetail) IsFullyAccepted() bool {
 	return grd.QuantityReceived > 0 && grd.QuantityRejected == 0 && grd.ConditionReceived == ConditionGood
 }
 
+// BaseUnitCost converts a unit cost in the purchase order currency to the base currency
+// at the receipt's exchange rate
+func (gr *GoodsReceipt) BaseUnitCost(unitCost float64) float64 {
+	return ToBaseCurrency(unitCost, gr.ExchangeRate)
+}
+
 // UpdateStatus updates the receipt status based on details
 func (gr *GoodsReceipt) UpdateStatus(hasDiscrepancy bool, isComplete bool) {
 	if hasDiscrepancy {
@@ -0,0 +1,237 @@
+package products
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+)
+
+// AllocationMethod represents how a landed cost voucher is spread over receipt lines
+type AllocationMethod string
+
+const (
+	AllocationMethodValue    AllocationMethod = "value"
+	AllocationMethodQuantity AllocationMethod = "quantity"
+	AllocationMethodWeight   AllocationMethod = "weight"
+)
+
+// IsValid checks if the allocation method is valid
+func (m AllocationMethod) IsValid() bool {
+	switch m {
+	case AllocationMethodValue, AllocationMethodQuantity, AllocationMethodWeight:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the allocation method
+func (m AllocationMethod) String() string {
+	return string(m)
+}
+
+// Value implements the driver.Valuer interface for AllocationMethod
+func (m AllocationMethod) Value() (driver.Value, error) {
+	return string(m), nil
+}
+
+// Scan implements the sql.Scanner interface for AllocationMethod
+func (m *AllocationMethod) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*m = AllocationMethod(str)
+	case []byte:
+		*m = AllocationMethod(str)
+	default:
+		return fmt.Errorf("cannot scan %T into AllocationMethod", value)
+	}
+	return nil
+}
+
+// LandedCostStatus represents the status of a landed cost voucher
+type LandedCostStatus string
+
+const (
+	LandedCostStatusDraft     LandedCostStatus = "draft"
+	LandedCostStatusPosted    LandedCostStatus = "posted"
+	LandedCostStatusCancelled LandedCostStatus = "cancelled"
+)
+
+// IsValid checks if the landed cost status is valid
+func (s LandedCostStatus) IsValid() bool {
+	switch s {
+	case LandedCostStatusDraft, LandedCostStatusPosted, LandedCostStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the landed cost status
+func (s LandedCostStatus) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for LandedCostStatus
+func (s LandedCostStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for LandedCostStatus
+func (s *LandedCostStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*s = LandedCostStatus(str)
+	case []byte:
+		*s = LandedCostStatus(str)
+	default:
+		return fmt.Errorf("cannot scan %T into LandedCostStatus", value)
+	}
+	return nil
+}
+
+// LandedCostVoucher represents freight, duty, insurance and handling charges attached to goods receipts
+type LandedCostVoucher struct {
+	VoucherID        int              `json:"voucher_id" db:"voucher_id"`
+	VoucherNumber    string           `json:"voucher_number" db:"voucher_number"`
+	SupplierID       *int             `json:"supplier_id,omitempty" db:"supplier_id"`
+	ReferenceNumber  *string          `json:"reference_number,omitempty" db:"reference_number"`
+	VoucherDate      time.Time        `json:"voucher_date" db:"voucher_date"`
+	AllocationMethod AllocationMethod `json:"allocation_method" db:"allocation_method"`
+	FreightAmount    float64          `json:"freight_amount" db:"freight_amount"`
+	DutyAmount       float64          `json:"duty_amount" db:"duty_amount"`
+	InsuranceAmount  float64          `json:"insurance_amount" db:"insurance_amount"`
+	HandlingAmount   float64          `json:"handling_amount" db:"handling_amount"`
+	TotalAmount      float64          `json:"total_amount" db:"total_amount"`
+	VoucherStatus    LandedCostStatus `json:"voucher_status" db:"voucher_status"`
+	Notes            *string          `json:"notes,omitempty" db:"notes"`
+	CreatedBy        int              `json:"created_by" db:"created_by"`
+	PostedBy         *int             `json:"posted_by,omitempty" db:"posted_by"`
+	PostedAt         *time.Time       `json:"posted_at,omitempty" db:"posted_at"`
+	CreatedAt        time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at" db:"updated_at"`
+
+	// Related data
+	ReceiptIDs []int `json:"receipt_ids,omitempty" db:"-"`
+}
+
+// LandedCostVoucherListItem represents a simplified landed cost voucher for list views
+type LandedCostVoucherListItem struct {
+	VoucherID        int              `json:"voucher_id" db:"voucher_id"`
+	VoucherNumber    string           `json:"voucher_number" db:"voucher_number"`
+	SupplierID       *int             `json:"supplier_id,omitempty" db:"supplier_id"`
+	VoucherDate      time.Time        `json:"voucher_date" db:"voucher_date"`
+	AllocationMethod AllocationMethod `json:"allocation_method" db:"allocation_method"`
+	TotalAmount      float64          `json:"total_amount" db:"total_amount"`
+	VoucherStatus    LandedCostStatus `json:"voucher_status" db:"voucher_status"`
+	ReceiptCount     int              `json:"receipt_count" db:"receipt_count"`
+	CreatedAt        time.Time        `json:"created_at" db:"created_at"`
+}
+
+// LandedCostAllocation represents the share of a voucher assigned to one goods receipt line
+type LandedCostAllocation struct {
+	AllocationID    int     `json:"allocation_id" db:"allocation_id"`
+	VoucherID       int     `json:"voucher_id" db:"voucher_id"`
+	ReceiptID       int     `json:"receipt_id" db:"receipt_id"`
+	ReceiptDetailID int     `json:"receipt_detail_id" db:"receipt_detail_id"`
+	ProductID       int     `json:"product_id" db:"product_id"`
+	Quantity        int     `json:"quantity" db:"quantity"`
+	UnitCost        float64 `json:"unit_cost" db:"unit_cost"`
+	UnitWeight      float64 `json:"unit_weight" db:"unit_weight"`
+	BasisAmount     float64 `json:"basis_amount" db:"basis_amount"`
+	AllocatedAmount float64 `json:"allocated_amount" db:"allocated_amount"`
+	UnitLandedCost  float64 `json:"unit_landed_cost" db:"unit_landed_cost"`
+}
+
+// LandedCostVoucherCreateRequest represents a request to create a landed cost voucher
+type LandedCostVoucherCreateRequest struct {
+	SupplierID       *int             `json:"supplier_id,omitempty" binding:"omitempty,min=1"`
+	ReferenceNumber  *string          `json:"reference_number,omitempty" binding:"omitempty,max=100"`
+	VoucherDate      *time.Time       `json:"voucher_date,omitempty"`
+	AllocationMethod AllocationMethod `json:"allocation_method" binding:"required"`
+	FreightAmount    float64          `json:"freight_amount" binding:"min=0"`
+	DutyAmount       float64          `json:"duty_amount" binding:"min=0"`
+	InsuranceAmount  float64          `json:"insurance_amount" binding:"min=0"`
+	HandlingAmount   float64          `json:"handling_amount" binding:"min=0"`
+	Notes            *string          `json:"notes,omitempty"`
+	ReceiptIDs       []int            `json:"receipt_ids" binding:"required,min=1,dive,min=1"`
+}
+
+// LandedCostVoucherFilterParams represents filtering parameters for landed cost voucher queries
+type LandedCostVoucherFilterParams struct {
+	SupplierID       *int              `json:"supplier_id,omitempty" form:"supplier_id"`
+	ReceiptID        *int              `json:"receipt_id,omitempty" form:"receipt_id"`
+	AllocationMethod *AllocationMethod `json:"allocation_method,omitempty" form:"allocation_method"`
+	VoucherStatus    *LandedCostStatus `json:"voucher_status,omitempty" form:"voucher_status"`
+	DateFrom         *time.Time        `json:"date_from,omitempty" form:"date_from"`
+	DateTo           *time.Time        `json:"date_to,omitempty" form:"date_to"`
+	Search           string            `json:"search,omitempty" form:"search"`
+	common.PaginationParams
+}
+
+// CalculateTotalAmount sums the cost components of the voucher
+func (v *LandedCostVoucher) CalculateTotalAmount() {
+	v.TotalAmount = v.FreightAmount + v.DutyAmount + v.InsuranceAmount + v.HandlingAmount
+}
+
+// CanPost checks if the voucher can be posted
+func (v *LandedCostVoucher) CanPost() bool {
+	return v.VoucherStatus == LandedCostStatusDraft
+}
+
+// CanCancel checks if the voucher can be cancelled
+func (v *LandedCostVoucher) CanCancel() bool {
+	return v.VoucherStatus == LandedCostStatusDraft
+}
+
+// Allocate spreads the voucher total over the given receipt lines using the voucher's allocation method.
+// Amounts are rounded to cents and the rounding difference is assigned to the last line.
+func (v *LandedCostVoucher) Allocate(lines []LandedCostAllocation) error {
+	if len(lines) == 0 {
+		return fmt.Errorf("no receipt lines to allocate landed cost to")
+	}
+
+	totalBasis := 0.0
+	for i := range lines {
+		switch v.AllocationMethod {
+		case AllocationMethodValue:
+			lines[i].BasisAmount = float64(lines[i].Quantity) * lines[i].UnitCost
+		case AllocationMethodQuantity:
+			lines[i].BasisAmount = float64(lines[i].Quantity)
+		case AllocationMethodWeight:
+			lines[i].BasisAmount = float64(lines[i].Quantity) * lines[i].UnitWeight
+		default:
+			return fmt.Errorf("invalid allocation method: %s", v.AllocationMethod)
+		}
+		totalBasis += lines[i].BasisAmount
+	}
+
+	if totalBasis <= 0 {
+		return fmt.Errorf("cannot allocate by %s: receipt lines have no %s", v.AllocationMethod, v.AllocationMethod)
+	}
+
+	remaining := v.TotalAmount
+	for i := range lines {
+		lines[i].VoucherID = v.VoucherID
+		if i == len(lines)-1 {
+			lines[i].AllocatedAmount = math.Round(remaining*100) / 100
+		} else {
+			lines[i].AllocatedAmount = math.Round(v.TotalAmount*lines[i].BasisAmount/totalBasis*100) / 100
+			remaining -= lines[i].AllocatedAmount
+		}
+		if lines[i].Quantity > 0 {
+			lines[i].UnitLandedCost = lines[i].AllocatedAmount / float64(lines[i].Quantity)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,771 @@
+package products
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+)
+
+// GLAccountType represents the classification of a general ledger account
+type GLAccountType string
+
+const (
+	GLAccountTypeAsset     GLAccountType = "asset"
+	GLAccountTypeLiability GLAccountType = "liability"
+	GLAccountTypeEquity    GLAccountType = "equity"
+	GLAccountTypeRevenue   GLAccountType = "revenue"
+	GLAccountTypeExpense   GLAccountType = "expense"
+)
+
+// IsValid checks if the account type is valid
+func (t GLAccountType) IsValid() bool {
+	switch t {
+	case GLAccountTypeAsset, GLAccountTypeLiability, GLAccountTypeEquity, GLAccountTypeRevenue, GLAccountTypeExpense:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the account type
+func (t GLAccountType) String() string {
+	return string(t)
+}
+
+// Value implements the driver.Valuer interface for GLAccountType
+func (t GLAccountType) Value() (driver.Value, error) {
+	return string(t), nil
+}
+
+// Scan implements the sql.Scanner interface for GLAccountType
+func (t *GLAccountType) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*t = GLAccountType(str)
+	case []byte:
+		*t = GLAccountType(str)
+	default:
+		return fmt.Errorf("cannot scan %T into GLAccountType", value)
+	}
+	return nil
+}
+
+// PostingRule names the role an account plays when operational documents are posted
+type PostingRule string
+
+const (
+	PostingRuleInventory           PostingRule = "inventory"
+	PostingRuleGRNI                PostingRule = "goods_received_not_invoiced"
+	PostingRuleAccountsPayable     PostingRule = "accounts_payable"
+	PostingRuleCash                PostingRule = "cash"
+	PostingRuleBank                PostingRule = "bank"
+	PostingRulePurchaseDiscount    PostingRule = "purchase_discount"
+	PostingRuleInventoryAdjustment PostingRule = "inventory_adjustment"
+	PostingRuleAccountsReceivable  PostingRule = "accounts_receivable"
+	PostingRuleSalesRevenue        PostingRule = "sales_revenue"
+	PostingRuleCostOfGoodsSold     PostingRule = "cost_of_goods_sold"
+	PostingRuleVATInput            PostingRule = "vat_input"
+	PostingRuleVATOutput           PostingRule = "vat_output"
+	PostingRuleRealizedFX          PostingRule = "realized_fx_gain_loss"
+	PostingRuleAccruedLandedCost   PostingRule = "accrued_landed_cost"
+	PostingRulePriceVariance       PostingRule = "purchase_price_variance"
+)
+
+// IsValid checks if the posting rule is valid
+func (r PostingRule) IsValid() bool {
+	switch r {
+	case PostingRuleInventory, PostingRuleGRNI, PostingRuleAccountsPayable, PostingRuleCash,
+		PostingRuleBank, PostingRulePurchaseDiscount, PostingRuleInventoryAdjustment,
+		PostingRuleAccountsReceivable, PostingRuleSalesRevenue, PostingRuleCostOfGoodsSold,
+		PostingRuleVATInput, PostingRuleVATOutput, PostingRuleRealizedFX, PostingRuleAccruedLandedCost,
+		PostingRulePriceVariance:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the posting rule
+func (r PostingRule) String() string {
+	return string(r)
+}
+
+// Value implements the driver.Valuer interface for PostingRule
+func (r PostingRule) Value() (driver.Value, error) {
+	return string(r), nil
+}
+
+// Scan implements the sql.Scanner interface for PostingRule
+func (r *PostingRule) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*r = PostingRule(str)
+	case []byte:
+		*r = PostingRule(str)
+	default:
+		return fmt.Errorf("cannot scan %T into PostingRule", value)
+	}
+	return nil
+}
+
+// JournalSourceType identifies the document a journal entry was posted from
+type JournalSourceType string
+
+const (
+	JournalSourceManual            JournalSourceType = "manual"
+	JournalSourceGoodsReceipt      JournalSourceType = "goods_receipt"
+	JournalSourceSupplierInvoice   JournalSourceType = "supplier_invoice"
+	JournalSourceSupplierDebitNote JournalSourceType = "supplier_debit_note"
+	JournalSourcePaymentVoucher    JournalSourceType = "payment_voucher"
+	JournalSourceStockAdjustment   JournalSourceType = "stock_adjustment"
+	JournalSourceSalesInvoice      JournalSourceType = "sales_invoice"
+	JournalSourceCustomerReceipt   JournalSourceType = "customer_receipt"
+	JournalSourceLandedCost        JournalSourceType = "landed_cost"
+)
+
+// IsValid checks if the journal source type is valid
+func (t JournalSourceType) IsValid() bool {
+	switch t {
+	case JournalSourceManual, JournalSourceGoodsReceipt, JournalSourceSupplierInvoice,
+		JournalSourceSupplierDebitNote, JournalSourcePaymentVoucher, JournalSourceStockAdjustment,
+		JournalSourceSalesInvoice, JournalSourceCustomerReceipt, JournalSourceLandedCost:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the journal source type
+func (t JournalSourceType) String() string {
+	return string(t)
+}
+
+// Value implements the driver.Valuer interface for JournalSourceType
+func (t JournalSourceType) Value() (driver.Value, error) {
+	return string(t), nil
+}
+
+// Scan implements the sql.Scanner interface for JournalSourceType
+func (t *JournalSourceType) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*t = JournalSourceType(str)
+	case []byte:
+		*t = JournalSourceType(str)
+	default:
+		return fmt.Errorf("cannot scan %T into JournalSourceType", value)
+	}
+	return nil
+}
+
+// JournalStatus represents the status of a journal entry
+type JournalStatus string
+
+const (
+	JournalStatusPosted   JournalStatus = "posted"
+	JournalStatusReversed JournalStatus = "reversed"
+)
+
+// IsValid checks if the journal status is valid
+func (s JournalStatus) IsValid() bool {
+	switch s {
+	case JournalStatusPosted, JournalStatusReversed:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the journal status
+func (s JournalStatus) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for JournalStatus
+func (s JournalStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for JournalStatus
+func (s *JournalStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*s = JournalStatus(str)
+	case []byte:
+		*s = JournalStatus(str)
+	default:
+		return fmt.Errorf("cannot scan %T into JournalStatus", value)
+	}
+	return nil
+}
+
+// AccountingPeriodStatus represents whether postings are accepted for a period
+type AccountingPeriodStatus string
+
+const (
+	AccountingPeriodStatusOpen   AccountingPeriodStatus = "open"
+	AccountingPeriodStatusClosed AccountingPeriodStatus = "closed"
+)
+
+// IsValid checks if the accounting period status is valid
+func (s AccountingPeriodStatus) IsValid() bool {
+	switch s {
+	case AccountingPeriodStatusOpen, AccountingPeriodStatusClosed:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the accounting period status
+func (s AccountingPeriodStatus) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for AccountingPeriodStatus
+func (s AccountingPeriodStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for AccountingPeriodStatus
+func (s *AccountingPeriodStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*s = AccountingPeriodStatus(str)
+	case []byte:
+		*s = AccountingPeriodStatus(str)
+	default:
+		return fmt.Errorf("cannot scan %T into AccountingPeriodStatus", value)
+	}
+	return nil
+}
+
+// GLAccount represents an account in the chart of accounts
+type GLAccount struct {
+	AccountID   int           `json:"account_id" db:"account_id"`
+	AccountCode string        `json:"account_code" db:"account_code"`
+	AccountName string        `json:"account_name" db:"account_name"`
+	AccountType GLAccountType `json:"account_type" db:"account_type"`
+	Description *string       `json:"description,omitempty" db:"description"`
+	IsActive    bool          `json:"is_active" db:"is_active"`
+	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// GLAccountCreateRequest represents a request to add an account to the chart of accounts
+type GLAccountCreateRequest struct {
+	AccountCode string        `json:"account_code" binding:"required,max=20"`
+	AccountName string        `json:"account_name" binding:"required,max=100"`
+	AccountType GLAccountType `json:"account_type" binding:"required"`
+	Description *string       `json:"description,omitempty"`
+}
+
+// GLAccountUpdateRequest represents a request to update an account. The type cannot change
+// once an account exists, since it decides the side its balance is reported on.
+type GLAccountUpdateRequest struct {
+	AccountName *string `json:"account_name,omitempty" binding:"omitempty,max=100"`
+	Description *string `json:"description,omitempty"`
+	IsActive    *bool   `json:"is_active,omitempty"`
+}
+
+// GLAccountFilterParams represents filtering parameters for chart of accounts queries
+type GLAccountFilterParams struct {
+	AccountType *GLAccountType `json:"account_type,omitempty" form:"account_type"`
+	IsActive    *bool          `json:"is_active,omitempty" form:"is_active"`
+	Search      string         `json:"search,omitempty" form:"search"`
+	common.PaginationParams
+}
+
+// GLPostingRule maps a posting rule to the account it books to
+type GLPostingRule struct {
+	RuleKey     PostingRule `json:"rule_key" db:"rule_key"`
+	AccountID   int         `json:"account_id" db:"account_id"`
+	AccountCode string      `json:"account_code" db:"account_code"`
+	AccountName string      `json:"account_name" db:"account_name"`
+	UpdatedAt   time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// GLPostingRuleUpdateRequest represents a request to point a posting rule at another account
+type GLPostingRuleUpdateRequest struct {
+	AccountID int `json:"account_id" binding:"required,min=1"`
+}
+
+// JournalEntry is a balanced set of debits and credits posted to the general ledger
+type JournalEntry struct {
+	JournalID     int               `json:"journal_id" db:"journal_id"`
+	JournalNumber string            `json:"journal_number" db:"journal_number"`
+	EntryDate     time.Time         `json:"entry_date" db:"entry_date"`
+	SourceType    JournalSourceType `json:"source_type" db:"source_type"`
+	SourceID      *int              `json:"source_id,omitempty" db:"source_id"`
+	SourceNumber  *string           `json:"source_number,omitempty" db:"source_number"`
+	Description   string            `json:"description" db:"description"`
+	JournalStatus JournalStatus     `json:"journal_status" db:"journal_status"`
+	ReversalOfID  *int              `json:"reversal_of_id,omitempty" db:"reversal_of_id"`
+	TotalDebit    float64           `json:"total_debit" db:"total_debit"`
+	TotalCredit   float64           `json:"total_credit" db:"total_credit"`
+	PostedBy      *int              `json:"posted_by,omitempty" db:"posted_by"`
+	CreatedAt     time.Time         `json:"created_at" db:"created_at"`
+
+	// Related data
+	Lines []JournalLine `json:"lines,omitempty" db:"-"`
+}
+
+// JournalLine is one debit or credit of a journal entry. Lines built from operational documents
+// name a posting rule that is resolved to its account when the entry is posted.
+type JournalLine struct {
+	LineID      int          `json:"line_id" db:"line_id"`
+	JournalID   int          `json:"journal_id" db:"journal_id"`
+	AccountID   int          `json:"account_id" db:"account_id"`
+	AccountCode string       `json:"account_code,omitempty" db:"account_code"`
+	AccountName string       `json:"account_name,omitempty" db:"account_name"`
+	Rule        *PostingRule `json:"-" db:"-"`
+	Debit       float64      `json:"debit" db:"debit"`
+	Credit      float64      `json:"credit" db:"credit"`
+	Memo        *string      `json:"memo,omitempty" db:"memo"`
+}
+
+// JournalEntryCreateRequest represents a request to post a manual journal entry
+type JournalEntryCreateRequest struct {
+	EntryDate   *time.Time                 `json:"entry_date,omitempty"`
+	Description string                     `json:"description" binding:"required,max=255"`
+	Lines       []JournalLineCreateRequest `json:"lines" binding:"required,min=2,dive"`
+}
+
+// JournalLineCreateRequest represents one line of a manual journal entry
+type JournalLineCreateRequest struct {
+	AccountID int     `json:"account_id" binding:"required,min=1"`
+	Debit     float64 `json:"debit" binding:"min=0"`
+	Credit    float64 `json:"credit" binding:"min=0"`
+	Memo      *string `json:"memo,omitempty"`
+}
+
+// JournalReverseRequest represents a request to reverse a posted journal entry
+type JournalReverseRequest struct {
+	EntryDate *time.Time `json:"entry_date,omitempty"`
+}
+
+// JournalEntryFilterParams represents filtering parameters for journal entry queries
+type JournalEntryFilterParams struct {
+	SourceType    *JournalSourceType `json:"source_type,omitempty" form:"source_type"`
+	SourceID      *int               `json:"source_id,omitempty" form:"source_id"`
+	AccountID     *int               `json:"account_id,omitempty" form:"account_id"`
+	JournalStatus *JournalStatus     `json:"journal_status,omitempty" form:"journal_status"`
+	DateFrom      *time.Time         `json:"date_from,omitempty" form:"date_from" time_format:"2006-01-02"`
+	DateTo        *time.Time         `json:"date_to,omitempty" form:"date_to" time_format:"2006-01-02"`
+	common.PaginationParams
+}
+
+// AccountingPeriod is a calendar month of the general ledger
+type AccountingPeriod struct {
+	PeriodStart  time.Time              `json:"period_start" db:"period_start"`
+	PeriodEnd    time.Time              `json:"period_end" db:"period_end"`
+	PeriodStatus AccountingPeriodStatus `json:"period_status" db:"period_status"`
+	ClosedBy     *int                   `json:"closed_by,omitempty" db:"closed_by"`
+	ClosedAt     *time.Time             `json:"closed_at,omitempty" db:"closed_at"`
+}
+
+// AccountingPeriodRequest identifies a month to close, reopen or report on
+type AccountingPeriodRequest struct {
+	Year  int `json:"year" form:"year" binding:"required,min=2000,max=2100"`
+	Month int `json:"month" form:"month" binding:"required,min=1,max=12"`
+}
+
+// Start returns the first day of the requested month
+func (r *AccountingPeriodRequest) Start() time.Time {
+	return time.Date(r.Year, time.Month(r.Month), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// PeriodStart returns the first day of the month containing date
+func PeriodStart(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// PeriodEnd returns the last day of the month starting at periodStart
+func PeriodEnd(periodStart time.Time) time.Time {
+	return periodStart.AddDate(0, 1, -1)
+}
+
+// TrialBalanceParams selects the entry dates included in a trial balance
+type TrialBalanceParams struct {
+	DateFrom *time.Time `json:"date_from,omitempty" form:"date_from" time_format:"2006-01-02"`
+	DateTo   *time.Time `json:"date_to,omitempty" form:"date_to" time_format:"2006-01-02"`
+}
+
+// TrialBalanceRow holds the movements and closing balance of one account
+type TrialBalanceRow struct {
+	AccountID     int           `json:"account_id"`
+	AccountCode   string        `json:"account_code"`
+	AccountName   string        `json:"account_name"`
+	AccountType   GLAccountType `json:"account_type"`
+	Debit         float64       `json:"debit"`
+	Credit        float64       `json:"credit"`
+	BalanceDebit  float64       `json:"balance_debit"`
+	BalanceCredit float64       `json:"balance_credit"`
+}
+
+// TrialBalance lists account balances whose debit and credit totals must agree
+type TrialBalance struct {
+	DateFrom           *time.Time        `json:"date_from,omitempty"`
+	DateTo             *time.Time        `json:"date_to,omitempty"`
+	Rows               []TrialBalanceRow `json:"rows"`
+	TotalDebit         float64           `json:"total_debit"`
+	TotalCredit        float64           `json:"total_credit"`
+	TotalBalanceDebit  float64           `json:"total_balance_debit"`
+	TotalBalanceCredit float64           `json:"total_balance_credit"`
+	IsBalanced         bool              `json:"is_balanced"`
+}
+
+// NewTrialBalance derives each account's closing balance side from its movements, orders the rows
+// by account code and totals them
+func NewTrialBalance(params *TrialBalanceParams, rows []TrialBalanceRow) *TrialBalance {
+	tb := &TrialBalance{
+		DateFrom: params.DateFrom,
+		DateTo:   params.DateTo,
+		Rows:     []TrialBalanceRow{},
+	}
+
+	for _, row := range rows {
+		net := roundCents(row.Debit - row.Credit)
+		switch {
+		case net > 0:
+			row.BalanceDebit = net
+		case net < 0:
+			row.BalanceCredit = -net
+		}
+		tb.TotalDebit += row.Debit
+		tb.TotalCredit += row.Credit
+		tb.TotalBalanceDebit += row.BalanceDebit
+		tb.TotalBalanceCredit += row.BalanceCredit
+		tb.Rows = append(tb.Rows, row)
+	}
+
+	sort.Slice(tb.Rows, func(i, j int) bool { return tb.Rows[i].AccountCode < tb.Rows[j].AccountCode })
+	tb.TotalDebit = roundCents(tb.TotalDebit)
+	tb.TotalCredit = roundCents(tb.TotalCredit)
+	tb.TotalBalanceDebit = roundCents(tb.TotalBalanceDebit)
+	tb.TotalBalanceCredit = roundCents(tb.TotalBalanceCredit)
+	tb.IsBalanced = tb.TotalDebit == tb.TotalCredit && tb.TotalBalanceDebit == tb.TotalBalanceCredit
+	return tb
+}
+
+// Totals returns the sum of the debits and credits of the entry
+func (e *JournalEntry) Totals() (float64, float64) {
+	var debit, credit float64
+	for _, line := range e.Lines {
+		debit += line.Debit
+		credit += line.Credit
+	}
+	return roundCents(debit), roundCents(credit)
+}
+
+// Validate checks that every line books a positive amount to one side and that the entry balances
+func (e *JournalEntry) Validate() error {
+	if len(e.Lines) < 2 {
+		return fmt.Errorf("journal entry needs at least two lines")
+	}
+
+	for i, line := range e.Lines {
+		if line.Debit < 0 || line.Credit < 0 {
+			return fmt.Errorf("line %d: amounts cannot be negative", i+1)
+		}
+		if (line.Debit > 0) == (line.Credit > 0) {
+			return fmt.Errorf("line %d: enter either a debit or a credit", i+1)
+		}
+		if line.AccountID == 0 && line.Rule == nil {
+			return fmt.Errorf("line %d: account is required", i+1)
+		}
+	}
+
+	debit, credit := e.Totals()
+	if debit != credit {
+		return fmt.Errorf("journal entry is not balanced: debit %.2f, credit %.2f", debit, credit)
+	}
+
+	return nil
+}
+
+// CanReverse checks if the entry is posted and is not itself a reversal
+func (e *JournalEntry) CanReverse() bool {
+	return e.JournalStatus == JournalStatusPosted && e.ReversalOfID == nil
+}
+
+// Reversal builds the entry that cancels this one by swapping debits and credits
+func (e *JournalEntry) Reversal(entryDate time.Time, postedBy *int) *JournalEntry {
+	reversal := &JournalEntry{
+		EntryDate:     entryDate,
+		SourceType:    e.SourceType,
+		SourceID:      e.SourceID,
+		SourceNumber:  e.SourceNumber,
+		Description:   "Reversal of " + e.JournalNumber + ": " + e.Description,
+		JournalStatus: JournalStatusPosted,
+		ReversalOfID:  &e.JournalID,
+		PostedBy:      postedBy,
+	}
+	for _, line := range e.Lines {
+		reversal.Lines = append(reversal.Lines, JournalLine{
+			AccountID: line.AccountID,
+			Debit:     line.Credit,
+			Credit:    line.Debit,
+			Memo:      line.Memo,
+		})
+	}
+	return reversal
+}
+
+// NewManualJournalEntry builds a journal entry from a manual posting request
+func NewManualJournalEntry(req *JournalEntryCreateRequest, postedBy int) *JournalEntry {
+	entryDate := time.Now()
+	if req.EntryDate != nil {
+		entryDate = *req.EntryDate
+	}
+
+	entry := &JournalEntry{
+		EntryDate:     entryDate,
+		SourceType:    JournalSourceManual,
+		Description:   req.Description,
+		JournalStatus: JournalStatusPosted,
+		PostedBy:      &postedBy,
+	}
+	for _, line := range req.Lines {
+		entry.Lines = append(entry.Lines, JournalLine{
+			AccountID: line.AccountID,
+			Debit:     roundCents(line.Debit),
+			Credit:    roundCents(line.Credit),
+			Memo:      line.Memo,
+		})
+	}
+	return entry
+}
+
+// GoodsReceiptJournal books the accepted goods of a processed receipt into inventory against
+// goods received not invoiced, at the receipt's exchange rate. Receipts without accepted value post nothing.
+func GoodsReceiptJournal(receipt *GoodsReceipt, details []GoodsReceiptDetail, postedBy int) *JournalEntry {
+	var value float64
+	for _, detail := range details {
+		value += float64(detail.QuantityAccepted) * receipt.BaseUnitCost(detail.UnitCost)
+	}
+
+	return documentJournal(JournalSourceGoodsReceipt, receipt.ReceiptID, receipt.ReceiptNumber, receipt.ReceiptDate,
+		"Goods receipt "+receipt.ReceiptNumber, &postedBy,
+		ruleLine(PostingRuleInventory, value, 0),
+		ruleLine(PostingRuleGRNI, 0, value),
+	)
+}
+
+// LandedCostJournal capitalizes a posted landed cost voucher into inventory against accrued landed
+// costs, which are cleared when the freight, duty and insurance bills are paid
+func LandedCostJournal(voucher *LandedCostVoucher, postedBy int) *JournalEntry {
+	return documentJournal(JournalSourceLandedCost, voucher.VoucherID, voucher.VoucherNumber, voucher.VoucherDate,
+		"Landed cost voucher "+voucher.VoucherNumber, &postedBy,
+		ruleLine(PostingRuleInventory, voucher.TotalAmount, 0),
+		ruleLine(PostingRuleAccruedLandedCost, 0, voucher.TotalAmount),
+	)
+}
+
+// SupplierInvoiceJournal books a supplier invoice to accounts payable and the tax to input VAT. Goods received
+// not invoiced is cleared at receiptValue, the base currency value the matched receipts booked, and the difference
+// to the amount net of tax is a purchase price variance. Without matched receipts the net amount clears it.
+// Invoice amounts are converted at the invoice's exchange rate.
+func SupplierInvoiceJournal(invoice *SupplierInvoice, receiptValue *float64, postedBy *int) *JournalEntry {
+	amount := ToBaseCurrency(invoice.InvoiceAmount, invoice.ExchangeRate)
+	tax := ToBaseCurrency(invoice.TaxAmount, invoice.ExchangeRate)
+
+	cleared := amount - tax
+	if receiptValue != nil {
+		cleared = roundCents(*receiptValue)
+	}
+
+	return documentJournal(JournalSourceSupplierInvoice, invoice.InvoiceID, invoice.InvoiceNumber, invoice.InvoiceDate,
+		"Supplier invoice "+invoice.InvoiceNumber, postedBy,
+		ruleLine(PostingRuleGRNI, cleared, 0),
+		signedRuleLine(PostingRulePriceVariance, amount-tax-cleared),
+		ruleLine(PostingRuleVATInput, tax, 0),
+		ruleLine(PostingRuleAccountsPayable, 0, amount),
+	)
+}
+
+// SupplierDebitNoteJournal books goods returned to a supplier as a reduction of accounts payable.
+// Returns from stock credit inventory at stockValue, the cost the goods were carried at, and the
+// difference to the debit note amount is a purchase price variance; without a stock value the debit
+// note amount is credited. Rejected receipt lines never entered inventory, so they clear goods
+// received not invoiced instead.
+func SupplierDebitNoteJournal(debitNote *SupplierDebitNote, source ReturnSource, stockValue *float64) *JournalEntry {
+	amount := ToBaseCurrency(debitNote.DebitAmount, debitNote.ExchangeRate)
+
+	credit, credited := PostingRuleInventory, amount
+	if source == ReturnSourceReceipt {
+		credit = PostingRuleGRNI
+	} else if stockValue != nil {
+		credited = roundCents(*stockValue)
+	}
+
+	return documentJournal(JournalSourceSupplierDebitNote, debitNote.DebitNoteID, debitNote.DebitNoteNumber, debitNote.DebitNoteDate,
+		"Supplier debit note "+debitNote.DebitNoteNumber, &debitNote.CreatedBy,
+		ruleLine(PostingRuleAccountsPayable, amount, 0),
+		ruleLine(credit, 0, credited),
+		signedRuleLine(PostingRulePriceVariance, credited-amount),
+	)
+}
+
+// DebitNoteApplicationJournal books the realized exchange difference of crediting a foreign currency
+// debit note against invoices booked at other rates. Differences of zero post nothing.
+func DebitNoteApplicationJournal(debitNote *SupplierDebitNote, difference float64, entryDate time.Time, appliedBy int) *JournalEntry {
+	return documentJournal(JournalSourceSupplierDebitNote, debitNote.DebitNoteID, debitNote.DebitNoteNumber, entryDate,
+		"Exchange difference on "+debitNote.DebitNoteNumber, &appliedBy,
+		signedRuleLine(PostingRuleAccountsPayable, difference),
+		signedRuleLine(PostingRuleRealizedFX, -difference),
+	)
+}
+
+// PaymentVoucherJournal books a supplier payment out of cash or bank against accounts payable. Early payment
+// discounts taken by the allocations settle payables without cash and are booked as purchase discounts.
+// Allocated amounts clear payables at the rate their invoices were booked at; the difference to the
+// payment rate is the realized exchange gain or loss.
+func PaymentVoucherJournal(voucher *PaymentVoucher, allocations []PaymentAllocationRequest) *JournalEntry {
+	paid := ToBaseCurrency(voucher.Amount, voucher.ExchangeRate)
+	discount, difference := allocationSettlement(allocations, voucher.ExchangeRate)
+	cashRule := PostingRuleBank
+	if voucher.PaymentMethod == PaymentMethodCash {
+		cashRule = PostingRuleCash
+	}
+
+	return documentJournal(JournalSourcePaymentVoucher, voucher.VoucherID, voucher.VoucherNumber, voucher.PaymentDate,
+		"Supplier payment "+voucher.VoucherNumber, &voucher.ProcessedBy,
+		signedRuleLine(PostingRuleAccountsPayable, paid+discount+difference),
+		ruleLine(cashRule, 0, paid),
+		ruleLine(PostingRulePurchaseDiscount, 0, discount),
+		signedRuleLine(PostingRuleRealizedFX, -difference),
+	)
+}
+
+// PaymentAllocationJournal books the early payment discounts taken and the exchange differences realized
+// when an existing voucher is allocated later
+func PaymentAllocationJournal(voucher *PaymentVoucher, allocations []PaymentAllocationRequest, entryDate time.Time) *JournalEntry {
+	discount, difference := allocationSettlement(allocations, voucher.ExchangeRate)
+	return documentJournal(JournalSourcePaymentVoucher, voucher.VoucherID, voucher.VoucherNumber, entryDate,
+		"Allocation of "+voucher.VoucherNumber, &voucher.ProcessedBy,
+		signedRuleLine(PostingRuleAccountsPayable, discount+difference),
+		ruleLine(PostingRulePurchaseDiscount, 0, discount),
+		signedRuleLine(PostingRuleRealizedFX, -difference),
+	)
+}
+
+// StockAdjustmentJournal books the cost impact of an approved adjustment. Shrinkage is expensed out
+// of inventory; a count above the system quantity credits the same adjustment account.
+func StockAdjustmentJournal(adjustment *StockAdjustment, entryDate time.Time, approvedBy int) *JournalEntry {
+	number := fmt.Sprintf("ADJ-%d", adjustment.AdjustmentID)
+	description := fmt.Sprintf("Stock adjustment %d (%s)", adjustment.AdjustmentID, adjustment.AdjustmentType)
+
+	impact := adjustment.CostImpact
+	if impact < 0 {
+		return documentJournal(JournalSourceStockAdjustment, adjustment.AdjustmentID, number, entryDate, description, &approvedBy,
+			ruleLine(PostingRuleInventoryAdjustment, -impact, 0),
+			ruleLine(PostingRuleInventory, 0, -impact),
+		)
+	}
+	return documentJournal(JournalSourceStockAdjustment, adjustment.AdjustmentID, number, entryDate, description, &approvedBy,
+		ruleLine(PostingRuleInventory, impact, 0),
+		ruleLine(PostingRuleInventoryAdjustment, 0, impact),
+	)
+}
+
+// SalesInvoiceJournal books a sales invoice to accounts receivable against sales revenue and output VAT,
+// and moves the cost of the goods sold out of inventory
+func SalesInvoiceJournal(invoice *SalesInvoice) *JournalEntry {
+	cost := invoice.CostOfSales()
+
+	return documentJournal(JournalSourceSalesInvoice, invoice.InvoiceID, invoice.InvoiceNumber, invoice.InvoiceDate,
+		"Sales invoice "+invoice.InvoiceNumber, &invoice.CreatedBy,
+		ruleLine(PostingRuleAccountsReceivable, invoice.TotalAmount, 0),
+		ruleLine(PostingRuleSalesRevenue, 0, invoice.Subtotal),
+		ruleLine(PostingRuleVATOutput, 0, invoice.TaxAmount),
+		ruleLine(PostingRuleCostOfGoodsSold, cost, 0),
+		ruleLine(PostingRuleInventory, 0, cost),
+	)
+}
+
+// CustomerReceiptJournal books money received from a customer into cash or bank against accounts receivable
+func CustomerReceiptJournal(receipt *CustomerReceipt) *JournalEntry {
+	cashRule := PostingRuleBank
+	if receipt.PaymentMethod == PaymentMethodCash {
+		cashRule = PostingRuleCash
+	}
+
+	return documentJournal(JournalSourceCustomerReceipt, receipt.ReceiptID, receipt.ReceiptNumber, receipt.ReceiptDate,
+		"Customer receipt "+receipt.ReceiptNumber, &receipt.ReceivedBy,
+		ruleLine(cashRule, receipt.Amount, 0),
+		ruleLine(PostingRuleAccountsReceivable, 0, receipt.Amount),
+	)
+}
+
+// documentJournal builds the entry of an operational document, dropping zero lines. Documents without
+// financial effect return nil so callers can skip posting.
+func documentJournal(sourceType JournalSourceType, sourceID int, sourceNumber string, entryDate time.Time, description string, postedBy *int, lines ...JournalLine) *JournalEntry {
+	entry := &JournalEntry{
+		EntryDate:     entryDate,
+		SourceType:    sourceType,
+		SourceID:      &sourceID,
+		SourceNumber:  &sourceNumber,
+		Description:   description,
+		JournalStatus: JournalStatusPosted,
+		PostedBy:      postedBy,
+	}
+	for _, line := range lines {
+		line.Debit = roundCents(line.Debit)
+		line.Credit = roundCents(line.Credit)
+		if line.Debit != 0 || line.Credit != 0 {
+			entry.Lines = append(entry.Lines, line)
+		}
+	}
+
+	if len(entry.Lines) == 0 {
+		return nil
+	}
+	return entry
+}
+
+func ruleLine(rule PostingRule, debit, credit float64) JournalLine {
+	return JournalLine{Rule: &rule, Debit: debit, Credit: credit}
+}
+
+// signedRuleLine debits a positive amount and credits a negative one
+func signedRuleLine(rule PostingRule, amount float64) JournalLine {
+	if amount < 0 {
+		return ruleLine(rule, 0, -amount)
+	}
+	return ruleLine(rule, amount, 0)
+}
+
+// allocationSettlement returns the base currency value of the discounts taken by the allocations,
+// at the rates their invoices were booked at, and the net exchange difference realized by paying at paymentRate
+func allocationSettlement(allocations []PaymentAllocationRequest, paymentRate float64) (float64, float64) {
+	var discount, difference float64
+	for _, allocation := range allocations {
+		discount += ToBaseCurrency(allocation.DiscountAmount, allocation.InvoiceRate)
+		difference += allocation.ExchangeDifference(paymentRate)
+	}
+	return roundCents(discount), roundCents(difference)
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,439 @@
+package products
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+)
+
+// PaymentRunStatus represents the status of a supplier payment run
+type PaymentRunStatus string
+
+const (
+	PaymentRunStatusDraft     PaymentRunStatus = "draft"
+	PaymentRunStatusApproved  PaymentRunStatus = "approved"
+	PaymentRunStatusExecuted  PaymentRunStatus = "executed"
+	PaymentRunStatusCancelled PaymentRunStatus = "cancelled"
+)
+
+// IsValid checks if the payment run status is valid
+func (s PaymentRunStatus) IsValid() bool {
+	switch s {
+	case PaymentRunStatusDraft, PaymentRunStatusApproved, PaymentRunStatusExecuted, PaymentRunStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the payment run status
+func (s PaymentRunStatus) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for PaymentRunStatus
+func (s PaymentRunStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for PaymentRunStatus
+func (s *PaymentRunStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*s = PaymentRunStatus(str)
+	case []byte:
+		*s = PaymentRunStatus(str)
+	default:
+		return fmt.Errorf("cannot scan %T into PaymentRunStatus", value)
+	}
+	return nil
+}
+
+// PaymentRunLineStatus represents the status of one transfer in a payment run
+type PaymentRunLineStatus string
+
+const (
+	PaymentRunLineStatusPending PaymentRunLineStatus = "pending"
+	PaymentRunLineStatusPaid    PaymentRunLineStatus = "paid"
+	PaymentRunLineStatusFailed  PaymentRunLineStatus = "failed"
+)
+
+// IsValid checks if the payment run line status is valid
+func (s PaymentRunLineStatus) IsValid() bool {
+	switch s {
+	case PaymentRunLineStatusPending, PaymentRunLineStatusPaid, PaymentRunLineStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the payment run line status
+func (s PaymentRunLineStatus) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for PaymentRunLineStatus
+func (s PaymentRunLineStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for PaymentRunLineStatus
+func (s *PaymentRunLineStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*s = PaymentRunLineStatus(str)
+	case []byte:
+		*s = PaymentRunLineStatus(str)
+	default:
+		return fmt.Errorf("cannot scan %T into PaymentRunLineStatus", value)
+	}
+	return nil
+}
+
+// PaymentRunPriority decides which due invoices are paid first when a run has a budget
+type PaymentRunPriority string
+
+const (
+	// PaymentRunPriorityDueDate pays the invoices that fell due first
+	PaymentRunPriorityDueDate PaymentRunPriority = "due_date"
+	// PaymentRunPriorityDiscount pays invoices whose early payment discount expires soonest first
+	PaymentRunPriorityDiscount PaymentRunPriority = "discount"
+	// PaymentRunPriorityAmount pays the largest outstanding amounts first
+	PaymentRunPriorityAmount PaymentRunPriority = "amount"
+)
+
+// IsValid checks if the payment run priority is valid
+func (p PaymentRunPriority) IsValid() bool {
+	switch p {
+	case PaymentRunPriorityDueDate, PaymentRunPriorityDiscount, PaymentRunPriorityAmount:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the payment run priority
+func (p PaymentRunPriority) String() string {
+	return string(p)
+}
+
+// Value implements the driver.Valuer interface for PaymentRunPriority
+func (p PaymentRunPriority) Value() (driver.Value, error) {
+	return string(p), nil
+}
+
+// Scan implements the sql.Scanner interface for PaymentRunPriority
+func (p *PaymentRunPriority) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*p = PaymentRunPriority(str)
+	case []byte:
+		*p = PaymentRunPriority(str)
+	default:
+		return fmt.Errorf("cannot scan %T into PaymentRunPriority", value)
+	}
+	return nil
+}
+
+// Bank file formats of a payment run
+const (
+	BankFileFormatCSV        = "csv"
+	BankFileFormatFixedWidth = "fixed_width"
+)
+
+// PaymentRun is a batch of supplier payments sent to the bank as one bulk transfer file.
+// Each line pays one invoice and becomes its own transfer and payment voucher when the run is executed.
+type PaymentRun struct {
+	RunID         int                `json:"run_id" db:"run_id"`
+	RunNumber     string             `json:"run_number" db:"run_number"`
+	PaymentDate   time.Time          `json:"payment_date" db:"payment_date"`
+	DueBefore     time.Time          `json:"due_before" db:"due_before"`
+	SupplierID    *int               `json:"supplier_id,omitempty" db:"supplier_id"`
+	Priority      PaymentRunPriority `json:"priority" db:"priority"`
+	MaxAmount     *float64           `json:"max_amount,omitempty" db:"max_amount"`
+	RunStatus     PaymentRunStatus   `json:"run_status" db:"run_status"`
+	LineCount     int                `json:"line_count" db:"line_count"`
+	TotalAmount   float64            `json:"total_amount" db:"total_amount"`
+	TotalDiscount float64            `json:"total_discount" db:"total_discount"`
+	Notes         *string            `json:"notes,omitempty" db:"notes"`
+	CreatedBy     int                `json:"created_by" db:"created_by"`
+	ApprovedBy    *int               `json:"approved_by,omitempty" db:"approved_by"`
+	ApprovedAt    *time.Time         `json:"approved_at,omitempty" db:"approved_at"`
+	ExecutedBy    *int               `json:"executed_by,omitempty" db:"executed_by"`
+	ExecutedAt    *time.Time         `json:"executed_at,omitempty" db:"executed_at"`
+	CreatedAt     time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at" db:"updated_at"`
+
+	// Related data
+	Lines []PaymentRunLine `json:"lines,omitempty" db:"-"`
+}
+
+// PaymentRunLine is one invoice paid by a payment run
+type PaymentRunLine struct {
+	LineID         int                  `json:"line_id" db:"line_id"`
+	RunID          int                  `json:"run_id" db:"run_id"`
+	InvoiceID      int                  `json:"invoice_id" db:"invoice_id"`
+	SupplierID     int                  `json:"supplier_id" db:"supplier_id"`
+	Amount         float64              `json:"amount" db:"amount"`
+	DiscountAmount float64              `json:"discount_amount" db:"discount_amount"`
+	BankAccount    *string              `json:"bank_account,omitempty" db:"bank_account"`
+	LineStatus     PaymentRunLineStatus `json:"line_status" db:"line_status"`
+	VoucherID      *int                 `json:"voucher_id,omitempty" db:"voucher_id"`
+	FailureReason  *string              `json:"failure_reason,omitempty" db:"failure_reason"`
+	CreatedAt      time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at" db:"updated_at"`
+
+	// Related data
+	InvoiceNumber string    `json:"invoice_number,omitempty" db:"invoice_number"`
+	DueDate       time.Time `json:"due_date" db:"due_date"`
+	SupplierCode  string    `json:"supplier_code,omitempty" db:"supplier_code"`
+	SupplierName  string    `json:"supplier_name,omitempty" db:"supplier_name"`
+	VoucherNumber *string   `json:"voucher_number,omitempty" db:"voucher_number"`
+}
+
+// PaymentRunListItem represents a simplified payment run for list views
+type PaymentRunListItem struct {
+	RunID         int              `json:"run_id" db:"run_id"`
+	RunNumber     string           `json:"run_number" db:"run_number"`
+	PaymentDate   time.Time        `json:"payment_date" db:"payment_date"`
+	RunStatus     PaymentRunStatus `json:"run_status" db:"run_status"`
+	LineCount     int              `json:"line_count" db:"line_count"`
+	TotalAmount   float64          `json:"total_amount" db:"total_amount"`
+	TotalDiscount float64          `json:"total_discount" db:"total_discount"`
+	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
+}
+
+// PaymentRunCandidate is an open invoice that a payment run can pay, with the supplier bank details
+type PaymentRunCandidate struct {
+	Invoice      SupplierInvoice
+	SupplierCode string
+	SupplierName string
+	BankAccount  *string
+}
+
+// PaymentRunCreateRequest selects the invoices due by DueBefore into a new draft payment run.
+// With MaxAmount set, invoices are taken in priority order until the budget is used up.
+type PaymentRunCreateRequest struct {
+	PaymentDate   *time.Time         `json:"payment_date,omitempty"`
+	DueBefore     *time.Time         `json:"due_before,omitempty"`
+	SupplierID    *int               `json:"supplier_id,omitempty" binding:"omitempty,min=1"`
+	Priority      PaymentRunPriority `json:"priority,omitempty"`
+	MaxAmount     *float64           `json:"max_amount,omitempty" binding:"omitempty,gt=0"`
+	TakeDiscounts bool               `json:"take_discounts,omitempty"`
+	Notes         *string            `json:"notes,omitempty"`
+}
+
+// PaymentRunFailLineRequest represents a request to record that the bank rejected a transfer
+type PaymentRunFailLineRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// PaymentRunFilterParams represents filtering parameters for payment run queries
+type PaymentRunFilterParams struct {
+	RunStatus *PaymentRunStatus `json:"run_status,omitempty" form:"run_status"`
+	DateFrom  *time.Time        `json:"date_from,omitempty" form:"date_from"`
+	DateTo    *time.Time        `json:"date_to,omitempty" form:"date_to"`
+	Search    string            `json:"search,omitempty" form:"search"`
+	common.PaginationParams
+}
+
+// CanEdit checks if lines can still be removed from the payment run
+func (pr *PaymentRun) CanEdit() bool {
+	return pr.RunStatus == PaymentRunStatusDraft
+}
+
+// CanApprove checks if the payment run can be approved
+func (pr *PaymentRun) CanApprove() bool {
+	return pr.RunStatus == PaymentRunStatusDraft
+}
+
+// CanExport checks if a bank file can be generated for the payment run
+func (pr *PaymentRun) CanExport() bool {
+	return pr.RunStatus == PaymentRunStatusApproved || pr.RunStatus == PaymentRunStatusExecuted
+}
+
+// CanExecute checks if the payments of the run can be posted
+func (pr *PaymentRun) CanExecute() bool {
+	return pr.RunStatus == PaymentRunStatusApproved
+}
+
+// CanCancel checks if the payment run can be cancelled
+func (pr *PaymentRun) CanCancel() bool {
+	return pr.RunStatus == PaymentRunStatusDraft || pr.RunStatus == PaymentRunStatusApproved
+}
+
+// CanFailLine checks if a transfer of the run can be marked as rejected by the bank
+func (pr *PaymentRun) CanFailLine() bool {
+	return pr.RunStatus == PaymentRunStatusApproved || pr.RunStatus == PaymentRunStatusExecuted
+}
+
+// ActiveLines returns the lines that have not failed
+func (pr *PaymentRun) ActiveLines() []PaymentRunLine {
+	lines := make([]PaymentRunLine, 0, len(pr.Lines))
+	for _, line := range pr.Lines {
+		if line.LineStatus != PaymentRunLineStatusFailed {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// MissingBankAccounts returns the names of suppliers in the run without a bank account
+func (pr *PaymentRun) MissingBankAccounts() []string {
+	seen := make(map[int]bool)
+	var names []string
+	for _, line := range pr.ActiveLines() {
+		if (line.BankAccount == nil || strings.TrimSpace(*line.BankAccount) == "") && !seen[line.SupplierID] {
+			seen[line.SupplierID] = true
+			names = append(names, line.SupplierName)
+		}
+	}
+	return names
+}
+
+// BankFileRecords returns one record per transfer of the run keyed by bank file field name.
+// The fields are line_number, run_number, payment_date, bank_account, supplier_code,
+// supplier_name, amount, amount_cents, currency and reference.
+func (pr *PaymentRun) BankFileRecords(currency string) []map[string]string {
+	lines := pr.ActiveLines()
+	records := make([]map[string]string, 0, len(lines))
+	for i, line := range lines {
+		bankAccount := ""
+		if line.BankAccount != nil {
+			bankAccount = strings.TrimSpace(*line.BankAccount)
+		}
+		records = append(records, map[string]string{
+			"line_number":   fmt.Sprintf("%d", i+1),
+			"run_number":    pr.RunNumber,
+			"payment_date":  pr.PaymentDate.Format("20060102"),
+			"bank_account":  bankAccount,
+			"supplier_code": line.SupplierCode,
+			"supplier_name": line.SupplierName,
+			"amount":        fmt.Sprintf("%.2f", line.Amount),
+			"amount_cents":  fmt.Sprintf("%d", int64(math.Round(line.Amount*100))),
+			"currency":      currency,
+			"reference":     line.InvoiceNumber,
+		})
+	}
+	return records
+}
+
+// BankFileTable returns the transfers of the run as a header and rows for a CSV bank file
+func (pr *PaymentRun) BankFileTable(currency string) ([]string, [][]interface{}) {
+	header := []string{"No", "Bank Account", "Supplier Code", "Supplier Name", "Amount", "Currency", "Reference", "Payment Date"}
+
+	records := pr.BankFileRecords(currency)
+	rows := make([][]interface{}, 0, len(records))
+	for _, record := range records {
+		rows = append(rows, []interface{}{
+			record["line_number"],
+			record["bank_account"],
+			record["supplier_code"],
+			record["supplier_name"],
+			record["amount"],
+			record["currency"],
+			record["reference"],
+			record["payment_date"],
+		})
+	}
+	return header, rows
+}
+
+// BuildPaymentRunLines selects lines from the candidate invoices in priority order. When taking
+// discounts, each line pays the outstanding amount less the discount still available on the payment date.
+// Invoices that no longer fit in the remaining budget are skipped.
+func BuildPaymentRunLines(candidates []PaymentRunCandidate, priority PaymentRunPriority, paymentDate time.Time, takeDiscounts bool, maxAmount *float64) []PaymentRunLine {
+	lines := make([]PaymentRunLine, 0, len(candidates))
+	for _, candidate := range candidates {
+		invoice := candidate.Invoice
+		if !invoice.CanAllocate() || invoice.OutstandingAmount <= 0 {
+			continue
+		}
+
+		line := PaymentRunLine{
+			InvoiceID:     invoice.InvoiceID,
+			SupplierID:    invoice.SupplierID,
+			LineStatus:    PaymentRunLineStatusPending,
+			BankAccount:   candidate.BankAccount,
+			InvoiceNumber: invoice.InvoiceNumber,
+			DueDate:       invoice.DueDate,
+			SupplierCode:  candidate.SupplierCode,
+			SupplierName:  candidate.SupplierName,
+		}
+		if takeDiscounts {
+			line.DiscountAmount = invoice.AvailableDiscount(paymentDate)
+		}
+		line.Amount = math.Round((invoice.OutstandingAmount-line.DiscountAmount)*100) / 100
+		lines = append(lines, line)
+	}
+
+	sortPaymentRunLines(lines, candidates, priority, paymentDate)
+
+	if maxAmount == nil {
+		return lines
+	}
+
+	selected := make([]PaymentRunLine, 0, len(lines))
+	remaining := *maxAmount
+	for _, line := range lines {
+		if line.Amount > remaining+0.005 {
+			continue
+		}
+		remaining -= line.Amount
+		selected = append(selected, line)
+	}
+	return selected
+}
+
+// sortPaymentRunLines orders lines by priority, breaking ties by due date and invoice ID
+func sortPaymentRunLines(lines []PaymentRunLine, candidates []PaymentRunCandidate, priority PaymentRunPriority, paymentDate time.Time) {
+	discountDueDates := make(map[int]*time.Time, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.Invoice.AvailableDiscount(paymentDate) > 0 {
+			discountDueDates[candidate.Invoice.InvoiceID] = candidate.Invoice.DiscountDueDate
+		}
+	}
+
+	byDueDate := func(a, b PaymentRunLine) bool {
+		if !a.DueDate.Equal(b.DueDate) {
+			return a.DueDate.Before(b.DueDate)
+		}
+		return a.InvoiceID < b.InvoiceID
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		a, b := lines[i], lines[j]
+		switch priority {
+		case PaymentRunPriorityDiscount:
+			da, db := discountDueDates[a.InvoiceID], discountDueDates[b.InvoiceID]
+			switch {
+			case da != nil && db == nil:
+				return true
+			case da == nil && db != nil:
+				return false
+			case da != nil && db != nil && !da.Equal(*db):
+				return da.Before(*db)
+			}
+		case PaymentRunPriorityAmount:
+			if a.Amount != b.Amount {
+				return a.Amount > b.Amount
+			}
+		}
+		return byDueDate(a, b)
+	})
+}
@@ -0,0 +1,198 @@
+package products
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+)
+
+// PaymentTerm represents structured supplier payment terms such as "2/10 net 30":
+// a discount percentage available within the discount window and the full amount due after NetDays
+type PaymentTerm struct {
+	TermID          int       `json:"term_id" db:"term_id"`
+	TermCode        string    `json:"term_code" db:"term_code"`
+	TermName        string    `json:"term_name" db:"term_name"`
+	NetDays         int       `json:"net_days" db:"net_days"`
+	DiscountPercent float64   `json:"discount_percent" db:"discount_percent"`
+	DiscountDays    int       `json:"discount_days" db:"discount_days"`
+	IsActive        bool      `json:"is_active" db:"is_active"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PaymentTermCreateRequest represents a request to create payment terms
+type PaymentTermCreateRequest struct {
+	TermCode        string  `json:"term_code" binding:"required,max=30"`
+	TermName        string  `json:"term_name" binding:"required,max=100"`
+	NetDays         int     `json:"net_days" binding:"min=0"`
+	DiscountPercent float64 `json:"discount_percent" binding:"min=0,lt=100"`
+	DiscountDays    int     `json:"discount_days" binding:"min=0"`
+}
+
+// PaymentTermUpdateRequest represents a request to update payment terms
+type PaymentTermUpdateRequest struct {
+	TermName        *string  `json:"term_name,omitempty" binding:"omitempty,max=100"`
+	NetDays         *int     `json:"net_days,omitempty" binding:"omitempty,min=0"`
+	DiscountPercent *float64 `json:"discount_percent,omitempty" binding:"omitempty,min=0,lt=100"`
+	DiscountDays    *int     `json:"discount_days,omitempty" binding:"omitempty,min=0"`
+	IsActive        *bool    `json:"is_active,omitempty"`
+}
+
+// PaymentTermFilterParams represents filtering parameters for payment term queries
+type PaymentTermFilterParams struct {
+	IsActive    *bool  `json:"is_active,omitempty" form:"is_active"`
+	HasDiscount *bool  `json:"has_discount,omitempty" form:"has_discount"`
+	Search      string `json:"search,omitempty" form:"search"`
+	common.PaginationParams
+}
+
+// Validate checks that the discount window fits within the net terms
+func (pt *PaymentTerm) Validate() error {
+	if pt.NetDays < 0 || pt.DiscountDays < 0 {
+		return fmt.Errorf("payment term days cannot be negative")
+	}
+	if pt.DiscountPercent < 0 || pt.DiscountPercent >= 100 {
+		return fmt.Errorf("discount percent must be between 0 and 100")
+	}
+	if pt.DiscountPercent > 0 && pt.DiscountDays == 0 {
+		return fmt.Errorf("discount days are required when a discount percent is set")
+	}
+	if pt.DiscountDays > pt.NetDays {
+		return fmt.Errorf("discount days cannot exceed net days")
+	}
+	return nil
+}
+
+// HasDiscount checks if the terms offer an early payment discount
+func (pt *PaymentTerm) HasDiscount() bool {
+	return pt.DiscountPercent > 0 && pt.DiscountDays > 0
+}
+
+// DueDate returns the net due date for an invoice dated invoiceDate
+func (pt *PaymentTerm) DueDate(invoiceDate time.Time) time.Time {
+	return invoiceDate.AddDate(0, 0, pt.NetDays)
+}
+
+// DiscountDueDate returns the last day the early payment discount can be taken, or nil without a discount
+func (pt *PaymentTerm) DiscountDueDate(invoiceDate time.Time) *time.Time {
+	if !pt.HasDiscount() {
+		return nil
+	}
+	date := invoiceDate.AddDate(0, 0, pt.DiscountDays)
+	return &date
+}
+
+// Describe returns the conventional notation of the terms, e.g. "2/10 net 30"
+func (pt *PaymentTerm) Describe() string {
+	if pt.HasDiscount() {
+		return fmt.Sprintf("%s/%d net %d", formatPercent(pt.DiscountPercent), pt.DiscountDays, pt.NetDays)
+	}
+	if pt.NetDays == 0 {
+		return "due on receipt"
+	}
+	return fmt.Sprintf("net %d", pt.NetDays)
+}
+
+// AnnualizedDiscountRate returns the annual return of paying within the discount window
+// instead of on the net due date, as a percentage
+func AnnualizedDiscountRate(discountPercent float64, daysGained int) float64 {
+	if discountPercent <= 0 || discountPercent >= 100 || daysGained <= 0 {
+		return 0
+	}
+	rate := discountPercent / (100 - discountPercent) * 365 / float64(daysGained) * 100
+	return math.Round(rate*100) / 100
+}
+
+func formatPercent(percent float64) string {
+	if percent == math.Trunc(percent) {
+		return fmt.Sprintf("%d", int(percent))
+	}
+	return fmt.Sprintf("%g", percent)
+}
+
+// EarlyPaymentDiscountParams represents the filters of the capture-discounts report
+type EarlyPaymentDiscountParams struct {
+	AsOfDate   *time.Time `json:"as_of_date,omitempty" form:"as_of_date" time_format:"2006-01-02"`
+	SupplierID *int       `json:"supplier_id,omitempty" form:"supplier_id"`
+	WithinDays *int       `json:"within_days,omitempty" form:"within_days" binding:"omitempty,min=0"`
+}
+
+// EarlyPaymentDiscountOpportunity is an open invoice whose early payment discount can still be captured
+type EarlyPaymentDiscountOpportunity struct {
+	InvoiceID         int       `json:"invoice_id"`
+	InvoiceNumber     string    `json:"invoice_number"`
+	SupplierID        int       `json:"supplier_id"`
+	SupplierName      string    `json:"supplier_name"`
+	InvoiceDate       time.Time `json:"invoice_date"`
+	DueDate           time.Time `json:"due_date"`
+	DiscountDueDate   time.Time `json:"discount_due_date"`
+	DaysLeft          int       `json:"days_left"`
+	DiscountPercent   float64   `json:"discount_percent"`
+	OutstandingAmount float64   `json:"outstanding_amount"`
+	DiscountAvailable float64   `json:"discount_available"`
+	NetPaymentAmount  float64   `json:"net_payment_amount"`
+	AnnualizedReturn  float64   `json:"annualized_return"`
+}
+
+// NewEarlyPaymentDiscountOpportunity evaluates an invoice for a payment made on asOf.
+// It reports false when no discount is left to capture.
+func NewEarlyPaymentDiscountOpportunity(invoice *SupplierInvoice, supplierName string, asOf time.Time) (EarlyPaymentDiscountOpportunity, bool) {
+	discount := invoice.AvailableDiscount(asOf)
+	if discount <= 0 || invoice.DiscountDueDate == nil || !invoice.CanAllocate() {
+		return EarlyPaymentDiscountOpportunity{}, false
+	}
+
+	return EarlyPaymentDiscountOpportunity{
+		InvoiceID:         invoice.InvoiceID,
+		InvoiceNumber:     invoice.InvoiceNumber,
+		SupplierID:        invoice.SupplierID,
+		SupplierName:      supplierName,
+		InvoiceDate:       invoice.InvoiceDate,
+		DueDate:           invoice.DueDate,
+		DiscountDueDate:   *invoice.DiscountDueDate,
+		DaysLeft:          daysBetween(asOf, *invoice.DiscountDueDate),
+		DiscountPercent:   invoice.DiscountPercent,
+		OutstandingAmount: invoice.OutstandingAmount,
+		DiscountAvailable: discount,
+		NetPaymentAmount:  math.Round((invoice.OutstandingAmount-discount)*100) / 100,
+		AnnualizedReturn:  AnnualizedDiscountRate(invoice.DiscountPercent, daysBetween(*invoice.DiscountDueDate, invoice.DueDate)),
+	}, true
+}
+
+// EarlyPaymentDiscountReport lists invoices to pay now to capture their early payment discounts
+type EarlyPaymentDiscountReport struct {
+	AsOfDate         time.Time                         `json:"as_of_date"`
+	Opportunities    []EarlyPaymentDiscountOpportunity `json:"opportunities"`
+	TotalOutstanding float64                           `json:"total_outstanding"`
+	TotalDiscount    float64                           `json:"total_discount"`
+	TotalNetPayment  float64                           `json:"total_net_payment"`
+}
+
+// NewEarlyPaymentDiscountReport totals the opportunities, keeping those whose discount window
+// closes within withinDays when set. Windows closing soonest come first.
+func NewEarlyPaymentDiscountReport(asOf time.Time, opportunities []EarlyPaymentDiscountOpportunity, withinDays *int) *EarlyPaymentDiscountReport {
+	report := &EarlyPaymentDiscountReport{AsOfDate: asOf, Opportunities: []EarlyPaymentDiscountOpportunity{}}
+
+	for _, opportunity := range opportunities {
+		if withinDays != nil && opportunity.DaysLeft > *withinDays {
+			continue
+		}
+		report.Opportunities = append(report.Opportunities, opportunity)
+		report.TotalOutstanding += opportunity.OutstandingAmount
+		report.TotalDiscount += opportunity.DiscountAvailable
+		report.TotalNetPayment += opportunity.NetPaymentAmount
+	}
+
+	sort.SliceStable(report.Opportunities, func(i, j int) bool {
+		a, b := report.Opportunities[i], report.Opportunities[j]
+		if !a.DiscountDueDate.Equal(b.DiscountDueDate) {
+			return a.DiscountDueDate.Before(b.DiscountDueDate)
+		}
+		return a.DiscountAvailable > b.DiscountAvailable
+	})
+
+	return report
+}
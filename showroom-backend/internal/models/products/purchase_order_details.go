@@ -65,6 +65,9 @@ type PurchaseOrderDetail struct {
 	QuantityPending  int         `json:"quantity_pending" db:"quantity_pending"`
 	UnitCost         float64     `json:"unit_cost" db:"unit_cost"`
 	TotalCost        float64     `json:"total_cost" db:"total_cost"`
+	TaxCodeID        *int        `json:"tax_code_id,omitempty" db:"tax_code_id"`
+	TaxRate          float64     `json:"tax_rate" db:"tax_rate"`
+	TaxAmount        float64     `json:"tax_amount" db:"tax_amount"`
 	ExpectedDate     *time.Time  `json:"expected_date,omitempty" db:"expected_date"`
 	ReceivedDate     *time.Time  `json:"received_date,omitempty" db:"received_date"`
 	LineStatus       LineStatus  `json:"line_status" db:"line_status"`
@@ -82,6 +85,7 @@ type PurchaseOrderDetailListItem struct {
 	QuantityPending  int        `json:"quantity_pending" db:"quantity_pending"`
 	UnitCost         float64    `json:"unit_cost" db:"unit_cost"`
 	TotalCost        float64    `json:"total_cost" db:"total_cost"`
+	TaxAmount        float64    `json:"tax_amount" db:"tax_amount"`
 	LineStatus       LineStatus `json:"line_status" db:"line_status"`
 }
 
@@ -90,7 +94,9 @@ type PurchaseOrderDetailCreateRequest struct {
 	ProductID       int      `json:"product_id" binding:"required,min=1"`
 	ItemDescription *string  `json:"item_description,omitempty" binding:"omitempty,max=500"`
 	QuantityOrdered int      `json:"quantity_ordered" binding:"required,min=1"`
-	UnitCost        float64  `json:"unit_cost" binding:"required,min=0"`
+	// UnitCost defaults to the supplier's active price list when omitted
+	UnitCost        *float64 `json:"unit_cost,omitempty" binding:"omitempty,min=0"`
+	TaxCodeID       *int     `json:"tax_code_id,omitempty" binding:"omitempty,min=1"`
 	ExpectedDate    *time.Time `json:"expected_date,omitempty"`
 	ItemNotes       *string  `json:"item_notes,omitempty"`
 }
@@ -155,6 +161,19 @@ func (pod *PurchaseOrderDetail) CalculateTotalCost() {
 	pod.TotalCost = float64(pod.QuantityOrdered) * pod.UnitCost
 }
 
+// ApplyTax snapshots the rate of the tax code on the line and calculates its tax.
+// A nil tax code leaves the line untaxed.
+func (pod *PurchaseOrderDetail) ApplyTax(taxCode *TaxCode, pricesIncludeTax bool) {
+	pod.TaxCodeID, pod.TaxRate, pod.TaxAmount = nil, 0, 0
+	if taxCode == nil {
+		return
+	}
+
+	pod.TaxCodeID = &taxCode.TaxCodeID
+	pod.TaxRate = taxCode.Rate
+	_, pod.TaxAmount = CalculateTax(pod.TotalCost, pod.TaxRate, pricesIncludeTax)
+}
+
 // CanReceiveMore checks if more quantity can be received
 func (pod *PurchaseOrderDetail) CanReceiveMore() bool {
 	return pod.LineStatus != LineStatusReceived && pod.LineStatus != LineStatusCancelled && pod.QuantityPending > 0
@@ -158,6 +158,9 @@ type PurchaseOrderParts struct {
 	RequiredDate         *time.Time    `json:"required_date,omitempty" db:"required_date"`
 	ExpectedDeliveryDate *time.Time    `json:"expected_delivery_date,omitempty" db:"expected_delivery_date"`
 	POType               POType        `json:"po_type" db:"po_type"`
+	PricesIncludeTax     bool          `json:"prices_include_tax" db:"prices_include_tax"`
+	CurrencyCode         string        `json:"currency_code" db:"currency_code"`
+	ExchangeRate         float64       `json:"exchange_rate" db:"exchange_rate"`
 	Subtotal             float64       `json:"subtotal" db:"subtotal"`
 	TaxAmount            float64       `json:"tax_amount" db:"tax_amount"`
 	DiscountAmount       float64       `json:"discount_amount" db:"discount_amount"`
@@ -185,6 +188,7 @@ type PurchaseOrderPartsListItem struct {
 	RequiredDate         *time.Time   `json:"required_date,omitempty" db:"required_date"`
 	ExpectedDeliveryDate *time.Time   `json:"expected_delivery_date,omitempty" db:"expected_delivery_date"`
 	POType               POType       `json:"po_type" db:"po_type"`
+	CurrencyCode         string       `json:"currency_code" db:"currency_code"`
 	TotalAmount          float64      `json:"total_amount" db:"total_amount"`
 	Status               POStatus     `json:"status" db:"status"`
 	PaymentTerms         PaymentTerms `json:"payment_terms" db:"payment_terms"`
@@ -201,6 +205,13 @@ type PurchaseOrderPartsCreateRequest struct {
 	DeliveryAddress      *string       `json:"delivery_address,omitempty" binding:"omitempty,max=500"`
 	PONotes              *string       `json:"po_notes,omitempty"`
 	TermsAndConditions   *string       `json:"terms_and_conditions,omitempty"`
+	// PricesIncludeTax marks line unit costs as including the tax of their tax code
+	PricesIncludeTax     bool          `json:"prices_include_tax"`
+	// CurrencyCode defaults to the base currency. ExchangeRate defaults to the rate in effect on the PO date.
+	CurrencyCode         string        `json:"currency_code,omitempty" binding:"omitempty,len=3"`
+	ExchangeRate         *float64      `json:"exchange_rate,omitempty" binding:"omitempty,gt=0"`
+	// BlanketTerms is required for blanket and contract purchase orders
+	BlanketTerms         *BlanketOrderTermsRequest `json:"blanket_terms,omitempty"`
 }
 
 // PurchaseOrderPartsUpdateRequest represents a request to update a purchase order
@@ -264,6 +275,21 @@ func (po *PurchaseOrderParts) CalculateTotals() {
 	po.TotalAmount = po.Subtotal + po.TaxAmount + po.ShippingCost - po.DiscountAmount
 }
 
+// ApplyLineTotals sets the subtotal from the sum of the line totals. Once any line carries a
+// tax code the tax amount is the sum of the line taxes; with tax-inclusive prices it is taken
+// out of the subtotal so the tax is not counted twice.
+func (po *PurchaseOrderParts) ApplyLineTotals(lineTotal, lineTax float64, taxedLines int) {
+	if taxedLines > 0 {
+		po.TaxAmount = roundCents(lineTax)
+	}
+
+	po.Subtotal = lineTotal
+	if po.PricesIncludeTax {
+		po.Subtotal = roundCents(lineTotal - lineTax)
+	}
+	po.CalculateTotals()
+}
+
 // SetPaymentDueDate sets the payment due date based on payment terms
 func (po *PurchaseOrderParts) SetPaymentDueDate() {
 	if po.PaymentDueDate != nil {
@@ -0,0 +1,408 @@
+package products
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+)
+
+// ReturnStatus represents the status of a purchase return
+type ReturnStatus string
+
+const (
+	ReturnStatusDraft     ReturnStatus = "draft"
+	ReturnStatusApproved  ReturnStatus = "approved"
+	ReturnStatusShipped   ReturnStatus = "shipped"
+	ReturnStatusCompleted ReturnStatus = "completed"
+	ReturnStatusCancelled ReturnStatus = "cancelled"
+)
+
+// IsValid checks if the return status is valid
+func (s ReturnStatus) IsValid() bool {
+	switch s {
+	case ReturnStatusDraft, ReturnStatusApproved, ReturnStatusShipped, ReturnStatusCompleted, ReturnStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the return status
+func (s ReturnStatus) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for ReturnStatus
+func (s ReturnStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for ReturnStatus
+func (s *ReturnStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*s = ReturnStatus(str)
+	case []byte:
+		*s = ReturnStatus(str)
+	default:
+		return fmt.Errorf("cannot scan %T into ReturnStatus", value)
+	}
+	return nil
+}
+
+// ShipmentStatus represents the shipment status of goods returned to a supplier
+type ShipmentStatus string
+
+const (
+	ShipmentStatusPending   ShipmentStatus = "pending"
+	ShipmentStatusInTransit ShipmentStatus = "in_transit"
+	ShipmentStatusDelivered ShipmentStatus = "delivered"
+	ShipmentStatusRefused   ShipmentStatus = "refused"
+)
+
+// IsValid checks if the shipment status is valid
+func (s ShipmentStatus) IsValid() bool {
+	switch s {
+	case ShipmentStatusPending, ShipmentStatusInTransit, ShipmentStatusDelivered, ShipmentStatusRefused:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the shipment status
+func (s ShipmentStatus) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for ShipmentStatus
+func (s ShipmentStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for ShipmentStatus
+func (s *ShipmentStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*s = ShipmentStatus(str)
+	case []byte:
+		*s = ShipmentStatus(str)
+	default:
+		return fmt.Errorf("cannot scan %T into ShipmentStatus", value)
+	}
+	return nil
+}
+
+// ReturnSource represents where returned goods are taken from
+type ReturnSource string
+
+const (
+	ReturnSourceReceipt ReturnSource = "receipt"
+	ReturnSourceStock   ReturnSource = "stock"
+)
+
+// IsValid checks if the return source is valid
+func (s ReturnSource) IsValid() bool {
+	switch s {
+	case ReturnSourceReceipt, ReturnSourceStock:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the return source
+func (s ReturnSource) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for ReturnSource
+func (s ReturnSource) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for ReturnSource
+func (s *ReturnSource) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*s = ReturnSource(str)
+	case []byte:
+		*s = ReturnSource(str)
+	default:
+		return fmt.Errorf("cannot scan %T into ReturnSource", value)
+	}
+	return nil
+}
+
+// PurchaseReturn represents a return-to-vendor document
+type PurchaseReturn struct {
+	ReturnID         int            `json:"return_id" db:"return_id"`
+	ReturnNumber     string         `json:"return_number" db:"return_number"`
+	SupplierID       int            `json:"supplier_id" db:"supplier_id"`
+	ReceiptID        *int           `json:"receipt_id,omitempty" db:"receipt_id"`
+	POID             *int           `json:"po_id,omitempty" db:"po_id"`
+	ReturnSource     ReturnSource   `json:"return_source" db:"return_source"`
+	ReturnDate       time.Time      `json:"return_date" db:"return_date"`
+	ReturnStatus     ReturnStatus   `json:"return_status" db:"return_status"`
+	ShipmentStatus   ShipmentStatus `json:"shipment_status" db:"shipment_status"`
+	CarrierName      *string        `json:"carrier_name,omitempty" db:"carrier_name"`
+	TrackingNumber   *string        `json:"tracking_number,omitempty" db:"tracking_number"`
+	ShippedAt        *time.Time     `json:"shipped_at,omitempty" db:"shipped_at"`
+	DeliveredAt      *time.Time     `json:"delivered_at,omitempty" db:"delivered_at"`
+	TotalReturnValue float64        `json:"total_return_value" db:"total_return_value"`
+	ReturnReason     string         `json:"return_reason" db:"return_reason"`
+	ReturnNotes      *string        `json:"return_notes,omitempty" db:"return_notes"`
+	CreatedBy        int            `json:"created_by" db:"created_by"`
+	ApprovedBy       *int           `json:"approved_by,omitempty" db:"approved_by"`
+	ApprovedAt       *time.Time     `json:"approved_at,omitempty" db:"approved_at"`
+	CreatedAt        time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// PurchaseReturnListItem represents a simplified purchase return for list views
+type PurchaseReturnListItem struct {
+	ReturnID         int            `json:"return_id" db:"return_id"`
+	ReturnNumber     string         `json:"return_number" db:"return_number"`
+	SupplierID       int            `json:"supplier_id" db:"supplier_id"`
+	SupplierName     string         `json:"supplier_name" db:"supplier_name"`
+	ReceiptID        *int           `json:"receipt_id,omitempty" db:"receipt_id"`
+	ReturnSource     ReturnSource   `json:"return_source" db:"return_source"`
+	ReturnDate       time.Time      `json:"return_date" db:"return_date"`
+	ReturnStatus     ReturnStatus   `json:"return_status" db:"return_status"`
+	ShipmentStatus   ShipmentStatus `json:"shipment_status" db:"shipment_status"`
+	TotalReturnValue float64        `json:"total_return_value" db:"total_return_value"`
+	CreatedAt        time.Time      `json:"created_at" db:"created_at"`
+}
+
+// PurchaseReturnDetail represents a line item in a purchase return
+type PurchaseReturnDetail struct {
+	ReturnDetailID   int     `json:"return_detail_id" db:"return_detail_id"`
+	ReturnID         int     `json:"return_id" db:"return_id"`
+	ProductID        int     `json:"product_id" db:"product_id"`
+	ReceiptDetailID  *int    `json:"receipt_detail_id,omitempty" db:"receipt_detail_id"`
+	QuantityReturned int     `json:"quantity_returned" db:"quantity_returned"`
+	UnitCost         float64 `json:"unit_cost" db:"unit_cost"`
+	TotalCost        float64 `json:"total_cost" db:"total_cost"`
+	ReturnReason     *string `json:"return_reason,omitempty" db:"return_reason"`
+}
+
+// PurchaseReturnCreateRequest represents a request to create a purchase return
+type PurchaseReturnCreateRequest struct {
+	SupplierID   int                                 `json:"supplier_id" binding:"required,min=1"`
+	POID         *int                                `json:"po_id,omitempty" binding:"omitempty,min=1"`
+	ReturnDate   *time.Time                          `json:"return_date,omitempty"`
+	ReturnReason string                              `json:"return_reason" binding:"required,max=255"`
+	ReturnNotes  *string                             `json:"return_notes,omitempty"`
+	Items        []PurchaseReturnDetailCreateRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// PurchaseReturnDetailCreateRequest represents a request to add a line to a purchase return from stock.
+// UnitCost is the price the supplier credits, defaulting to the product's cost; inventory is always
+// relieved at the product's cost.
+type PurchaseReturnDetailCreateRequest struct {
+	ProductID        int      `json:"product_id" binding:"required,min=1"`
+	QuantityReturned int      `json:"quantity_returned" binding:"required,min=1"`
+	UnitCost         *float64 `json:"unit_cost,omitempty" binding:"omitempty,min=0"`
+	ReturnReason     *string  `json:"return_reason,omitempty"`
+}
+
+// PurchaseReturnFromReceiptRequest represents a request to return rejected lines of a goods receipt
+type PurchaseReturnFromReceiptRequest struct {
+	ReturnDate  *time.Time `json:"return_date,omitempty"`
+	ReturnNotes *string    `json:"return_notes,omitempty"`
+	// ReceiptDetailIDs limits the return to specific lines; all rejected lines are used when empty
+	ReceiptDetailIDs []int `json:"receipt_detail_ids,omitempty"`
+}
+
+// PurchaseReturnShipmentRequest represents a request to update the shipment of a purchase return
+type PurchaseReturnShipmentRequest struct {
+	ShipmentStatus ShipmentStatus `json:"shipment_status" binding:"required"`
+	CarrierName    *string        `json:"carrier_name,omitempty" binding:"omitempty,max=100"`
+	TrackingNumber *string        `json:"tracking_number,omitempty" binding:"omitempty,max=100"`
+}
+
+// PurchaseReturnFilterParams represents filtering parameters for purchase return queries
+type PurchaseReturnFilterParams struct {
+	SupplierID     *int            `json:"supplier_id,omitempty" form:"supplier_id"`
+	ReceiptID      *int            `json:"receipt_id,omitempty" form:"receipt_id"`
+	ReturnSource   *ReturnSource   `json:"return_source,omitempty" form:"return_source"`
+	ReturnStatus   *ReturnStatus   `json:"return_status,omitempty" form:"return_status"`
+	ShipmentStatus *ShipmentStatus `json:"shipment_status,omitempty" form:"shipment_status"`
+	DateFrom       *time.Time      `json:"date_from,omitempty" form:"date_from"`
+	DateTo         *time.Time      `json:"date_to,omitempty" form:"date_to"`
+	Search         string          `json:"search,omitempty" form:"search"`
+	common.PaginationParams
+}
+
+// UpdateTotalCost calculates and updates the total cost of the return line
+func (d *PurchaseReturnDetail) UpdateTotalCost() {
+	d.TotalCost = float64(d.QuantityReturned) * d.UnitCost
+}
+
+// CalculateTotalValue calculates the total value of the return
+func (pr *PurchaseReturn) CalculateTotalValue(details []PurchaseReturnDetail) {
+	total := 0.0
+	for _, detail := range details {
+		total += detail.TotalCost
+	}
+	pr.TotalReturnValue = total
+}
+
+// CanEdit checks if the return can still be edited
+func (pr *PurchaseReturn) CanEdit() bool {
+	return pr.ReturnStatus == ReturnStatusDraft
+}
+
+// CanApprove checks if the return can be approved
+func (pr *PurchaseReturn) CanApprove() bool {
+	return pr.ReturnStatus == ReturnStatusDraft
+}
+
+// CanShip checks if the return can be shipped to the supplier
+func (pr *PurchaseReturn) CanShip() bool {
+	return pr.ReturnStatus == ReturnStatusApproved || pr.ReturnStatus == ReturnStatusShipped
+}
+
+// CanCancel checks if the return can be cancelled
+func (pr *PurchaseReturn) CanCancel() bool {
+	return pr.ReturnStatus == ReturnStatusDraft
+}
+
+// ApplyShipmentStatus updates shipment tracking and derives the return status
+func (pr *PurchaseReturn) ApplyShipmentStatus(status ShipmentStatus) {
+	now := time.Now()
+	pr.ShipmentStatus = status
+	switch status {
+	case ShipmentStatusInTransit:
+		if pr.ShippedAt == nil {
+			pr.ShippedAt = &now
+		}
+		pr.ReturnStatus = ReturnStatusShipped
+	case ShipmentStatusDelivered:
+		if pr.ShippedAt == nil {
+			pr.ShippedAt = &now
+		}
+		pr.DeliveredAt = &now
+		pr.ReturnStatus = ReturnStatusCompleted
+	case ShipmentStatusRefused:
+		pr.ReturnStatus = ReturnStatusShipped
+	}
+}
+
+// DebitNoteStatus represents the status of a supplier debit note
+type DebitNoteStatus string
+
+const (
+	DebitNoteStatusOpen      DebitNoteStatus = "open"
+	DebitNoteStatusPartial   DebitNoteStatus = "partial"
+	DebitNoteStatusApplied   DebitNoteStatus = "applied"
+	DebitNoteStatusCancelled DebitNoteStatus = "cancelled"
+)
+
+// IsValid checks if the debit note status is valid
+func (s DebitNoteStatus) IsValid() bool {
+	switch s {
+	case DebitNoteStatusOpen, DebitNoteStatusPartial, DebitNoteStatusApplied, DebitNoteStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the debit note status
+func (s DebitNoteStatus) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for DebitNoteStatus
+func (s DebitNoteStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for DebitNoteStatus
+func (s *DebitNoteStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*s = DebitNoteStatus(str)
+	case []byte:
+		*s = DebitNoteStatus(str)
+	default:
+		return fmt.Errorf("cannot scan %T into DebitNoteStatus", value)
+	}
+	return nil
+}
+
+// SupplierDebitNote represents a claim against a supplier for returned goods
+type SupplierDebitNote struct {
+	DebitNoteID     int             `json:"debit_note_id" db:"debit_note_id"`
+	DebitNoteNumber string          `json:"debit_note_number" db:"debit_note_number"`
+	SupplierID      int             `json:"supplier_id" db:"supplier_id"`
+	ReturnID        *int            `json:"return_id,omitempty" db:"return_id"`
+	DebitNoteDate   time.Time       `json:"debit_note_date" db:"debit_note_date"`
+	CurrencyCode    string          `json:"currency_code" db:"currency_code"`
+	ExchangeRate    float64         `json:"exchange_rate" db:"exchange_rate"`
+	DebitAmount     float64         `json:"debit_amount" db:"debit_amount"`
+	AppliedAmount   float64         `json:"applied_amount" db:"applied_amount"`
+	DebitNoteStatus DebitNoteStatus `json:"debit_note_status" db:"debit_note_status"`
+	Notes           *string         `json:"notes,omitempty" db:"notes"`
+	CreatedBy       int             `json:"created_by" db:"created_by"`
+	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// SupplierDebitNoteApplication records how much of a debit note was applied to a supplier invoice
+type SupplierDebitNoteApplication struct {
+	ApplicationID int       `json:"application_id" db:"application_id"`
+	DebitNoteID   int       `json:"debit_note_id" db:"debit_note_id"`
+	InvoiceID     int       `json:"invoice_id" db:"invoice_id"`
+	AppliedAmount float64   `json:"applied_amount" db:"applied_amount"`
+	AppliedAt     time.Time `json:"applied_at" db:"applied_at"`
+	AppliedBy     int       `json:"applied_by" db:"applied_by"`
+}
+
+// SupplierDebitNoteFilterParams represents filtering parameters for supplier debit note queries
+type SupplierDebitNoteFilterParams struct {
+	SupplierID      *int             `json:"supplier_id,omitempty" form:"supplier_id"`
+	ReturnID        *int             `json:"return_id,omitempty" form:"return_id"`
+	DebitNoteStatus *DebitNoteStatus `json:"debit_note_status,omitempty" form:"debit_note_status"`
+	DateFrom        *time.Time       `json:"date_from,omitempty" form:"date_from"`
+	DateTo          *time.Time       `json:"date_to,omitempty" form:"date_to"`
+	common.PaginationParams
+}
+
+// GetRemainingAmount returns the amount of the debit note not yet applied
+func (dn *SupplierDebitNote) GetRemainingAmount() float64 {
+	remaining := dn.DebitAmount - dn.AppliedAmount
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// UpdateStatus updates the debit note status based on the applied amount
+func (dn *SupplierDebitNote) UpdateStatus() {
+	if dn.DebitNoteStatus == DebitNoteStatusCancelled {
+		return
+	}
+	if dn.GetRemainingAmount() <= 0 {
+		dn.DebitNoteStatus = DebitNoteStatusApplied
+	} else if dn.AppliedAmount > 0 {
+		dn.DebitNoteStatus = DebitNoteStatusPartial
+	} else {
+		dn.DebitNoteStatus = DebitNoteStatusOpen
+	}
+}
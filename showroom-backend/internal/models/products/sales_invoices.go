@@ -0,0 +1,236 @@
+package products
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+)
+
+// SalesInvoiceStatus represents the status of a sales invoice
+type SalesInvoiceStatus string
+
+const (
+	SalesInvoiceStatusIssued SalesInvoiceStatus = "issued"
+	SalesInvoiceStatusVoid   SalesInvoiceStatus = "void"
+)
+
+// IsValid checks if the sales invoice status is valid
+func (s SalesInvoiceStatus) IsValid() bool {
+	switch s {
+	case SalesInvoiceStatusIssued, SalesInvoiceStatusVoid:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the sales invoice status
+func (s SalesInvoiceStatus) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for SalesInvoiceStatus
+func (s SalesInvoiceStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for SalesInvoiceStatus
+func (s *SalesInvoiceStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*s = SalesInvoiceStatus(str)
+	case []byte:
+		*s = SalesInvoiceStatus(str)
+	default:
+		return fmt.Errorf("cannot scan %T into SalesInvoiceStatus", value)
+	}
+	return nil
+}
+
+// SalesPaymentType distinguishes sales paid at the counter from sales on the customer's credit
+type SalesPaymentType string
+
+const (
+	SalesPaymentTypeCash   SalesPaymentType = "cash"
+	SalesPaymentTypeCredit SalesPaymentType = "credit"
+)
+
+// IsValid checks if the sales payment type is valid
+func (t SalesPaymentType) IsValid() bool {
+	switch t {
+	case SalesPaymentTypeCash, SalesPaymentTypeCredit:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the sales payment type
+func (t SalesPaymentType) String() string {
+	return string(t)
+}
+
+// Value implements the driver.Valuer interface for SalesPaymentType
+func (t SalesPaymentType) Value() (driver.Value, error) {
+	return string(t), nil
+}
+
+// Scan implements the sql.Scanner interface for SalesPaymentType
+func (t *SalesPaymentType) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*t = SalesPaymentType(str)
+	case []byte:
+		*t = SalesPaymentType(str)
+	default:
+		return fmt.Errorf("cannot scan %T into SalesPaymentType", value)
+	}
+	return nil
+}
+
+// SalesInvoice represents a spare part sale to a customer. Issuing the invoice takes the goods
+// out of stock; Subtotal is the tax base and TotalAmount what the customer owes. Every issued invoice
+// is a receivable until customer receipts settle it: cash sales are due on the invoice date and
+// credit sales on the customer's payment term.
+type SalesInvoice struct {
+	InvoiceID         int                `json:"invoice_id" db:"invoice_id"`
+	InvoiceNumber     string             `json:"invoice_number" db:"invoice_number"`
+	CustomerID        int                `json:"customer_id" db:"customer_id"`
+	CustomerName      string             `json:"customer_name" db:"customer_name"`
+	CustomerTaxNumber *string            `json:"customer_tax_number,omitempty" db:"customer_tax_number"`
+	CustomerAddress   string             `json:"customer_address" db:"customer_address"`
+	InvoiceDate       time.Time          `json:"invoice_date" db:"invoice_date"`
+	PaymentType       SalesPaymentType   `json:"payment_type" db:"payment_type"`
+	DueDate           time.Time          `json:"due_date" db:"due_date"`
+	PricesIncludeTax  bool               `json:"prices_include_tax" db:"prices_include_tax"`
+	Subtotal          float64            `json:"subtotal" db:"subtotal"`
+	TaxAmount         float64            `json:"tax_amount" db:"tax_amount"`
+	TotalAmount       float64            `json:"total_amount" db:"total_amount"`
+	PaidAmount        float64            `json:"paid_amount" db:"paid_amount"`
+	OutstandingAmount float64            `json:"outstanding_amount" db:"outstanding_amount"`
+	TaxInvoiceNumber  *string            `json:"tax_invoice_number,omitempty" db:"tax_invoice_number"`
+	InvoiceStatus     SalesInvoiceStatus `json:"invoice_status" db:"invoice_status"`
+	Notes             *string            `json:"notes,omitempty" db:"notes"`
+	CreatedBy         int                `json:"created_by" db:"created_by"`
+	VoidedBy          *int               `json:"voided_by,omitempty" db:"voided_by"`
+	VoidedAt          *time.Time         `json:"voided_at,omitempty" db:"voided_at"`
+	CreatedAt         time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at" db:"updated_at"`
+	Lines             []SalesInvoiceLine `json:"lines,omitempty"`
+}
+
+// SalesInvoiceLine represents a product sold on a sales invoice. The tax rate is a snapshot of
+// the tax code at the time of sale and UnitCost the product cost moved out of inventory.
+type SalesInvoiceLine struct {
+	LineID         int     `json:"line_id" db:"line_id"`
+	InvoiceID      int     `json:"invoice_id" db:"invoice_id"`
+	ProductID      int     `json:"product_id" db:"product_id"`
+	ProductCode    string  `json:"product_code" db:"product_code"`
+	ProductName    string  `json:"product_name" db:"product_name"`
+	Quantity       int     `json:"quantity" db:"quantity"`
+	UnitPrice      float64 `json:"unit_price" db:"unit_price"`
+	DiscountAmount float64 `json:"discount_amount" db:"discount_amount"`
+	TaxCodeID      *int    `json:"tax_code_id,omitempty" db:"tax_code_id"`
+	TaxRate        float64 `json:"tax_rate" db:"tax_rate"`
+	TaxBase        float64 `json:"tax_base" db:"tax_base"`
+	TaxAmount      float64 `json:"tax_amount" db:"tax_amount"`
+	LineTotal      float64 `json:"line_total" db:"line_total"`
+	UnitCost       float64 `json:"unit_cost" db:"unit_cost"`
+}
+
+// SalesInvoiceCreateRequest represents a request to issue a sales invoice. PaymentType defaults to cash;
+// credit sales are only issued within the customer's credit limit.
+type SalesInvoiceCreateRequest struct {
+	CustomerID       int                             `json:"customer_id" binding:"required,min=1"`
+	InvoiceDate      *time.Time                      `json:"invoice_date,omitempty"`
+	PaymentType      SalesPaymentType                `json:"payment_type,omitempty"`
+	PricesIncludeTax bool                            `json:"prices_include_tax"`
+	Notes            *string                         `json:"notes,omitempty"`
+	Lines            []SalesInvoiceLineCreateRequest `json:"lines" binding:"required,min=1,dive"`
+}
+
+// SalesInvoiceLineCreateRequest represents a line of a sales invoice request.
+// UnitPrice defaults to the product's selling price.
+type SalesInvoiceLineCreateRequest struct {
+	ProductID      int      `json:"product_id" binding:"required,min=1"`
+	Quantity       int      `json:"quantity" binding:"required,min=1"`
+	UnitPrice      *float64 `json:"unit_price,omitempty" binding:"omitempty,min=0"`
+	DiscountAmount float64  `json:"discount_amount" binding:"min=0"`
+	TaxCodeID      *int     `json:"tax_code_id,omitempty" binding:"omitempty,min=1"`
+}
+
+// SalesInvoiceTaxNumberRequest represents a request to record the tax invoice number of a sales invoice
+type SalesInvoiceTaxNumberRequest struct {
+	TaxInvoiceNumber string `json:"tax_invoice_number" binding:"required,max=30"`
+}
+
+// SalesInvoiceFilterParams represents filtering parameters for sales invoice queries
+type SalesInvoiceFilterParams struct {
+	CustomerID     *int                `json:"customer_id,omitempty" form:"customer_id"`
+	InvoiceStatus  *SalesInvoiceStatus `json:"invoice_status,omitempty" form:"invoice_status"`
+	PaymentType    *SalesPaymentType   `json:"payment_type,omitempty" form:"payment_type"`
+	HasOutstanding *bool               `json:"has_outstanding,omitempty" form:"has_outstanding"`
+	DateFrom       *time.Time          `json:"date_from,omitempty" form:"date_from"`
+	DateTo         *time.Time          `json:"date_to,omitempty" form:"date_to"`
+	Search         string              `json:"search,omitempty" form:"search"`
+	common.PaginationParams
+}
+
+// ApplyTax calculates the tax base, tax and total of the line
+func (l *SalesInvoiceLine) ApplyTax(pricesIncludeTax bool) error {
+	gross := float64(l.Quantity) * l.UnitPrice
+	if l.DiscountAmount > gross {
+		return fmt.Errorf("discount of product %d exceeds the line amount", l.ProductID)
+	}
+
+	l.TaxBase, l.TaxAmount = CalculateTax(gross-l.DiscountAmount, l.TaxRate, pricesIncludeTax)
+	l.LineTotal = roundCents(l.TaxBase + l.TaxAmount)
+	return nil
+}
+
+// CalculateTotals applies tax to every line and sums the invoice totals
+func (si *SalesInvoice) CalculateTotals() error {
+	si.Subtotal, si.TaxAmount, si.TotalAmount = 0, 0, 0
+	for i := range si.Lines {
+		line := &si.Lines[i]
+		if err := line.ApplyTax(si.PricesIncludeTax); err != nil {
+			return err
+		}
+		si.Subtotal += line.TaxBase
+		si.TaxAmount += line.TaxAmount
+		si.TotalAmount += line.LineTotal
+	}
+
+	si.Subtotal = roundCents(si.Subtotal)
+	si.TaxAmount = roundCents(si.TaxAmount)
+	si.TotalAmount = roundCents(si.TotalAmount)
+	return nil
+}
+
+// CostOfSales returns the inventory cost of the goods on the invoice
+func (si *SalesInvoice) CostOfSales() float64 {
+	var cost float64
+	for _, line := range si.Lines {
+		cost += float64(line.Quantity) * line.UnitCost
+	}
+	return roundCents(cost)
+}
+
+// CanVoid checks if the invoice can still be voided. Invoices with receipts allocated must have
+// their receipts voided first.
+func (si *SalesInvoice) CanVoid() bool {
+	return si.InvoiceStatus == SalesInvoiceStatusIssued && si.PaidAmount == 0
+}
+
+// IsOverdue checks if the invoice is still unpaid after its due date
+func (si *SalesInvoice) IsOverdue(asOf time.Time) bool {
+	return si.InvoiceStatus == SalesInvoiceStatusIssued && si.OutstandingAmount > 0 && daysBetween(si.DueDate, asOf) > 0
+}
@@ -67,12 +67,13 @@ const (
 	ReferenceTypeAdjustment ReferenceType = "adjustment"
 	ReferenceTypeTransfer   ReferenceType = "transfer"
 	ReferenceTypeReturn     ReferenceType = "return"
+	ReferenceTypeLandedCost ReferenceType = "landed_cost"
 )
 
 // IsValid checks if the reference type is valid
 func (r ReferenceType) IsValid() bool {
 	switch r {
-	case ReferenceTypePurchase, ReferenceTypeSales, ReferenceTypeRepair, ReferenceTypeAdjustment, ReferenceTypeTransfer, ReferenceTypeReturn:
+	case ReferenceTypePurchase, ReferenceTypeSales, ReferenceTypeRepair, ReferenceTypeAdjustment, ReferenceTypeTransfer, ReferenceTypeReturn, ReferenceTypeLandedCost:
 		return true
 	default:
 		return false
@@ -3,6 +3,7 @@ package products
 import (
 	"database/sql/driver"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
@@ -54,7 +55,7 @@ func (m *PaymentMethod) Scan(value interface{}) error {
 	return nil
 }
 
-// PaymentStatus represents the status of a payment
+// PaymentStatus represents the settlement status of a supplier invoice
 type PaymentStatus string
 
 const (
@@ -101,222 +102,433 @@ func (s *PaymentStatus) Scan(value interface{}) error {
 	return nil
 }
 
-// SupplierPayment represents a payment to a supplier
-type SupplierPayment struct {
-	PaymentID         int           `json:"payment_id" db:"payment_id"`
+// PaymentVoucherStatus represents the status of a payment voucher
+type PaymentVoucherStatus string
+
+const (
+	PaymentVoucherStatusPosted PaymentVoucherStatus = "posted"
+	PaymentVoucherStatusVoid   PaymentVoucherStatus = "void"
+)
+
+// IsValid checks if the payment voucher status is valid
+func (s PaymentVoucherStatus) IsValid() bool {
+	switch s {
+	case PaymentVoucherStatusPosted, PaymentVoucherStatusVoid:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the payment voucher status
+func (s PaymentVoucherStatus) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for PaymentVoucherStatus
+func (s PaymentVoucherStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for PaymentVoucherStatus
+func (s *PaymentVoucherStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*s = PaymentVoucherStatus(str)
+	case []byte:
+		*s = PaymentVoucherStatus(str)
+	default:
+		return fmt.Errorf("cannot scan %T into PaymentVoucherStatus", value)
+	}
+	return nil
+}
+
+// SupplierInvoice represents an invoice received from a supplier.
+// Paid, discount and outstanding amounts are derived from the allocations of posted payment vouchers
+// and the debit notes credited to the invoice.
+type SupplierInvoice struct {
+	InvoiceID         int           `json:"invoice_id" db:"invoice_id"`
 	SupplierID        int           `json:"supplier_id" db:"supplier_id"`
 	POID              *int          `json:"po_id,omitempty" db:"po_id"`
-	PaymentNumber     string        `json:"payment_number" db:"payment_number"`
+	InvoiceNumber     string        `json:"invoice_number" db:"invoice_number"`
+	InvoiceDate       time.Time     `json:"invoice_date" db:"invoice_date"`
+	DueDate           time.Time     `json:"due_date" db:"due_date"`
+	CurrencyCode      string        `json:"currency_code" db:"currency_code"`
+	ExchangeRate      float64       `json:"exchange_rate" db:"exchange_rate"`
 	InvoiceAmount     float64       `json:"invoice_amount" db:"invoice_amount"`
-	PaymentAmount     float64       `json:"payment_amount" db:"payment_amount"`
+	TaxAmount         float64       `json:"tax_amount" db:"tax_amount"`
+	TaxInvoiceNumber  *string       `json:"tax_invoice_number,omitempty" db:"tax_invoice_number"`
+	PaymentTermID     *int          `json:"payment_term_id,omitempty" db:"payment_term_id"`
+	DiscountPercent   float64       `json:"discount_percent" db:"discount_percent"`
+	DiscountDueDate   *time.Time    `json:"discount_due_date,omitempty" db:"discount_due_date"`
+	PaidAmount        float64       `json:"paid_amount" db:"paid_amount"`
 	DiscountTaken     float64       `json:"discount_taken" db:"discount_taken"`
+	CreditAmount      float64       `json:"credit_amount" db:"credit_amount"`
 	OutstandingAmount float64       `json:"outstanding_amount" db:"outstanding_amount"`
-	InvoiceDate       time.Time     `json:"invoice_date" db:"invoice_date"`
-	PaymentDate       time.Time     `json:"payment_date" db:"payment_date"`
-	DueDate           time.Time     `json:"due_date" db:"due_date"`
-	PaymentMethod     PaymentMethod `json:"payment_method" db:"payment_method"`
-	PaymentReference  *string       `json:"payment_reference,omitempty" db:"payment_reference"`
-	InvoiceNumber     string        `json:"invoice_number" db:"invoice_number"`
-	PaymentStatus     PaymentStatus `json:"payment_status" db:"payment_status"`
+	InvoiceStatus     PaymentStatus `json:"invoice_status" db:"invoice_status"`
 	DaysOverdue       int           `json:"days_overdue" db:"days_overdue"`
 	PenaltyAmount     float64       `json:"penalty_amount" db:"penalty_amount"`
-	ProcessedBy       int           `json:"processed_by" db:"processed_by"`
-	PaymentNotes      *string       `json:"payment_notes,omitempty" db:"payment_notes"`
+	Notes             *string       `json:"notes,omitempty" db:"notes"`
+	CreatedBy         int           `json:"created_by" db:"created_by"`
 	CreatedAt         time.Time     `json:"created_at" db:"created_at"`
 	UpdatedAt         time.Time     `json:"updated_at" db:"updated_at"`
+
+	// Related data
+	Allocations []PaymentAllocation `json:"allocations,omitempty" db:"-"`
 }
 
-// SupplierPaymentListItem represents a simplified supplier payment for list views
-type SupplierPaymentListItem struct {
-	PaymentID         int           `json:"payment_id" db:"payment_id"`
+// SupplierInvoiceListItem represents a simplified supplier invoice for list views
+type SupplierInvoiceListItem struct {
+	InvoiceID         int           `json:"invoice_id" db:"invoice_id"`
 	SupplierID        int           `json:"supplier_id" db:"supplier_id"`
+	SupplierName      string        `json:"supplier_name" db:"supplier_name"`
 	POID              *int          `json:"po_id,omitempty" db:"po_id"`
-	PaymentNumber     string        `json:"payment_number" db:"payment_number"`
-	InvoiceAmount     float64       `json:"invoice_amount" db:"invoice_amount"`
-	PaymentAmount     float64       `json:"payment_amount" db:"payment_amount"`
-	OutstandingAmount float64       `json:"outstanding_amount" db:"outstanding_amount"`
+	InvoiceNumber     string        `json:"invoice_number" db:"invoice_number"`
 	InvoiceDate       time.Time     `json:"invoice_date" db:"invoice_date"`
-	PaymentDate       time.Time     `json:"payment_date" db:"payment_date"`
 	DueDate           time.Time     `json:"due_date" db:"due_date"`
-	PaymentMethod     PaymentMethod `json:"payment_method" db:"payment_method"`
-	InvoiceNumber     string        `json:"invoice_number" db:"invoice_number"`
-	PaymentStatus     PaymentStatus `json:"payment_status" db:"payment_status"`
+	CurrencyCode      string        `json:"currency_code" db:"currency_code"`
+	InvoiceAmount     float64       `json:"invoice_amount" db:"invoice_amount"`
+	OutstandingAmount float64       `json:"outstanding_amount" db:"outstanding_amount"`
+	InvoiceStatus     PaymentStatus `json:"invoice_status" db:"invoice_status"`
 	DaysOverdue       int           `json:"days_overdue" db:"days_overdue"`
 }
 
-// SupplierPaymentCreateRequest represents a request to create a supplier payment
-type SupplierPaymentCreateRequest struct {
-	SupplierID       int           `json:"supplier_id" binding:"required,min=1"`
-	POID             *int          `json:"po_id,omitempty" binding:"omitempty,min=1"`
-	InvoiceAmount    float64       `json:"invoice_amount" binding:"required,min=0"`
-	PaymentAmount    float64       `json:"payment_amount" binding:"required,min=0"`
-	DiscountTaken    float64       `json:"discount_taken" binding:"min=0"`
-	InvoiceDate      time.Time     `json:"invoice_date" binding:"required"`
-	PaymentDate      time.Time     `json:"payment_date" binding:"required"`
-	DueDate          time.Time     `json:"due_date" binding:"required"`
-	PaymentMethod    PaymentMethod `json:"payment_method" binding:"required"`
-	PaymentReference *string       `json:"payment_reference,omitempty" binding:"omitempty,max=100"`
-	InvoiceNumber    string        `json:"invoice_number" binding:"required,max=100"`
-	PaymentNotes     *string       `json:"payment_notes,omitempty"`
-}
-
-// SupplierPaymentUpdateRequest represents a request to update a supplier payment
-type SupplierPaymentUpdateRequest struct {
-	PaymentAmount     *float64       `json:"payment_amount,omitempty" binding:"omitempty,min=0"`
-	DiscountTaken     *float64       `json:"discount_taken,omitempty" binding:"omitempty,min=0"`
-	PaymentDate       *time.Time     `json:"payment_date,omitempty"`
-	DueDate           *time.Time     `json:"due_date,omitempty"`
-	PaymentMethod     *PaymentMethod `json:"payment_method,omitempty"`
-	PaymentReference  *string        `json:"payment_reference,omitempty" binding:"omitempty,max=100"`
-	PaymentStatus     *PaymentStatus `json:"payment_status,omitempty"`
-	PenaltyAmount     *float64       `json:"penalty_amount,omitempty" binding:"omitempty,min=0"`
-	PaymentNotes      *string        `json:"payment_notes,omitempty"`
-}
-
-// SupplierPaymentFilterParams represents filtering parameters for supplier payment queries
-type SupplierPaymentFilterParams struct {
+// SupplierInvoiceCreateRequest represents a request to record a supplier invoice.
+// When raised against a purchase order, the amount defaults to the PO total and the due date to the PO payment due date.
+// TaxAmount is the input VAT on the supplier's tax invoice and is included in InvoiceAmount.
+// The currency defaults to the PO currency; foreign currency invoices are booked at the rate
+// in effect on the invoice date unless an exchange rate is given.
+type SupplierInvoiceCreateRequest struct {
+	SupplierID       int        `json:"supplier_id" binding:"required,min=1"`
+	POID             *int       `json:"po_id,omitempty" binding:"omitempty,min=1"`
+	InvoiceNumber    string     `json:"invoice_number" binding:"required,max=100"`
+	InvoiceDate      time.Time  `json:"invoice_date" binding:"required"`
+	DueDate          *time.Time `json:"due_date,omitempty"`
+	PaymentTermID    *int       `json:"payment_term_id,omitempty" binding:"omitempty,min=1"`
+	CurrencyCode     string     `json:"currency_code,omitempty" binding:"omitempty,len=3"`
+	ExchangeRate     *float64   `json:"exchange_rate,omitempty" binding:"omitempty,gt=0"`
+	InvoiceAmount    float64    `json:"invoice_amount" binding:"min=0"`
+	TaxAmount        float64    `json:"tax_amount" binding:"min=0"`
+	TaxInvoiceNumber *string    `json:"tax_invoice_number,omitempty" binding:"omitempty,max=30"`
+	Notes            *string    `json:"notes,omitempty"`
+}
+
+// SupplierInvoiceUpdateRequest represents a request to update a supplier invoice
+type SupplierInvoiceUpdateRequest struct {
+	InvoiceNumber    *string    `json:"invoice_number,omitempty" binding:"omitempty,max=100"`
+	InvoiceDate      *time.Time `json:"invoice_date,omitempty"`
+	DueDate          *time.Time `json:"due_date,omitempty"`
+	PaymentTermID    *int       `json:"payment_term_id,omitempty" binding:"omitempty,min=1"`
+	ExchangeRate     *float64   `json:"exchange_rate,omitempty" binding:"omitempty,gt=0"`
+	InvoiceAmount    *float64   `json:"invoice_amount,omitempty" binding:"omitempty,gt=0"`
+	TaxAmount        *float64   `json:"tax_amount,omitempty" binding:"omitempty,min=0"`
+	TaxInvoiceNumber *string    `json:"tax_invoice_number,omitempty" binding:"omitempty,max=30"`
+	Notes            *string    `json:"notes,omitempty"`
+}
+
+// SupplierInvoiceFilterParams represents filtering parameters for supplier invoice queries
+type SupplierInvoiceFilterParams struct {
 	SupplierID     *int           `json:"supplier_id,omitempty" form:"supplier_id"`
 	POID           *int           `json:"po_id,omitempty" form:"po_id"`
-	PaymentStatus  *PaymentStatus `json:"payment_status,omitempty" form:"payment_status"`
-	PaymentMethod  *PaymentMethod `json:"payment_method,omitempty" form:"payment_method"`
-	ProcessedBy    *int           `json:"processed_by,omitempty" form:"processed_by"`
+	InvoiceStatus  *PaymentStatus `json:"invoice_status,omitempty" form:"invoice_status"`
 	DateFrom       *time.Time     `json:"date_from,omitempty" form:"date_from"`
 	DateTo         *time.Time     `json:"date_to,omitempty" form:"date_to"`
 	IsOverdue      *bool          `json:"is_overdue,omitempty" form:"is_overdue"`
-	MinAmount      *float64       `json:"min_amount,omitempty" form:"min_amount"`
-	MaxAmount      *float64       `json:"max_amount,omitempty" form:"max_amount"`
+	HasOutstanding *bool          `json:"has_outstanding,omitempty" form:"has_outstanding"`
 	Search         string         `json:"search,omitempty" form:"search"`
 	common.PaginationParams
 }
 
-// CalculateOutstandingAmount calculates the outstanding amount
-func (sp *SupplierPayment) CalculateOutstandingAmount() {
-	sp.OutstandingAmount = sp.InvoiceAmount - sp.PaymentAmount - sp.DiscountTaken
+// PaymentVoucher represents a single payment made to a supplier, such as one bank transfer.
+// A voucher can be allocated across several invoices and an invoice can be settled by several vouchers.
+type PaymentVoucher struct {
+	VoucherID         int                  `json:"voucher_id" db:"voucher_id"`
+	VoucherNumber     string               `json:"voucher_number" db:"voucher_number"`
+	SupplierID        int                  `json:"supplier_id" db:"supplier_id"`
+	PaymentDate       time.Time            `json:"payment_date" db:"payment_date"`
+	PaymentMethod     PaymentMethod        `json:"payment_method" db:"payment_method"`
+	PaymentReference  *string              `json:"payment_reference,omitempty" db:"payment_reference"`
+	CurrencyCode      string               `json:"currency_code" db:"currency_code"`
+	ExchangeRate      float64              `json:"exchange_rate" db:"exchange_rate"`
+	Amount            float64              `json:"amount" db:"amount"`
+	AllocatedAmount   float64              `json:"allocated_amount" db:"allocated_amount"`
+	UnallocatedAmount float64              `json:"unallocated_amount" db:"unallocated_amount"`
+	VoucherStatus     PaymentVoucherStatus `json:"voucher_status" db:"voucher_status"`
+	Notes             *string              `json:"notes,omitempty" db:"notes"`
+	ProcessedBy       int                  `json:"processed_by" db:"processed_by"`
+	CreatedAt         time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at" db:"updated_at"`
+
+	// Related data
+	Allocations []PaymentAllocation `json:"allocations,omitempty" db:"-"`
 }
 
-// UpdateDaysOverdue calculates and updates days overdue
-func (sp *SupplierPayment) UpdateDaysOverdue() {
-	if sp.PaymentStatus == PaymentStatusPaid {
-		sp.DaysOverdue = 0
-		return
-	}
-	
-	now := time.Now()
-	if now.After(sp.DueDate) {
-		sp.DaysOverdue = int(now.Sub(sp.DueDate).Hours() / 24)
-	} else {
-		sp.DaysOverdue = 0
-	}
+// PaymentVoucherListItem represents a simplified payment voucher for list views
+type PaymentVoucherListItem struct {
+	VoucherID         int                  `json:"voucher_id" db:"voucher_id"`
+	VoucherNumber     string               `json:"voucher_number" db:"voucher_number"`
+	SupplierID        int                  `json:"supplier_id" db:"supplier_id"`
+	SupplierName      string               `json:"supplier_name" db:"supplier_name"`
+	PaymentDate       time.Time            `json:"payment_date" db:"payment_date"`
+	PaymentMethod     PaymentMethod        `json:"payment_method" db:"payment_method"`
+	PaymentReference  *string              `json:"payment_reference,omitempty" db:"payment_reference"`
+	CurrencyCode      string               `json:"currency_code" db:"currency_code"`
+	Amount            float64              `json:"amount" db:"amount"`
+	UnallocatedAmount float64              `json:"unallocated_amount" db:"unallocated_amount"`
+	VoucherStatus     PaymentVoucherStatus `json:"voucher_status" db:"voucher_status"`
 }
 
-// UpdatePaymentStatus updates the payment status based on amounts and dates
-func (sp *SupplierPayment) UpdatePaymentStatus() {
-	sp.CalculateOutstandingAmount()
-	sp.UpdateDaysOverdue()
-	
-	// If disputed, don't change status automatically
-	if sp.PaymentStatus == PaymentStatusDisputed {
-		return
-	}
-	
-	if sp.OutstandingAmount <= 0 {
-		sp.PaymentStatus = PaymentStatusPaid
-	} else if sp.PaymentAmount > 0 {
-		if sp.DaysOverdue > 0 {
-			sp.PaymentStatus = PaymentStatusOverdue
-		} else {
-			sp.PaymentStatus = PaymentStatusPartial
-		}
-	} else {
-		if sp.DaysOverdue > 0 {
-			sp.PaymentStatus = PaymentStatusOverdue
-		} else {
-			sp.PaymentStatus = PaymentStatusPending
-		}
-	}
+// PaymentAllocation records how much of a payment voucher settles a supplier invoice.
+// FXGainLoss is the realized exchange gain, or loss when negative, in the base currency.
+type PaymentAllocation struct {
+	AllocationID    int       `json:"allocation_id" db:"allocation_id"`
+	VoucherID       int       `json:"voucher_id" db:"voucher_id"`
+	InvoiceID       int       `json:"invoice_id" db:"invoice_id"`
+	AllocatedAmount float64   `json:"allocated_amount" db:"allocated_amount"`
+	DiscountAmount  float64   `json:"discount_amount" db:"discount_amount"`
+	FXGainLoss      float64   `json:"fx_gain_loss" db:"fx_gain_loss"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+
+	// Related data
+	VoucherNumber    string               `json:"voucher_number,omitempty" db:"voucher_number"`
+	InvoiceNumber    string               `json:"invoice_number,omitempty" db:"invoice_number"`
+	PaymentDate      time.Time            `json:"payment_date" db:"payment_date"`
+	PaymentMethod    PaymentMethod        `json:"payment_method,omitempty" db:"payment_method"`
+	PaymentReference *string              `json:"payment_reference,omitempty" db:"payment_reference"`
+	VoucherStatus    PaymentVoucherStatus `json:"voucher_status,omitempty" db:"voucher_status"`
 }
 
-// IsFullyPaid checks if the payment is fully paid
-func (sp *SupplierPayment) IsFullyPaid() bool {
-	return sp.PaymentStatus == PaymentStatusPaid || sp.OutstandingAmount <= 0
+// PaymentAllocationRequest represents an amount of a payment voucher applied to one invoice.
+// Set TakeDiscount to apply the early payment discount still available on the payment date;
+// with no amount given the rest of the invoice is paid.
+type PaymentAllocationRequest struct {
+	InvoiceID      int     `json:"invoice_id" binding:"required,min=1"`
+	Amount         float64 `json:"amount" binding:"min=0"`
+	DiscountAmount float64 `json:"discount_amount" binding:"min=0"`
+	TakeDiscount   bool    `json:"take_discount,omitempty"`
+	// InvoiceRate is the exchange rate the invoice was booked at, filled in from the invoice
+	InvoiceRate float64 `json:"-"`
 }
 
-// IsOverdue checks if the payment is overdue
-func (sp *SupplierPayment) IsOverdue() bool {
-	return sp.PaymentStatus == PaymentStatusOverdue || sp.DaysOverdue > 0
+// PaymentVoucherCreateRequest represents a request to record a payment to a supplier.
+// Foreign currency payments are converted at the rate in effect on the payment date unless
+// an exchange rate is given, such as the rate the bank applied.
+type PaymentVoucherCreateRequest struct {
+	SupplierID       int                        `json:"supplier_id" binding:"required,min=1"`
+	PaymentDate      *time.Time                 `json:"payment_date,omitempty"`
+	PaymentMethod    PaymentMethod              `json:"payment_method" binding:"required"`
+	PaymentReference *string                    `json:"payment_reference,omitempty" binding:"omitempty,max=100"`
+	CurrencyCode     string                     `json:"currency_code,omitempty" binding:"omitempty,len=3"`
+	ExchangeRate     *float64                   `json:"exchange_rate,omitempty" binding:"omitempty,gt=0"`
+	Amount           float64                    `json:"amount" binding:"required,gt=0"`
+	Notes            *string                    `json:"notes,omitempty"`
+	Allocations      []PaymentAllocationRequest `json:"allocations,omitempty" binding:"omitempty,dive"`
 }
 
-// CanAddPayment checks if additional payment can be added
-func (sp *SupplierPayment) CanAddPayment() bool {
-	return !sp.IsFullyPaid() && sp.PaymentStatus != PaymentStatusDisputed
+// PaymentVoucherUpdateRequest represents a request to correct the details of a payment voucher
+type PaymentVoucherUpdateRequest struct {
+	PaymentDate      *time.Time     `json:"payment_date,omitempty"`
+	PaymentMethod    *PaymentMethod `json:"payment_method,omitempty"`
+	PaymentReference *string        `json:"payment_reference,omitempty" binding:"omitempty,max=100"`
+	Notes            *string        `json:"notes,omitempty"`
 }
 
-// AddPayment adds a payment amount and updates status
-func (sp *SupplierPayment) AddPayment(amount float64, method PaymentMethod, reference *string) {
-	if !sp.CanAddPayment() || amount <= 0 {
-		return
-	}
-	
-	// Ensure we don't overpay
-	maxPayable := sp.InvoiceAmount - sp.PaymentAmount - sp.DiscountTaken
-	if amount > maxPayable {
-		amount = maxPayable
+// PaymentVoucherAllocateRequest represents a request to allocate the unallocated part of a payment voucher
+type PaymentVoucherAllocateRequest struct {
+	Allocations []PaymentAllocationRequest `json:"allocations" binding:"required,min=1,dive"`
+}
+
+// PaymentVoucherFilterParams represents filtering parameters for payment voucher queries
+type PaymentVoucherFilterParams struct {
+	SupplierID     *int                  `json:"supplier_id,omitempty" form:"supplier_id"`
+	InvoiceID      *int                  `json:"invoice_id,omitempty" form:"invoice_id"`
+	PaymentMethod  *PaymentMethod        `json:"payment_method,omitempty" form:"payment_method"`
+	VoucherStatus  *PaymentVoucherStatus `json:"voucher_status,omitempty" form:"voucher_status"`
+	DateFrom       *time.Time            `json:"date_from,omitempty" form:"date_from"`
+	DateTo         *time.Time            `json:"date_to,omitempty" form:"date_to"`
+	HasUnallocated *bool                 `json:"has_unallocated,omitempty" form:"has_unallocated"`
+	Search         string                `json:"search,omitempty" form:"search"`
+	common.PaginationParams
+}
+
+// CalculateOutstandingAmount calculates the outstanding amount from settlements
+func (si *SupplierInvoice) CalculateOutstandingAmount() {
+	si.OutstandingAmount = si.InvoiceAmount - si.PaidAmount - si.DiscountTaken - si.CreditAmount
+	if si.OutstandingAmount < 0 {
+		si.OutstandingAmount = 0
 	}
-	
-	sp.PaymentAmount += amount
-	sp.PaymentMethod = method
-	sp.PaymentReference = reference
-	sp.PaymentDate = time.Now()
-	sp.UpdatePaymentStatus()
-}
-
-// CalculatePenalty calculates penalty based on days overdue and rate
-func (sp *SupplierPayment) CalculatePenalty(dailyPenaltyRate float64) {
-	if sp.DaysOverdue <= 0 || sp.IsFullyPaid() {
-		sp.PenaltyAmount = 0
+}
+
+// GetSettledAmount returns the amount settled by payments, discounts and credits
+func (si *SupplierInvoice) GetSettledAmount() float64 {
+	return si.PaidAmount + si.DiscountTaken + si.CreditAmount
+}
+
+// UpdateDaysOverdue calculates days overdue at the given time
+func (si *SupplierInvoice) UpdateDaysOverdue(at time.Time) {
+	if si.OutstandingAmount <= 0 || !at.After(si.DueDate) {
+		si.DaysOverdue = 0
 		return
 	}
-	
-	sp.PenaltyAmount = sp.OutstandingAmount * (dailyPenaltyRate / 100) * float64(sp.DaysOverdue)
+	si.DaysOverdue = int(at.Sub(si.DueDate).Hours() / 24)
 }
 
-// GetPaymentProgressPercentage returns the payment progress as percentage
-func (sp *SupplierPayment) GetPaymentProgressPercentage() float64 {
-	if sp.InvoiceAmount == 0 {
-		return 0
+// UpdateStatus derives the invoice status from its settlements and due date.
+// Disputed invoices keep their status until the dispute is resolved.
+func (si *SupplierInvoice) UpdateStatus(at time.Time) {
+	si.CalculateOutstandingAmount()
+	si.UpdateDaysOverdue(at)
+
+	if si.InvoiceStatus == PaymentStatusDisputed {
+		return
 	}
-	return ((sp.PaymentAmount + sp.DiscountTaken) / sp.InvoiceAmount) * 100
-}
-
-// GetPaymentDescription returns a human-readable description of the payment
-func (sp *SupplierPayment) GetPaymentDescription() string {
-	switch sp.PaymentStatus {
-	case PaymentStatusPending:
-		return "Payment pending"
-	case PaymentStatusPartial:
-		percentage := sp.GetPaymentProgressPercentage()
-		return fmt.Sprintf("Partially paid (%.1f%%)", percentage)
-	case PaymentStatusPaid:
-		return "Fully paid"
-	case PaymentStatusOverdue:
-		return fmt.Sprintf("Overdue by %d days", sp.DaysOverdue)
-	case PaymentStatusDisputed:
-		return "Payment disputed"
+
+	switch {
+	case si.OutstandingAmount <= 0:
+		si.InvoiceStatus = PaymentStatusPaid
+	case at.After(si.DueDate):
+		si.InvoiceStatus = PaymentStatusOverdue
+	case si.GetSettledAmount() > 0:
+		si.InvoiceStatus = PaymentStatusPartial
 	default:
-		return string(sp.PaymentStatus)
+		si.InvoiceStatus = PaymentStatusPending
+	}
+}
+
+// ApplyPaymentTerm sets the due date and early payment discount window from payment terms
+func (si *SupplierInvoice) ApplyPaymentTerm(term *PaymentTerm) {
+	si.PaymentTermID = &term.TermID
+	si.DueDate = term.DueDate(si.InvoiceDate)
+	si.DiscountPercent = 0
+	si.DiscountDueDate = nil
+	if term.HasDiscount() {
+		si.DiscountPercent = term.DiscountPercent
+		si.DiscountDueDate = term.DiscountDueDate(si.InvoiceDate)
 	}
 }
 
-// GetRemainingAmount returns the amount still to be paid
-func (sp *SupplierPayment) GetRemainingAmount() float64 {
-	remaining := sp.InvoiceAmount - sp.PaymentAmount - sp.DiscountTaken
-	if remaining < 0 {
+// AvailableDiscount returns the early payment discount that can still be taken for a payment
+// made on paymentDate. The discount window includes the whole discount due date.
+func (si *SupplierInvoice) AvailableDiscount(paymentDate time.Time) float64 {
+	if si.DiscountPercent <= 0 || si.DiscountDueDate == nil || daysBetween(*si.DiscountDueDate, paymentDate) > 0 {
 		return 0
 	}
-	return remaining
+
+	discount := math.Round(si.InvoiceAmount*si.DiscountPercent) / 100
+	discount -= si.DiscountTaken
+	if discount > si.OutstandingAmount {
+		discount = si.OutstandingAmount
+	}
+	if discount < 0 {
+		return 0
+	}
+	return math.Round(discount*100) / 100
+}
+
+// IsFullyPaid checks if the invoice is fully settled
+func (si *SupplierInvoice) IsFullyPaid() bool {
+	return si.OutstandingAmount <= 0
+}
+
+// CanAllocate checks if payments can be allocated to the invoice
+func (si *SupplierInvoice) CanAllocate() bool {
+	return !si.IsFullyPaid() && si.InvoiceStatus != PaymentStatusDisputed
+}
+
+// CalculateUnallocatedAmount calculates the part of the voucher not yet allocated to invoices
+func (pv *PaymentVoucher) CalculateUnallocatedAmount() {
+	pv.UnallocatedAmount = pv.Amount - pv.AllocatedAmount
 }
 
-// GetTotalAmountDue returns total amount due including penalties
-func (sp *SupplierPayment) GetTotalAmountDue() float64 {
-	return sp.GetRemainingAmount() + sp.PenaltyAmount
-}
\ No newline at end of file
+// IsVoid checks if the payment voucher has been voided
+func (pv *PaymentVoucher) IsVoid() bool {
+	return pv.VoucherStatus == PaymentVoucherStatusVoid
+}
+
+// ApplyEarlyPaymentDiscounts fills in the discount of allocations that take the early payment
+// discount available on the voucher payment date. Allocations without an amount pay the rest of the invoice.
+func (pv *PaymentVoucher) ApplyEarlyPaymentDiscounts(allocations []PaymentAllocationRequest, invoices map[int]*SupplierInvoice) {
+	for i := range allocations {
+		allocation := &allocations[i]
+		invoice, ok := invoices[allocation.InvoiceID]
+		if !allocation.TakeDiscount || !ok {
+			continue
+		}
+
+		allocation.DiscountAmount = invoice.AvailableDiscount(pv.PaymentDate)
+		if allocation.Amount == 0 {
+			allocation.Amount = math.Round((invoice.OutstandingAmount-allocation.DiscountAmount)*100) / 100
+		}
+	}
+}
+
+// ApplyInvoiceRates records on each allocation the exchange rate its invoice was booked at,
+// so the realized exchange difference of the payment can be derived
+func (pv *PaymentVoucher) ApplyInvoiceRates(allocations []PaymentAllocationRequest, invoices map[int]*SupplierInvoice) {
+	for i := range allocations {
+		if invoice, ok := invoices[allocations[i].InvoiceID]; ok {
+			allocations[i].InvoiceRate = invoice.ExchangeRate
+		}
+	}
+}
+
+// ExchangeDifference returns the realized exchange gain, or loss when negative, of paying the
+// allocated amount at the payment rate instead of the rate the invoice was booked at.
+// Discounts settle the invoice without payment and realize no difference.
+func (a *PaymentAllocationRequest) ExchangeDifference(paymentRate float64) float64 {
+	return ExchangeDifference(a.Amount, a.InvoiceRate, paymentRate)
+}
+
+// ValidateAllocations checks allocations against the unallocated amount of the voucher and
+// the outstanding balance of each invoice. Invoices must be keyed by invoice ID.
+func (pv *PaymentVoucher) ValidateAllocations(allocations []PaymentAllocationRequest, invoices map[int]*SupplierInvoice) error {
+	if pv.IsVoid() {
+		return fmt.Errorf("cannot allocate a void payment voucher")
+	}
+
+	var total float64
+	requested := make(map[int]float64, len(allocations))
+	discounts := make(map[int]float64, len(allocations))
+	for _, allocation := range allocations {
+		if allocation.Amount <= 0 && allocation.DiscountAmount <= 0 {
+			return fmt.Errorf("allocation to invoice %d must have an amount or a discount", allocation.InvoiceID)
+		}
+
+		invoice, ok := invoices[allocation.InvoiceID]
+		if !ok {
+			return fmt.Errorf("supplier invoice %d not found", allocation.InvoiceID)
+		}
+		if invoice.SupplierID != pv.SupplierID {
+			return fmt.Errorf("supplier invoice %s does not belong to the voucher supplier", invoice.InvoiceNumber)
+		}
+		if invoice.CurrencyCode != pv.CurrencyCode {
+			return fmt.Errorf("supplier invoice %s is in %s, not the voucher currency %s",
+				invoice.InvoiceNumber, invoice.CurrencyCode, pv.CurrencyCode)
+		}
+		if !invoice.CanAllocate() {
+			return fmt.Errorf("supplier invoice %s cannot take payments in status %s", invoice.InvoiceNumber, invoice.InvoiceStatus)
+		}
+
+		discounts[allocation.InvoiceID] += allocation.DiscountAmount
+		if discounts[allocation.InvoiceID] > invoice.AvailableDiscount(pv.PaymentDate)+0.005 {
+			return fmt.Errorf("discount on invoice %s exceeds the early payment discount available on %s",
+				invoice.InvoiceNumber, pv.PaymentDate.Format("2006-01-02"))
+		}
+
+		requested[allocation.InvoiceID] += allocation.Amount + allocation.DiscountAmount
+		if requested[allocation.InvoiceID] > invoice.OutstandingAmount+0.005 {
+			return fmt.Errorf("allocation to invoice %s exceeds its outstanding amount %.2f",
+				invoice.InvoiceNumber, invoice.OutstandingAmount)
+		}
+		total += allocation.Amount
+	}
+
+	if total > pv.Amount-pv.AllocatedAmount+0.005 {
+		return fmt.Errorf("allocations of %.2f exceed the unallocated voucher amount %.2f", total, pv.Amount-pv.AllocatedAmount)
+	}
+
+	return nil
+}
@@ -0,0 +1,243 @@
+package products
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+)
+
+// DefaultCurrency is the currency purchase orders are raised in unless another is given
+const DefaultCurrency = BaseCurrency
+
+// PriceHistorySource represents where a recorded supplier price came from
+type PriceHistorySource string
+
+const (
+	PriceHistorySourcePriceList     PriceHistorySource = "price_list"
+	PriceHistorySourcePurchaseOrder PriceHistorySource = "purchase_order"
+)
+
+// IsValid checks if the price history source is valid
+func (s PriceHistorySource) IsValid() bool {
+	switch s {
+	case PriceHistorySourcePriceList, PriceHistorySourcePurchaseOrder:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation of the price history source
+func (s PriceHistorySource) String() string {
+	return string(s)
+}
+
+// Value implements the driver.Valuer interface for PriceHistorySource
+func (s PriceHistorySource) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for PriceHistorySource
+func (s *PriceHistorySource) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch str := value.(type) {
+	case string:
+		*s = PriceHistorySource(str)
+	case []byte:
+		*s = PriceHistorySource(str)
+	default:
+		return fmt.Errorf("cannot scan %T into PriceHistorySource", value)
+	}
+	return nil
+}
+
+// SupplierPriceList represents a supplier's quoted price for a product over a validity period
+type SupplierPriceList struct {
+	PriceListID          int        `json:"price_list_id" db:"price_list_id"`
+	SupplierID           int        `json:"supplier_id" db:"supplier_id"`
+	ProductID            int        `json:"product_id" db:"product_id"`
+	Currency             string     `json:"currency" db:"currency"`
+	UnitPrice            float64    `json:"unit_price" db:"unit_price"`
+	MinimumOrderQuantity int        `json:"minimum_order_quantity" db:"minimum_order_quantity"`
+	ValidFrom            time.Time  `json:"valid_from" db:"valid_from"`
+	ValidTo              *time.Time `json:"valid_to,omitempty" db:"valid_to"`
+	IsActive             bool       `json:"is_active" db:"is_active"`
+	Notes                *string    `json:"notes,omitempty" db:"notes"`
+	CreatedBy            int        `json:"created_by" db:"created_by"`
+	CreatedAt            time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Related data
+	PriceBreaks []SupplierPriceBreak `json:"price_breaks,omitempty" db:"-"`
+}
+
+// SupplierPriceBreak represents a lower unit price from a minimum order quantity upwards
+type SupplierPriceBreak struct {
+	PriceBreakID int     `json:"price_break_id" db:"price_break_id"`
+	PriceListID  int     `json:"price_list_id" db:"price_list_id"`
+	MinQuantity  int     `json:"min_quantity" db:"min_quantity"`
+	UnitPrice    float64 `json:"unit_price" db:"unit_price"`
+}
+
+// SupplierPriceListListItem represents a simplified price list entry for list views
+type SupplierPriceListListItem struct {
+	PriceListID          int        `json:"price_list_id" db:"price_list_id"`
+	SupplierID           int        `json:"supplier_id" db:"supplier_id"`
+	SupplierName         string     `json:"supplier_name" db:"supplier_name"`
+	ProductID            int        `json:"product_id" db:"product_id"`
+	ProductName          string     `json:"product_name" db:"product_name"`
+	Currency             string     `json:"currency" db:"currency"`
+	UnitPrice            float64    `json:"unit_price" db:"unit_price"`
+	MinimumOrderQuantity int        `json:"minimum_order_quantity" db:"minimum_order_quantity"`
+	ValidFrom            time.Time  `json:"valid_from" db:"valid_from"`
+	ValidTo              *time.Time `json:"valid_to,omitempty" db:"valid_to"`
+	IsActive             bool       `json:"is_active" db:"is_active"`
+	PriceBreakCount      int        `json:"price_break_count" db:"price_break_count"`
+}
+
+// SupplierPriceBreakRequest represents a price break in a price list request
+type SupplierPriceBreakRequest struct {
+	MinQuantity int     `json:"min_quantity" binding:"required,min=1"`
+	UnitPrice   float64 `json:"unit_price" binding:"required,gt=0"`
+}
+
+// SupplierPriceListCreateRequest represents a request to create a supplier price list entry
+type SupplierPriceListCreateRequest struct {
+	SupplierID           int                         `json:"supplier_id" binding:"required,min=1"`
+	ProductID            int                         `json:"product_id" binding:"required,min=1"`
+	Currency             string                      `json:"currency,omitempty" binding:"omitempty,len=3"`
+	UnitPrice            float64                     `json:"unit_price" binding:"required,gt=0"`
+	MinimumOrderQuantity int                         `json:"minimum_order_quantity" binding:"min=0"`
+	ValidFrom            time.Time                   `json:"valid_from" binding:"required"`
+	ValidTo              *time.Time                  `json:"valid_to,omitempty"`
+	Notes                *string                     `json:"notes,omitempty"`
+	PriceBreaks          []SupplierPriceBreakRequest `json:"price_breaks,omitempty" binding:"omitempty,dive"`
+}
+
+// SupplierPriceListUpdateRequest represents a request to update a supplier price list entry.
+// PriceBreaks replaces all existing breaks when provided.
+type SupplierPriceListUpdateRequest struct {
+	UnitPrice            *float64                     `json:"unit_price,omitempty" binding:"omitempty,gt=0"`
+	MinimumOrderQuantity *int                         `json:"minimum_order_quantity,omitempty" binding:"omitempty,min=0"`
+	ValidFrom            *time.Time                   `json:"valid_from,omitempty"`
+	ValidTo              *time.Time                   `json:"valid_to,omitempty"`
+	IsActive             *bool                        `json:"is_active,omitempty"`
+	Notes                *string                      `json:"notes,omitempty"`
+	PriceBreaks          *[]SupplierPriceBreakRequest `json:"price_breaks,omitempty" binding:"omitempty,dive"`
+}
+
+// SupplierPriceListFilterParams represents filtering parameters for supplier price list queries
+type SupplierPriceListFilterParams struct {
+	SupplierID *int       `json:"supplier_id,omitempty" form:"supplier_id"`
+	ProductID  *int       `json:"product_id,omitempty" form:"product_id"`
+	Currency   string     `json:"currency,omitempty" form:"currency"`
+	IsActive   *bool      `json:"is_active,omitempty" form:"is_active"`
+	ValidOn    *time.Time `json:"valid_on,omitempty" form:"valid_on"`
+	Search     string     `json:"search,omitempty" form:"search"`
+	common.PaginationParams
+}
+
+// SupplierPriceLookupParams represents a request to resolve the effective supplier price for an order quantity
+type SupplierPriceLookupParams struct {
+	SupplierID int    `json:"supplier_id" form:"supplier_id" binding:"required,min=1"`
+	ProductID  int    `json:"product_id" form:"product_id" binding:"required,min=1"`
+	Quantity   int    `json:"quantity" form:"quantity" binding:"omitempty,min=1"`
+	Currency   string `json:"currency,omitempty" form:"currency" binding:"omitempty,len=3"`
+}
+
+// SupplierPriceLookup represents the effective supplier price for an order quantity
+type SupplierPriceLookup struct {
+	PriceListID          int     `json:"price_list_id"`
+	SupplierID           int     `json:"supplier_id"`
+	ProductID            int     `json:"product_id"`
+	Currency             string  `json:"currency"`
+	Quantity             int     `json:"quantity"`
+	UnitPrice            float64 `json:"unit_price"`
+	MinimumOrderQuantity int     `json:"minimum_order_quantity"`
+	BelowMinimum         bool    `json:"below_minimum"`
+}
+
+// SupplierPriceHistory records a price quoted or paid for a product by a supplier
+type SupplierPriceHistory struct {
+	HistoryID   int                `json:"history_id" db:"history_id"`
+	SupplierID  int                `json:"supplier_id" db:"supplier_id"`
+	ProductID   int                `json:"product_id" db:"product_id"`
+	Currency    string             `json:"currency" db:"currency"`
+	UnitPrice   float64            `json:"unit_price" db:"unit_price"`
+	Quantity    *int               `json:"quantity,omitempty" db:"quantity"`
+	Source      PriceHistorySource `json:"source" db:"source"`
+	ReferenceID int                `json:"reference_id" db:"reference_id"`
+	RecordedAt  time.Time          `json:"recorded_at" db:"recorded_at"`
+}
+
+// SupplierPriceHistoryFilterParams represents filtering parameters for supplier price history queries
+type SupplierPriceHistoryFilterParams struct {
+	SupplierID int                 `json:"supplier_id" form:"supplier_id" binding:"required,min=1"`
+	ProductID  *int                `json:"product_id,omitempty" form:"product_id"`
+	Source     *PriceHistorySource `json:"source,omitempty" form:"source"`
+	DateFrom   *time.Time          `json:"date_from,omitempty" form:"date_from"`
+	DateTo     *time.Time          `json:"date_to,omitempty" form:"date_to"`
+	common.PaginationParams
+}
+
+// PurchaseOrderLineItemResult represents a created PO line together with its price list check
+type PurchaseOrderLineItemResult struct {
+	Detail       *PurchaseOrderDetail `json:"detail"`
+	PriceListID  *int                 `json:"price_list_id,omitempty"`
+	ListUnitCost *float64             `json:"list_unit_cost,omitempty"`
+	Warnings     []string             `json:"warnings,omitempty"`
+}
+
+// IsValidOn checks if the price list applies at the given time
+func (pl *SupplierPriceList) IsValidOn(at time.Time) bool {
+	if !pl.IsActive || at.Before(pl.ValidFrom) {
+		return false
+	}
+	return pl.ValidTo == nil || !at.After(*pl.ValidTo)
+}
+
+// GetUnitPrice returns the unit price for an order quantity, applying the highest price break reached
+func (pl *SupplierPriceList) GetUnitPrice(quantity int) float64 {
+	price := pl.UnitPrice
+	bestMin := 0
+	for _, priceBreak := range pl.PriceBreaks {
+		if quantity >= priceBreak.MinQuantity && priceBreak.MinQuantity > bestMin {
+			price = priceBreak.UnitPrice
+			bestMin = priceBreak.MinQuantity
+		}
+	}
+	return price
+}
+
+// SetPriceBreaks validates and replaces the price breaks, keeping them ordered by quantity
+func (pl *SupplierPriceList) SetPriceBreaks(requests []SupplierPriceBreakRequest) error {
+	seen := make(map[int]bool, len(requests))
+	breaks := make([]SupplierPriceBreak, 0, len(requests))
+	for _, req := range requests {
+		if seen[req.MinQuantity] {
+			return fmt.Errorf("duplicate price break for quantity %d", req.MinQuantity)
+		}
+		seen[req.MinQuantity] = true
+		breaks = append(breaks, SupplierPriceBreak{
+			PriceListID: pl.PriceListID,
+			MinQuantity: req.MinQuantity,
+			UnitPrice:   req.UnitPrice,
+		})
+	}
+	sort.Slice(breaks, func(i, j int) bool { return breaks[i].MinQuantity < breaks[j].MinQuantity })
+	pl.PriceBreaks = breaks
+	return nil
+}
+
+// Validate checks the validity window of the price list
+func (pl *SupplierPriceList) Validate() error {
+	if pl.ValidTo != nil && pl.ValidTo.Before(pl.ValidFrom) {
+		return fmt.Errorf("price list validity end must not be before its start")
+	}
+	return nil
+}
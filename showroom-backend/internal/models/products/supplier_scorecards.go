@@ -0,0 +1,152 @@
+package products
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Weights of each metric in the overall supplier score
+const (
+	ScorecardWeightOnTime  = 0.4
+	ScorecardWeightFill    = 0.3
+	ScorecardWeightQuality = 0.3
+)
+
+// SupplierScorecardParams represents the evaluation period of a supplier scorecard.
+// Receipts are counted by receipt date and ordered quantities by PO date.
+type SupplierScorecardParams struct {
+	DateFrom *time.Time `json:"date_from,omitempty" form:"date_from" time_format:"2006-01-02"`
+	DateTo   *time.Time `json:"date_to,omitempty" form:"date_to" time_format:"2006-01-02"`
+}
+
+// SupplierScorecardCompareParams represents a request to compare suppliers over a period
+type SupplierScorecardCompareParams struct {
+	SupplierIDs []int `json:"supplier_ids,omitempty" form:"supplier_ids"`
+	SupplierScorecardParams
+}
+
+// Validate checks the evaluation period
+func (p *SupplierScorecardParams) Validate() error {
+	if p.DateFrom != nil && p.DateTo != nil && p.DateTo.Before(*p.DateFrom) {
+		return fmt.Errorf("date_to must not be before date_from")
+	}
+	return nil
+}
+
+// SupplierPerformanceMetrics holds the raw delivery and quality counts of a supplier over a period
+type SupplierPerformanceMetrics struct {
+	SupplierID         int     `json:"supplier_id" db:"supplier_id"`
+	SupplierCode       string  `json:"supplier_code" db:"supplier_code"`
+	SupplierName       string  `json:"supplier_name" db:"supplier_name"`
+	PurchaseOrderCount int     `json:"purchase_order_count" db:"purchase_order_count"`
+	ReceiptCount       int     `json:"receipt_count" db:"receipt_count"`
+	ScheduledReceipts  int     `json:"scheduled_receipts" db:"scheduled_receipts"`
+	OnTimeReceipts     int     `json:"on_time_receipts" db:"on_time_receipts"`
+	QuantityOrdered    int     `json:"quantity_ordered" db:"quantity_ordered"`
+	QuantityFilled     int     `json:"quantity_filled" db:"quantity_filled"`
+	QuantityReceived   int     `json:"quantity_received" db:"quantity_received"`
+	QuantityRejected   int     `json:"quantity_rejected" db:"quantity_rejected"`
+	DefectiveLines     int     `json:"defective_lines" db:"defective_lines"`
+	LeadTimeSamples    int     `json:"lead_time_samples" db:"lead_time_samples"`
+	TotalLeadTimeDays  float64 `json:"total_lead_time_days" db:"total_lead_time_days"`
+	TotalReceivedValue float64 `json:"total_received_value" db:"total_received_value"`
+}
+
+// SupplierScorecard represents the computed performance of a supplier over a period.
+// Rates are fractions between 0 and 1 and are nil when there is nothing to measure.
+type SupplierScorecard struct {
+	SupplierPerformanceMetrics
+	PeriodFrom           *time.Time `json:"period_from,omitempty"`
+	PeriodTo             *time.Time `json:"period_to,omitempty"`
+	OnTimeDeliveryRate   *float64   `json:"on_time_delivery_rate,omitempty"`
+	FillRate             *float64   `json:"fill_rate,omitempty"`
+	QualityRejectionRate *float64   `json:"quality_rejection_rate,omitempty"`
+	AverageLeadTimeDays  *float64   `json:"average_lead_time_days,omitempty"`
+	OverallScore         float64    `json:"overall_score"`
+	Rank                 int        `json:"rank"`
+	RankedOutOf          int        `json:"ranked_out_of"`
+}
+
+// SupplierScorecardComparison ranks suppliers against each other over the same period
+type SupplierScorecardComparison struct {
+	PeriodFrom *time.Time          `json:"period_from,omitempty"`
+	PeriodTo   *time.Time          `json:"period_to,omitempty"`
+	Scorecards []SupplierScorecard `json:"scorecards"`
+}
+
+// NewSupplierScorecard computes the rates and overall score from raw supplier metrics
+func NewSupplierScorecard(metrics SupplierPerformanceMetrics) SupplierScorecard {
+	scorecard := SupplierScorecard{SupplierPerformanceMetrics: metrics}
+
+	if metrics.ScheduledReceipts > 0 {
+		rate := roundRate(float64(metrics.OnTimeReceipts) / float64(metrics.ScheduledReceipts))
+		scorecard.OnTimeDeliveryRate = &rate
+	}
+	if metrics.QuantityOrdered > 0 {
+		rate := roundRate(math.Min(float64(metrics.QuantityFilled)/float64(metrics.QuantityOrdered), 1))
+		scorecard.FillRate = &rate
+	}
+	if metrics.QuantityReceived > 0 {
+		rate := roundRate(float64(metrics.QuantityRejected) / float64(metrics.QuantityReceived))
+		scorecard.QualityRejectionRate = &rate
+	}
+	if metrics.LeadTimeSamples > 0 {
+		days := math.Round(metrics.TotalLeadTimeDays/float64(metrics.LeadTimeSamples)*10) / 10
+		scorecard.AverageLeadTimeDays = &days
+	}
+
+	scorecard.OverallScore = scorecard.calculateOverallScore()
+	return scorecard
+}
+
+// calculateOverallScore weighs the available rates into a score out of 100.
+// Metrics without data are left out and the remaining weights are rescaled.
+func (s *SupplierScorecard) calculateOverallScore() float64 {
+	var score, weight float64
+	if s.OnTimeDeliveryRate != nil {
+		score += *s.OnTimeDeliveryRate * ScorecardWeightOnTime
+		weight += ScorecardWeightOnTime
+	}
+	if s.FillRate != nil {
+		score += *s.FillRate * ScorecardWeightFill
+		weight += ScorecardWeightFill
+	}
+	if s.QualityRejectionRate != nil {
+		score += (1 - *s.QualityRejectionRate) * ScorecardWeightQuality
+		weight += ScorecardWeightQuality
+	}
+	if weight == 0 {
+		return 0
+	}
+	return math.Round(score/weight*10000) / 100
+}
+
+// RankSupplierScorecards orders scorecards by overall score and assigns ranks.
+// Ties share a rank and are ordered by shorter lead time, then by supplier name.
+func RankSupplierScorecards(scorecards []SupplierScorecard) {
+	sort.SliceStable(scorecards, func(i, j int) bool {
+		a, b := scorecards[i], scorecards[j]
+		if a.OverallScore != b.OverallScore {
+			return a.OverallScore > b.OverallScore
+		}
+		if a.AverageLeadTimeDays != nil && b.AverageLeadTimeDays != nil && *a.AverageLeadTimeDays != *b.AverageLeadTimeDays {
+			return *a.AverageLeadTimeDays < *b.AverageLeadTimeDays
+		}
+		return a.SupplierName < b.SupplierName
+	})
+
+	for i := range scorecards {
+		scorecards[i].RankedOutOf = len(scorecards)
+		if i > 0 && scorecards[i].OverallScore == scorecards[i-1].OverallScore {
+			scorecards[i].Rank = scorecards[i-1].Rank
+		} else {
+			scorecards[i].Rank = i + 1
+		}
+	}
+}
+
+func roundRate(rate float64) float64 {
+	return math.Round(rate*10000) / 10000
+}
@@ -0,0 +1,229 @@
+package products
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+)
+
+// TaxCode represents a VAT rate that can be applied to purchase and sales lines.
+// Lines keep a snapshot of the rate, so changing a code only affects new documents.
+type TaxCode struct {
+	TaxCodeID int       `json:"tax_code_id" db:"tax_code_id"`
+	Code      string    `json:"code" db:"code"`
+	Name      string    `json:"name" db:"name"`
+	Rate      float64   `json:"rate" db:"rate"`
+	IsActive  bool      `json:"is_active" db:"is_active"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// TaxCodeCreateRequest represents a request to create a tax code. Rate is a percentage.
+type TaxCodeCreateRequest struct {
+	Code string  `json:"code" binding:"required,max=20"`
+	Name string  `json:"name" binding:"required,max=100"`
+	Rate float64 `json:"rate" binding:"min=0,max=100"`
+}
+
+// TaxCodeUpdateRequest represents a request to update a tax code
+type TaxCodeUpdateRequest struct {
+	Name     *string  `json:"name,omitempty" binding:"omitempty,max=100"`
+	Rate     *float64 `json:"rate,omitempty" binding:"omitempty,min=0,max=100"`
+	IsActive *bool    `json:"is_active,omitempty"`
+}
+
+// TaxCodeFilterParams represents filtering parameters for tax code queries
+type TaxCodeFilterParams struct {
+	IsActive *bool  `json:"is_active,omitempty" form:"is_active"`
+	Search   string `json:"search,omitempty" form:"search"`
+	common.PaginationParams
+}
+
+// CalculateTax splits a line amount into its tax base and tax at ratePercent.
+// With pricesIncludeTax the amount already contains the tax, otherwise the tax is added on top.
+func CalculateTax(amount, ratePercent float64, pricesIncludeTax bool) (base, tax float64) {
+	amount = roundCents(amount)
+	if ratePercent <= 0 {
+		return amount, 0
+	}
+
+	if pricesIncludeTax {
+		base = roundCents(amount * 100 / (100 + ratePercent))
+		return base, roundCents(amount - base)
+	}
+	return amount, roundCents(amount * ratePercent / 100)
+}
+
+// VATReportLine is one tax invoice on the input or output side of the VAT report
+type VATReportLine struct {
+	DocumentID       int       `json:"document_id"`
+	DocumentNumber   string    `json:"document_number"`
+	TaxInvoiceNumber *string   `json:"tax_invoice_number,omitempty"`
+	DocumentDate     time.Time `json:"document_date"`
+	PartyName        string    `json:"party_name"`
+	PartyTaxNumber   *string   `json:"party_tax_number,omitempty"`
+	TaxBase          float64   `json:"tax_base"`
+	TaxAmount        float64   `json:"tax_amount"`
+}
+
+// VATReport lists the input VAT from supplier invoices and the output VAT from sales invoices of a month
+type VATReport struct {
+	PeriodStart     time.Time       `json:"period_start"`
+	PeriodEnd       time.Time       `json:"period_end"`
+	InputLines      []VATReportLine `json:"input_lines"`
+	OutputLines     []VATReportLine `json:"output_lines"`
+	TotalInputBase  float64         `json:"total_input_base"`
+	TotalInputVAT   float64         `json:"total_input_vat"`
+	TotalOutputBase float64         `json:"total_output_base"`
+	TotalOutputVAT  float64         `json:"total_output_vat"`
+	NetVATPayable   float64         `json:"net_vat_payable"`
+}
+
+// NewVATReport totals the input and output lines of the month starting at periodStart.
+// A negative net VAT payable is a credit carried to the next period.
+func NewVATReport(periodStart time.Time, inputLines, outputLines []VATReportLine) *VATReport {
+	report := &VATReport{
+		PeriodStart: periodStart,
+		PeriodEnd:   PeriodEnd(periodStart),
+		InputLines:  inputLines,
+		OutputLines: outputLines,
+	}
+	if report.InputLines == nil {
+		report.InputLines = []VATReportLine{}
+	}
+	if report.OutputLines == nil {
+		report.OutputLines = []VATReportLine{}
+	}
+
+	for _, line := range inputLines {
+		report.TotalInputBase += line.TaxBase
+		report.TotalInputVAT += line.TaxAmount
+	}
+	for _, line := range outputLines {
+		report.TotalOutputBase += line.TaxBase
+		report.TotalOutputVAT += line.TaxAmount
+	}
+
+	report.TotalInputBase = roundCents(report.TotalInputBase)
+	report.TotalInputVAT = roundCents(report.TotalInputVAT)
+	report.TotalOutputBase = roundCents(report.TotalOutputBase)
+	report.TotalOutputVAT = roundCents(report.TotalOutputVAT)
+	report.NetVATPayable = roundCents(report.TotalOutputVAT - report.TotalInputVAT)
+	return report
+}
+
+// e-Faktur import headers for output tax invoices (FK), the buyer (LT) and the invoice lines (OF)
+var (
+	eFakturFKHeader = []string{"FK", "KD_JENIS_TRANSAKSI", "FG_PENGGANTI", "NOMOR_FAKTUR", "MASA_PAJAK", "TAHUN_PAJAK",
+		"TANGGAL_FAKTUR", "NPWP", "NAMA", "ALAMAT_LENGKAP", "JUMLAH_DPP", "JUMLAH_PPN", "JUMLAH_PPNBM",
+		"ID_KETERANGAN_TAMBAHAN", "FG_UANG_MUKA", "UANG_MUKA_DPP", "UANG_MUKA_PPN", "UANG_MUKA_PPNBM", "REFERENSI",
+		"KODE_DOKUMEN_PENDUKUNG"}
+	eFakturLTHeader = []string{"LT", "NPWP", "NAMA", "JALAN", "BLOK", "NOMOR", "RT", "RW", "KECAMATAN", "KELURAHAN",
+		"KABUPATEN", "PROPINSI", "KODE_POS", "NOMOR_TELEPON"}
+	eFakturOFHeader = []string{"OF", "KODE_OBJEK", "NAMA", "HARGA_SATUAN", "JUMLAH_BARANG", "HARGA_TOTAL", "DISKON",
+		"DPP", "PPN", "TARIF_PPNBM", "PPNBM"}
+)
+
+// WriteEFakturCSV writes issued sales invoices carrying VAT in the e-Faktur import format.
+// Every invoice must have a tax invoice number; invoices without tax are skipped.
+func WriteEFakturCSV(w io.Writer, invoices []SalesInvoice) error {
+	var missing []string
+	for _, invoice := range invoices {
+		if invoice.InvoiceStatus == SalesInvoiceStatusIssued && invoice.TaxAmount > 0 && invoice.TaxInvoiceNumber == nil {
+			missing = append(missing, invoice.InvoiceNumber)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("sales invoices without a tax invoice number: %s", strings.Join(missing, ", "))
+	}
+
+	writer := csv.NewWriter(w)
+	for _, header := range [][]string{eFakturFKHeader, eFakturLTHeader, eFakturOFHeader} {
+		if err := writer.Write(header); err != nil {
+			return fmt.Errorf("failed to write e-Faktur header: %w", err)
+		}
+	}
+
+	for _, invoice := range invoices {
+		if invoice.InvoiceStatus != SalesInvoiceStatusIssued || invoice.TaxAmount <= 0 {
+			continue
+		}
+
+		number, err := eFakturNumber(*invoice.TaxInvoiceNumber)
+		if err != nil {
+			return fmt.Errorf("sales invoice %s: %w", invoice.InvoiceNumber, err)
+		}
+
+		taxNumber := strings.Repeat("0", 15)
+		if invoice.CustomerTaxNumber != nil {
+			if digits := digitsOnly(*invoice.CustomerTaxNumber); digits != "" {
+				taxNumber = digits
+			}
+		}
+
+		record := []string{"FK", "01", "0", number,
+			strconv.Itoa(int(invoice.InvoiceDate.Month())), strconv.Itoa(invoice.InvoiceDate.Year()),
+			invoice.InvoiceDate.Format("02/01/2006"), taxNumber, invoice.CustomerName, invoice.CustomerAddress,
+			eFakturInt(invoice.Subtotal), eFakturInt(invoice.TaxAmount), "0", "", "0", "0", "0", "0",
+			invoice.InvoiceNumber, ""}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write e-Faktur row: %w", err)
+		}
+
+		for _, line := range invoice.Lines {
+			discount := line.DiscountAmount
+			if invoice.PricesIncludeTax && line.TaxRate > 0 {
+				discount = discount * 100 / (100 + line.TaxRate)
+			}
+			discount = roundCents(discount)
+			total := roundCents(line.TaxBase + discount)
+
+			record := []string{"OF", line.ProductCode, line.ProductName,
+				eFakturAmount(total / float64(line.Quantity)), strconv.Itoa(line.Quantity), eFakturAmount(total),
+				eFakturAmount(discount), eFakturAmount(line.TaxBase), eFakturAmount(line.TaxAmount), "0", "0"}
+			if err := writer.Write(record); err != nil {
+				return fmt.Errorf("failed to write e-Faktur row: %w", err)
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// eFakturNumber reduces a tax invoice number such as 010.000-24.00000001 to the 13 digit serial e-Faktur expects
+func eFakturNumber(taxInvoiceNumber string) (string, error) {
+	digits := digitsOnly(taxInvoiceNumber)
+	if len(digits) == 16 {
+		digits = digits[3:]
+	}
+	if len(digits) != 13 {
+		return "", fmt.Errorf("invalid tax invoice number %s", taxInvoiceNumber)
+	}
+	return digits, nil
+}
+
+func digitsOnly(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// eFakturInt formats the invoice totals, which e-Faktur takes as whole rupiah rounded down
+func eFakturInt(amount float64) string {
+	return strconv.FormatInt(int64(math.Floor(amount+0.005)), 10)
+}
+
+func eFakturAmount(amount float64) string {
+	return strconv.FormatFloat(roundCents(amount), 'f', -1, 64)
+}
@@ -0,0 +1,155 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+)
+
+// LoginOutcome represents how a login attempt ended
+type LoginOutcome string
+
+const (
+	LoginOutcomeSuccess           LoginOutcome = "success"
+	LoginOutcomeFailed            LoginOutcome = "failed"
+	LoginOutcomeTwoFactorRequired LoginOutcome = "two_factor_required"
+	LoginOutcomeTwoFactorFailed   LoginOutcome = "two_factor_failed"
+	LoginOutcomeInactive          LoginOutcome = "inactive"
+	LoginOutcomeLocked            LoginOutcome = "locked"
+	LoginOutcomeThrottled         LoginOutcome = "throttled"
+	LoginOutcomePasswordReset     LoginOutcome = "password_reset"
+)
+
+// IsValid checks if the login outcome is valid
+func (o LoginOutcome) IsValid() bool {
+	switch o {
+	case LoginOutcomeSuccess, LoginOutcomeFailed, LoginOutcomeTwoFactorRequired, LoginOutcomeTwoFactorFailed,
+		LoginOutcomeInactive, LoginOutcomeLocked, LoginOutcomeThrottled, LoginOutcomePasswordReset:
+		return true
+	}
+	return false
+}
+
+// IsFailure reports whether the outcome counts as a guess towards throttling and lockout.
+// Attempts that were refused before the credentials were checked do not count.
+func (o LoginOutcome) IsFailure() bool {
+	return o == LoginOutcomeFailed || o == LoginOutcomeTwoFactorFailed
+}
+
+// LoginAttempt represents an entry in the login attempts log. UserID is nil when the username
+// does not belong to any user.
+type LoginAttempt struct {
+	AttemptID int          `json:"attempt_id" db:"attempt_id"`
+	Username  string       `json:"username" db:"username"`
+	UserID    *int         `json:"user_id" db:"user_id"`
+	IPAddress string       `json:"ip_address" db:"ip_address"`
+	UserAgent string       `json:"user_agent" db:"user_agent"`
+	Outcome   LoginOutcome `json:"outcome" db:"outcome"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+}
+
+// LoginAttemptFilterParams represents filtering parameters for the login attempts log
+type LoginAttemptFilterParams struct {
+	Username  string        `json:"username,omitempty" form:"username"`
+	UserID    *int          `json:"user_id,omitempty" form:"user_id"`
+	IPAddress string        `json:"ip_address,omitempty" form:"ip_address"`
+	Outcome   *LoginOutcome `json:"outcome,omitempty" form:"outcome"`
+	DateFrom  *time.Time    `json:"date_from,omitempty" form:"date_from"`
+	DateTo    *time.Time    `json:"date_to,omitempty" form:"date_to"`
+	common.PaginationParams
+}
+
+// LoginLock represents the consecutive failed logins of a user. LockedUntil is set once the
+// failures reach the lockout threshold; a successful login or an admin unlock clears them.
+type LoginLock struct {
+	UserID       int        `json:"user_id" db:"user_id"`
+	FailedCount  int        `json:"failed_count" db:"failed_count"`
+	LastFailedAt *time.Time `json:"last_failed_at" db:"last_failed_at"`
+	LockedUntil  *time.Time `json:"locked_until" db:"locked_until"`
+}
+
+// IsLocked reports whether the account is locked at a moment
+func (l *LoginLock) IsLocked(now time.Time) bool {
+	return l != nil && l.LockedUntil != nil && now.Before(*l.LockedUntil)
+}
+
+// Progressive delay between failed logins, so a handful of typos cost nothing but guessing
+// gets slower with every failure
+const (
+	loginFreeFailures = 2
+	loginBaseDelay    = time.Second
+	loginMaxDelay     = 30 * time.Second
+)
+
+// LoginThrottlePolicy configures brute-force protection. Failures are counted per user, where
+// MaxFailures consecutive failures lock the account for Lockout, and per IP address, where
+// IPMaxFailures failures within IPWindow block the address.
+type LoginThrottlePolicy struct {
+	MaxFailures   int
+	Lockout       time.Duration
+	IPMaxFailures int
+	IPWindow      time.Duration
+}
+
+// Delay returns how long to wait after the last of a number of failed logins before the next
+// attempt is accepted
+func (p LoginThrottlePolicy) Delay(failures int) time.Duration {
+	if failures <= loginFreeFailures {
+		return 0
+	}
+	delay := loginBaseDelay
+	for i := loginFreeFailures + 1; i < failures && delay < loginMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > loginMaxDelay {
+		delay = loginMaxDelay
+	}
+	return delay
+}
+
+// RetryAfter returns how long a login must wait given the recent failures, or zero when it may
+// proceed. lock is nil for usernames that do not belong to a user.
+func (p LoginThrottlePolicy) RetryAfter(lock *LoginLock, ipFailures int, lastIPFailure *time.Time, now time.Time) (time.Duration, LoginOutcome) {
+	if lock.IsLocked(now) {
+		return lock.LockedUntil.Sub(now), LoginOutcomeLocked
+	}
+
+	var wait time.Duration
+	if lock != nil && lock.LastFailedAt != nil {
+		wait = lock.LastFailedAt.Add(p.Delay(lock.FailedCount)).Sub(now)
+	}
+
+	if lastIPFailure != nil {
+		ipDelay := p.Delay(ipFailures)
+		if p.IPMaxFailures > 0 && ipFailures >= p.IPMaxFailures {
+			ipDelay = p.IPWindow
+		}
+		if ipWait := lastIPFailure.Add(ipDelay).Sub(now); ipWait > wait {
+			wait = ipWait
+		}
+	}
+
+	if wait <= 0 {
+		return 0, ""
+	}
+	return wait, LoginOutcomeThrottled
+}
+
+// LoginBlockedError is returned when a login is refused because of earlier failures
+type LoginBlockedError struct {
+	Outcome    LoginOutcome
+	RetryAfter time.Duration
+}
+
+func (e *LoginBlockedError) Error() string {
+	if e.Outcome == LoginOutcomeLocked {
+		return fmt.Sprintf("account is locked after too many failed logins, try again in %d seconds", e.RetryAfterSeconds())
+	}
+	return fmt.Sprintf("too many failed logins, try again in %d seconds", e.RetryAfterSeconds())
+}
+
+// RetryAfterSeconds returns the wait rounded up to whole seconds, as sent in a Retry-After header
+func (e *LoginBlockedError) RetryAfterSeconds() int {
+	return int((e.RetryAfter + time.Second - 1) / time.Second)
+}
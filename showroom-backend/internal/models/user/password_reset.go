@@ -0,0 +1,69 @@
+package user
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrPasswordResetTokenInvalid is returned for any reset token that cannot be used, without
+// telling apart unknown, used and expired tokens
+var ErrPasswordResetTokenInvalid = errors.New("invalid or expired password reset token")
+
+const (
+	// PasswordResetMaxRequests is how many reset emails a user can be sent within
+	// PasswordResetRequestWindow
+	PasswordResetMaxRequests   = 3
+	PasswordResetRequestWindow = time.Hour
+
+	// PasswordResetIPMaxRequests is how many resets an IP address can request within
+	// PasswordResetRequestWindow, across all usernames
+	PasswordResetIPMaxRequests = 10
+)
+
+// PasswordResetRequests counts the password resets requested for a username and from an IP address
+// within PasswordResetRequestWindow, with the earliest of each
+type PasswordResetRequests struct {
+	Username       int
+	UsernameOldest *time.Time
+	IPAddress      int
+	IPOldest       *time.Time
+}
+
+// RetryAfter returns how long a new request must wait until the oldest request over a limit leaves
+// the window, or zero when it may proceed
+func (r *PasswordResetRequests) RetryAfter(now time.Time) time.Duration {
+	var wait time.Duration
+	if r.Username >= PasswordResetMaxRequests && r.UsernameOldest != nil {
+		wait = r.UsernameOldest.Add(PasswordResetRequestWindow).Sub(now)
+	}
+	if r.IPAddress >= PasswordResetIPMaxRequests && r.IPOldest != nil {
+		if ipWait := r.IPOldest.Add(PasswordResetRequestWindow).Sub(now); ipWait > wait {
+			wait = ipWait
+		}
+	}
+
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+// PasswordResetToken represents a single-use token emailed to a user to set a new password.
+// Only its SHA-256 hash is stored, and requesting a new token invalidates the earlier ones.
+type PasswordResetToken struct {
+	TokenID   int        `json:"token_id" db:"token_id"`
+	UserID    int        `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	IPAddress *string    `json:"ip_address" db:"ip_address"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time `json:"used_at" db:"used_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Check returns ErrPasswordResetTokenInvalid when the token has been used or has expired
+func (t *PasswordResetToken) Check(now time.Time) error {
+	if t.UsedAt != nil || !now.Before(t.ExpiresAt) {
+		return ErrPasswordResetTokenInvalid
+	}
+	return nil
+}
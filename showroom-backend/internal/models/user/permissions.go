@@ -0,0 +1,187 @@
+package user
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+)
+
+// Permission codes checked by the API routes. A code is "<resource>.<action>".
+const (
+	PermUserManage   = "user.manage"
+	PermRoleManage   = "role.manage"
+	PermJobManage    = "job.manage"
+	PermAPIKeyManage = "api_key.manage"
+	PermAuditRead    = "audit.read"
+
+	PermCustomerRead  = "customer.read"
+	PermCustomerWrite = "customer.write"
+	PermSupplierRead  = "supplier.read"
+	PermSupplierWrite = "supplier.write"
+	PermVehicleRead   = "vehicle.read"
+	PermVehicleWrite  = "vehicle.write"
+	PermProductRead   = "product.read"
+	PermProductWrite  = "product.write"
+
+	PermPurchaseOrderRead     = "purchase_order.read"
+	PermPurchaseOrderWrite    = "purchase_order.write"
+	PermPurchaseOrderApprove  = "purchase_order.approve"
+	PermGoodsReceiptRead      = "goods_receipt.read"
+	PermGoodsReceiptWrite     = "goods_receipt.write"
+	PermPurchaseReturnRead    = "purchase_return.read"
+	PermPurchaseReturnWrite   = "purchase_return.write"
+	PermPurchaseReturnApprove = "purchase_return.approve"
+	PermLandedCostRead        = "landed_cost.read"
+	PermLandedCostWrite       = "landed_cost.write"
+	PermPriceListRead         = "price_list.read"
+	PermPriceListWrite        = "price_list.write"
+
+	PermStockRead          = "stock.read"
+	PermStockMove          = "stock.move"
+	PermStockAdjust        = "stock.adjust"
+	PermStockApproveAdjust = "stock.approve_adjustment"
+
+	PermPaymentTermRead      = "payment_term.read"
+	PermPaymentTermWrite     = "payment_term.write"
+	PermSupplierInvoiceRead  = "supplier_invoice.read"
+	PermSupplierInvoiceWrite = "supplier_invoice.write"
+	PermSupplierPaymentRead  = "supplier_payment.read"
+	PermSupplierPaymentWrite = "supplier_payment.write"
+	PermPaymentRunRead       = "payment_run.read"
+	PermPaymentRunWrite      = "payment_run.write"
+	PermPaymentRunApprove    = "payment_run.approve"
+	PermBankStatementRead    = "bank_statement.read"
+	PermBankReconcile        = "bank_statement.reconcile"
+	PermExchangeRateRead     = "exchange_rate.read"
+	PermExchangeRateWrite    = "exchange_rate.write"
+
+	PermGLRead    = "gl.read"
+	PermGLPost    = "gl.post"
+	PermGLManage  = "gl.manage"
+	PermTaxRead   = "tax.read"
+	PermTaxManage = "tax.manage"
+
+	PermSalesInvoiceRead     = "sales_invoice.read"
+	PermSalesInvoiceCreate   = "sales_invoice.create"
+	PermSalesInvoiceVoid     = "sales_invoice.void"
+	PermCustomerReceiptRead  = "customer_receipt.read"
+	PermCustomerReceiptWrite = "customer_receipt.write"
+	PermCustomerAccountRead  = "customer_account.read"
+)
+
+// PermissionDefinition describes a permission that can be granted to roles
+type PermissionDefinition struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+// Permissions lists every permission the API checks, in display order
+var Permissions = []PermissionDefinition{
+	{PermUserManage, "Manage users and their sessions"},
+	{PermRoleManage, "Edit the permissions granted to roles"},
+	{PermJobManage, "View and trigger background jobs"},
+	{PermAPIKeyManage, "Create and revoke API keys for integrations"},
+	{PermAuditRead, "View the audit log of data changes"},
+	{PermCustomerRead, "View customers"},
+	{PermCustomerWrite, "Create, update and delete customers"},
+	{PermSupplierRead, "View suppliers and scorecards"},
+	{PermSupplierWrite, "Create, update and delete suppliers"},
+	{PermVehicleRead, "View vehicle brands, categories and models"},
+	{PermVehicleWrite, "Maintain vehicle brands, categories and models"},
+	{PermProductRead, "View products, categories and stock levels"},
+	{PermProductWrite, "Create, update and delete products and categories"},
+	{PermPurchaseOrderRead, "View purchase orders"},
+	{PermPurchaseOrderWrite, "Create and update purchase orders"},
+	{PermPurchaseOrderApprove, "Approve and cancel purchase orders"},
+	{PermGoodsReceiptRead, "View goods receipts"},
+	{PermGoodsReceiptWrite, "Receive and process goods"},
+	{PermPurchaseReturnRead, "View purchase returns and supplier debit notes"},
+	{PermPurchaseReturnWrite, "Create purchase returns and apply debit notes"},
+	{PermPurchaseReturnApprove, "Approve and cancel purchase returns"},
+	{PermLandedCostRead, "View landed cost vouchers"},
+	{PermLandedCostWrite, "Create, post and cancel landed cost vouchers"},
+	{PermPriceListRead, "View supplier price lists"},
+	{PermPriceListWrite, "Maintain supplier price lists"},
+	{PermStockRead, "View stock movements and adjustments"},
+	{PermStockMove, "Record stock movements and transfers"},
+	{PermStockAdjust, "Create stock adjustments and physical counts"},
+	{PermStockApproveAdjust, "Approve stock adjustments"},
+	{PermPaymentTermRead, "View payment terms"},
+	{PermPaymentTermWrite, "Maintain payment terms"},
+	{PermSupplierInvoiceRead, "View supplier invoices and AP aging"},
+	{PermSupplierInvoiceWrite, "Record and update supplier invoices"},
+	{PermSupplierPaymentRead, "View supplier payments"},
+	{PermSupplierPaymentWrite, "Record, allocate and void supplier payments"},
+	{PermPaymentRunRead, "View payment runs and download bank files"},
+	{PermPaymentRunWrite, "Prepare and execute payment runs"},
+	{PermPaymentRunApprove, "Approve and cancel payment runs"},
+	{PermBankStatementRead, "View bank statements and reconciliation"},
+	{PermBankReconcile, "Import bank statements and reconcile lines"},
+	{PermExchangeRateRead, "View exchange rates"},
+	{PermExchangeRateWrite, "Enter and import exchange rates"},
+	{PermGLRead, "View the general ledger and trial balance"},
+	{PermGLPost, "Post and reverse manual journals"},
+	{PermGLManage, "Maintain accounts, posting rules and accounting periods"},
+	{PermTaxRead, "View tax codes and VAT reports"},
+	{PermTaxManage, "Maintain tax codes"},
+	{PermSalesInvoiceRead, "View sales invoices"},
+	{PermSalesInvoiceCreate, "Issue sales invoices"},
+	{PermSalesInvoiceVoid, "Void sales invoices"},
+	{PermCustomerReceiptRead, "View customer receipts"},
+	{PermCustomerReceiptWrite, "Record, allocate and void customer receipts"},
+	{PermCustomerAccountRead, "View customer credit status and statements"},
+}
+
+// IsValidPermission checks if a code is a known permission
+func IsValidPermission(code string) bool {
+	for _, permission := range Permissions {
+		if permission.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// AllPermissionCodes returns the code of every permission
+func AllPermissionCodes() []string {
+	codes := make([]string, len(Permissions))
+	for i, permission := range Permissions {
+		codes[i] = permission.Code
+	}
+	return codes
+}
+
+// RoleHasAllPermissions reports whether a role is granted every permission regardless of the
+// stored mappings. Admins always keep full access so they cannot lock themselves out.
+func RoleHasAllPermissions(role common.UserRole) bool {
+	return role == common.RoleAdmin
+}
+
+// RolePermissions represents the permissions granted to a role
+type RolePermissions struct {
+	Role        common.UserRole `json:"role"`
+	Permissions []string        `json:"permissions"`
+}
+
+// RolePermissionsUpdateRequest represents a request to replace the permissions of a role
+type RolePermissionsUpdateRequest struct {
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// Validate checks the permission codes, returning them sorted and without duplicates
+func (r *RolePermissionsUpdateRequest) Validate() ([]string, error) {
+	seen := make(map[string]bool, len(r.Permissions))
+	codes := make([]string, 0, len(r.Permissions))
+	for _, code := range r.Permissions {
+		if !IsValidPermission(code) {
+			return nil, fmt.Errorf("unknown permission: %s", code)
+		}
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	return codes, nil
+}
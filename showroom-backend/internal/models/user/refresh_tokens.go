@@ -0,0 +1,40 @@
+package user
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used, session revoked")
+	ErrSessionRevoked      = errors.New("session is no longer active")
+)
+
+// RefreshToken represents an opaque refresh token issued to a session. Only its SHA-256 hash
+// is stored. Every refresh rotates the token, and presenting a rotated token again means it
+// leaked, so the whole session is revoked.
+type RefreshToken struct {
+	TokenID   int        `json:"token_id" db:"token_id"`
+	SessionID int        `json:"session_id" db:"session_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	IssuedAt  time.Time  `json:"issued_at" db:"issued_at"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty" db:"rotated_at"`
+}
+
+// Check returns why the token cannot be exchanged for a new one, if it cannot. Reuse is reported
+// before anything else so the session is revoked even when it has already ended or expired.
+func (t *RefreshToken) Check(sessionActive bool, now time.Time) error {
+	if t.RotatedAt != nil {
+		return ErrRefreshTokenReused
+	}
+	if !sessionActive {
+		return ErrSessionRevoked
+	}
+	if !now.Before(t.ExpiresAt) {
+		return ErrRefreshTokenExpired
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+)
+
+// ErrSessionIdle is returned for sessions that ended because they were not used for the idle
+// timeout
+var ErrSessionIdle = errors.New("session expired after inactivity, please log in again")
+
+// SessionPolicy limits sessions. A session that is not used for IdleTimeout ends. A user may hold
+// MaxSessions active sessions, or RoleMaxSessions for the roles listed there, and logging in
+// beyond the limit ends their oldest sessions. Zero disables a limit.
+type SessionPolicy struct {
+	IdleTimeout     time.Duration
+	MaxSessions     int
+	RoleMaxSessions map[common.UserRole]int
+}
+
+// NewSessionPolicy builds a policy from per-role session limits keyed by role name, rejecting
+// unknown roles and negative limits
+func NewSessionPolicy(idleTimeout time.Duration, maxSessions int, roleMaxSessions map[string]int) (SessionPolicy, error) {
+	if idleTimeout < 0 || maxSessions < 0 {
+		return SessionPolicy{}, fmt.Errorf("session limits cannot be negative")
+	}
+
+	policy := SessionPolicy{
+		IdleTimeout:     idleTimeout,
+		MaxSessions:     maxSessions,
+		RoleMaxSessions: make(map[common.UserRole]int, len(roleMaxSessions)),
+	}
+	for name, limit := range roleMaxSessions {
+		role := common.UserRole(name)
+		if !role.IsValid() {
+			return SessionPolicy{}, fmt.Errorf("invalid role: %s", name)
+		}
+		if limit < 0 {
+			return SessionPolicy{}, fmt.Errorf("session limit of role %s cannot be negative", name)
+		}
+		policy.RoleMaxSessions[role] = limit
+	}
+	return policy, nil
+}
+
+// MaxSessionsFor returns how many active sessions users of a role may hold, zero for no limit
+func (p SessionPolicy) MaxSessionsFor(role common.UserRole) int {
+	if limit, ok := p.RoleMaxSessions[role]; ok {
+		return limit
+	}
+	return p.MaxSessions
+}
+
+// IsIdle reports whether a session has not been used for longer than the idle timeout
+func (s *UserSession) IsIdle(idleTimeout time.Duration, now time.Time) bool {
+	return idleTimeout > 0 && now.Sub(s.LastActivityAt) >= idleTimeout
+}
@@ -0,0 +1,15 @@
+package user
+
+import "time"
+
+// SigningKey represents a key access tokens are signed with. The newest key that has not been
+// rotated signs new tokens; rotated keys keep validating tokens until they retire, after every
+// token they signed has expired. The private key is stored encrypted.
+type SigningKey struct {
+	KeyID      string     `json:"kid" db:"kid"`
+	Algorithm  string     `json:"algorithm" db:"algorithm"`
+	PrivateKey string     `json:"-" db:"private_key"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	RotatedAt  *time.Time `json:"rotated_at" db:"rotated_at"`
+	RetiresAt  *time.Time `json:"retires_at" db:"retires_at"`
+}
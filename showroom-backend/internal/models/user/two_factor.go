@@ -0,0 +1,153 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+)
+
+var (
+	ErrTwoFactorNotEnrolled       = errors.New("two-factor authentication is not set up")
+	ErrTwoFactorAlreadyEnabled    = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorCodeInvalid       = errors.New("invalid two-factor code")
+	ErrTwoFactorRequiredByRole    = errors.New("two-factor authentication is required for this role")
+	ErrLoginChallengeInvalid      = errors.New("invalid login challenge")
+	ErrLoginChallengeExpired      = errors.New("login challenge expired")
+	ErrLoginChallengeExhausted    = errors.New("too many invalid codes, log in again")
+	ErrLoginChallengeNotEnrolling = errors.New("login challenge is not waiting for enrollment")
+)
+
+const (
+	// RecoveryCodeCount is how many recovery codes are issued at a time
+	RecoveryCodeCount = 10
+	// LoginChallengeLifetime is how long a password-verified login waits for its second factor
+	LoginChallengeLifetime = 5 * time.Minute
+	// LoginChallengeMaxAttempts is how many wrong codes end a login challenge
+	LoginChallengeMaxAttempts = 5
+)
+
+// UserTwoFactor represents the TOTP enrollment of a user. The secret is pending until the user
+// proves their authenticator works by confirming a code; only then is EnabledAt set.
+// LastUsedStep is the time step of the last accepted code, so a code cannot be replayed.
+type UserTwoFactor struct {
+	UserID       int        `json:"user_id" db:"user_id"`
+	Secret       string     `json:"-" db:"secret"`
+	EnabledAt    *time.Time `json:"enabled_at" db:"enabled_at"`
+	LastUsedStep int64      `json:"-" db:"last_used_step"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsEnabled reports whether the enrollment has been confirmed
+func (t *UserTwoFactor) IsEnabled() bool {
+	return t != nil && t.EnabledAt != nil
+}
+
+// RecoveryCode represents a one-time code that replaces a TOTP code when the authenticator is
+// lost. Only the bcrypt hash of the normalized code is stored.
+type RecoveryCode struct {
+	CodeID   int        `json:"code_id" db:"code_id"`
+	UserID   int        `json:"user_id" db:"user_id"`
+	CodeHash string     `json:"-" db:"code_hash"`
+	UsedAt   *time.Time `json:"used_at" db:"used_at"`
+}
+
+// NormalizeRecoveryCode strips the separators and case users may type a recovery code with
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// FormatRecoveryCode groups a normalized recovery code as xxxxx-xxxxx for display
+func FormatRecoveryCode(code string) string {
+	if len(code) <= 5 {
+		return code
+	}
+	return code[:5] + "-" + code[5:]
+}
+
+// LoginChallenge represents a login whose password has been verified but which still needs a
+// second factor before a session is created. Only the hash of the challenge token is stored.
+type LoginChallenge struct {
+	ChallengeID int        `json:"challenge_id" db:"challenge_id"`
+	UserID      int        `json:"user_id" db:"user_id"`
+	TokenHash   string     `json:"-" db:"token_hash"`
+	IPAddress   *string    `json:"ip_address" db:"ip_address"`
+	UserAgent   *string    `json:"user_agent" db:"user_agent"`
+	Enrolling   bool       `json:"enrolling" db:"enrolling"`
+	Attempts    int        `json:"attempts" db:"attempts"`
+	ExpiresAt   time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt      *time.Time `json:"used_at" db:"used_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Check returns why the challenge can no longer be answered, if it cannot
+func (c *LoginChallenge) Check(now time.Time) error {
+	if c.UsedAt != nil {
+		return ErrLoginChallengeInvalid
+	}
+	if c.Attempts >= LoginChallengeMaxAttempts {
+		return ErrLoginChallengeExhausted
+	}
+	if !now.Before(c.ExpiresAt) {
+		return ErrLoginChallengeExpired
+	}
+	return nil
+}
+
+// TwoFactorPolicy lists the roles whose users must use two-factor authentication. Users of other
+// roles may still opt in.
+type TwoFactorPolicy struct {
+	RequiredRoles []common.UserRole
+}
+
+// NewTwoFactorPolicy builds a policy from role names, rejecting unknown roles
+func NewTwoFactorPolicy(roles []string) (TwoFactorPolicy, error) {
+	var policy TwoFactorPolicy
+	for _, name := range roles {
+		role := common.UserRole(name)
+		if !role.IsValid() {
+			return TwoFactorPolicy{}, fmt.Errorf("invalid role: %s", name)
+		}
+		policy.RequiredRoles = append(policy.RequiredRoles, role)
+	}
+	return policy, nil
+}
+
+// Requires reports whether users of a role must use two-factor authentication
+func (p TwoFactorPolicy) Requires(role common.UserRole) bool {
+	for _, required := range p.RequiredRoles {
+		if required == role {
+			return true
+		}
+	}
+	return false
+}
+
+// TwoFactorStatus represents the two-factor state of the current user
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	Required               bool       `json:"required"`
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorEnrollment carries a new TOTP secret to the user. QRPayload is the text to encode in
+// the QR code scanned by the authenticator app.
+type TwoFactorEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+	QRPayload       string `json:"qr_payload"`
+}
+
+// TwoFactorCodeRequest represents a request carrying a TOTP or recovery code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// RecoveryCodesResponse carries freshly generated recovery codes. They are shown only once.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -25,6 +25,8 @@ type User struct {
 	IsActive     bool               `json:"is_active" db:"is_active"`
 	ProfileImage *string            `json:"profile_image,omitempty" db:"profile_image"`
 	Notes        *string            `json:"notes,omitempty" db:"notes"`
+	PasswordChangedAt  time.Time    `json:"password_changed_at" db:"password_changed_at"`
+	MustChangePassword bool         `json:"must_change_password" db:"must_change_password"`
 	Creator      *UserCreatorInfo   `json:"creator,omitempty" db:"-"`
 }
 
@@ -45,7 +47,13 @@ type UserSession struct {
 	IPAddress    *string    `json:"ip_address,omitempty" db:"ip_address"`
 	UserAgent    *string    `json:"user_agent,omitempty" db:"user_agent"`
 	IsActive     bool       `json:"is_active" db:"is_active"`
+	// LastActivityAt is when the session last made an authenticated request
+	LastActivityAt time.Time `json:"last_activity_at" db:"last_activity_at"`
+	// PasswordChangeRequired limits the session to changing the password
+	PasswordChangeRequired bool `json:"password_change_required" db:"password_change_required"`
 	Duration     string     `json:"duration,omitempty" db:"-"`
+	// Current marks the session making the request in a user's list of their devices
+	Current bool `json:"current,omitempty" db:"-"`
 }
 
 // UserProfile represents user profile with sessions
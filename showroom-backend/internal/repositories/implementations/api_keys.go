@@ -0,0 +1,180 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// apiKeyLastUsedInterval limits how often last-used tracking writes to a busy key
+const apiKeyLastUsedInterval = "1 minute"
+
+const apiKeyColumns = `key_id, name, prefix, key_hash, scopes, allowed_ips, created_by, expires_at,
+		       last_used_at, last_used_ip, revoked_at, revoked_by, created_at`
+
+type apiKeyRepository struct {
+	db *sql.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *sql.DB) interfaces.APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// scanAPIKey reads a row selected with apiKeyColumns
+func scanAPIKey(row interface{ Scan(...interface{}) error }) (*user.APIKey, error) {
+	var key user.APIKey
+	err := row.Scan(
+		&key.KeyID, &key.Name, &key.Prefix, &key.KeyHash, pq.Array(&key.Scopes), pq.Array(&key.AllowedIPs),
+		&key.CreatedBy, &key.ExpiresAt, &key.LastUsedAt, &key.LastUsedIP, &key.RevokedAt, &key.RevokedBy,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Create stores a new API key
+func (r *apiKeyRepository) Create(ctx context.Context, key *user.APIKey) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO api_keys (name, prefix, key_hash, scopes, allowed_ips, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING key_id, created_at`,
+		key.Name, key.Prefix, key.KeyHash, pq.Array(key.Scopes), pq.Array(key.AllowedIPs),
+		key.CreatedBy, key.ExpiresAt,
+	).Scan(&key.KeyID, &key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an API key by ID
+func (r *apiKeyRepository) GetByID(ctx context.Context, id int) (*user.APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx,
+		"SELECT "+apiKeyColumns+" FROM api_keys WHERE key_id = $1", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("API key not found")
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	return key, nil
+}
+
+// GetByPrefix retrieves an API key by its prefix
+func (r *apiKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*user.APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx,
+		"SELECT "+apiKeyColumns+" FROM api_keys WHERE prefix = $1", prefix))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, user.ErrAPIKeyInvalid
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	return key, nil
+}
+
+// List retrieves API keys with filtering and pagination, newest first
+func (r *apiKeyRepository) List(ctx context.Context, params *user.APIKeyFilterParams) ([]user.APIKey, int, error) {
+	whereConditions := []string{}
+	args := []interface{}{}
+	argIndex := 1
+
+	if params.CreatedBy != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("created_by = $%d", argIndex))
+		args = append(args, *params.CreatedBy)
+		argIndex++
+	}
+
+	if params.Active != nil {
+		if *params.Active {
+			whereConditions = append(whereConditions, "revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())")
+		} else {
+			whereConditions = append(whereConditions, "(revoked_at IS NOT NULL OR expires_at <= NOW())")
+		}
+	}
+
+	whereClause := ""
+	if len(whereConditions) > 0 {
+		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM api_keys %s", whereClause)
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count API keys: %w", err)
+	}
+
+	params.Validate()
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM api_keys %s
+		ORDER BY created_at DESC, key_id DESC
+		LIMIT $%d OFFSET $%d`, apiKeyColumns, whereClause, argIndex, argIndex+1)
+	args = append(args, params.Limit, params.GetOffset())
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []user.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan API key: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+
+	return keys, total, rows.Err()
+}
+
+// Revoke marks an API key revoked
+func (r *apiKeyRepository) Revoke(ctx context.Context, id int, revokedBy int) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE api_keys
+		SET revoked_at = NOW(), revoked_by = $2
+		WHERE key_id = $1 AND revoked_at IS NULL`, id, revokedBy)
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("API key not found or already revoked")
+	}
+
+	return nil
+}
+
+// TouchLastUsed records when and from where an API key was last used, at most once per
+// apiKeyLastUsedInterval
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id int, ipAddress string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE api_keys
+		SET last_used_at = NOW(), last_used_ip = $2
+		WHERE key_id = $1
+		  AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '`+apiKeyLastUsedInterval+`'
+		       OR last_used_ip IS DISTINCT FROM $2)`, id, ipAddress)
+	if err != nil {
+		return fmt.Errorf("failed to record API key use: %w", err)
+	}
+
+	return nil
+}
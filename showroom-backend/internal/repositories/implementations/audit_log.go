@@ -0,0 +1,141 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/audit"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+const auditLogColumns = `a.audit_id, a.occurred_at, a.user_id, u.username, a.session_id, a.api_key_id, a.ip_address,
+		       a.entity, a.entity_id, a.action, a.before_data, a.after_data`
+
+type auditLogRepository struct {
+	db *sql.DB
+}
+
+// NewAuditLogRepository creates a new audit log repository
+func NewAuditLogRepository(db *sql.DB) interfaces.AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+// scanAuditLogEntry reads a row selected with auditLogColumns
+func scanAuditLogEntry(row interface{ Scan(...interface{}) error }) (*audit.AuditLogEntry, error) {
+	var entry audit.AuditLogEntry
+	var before, after []byte
+	err := row.Scan(
+		&entry.AuditID, &entry.OccurredAt, &entry.UserID, &entry.Username, &entry.SessionID, &entry.APIKeyID,
+		&entry.IPAddress, &entry.Entity, &entry.EntityID, &entry.Action, &before, &after,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if before != nil {
+		entry.Before = before
+	}
+	if after != nil {
+		entry.After = after
+	}
+	return &entry, nil
+}
+
+// GetByID retrieves an audit log entry by ID
+func (r *auditLogRepository) GetByID(ctx context.Context, id int64) (*audit.AuditLogEntry, error) {
+	entry, err := scanAuditLogEntry(r.db.QueryRowContext(ctx, `
+		SELECT `+auditLogColumns+`
+		FROM audit_log a
+		LEFT JOIN users u ON u.user_id = a.user_id
+		WHERE a.audit_id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("audit log entry not found")
+		}
+		return nil, fmt.Errorf("failed to get audit log entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// List retrieves audit log entries with filtering and pagination, newest first
+func (r *auditLogRepository) List(ctx context.Context, params *audit.AuditLogFilterParams) ([]audit.AuditLogEntry, int, error) {
+	whereConditions := []string{}
+	args := []interface{}{}
+	argIndex := 1
+
+	if params.Entity != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.entity = $%d", argIndex))
+		args = append(args, params.Entity)
+		argIndex++
+	}
+
+	if params.EntityID != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.entity_id = $%d", argIndex))
+		args = append(args, params.EntityID)
+		argIndex++
+	}
+
+	if params.UserID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.user_id = $%d", argIndex))
+		args = append(args, *params.UserID)
+		argIndex++
+	}
+
+	if params.Action != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.action = $%d", argIndex))
+		args = append(args, *params.Action)
+		argIndex++
+	}
+
+	if params.DateFrom != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.occurred_at >= $%d", argIndex))
+		args = append(args, *params.DateFrom)
+		argIndex++
+	}
+
+	if params.DateTo != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.occurred_at <= $%d", argIndex))
+		args = append(args, *params.DateTo)
+		argIndex++
+	}
+
+	whereClause := ""
+	if len(whereConditions) > 0 {
+		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM audit_log a %s", whereClause)
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit log entries: %w", err)
+	}
+
+	params.Validate()
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM audit_log a
+		LEFT JOIN users u ON u.user_id = a.user_id
+		%s
+		ORDER BY a.occurred_at DESC, a.audit_id DESC
+		LIMIT $%d OFFSET $%d`, auditLogColumns, whereClause, argIndex, argIndex+1)
+	args = append(args, params.Limit, params.GetOffset())
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit log entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []audit.AuditLogEntry{}
+	for rows.Next() {
+		entry, err := scanAuditLogEntry(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan audit log entry: %w", err)
+		}
+		entries = append(entries, *entry)
+	}
+
+	return entries, total, rows.Err()
+}
@@ -0,0 +1,615 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// bankStatementTotalsJoin derives the period, counts and totals of a statement aliased bs from its lines
+const bankStatementTotalsJoin = `
+	LEFT JOIN LATERAL (
+		SELECT MIN(l.transaction_date) AS period_start,
+			   MAX(l.transaction_date) AS period_end,
+			   COUNT(*) AS line_count,
+			   COUNT(*) FILTER (WHERE l.match_status = 'matched') AS matched_count,
+			   COALESCE(SUM(-l.amount) FILTER (WHERE l.amount < 0), 0) AS total_debit,
+			   COALESCE(SUM(l.amount) FILTER (WHERE l.amount > 0), 0) AS total_credit
+		FROM bank_statement_lines l
+		WHERE l.statement_id = bs.statement_id
+	) totals ON TRUE`
+
+const bankStatementSelectFields = `
+	bs.statement_id, bs.bank_account, bs.file_name, totals.period_start, totals.period_end,
+	totals.line_count, totals.matched_count, totals.total_debit, totals.total_credit,
+	bs.imported_by, bs.created_at`
+
+const bankStatementLineSelectFields = `
+	l.line_id, l.statement_id, l.bank_account, l.line_number, l.transaction_date,
+	l.description, l.reference, l.amount, l.balance, l.fingerprint, l.match_status,
+	l.match_method, l.voucher_id, l.receipt_id, l.matched_by, l.matched_at, pv.voucher_number,
+	cr.receipt_number`
+
+// bankStatementLineJoins joins the payment or receipt matched to a statement line aliased l
+const bankStatementLineJoins = `
+		LEFT JOIN supplier_payment_vouchers pv ON pv.voucher_id = l.voucher_id
+		LEFT JOIN customer_receipts cr ON cr.receipt_id = l.receipt_id`
+
+// bankMatchCandidateCondition matches posted vouchers aliased pv that could appear on a bank
+// statement and are not reconciled against a statement line yet
+const bankMatchCandidateCondition = `pv.voucher_status = 'posted'
+	AND pv.payment_method != 'cash'
+	AND NOT EXISTS (SELECT 1 FROM bank_statement_lines bl WHERE bl.voucher_id = pv.voucher_id)`
+
+// bankReceiptCandidateCondition matches posted customer receipts aliased cr that could appear
+// on a bank statement and are not reconciled against a statement line yet
+const bankReceiptCandidateCondition = `cr.receipt_status = 'posted'
+	AND cr.payment_method != 'cash'
+	AND NOT EXISTS (SELECT 1 FROM bank_statement_lines bl WHERE bl.receipt_id = cr.receipt_id)`
+
+// BankStatementRepository implements interfaces.BankStatementRepository
+type BankStatementRepository struct {
+	db *sql.DB
+}
+
+// NewBankStatementRepository creates a new bank statement repository
+func NewBankStatementRepository(db *sql.DB) interfaces.BankStatementRepository {
+	return &BankStatementRepository{db: db}
+}
+
+// Import stores a statement with its lines. Lines already imported for the bank account from an
+// overlapping statement are skipped and counted in SkippedLines.
+func (r *BankStatementRepository) Import(ctx context.Context, statement *products.BankStatement, lines []products.BankStatementLine) (*products.BankStatement, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO bank_statements (bank_account, file_name, imported_by)
+		VALUES ($1, $2, $3)
+		RETURNING statement_id`
+
+	err = tx.QueryRowContext(ctx, query,
+		statement.BankAccount,
+		statement.FileName,
+		statement.ImportedBy,
+	).Scan(&statement.StatementID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bank statement: %w", err)
+	}
+
+	skipped := 0
+	for _, line := range lines {
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO bank_statement_lines (
+				statement_id, bank_account, line_number, transaction_date, description,
+				reference, amount, balance, fingerprint, match_status
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			ON CONFLICT (bank_account, fingerprint) DO NOTHING`,
+			statement.StatementID,
+			statement.BankAccount,
+			line.LineNumber,
+			line.TransactionDate,
+			line.Description,
+			line.Reference,
+			line.Amount,
+			line.Balance,
+			line.Fingerprint,
+			products.BankMatchStatusUnmatched,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create bank statement line: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to check rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			skipped++
+		}
+	}
+
+	if skipped == len(lines) {
+		return nil, fmt.Errorf("all %d transactions were already imported for bank account %s", skipped, statement.BankAccount)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	imported, err := r.GetByID(ctx, statement.StatementID, &products.BankStatementLineFilterParams{})
+	if err != nil {
+		return nil, err
+	}
+	imported.SkippedLines = skipped
+	return imported, nil
+}
+
+// GetByID retrieves a bank statement by ID with its lines
+func (r *BankStatementRepository) GetByID(ctx context.Context, id int, params *products.BankStatementLineFilterParams) (*products.BankStatement, error) {
+	query := "SELECT " + bankStatementSelectFields + `
+		FROM bank_statements bs` + bankStatementTotalsJoin + `
+		WHERE bs.statement_id = $1`
+
+	statement, err := scanBankStatement(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("bank statement not found")
+		}
+		return nil, fmt.Errorf("failed to get bank statement: %w", err)
+	}
+
+	linesQuery := "SELECT " + bankStatementLineSelectFields + `
+		FROM bank_statement_lines l` + bankStatementLineJoins + `
+		WHERE l.statement_id = $1`
+	args := []interface{}{id}
+	if params.MatchStatus != nil {
+		linesQuery += " AND l.match_status = $2"
+		args = append(args, *params.MatchStatus)
+	}
+	linesQuery += " ORDER BY l.transaction_date, l.line_number"
+
+	rows, err := r.db.QueryContext(ctx, linesQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bank statement lines: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		line, err := scanBankStatementLine(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bank statement line: %w", err)
+		}
+		statement.Lines = append(statement.Lines, *line)
+	}
+
+	return statement, nil
+}
+
+// List retrieves bank statements with pagination
+func (r *BankStatementRepository) List(ctx context.Context, params *products.BankStatementFilterParams) (*common.PaginatedResponse, error) {
+	baseQuery := `FROM bank_statements bs` + bankStatementTotalsJoin + ` WHERE 1=1`
+
+	args := []interface{}{}
+	whereConditions := []string{}
+	argIndex := 1
+
+	// Add filters
+	if params.BankAccount != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("bs.bank_account = $%d", argIndex))
+		args = append(args, params.BankAccount)
+		argIndex++
+	}
+
+	if params.DateFrom != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("totals.period_end >= $%d", argIndex))
+		args = append(args, *params.DateFrom)
+		argIndex++
+	}
+
+	if params.DateTo != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("totals.period_start <= $%d", argIndex))
+		args = append(args, *params.DateTo)
+		argIndex++
+	}
+
+	if len(whereConditions) > 0 {
+		baseQuery += " AND " + strings.Join(whereConditions, " AND ")
+	}
+
+	// Count query
+	countQuery := "SELECT COUNT(*) " + baseQuery
+	var total int64
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count bank statements: %w", err)
+	}
+
+	// Calculate pagination
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	// Main query
+	mainQuery := "SELECT " + bankStatementSelectFields + " " + baseQuery +
+		" ORDER BY totals.period_end DESC NULLS LAST, bs.statement_id DESC LIMIT $" + fmt.Sprintf("%d", argIndex) +
+		" OFFSET $" + fmt.Sprintf("%d", argIndex+1)
+
+	args = append(args, params.Limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, mainQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bank statements: %w", err)
+	}
+	defer rows.Close()
+
+	var statements []products.BankStatement
+	for rows.Next() {
+		statement, err := scanBankStatement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bank statement: %w", err)
+		}
+		statements = append(statements, *statement)
+	}
+
+	totalPages := (total + int64(params.Limit) - 1) / int64(params.Limit)
+
+	return &common.PaginatedResponse{
+		Data:       statements,
+		Total:      int(total),
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: int(totalPages),
+		HasMore:    params.Page < int(totalPages),
+	}, nil
+}
+
+// Delete removes an imported statement that has no matched lines
+func (r *BankStatementRepository) Delete(ctx context.Context, id int) error {
+	query := `
+		DELETE FROM bank_statements bs
+		WHERE bs.statement_id = $1
+		AND NOT EXISTS (
+			SELECT 1 FROM bank_statement_lines l
+			WHERE l.statement_id = bs.statement_id AND l.match_status = 'matched'
+		)`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete bank statement: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("bank statement not found or has matched lines")
+	}
+
+	return nil
+}
+
+// GetLine retrieves a bank statement line by ID
+func (r *BankStatementRepository) GetLine(ctx context.Context, lineID int) (*products.BankStatementLine, error) {
+	query := "SELECT " + bankStatementLineSelectFields + `
+		FROM bank_statement_lines l` + bankStatementLineJoins + `
+		WHERE l.line_id = $1`
+
+	line, err := scanBankStatementLine(r.db.QueryRowContext(ctx, query, lineID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("bank statement line not found")
+		}
+		return nil, fmt.Errorf("failed to get bank statement line: %w", err)
+	}
+
+	return line, nil
+}
+
+// GetMatchCandidates retrieves the unreconciled payments made and customer receipts received
+// between dateFrom and dateTo. Foreign currency payments are matched on their base currency amount.
+func (r *BankStatementRepository) GetMatchCandidates(ctx context.Context, dateFrom, dateTo time.Time) ([]products.BankMatchCandidate, error) {
+	query := `
+		SELECT pv.voucher_id, 0, pv.voucher_number, pv.payment_date, pv.payment_reference,
+			   ROUND(pv.amount * pv.exchange_rate, 2)
+		FROM supplier_payment_vouchers pv
+		WHERE ` + bankMatchCandidateCondition + `
+		AND pv.payment_date >= $1::date
+		AND pv.payment_date < $2::date + INTERVAL '1 day'
+		UNION ALL
+		SELECT 0, cr.receipt_id, cr.receipt_number, cr.receipt_date, cr.payment_reference, cr.amount
+		FROM customer_receipts cr
+		WHERE ` + bankReceiptCandidateCondition + `
+		AND cr.receipt_date >= $1::date
+		AND cr.receipt_date < $2::date + INTERVAL '1 day'
+		ORDER BY 4, 1, 2`
+
+	rows, err := r.db.QueryContext(ctx, query, dateFrom, dateTo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bank match candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []products.BankMatchCandidate
+	for rows.Next() {
+		var candidate products.BankMatchCandidate
+		err := rows.Scan(
+			&candidate.VoucherID,
+			&candidate.ReceiptID,
+			&candidate.DocumentNumber,
+			&candidate.PaymentDate,
+			&candidate.PaymentReference,
+			&candidate.Amount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bank match candidate: %w", err)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
+// GetMatchCandidate retrieves a payment voucher if it can still be matched to a statement line
+func (r *BankStatementRepository) GetMatchCandidate(ctx context.Context, voucherID int) (*products.BankMatchCandidate, error) {
+	query := `
+		SELECT pv.voucher_id, pv.voucher_number, pv.payment_date, pv.payment_reference,
+			   ROUND(pv.amount * pv.exchange_rate, 2)
+		FROM supplier_payment_vouchers pv
+		WHERE pv.voucher_id = $1 AND ` + bankMatchCandidateCondition
+
+	var candidate products.BankMatchCandidate
+	err := r.db.QueryRowContext(ctx, query, voucherID).Scan(
+		&candidate.VoucherID,
+		&candidate.DocumentNumber,
+		&candidate.PaymentDate,
+		&candidate.PaymentReference,
+		&candidate.Amount,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("payment voucher not found, void, paid in cash or already reconciled")
+		}
+		return nil, fmt.Errorf("failed to get payment voucher: %w", err)
+	}
+
+	return &candidate, nil
+}
+
+// GetReceiptMatchCandidate retrieves a customer receipt if it can still be matched to a statement line
+func (r *BankStatementRepository) GetReceiptMatchCandidate(ctx context.Context, receiptID int) (*products.BankMatchCandidate, error) {
+	query := `
+		SELECT cr.receipt_id, cr.receipt_number, cr.receipt_date, cr.payment_reference, cr.amount
+		FROM customer_receipts cr
+		WHERE cr.receipt_id = $1 AND ` + bankReceiptCandidateCondition
+
+	var candidate products.BankMatchCandidate
+	err := r.db.QueryRowContext(ctx, query, receiptID).Scan(
+		&candidate.ReceiptID,
+		&candidate.DocumentNumber,
+		&candidate.PaymentDate,
+		&candidate.PaymentReference,
+		&candidate.Amount,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("customer receipt not found, void, received in cash or already reconciled")
+		}
+		return nil, fmt.Errorf("failed to get customer receipt: %w", err)
+	}
+
+	return &candidate, nil
+}
+
+// ApplyMatches reconciles statement lines with payments and receipts. Matches whose line, payment
+// or receipt was reconciled in the meantime are skipped; the number of matches applied is returned.
+func (r *BankStatementRepository) ApplyMatches(ctx context.Context, matches []products.BankStatementMatch, method products.BankMatchMethod, matchedBy int) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	applied := 0
+	for _, match := range matches {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE bank_statement_lines
+			SET match_status = 'matched', match_method = $1, voucher_id = NULLIF($2::int, 0),
+				receipt_id = NULLIF($3::int, 0), matched_by = $4, matched_at = NOW()
+			WHERE line_id = $5 AND match_status = 'unmatched'
+			AND NOT EXISTS (
+				SELECT 1 FROM bank_statement_lines bl
+				WHERE bl.voucher_id = $2::int OR bl.receipt_id = $3::int
+			)`,
+			method, match.VoucherID, match.ReceiptID, matchedBy, match.LineID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to match bank statement line: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to check rows affected: %w", err)
+		}
+		applied += int(rowsAffected)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return applied, nil
+}
+
+// Unmatch clears the payment or receipt matched to a statement line
+func (r *BankStatementRepository) Unmatch(ctx context.Context, lineID int) error {
+	result, err := unmatchBankStatementLines(ctx, r.db, "line_id = $1", lineID)
+	if err != nil {
+		return err
+	}
+
+	if result == 0 {
+		return fmt.Errorf("bank statement line not found or not matched")
+	}
+
+	return nil
+}
+
+// GetReconciliation summarizes statement lines per bank account for the period, with the
+// unreconciled non-cash payments made and customer receipts received in the same period
+func (r *BankStatementRepository) GetReconciliation(ctx context.Context, params *products.BankReconciliationParams) (*products.BankReconciliationReport, error) {
+	lineConditions := []string{"1=1"}
+	args := []interface{}{}
+	argIndex := 1
+
+	// Payments and receipts are not tied to a bank account, so only the period applies to them
+	paymentConditions := []string{bankMatchCandidateCondition}
+	receiptConditions := []string{bankReceiptCandidateCondition}
+	paymentArgs := []interface{}{}
+
+	if params.BankAccount != "" {
+		lineConditions = append(lineConditions, fmt.Sprintf("l.bank_account = $%d", argIndex))
+		args = append(args, params.BankAccount)
+		argIndex++
+	}
+
+	if params.DateFrom != nil {
+		lineConditions = append(lineConditions, fmt.Sprintf("l.transaction_date >= $%d", argIndex))
+		args = append(args, *params.DateFrom)
+		argIndex++
+		paymentArgs = append(paymentArgs, *params.DateFrom)
+		paymentConditions = append(paymentConditions, fmt.Sprintf("pv.payment_date >= $%d", len(paymentArgs)))
+		receiptConditions = append(receiptConditions, fmt.Sprintf("cr.receipt_date >= $%d", len(paymentArgs)))
+	}
+
+	if params.DateTo != nil {
+		lineConditions = append(lineConditions, fmt.Sprintf("l.transaction_date <= $%d", argIndex))
+		args = append(args, *params.DateTo)
+		paymentArgs = append(paymentArgs, *params.DateTo)
+		paymentConditions = append(paymentConditions, fmt.Sprintf("pv.payment_date < $%d::date + INTERVAL '1 day'", len(paymentArgs)))
+		receiptConditions = append(receiptConditions, fmt.Sprintf("cr.receipt_date < $%d::date + INTERVAL '1 day'", len(paymentArgs)))
+	}
+
+	query := `
+		SELECT l.bank_account,
+			   COUNT(*),
+			   COALESCE(SUM(-l.amount) FILTER (WHERE l.amount < 0), 0),
+			   COALESCE(SUM(l.amount) FILTER (WHERE l.amount > 0), 0),
+			   COUNT(*) FILTER (WHERE l.match_status = 'matched'),
+			   COALESCE(SUM(ABS(l.amount)) FILTER (WHERE l.match_status = 'matched'), 0),
+			   COUNT(*) FILTER (WHERE l.match_status = 'unmatched'),
+			   COALESCE(SUM(-l.amount) FILTER (WHERE l.match_status = 'unmatched' AND l.amount < 0), 0),
+			   COALESCE(SUM(l.amount) FILTER (WHERE l.match_status = 'unmatched' AND l.amount > 0), 0)
+		FROM bank_statement_lines l
+		WHERE ` + strings.Join(lineConditions, " AND ") + `
+		GROUP BY l.bank_account`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bank reconciliation: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []products.BankAccountReconciliation
+	for rows.Next() {
+		var account products.BankAccountReconciliation
+		err := rows.Scan(
+			&account.BankAccount,
+			&account.StatementLines,
+			&account.TotalDebit,
+			&account.TotalCredit,
+			&account.MatchedLines,
+			&account.MatchedAmount,
+			&account.UnmatchedLines,
+			&account.UnmatchedDebit,
+			&account.UnmatchedCredit,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bank reconciliation: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+
+	var payments, receipts products.BankUnreconciledTotals
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(ROUND(SUM(pv.amount * pv.exchange_rate), 2), 0)
+		FROM supplier_payment_vouchers pv
+		WHERE `+strings.Join(paymentConditions, " AND "), paymentArgs...).Scan(&payments.Count, &payments.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize unreconciled payments: %w", err)
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(cr.amount), 0)
+		FROM customer_receipts cr
+		WHERE `+strings.Join(receiptConditions, " AND "), paymentArgs...).Scan(&receipts.Count, &receipts.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize unreconciled receipts: %w", err)
+	}
+
+	return products.NewBankReconciliationReport(params, accounts, payments, receipts), nil
+}
+
+// unmatchBankStatementLines clears the matches of the statement lines matching the condition,
+// e.g. when the matched payment or receipt is voided
+func unmatchBankStatementLines(ctx context.Context, db sqlExecer, condition string, args ...interface{}) (int64, error) {
+	result, err := db.ExecContext(ctx, `
+		UPDATE bank_statement_lines
+		SET match_status = 'unmatched', match_method = NULL, voucher_id = NULL,
+			receipt_id = NULL, matched_by = NULL, matched_at = NULL
+		WHERE match_status = 'matched' AND `+condition, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to unmatch bank statement lines: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+func scanBankStatement(row rowScanner) (*products.BankStatement, error) {
+	statement := &products.BankStatement{}
+	err := row.Scan(
+		&statement.StatementID,
+		&statement.BankAccount,
+		&statement.FileName,
+		&statement.PeriodStart,
+		&statement.PeriodEnd,
+		&statement.LineCount,
+		&statement.MatchedCount,
+		&statement.TotalDebit,
+		&statement.TotalCredit,
+		&statement.ImportedBy,
+		&statement.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+func scanBankStatementLine(row rowScanner) (*products.BankStatementLine, error) {
+	line := &products.BankStatementLine{}
+	err := row.Scan(
+		&line.LineID,
+		&line.StatementID,
+		&line.BankAccount,
+		&line.LineNumber,
+		&line.TransactionDate,
+		&line.Description,
+		&line.Reference,
+		&line.Amount,
+		&line.Balance,
+		&line.Fingerprint,
+		&line.MatchStatus,
+		&line.MatchMethod,
+		&line.VoucherID,
+		&line.ReceiptID,
+		&line.MatchedBy,
+		&line.MatchedAt,
+		&line.VoucherNumber,
+		&line.ReceiptNumber,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return line, nil
+}
@@ -0,0 +1,314 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+const blanketOrderTermsSelectFields = `
+	po_id, agreed_quantity, agreed_value, valid_from, valid_to,
+	consumed_quantity, consumed_value, created_at, updated_at`
+
+// blanketLineBalancesQuery selects the lines of a blanket order with the quantity of each
+// product drawn by its active releases
+const blanketLineBalancesQuery = `
+		SELECT d.po_detail_id, d.product_id, d.item_description, d.unit_cost, d.tax_code_id,
+			   d.tax_rate, d.quantity_ordered,
+			   COALESCE((
+				   SELECT SUM(rd.quantity_ordered)
+				   FROM blanket_order_releases br
+				   JOIN purchase_order_details rd ON rd.po_id = br.release_po_id
+				   WHERE br.blanket_po_id = d.po_id
+					 AND br.release_status = 'active'
+					 AND rd.product_id = d.product_id
+			   ), 0)
+		FROM purchase_order_details d
+		WHERE d.po_id = $1
+		ORDER BY d.po_detail_id`
+
+// BlanketOrderRepository implements interfaces.BlanketOrderRepository
+type BlanketOrderRepository struct {
+	db *sql.DB
+}
+
+// NewBlanketOrderRepository creates a new blanket order repository
+func NewBlanketOrderRepository(db *sql.DB) interfaces.BlanketOrderRepository {
+	return &BlanketOrderRepository{db: db}
+}
+
+// CreateTerms stores the agreed terms of a blanket or contract purchase order
+func (r *BlanketOrderRepository) CreateTerms(ctx context.Context, terms *products.BlanketOrderTerms) (*products.BlanketOrderTerms, error) {
+	query := `
+		INSERT INTO blanket_order_terms (
+			po_id, agreed_quantity, agreed_value, valid_from, valid_to
+		) VALUES ($1, $2, $3, $4, $5)
+		RETURNING consumed_quantity, consumed_value, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		terms.POID,
+		terms.AgreedQuantity,
+		terms.AgreedValue,
+		terms.ValidFrom,
+		terms.ValidTo,
+	).Scan(&terms.ConsumedQuantity, &terms.ConsumedValue, &terms.CreatedAt, &terms.UpdatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blanket order terms: %w", err)
+	}
+
+	return terms, nil
+}
+
+// GetTerms retrieves the blanket terms of a purchase order
+func (r *BlanketOrderRepository) GetTerms(ctx context.Context, poID int) (*products.BlanketOrderTerms, error) {
+	query := "SELECT " + blanketOrderTermsSelectFields + `
+		FROM blanket_order_terms
+		WHERE po_id = $1`
+
+	return scanBlanketOrderTerms(r.db.QueryRowContext(ctx, query, poID))
+}
+
+// GetLineBalances retrieves the agreed and released quantity of each blanket order line
+func (r *BlanketOrderRepository) GetLineBalances(ctx context.Context, blanketPOID int) ([]products.BlanketLineBalance, error) {
+	return queryBlanketLineBalances(ctx, r.db, blanketPOID, "")
+}
+
+// queryBlanketLineBalances reads the blanket line balances, appending lock (e.g. FOR UPDATE) to the query
+func queryBlanketLineBalances(ctx context.Context, db sqlQuerier, blanketPOID int, lock string) ([]products.BlanketLineBalance, error) {
+	rows, err := db.QueryContext(ctx, blanketLineBalancesQuery+" "+lock, blanketPOID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blanket line balances: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []products.BlanketLineBalance
+	for rows.Next() {
+		var line products.BlanketLineBalance
+		err := rows.Scan(
+			&line.PODetailID,
+			&line.ProductID,
+			&line.ItemDescription,
+			&line.UnitCost,
+			&line.TaxCodeID,
+			&line.TaxRate,
+			&line.AgreedQuantity,
+			&line.ReleasedQuantity,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan blanket line balance: %w", err)
+		}
+		line.RemainingQuantity = line.AgreedQuantity - line.ReleasedQuantity
+		if line.RemainingQuantity < 0 {
+			line.RemainingQuantity = 0
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// CreateRelease creates a release order with its lines and draws it against the blanket order in
+// one transaction. The blanket terms and lines are locked first, so concurrent releases are checked
+// one after the other against the remaining line quantities and the agreed quantity and value.
+// The blanket order is completed once nothing is left to release.
+func (r *BlanketOrderRepository) CreateRelease(ctx context.Context, release *products.BlanketRelease, releasePO *products.PurchaseOrderParts, details []products.PurchaseOrderDetail) (*products.BlanketRelease, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	terms, err := scanBlanketOrderTerms(tx.QueryRowContext(ctx, "SELECT "+blanketOrderTermsSelectFields+`
+		FROM blanket_order_terms
+		WHERE po_id = $1
+		FOR UPDATE`, release.BlanketPOID))
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := queryBlanketLineBalances(ctx, tx, release.BlanketPOID, "FOR UPDATE OF d")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := products.CheckBlanketReleaseLines(lines, details); err != nil {
+		return nil, err
+	}
+
+	if err := terms.CanRelease(release.ReleaseQuantity, release.ReleaseValue, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := insertPurchaseOrder(ctx, tx, releasePO); err != nil {
+		return nil, err
+	}
+
+	for i := range details {
+		details[i].POID = releasePO.POID
+	}
+
+	if err := insertPurchaseOrderDetails(ctx, tx, details); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE blanket_order_terms
+		SET consumed_quantity = consumed_quantity + $1,
+			consumed_value = consumed_value + $2,
+			updated_at = NOW()
+		WHERE po_id = $3`,
+		release.ReleaseQuantity, release.ReleaseValue, release.BlanketPOID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume blanket balance: %w", err)
+	}
+
+	release.ReleasePOID = releasePO.POID
+	release.ReleaseStatus = products.BlanketReleaseStatusActive
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO blanket_order_releases (
+			blanket_po_id, release_po_id, release_quantity, release_value,
+			release_status, released_by
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING release_id, created_at`,
+		release.BlanketPOID,
+		release.ReleasePOID,
+		release.ReleaseQuantity,
+		release.ReleaseValue,
+		release.ReleaseStatus,
+		release.ReleasedBy,
+	).Scan(&release.ReleaseID, &release.CreatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blanket release: %w", err)
+	}
+
+	// Close the blanket once nothing is left to release
+	terms.ConsumedQuantity += release.ReleaseQuantity
+	terms.ConsumedValue += release.ReleaseValue
+	if terms.IsExhausted() {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE purchase_orders_parts SET status = $2, updated_at = NOW() WHERE po_id = $1`,
+			release.BlanketPOID, products.POStatusCompleted)
+		if err != nil {
+			return nil, fmt.Errorf("failed to complete exhausted blanket order: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return release, nil
+}
+
+// CancelRelease cancels the active release for a release order and returns its balance to the blanket order.
+// It reports false when the purchase order is not an active release.
+func (r *BlanketOrderRepository) CancelRelease(ctx context.Context, releasePOID int) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var blanketPOID, quantity int
+	var value float64
+	err = tx.QueryRowContext(ctx, `
+		UPDATE blanket_order_releases
+		SET release_status = 'cancelled'
+		WHERE release_po_id = $1 AND release_status = 'active'
+		RETURNING blanket_po_id, release_quantity, release_value`, releasePOID).Scan(&blanketPOID, &quantity, &value)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to cancel blanket release: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE blanket_order_terms
+		SET consumed_quantity = GREATEST(consumed_quantity - $1, 0),
+			consumed_value = GREATEST(consumed_value - $2, 0),
+			updated_at = NOW()
+		WHERE po_id = $3`, quantity, value, blanketPOID)
+	if err != nil {
+		return false, fmt.Errorf("failed to restore blanket balance: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// ListReleases retrieves all releases drawn against a blanket order
+func (r *BlanketOrderRepository) ListReleases(ctx context.Context, blanketPOID int) ([]products.BlanketRelease, error) {
+	query := `
+		SELECT br.release_id, br.blanket_po_id, br.release_po_id, br.release_quantity,
+			   br.release_value, br.release_status, br.released_by, br.created_at,
+			   po.po_number, po.status
+		FROM blanket_order_releases br
+		JOIN purchase_orders_parts po ON po.po_id = br.release_po_id
+		WHERE br.blanket_po_id = $1
+		ORDER BY br.created_at DESC, br.release_id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, blanketPOID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blanket releases: %w", err)
+	}
+	defer rows.Close()
+
+	var releases []products.BlanketRelease
+	for rows.Next() {
+		var release products.BlanketRelease
+		var status string
+		err := rows.Scan(
+			&release.ReleaseID,
+			&release.BlanketPOID,
+			&release.ReleasePOID,
+			&release.ReleaseQuantity,
+			&release.ReleaseValue,
+			&status,
+			&release.ReleasedBy,
+			&release.CreatedAt,
+			&release.ReleasePONumber,
+			&release.ReleasePOStatus,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan blanket release: %w", err)
+		}
+		release.ReleaseStatus = products.BlanketReleaseStatus(status)
+		releases = append(releases, release)
+	}
+
+	return releases, nil
+}
+
+func scanBlanketOrderTerms(row rowScanner) (*products.BlanketOrderTerms, error) {
+	terms := &products.BlanketOrderTerms{}
+	err := row.Scan(
+		&terms.POID,
+		&terms.AgreedQuantity,
+		&terms.AgreedValue,
+		&terms.ValidFrom,
+		&terms.ValidTo,
+		&terms.ConsumedQuantity,
+		&terms.ConsumedValue,
+		&terms.CreatedAt,
+		&terms.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("blanket order terms not found")
+		}
+		return nil, fmt.Errorf("failed to get blanket order terms: %w", err)
+	}
+
+	return terms, nil
+}
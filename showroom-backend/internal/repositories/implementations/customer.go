@@ -25,8 +25,8 @@ func NewCustomerRepository(db *sql.DB) interfaces.CustomerRepository {
 // Create creates a new customer
 func (r *CustomerRepository) Create(ctx context.Context, customer *master.Customer) (*master.Customer, error) {
 	query := `
-		INSERT INTO customers (customer_code, customer_name, customer_type, phone, email, address, city, postal_code, tax_number, contact_person, notes, created_by)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		INSERT INTO customers (customer_code, customer_name, customer_type, phone, email, address, city, postal_code, tax_number, contact_person, notes, credit_limit, payment_term_id, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING customer_id, created_at, updated_at`
 
 	err := r.db.QueryRowContext(ctx, query,
@@ -41,6 +41,8 @@ func (r *CustomerRepository) Create(ctx context.Context, customer *master.Custom
 		customer.TaxNumber,
 		customer.ContactPerson,
 		customer.Notes,
+		customer.CreditLimit,
+		customer.PaymentTermID,
 		customer.CreatedBy,
 	).Scan(&customer.CustomerID, &customer.CreatedAt, &customer.UpdatedAt)
 
@@ -55,7 +57,7 @@ func (r *CustomerRepository) Create(ctx context.Context, customer *master.Custom
 // GetByID retrieves a customer by ID
 func (r *CustomerRepository) GetByID(ctx context.Context, id int) (*master.Customer, error) {
 	query := `
-		SELECT customer_id, customer_code, customer_name, customer_type, phone, email, address, city, postal_code, tax_number, contact_person, notes, is_active, created_at, updated_at, created_by
+		SELECT customer_id, customer_code, customer_name, customer_type, phone, email, address, city, postal_code, tax_number, contact_person, notes, credit_limit, payment_term_id, is_active, created_at, updated_at, created_by
 		FROM customers
 		WHERE customer_id = $1`
 
@@ -73,6 +75,8 @@ func (r *CustomerRepository) GetByID(ctx context.Context, id int) (*master.Custo
 		&customer.TaxNumber,
 		&customer.ContactPerson,
 		&customer.Notes,
+		&customer.CreditLimit,
+		&customer.PaymentTermID,
 		&customer.IsActive,
 		&customer.CreatedAt,
 		&customer.UpdatedAt,
@@ -92,7 +96,7 @@ func (r *CustomerRepository) GetByID(ctx context.Context, id int) (*master.Custo
 // GetByCode retrieves a customer by code
 func (r *CustomerRepository) GetByCode(ctx context.Context, code string) (*master.Customer, error) {
 	query := `
-		SELECT customer_id, customer_code, customer_name, customer_type, phone, email, address, city, postal_code, tax_number, contact_person, notes, is_active, created_at, updated_at, created_by
+		SELECT customer_id, customer_code, customer_name, customer_type, phone, email, address, city, postal_code, tax_number, contact_person, notes, credit_limit, payment_term_id, is_active, created_at, updated_at, created_by
 		FROM customers
 		WHERE customer_code = $1`
 
@@ -110,6 +114,8 @@ func (r *CustomerRepository) GetByCode(ctx context.Context, code string) (*maste
 		&customer.TaxNumber,
 		&customer.ContactPerson,
 		&customer.Notes,
+		&customer.CreditLimit,
+		&customer.PaymentTermID,
 		&customer.IsActive,
 		&customer.CreatedAt,
 		&customer.UpdatedAt,
@@ -130,8 +136,8 @@ func (r *CustomerRepository) GetByCode(ctx context.Context, code string) (*maste
 func (r *CustomerRepository) Update(ctx context.Context, id int, customer *master.Customer) (*master.Customer, error) {
 	query := `
 		UPDATE customers
-		SET customer_name = $1, customer_type = $2, phone = $3, email = $4, address = $5, city = $6, postal_code = $7, tax_number = $8, contact_person = $9, notes = $10, is_active = $11, updated_at = NOW()
-		WHERE customer_id = $12
+		SET customer_name = $1, customer_type = $2, phone = $3, email = $4, address = $5, city = $6, postal_code = $7, tax_number = $8, contact_person = $9, notes = $10, is_active = $11, credit_limit = $12, payment_term_id = $13, updated_at = NOW()
+		WHERE customer_id = $14
 		RETURNING updated_at`
 
 	err := r.db.QueryRowContext(ctx, query,
@@ -146,6 +152,8 @@ func (r *CustomerRepository) Update(ctx context.Context, id int, customer *maste
 		customer.ContactPerson,
 		customer.Notes,
 		customer.IsActive,
+		customer.CreditLimit,
+		customer.PaymentTermID,
 		id,
 	).Scan(&customer.UpdatedAt)
 
@@ -0,0 +1,521 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// customerReceiptAllocatedExpr is the amount of a receipt aliased cr already allocated to sales invoices
+const customerReceiptAllocatedExpr = `COALESCE((
+	SELECT SUM(ra.allocated_amount) FROM customer_receipt_allocations ra WHERE ra.receipt_id = cr.receipt_id
+), 0)`
+
+const customerReceiptSelectFields = `
+	cr.receipt_id, cr.receipt_number, cr.customer_id, c.customer_name, cr.receipt_date,
+	cr.payment_method, cr.payment_reference, cr.amount, ` + customerReceiptAllocatedExpr + `,
+	cr.receipt_status, cr.notes, cr.received_by, cr.voided_by, cr.voided_at, cr.created_at, cr.updated_at`
+
+// CustomerReceiptRepository implements interfaces.CustomerReceiptRepository
+type CustomerReceiptRepository struct {
+	db *sql.DB
+}
+
+// NewCustomerReceiptRepository creates a new customer receipt repository
+func NewCustomerReceiptRepository(db *sql.DB) interfaces.CustomerReceiptRepository {
+	return &CustomerReceiptRepository{db: db}
+}
+
+// Create records a customer receipt, allocates it to sales invoices and posts it to the general
+// ledger in one transaction
+func (r *CustomerReceiptRepository) Create(ctx context.Context, receipt *products.CustomerReceipt, allocations []products.CustomerReceiptAllocationRequest) (*products.CustomerReceipt, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	invoices, err := getSalesInvoicesForUpdate(ctx, tx, customerReceiptInvoiceIDs(allocations))
+	if err != nil {
+		return nil, err
+	}
+
+	receipt.AllocatedAmount = 0
+	receipt.ReceiptStatus = products.CustomerReceiptStatusPosted
+	if err := receipt.ValidateAllocations(allocations, invoices); err != nil {
+		return nil, err
+	}
+
+	receipt.ReceiptNumber, err = generateCustomerReceiptNumber(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO customer_receipts (
+			receipt_number, customer_id, receipt_date, payment_method, payment_reference,
+			amount, receipt_status, notes, received_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING receipt_id, created_at, updated_at`,
+		receipt.ReceiptNumber,
+		receipt.CustomerID,
+		receipt.ReceiptDate,
+		receipt.PaymentMethod,
+		receipt.PaymentReference,
+		receipt.Amount,
+		receipt.ReceiptStatus,
+		receipt.Notes,
+		receipt.ReceivedBy,
+	).Scan(&receipt.ReceiptID, &receipt.CreatedAt, &receipt.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create customer receipt: %w", err)
+	}
+
+	if err := insertCustomerReceiptAllocations(ctx, tx, receipt.ReceiptID, allocations); err != nil {
+		return nil, err
+	}
+
+	if err := postJournalEntry(ctx, tx, products.CustomerReceiptJournal(receipt)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByID(ctx, receipt.ReceiptID)
+}
+
+// GetByID retrieves a customer receipt by ID with its allocations
+func (r *CustomerReceiptRepository) GetByID(ctx context.Context, id int) (*products.CustomerReceipt, error) {
+	receipt, err := getCustomerReceipt(ctx, r.db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT ra.allocation_id, ra.receipt_id, ra.invoice_id, si.invoice_number, ra.allocated_amount, ra.created_at
+		FROM customer_receipt_allocations ra
+		JOIN sales_invoices si ON si.invoice_id = ra.invoice_id
+		WHERE ra.receipt_id = $1
+		ORDER BY ra.allocation_id ASC`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query customer receipt allocations: %w", err)
+	}
+	defer rows.Close()
+
+	receipt.Allocations = []products.CustomerReceiptAllocation{}
+	for rows.Next() {
+		var allocation products.CustomerReceiptAllocation
+		err := rows.Scan(
+			&allocation.AllocationID,
+			&allocation.ReceiptID,
+			&allocation.InvoiceID,
+			&allocation.InvoiceNumber,
+			&allocation.AllocatedAmount,
+			&allocation.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan customer receipt allocation: %w", err)
+		}
+		receipt.Allocations = append(receipt.Allocations, allocation)
+	}
+
+	return receipt, nil
+}
+
+// getCustomerReceipt loads a receipt header with its allocated and unallocated amounts
+func getCustomerReceipt(ctx context.Context, db sqlRowQuerier, id int) (*products.CustomerReceipt, error) {
+	query := "SELECT " + customerReceiptSelectFields + `
+		FROM customer_receipts cr
+		JOIN customers c ON c.customer_id = cr.customer_id
+		WHERE cr.receipt_id = $1`
+
+	receipt, err := scanCustomerReceipt(db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("customer receipt not found")
+		}
+		return nil, fmt.Errorf("failed to get customer receipt: %w", err)
+	}
+
+	return receipt, nil
+}
+
+// List retrieves customer receipts with pagination
+func (r *CustomerReceiptRepository) List(ctx context.Context, params *products.CustomerReceiptFilterParams) (*common.PaginatedResponse, error) {
+	baseQuery := `FROM customer_receipts cr JOIN customers c ON c.customer_id = cr.customer_id WHERE 1=1`
+
+	args := []interface{}{}
+	whereConditions := []string{}
+	argIndex := 1
+
+	// Add filters
+	if params.CustomerID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("cr.customer_id = $%d", argIndex))
+		args = append(args, *params.CustomerID)
+		argIndex++
+	}
+
+	if params.InvoiceID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("cr.receipt_id IN (SELECT receipt_id FROM customer_receipt_allocations WHERE invoice_id = $%d)", argIndex))
+		args = append(args, *params.InvoiceID)
+		argIndex++
+	}
+
+	if params.PaymentMethod != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("cr.payment_method = $%d", argIndex))
+		args = append(args, *params.PaymentMethod)
+		argIndex++
+	}
+
+	if params.ReceiptStatus != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("cr.receipt_status = $%d", argIndex))
+		args = append(args, *params.ReceiptStatus)
+		argIndex++
+	}
+
+	if params.DateFrom != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("cr.receipt_date >= $%d", argIndex))
+		args = append(args, *params.DateFrom)
+		argIndex++
+	}
+
+	if params.DateTo != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("cr.receipt_date <= $%d", argIndex))
+		args = append(args, *params.DateTo)
+		argIndex++
+	}
+
+	if params.Search != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("(cr.receipt_number ILIKE $%d OR cr.payment_reference ILIKE $%d OR c.customer_name ILIKE $%d)", argIndex, argIndex, argIndex))
+		args = append(args, "%"+params.Search+"%")
+		argIndex++
+	}
+
+	if len(whereConditions) > 0 {
+		baseQuery += " AND " + strings.Join(whereConditions, " AND ")
+	}
+
+	// Count query
+	countQuery := "SELECT COUNT(*) " + baseQuery
+	var total int64
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count customer receipts: %w", err)
+	}
+
+	// Calculate pagination
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	// Main query
+	mainQuery := "SELECT " + customerReceiptSelectFields + " " + baseQuery +
+		" ORDER BY cr.receipt_date DESC, cr.receipt_id DESC LIMIT $" + fmt.Sprintf("%d", argIndex) +
+		" OFFSET $" + fmt.Sprintf("%d", argIndex+1)
+
+	args = append(args, params.Limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, mainQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query customer receipts: %w", err)
+	}
+	defer rows.Close()
+
+	var receipts []products.CustomerReceipt
+	for rows.Next() {
+		receipt, err := scanCustomerReceipt(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan customer receipt: %w", err)
+		}
+		receipts = append(receipts, *receipt)
+	}
+
+	totalPages := (total + int64(params.Limit) - 1) / int64(params.Limit)
+
+	return &common.PaginatedResponse{
+		Data:       receipts,
+		Total:      int(total),
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: int(totalPages),
+		HasMore:    params.Page < int(totalPages),
+	}, nil
+}
+
+// Allocate allocates the unallocated amount of a posted receipt to sales invoices
+func (r *CustomerReceiptRepository) Allocate(ctx context.Context, id int, allocations []products.CustomerReceiptAllocationRequest) (*products.CustomerReceipt, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockCustomerReceipt(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	receipt, err := getCustomerReceipt(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, err := getSalesInvoicesForUpdate(ctx, tx, customerReceiptInvoiceIDs(allocations))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := receipt.ValidateAllocations(allocations, invoices); err != nil {
+		return nil, err
+	}
+
+	if err := insertCustomerReceiptAllocations(ctx, tx, id, allocations); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE customer_receipts SET updated_at = NOW() WHERE receipt_id = $1", id); err != nil {
+		return nil, fmt.Errorf("failed to update customer receipt: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// Void voids a posted receipt so its allocations no longer settle the invoices and reverses its journal
+func (r *CustomerReceiptRepository) Void(ctx context.Context, id int, voidedBy int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockCustomerReceipt(ctx, tx, id); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE customer_receipts
+		SET receipt_status = 'void', voided_by = $2, voided_at = NOW(), updated_at = NOW()
+		WHERE receipt_id = $1 AND receipt_status = 'posted'`, id, voidedBy)
+	if err != nil {
+		return fmt.Errorf("failed to void customer receipt: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("customer receipt is already void")
+	}
+
+	// A void receipt no longer explains the bank transaction it was reconciled with
+	if _, err := unmatchBankStatementLines(ctx, tx, "receipt_id = $1", id); err != nil {
+		return err
+	}
+
+	if err := reverseSourceJournals(ctx, tx, products.JournalSourceCustomerReceipt, id, time.Now(), &voidedBy); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetStatementActivity returns the balance a customer owed before periodStart and the issued
+// invoices and posted receipts dated within the period
+func (r *CustomerReceiptRepository) GetStatementActivity(ctx context.Context, customerID int, periodStart, periodEnd time.Time) (float64, []products.CustomerStatementLine, error) {
+	var openingBalance float64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			COALESCE((SELECT SUM(total_amount) FROM sales_invoices
+				WHERE customer_id = $1 AND invoice_status = 'issued' AND invoice_date < $2), 0)
+			- COALESCE((SELECT SUM(amount) FROM customer_receipts
+				WHERE customer_id = $1 AND receipt_status = 'posted' AND receipt_date < $2), 0)`,
+		customerID, periodStart).Scan(&openingBalance)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get customer opening balance: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT invoice_date, 'invoice', invoice_number, NULL, due_date, total_amount, 0
+		FROM sales_invoices
+		WHERE customer_id = $1 AND invoice_status = 'issued' AND invoice_date BETWEEN $2 AND $3
+		UNION ALL
+		SELECT receipt_date, 'receipt', receipt_number, payment_reference, NULL, 0, amount
+		FROM customer_receipts
+		WHERE customer_id = $1 AND receipt_status = 'posted' AND receipt_date BETWEEN $2 AND $3
+		ORDER BY 1, 3`, customerID, periodStart, periodEnd)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to query customer statement lines: %w", err)
+	}
+	defer rows.Close()
+
+	lines := []products.CustomerStatementLine{}
+	for rows.Next() {
+		var line products.CustomerStatementLine
+		err := rows.Scan(
+			&line.Date,
+			&line.DocumentType,
+			&line.DocumentNumber,
+			&line.Reference,
+			&line.DueDate,
+			&line.Debit,
+			&line.Credit,
+		)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to scan customer statement line: %w", err)
+		}
+		lines = append(lines, line)
+	}
+
+	if err := rows.Err(); err != nil {
+		return 0, nil, fmt.Errorf("failed to iterate customer statement lines: %w", err)
+	}
+
+	return openingBalance, lines, nil
+}
+
+// generateCustomerReceiptNumber generates the next receipt number with format CR-YYYYMMDD-XXXX
+func generateCustomerReceiptNumber(ctx context.Context, db sqlRowQuerier) (string, error) {
+	dateStr := time.Now().Format("20060102")
+
+	query := `
+		SELECT COALESCE(MAX(
+			CAST(SUBSTRING(receipt_number FROM 'CR-\d{8}-(\d+)') AS INTEGER)
+		), 0) + 1
+		FROM customer_receipts
+		WHERE receipt_number LIKE $1`
+
+	var nextNumber int
+	err := db.QueryRowContext(ctx, query, "CR-"+dateStr+"-%").Scan(&nextNumber)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate customer receipt number: %w", err)
+	}
+
+	return fmt.Sprintf("CR-%s-%04d", dateStr, nextNumber), nil
+}
+
+// insertCustomerReceiptAllocations stores the allocations of a receipt
+func insertCustomerReceiptAllocations(ctx context.Context, tx *sql.Tx, receiptID int, allocations []products.CustomerReceiptAllocationRequest) error {
+	for _, allocation := range allocations {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO customer_receipt_allocations (receipt_id, invoice_id, allocated_amount)
+			VALUES ($1, $2, $3)`,
+			receiptID, allocation.InvoiceID, allocation.Amount)
+		if err != nil {
+			return fmt.Errorf("failed to create customer receipt allocation: %w", err)
+		}
+	}
+	return nil
+}
+
+// lockCustomerReceipt locks a receipt row so concurrent allocations see each other
+func lockCustomerReceipt(ctx context.Context, tx *sql.Tx, id int) error {
+	var receiptID int
+	err := tx.QueryRowContext(ctx, "SELECT receipt_id FROM customer_receipts WHERE receipt_id = $1 FOR UPDATE", id).Scan(&receiptID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("customer receipt not found")
+		}
+		return fmt.Errorf("failed to lock customer receipt: %w", err)
+	}
+	return nil
+}
+
+// getSalesInvoicesForUpdate loads and locks sales invoices keyed by invoice ID
+func getSalesInvoicesForUpdate(ctx context.Context, tx *sql.Tx, ids []int) (map[int]*products.SalesInvoice, error) {
+	invoices := make(map[int]*products.SalesInvoice, len(ids))
+	if len(ids) == 0 {
+		return invoices, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+
+	query := "SELECT " + salesInvoiceSelectFields + `
+		FROM sales_invoices si
+		JOIN customers c ON c.customer_id = si.customer_id
+		WHERE si.invoice_id IN (` + strings.Join(placeholders, ", ") + `)
+		ORDER BY si.invoice_id
+		FOR UPDATE OF si`
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sales invoices: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		invoice, err := scanSalesInvoice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sales invoice: %w", err)
+		}
+		invoices[invoice.InvoiceID] = invoice
+	}
+
+	return invoices, nil
+}
+
+// customerReceiptInvoiceIDs returns the distinct invoice IDs referenced by allocations
+func customerReceiptInvoiceIDs(allocations []products.CustomerReceiptAllocationRequest) []int {
+	seen := make(map[int]bool, len(allocations))
+	ids := make([]int, 0, len(allocations))
+	for _, allocation := range allocations {
+		if !seen[allocation.InvoiceID] {
+			seen[allocation.InvoiceID] = true
+			ids = append(ids, allocation.InvoiceID)
+		}
+	}
+	return ids
+}
+
+func scanCustomerReceipt(row rowScanner) (*products.CustomerReceipt, error) {
+	receipt := &products.CustomerReceipt{}
+	err := row.Scan(
+		&receipt.ReceiptID,
+		&receipt.ReceiptNumber,
+		&receipt.CustomerID,
+		&receipt.CustomerName,
+		&receipt.ReceiptDate,
+		&receipt.PaymentMethod,
+		&receipt.PaymentReference,
+		&receipt.Amount,
+		&receipt.AllocatedAmount,
+		&receipt.ReceiptStatus,
+		&receipt.Notes,
+		&receipt.ReceivedBy,
+		&receipt.VoidedBy,
+		&receipt.VoidedAt,
+		&receipt.CreatedAt,
+		&receipt.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	receipt.UnallocatedAmount = receipt.Amount - receipt.AllocatedAmount
+	return receipt, nil
+}
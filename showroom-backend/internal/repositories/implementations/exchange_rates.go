@@ -0,0 +1,206 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// ExchangeRateRepository implements interfaces.ExchangeRateRepository
+type ExchangeRateRepository struct {
+	db *sql.DB
+}
+
+// NewExchangeRateRepository creates a new exchange rate repository
+func NewExchangeRateRepository(db *sql.DB) interfaces.ExchangeRateRepository {
+	return &ExchangeRateRepository{db: db}
+}
+
+const upsertExchangeRateQuery = `
+	INSERT INTO exchange_rates (currency_code, rate_date, rate, source, created_by)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (currency_code, rate_date) DO UPDATE SET
+		rate = EXCLUDED.rate, source = EXCLUDED.source,
+		created_by = EXCLUDED.created_by, updated_at = NOW()
+	RETURNING rate_id, created_at, updated_at`
+
+// Upsert creates the rate of a currency on a date or replaces the one already entered
+func (r *ExchangeRateRepository) Upsert(ctx context.Context, rate *products.ExchangeRate) (*products.ExchangeRate, error) {
+	err := r.db.QueryRowContext(ctx, upsertExchangeRateQuery,
+		rate.CurrencyCode,
+		rate.RateDate,
+		rate.Rate,
+		rate.Source,
+		rate.CreatedBy,
+	).Scan(&rate.RateID, &rate.CreatedAt, &rate.UpdatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to save exchange rate: %w", err)
+	}
+
+	return rate, nil
+}
+
+// BulkUpsert saves imported rates in one transaction so a failing row leaves no partial import
+func (r *ExchangeRateRepository) BulkUpsert(ctx context.Context, rates []products.ExchangeRate, createdBy int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i := range rates {
+		rate := &rates[i]
+		rate.CreatedBy = createdBy
+		err := tx.QueryRowContext(ctx, upsertExchangeRateQuery,
+			rate.CurrencyCode,
+			rate.RateDate,
+			rate.Rate,
+			rate.Source,
+			rate.CreatedBy,
+		).Scan(&rate.RateID, &rate.CreatedAt, &rate.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to save exchange rate for %s on %s: %w",
+				rate.CurrencyCode, rate.RateDate.Format("2006-01-02"), err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves exchange rates with pagination, newest first
+func (r *ExchangeRateRepository) List(ctx context.Context, params *products.ExchangeRateFilterParams) (*common.PaginatedResponse, error) {
+	baseQuery := `FROM exchange_rates er WHERE 1=1`
+
+	args := []interface{}{}
+	whereConditions := []string{}
+	argIndex := 1
+
+	// Add filters
+	if params.CurrencyCode != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("er.currency_code = $%d", argIndex))
+		args = append(args, strings.ToUpper(params.CurrencyCode))
+		argIndex++
+	}
+
+	if params.DateFrom != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("er.rate_date >= $%d", argIndex))
+		args = append(args, *params.DateFrom)
+		argIndex++
+	}
+
+	if params.DateTo != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("er.rate_date <= $%d", argIndex))
+		args = append(args, *params.DateTo)
+		argIndex++
+	}
+
+	if len(whereConditions) > 0 {
+		baseQuery += " AND " + strings.Join(whereConditions, " AND ")
+	}
+
+	// Count query
+	countQuery := "SELECT COUNT(*) " + baseQuery
+	var total int64
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count exchange rates: %w", err)
+	}
+
+	// Calculate pagination
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	// Main query
+	selectFields := `
+		er.rate_id, er.currency_code, er.rate_date, er.rate, er.source,
+		er.created_by, er.created_at, er.updated_at`
+
+	mainQuery := "SELECT " + selectFields + " " + baseQuery +
+		" ORDER BY er.rate_date DESC, er.currency_code ASC LIMIT $" + fmt.Sprintf("%d", argIndex) +
+		" OFFSET $" + fmt.Sprintf("%d", argIndex+1)
+
+	args = append(args, params.Limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, mainQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query exchange rates: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []products.ExchangeRate
+	for rows.Next() {
+		var rate products.ExchangeRate
+		err := rows.Scan(
+			&rate.RateID,
+			&rate.CurrencyCode,
+			&rate.RateDate,
+			&rate.Rate,
+			&rate.Source,
+			&rate.CreatedBy,
+			&rate.CreatedAt,
+			&rate.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan exchange rate: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+
+	totalPages := (total + int64(params.Limit) - 1) / int64(params.Limit)
+
+	return &common.PaginatedResponse{
+		Data:       rates,
+		Total:      int(total),
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: int(totalPages),
+		HasMore:    params.Page < int(totalPages),
+	}, nil
+}
+
+// GetRate retrieves the rate of a currency in effect on a date
+func (r *ExchangeRateRepository) GetRate(ctx context.Context, currencyCode string, date time.Time) (float64, error) {
+	return exchangeRateOn(ctx, r.db, currencyCode, date)
+}
+
+// exchangeRateOn returns the latest rate of a currency dated on or before date. The base
+// currency always has a rate of 1.
+func exchangeRateOn(ctx context.Context, db sqlRowQuerier, currencyCode string, date time.Time) (float64, error) {
+	if currencyCode == "" || currencyCode == products.BaseCurrency {
+		return 1, nil
+	}
+
+	query := `
+		SELECT rate FROM exchange_rates
+		WHERE currency_code = $1 AND rate_date <= $2
+		ORDER BY rate_date DESC
+		LIMIT 1`
+
+	var rate float64
+	err := db.QueryRowContext(ctx, query, currencyCode, date).Scan(&rate)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("no exchange rate for %s on or before %s", currencyCode, date.Format("2006-01-02"))
+		}
+		return 0, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+
+	return rate, nil
+}
@@ -0,0 +1,264 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// GLAccountRepository implements interfaces.GLAccountRepository
+type GLAccountRepository struct {
+	db *sql.DB
+}
+
+// NewGLAccountRepository creates a new general ledger account repository
+func NewGLAccountRepository(db *sql.DB) interfaces.GLAccountRepository {
+	return &GLAccountRepository{db: db}
+}
+
+// Create adds an account to the chart of accounts
+func (r *GLAccountRepository) Create(ctx context.Context, account *products.GLAccount) (*products.GLAccount, error) {
+	query := `
+		INSERT INTO gl_accounts (account_code, account_name, account_type, description)
+		VALUES ($1, $2, $3, $4)
+		RETURNING account_id, is_active, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		account.AccountCode,
+		account.AccountName,
+		account.AccountType,
+		account.Description,
+	).Scan(&account.AccountID, &account.IsActive, &account.CreatedAt, &account.UpdatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create account: %w", err)
+	}
+
+	return account, nil
+}
+
+// GetByID retrieves an account by ID
+func (r *GLAccountRepository) GetByID(ctx context.Context, id int) (*products.GLAccount, error) {
+	query := `
+		SELECT account_id, account_code, account_name, account_type, description,
+			   is_active, created_at, updated_at
+		FROM gl_accounts
+		WHERE account_id = $1`
+
+	account := &products.GLAccount{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&account.AccountID,
+		&account.AccountCode,
+		&account.AccountName,
+		&account.AccountType,
+		&account.Description,
+		&account.IsActive,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("account not found")
+		}
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	return account, nil
+}
+
+// Update updates the name, description and active flag of an account
+func (r *GLAccountRepository) Update(ctx context.Context, id int, account *products.GLAccount) (*products.GLAccount, error) {
+	query := `
+		UPDATE gl_accounts SET
+			account_name = $1, description = $2, is_active = $3, updated_at = NOW()
+		WHERE account_id = $4
+		RETURNING updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		account.AccountName,
+		account.Description,
+		account.IsActive,
+		id,
+	).Scan(&account.UpdatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("account not found")
+		}
+		return nil, fmt.Errorf("failed to update account: %w", err)
+	}
+
+	account.AccountID = id
+	return account, nil
+}
+
+// List retrieves accounts with pagination
+func (r *GLAccountRepository) List(ctx context.Context, params *products.GLAccountFilterParams) (*common.PaginatedResponse, error) {
+	baseQuery := `FROM gl_accounts a WHERE 1=1`
+
+	args := []interface{}{}
+	whereConditions := []string{}
+	argIndex := 1
+
+	// Add filters
+	if params.AccountType != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.account_type = $%d", argIndex))
+		args = append(args, *params.AccountType)
+		argIndex++
+	}
+
+	if params.IsActive != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.is_active = $%d", argIndex))
+		args = append(args, *params.IsActive)
+		argIndex++
+	}
+
+	if params.Search != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("(a.account_code ILIKE $%d OR a.account_name ILIKE $%d)", argIndex, argIndex))
+		args = append(args, "%"+params.Search+"%")
+		argIndex++
+	}
+
+	if len(whereConditions) > 0 {
+		baseQuery += " AND " + strings.Join(whereConditions, " AND ")
+	}
+
+	// Count query
+	countQuery := "SELECT COUNT(*) " + baseQuery
+	var total int64
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count accounts: %w", err)
+	}
+
+	// Calculate pagination
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	// Main query
+	selectFields := `
+		a.account_id, a.account_code, a.account_name, a.account_type, a.description,
+		a.is_active, a.created_at, a.updated_at`
+
+	mainQuery := "SELECT " + selectFields + " " + baseQuery +
+		" ORDER BY a.account_code ASC LIMIT $" + fmt.Sprintf("%d", argIndex) +
+		" OFFSET $" + fmt.Sprintf("%d", argIndex+1)
+
+	args = append(args, params.Limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, mainQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []products.GLAccount
+	for rows.Next() {
+		var account products.GLAccount
+		err := rows.Scan(
+			&account.AccountID,
+			&account.AccountCode,
+			&account.AccountName,
+			&account.AccountType,
+			&account.Description,
+			&account.IsActive,
+			&account.CreatedAt,
+			&account.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+
+	totalPages := (total + int64(params.Limit) - 1) / int64(params.Limit)
+
+	return &common.PaginatedResponse{
+		Data:       accounts,
+		Total:      int(total),
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: int(totalPages),
+		HasMore:    params.Page < int(totalPages),
+	}, nil
+}
+
+// IsCodeExists checks if an account code is already used
+func (r *GLAccountRepository) IsCodeExists(ctx context.Context, code string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM gl_accounts WHERE account_code = $1)`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, code).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check account code existence: %w", err)
+	}
+
+	return exists, nil
+}
+
+// IsPostingRuleAccount checks if automatic postings book to the account
+func (r *GLAccountRepository) IsPostingRuleAccount(ctx context.Context, id int) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM gl_posting_rules WHERE account_id = $1)`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check posting rules: %w", err)
+	}
+
+	return exists, nil
+}
+
+// GetPostingRules retrieves the account each posting rule books to
+func (r *GLAccountRepository) GetPostingRules(ctx context.Context) ([]products.GLPostingRule, error) {
+	query := `
+		SELECT pr.rule_key, pr.account_id, a.account_code, a.account_name, pr.updated_at
+		FROM gl_posting_rules pr
+		JOIN gl_accounts a ON a.account_id = pr.account_id
+		ORDER BY pr.rule_key`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query posting rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []products.GLPostingRule
+	for rows.Next() {
+		var rule products.GLPostingRule
+		err := rows.Scan(&rule.RuleKey, &rule.AccountID, &rule.AccountCode, &rule.AccountName, &rule.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan posting rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// UpdatePostingRule points a posting rule at another account. Entries already posted keep their accounts.
+func (r *GLAccountRepository) UpdatePostingRule(ctx context.Context, rule products.PostingRule, accountID int) error {
+	query := `
+		INSERT INTO gl_posting_rules (rule_key, account_id)
+		VALUES ($1, $2)
+		ON CONFLICT (rule_key) DO UPDATE SET account_id = EXCLUDED.account_id, updated_at = NOW()`
+
+	_, err := r.db.ExecContext(ctx, query, rule, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to update posting rule: %w", err)
+	}
+
+	return nil
+}
@@ -28,8 +28,9 @@ func (r *GoodsReceiptRepository) Create(ctx context.Context, receipt *products.G
 		INSERT INTO goods_receipts (
 			po_id, receipt_number, receipt_date, received_by,
 			supplier_delivery_note, supplier_invoice_number, total_received_value,
-			receipt_status, receipt_notes, discrepancy_notes, receipt_documents_json
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			receipt_status, receipt_notes, discrepancy_notes, receipt_documents_json,
+			currency_code, exchange_rate
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		RETURNING receipt_id, created_at`
 
 	// Set initial values
@@ -48,6 +49,8 @@ func (r *GoodsReceiptRepository) Create(ctx context.Context, receipt *products.G
 		receipt.ReceiptNotes,
 		receipt.DiscrepancyNotes,
 		receipt.ReceiptDocumentsJSON,
+		receipt.CurrencyCode,
+		receipt.ExchangeRate,
 	).Scan(&receipt.ReceiptID, &receipt.CreatedAt)
 
 	if err != nil {
@@ -63,7 +66,7 @@ func (r *GoodsReceiptRepository) GetByID(ctx context.Context, id int) (*products
 		SELECT receipt_id, po_id, receipt_number, receipt_date, received_by,
 			   supplier_delivery_note, supplier_invoice_number, total_received_value,
 			   receipt_status, receipt_notes, discrepancy_notes, receipt_documents_json,
-			   created_at
+			   currency_code, exchange_rate, created_at
 		FROM goods_receipts 
 		WHERE receipt_id = $1`
 
@@ -81,6 +84,8 @@ func (r *GoodsReceiptRepository) GetByID(ctx context.Context, id int) (*products
 		&receipt.ReceiptNotes,
 		&receipt.DiscrepancyNotes,
 		&receipt.ReceiptDocumentsJSON,
+		&receipt.CurrencyCode,
+		&receipt.ExchangeRate,
 		&receipt.CreatedAt,
 	)
 
@@ -100,7 +105,7 @@ func (r *GoodsReceiptRepository) GetByNumber(ctx context.Context, number string)
 		SELECT receipt_id, po_id, receipt_number, receipt_date, received_by,
 			   supplier_delivery_note, supplier_invoice_number, total_received_value,
 			   receipt_status, receipt_notes, discrepancy_notes, receipt_documents_json,
-			   created_at
+			   currency_code, exchange_rate, created_at
 		FROM goods_receipts 
 		WHERE receipt_number = $1`
 
@@ -118,6 +123,8 @@ func (r *GoodsReceiptRepository) GetByNumber(ctx context.Context, number string)
 		&receipt.ReceiptNotes,
 		&receipt.DiscrepancyNotes,
 		&receipt.ReceiptDocumentsJSON,
+		&receipt.CurrencyCode,
+		&receipt.ExchangeRate,
 		&receipt.CreatedAt,
 	)
 
@@ -136,8 +143,9 @@ func (r *GoodsReceiptRepository) Update(ctx context.Context, id int, receipt *pr
 	query := `
 		UPDATE goods_receipts 
 		SET receipt_date = $1, supplier_delivery_note = $2, supplier_invoice_number = $3,
-			receipt_notes = $4, discrepancy_notes = $5, receipt_documents_json = $6
-		WHERE receipt_id = $7`
+			receipt_notes = $4, discrepancy_notes = $5, receipt_documents_json = $6,
+			exchange_rate = $7, total_received_value = $8
+		WHERE receipt_id = $9`
 
 	_, err := r.db.ExecContext(ctx, query,
 		receipt.ReceiptDate,
@@ -146,6 +154,8 @@ func (r *GoodsReceiptRepository) Update(ctx context.Context, id int, receipt *pr
 		receipt.ReceiptNotes,
 		receipt.DiscrepancyNotes,
 		receipt.ReceiptDocumentsJSON,
+		receipt.ExchangeRate,
+		receipt.TotalReceivedValue,
 		id,
 	)
 
@@ -424,6 +434,50 @@ func (r *GoodsReceiptRepository) IsNumberExists(ctx context.Context, number stri
 	return exists, nil
 }
 
+// Process books a goods receipt in one transaction: the receipt leaves partial status with its received
+// value, the accepted goods are added to stock and the receipt journal is posted. A receipt that was
+// already processed is rejected, so retries cannot book the same goods twice.
+func (r *GoodsReceiptRepository) Process(ctx context.Context, receipt *products.GoodsReceipt, movements []products.StockMovement, entry *products.JournalEntry) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE goods_receipts SET receipt_status = $1, total_received_value = $2
+		WHERE receipt_id = $3 AND receipt_status = $4`,
+		receipt.ReceiptStatus, receipt.TotalReceivedValue, receipt.ReceiptID, products.ReceiptStatusPartial)
+	if err != nil {
+		return fmt.Errorf("failed to update receipt status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("goods receipt not found or already processed")
+	}
+
+	for i := range movements {
+		if err := applyStockMovement(ctx, tx, &movements[i], true); err != nil {
+			return fmt.Errorf("failed to create stock movement for product %d: %w", movements[i].ProductID, err)
+		}
+	}
+
+	if err := postJournalEntry(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
 // UpdateStatus updates the receipt status
 func (r *GoodsReceiptRepository) UpdateStatus(ctx context.Context, id int, status products.ReceiptStatus) error {
 	query := `UPDATE goods_receipts SET receipt_status = $1 WHERE receipt_id = $2`
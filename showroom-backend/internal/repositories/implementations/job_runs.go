@@ -0,0 +1,269 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/jobs"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+const jobRunSelectFields = `
+	jr.run_id, jr.job_name, jr.run_trigger, jr.triggered_by, jr.run_status, jr.scheduled_at, jr.started_at,
+	jr.finished_at, jr.duration_ms, jr.result, jr.error_message`
+
+// JobRunRepository implements interfaces.JobRunRepository
+type JobRunRepository struct {
+	db *sql.DB
+}
+
+// NewJobRunRepository creates a new job run repository
+func NewJobRunRepository(db *sql.DB) interfaces.JobRunRepository {
+	return &JobRunRepository{db: db}
+}
+
+// Create records the start of a job run
+func (r *JobRunRepository) Create(ctx context.Context, run *jobs.JobRun) (*jobs.JobRun, error) {
+	query := `
+		INSERT INTO scheduled_job_runs (job_name, run_trigger, triggered_by, run_status, started_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING run_id`
+
+	err := r.db.QueryRowContext(ctx, query,
+		run.JobName,
+		run.Trigger,
+		run.TriggeredBy,
+		run.Status,
+		run.StartedAt,
+	).Scan(&run.RunID)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job run: %w", err)
+	}
+
+	return run, nil
+}
+
+// ClaimScheduled records the start of a run for the schedule tick in run.ScheduledAt.
+// It returns false without recording anything when a run for that tick already exists.
+func (r *JobRunRepository) ClaimScheduled(ctx context.Context, run *jobs.JobRun) (bool, error) {
+	query := `
+		INSERT INTO scheduled_job_runs (job_name, run_trigger, triggered_by, run_status, scheduled_at, started_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (job_name, scheduled_at) DO NOTHING
+		RETURNING run_id`
+
+	err := r.db.QueryRowContext(ctx, query,
+		run.JobName,
+		run.Trigger,
+		run.TriggeredBy,
+		run.Status,
+		run.ScheduledAt,
+		run.StartedAt,
+	).Scan(&run.RunID)
+
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim scheduled job run: %w", err)
+	}
+
+	return true, nil
+}
+
+// Finish records the outcome of a job run
+func (r *JobRunRepository) Finish(ctx context.Context, run *jobs.JobRun) error {
+	query := `
+		UPDATE scheduled_job_runs SET
+			run_status = $1, finished_at = $2, duration_ms = $3, result = $4, error_message = $5
+		WHERE run_id = $6`
+
+	result, err := r.db.ExecContext(ctx, query,
+		run.Status,
+		run.FinishedAt,
+		run.DurationMs,
+		run.Result,
+		run.ErrorMessage,
+		run.RunID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to finish job run: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("job run not found")
+	}
+
+	return nil
+}
+
+// FailInterrupted marks runs of a job still recorded as running as failed.
+// Only call it while holding the job lock, when no other instance can be running the job.
+func (r *JobRunRepository) FailInterrupted(ctx context.Context, jobName string) (int64, error) {
+	query := `
+		UPDATE scheduled_job_runs SET
+			run_status = 'failed', finished_at = NOW(),
+			error_message = 'interrupted before completion'
+		WHERE job_name = $1 AND run_status = 'running'`
+
+	result, err := r.db.ExecContext(ctx, query, jobName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail interrupted job runs: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// List retrieves job run history with pagination, most recent first
+func (r *JobRunRepository) List(ctx context.Context, params *jobs.JobRunFilterParams) (*common.PaginatedResponse, error) {
+	baseQuery := `FROM scheduled_job_runs jr WHERE 1=1`
+
+	args := []interface{}{}
+	whereConditions := []string{}
+	argIndex := 1
+
+	// Add filters
+	if params.JobName != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("jr.job_name = $%d", argIndex))
+		args = append(args, params.JobName)
+		argIndex++
+	}
+
+	if params.Status != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("jr.run_status = $%d", argIndex))
+		args = append(args, *params.Status)
+		argIndex++
+	}
+
+	if params.Trigger != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("jr.run_trigger = $%d", argIndex))
+		args = append(args, *params.Trigger)
+		argIndex++
+	}
+
+	if params.DateFrom != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("jr.started_at >= $%d", argIndex))
+		args = append(args, *params.DateFrom)
+		argIndex++
+	}
+
+	if params.DateTo != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("jr.started_at < $%d::date + INTERVAL '1 day'", argIndex))
+		args = append(args, *params.DateTo)
+		argIndex++
+	}
+
+	if len(whereConditions) > 0 {
+		baseQuery += " AND " + strings.Join(whereConditions, " AND ")
+	}
+
+	// Count query
+	countQuery := "SELECT COUNT(*) " + baseQuery
+	var total int64
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count job runs: %w", err)
+	}
+
+	// Calculate pagination
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	// Main query
+	mainQuery := "SELECT " + jobRunSelectFields + " " + baseQuery +
+		" ORDER BY jr.started_at DESC, jr.run_id DESC LIMIT $" + fmt.Sprintf("%d", argIndex) +
+		" OFFSET $" + fmt.Sprintf("%d", argIndex+1)
+
+	args = append(args, params.Limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, mainQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query job runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []jobs.JobRun
+	for rows.Next() {
+		run, err := scanJobRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job run: %w", err)
+		}
+		runs = append(runs, *run)
+	}
+
+	totalPages := (total + int64(params.Limit) - 1) / int64(params.Limit)
+
+	return &common.PaginatedResponse{
+		Data:       runs,
+		Total:      int(total),
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: int(totalPages),
+		HasMore:    params.Page < int(totalPages),
+	}, nil
+}
+
+// GetLatestRuns retrieves the most recent run of each job keyed by job name
+func (r *JobRunRepository) GetLatestRuns(ctx context.Context) (map[string]jobs.JobRun, error) {
+	query := "SELECT DISTINCT ON (jr.job_name) " + jobRunSelectFields + `
+		FROM scheduled_job_runs jr
+		ORDER BY jr.job_name, jr.started_at DESC, jr.run_id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest job runs: %w", err)
+	}
+	defer rows.Close()
+
+	latest := make(map[string]jobs.JobRun)
+	for rows.Next() {
+		run, err := scanJobRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job run: %w", err)
+		}
+		latest[run.JobName] = *run
+	}
+
+	return latest, nil
+}
+
+func scanJobRun(row rowScanner) (*jobs.JobRun, error) {
+	run := &jobs.JobRun{}
+	err := row.Scan(
+		&run.RunID,
+		&run.JobName,
+		&run.Trigger,
+		&run.TriggeredBy,
+		&run.Status,
+		&run.ScheduledAt,
+		&run.StartedAt,
+		&run.FinishedAt,
+		&run.DurationMs,
+		&run.Result,
+		&run.ErrorMessage,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
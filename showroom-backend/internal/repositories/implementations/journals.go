@@ -0,0 +1,554 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// journalTotalsJoin derives the debit and credit totals of a journal entry aliased je
+const journalTotalsJoin = `
+	LEFT JOIN LATERAL (
+		SELECT COALESCE(SUM(jl.debit), 0) AS total_debit, COALESCE(SUM(jl.credit), 0) AS total_credit
+		FROM journal_lines jl WHERE jl.journal_id = je.journal_id
+	) totals ON TRUE`
+
+const journalSelectFields = `
+	je.journal_id, je.journal_number, je.entry_date, je.source_type, je.source_id, je.source_number,
+	je.description, je.journal_status, je.reversal_of_id, totals.total_debit, totals.total_credit,
+	je.posted_by, je.created_at`
+
+// journalQuerier is satisfied by both *sql.DB and *sql.Tx
+type journalQuerier interface {
+	sqlRowQuerier
+	sqlQuerier
+}
+
+// JournalRepository implements interfaces.JournalRepository
+type JournalRepository struct {
+	db *sql.DB
+}
+
+// NewJournalRepository creates a new journal repository
+func NewJournalRepository(db *sql.DB) interfaces.JournalRepository {
+	return &JournalRepository{db: db}
+}
+
+// Post posts a balanced journal entry into an open period
+func (r *JournalRepository) Post(ctx context.Context, entry *products.JournalEntry) (*products.JournalEntry, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := postJournalEntry(ctx, tx, entry); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByID(ctx, entry.JournalID)
+}
+
+// Reverse posts the entry cancelling a posted journal entry and marks the original as reversed
+func (r *JournalRepository) Reverse(ctx context.Context, id int, entryDate time.Time, postedBy int) (*products.JournalEntry, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	reversal, err := reverseJournalEntry(ctx, tx, id, entryDate, &postedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByID(ctx, reversal.JournalID)
+}
+
+// GetByID retrieves a journal entry by ID with its lines
+func (r *JournalRepository) GetByID(ctx context.Context, id int) (*products.JournalEntry, error) {
+	return getJournalEntry(ctx, r.db, id)
+}
+
+// List retrieves journal entries with pagination
+func (r *JournalRepository) List(ctx context.Context, params *products.JournalEntryFilterParams) (*common.PaginatedResponse, error) {
+	baseQuery := `FROM journal_entries je` + journalTotalsJoin + ` WHERE 1=1`
+
+	args := []interface{}{}
+	whereConditions := []string{}
+	argIndex := 1
+
+	// Add filters
+	if params.SourceType != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("je.source_type = $%d", argIndex))
+		args = append(args, *params.SourceType)
+		argIndex++
+	}
+
+	if params.SourceID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("je.source_id = $%d", argIndex))
+		args = append(args, *params.SourceID)
+		argIndex++
+	}
+
+	if params.AccountID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM journal_lines jl WHERE jl.journal_id = je.journal_id AND jl.account_id = $%d)", argIndex))
+		args = append(args, *params.AccountID)
+		argIndex++
+	}
+
+	if params.JournalStatus != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("je.journal_status = $%d", argIndex))
+		args = append(args, *params.JournalStatus)
+		argIndex++
+	}
+
+	if params.DateFrom != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("je.entry_date >= $%d", argIndex))
+		args = append(args, *params.DateFrom)
+		argIndex++
+	}
+
+	if params.DateTo != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("je.entry_date <= $%d", argIndex))
+		args = append(args, *params.DateTo)
+		argIndex++
+	}
+
+	if len(whereConditions) > 0 {
+		baseQuery += " AND " + strings.Join(whereConditions, " AND ")
+	}
+
+	// Count query
+	countQuery := "SELECT COUNT(*) " + baseQuery
+	var total int64
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count journal entries: %w", err)
+	}
+
+	// Calculate pagination
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	// Main query
+	mainQuery := "SELECT " + journalSelectFields + " " + baseQuery +
+		" ORDER BY je.entry_date DESC, je.journal_id DESC LIMIT $" + fmt.Sprintf("%d", argIndex) +
+		" OFFSET $" + fmt.Sprintf("%d", argIndex+1)
+
+	args = append(args, params.Limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, mainQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query journal entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []products.JournalEntry
+	for rows.Next() {
+		entry, err := scanJournalEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan journal entry: %w", err)
+		}
+		entries = append(entries, *entry)
+	}
+
+	totalPages := (total + int64(params.Limit) - 1) / int64(params.Limit)
+
+	return &common.PaginatedResponse{
+		Data:       entries,
+		Total:      int(total),
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: int(totalPages),
+		HasMore:    params.Page < int(totalPages),
+	}, nil
+}
+
+// GetTrialBalance totals the postings of every account with entries in the date range
+func (r *JournalRepository) GetTrialBalance(ctx context.Context, params *products.TrialBalanceParams) (*products.TrialBalance, error) {
+	whereConditions := []string{"1=1"}
+	args := []interface{}{}
+	argIndex := 1
+
+	if params.DateFrom != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("je.entry_date >= $%d", argIndex))
+		args = append(args, *params.DateFrom)
+		argIndex++
+	}
+
+	if params.DateTo != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("je.entry_date <= $%d", argIndex))
+		args = append(args, *params.DateTo)
+	}
+
+	query := `
+		SELECT a.account_id, a.account_code, a.account_name, a.account_type,
+			   COALESCE(SUM(jl.debit), 0), COALESCE(SUM(jl.credit), 0)
+		FROM journal_lines jl
+		JOIN journal_entries je ON je.journal_id = jl.journal_id
+		JOIN gl_accounts a ON a.account_id = jl.account_id
+		WHERE ` + strings.Join(whereConditions, " AND ") + `
+		GROUP BY a.account_id, a.account_code, a.account_name, a.account_type`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trial balance: %w", err)
+	}
+	defer rows.Close()
+
+	var balances []products.TrialBalanceRow
+	for rows.Next() {
+		var row products.TrialBalanceRow
+		err := rows.Scan(&row.AccountID, &row.AccountCode, &row.AccountName, &row.AccountType, &row.Debit, &row.Credit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trial balance row: %w", err)
+		}
+		balances = append(balances, row)
+	}
+
+	return products.NewTrialBalance(params, balances), nil
+}
+
+// CheckPeriodOpen returns an error when the accounting period containing date is closed
+func (r *JournalRepository) CheckPeriodOpen(ctx context.Context, date time.Time) error {
+	return ensurePeriodOpen(ctx, r.db, date)
+}
+
+// ListPeriods retrieves the twelve accounting periods of a year. Periods never closed are open.
+func (r *JournalRepository) ListPeriods(ctx context.Context, year int) ([]products.AccountingPeriod, error) {
+	query := `
+		SELECT gs::date, (gs + INTERVAL '1 month' - INTERVAL '1 day')::date,
+			   COALESCE(ap.period_status, 'open'), ap.closed_by, ap.closed_at
+		FROM generate_series(make_date($1, 1, 1), make_date($1, 12, 1), INTERVAL '1 month') gs
+		LEFT JOIN accounting_periods ap ON ap.period_start = gs::date
+		ORDER BY gs`
+
+	rows, err := r.db.QueryContext(ctx, query, year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query accounting periods: %w", err)
+	}
+	defer rows.Close()
+
+	var periods []products.AccountingPeriod
+	for rows.Next() {
+		var period products.AccountingPeriod
+		err := rows.Scan(&period.PeriodStart, &period.PeriodEnd, &period.PeriodStatus, &period.ClosedBy, &period.ClosedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan accounting period: %w", err)
+		}
+		periods = append(periods, period)
+	}
+
+	return periods, nil
+}
+
+// SetPeriodStatus closes or reopens the accounting period starting at periodStart
+func (r *JournalRepository) SetPeriodStatus(ctx context.Context, periodStart time.Time, status products.AccountingPeriodStatus, changedBy int) error {
+	var closedBy *int
+	var closedAt *time.Time
+	if status == products.AccountingPeriodStatusClosed {
+		now := time.Now()
+		closedBy, closedAt = &changedBy, &now
+	}
+
+	query := `
+		INSERT INTO accounting_periods (period_start, period_status, closed_by, closed_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (period_start) DO UPDATE SET
+			period_status = EXCLUDED.period_status, closed_by = EXCLUDED.closed_by, closed_at = EXCLUDED.closed_at`
+
+	_, err := r.db.ExecContext(ctx, query, periodStart, status, closedBy, closedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update accounting period: %w", err)
+	}
+
+	return nil
+}
+
+// postJournalEntry resolves the posting rules of an entry to accounts, checks that it balances and
+// that its period is open, and stores it. A nil entry, from a document without financial effect, is skipped.
+func postJournalEntry(ctx context.Context, tx *sql.Tx, entry *products.JournalEntry) error {
+	if entry == nil {
+		return nil
+	}
+
+	if err := ensurePeriodOpen(ctx, tx, entry.EntryDate); err != nil {
+		return err
+	}
+
+	for i := range entry.Lines {
+		line := &entry.Lines[i]
+		if line.AccountID != 0 || line.Rule == nil {
+			continue
+		}
+		err := tx.QueryRowContext(ctx, "SELECT account_id FROM gl_posting_rules WHERE rule_key = $1", *line.Rule).Scan(&line.AccountID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("no account is configured for posting rule %s", *line.Rule)
+			}
+			return fmt.Errorf("failed to resolve posting rule: %w", err)
+		}
+	}
+
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+
+	checked := make(map[int]bool, len(entry.Lines))
+	for _, line := range entry.Lines {
+		if checked[line.AccountID] {
+			continue
+		}
+		checked[line.AccountID] = true
+
+		var code string
+		var active bool
+		err := tx.QueryRowContext(ctx, "SELECT account_code, is_active FROM gl_accounts WHERE account_id = $1", line.AccountID).Scan(&code, &active)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("account %d not found", line.AccountID)
+			}
+			return fmt.Errorf("failed to get account: %w", err)
+		}
+		if !active {
+			return fmt.Errorf("account %s is inactive", code)
+		}
+	}
+
+	journalNumber, err := generateJournalNumber(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	entry.JournalNumber = journalNumber
+	entry.JournalStatus = products.JournalStatusPosted
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO journal_entries (
+			journal_number, entry_date, source_type, source_id, source_number,
+			description, journal_status, reversal_of_id, posted_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING journal_id, created_at`,
+		entry.JournalNumber,
+		entry.EntryDate,
+		entry.SourceType,
+		entry.SourceID,
+		entry.SourceNumber,
+		entry.Description,
+		entry.JournalStatus,
+		entry.ReversalOfID,
+		entry.PostedBy,
+	).Scan(&entry.JournalID, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create journal entry: %w", err)
+	}
+
+	for i := range entry.Lines {
+		line := &entry.Lines[i]
+		line.JournalID = entry.JournalID
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO journal_lines (journal_id, account_id, debit, credit, memo)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING line_id`,
+			line.JournalID, line.AccountID, line.Debit, line.Credit, line.Memo,
+		).Scan(&line.LineID)
+		if err != nil {
+			return fmt.Errorf("failed to create journal line: %w", err)
+		}
+	}
+
+	entry.TotalDebit, entry.TotalCredit = entry.Totals()
+	return nil
+}
+
+// reverseJournalEntry posts the reversal of a posted entry and marks the original as reversed
+func reverseJournalEntry(ctx context.Context, tx *sql.Tx, id int, entryDate time.Time, postedBy *int) (*products.JournalEntry, error) {
+	var journalID int
+	err := tx.QueryRowContext(ctx, "SELECT journal_id FROM journal_entries WHERE journal_id = $1 FOR UPDATE", id).Scan(&journalID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("journal entry not found")
+		}
+		return nil, fmt.Errorf("failed to lock journal entry: %w", err)
+	}
+
+	entry, err := getJournalEntry(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !entry.CanReverse() {
+		return nil, fmt.Errorf("journal entry %s cannot be reversed", entry.JournalNumber)
+	}
+
+	reversal := entry.Reversal(entryDate, postedBy)
+	if err := postJournalEntry(ctx, tx, reversal); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE journal_entries SET journal_status = 'reversed' WHERE journal_id = $1", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark journal entry as reversed: %w", err)
+	}
+
+	return reversal, nil
+}
+
+// reverseSourceJournals reverses every posted entry of a document, e.g. when it is voided or changed
+func reverseSourceJournals(ctx context.Context, tx *sql.Tx, sourceType products.JournalSourceType, sourceID int, entryDate time.Time, postedBy *int) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT journal_id FROM journal_entries
+		WHERE source_type = $1 AND source_id = $2 AND journal_status = 'posted' AND reversal_of_id IS NULL
+		ORDER BY journal_id`, sourceType, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to query journal entries: %w", err)
+	}
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan journal entry: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	for _, id := range ids {
+		if _, err := reverseJournalEntry(ctx, tx, id, entryDate, postedBy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensurePeriodOpen returns an error when the accounting period containing date is closed
+func ensurePeriodOpen(ctx context.Context, db sqlRowQuerier, date time.Time) error {
+	var closed bool
+	err := db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM accounting_periods WHERE period_start = $1 AND period_status = 'closed')`,
+		products.PeriodStart(date)).Scan(&closed)
+	if err != nil {
+		return fmt.Errorf("failed to check accounting period: %w", err)
+	}
+
+	if closed {
+		return fmt.Errorf("accounting period %s is closed", date.Format("2006-01"))
+	}
+
+	return nil
+}
+
+// getJournalEntry loads a journal entry with its lines
+func getJournalEntry(ctx context.Context, db journalQuerier, id int) (*products.JournalEntry, error) {
+	query := "SELECT " + journalSelectFields + `
+		FROM journal_entries je` + journalTotalsJoin + `
+		WHERE je.journal_id = $1`
+
+	entry, err := scanJournalEntry(db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("journal entry not found")
+		}
+		return nil, fmt.Errorf("failed to get journal entry: %w", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT jl.line_id, jl.journal_id, jl.account_id, a.account_code, a.account_name, jl.debit, jl.credit, jl.memo
+		FROM journal_lines jl
+		JOIN gl_accounts a ON a.account_id = jl.account_id
+		WHERE jl.journal_id = $1
+		ORDER BY jl.line_id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query journal lines: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line products.JournalLine
+		err := rows.Scan(
+			&line.LineID,
+			&line.JournalID,
+			&line.AccountID,
+			&line.AccountCode,
+			&line.AccountName,
+			&line.Debit,
+			&line.Credit,
+			&line.Memo,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan journal line: %w", err)
+		}
+		entry.Lines = append(entry.Lines, line)
+	}
+
+	return entry, nil
+}
+
+func generateJournalNumber(ctx context.Context, db sqlRowQuerier) (string, error) {
+	// Generate journal number with format JV-YYYYMMDD-XXXX
+	now := time.Now()
+	dateStr := now.Format("20060102")
+
+	query := `
+		SELECT COALESCE(MAX(
+			CAST(SUBSTRING(journal_number FROM 'JV-\d{8}-(\d+)') AS INTEGER)
+		), 0) + 1
+		FROM journal_entries
+		WHERE journal_number LIKE $1`
+
+	prefix := "JV-" + dateStr + "-%"
+	var nextNumber int
+	err := db.QueryRowContext(ctx, query, prefix).Scan(&nextNumber)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate journal number: %w", err)
+	}
+
+	return fmt.Sprintf("JV-%s-%04d", dateStr, nextNumber), nil
+}
+
+func scanJournalEntry(row rowScanner) (*products.JournalEntry, error) {
+	entry := &products.JournalEntry{}
+	err := row.Scan(
+		&entry.JournalID,
+		&entry.JournalNumber,
+		&entry.EntryDate,
+		&entry.SourceType,
+		&entry.SourceID,
+		&entry.SourceNumber,
+		&entry.Description,
+		&entry.JournalStatus,
+		&entry.ReversalOfID,
+		&entry.TotalDebit,
+		&entry.TotalCredit,
+		&entry.PostedBy,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
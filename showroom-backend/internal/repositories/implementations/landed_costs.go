@@ -0,0 +1,436 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// LandedCostRepository implements interfaces.LandedCostRepository
+type LandedCostRepository struct {
+	db *sql.DB
+}
+
+// NewLandedCostRepository creates a new landed cost repository
+func NewLandedCostRepository(db *sql.DB) interfaces.LandedCostRepository {
+	return &LandedCostRepository{db: db}
+}
+
+// Create creates a new landed cost voucher and links it to its goods receipts
+func (r *LandedCostRepository) Create(ctx context.Context, voucher *products.LandedCostVoucher) (*products.LandedCostVoucher, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	voucher.CalculateTotalAmount()
+
+	if voucher.VoucherDate.IsZero() {
+		voucher.VoucherDate = time.Now()
+	}
+
+	query := `
+		INSERT INTO landed_cost_vouchers (
+			voucher_number, supplier_id, reference_number, voucher_date,
+			allocation_method, freight_amount, duty_amount, insurance_amount,
+			handling_amount, total_amount, voucher_status, notes, created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING voucher_id, created_at, updated_at`
+
+	err = tx.QueryRowContext(ctx, query,
+		voucher.VoucherNumber,
+		voucher.SupplierID,
+		voucher.ReferenceNumber,
+		voucher.VoucherDate,
+		voucher.AllocationMethod,
+		voucher.FreightAmount,
+		voucher.DutyAmount,
+		voucher.InsuranceAmount,
+		voucher.HandlingAmount,
+		voucher.TotalAmount,
+		voucher.VoucherStatus,
+		voucher.Notes,
+		voucher.CreatedBy,
+	).Scan(&voucher.VoucherID, &voucher.CreatedAt, &voucher.UpdatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create landed cost voucher: %w", err)
+	}
+
+	receiptQuery := `INSERT INTO landed_cost_voucher_receipts (voucher_id, receipt_id) VALUES ($1, $2)`
+	for _, receiptID := range voucher.ReceiptIDs {
+		if _, err := tx.ExecContext(ctx, receiptQuery, voucher.VoucherID, receiptID); err != nil {
+			return nil, fmt.Errorf("failed to link goods receipt %d: %w", receiptID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return voucher, nil
+}
+
+// GetByID retrieves a landed cost voucher by ID together with its receipt IDs
+func (r *LandedCostRepository) GetByID(ctx context.Context, id int) (*products.LandedCostVoucher, error) {
+	query := `
+		SELECT voucher_id, voucher_number, supplier_id, reference_number, voucher_date,
+			   allocation_method, freight_amount, duty_amount, insurance_amount,
+			   handling_amount, total_amount, voucher_status, notes, created_by,
+			   posted_by, posted_at, created_at, updated_at
+		FROM landed_cost_vouchers
+		WHERE voucher_id = $1`
+
+	voucher := &products.LandedCostVoucher{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&voucher.VoucherID,
+		&voucher.VoucherNumber,
+		&voucher.SupplierID,
+		&voucher.ReferenceNumber,
+		&voucher.VoucherDate,
+		&voucher.AllocationMethod,
+		&voucher.FreightAmount,
+		&voucher.DutyAmount,
+		&voucher.InsuranceAmount,
+		&voucher.HandlingAmount,
+		&voucher.TotalAmount,
+		&voucher.VoucherStatus,
+		&voucher.Notes,
+		&voucher.CreatedBy,
+		&voucher.PostedBy,
+		&voucher.PostedAt,
+		&voucher.CreatedAt,
+		&voucher.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("landed cost voucher not found")
+		}
+		return nil, fmt.Errorf("failed to get landed cost voucher: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT receipt_id FROM landed_cost_voucher_receipts WHERE voucher_id = $1 ORDER BY receipt_id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query voucher receipts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var receiptID int
+		if err := rows.Scan(&receiptID); err != nil {
+			return nil, fmt.Errorf("failed to scan voucher receipt: %w", err)
+		}
+		voucher.ReceiptIDs = append(voucher.ReceiptIDs, receiptID)
+	}
+
+	return voucher, nil
+}
+
+// List retrieves landed cost vouchers with pagination
+func (r *LandedCostRepository) List(ctx context.Context, params *products.LandedCostVoucherFilterParams) (*common.PaginatedResponse, error) {
+	baseQuery := `
+		FROM landed_cost_vouchers v
+		WHERE 1=1`
+
+	args := []interface{}{}
+	whereConditions := []string{}
+	argIndex := 1
+
+	if params.SupplierID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("v.supplier_id = $%d", argIndex))
+		args = append(args, *params.SupplierID)
+		argIndex++
+	}
+
+	if params.ReceiptID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("EXISTS (SELECT 1 FROM landed_cost_voucher_receipts vr WHERE vr.voucher_id = v.voucher_id AND vr.receipt_id = $%d)", argIndex))
+		args = append(args, *params.ReceiptID)
+		argIndex++
+	}
+
+	if params.AllocationMethod != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("v.allocation_method = $%d", argIndex))
+		args = append(args, *params.AllocationMethod)
+		argIndex++
+	}
+
+	if params.VoucherStatus != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("v.voucher_status = $%d", argIndex))
+		args = append(args, *params.VoucherStatus)
+		argIndex++
+	}
+
+	if params.DateFrom != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("v.voucher_date >= $%d", argIndex))
+		args = append(args, *params.DateFrom)
+		argIndex++
+	}
+
+	if params.DateTo != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("v.voucher_date <= $%d", argIndex))
+		args = append(args, *params.DateTo)
+		argIndex++
+	}
+
+	if params.Search != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("(v.voucher_number ILIKE $%d OR v.reference_number ILIKE $%d)", argIndex, argIndex))
+		args = append(args, "%"+params.Search+"%")
+		argIndex++
+	}
+
+	if len(whereConditions) > 0 {
+		baseQuery += " AND " + strings.Join(whereConditions, " AND ")
+	}
+
+	// Count query
+	countQuery := "SELECT COUNT(*) " + baseQuery
+	var total int64
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count landed cost vouchers: %w", err)
+	}
+
+	// Calculate pagination
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	// Main query
+	selectFields := `
+		v.voucher_id, v.voucher_number, v.supplier_id, v.voucher_date,
+		v.allocation_method, v.total_amount, v.voucher_status,
+		(SELECT COUNT(*) FROM landed_cost_voucher_receipts vr WHERE vr.voucher_id = v.voucher_id),
+		v.created_at`
+
+	mainQuery := "SELECT " + selectFields + " " + baseQuery +
+		" ORDER BY v.voucher_date DESC, v.voucher_id DESC LIMIT $" + fmt.Sprintf("%d", argIndex) +
+		" OFFSET $" + fmt.Sprintf("%d", argIndex+1)
+
+	args = append(args, params.Limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, mainQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query landed cost vouchers: %w", err)
+	}
+	defer rows.Close()
+
+	var vouchers []products.LandedCostVoucherListItem
+	for rows.Next() {
+		var item products.LandedCostVoucherListItem
+		err := rows.Scan(
+			&item.VoucherID,
+			&item.VoucherNumber,
+			&item.SupplierID,
+			&item.VoucherDate,
+			&item.AllocationMethod,
+			&item.TotalAmount,
+			&item.VoucherStatus,
+			&item.ReceiptCount,
+			&item.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan landed cost voucher: %w", err)
+		}
+		vouchers = append(vouchers, item)
+	}
+
+	totalPages := (total + int64(params.Limit) - 1) / int64(params.Limit)
+
+	return &common.PaginatedResponse{
+		Data:       vouchers,
+		Total:      int(total),
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: int(totalPages),
+		HasMore:    params.Page < int(totalPages),
+	}, nil
+}
+
+// GetAllocations retrieves the posted allocation lines of a voucher
+func (r *LandedCostRepository) GetAllocations(ctx context.Context, voucherID int) ([]products.LandedCostAllocation, error) {
+	query := `
+		SELECT allocation_id, voucher_id, receipt_id, receipt_detail_id, product_id,
+			   quantity, unit_cost, unit_weight, basis_amount, allocated_amount,
+			   unit_landed_cost
+		FROM landed_cost_allocations
+		WHERE voucher_id = $1
+		ORDER BY allocation_id`
+
+	rows, err := r.db.QueryContext(ctx, query, voucherID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query landed cost allocations: %w", err)
+	}
+	defer rows.Close()
+
+	var allocations []products.LandedCostAllocation
+	for rows.Next() {
+		var allocation products.LandedCostAllocation
+		err := rows.Scan(
+			&allocation.AllocationID,
+			&allocation.VoucherID,
+			&allocation.ReceiptID,
+			&allocation.ReceiptDetailID,
+			&allocation.ProductID,
+			&allocation.Quantity,
+			&allocation.UnitCost,
+			&allocation.UnitWeight,
+			&allocation.BasisAmount,
+			&allocation.AllocatedAmount,
+			&allocation.UnitLandedCost,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan landed cost allocation: %w", err)
+		}
+		allocations = append(allocations, allocation)
+	}
+
+	return allocations, nil
+}
+
+// Post stores the allocation lines and marks a draft voucher as posted
+func (r *LandedCostRepository) Post(ctx context.Context, id int, allocations []products.LandedCostAllocation, postedBy int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	voucher := &products.LandedCostVoucher{VoucherID: id}
+	err = tx.QueryRowContext(ctx, `
+		UPDATE landed_cost_vouchers
+		SET voucher_status = 'posted', posted_by = $1, posted_at = NOW(), updated_at = NOW()
+		WHERE voucher_id = $2 AND voucher_status = 'draft'
+		RETURNING voucher_number, voucher_date, total_amount`, postedBy, id).Scan(
+		&voucher.VoucherNumber,
+		&voucher.VoucherDate,
+		&voucher.TotalAmount,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("landed cost voucher not found or not in draft status")
+		}
+		return fmt.Errorf("failed to post landed cost voucher: %w", err)
+	}
+
+	allocationQuery := `
+		INSERT INTO landed_cost_allocations (
+			voucher_id, receipt_id, receipt_detail_id, product_id, quantity,
+			unit_cost, unit_weight, basis_amount, allocated_amount, unit_landed_cost
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING allocation_id`
+
+	for i := range allocations {
+		allocations[i].VoucherID = id
+		err = tx.QueryRowContext(ctx, allocationQuery,
+			allocations[i].VoucherID,
+			allocations[i].ReceiptID,
+			allocations[i].ReceiptDetailID,
+			allocations[i].ProductID,
+			allocations[i].Quantity,
+			allocations[i].UnitCost,
+			allocations[i].UnitWeight,
+			allocations[i].BasisAmount,
+			allocations[i].AllocatedAmount,
+			allocations[i].UnitLandedCost,
+		).Scan(&allocations[i].AllocationID)
+		if err != nil {
+			return fmt.Errorf("failed to create landed cost allocation: %w", err)
+		}
+	}
+
+	if err := postJournalEntry(ctx, tx, products.LandedCostJournal(voucher, postedBy)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateStatus updates the status of a landed cost voucher
+func (r *LandedCostRepository) UpdateStatus(ctx context.Context, id int, status products.LandedCostStatus) error {
+	query := `UPDATE landed_cost_vouchers SET voucher_status = $1, updated_at = NOW() WHERE voucher_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, status, id)
+	if err != nil {
+		return fmt.Errorf("failed to update landed cost voucher status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("landed cost voucher not found")
+	}
+
+	return nil
+}
+
+// GetUnitLandedCosts sums the posted landed cost per unit for each line of a goods receipt, keyed by receipt detail ID
+func (r *LandedCostRepository) GetUnitLandedCosts(ctx context.Context, receiptID int) (map[int]float64, error) {
+	query := `
+		SELECT a.receipt_detail_id, COALESCE(SUM(a.unit_landed_cost), 0)
+		FROM landed_cost_allocations a
+		JOIN landed_cost_vouchers v ON a.voucher_id = v.voucher_id
+		WHERE a.receipt_id = $1 AND v.voucher_status = 'posted'
+		GROUP BY a.receipt_detail_id`
+
+	rows, err := r.db.QueryContext(ctx, query, receiptID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unit landed costs: %w", err)
+	}
+	defer rows.Close()
+
+	costs := make(map[int]float64)
+	for rows.Next() {
+		var receiptDetailID int
+		var unitLandedCost float64
+		if err := rows.Scan(&receiptDetailID, &unitLandedCost); err != nil {
+			return nil, fmt.Errorf("failed to scan unit landed cost: %w", err)
+		}
+		costs[receiptDetailID] = unitLandedCost
+	}
+
+	return costs, nil
+}
+
+// GenerateNumber generates a new landed cost voucher number
+func (r *LandedCostRepository) GenerateNumber(ctx context.Context) (string, error) {
+	// Generate voucher number with format LCV-YYYYMMDD-XXXX
+	now := time.Now()
+	dateStr := now.Format("20060102")
+
+	query := `
+		SELECT COALESCE(MAX(
+			CAST(SUBSTRING(voucher_number FROM 'LCV-\d{8}-(\d+)') AS INTEGER)
+		), 0) + 1
+		FROM landed_cost_vouchers
+		WHERE voucher_number LIKE $1`
+
+	prefix := "LCV-" + dateStr + "-%"
+	var nextNumber int
+	err := r.db.QueryRowContext(ctx, query, prefix).Scan(&nextNumber)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate voucher number: %w", err)
+	}
+
+	return fmt.Sprintf("LCV-%s-%04d", dateStr, nextNumber), nil
+}
@@ -0,0 +1,208 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+type loginAttemptRepository struct {
+	db *sql.DB
+}
+
+// NewLoginAttemptRepository creates a new login attempt repository
+func NewLoginAttemptRepository(db *sql.DB) interfaces.LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+// Create adds an entry to the login attempts log
+func (r *loginAttemptRepository) Create(ctx context.Context, attempt *user.LoginAttempt) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO login_attempts (username, user_id, ip_address, user_agent, outcome)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING attempt_id, created_at`,
+		attempt.Username, attempt.UserID, attempt.IPAddress, attempt.UserAgent, attempt.Outcome,
+	).Scan(&attempt.AttemptID, &attempt.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record login attempt: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves login attempts with filtering and pagination, newest first
+func (r *loginAttemptRepository) List(ctx context.Context, params *user.LoginAttemptFilterParams) ([]user.LoginAttempt, int, error) {
+	whereConditions := []string{}
+	args := []interface{}{}
+	argIndex := 1
+
+	if params.Username != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("LOWER(username) = LOWER($%d)", argIndex))
+		args = append(args, params.Username)
+		argIndex++
+	}
+
+	if params.UserID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("user_id = $%d", argIndex))
+		args = append(args, *params.UserID)
+		argIndex++
+	}
+
+	if params.IPAddress != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("ip_address = $%d", argIndex))
+		args = append(args, params.IPAddress)
+		argIndex++
+	}
+
+	if params.Outcome != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("outcome = $%d", argIndex))
+		args = append(args, *params.Outcome)
+		argIndex++
+	}
+
+	if params.DateFrom != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("created_at >= $%d", argIndex))
+		args = append(args, *params.DateFrom)
+		argIndex++
+	}
+
+	if params.DateTo != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("created_at <= $%d", argIndex))
+		args = append(args, *params.DateTo)
+		argIndex++
+	}
+
+	whereClause := ""
+	if len(whereConditions) > 0 {
+		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM login_attempts %s", whereClause)
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count login attempts: %w", err)
+	}
+
+	params.Validate()
+	query := fmt.Sprintf(`
+		SELECT attempt_id, username, user_id, ip_address, user_agent, outcome, created_at
+		FROM login_attempts %s
+		ORDER BY created_at DESC, attempt_id DESC
+		LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
+	args = append(args, params.Limit, params.GetOffset())
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list login attempts: %w", err)
+	}
+	defer rows.Close()
+
+	attempts := []user.LoginAttempt{}
+	for rows.Next() {
+		var attempt user.LoginAttempt
+		if err := rows.Scan(
+			&attempt.AttemptID, &attempt.Username, &attempt.UserID, &attempt.IPAddress,
+			&attempt.UserAgent, &attempt.Outcome, &attempt.CreatedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan login attempt: %w", err)
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, total, rows.Err()
+}
+
+// CountIPFailures counts the failed logins from an IP address since a moment and returns the
+// time of the latest one
+func (r *loginAttemptRepository) CountIPFailures(ctx context.Context, ipAddress string, since time.Time) (int, *time.Time, error) {
+	var count int
+	var last *time.Time
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), MAX(created_at)
+		FROM login_attempts
+		WHERE ip_address = $1 AND created_at >= $2 AND outcome IN ('failed', 'two_factor_failed')`,
+		ipAddress, since,
+	).Scan(&count, &last)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to count login failures: %w", err)
+	}
+
+	return count, last, nil
+}
+
+// CountPasswordResetRequests counts the password resets requested for a username and from an IP
+// address since a moment
+func (r *loginAttemptRepository) CountPasswordResetRequests(ctx context.Context, username, ipAddress string, since time.Time) (*user.PasswordResetRequests, error) {
+	requests := &user.PasswordResetRequests{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FILTER (WHERE username = $1), MIN(created_at) FILTER (WHERE username = $1),
+			   COUNT(*) FILTER (WHERE ip_address = $2), MIN(created_at) FILTER (WHERE ip_address = $2)
+		FROM login_attempts
+		WHERE (username = $1 OR ip_address = $2) AND created_at >= $3 AND outcome = $4`,
+		username, ipAddress, since, user.LoginOutcomePasswordReset,
+	).Scan(&requests.Username, &requests.UsernameOldest, &requests.IPAddress, &requests.IPOldest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count password reset requests: %w", err)
+	}
+
+	return requests, nil
+}
+
+// GetLock retrieves the failed login state of a user, empty when they have no recent failures
+func (r *loginAttemptRepository) GetLock(ctx context.Context, userID int) (*user.LoginLock, error) {
+	lock := &user.LoginLock{UserID: userID}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT failed_count, last_failed_at, locked_until
+		FROM user_login_locks
+		WHERE user_id = $1`, userID,
+	).Scan(&lock.FailedCount, &lock.LastFailedAt, &lock.LockedUntil)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get login lock: %w", err)
+	}
+
+	return lock, nil
+}
+
+// RecordFailure counts a failed login of a user, locking the account for lockFor once the
+// consecutive failures reach lockAfter
+func (r *loginAttemptRepository) RecordFailure(ctx context.Context, userID int, lockAfter int, lockFor time.Duration) (*user.LoginLock, error) {
+	lock := &user.LoginLock{UserID: userID}
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO user_login_locks (user_id, failed_count, last_failed_at)
+		VALUES ($1, 1, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET failed_count = user_login_locks.failed_count + 1, last_failed_at = NOW()
+		RETURNING failed_count, last_failed_at`, userID,
+	).Scan(&lock.FailedCount, &lock.LastFailedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record login failure: %w", err)
+	}
+
+	if lockAfter > 0 && lock.FailedCount >= lockAfter {
+		err = r.db.QueryRowContext(ctx, `
+			UPDATE user_login_locks
+			SET locked_until = NOW() + make_interval(secs => $2)
+			WHERE user_id = $1
+			RETURNING locked_until`, userID, lockFor.Seconds(),
+		).Scan(&lock.LockedUntil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock account: %w", err)
+		}
+	}
+
+	return lock, nil
+}
+
+// ResetLock clears the failed logins and any lock of a user
+func (r *loginAttemptRepository) ResetLock(ctx context.Context, userID int) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM user_login_locks WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("failed to reset login lock: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+type loginChallengeRepository struct {
+	db *sql.DB
+}
+
+// NewLoginChallengeRepository creates a new login challenge repository
+func NewLoginChallengeRepository(db *sql.DB) interfaces.LoginChallengeRepository {
+	return &loginChallengeRepository{db: db}
+}
+
+// Create stores a new login challenge
+func (r *loginChallengeRepository) Create(ctx context.Context, challenge *user.LoginChallenge) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO user_login_challenges (user_id, token_hash, ip_address, user_agent, enrolling, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING challenge_id, created_at`,
+		challenge.UserID, challenge.TokenHash, challenge.IPAddress, challenge.UserAgent,
+		challenge.Enrolling, challenge.ExpiresAt,
+	).Scan(&challenge.ChallengeID, &challenge.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create login challenge: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTokenHash retrieves a login challenge by the hash of its token
+func (r *loginChallengeRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*user.LoginChallenge, error) {
+	var challenge user.LoginChallenge
+	err := r.db.QueryRowContext(ctx, `
+		SELECT challenge_id, user_id, token_hash, ip_address, user_agent, enrolling, attempts,
+		       expires_at, used_at, created_at
+		FROM user_login_challenges
+		WHERE token_hash = $1`, tokenHash,
+	).Scan(&challenge.ChallengeID, &challenge.UserID, &challenge.TokenHash, &challenge.IPAddress,
+		&challenge.UserAgent, &challenge.Enrolling, &challenge.Attempts, &challenge.ExpiresAt,
+		&challenge.UsedAt, &challenge.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, user.ErrLoginChallengeInvalid
+		}
+		return nil, fmt.Errorf("failed to get login challenge: %w", err)
+	}
+
+	return &challenge, nil
+}
+
+// RecordFailedAttempt counts a wrong code against a login challenge
+func (r *loginChallengeRepository) RecordFailedAttempt(ctx context.Context, challengeID int) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE user_login_challenges SET attempts = attempts + 1 WHERE challenge_id = $1", challengeID)
+	if err != nil {
+		return fmt.Errorf("failed to record login challenge attempt: %w", err)
+	}
+
+	return nil
+}
+
+// Consume marks a login challenge answered, reporting false when it already was
+func (r *loginChallengeRepository) Consume(ctx context.Context, challengeID int) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE user_login_challenges
+		SET used_at = NOW()
+		WHERE challenge_id = $1 AND used_at IS NULL`, challengeID)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume login challenge: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
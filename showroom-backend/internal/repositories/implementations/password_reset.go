@@ -0,0 +1,105 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+type passwordResetRepository struct {
+	db *sql.DB
+}
+
+// NewPasswordResetRepository creates a new password reset repository
+func NewPasswordResetRepository(db *sql.DB) interfaces.PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+// Create stores a new reset token, invalidating the unused tokens issued to the user before it
+func (r *passwordResetRepository) Create(ctx context.Context, token *user.PasswordResetToken) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE password_reset_tokens
+		SET used_at = NOW()
+		WHERE user_id = $1 AND used_at IS NULL`, token.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate password reset tokens: %w", err)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO password_reset_tokens (user_id, token_hash, ip_address, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING token_id, created_at`,
+		token.UserID, token.TokenHash, token.IPAddress, token.ExpiresAt,
+	).Scan(&token.TokenID, &token.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create password reset token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTokenHash retrieves a reset token by its hash
+func (r *passwordResetRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*user.PasswordResetToken, error) {
+	var token user.PasswordResetToken
+	err := r.db.QueryRowContext(ctx, `
+		SELECT token_id, user_id, token_hash, ip_address, expires_at, used_at, created_at
+		FROM password_reset_tokens
+		WHERE token_hash = $1`, tokenHash,
+	).Scan(&token.TokenID, &token.UserID, &token.TokenHash, &token.IPAddress,
+		&token.ExpiresAt, &token.UsedAt, &token.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, user.ErrPasswordResetTokenInvalid
+		}
+		return nil, fmt.Errorf("failed to get password reset token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// Consume marks a reset token used, reporting false when it already was or has expired
+func (r *passwordResetRepository) Consume(ctx context.Context, tokenID int) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE password_reset_tokens
+		SET used_at = NOW()
+		WHERE token_id = $1 AND used_at IS NULL AND expires_at > NOW()`, tokenID)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume password reset token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// CountSince counts the reset tokens issued to a user since a moment
+func (r *passwordResetRepository) CountSince(ctx context.Context, userID int, since time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM password_reset_tokens
+		WHERE user_id = $1 AND created_at >= $2`, userID, since,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count password reset tokens: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,698 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// paymentRunTotalsJoin derives the line count and totals of a payment run aliased pr from its lines that have not failed
+const paymentRunTotalsJoin = `
+	LEFT JOIN LATERAL (
+		SELECT COUNT(*) AS line_count,
+			   COALESCE(SUM(l.amount), 0) AS total_amount,
+			   COALESCE(SUM(l.discount_amount), 0) AS total_discount
+		FROM supplier_payment_run_lines l
+		WHERE l.run_id = pr.run_id AND l.line_status != 'failed'
+	) totals ON TRUE`
+
+// paymentRunActiveLineCondition matches pending lines of open runs on alias l, so an invoice is only in one open run at a time
+const paymentRunActiveLineCondition = `l.line_status = 'pending'
+	AND EXISTS (
+		SELECT 1 FROM supplier_payment_runs r
+		WHERE r.run_id = l.run_id AND r.run_status IN ('draft', 'approved')
+	)`
+
+// PaymentRunRepository implements interfaces.PaymentRunRepository
+type PaymentRunRepository struct {
+	db *sql.DB
+}
+
+// NewPaymentRunRepository creates a new payment run repository
+func NewPaymentRunRepository(db *sql.DB) interfaces.PaymentRunRepository {
+	return &PaymentRunRepository{db: db}
+}
+
+// GetCandidates retrieves the open invoices in a currency due by dueBefore that are not already in an open payment run
+func (r *PaymentRunRepository) GetCandidates(ctx context.Context, dueBefore time.Time, currencyCode string, supplierID *int) ([]products.PaymentRunCandidate, error) {
+	query := "SELECT " + supplierInvoiceSelectFields + `, s.supplier_code, s.supplier_name, s.bank_account
+		FROM supplier_invoices si` + supplierInvoiceSettlementJoin + `
+		JOIN suppliers s ON si.supplier_id = s.supplier_id
+		WHERE si.invoice_status IN ('pending', 'partial', 'overdue')
+		AND si.due_date < $1::date + INTERVAL '1 day'
+		AND si.currency_code = $2
+		AND ` + supplierInvoiceOutstandingExpr + ` > 0
+		AND NOT EXISTS (
+			SELECT 1 FROM supplier_payment_run_lines l
+			WHERE l.invoice_id = si.invoice_id AND ` + paymentRunActiveLineCondition + `
+		)`
+
+	args := []interface{}{dueBefore, currencyCode}
+	if supplierID != nil {
+		query += " AND si.supplier_id = $3"
+		args = append(args, *supplierID)
+	}
+	query += " ORDER BY si.due_date, si.invoice_id"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query payment run candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []products.PaymentRunCandidate
+	for rows.Next() {
+		var candidate products.PaymentRunCandidate
+		invoice, err := scanSupplierInvoice(trailingColumnsScanner{
+			rowScanner: rows,
+			trailing:   []interface{}{&candidate.SupplierCode, &candidate.SupplierName, &candidate.BankAccount},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan payment run candidate: %w", err)
+		}
+		candidate.Invoice = *invoice
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
+// Create stores a draft payment run with its lines. The invoices are locked while checking that
+// no other open run took them in the meantime.
+func (r *PaymentRunRepository) Create(ctx context.Context, run *products.PaymentRun, lines []products.PaymentRunLine) (*products.PaymentRun, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids := make([]int, 0, len(lines))
+	for _, line := range lines {
+		ids = append(ids, line.InvoiceID)
+	}
+	if _, err := getSupplierInvoices(ctx, tx, ids, true); err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		var runNumber string
+		err := tx.QueryRowContext(ctx, `
+			SELECT r.run_number
+			FROM supplier_payment_run_lines l
+			JOIN supplier_payment_runs r ON r.run_id = l.run_id
+			WHERE l.invoice_id = $1 AND `+paymentRunActiveLineCondition+`
+			LIMIT 1`, line.InvoiceID).Scan(&runNumber)
+		if err == nil {
+			return nil, fmt.Errorf("supplier invoice %s is already in payment run %s", line.InvoiceNumber, runNumber)
+		}
+		if err != sql.ErrNoRows {
+			return nil, fmt.Errorf("failed to check open payment runs: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO supplier_payment_runs (
+			run_number, payment_date, due_before, supplier_id, priority,
+			max_amount, run_status, notes, created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING run_id`
+
+	err = tx.QueryRowContext(ctx, query,
+		run.RunNumber,
+		run.PaymentDate,
+		run.DueBefore,
+		run.SupplierID,
+		run.Priority,
+		run.MaxAmount,
+		products.PaymentRunStatusDraft,
+		run.Notes,
+		run.CreatedBy,
+	).Scan(&run.RunID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment run: %w", err)
+	}
+
+	// Bank accounts are read from the supplier until the run is approved
+	for _, line := range lines {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO supplier_payment_run_lines (run_id, invoice_id, supplier_id, amount, discount_amount, line_status)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			run.RunID, line.InvoiceID, line.SupplierID, line.Amount, line.DiscountAmount, products.PaymentRunLineStatusPending)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create payment run line: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByID(ctx, run.RunID)
+}
+
+// GetByID retrieves a payment run by ID with its lines
+func (r *PaymentRunRepository) GetByID(ctx context.Context, id int) (*products.PaymentRun, error) {
+	query := `
+		SELECT pr.run_id, pr.run_number, pr.payment_date, pr.due_before, pr.supplier_id,
+			   pr.priority, pr.max_amount, pr.run_status, totals.line_count, totals.total_amount,
+			   totals.total_discount, pr.notes, pr.created_by, pr.approved_by, pr.approved_at,
+			   pr.executed_by, pr.executed_at, pr.created_at, pr.updated_at
+		FROM supplier_payment_runs pr` + paymentRunTotalsJoin + `
+		WHERE pr.run_id = $1`
+
+	run := &products.PaymentRun{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&run.RunID,
+		&run.RunNumber,
+		&run.PaymentDate,
+		&run.DueBefore,
+		&run.SupplierID,
+		&run.Priority,
+		&run.MaxAmount,
+		&run.RunStatus,
+		&run.LineCount,
+		&run.TotalAmount,
+		&run.TotalDiscount,
+		&run.Notes,
+		&run.CreatedBy,
+		&run.ApprovedBy,
+		&run.ApprovedAt,
+		&run.ExecutedBy,
+		&run.ExecutedAt,
+		&run.CreatedAt,
+		&run.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("payment run not found")
+		}
+		return nil, fmt.Errorf("failed to get payment run: %w", err)
+	}
+
+	run.Lines, err = r.getLines(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// getLines retrieves the lines of a payment run in the order they were selected
+func (r *PaymentRunRepository) getLines(ctx context.Context, runID int) ([]products.PaymentRunLine, error) {
+	query := `
+		SELECT l.line_id, l.run_id, l.invoice_id, l.supplier_id, l.amount, l.discount_amount,
+			   COALESCE(l.bank_account, s.bank_account), l.line_status, l.voucher_id, l.failure_reason,
+			   l.created_at, l.updated_at, si.invoice_number, si.due_date, s.supplier_code,
+			   s.supplier_name, pv.voucher_number
+		FROM supplier_payment_run_lines l
+		JOIN supplier_invoices si ON si.invoice_id = l.invoice_id
+		JOIN suppliers s ON s.supplier_id = l.supplier_id
+		LEFT JOIN supplier_payment_vouchers pv ON pv.voucher_id = l.voucher_id
+		WHERE l.run_id = $1
+		ORDER BY l.line_id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query payment run lines: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []products.PaymentRunLine
+	for rows.Next() {
+		var line products.PaymentRunLine
+		err := rows.Scan(
+			&line.LineID,
+			&line.RunID,
+			&line.InvoiceID,
+			&line.SupplierID,
+			&line.Amount,
+			&line.DiscountAmount,
+			&line.BankAccount,
+			&line.LineStatus,
+			&line.VoucherID,
+			&line.FailureReason,
+			&line.CreatedAt,
+			&line.UpdatedAt,
+			&line.InvoiceNumber,
+			&line.DueDate,
+			&line.SupplierCode,
+			&line.SupplierName,
+			&line.VoucherNumber,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan payment run line: %w", err)
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// List retrieves payment runs with pagination
+func (r *PaymentRunRepository) List(ctx context.Context, params *products.PaymentRunFilterParams) (*common.PaginatedResponse, error) {
+	baseQuery := `FROM supplier_payment_runs pr` + paymentRunTotalsJoin + ` WHERE 1=1`
+
+	args := []interface{}{}
+	whereConditions := []string{}
+	argIndex := 1
+
+	// Add filters
+	if params.RunStatus != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pr.run_status = $%d", argIndex))
+		args = append(args, *params.RunStatus)
+		argIndex++
+	}
+
+	if params.DateFrom != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pr.payment_date >= $%d", argIndex))
+		args = append(args, *params.DateFrom)
+		argIndex++
+	}
+
+	if params.DateTo != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pr.payment_date <= $%d", argIndex))
+		args = append(args, *params.DateTo)
+		argIndex++
+	}
+
+	if params.Search != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("(pr.run_number ILIKE $%d OR pr.notes ILIKE $%d)", argIndex, argIndex))
+		args = append(args, "%"+params.Search+"%")
+		argIndex++
+	}
+
+	if len(whereConditions) > 0 {
+		baseQuery += " AND " + strings.Join(whereConditions, " AND ")
+	}
+
+	// Count query
+	countQuery := "SELECT COUNT(*) " + baseQuery
+	var total int64
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count payment runs: %w", err)
+	}
+
+	// Calculate pagination
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	// Main query
+	selectFields := `
+		pr.run_id, pr.run_number, pr.payment_date, pr.run_status, totals.line_count,
+		totals.total_amount, totals.total_discount, pr.created_at`
+
+	mainQuery := "SELECT " + selectFields + " " + baseQuery +
+		" ORDER BY pr.payment_date DESC, pr.run_id DESC LIMIT $" + fmt.Sprintf("%d", argIndex) +
+		" OFFSET $" + fmt.Sprintf("%d", argIndex+1)
+
+	args = append(args, params.Limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, mainQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query payment runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []products.PaymentRunListItem
+	for rows.Next() {
+		var run products.PaymentRunListItem
+		err := rows.Scan(
+			&run.RunID,
+			&run.RunNumber,
+			&run.PaymentDate,
+			&run.RunStatus,
+			&run.LineCount,
+			&run.TotalAmount,
+			&run.TotalDiscount,
+			&run.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan payment run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	totalPages := (total + int64(params.Limit) - 1) / int64(params.Limit)
+
+	return &common.PaginatedResponse{
+		Data:       runs,
+		Total:      int(total),
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: int(totalPages),
+		HasMore:    params.Page < int(totalPages),
+	}, nil
+}
+
+// RemoveLine removes an invoice from a draft payment run
+func (r *PaymentRunRepository) RemoveLine(ctx context.Context, runID, lineID int) error {
+	query := `
+		DELETE FROM supplier_payment_run_lines l
+		USING supplier_payment_runs pr
+		WHERE l.line_id = $1 AND l.run_id = $2
+		AND pr.run_id = l.run_id AND pr.run_status = 'draft'`
+
+	result, err := r.db.ExecContext(ctx, query, lineID, runID)
+	if err != nil {
+		return fmt.Errorf("failed to remove payment run line: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("payment run line not found or run is not a draft")
+	}
+
+	if _, err := r.db.ExecContext(ctx, "UPDATE supplier_payment_runs SET updated_at = NOW() WHERE run_id = $1", runID); err != nil {
+		return fmt.Errorf("failed to update payment run: %w", err)
+	}
+
+	return nil
+}
+
+// Approve freezes the supplier bank accounts of a draft run and approves it for the bank file
+func (r *PaymentRunRepository) Approve(ctx context.Context, id int, approvedBy int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockPaymentRun(ctx, tx, id); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE supplier_payment_run_lines l
+		SET bank_account = NULLIF(TRIM(s.bank_account), ''), updated_at = NOW()
+		FROM suppliers s
+		WHERE s.supplier_id = l.supplier_id AND l.run_id = $1 AND l.line_status = 'pending'`, id)
+	if err != nil {
+		return fmt.Errorf("failed to record payment run bank accounts: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE supplier_payment_runs pr
+		SET run_status = 'approved', approved_by = $1, approved_at = NOW(), updated_at = NOW()
+		WHERE pr.run_id = $2 AND pr.run_status = 'draft'
+		AND EXISTS (SELECT 1 FROM supplier_payment_run_lines l WHERE l.run_id = pr.run_id AND l.line_status = 'pending')
+		AND NOT EXISTS (
+			SELECT 1 FROM supplier_payment_run_lines l
+			WHERE l.run_id = pr.run_id AND l.line_status = 'pending' AND l.bank_account IS NULL
+		)`, approvedBy, id)
+	if err != nil {
+		return fmt.Errorf("failed to approve payment run: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("payment run is not a draft, has no lines or has suppliers without a bank account")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Cancel cancels a payment run that has not been executed, releasing its invoices for other runs
+func (r *PaymentRunRepository) Cancel(ctx context.Context, id int) error {
+	query := `
+		UPDATE supplier_payment_runs
+		SET run_status = 'cancelled', updated_at = NOW()
+		WHERE run_id = $1 AND run_status IN ('draft', 'approved')`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to cancel payment run: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("payment run not found or already executed")
+	}
+
+	return nil
+}
+
+// Execute posts one payment voucher per pending line of an approved run in a single transaction.
+// Any line that can no longer be paid fails the whole execution; mark it failed first.
+func (r *PaymentRunRepository) Execute(ctx context.Context, id int, executedBy int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var runNumber string
+	var paymentDate time.Time
+	var status products.PaymentRunStatus
+	err = tx.QueryRowContext(ctx, `
+		SELECT run_number, payment_date, run_status
+		FROM supplier_payment_runs WHERE run_id = $1 FOR UPDATE`, id).Scan(&runNumber, &paymentDate, &status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("payment run not found")
+		}
+		return fmt.Errorf("failed to lock payment run: %w", err)
+	}
+
+	if status != products.PaymentRunStatusApproved {
+		return fmt.Errorf("payment run cannot be executed in %s status", status)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT line_id, invoice_id, supplier_id, amount, discount_amount
+		FROM supplier_payment_run_lines
+		WHERE run_id = $1 AND line_status = 'pending'
+		ORDER BY line_id`, id)
+	if err != nil {
+		return fmt.Errorf("failed to query payment run lines: %w", err)
+	}
+
+	var lines []products.PaymentRunLine
+	for rows.Next() {
+		var line products.PaymentRunLine
+		if err := rows.Scan(&line.LineID, &line.InvoiceID, &line.SupplierID, &line.Amount, &line.DiscountAmount); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan payment run line: %w", err)
+		}
+		lines = append(lines, line)
+	}
+	rows.Close()
+
+	ids := make([]int, 0, len(lines))
+	for _, line := range lines {
+		ids = append(ids, line.InvoiceID)
+	}
+	invoices, err := getSupplierInvoices(ctx, tx, ids, true)
+	if err != nil {
+		return err
+	}
+
+	notes := "Payment run " + runNumber
+	rates := make(map[string]float64)
+	for _, line := range lines {
+		voucherNumber, err := generatePaymentVoucherNumber(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		// Transfers are made in the invoice currency at the rate of the payment date
+		currencyCode := products.BaseCurrency
+		if invoice, ok := invoices[line.InvoiceID]; ok {
+			currencyCode = invoice.CurrencyCode
+		}
+		rate, ok := rates[currencyCode]
+		if !ok {
+			if rate, err = exchangeRateOn(ctx, tx, currencyCode, paymentDate); err != nil {
+				return fmt.Errorf("payment run line %d: %w", line.LineID, err)
+			}
+			rates[currencyCode] = rate
+		}
+
+		voucher := &products.PaymentVoucher{
+			VoucherNumber:    voucherNumber,
+			SupplierID:       line.SupplierID,
+			PaymentDate:      paymentDate,
+			PaymentMethod:    products.PaymentMethodTransfer,
+			PaymentReference: &runNumber,
+			CurrencyCode:     currencyCode,
+			ExchangeRate:     rate,
+			Amount:           line.Amount,
+			VoucherStatus:    products.PaymentVoucherStatusPosted,
+			Notes:            &notes,
+			ProcessedBy:      executedBy,
+		}
+		allocations := []products.PaymentAllocationRequest{{
+			InvoiceID:      line.InvoiceID,
+			Amount:         line.Amount,
+			DiscountAmount: line.DiscountAmount,
+		}}
+		voucher.ApplyInvoiceRates(allocations, invoices)
+		if err := voucher.ValidateAllocations(allocations, invoices); err != nil {
+			return fmt.Errorf("payment run line %d: %w", line.LineID, err)
+		}
+
+		if err := insertPaymentVoucher(ctx, tx, voucher); err != nil {
+			return err
+		}
+		if err := insertPaymentAllocations(ctx, tx, voucher, allocations); err != nil {
+			return err
+		}
+		if err := postJournalEntry(ctx, tx, products.PaymentVoucherJournal(voucher, allocations)); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE supplier_payment_run_lines
+			SET line_status = 'paid', voucher_id = $1, updated_at = NOW()
+			WHERE line_id = $2`, voucher.VoucherID, line.LineID)
+		if err != nil {
+			return fmt.Errorf("failed to update payment run line: %w", err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE supplier_payment_runs
+		SET run_status = 'executed', executed_by = $1, executed_at = NOW(), updated_at = NOW()
+		WHERE run_id = $2`, executedBy, id)
+	if err != nil {
+		return fmt.Errorf("failed to execute payment run: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// FailLine records that the bank rejected a transfer. The voucher of a paid line is voided,
+// which reopens the invoice so a later run can pay it.
+func (r *PaymentRunRepository) FailLine(ctx context.Context, runID, lineID int, reason string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockPaymentRun(ctx, tx, runID); err != nil {
+		return err
+	}
+
+	var status products.PaymentRunLineStatus
+	var voucherID *int
+	err = tx.QueryRowContext(ctx, `
+		SELECT line_status, voucher_id FROM supplier_payment_run_lines
+		WHERE line_id = $1 AND run_id = $2 FOR UPDATE`, lineID, runID).Scan(&status, &voucherID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("payment run line not found")
+		}
+		return fmt.Errorf("failed to lock payment run line: %w", err)
+	}
+
+	if status == products.PaymentRunLineStatusFailed {
+		return fmt.Errorf("payment run line has already failed")
+	}
+
+	if voucherID != nil {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE supplier_payment_vouchers
+			SET voucher_status = 'void', updated_at = NOW()
+			WHERE voucher_id = $1 AND voucher_status = 'posted'`, *voucherID)
+		if err != nil {
+			return fmt.Errorf("failed to void payment voucher: %w", err)
+		}
+
+		_, err = refreshSupplierInvoiceStatuses(ctx, tx,
+			"i.invoice_id IN (SELECT invoice_id FROM supplier_payment_allocations WHERE voucher_id = $1)", *voucherID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := unmatchBankStatementLines(ctx, tx, "voucher_id = $1", *voucherID); err != nil {
+			return err
+		}
+
+		if err := reverseSourceJournals(ctx, tx, products.JournalSourcePaymentVoucher, *voucherID, time.Now(), nil); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE supplier_payment_run_lines
+		SET line_status = 'failed', failure_reason = $1, updated_at = NOW()
+		WHERE line_id = $2`, reason, lineID)
+	if err != nil {
+		return fmt.Errorf("failed to fail payment run line: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateNumber generates a unique payment run number
+func (r *PaymentRunRepository) GenerateNumber(ctx context.Context) (string, error) {
+	// Generate run number with format PRUN-YYYYMMDD-XXXX
+	now := time.Now()
+	dateStr := now.Format("20060102")
+
+	query := `
+		SELECT COALESCE(MAX(
+			CAST(SUBSTRING(run_number FROM 'PRUN-\d{8}-(\d+)') AS INTEGER)
+		), 0) + 1
+		FROM supplier_payment_runs
+		WHERE run_number LIKE $1`
+
+	prefix := "PRUN-" + dateStr + "-%"
+	var nextNumber int
+	err := r.db.QueryRowContext(ctx, query, prefix).Scan(&nextNumber)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate payment run number: %w", err)
+	}
+
+	return fmt.Sprintf("PRUN-%s-%04d", dateStr, nextNumber), nil
+}
+
+// lockPaymentRun locks a payment run row so status changes and line updates are serialized
+func lockPaymentRun(ctx context.Context, tx *sql.Tx, id int) error {
+	var runID int
+	err := tx.QueryRowContext(ctx, "SELECT run_id FROM supplier_payment_runs WHERE run_id = $1 FOR UPDATE", id).Scan(&runID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("payment run not found")
+		}
+		return fmt.Errorf("failed to lock payment run: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// PaymentTermRepository implements interfaces.PaymentTermRepository
+type PaymentTermRepository struct {
+	db *sql.DB
+}
+
+// NewPaymentTermRepository creates a new payment term repository
+func NewPaymentTermRepository(db *sql.DB) interfaces.PaymentTermRepository {
+	return &PaymentTermRepository{db: db}
+}
+
+// Create creates new payment terms
+func (r *PaymentTermRepository) Create(ctx context.Context, term *products.PaymentTerm) (*products.PaymentTerm, error) {
+	query := `
+		INSERT INTO payment_terms (term_code, term_name, net_days, discount_percent, discount_days)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING term_id, is_active, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		term.TermCode,
+		term.TermName,
+		term.NetDays,
+		term.DiscountPercent,
+		term.DiscountDays,
+	).Scan(&term.TermID, &term.IsActive, &term.CreatedAt, &term.UpdatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment term: %w", err)
+	}
+
+	return term, nil
+}
+
+// GetByID retrieves payment terms by ID
+func (r *PaymentTermRepository) GetByID(ctx context.Context, id int) (*products.PaymentTerm, error) {
+	query := `
+		SELECT term_id, term_code, term_name, net_days, discount_percent, discount_days,
+			   is_active, created_at, updated_at
+		FROM payment_terms
+		WHERE term_id = $1`
+
+	term := &products.PaymentTerm{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&term.TermID,
+		&term.TermCode,
+		&term.TermName,
+		&term.NetDays,
+		&term.DiscountPercent,
+		&term.DiscountDays,
+		&term.IsActive,
+		&term.CreatedAt,
+		&term.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("payment term not found")
+		}
+		return nil, fmt.Errorf("failed to get payment term: %w", err)
+	}
+
+	return term, nil
+}
+
+// Update updates payment terms. Invoices keep the due dates and discounts they were created with.
+func (r *PaymentTermRepository) Update(ctx context.Context, id int, term *products.PaymentTerm) (*products.PaymentTerm, error) {
+	query := `
+		UPDATE payment_terms SET
+			term_name = $1, net_days = $2, discount_percent = $3, discount_days = $4,
+			is_active = $5, updated_at = NOW()
+		WHERE term_id = $6
+		RETURNING updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		term.TermName,
+		term.NetDays,
+		term.DiscountPercent,
+		term.DiscountDays,
+		term.IsActive,
+		id,
+	).Scan(&term.UpdatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("payment term not found")
+		}
+		return nil, fmt.Errorf("failed to update payment term: %w", err)
+	}
+
+	term.TermID = id
+	return term, nil
+}
+
+// Delete deletes payment terms that no supplier or invoice uses
+func (r *PaymentTermRepository) Delete(ctx context.Context, id int) error {
+	query := `
+		DELETE FROM payment_terms pt
+		WHERE pt.term_id = $1
+		AND NOT EXISTS (SELECT 1 FROM suppliers s WHERE s.payment_term_id = pt.term_id)
+		AND NOT EXISTS (SELECT 1 FROM supplier_invoices si WHERE si.payment_term_id = pt.term_id)`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete payment term: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("payment term not found or in use; deactivate it instead")
+	}
+
+	return nil
+}
+
+// List retrieves payment terms with pagination
+func (r *PaymentTermRepository) List(ctx context.Context, params *products.PaymentTermFilterParams) (*common.PaginatedResponse, error) {
+	baseQuery := `FROM payment_terms pt WHERE 1=1`
+
+	args := []interface{}{}
+	whereConditions := []string{}
+	argIndex := 1
+
+	// Add filters
+	if params.IsActive != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pt.is_active = $%d", argIndex))
+		args = append(args, *params.IsActive)
+		argIndex++
+	}
+
+	if params.HasDiscount != nil {
+		if *params.HasDiscount {
+			whereConditions = append(whereConditions, "pt.discount_percent > 0")
+		} else {
+			whereConditions = append(whereConditions, "pt.discount_percent = 0")
+		}
+	}
+
+	if params.Search != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("(pt.term_code ILIKE $%d OR pt.term_name ILIKE $%d)", argIndex, argIndex))
+		args = append(args, "%"+params.Search+"%")
+		argIndex++
+	}
+
+	if len(whereConditions) > 0 {
+		baseQuery += " AND " + strings.Join(whereConditions, " AND ")
+	}
+
+	// Count query
+	countQuery := "SELECT COUNT(*) " + baseQuery
+	var total int64
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count payment terms: %w", err)
+	}
+
+	// Calculate pagination
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	// Main query
+	selectFields := `
+		pt.term_id, pt.term_code, pt.term_name, pt.net_days, pt.discount_percent,
+		pt.discount_days, pt.is_active, pt.created_at, pt.updated_at`
+
+	mainQuery := "SELECT " + selectFields + " " + baseQuery +
+		" ORDER BY pt.net_days ASC, pt.term_code ASC LIMIT $" + fmt.Sprintf("%d", argIndex) +
+		" OFFSET $" + fmt.Sprintf("%d", argIndex+1)
+
+	args = append(args, params.Limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, mainQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query payment terms: %w", err)
+	}
+	defer rows.Close()
+
+	var terms []products.PaymentTerm
+	for rows.Next() {
+		var term products.PaymentTerm
+		err := rows.Scan(
+			&term.TermID,
+			&term.TermCode,
+			&term.TermName,
+			&term.NetDays,
+			&term.DiscountPercent,
+			&term.DiscountDays,
+			&term.IsActive,
+			&term.CreatedAt,
+			&term.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan payment term: %w", err)
+		}
+		terms = append(terms, term)
+	}
+
+	totalPages := (total + int64(params.Limit) - 1) / int64(params.Limit)
+
+	return &common.PaginatedResponse{
+		Data:       terms,
+		Total:      int(total),
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: int(totalPages),
+		HasMore:    params.Page < int(totalPages),
+	}, nil
+}
+
+// IsCodeExists checks if a payment term code is already used
+func (r *PaymentTermRepository) IsCodeExists(ctx context.Context, code string, excludeID int) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM payment_terms WHERE UPPER(term_code) = UPPER($1) AND term_id != $2)`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, code, excludeID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check payment term code existence: %w", err)
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,512 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// paymentVoucherAllocatedExpr is the amount of a voucher aliased pv already allocated to invoices
+const paymentVoucherAllocatedExpr = `COALESCE((
+	SELECT SUM(pa.allocated_amount) FROM supplier_payment_allocations pa WHERE pa.voucher_id = pv.voucher_id
+), 0)`
+
+// sqlRowQuerier is satisfied by both *sql.DB and *sql.Tx
+type sqlRowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// PaymentVoucherRepository implements interfaces.PaymentVoucherRepository
+type PaymentVoucherRepository struct {
+	db *sql.DB
+}
+
+// NewPaymentVoucherRepository creates a new payment voucher repository
+func NewPaymentVoucherRepository(db *sql.DB) interfaces.PaymentVoucherRepository {
+	return &PaymentVoucherRepository{db: db}
+}
+
+// Create records a payment voucher and allocates it to supplier invoices in one transaction
+func (r *PaymentVoucherRepository) Create(ctx context.Context, voucher *products.PaymentVoucher, allocations []products.PaymentAllocationRequest) (*products.PaymentVoucher, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	invoices, err := getSupplierInvoices(ctx, tx, allocationInvoiceIDs(allocations), true)
+	if err != nil {
+		return nil, err
+	}
+
+	voucher.AllocatedAmount = 0
+	voucher.VoucherStatus = products.PaymentVoucherStatusPosted
+	voucher.ApplyEarlyPaymentDiscounts(allocations, invoices)
+	voucher.ApplyInvoiceRates(allocations, invoices)
+	if err := voucher.ValidateAllocations(allocations, invoices); err != nil {
+		return nil, err
+	}
+
+	if err := insertPaymentVoucher(ctx, tx, voucher); err != nil {
+		return nil, err
+	}
+
+	if err := insertPaymentAllocations(ctx, tx, voucher, allocations); err != nil {
+		return nil, err
+	}
+
+	if err := postJournalEntry(ctx, tx, products.PaymentVoucherJournal(voucher, allocations)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByID(ctx, voucher.VoucherID)
+}
+
+// GetByID retrieves a payment voucher by ID with its allocations
+func (r *PaymentVoucherRepository) GetByID(ctx context.Context, id int) (*products.PaymentVoucher, error) {
+	voucher, err := getPaymentVoucher(ctx, r.db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT pa.allocation_id, pa.voucher_id, pa.invoice_id, pa.allocated_amount,
+			   pa.discount_amount, pa.fx_gain_loss, pa.created_at, pv.voucher_number, si.invoice_number,
+			   pv.payment_date, pv.payment_method, pv.payment_reference, pv.voucher_status
+		FROM supplier_payment_allocations pa
+		JOIN supplier_payment_vouchers pv ON pv.voucher_id = pa.voucher_id
+		JOIN supplier_invoices si ON si.invoice_id = pa.invoice_id
+		WHERE pa.voucher_id = $1
+		ORDER BY pa.allocation_id ASC`
+
+	voucher.Allocations, err = queryPaymentAllocations(ctx, r.db, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return voucher, nil
+}
+
+// getPaymentVoucher loads a payment voucher header with its allocated and unallocated amounts
+func getPaymentVoucher(ctx context.Context, db sqlRowQuerier, id int) (*products.PaymentVoucher, error) {
+	query := `
+		SELECT pv.voucher_id, pv.voucher_number, pv.supplier_id, pv.payment_date,
+			   pv.payment_method, pv.payment_reference, pv.currency_code, pv.exchange_rate,
+			   pv.amount, ` + paymentVoucherAllocatedExpr + `,
+			   pv.voucher_status, pv.notes, pv.processed_by, pv.created_at, pv.updated_at
+		FROM supplier_payment_vouchers pv
+		WHERE pv.voucher_id = $1`
+
+	voucher := &products.PaymentVoucher{}
+	err := db.QueryRowContext(ctx, query, id).Scan(
+		&voucher.VoucherID,
+		&voucher.VoucherNumber,
+		&voucher.SupplierID,
+		&voucher.PaymentDate,
+		&voucher.PaymentMethod,
+		&voucher.PaymentReference,
+		&voucher.CurrencyCode,
+		&voucher.ExchangeRate,
+		&voucher.Amount,
+		&voucher.AllocatedAmount,
+		&voucher.VoucherStatus,
+		&voucher.Notes,
+		&voucher.ProcessedBy,
+		&voucher.CreatedAt,
+		&voucher.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("payment voucher not found")
+		}
+		return nil, fmt.Errorf("failed to get payment voucher: %w", err)
+	}
+
+	voucher.CalculateUnallocatedAmount()
+	return voucher, nil
+}
+
+// Update updates the payment details of a voucher. Amounts are changed by voiding and re-entering.
+func (r *PaymentVoucherRepository) Update(ctx context.Context, id int, voucher *products.PaymentVoucher) (*products.PaymentVoucher, error) {
+	query := `
+		UPDATE supplier_payment_vouchers SET
+			payment_date = $1, payment_method = $2, payment_reference = $3,
+			notes = $4, updated_at = NOW()
+		WHERE voucher_id = $5 AND voucher_status = 'posted'`
+
+	result, err := r.db.ExecContext(ctx, query,
+		voucher.PaymentDate,
+		voucher.PaymentMethod,
+		voucher.PaymentReference,
+		voucher.Notes,
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update payment voucher: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("payment voucher not found or void")
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// List retrieves payment vouchers with pagination
+func (r *PaymentVoucherRepository) List(ctx context.Context, params *products.PaymentVoucherFilterParams) (*common.PaginatedResponse, error) {
+	baseQuery := `
+		FROM supplier_payment_vouchers pv
+		LEFT JOIN suppliers s ON pv.supplier_id = s.supplier_id
+		WHERE 1=1`
+
+	args := []interface{}{}
+	whereConditions := []string{}
+	argIndex := 1
+
+	// Add filters
+	if params.SupplierID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pv.supplier_id = $%d", argIndex))
+		args = append(args, *params.SupplierID)
+		argIndex++
+	}
+
+	if params.InvoiceID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM supplier_payment_allocations pa WHERE pa.voucher_id = pv.voucher_id AND pa.invoice_id = $%d)", argIndex))
+		args = append(args, *params.InvoiceID)
+		argIndex++
+	}
+
+	if params.PaymentMethod != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pv.payment_method = $%d", argIndex))
+		args = append(args, *params.PaymentMethod)
+		argIndex++
+	}
+
+	if params.VoucherStatus != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pv.voucher_status = $%d", argIndex))
+		args = append(args, *params.VoucherStatus)
+		argIndex++
+	}
+
+	if params.DateFrom != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pv.payment_date >= $%d", argIndex))
+		args = append(args, *params.DateFrom)
+		argIndex++
+	}
+
+	if params.DateTo != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pv.payment_date <= $%d", argIndex))
+		args = append(args, *params.DateTo)
+		argIndex++
+	}
+
+	if params.HasUnallocated != nil {
+		if *params.HasUnallocated {
+			whereConditions = append(whereConditions, "pv.voucher_status = 'posted' AND pv.amount > "+paymentVoucherAllocatedExpr)
+		} else {
+			whereConditions = append(whereConditions, "pv.amount <= "+paymentVoucherAllocatedExpr)
+		}
+	}
+
+	if params.Search != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("(pv.voucher_number ILIKE $%d OR pv.payment_reference ILIKE $%d OR s.supplier_name ILIKE $%d)", argIndex, argIndex, argIndex))
+		args = append(args, "%"+params.Search+"%")
+		argIndex++
+	}
+
+	if len(whereConditions) > 0 {
+		baseQuery += " AND " + strings.Join(whereConditions, " AND ")
+	}
+
+	// Count query
+	countQuery := "SELECT COUNT(*) " + baseQuery
+	var total int64
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count payment vouchers: %w", err)
+	}
+
+	// Calculate pagination
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	// Main query
+	selectFields := `
+		pv.voucher_id, pv.voucher_number, pv.supplier_id, COALESCE(s.supplier_name, ''),
+		pv.payment_date, pv.payment_method, pv.payment_reference, pv.currency_code, pv.amount,
+		CASE WHEN pv.voucher_status = 'posted' THEN pv.amount - ` + paymentVoucherAllocatedExpr + ` ELSE 0 END,
+		pv.voucher_status`
+
+	mainQuery := "SELECT " + selectFields + " " + baseQuery +
+		" ORDER BY pv.payment_date DESC, pv.voucher_id DESC LIMIT $" + fmt.Sprintf("%d", argIndex) +
+		" OFFSET $" + fmt.Sprintf("%d", argIndex+1)
+
+	args = append(args, params.Limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, mainQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query payment vouchers: %w", err)
+	}
+	defer rows.Close()
+
+	var vouchers []products.PaymentVoucherListItem
+	for rows.Next() {
+		var voucher products.PaymentVoucherListItem
+		err := rows.Scan(
+			&voucher.VoucherID,
+			&voucher.VoucherNumber,
+			&voucher.SupplierID,
+			&voucher.SupplierName,
+			&voucher.PaymentDate,
+			&voucher.PaymentMethod,
+			&voucher.PaymentReference,
+			&voucher.CurrencyCode,
+			&voucher.Amount,
+			&voucher.UnallocatedAmount,
+			&voucher.VoucherStatus,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan payment voucher: %w", err)
+		}
+		vouchers = append(vouchers, voucher)
+	}
+
+	totalPages := (total + int64(params.Limit) - 1) / int64(params.Limit)
+
+	return &common.PaginatedResponse{
+		Data:       vouchers,
+		Total:      int(total),
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: int(totalPages),
+		HasMore:    params.Page < int(totalPages),
+	}, nil
+}
+
+// Allocate allocates the unallocated amount of a posted voucher to supplier invoices
+func (r *PaymentVoucherRepository) Allocate(ctx context.Context, id int, allocations []products.PaymentAllocationRequest) (*products.PaymentVoucher, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockPaymentVoucher(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	voucher, err := getPaymentVoucher(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, err := getSupplierInvoices(ctx, tx, allocationInvoiceIDs(allocations), true)
+	if err != nil {
+		return nil, err
+	}
+
+	voucher.ApplyEarlyPaymentDiscounts(allocations, invoices)
+	voucher.ApplyInvoiceRates(allocations, invoices)
+	if err := voucher.ValidateAllocations(allocations, invoices); err != nil {
+		return nil, err
+	}
+
+	if err := insertPaymentAllocations(ctx, tx, voucher, allocations); err != nil {
+		return nil, err
+	}
+
+	if err := postJournalEntry(ctx, tx, products.PaymentAllocationJournal(voucher, allocations, time.Now())); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE supplier_payment_vouchers SET updated_at = NOW() WHERE voucher_id = $1", id); err != nil {
+		return nil, fmt.Errorf("failed to update payment voucher: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// Void voids a posted voucher so its allocations no longer settle the invoices
+func (r *PaymentVoucherRepository) Void(ctx context.Context, id int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockPaymentVoucher(ctx, tx, id); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE supplier_payment_vouchers
+		SET voucher_status = 'void', updated_at = NOW()
+		WHERE voucher_id = $1 AND voucher_status = 'posted'`, id)
+	if err != nil {
+		return fmt.Errorf("failed to void payment voucher: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("payment voucher is already void")
+	}
+
+	_, err = refreshSupplierInvoiceStatuses(ctx, tx,
+		"i.invoice_id IN (SELECT invoice_id FROM supplier_payment_allocations WHERE voucher_id = $1)", id)
+	if err != nil {
+		return err
+	}
+
+	// A void payment no longer explains the bank transaction it was reconciled with
+	if _, err := unmatchBankStatementLines(ctx, tx, "voucher_id = $1", id); err != nil {
+		return err
+	}
+
+	if err := reverseSourceJournals(ctx, tx, products.JournalSourcePaymentVoucher, id, time.Now(), nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateNumber generates a unique payment voucher number
+func (r *PaymentVoucherRepository) GenerateNumber(ctx context.Context) (string, error) {
+	return generatePaymentVoucherNumber(ctx, r.db)
+}
+
+// generatePaymentVoucherNumber generates the next voucher number. Inside a transaction it
+// sees the vouchers already inserted by that transaction.
+func generatePaymentVoucherNumber(ctx context.Context, db sqlRowQuerier) (string, error) {
+	// Generate voucher number with format PV-YYYYMMDD-XXXX
+	now := time.Now()
+	dateStr := now.Format("20060102")
+
+	query := `
+		SELECT COALESCE(MAX(
+			CAST(SUBSTRING(voucher_number FROM 'PV-\d{8}-(\d+)') AS INTEGER)
+		), 0) + 1
+		FROM supplier_payment_vouchers
+		WHERE voucher_number LIKE $1`
+
+	prefix := "PV-" + dateStr + "-%"
+	var nextNumber int
+	err := db.QueryRowContext(ctx, query, prefix).Scan(&nextNumber)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate payment voucher number: %w", err)
+	}
+
+	return fmt.Sprintf("PV-%s-%04d", dateStr, nextNumber), nil
+}
+
+// insertPaymentVoucher stores a voucher header
+func insertPaymentVoucher(ctx context.Context, tx *sql.Tx, voucher *products.PaymentVoucher) error {
+	query := `
+		INSERT INTO supplier_payment_vouchers (
+			voucher_number, supplier_id, payment_date, payment_method,
+			payment_reference, amount, voucher_status, notes, processed_by,
+			currency_code, exchange_rate
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING voucher_id, created_at, updated_at`
+
+	err := tx.QueryRowContext(ctx, query,
+		voucher.VoucherNumber,
+		voucher.SupplierID,
+		voucher.PaymentDate,
+		voucher.PaymentMethod,
+		voucher.PaymentReference,
+		voucher.Amount,
+		voucher.VoucherStatus,
+		voucher.Notes,
+		voucher.ProcessedBy,
+		voucher.CurrencyCode,
+		voucher.ExchangeRate,
+	).Scan(&voucher.VoucherID, &voucher.CreatedAt, &voucher.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create payment voucher: %w", err)
+	}
+
+	return nil
+}
+
+// insertPaymentAllocations stores voucher allocations with their realized exchange difference
+// and re-derives the status of the invoices they settle
+func insertPaymentAllocations(ctx context.Context, tx *sql.Tx, voucher *products.PaymentVoucher, allocations []products.PaymentAllocationRequest) error {
+	for _, allocation := range allocations {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO supplier_payment_allocations (voucher_id, invoice_id, allocated_amount, discount_amount, fx_gain_loss)
+			VALUES ($1, $2, $3, $4, $5)`,
+			voucher.VoucherID, allocation.InvoiceID, allocation.Amount, allocation.DiscountAmount,
+			allocation.ExchangeDifference(voucher.ExchangeRate))
+		if err != nil {
+			return fmt.Errorf("failed to create payment allocation: %w", err)
+		}
+	}
+
+	if len(allocations) == 0 {
+		return nil
+	}
+
+	_, err := refreshSupplierInvoiceStatuses(ctx, tx,
+		"i.invoice_id IN (SELECT invoice_id FROM supplier_payment_allocations WHERE voucher_id = $1)", voucher.VoucherID)
+	return err
+}
+
+// lockPaymentVoucher locks a voucher row so concurrent allocations see each other
+func lockPaymentVoucher(ctx context.Context, tx *sql.Tx, id int) error {
+	var voucherID int
+	err := tx.QueryRowContext(ctx, "SELECT voucher_id FROM supplier_payment_vouchers WHERE voucher_id = $1 FOR UPDATE", id).Scan(&voucherID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("payment voucher not found")
+		}
+		return fmt.Errorf("failed to lock payment voucher: %w", err)
+	}
+	return nil
+}
+
+// allocationInvoiceIDs returns the distinct invoice IDs referenced by allocations
+func allocationInvoiceIDs(allocations []products.PaymentAllocationRequest) []int {
+	seen := make(map[int]bool, len(allocations))
+	ids := make([]int, 0, len(allocations))
+	for _, allocation := range allocations {
+		if !seen[allocation.InvoiceID] {
+			seen[allocation.InvoiceID] = true
+			ids = append(ids, allocation.InvoiceID)
+		}
+	}
+	return ids
+}
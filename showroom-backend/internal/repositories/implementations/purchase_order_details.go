@@ -26,8 +26,9 @@ func (r *PurchaseOrderDetailRepository) Create(ctx context.Context, detail *prod
 	query := `
 		INSERT INTO purchase_order_details (
 			po_id, product_id, item_description, quantity_ordered, 
-			unit_cost, total_cost, expected_date, line_status, item_notes
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			unit_cost, total_cost, expected_date, line_status, item_notes,
+			tax_code_id, tax_rate, tax_amount
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING po_detail_id, quantity_received, quantity_pending, received_date`
 
 	// Calculate total cost and set initial values
@@ -46,6 +47,9 @@ func (r *PurchaseOrderDetailRepository) Create(ctx context.Context, detail *prod
 		detail.ExpectedDate,
 		detail.LineStatus,
 		detail.ItemNotes,
+		detail.TaxCodeID,
+		detail.TaxRate,
+		detail.TaxAmount,
 	).Scan(&detail.PODetailID, &detail.QuantityReceived, &detail.QuantityPending, &detail.ReceivedDate)
 
 	if err != nil {
@@ -60,6 +64,7 @@ func (r *PurchaseOrderDetailRepository) GetByID(ctx context.Context, id int) (*p
 	query := `
 		SELECT po_detail_id, po_id, product_id, item_description, quantity_ordered,
 			   quantity_received, quantity_pending, unit_cost, total_cost,
+			   tax_code_id, tax_rate, tax_amount,
 			   expected_date, received_date, line_status, item_notes
 		FROM purchase_order_details 
 		WHERE po_detail_id = $1`
@@ -75,6 +80,9 @@ func (r *PurchaseOrderDetailRepository) GetByID(ctx context.Context, id int) (*p
 		&detail.QuantityPending,
 		&detail.UnitCost,
 		&detail.TotalCost,
+		&detail.TaxCodeID,
+		&detail.TaxRate,
+		&detail.TaxAmount,
 		&detail.ExpectedDate,
 		&detail.ReceivedDate,
 		&detail.LineStatus,
@@ -197,7 +205,7 @@ func (r *PurchaseOrderDetailRepository) GetByPOID(ctx context.Context, poID int,
 	selectFields := `
 		pod.po_detail_id, pod.po_id, pod.product_id, pod.item_description,
 		pod.quantity_ordered, pod.quantity_received, pod.quantity_pending,
-		pod.unit_cost, pod.total_cost, pod.line_status`
+		pod.unit_cost, pod.total_cost, pod.tax_amount, pod.line_status`
 	
 	mainQuery := "SELECT " + selectFields + " " + baseQuery + 
 		" ORDER BY pod.po_detail_id ASC LIMIT $" + fmt.Sprintf("%d", argIndex) + 
@@ -224,6 +232,7 @@ func (r *PurchaseOrderDetailRepository) GetByPOID(ctx context.Context, poID int,
 			&detail.QuantityPending,
 			&detail.UnitCost,
 			&detail.TotalCost,
+			&detail.TaxAmount,
 			&detail.LineStatus,
 		)
 		if err != nil {
@@ -294,7 +303,7 @@ func (r *PurchaseOrderDetailRepository) GetByProductID(ctx context.Context, prod
 	selectFields := `
 		pod.po_detail_id, pod.po_id, pod.product_id, pod.item_description,
 		pod.quantity_ordered, pod.quantity_received, pod.quantity_pending,
-		pod.unit_cost, pod.total_cost, pod.line_status`
+		pod.unit_cost, pod.total_cost, pod.tax_amount, pod.line_status`
 	
 	mainQuery := "SELECT " + selectFields + " " + baseQuery + 
 		" ORDER BY pod.po_detail_id DESC LIMIT $" + fmt.Sprintf("%d", argIndex) + 
@@ -321,6 +330,7 @@ func (r *PurchaseOrderDetailRepository) GetByProductID(ctx context.Context, prod
 			&detail.QuantityPending,
 			&detail.UnitCost,
 			&detail.TotalCost,
+			&detail.TaxAmount,
 			&detail.LineStatus,
 		)
 		if err != nil {
@@ -383,6 +393,7 @@ func (r *PurchaseOrderDetailRepository) GetPendingReceiptItems(ctx context.Conte
 	query := `
 		SELECT po_detail_id, po_id, product_id, item_description, quantity_ordered,
 			   quantity_received, quantity_pending, unit_cost, total_cost,
+			   tax_code_id, tax_rate, tax_amount,
 			   expected_date, received_date, line_status, item_notes
 		FROM purchase_order_details 
 		WHERE po_id = $1 AND line_status IN ('pending', 'partial')
@@ -407,6 +418,9 @@ func (r *PurchaseOrderDetailRepository) GetPendingReceiptItems(ctx context.Conte
 			&detail.QuantityPending,
 			&detail.UnitCost,
 			&detail.TotalCost,
+			&detail.TaxCodeID,
+			&detail.TaxRate,
+			&detail.TaxAmount,
 			&detail.ExpectedDate,
 			&detail.ReceivedDate,
 			&detail.LineStatus,
@@ -433,18 +447,32 @@ func (r *PurchaseOrderDetailRepository) BulkCreate(ctx context.Context, details
 	}
 	defer tx.Rollback()
 
+	if err := insertPurchaseOrderDetails(ctx, tx, details); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// insertPurchaseOrderDetails stores purchase order lines as pending
+func insertPurchaseOrderDetails(ctx context.Context, db sqlExecer, details []products.PurchaseOrderDetail) error {
 	query := `
 		INSERT INTO purchase_order_details (
 			po_id, product_id, item_description, quantity_ordered, 
-			unit_cost, total_cost, expected_date, line_status, item_notes
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+			unit_cost, total_cost, expected_date, line_status, item_notes,
+			tax_code_id, tax_rate, tax_amount
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
 
 	for _, detail := range details {
 		// Calculate total cost and set initial values
 		detail.TotalCost = float64(detail.QuantityOrdered) * detail.UnitCost
 		detail.LineStatus = products.LineStatusPending
 
-		_, err := tx.ExecContext(ctx, query,
+		_, err := db.ExecContext(ctx, query,
 			detail.POID,
 			detail.ProductID,
 			detail.ItemDescription,
@@ -454,16 +482,15 @@ func (r *PurchaseOrderDetailRepository) BulkCreate(ctx context.Context, details
 			detail.ExpectedDate,
 			detail.LineStatus,
 			detail.ItemNotes,
+			detail.TaxCodeID,
+			detail.TaxRate,
+			detail.TaxAmount,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to create purchase order detail: %w", err)
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
 	return nil
 }
 
@@ -25,16 +25,25 @@ func NewPurchaseOrderPartsRepository(db *sql.DB) interfaces.PurchaseOrderPartsRe
 
 // Create creates a new purchase order
 func (r *PurchaseOrderPartsRepository) Create(ctx context.Context, po *products.PurchaseOrderParts) (*products.PurchaseOrderParts, error) {
+	if err := insertPurchaseOrder(ctx, r.db, po); err != nil {
+		return nil, err
+	}
+
+	return po, nil
+}
+
+// insertPurchaseOrder stores a purchase order header, inside a transaction when db is one
+func insertPurchaseOrder(ctx context.Context, db sqlRowQuerier, po *products.PurchaseOrderParts) error {
 	query := `
 		INSERT INTO purchase_orders_parts (
 			po_number, supplier_id, po_date, required_date, expected_delivery_date,
 			po_type, subtotal, tax_amount, discount_amount, shipping_cost, total_amount,
 			status, payment_terms, payment_due_date, created_by, delivery_address,
-			po_notes, terms_and_conditions
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+			po_notes, terms_and_conditions, prices_include_tax, currency_code, exchange_rate
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
 		RETURNING po_id, created_at, updated_at`
 
-	err := r.db.QueryRowContext(ctx, query,
+	err := db.QueryRowContext(ctx, query,
 		po.PONumber,
 		po.SupplierID,
 		po.PODate,
@@ -53,13 +62,16 @@ func (r *PurchaseOrderPartsRepository) Create(ctx context.Context, po *products.
 		po.DeliveryAddress,
 		po.PONotes,
 		po.TermsAndConditions,
+		po.PricesIncludeTax,
+		po.CurrencyCode,
+		po.ExchangeRate,
 	).Scan(&po.POID, &po.CreatedAt, &po.UpdatedAt)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to create purchase order: %w", err)
+		return fmt.Errorf("failed to create purchase order: %w", err)
 	}
 
-	return po, nil
+	return nil
 }
 
 // GetByID retrieves a purchase order by ID
@@ -68,7 +80,8 @@ func (r *PurchaseOrderPartsRepository) GetByID(ctx context.Context, id int) (*pr
 		SELECT po_id, po_number, supplier_id, po_date, required_date, expected_delivery_date,
 			   po_type, subtotal, tax_amount, discount_amount, shipping_cost, total_amount,
 			   status, payment_terms, payment_due_date, created_by, approved_by, approved_at,
-			   delivery_address, po_notes, terms_and_conditions, created_at, updated_at
+			   delivery_address, po_notes, terms_and_conditions, prices_include_tax,
+			   currency_code, exchange_rate, created_at, updated_at
 		FROM purchase_orders_parts
 		WHERE po_id = $1`
 
@@ -95,6 +108,9 @@ func (r *PurchaseOrderPartsRepository) GetByID(ctx context.Context, id int) (*pr
 		&po.DeliveryAddress,
 		&po.PONotes,
 		&po.TermsAndConditions,
+		&po.PricesIncludeTax,
+		&po.CurrencyCode,
+		&po.ExchangeRate,
 		&po.CreatedAt,
 		&po.UpdatedAt,
 	)
@@ -115,7 +131,8 @@ func (r *PurchaseOrderPartsRepository) GetByNumber(ctx context.Context, number s
 		SELECT po_id, po_number, supplier_id, po_date, required_date, expected_delivery_date,
 			   po_type, subtotal, tax_amount, discount_amount, shipping_cost, total_amount,
 			   status, payment_terms, payment_due_date, created_by, approved_by, approved_at,
-			   delivery_address, po_notes, terms_and_conditions, created_at, updated_at
+			   delivery_address, po_notes, terms_and_conditions, prices_include_tax,
+			   currency_code, exchange_rate, created_at, updated_at
 		FROM purchase_orders_parts
 		WHERE po_number = $1`
 
@@ -142,6 +159,9 @@ func (r *PurchaseOrderPartsRepository) GetByNumber(ctx context.Context, number s
 		&po.DeliveryAddress,
 		&po.PONotes,
 		&po.TermsAndConditions,
+		&po.PricesIncludeTax,
+		&po.CurrencyCode,
+		&po.ExchangeRate,
 		&po.CreatedAt,
 		&po.UpdatedAt,
 	)
@@ -237,7 +257,7 @@ func (r *PurchaseOrderPartsRepository) List(ctx context.Context, params *product
 
 	baseQuery := `
 		SELECT po_id, po_number, supplier_id, po_date, required_date, expected_delivery_date,
-			   po_type, total_amount, status, payment_terms, created_at
+			   po_type, currency_code, total_amount, status, payment_terms, created_at
 		FROM purchase_orders_parts`
 
 	countQuery := `SELECT COUNT(*) FROM purchase_orders_parts`
@@ -277,6 +297,7 @@ func (r *PurchaseOrderPartsRepository) List(ctx context.Context, params *product
 			&item.RequiredDate,
 			&item.ExpectedDeliveryDate,
 			&item.POType,
+			&item.CurrencyCode,
 			&item.TotalAmount,
 			&item.Status,
 			&item.PaymentTerms,
@@ -316,7 +337,7 @@ func (r *PurchaseOrderPartsRepository) GetPendingApproval(ctx context.Context, p
 
 	baseQuery := `
 		SELECT po_id, po_number, supplier_id, po_date, required_date, expected_delivery_date,
-			   po_type, total_amount, status, payment_terms, created_at
+			   po_type, currency_code, total_amount, status, payment_terms, created_at
 		FROM purchase_orders_parts
 		WHERE status = 'draft' AND approved_by IS NULL`
 
@@ -373,6 +394,7 @@ func (r *PurchaseOrderPartsRepository) GetPendingApproval(ctx context.Context, p
 			&item.RequiredDate,
 			&item.ExpectedDeliveryDate,
 			&item.POType,
+			&item.CurrencyCode,
 			&item.TotalAmount,
 			&item.Status,
 			&item.PaymentTerms,
@@ -477,35 +499,50 @@ func (r *PurchaseOrderPartsRepository) IsNumberExists(ctx context.Context, numbe
 
 // CalculateTotals calculates and updates totals for a purchase order
 func (r *PurchaseOrderPartsRepository) CalculateTotals(ctx context.Context, id int) (*products.PurchaseOrderParts, error) {
-	// Calculate subtotal from line items
+	// Recalculate line taxes, as quantities and costs may have changed since the rate was applied
+	lineTaxQuery := `
+		UPDATE purchase_order_details d SET tax_amount = CASE
+				WHEN d.tax_rate = 0 THEN 0
+				WHEN p.prices_include_tax THEN d.total_cost - ROUND(d.total_cost * 100 / (100 + d.tax_rate), 2)
+				ELSE ROUND(d.total_cost * d.tax_rate / 100, 2)
+			END
+		FROM purchase_orders_parts p
+		WHERE p.po_id = d.po_id AND d.po_id = $1`
+
+	_, err := r.db.ExecContext(ctx, lineTaxQuery, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate line taxes: %w", err)
+	}
+
+	// Calculate subtotal and tax from line items
 	subtotalQuery := `
-		SELECT COALESCE(SUM(total_cost), 0)
+		SELECT COALESCE(SUM(total_cost), 0), COALESCE(SUM(tax_amount), 0), COUNT(tax_code_id)
 		FROM purchase_order_details
 		WHERE po_id = $1`
 
-	var subtotal float64
-	err := r.db.QueryRowContext(ctx, subtotalQuery, id).Scan(&subtotal)
+	var lineTotal, lineTax float64
+	var taxedLines int
+	err = r.db.QueryRowContext(ctx, subtotalQuery, id).Scan(&lineTotal, &lineTax, &taxedLines)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate subtotal: %w", err)
 	}
 
-	// Get current PO to preserve tax, discount, and shipping
+	// Get current PO to preserve discount and shipping, and tax when no line is taxed
 	po, err := r.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get purchase order: %w", err)
 	}
 
 	// Calculate total
-	po.Subtotal = subtotal
-	po.CalculateTotals()
+	po.ApplyLineTotals(lineTotal, lineTax, taxedLines)
 
 	// Update the totals
 	updateQuery := `
 		UPDATE purchase_orders_parts 
-		SET subtotal = $2, total_amount = $3, updated_at = NOW()
+		SET subtotal = $2, tax_amount = $3, total_amount = $4, updated_at = NOW()
 		WHERE po_id = $1`
 
-	_, err = r.db.ExecContext(ctx, updateQuery, id, po.Subtotal, po.TotalAmount)
+	_, err = r.db.ExecContext(ctx, updateQuery, id, po.Subtotal, po.TaxAmount, po.TotalAmount)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update totals: %w", err)
 	}
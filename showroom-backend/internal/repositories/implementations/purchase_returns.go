@@ -0,0 +1,472 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// PurchaseReturnRepository implements interfaces.PurchaseReturnRepository
+type PurchaseReturnRepository struct {
+	db *sql.DB
+}
+
+// NewPurchaseReturnRepository creates a new purchase return repository
+func NewPurchaseReturnRepository(db *sql.DB) interfaces.PurchaseReturnRepository {
+	return &PurchaseReturnRepository{db: db}
+}
+
+// Create creates a new purchase return together with its line items
+func (r *PurchaseReturnRepository) Create(ctx context.Context, purchaseReturn *products.PurchaseReturn, details []products.PurchaseReturnDetail) (*products.PurchaseReturn, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i := range details {
+		details[i].UpdateTotalCost()
+	}
+	purchaseReturn.CalculateTotalValue(details)
+
+	if purchaseReturn.ReturnDate.IsZero() {
+		purchaseReturn.ReturnDate = time.Now()
+	}
+
+	query := `
+		INSERT INTO purchase_returns (
+			return_number, supplier_id, receipt_id, po_id, return_source,
+			return_date, return_status, shipment_status, total_return_value,
+			return_reason, return_notes, created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING return_id, created_at, updated_at`
+
+	err = tx.QueryRowContext(ctx, query,
+		purchaseReturn.ReturnNumber,
+		purchaseReturn.SupplierID,
+		purchaseReturn.ReceiptID,
+		purchaseReturn.POID,
+		purchaseReturn.ReturnSource,
+		purchaseReturn.ReturnDate,
+		purchaseReturn.ReturnStatus,
+		purchaseReturn.ShipmentStatus,
+		purchaseReturn.TotalReturnValue,
+		purchaseReturn.ReturnReason,
+		purchaseReturn.ReturnNotes,
+		purchaseReturn.CreatedBy,
+	).Scan(&purchaseReturn.ReturnID, &purchaseReturn.CreatedAt, &purchaseReturn.UpdatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create purchase return: %w", err)
+	}
+
+	detailQuery := `
+		INSERT INTO purchase_return_details (
+			return_id, product_id, receipt_detail_id, quantity_returned,
+			unit_cost, total_cost, return_reason
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING return_detail_id`
+
+	for i := range details {
+		details[i].ReturnID = purchaseReturn.ReturnID
+		err = tx.QueryRowContext(ctx, detailQuery,
+			details[i].ReturnID,
+			details[i].ProductID,
+			details[i].ReceiptDetailID,
+			details[i].QuantityReturned,
+			details[i].UnitCost,
+			details[i].TotalCost,
+			details[i].ReturnReason,
+		).Scan(&details[i].ReturnDetailID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create purchase return detail: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return purchaseReturn, nil
+}
+
+// GetByID retrieves a purchase return by ID
+func (r *PurchaseReturnRepository) GetByID(ctx context.Context, id int) (*products.PurchaseReturn, error) {
+	return r.getOne(ctx, "return_id = $1", id)
+}
+
+// GetByNumber retrieves a purchase return by return number
+func (r *PurchaseReturnRepository) GetByNumber(ctx context.Context, number string) (*products.PurchaseReturn, error) {
+	return r.getOne(ctx, "return_number = $1", number)
+}
+
+func (r *PurchaseReturnRepository) getOne(ctx context.Context, condition string, arg interface{}) (*products.PurchaseReturn, error) {
+	query := `
+		SELECT return_id, return_number, supplier_id, receipt_id, po_id,
+			   return_source, return_date, return_status, shipment_status,
+			   carrier_name, tracking_number, shipped_at, delivered_at,
+			   total_return_value, return_reason, return_notes, created_by,
+			   approved_by, approved_at, created_at, updated_at
+		FROM purchase_returns
+		WHERE ` + condition
+
+	purchaseReturn := &products.PurchaseReturn{}
+	err := r.db.QueryRowContext(ctx, query, arg).Scan(
+		&purchaseReturn.ReturnID,
+		&purchaseReturn.ReturnNumber,
+		&purchaseReturn.SupplierID,
+		&purchaseReturn.ReceiptID,
+		&purchaseReturn.POID,
+		&purchaseReturn.ReturnSource,
+		&purchaseReturn.ReturnDate,
+		&purchaseReturn.ReturnStatus,
+		&purchaseReturn.ShipmentStatus,
+		&purchaseReturn.CarrierName,
+		&purchaseReturn.TrackingNumber,
+		&purchaseReturn.ShippedAt,
+		&purchaseReturn.DeliveredAt,
+		&purchaseReturn.TotalReturnValue,
+		&purchaseReturn.ReturnReason,
+		&purchaseReturn.ReturnNotes,
+		&purchaseReturn.CreatedBy,
+		&purchaseReturn.ApprovedBy,
+		&purchaseReturn.ApprovedAt,
+		&purchaseReturn.CreatedAt,
+		&purchaseReturn.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("purchase return not found")
+		}
+		return nil, fmt.Errorf("failed to get purchase return: %w", err)
+	}
+
+	return purchaseReturn, nil
+}
+
+// GetDetails retrieves the line items of a purchase return
+func (r *PurchaseReturnRepository) GetDetails(ctx context.Context, returnID int) ([]products.PurchaseReturnDetail, error) {
+	query := `
+		SELECT return_detail_id, return_id, product_id, receipt_detail_id,
+			   quantity_returned, unit_cost, total_cost, return_reason
+		FROM purchase_return_details
+		WHERE return_id = $1
+		ORDER BY return_detail_id`
+
+	rows, err := r.db.QueryContext(ctx, query, returnID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query purchase return details: %w", err)
+	}
+	defer rows.Close()
+
+	var details []products.PurchaseReturnDetail
+	for rows.Next() {
+		var detail products.PurchaseReturnDetail
+		err := rows.Scan(
+			&detail.ReturnDetailID,
+			&detail.ReturnID,
+			&detail.ProductID,
+			&detail.ReceiptDetailID,
+			&detail.QuantityReturned,
+			&detail.UnitCost,
+			&detail.TotalCost,
+			&detail.ReturnReason,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan purchase return detail: %w", err)
+		}
+		details = append(details, detail)
+	}
+
+	return details, nil
+}
+
+// List retrieves purchase returns with pagination
+func (r *PurchaseReturnRepository) List(ctx context.Context, params *products.PurchaseReturnFilterParams) (*common.PaginatedResponse, error) {
+	baseQuery := `
+		FROM purchase_returns pr
+		LEFT JOIN suppliers s ON pr.supplier_id = s.supplier_id
+		WHERE 1=1`
+
+	args := []interface{}{}
+	whereConditions := []string{}
+	argIndex := 1
+
+	if params.SupplierID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pr.supplier_id = $%d", argIndex))
+		args = append(args, *params.SupplierID)
+		argIndex++
+	}
+
+	if params.ReceiptID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pr.receipt_id = $%d", argIndex))
+		args = append(args, *params.ReceiptID)
+		argIndex++
+	}
+
+	if params.ReturnSource != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pr.return_source = $%d", argIndex))
+		args = append(args, *params.ReturnSource)
+		argIndex++
+	}
+
+	if params.ReturnStatus != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pr.return_status = $%d", argIndex))
+		args = append(args, *params.ReturnStatus)
+		argIndex++
+	}
+
+	if params.ShipmentStatus != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pr.shipment_status = $%d", argIndex))
+		args = append(args, *params.ShipmentStatus)
+		argIndex++
+	}
+
+	if params.DateFrom != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pr.return_date >= $%d", argIndex))
+		args = append(args, *params.DateFrom)
+		argIndex++
+	}
+
+	if params.DateTo != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pr.return_date <= $%d", argIndex))
+		args = append(args, *params.DateTo)
+		argIndex++
+	}
+
+	if params.Search != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("(pr.return_number ILIKE $%d OR s.supplier_name ILIKE $%d OR pr.tracking_number ILIKE $%d)", argIndex, argIndex, argIndex))
+		args = append(args, "%"+params.Search+"%")
+		argIndex++
+	}
+
+	if len(whereConditions) > 0 {
+		baseQuery += " AND " + strings.Join(whereConditions, " AND ")
+	}
+
+	// Count query
+	countQuery := "SELECT COUNT(*) " + baseQuery
+	var total int64
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count purchase returns: %w", err)
+	}
+
+	// Calculate pagination
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	// Main query
+	selectFields := `
+		pr.return_id, pr.return_number, pr.supplier_id, COALESCE(s.supplier_name, ''),
+		pr.receipt_id, pr.return_source, pr.return_date, pr.return_status,
+		pr.shipment_status, pr.total_return_value, pr.created_at`
+
+	mainQuery := "SELECT " + selectFields + " " + baseQuery +
+		" ORDER BY pr.return_date DESC, pr.return_id DESC LIMIT $" + fmt.Sprintf("%d", argIndex) +
+		" OFFSET $" + fmt.Sprintf("%d", argIndex+1)
+
+	args = append(args, params.Limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, mainQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query purchase returns: %w", err)
+	}
+	defer rows.Close()
+
+	var returns []products.PurchaseReturnListItem
+	for rows.Next() {
+		var item products.PurchaseReturnListItem
+		err := rows.Scan(
+			&item.ReturnID,
+			&item.ReturnNumber,
+			&item.SupplierID,
+			&item.SupplierName,
+			&item.ReceiptID,
+			&item.ReturnSource,
+			&item.ReturnDate,
+			&item.ReturnStatus,
+			&item.ShipmentStatus,
+			&item.TotalReturnValue,
+			&item.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan purchase return: %w", err)
+		}
+		returns = append(returns, item)
+	}
+
+	totalPages := (total + int64(params.Limit) - 1) / int64(params.Limit)
+
+	return &common.PaginatedResponse{
+		Data:       returns,
+		Total:      int(total),
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: int(totalPages),
+		HasMore:    params.Page < int(totalPages),
+	}, nil
+}
+
+// Approve approves a draft purchase return in one transaction: the return movements are recorded,
+// and the supplier debit note is issued and applied to the supplier's outstanding invoices. Only
+// returns from stock reduce the stock quantity; rejected receipt lines never entered stock.
+func (r *PurchaseReturnRepository) Approve(ctx context.Context, id int, approvedBy int, movements []products.StockMovement, debitNote *products.SupplierDebitNote) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var source products.ReturnSource
+	err = tx.QueryRowContext(ctx, `
+		UPDATE purchase_returns
+		SET return_status = 'approved', approved_by = $1, approved_at = NOW(), updated_at = NOW()
+		WHERE return_id = $2 AND return_status = 'draft'
+		RETURNING return_source`, approvedBy, id).Scan(&source)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("purchase return not found or not in draft status")
+		}
+		return fmt.Errorf("failed to approve purchase return: %w", err)
+	}
+
+	// Goods returned from stock leave inventory at the cost they are carried at, whatever price
+	// the supplier credits for them
+	var stockValue *float64
+	if source == products.ReturnSourceStock {
+		var value float64
+		for i := range movements {
+			err := tx.QueryRowContext(ctx,
+				"SELECT cost_price FROM products_spare_parts WHERE product_id = $1 FOR UPDATE",
+				movements[i].ProductID).Scan(&movements[i].UnitCost)
+			if err != nil {
+				if err == sql.ErrNoRows {
+					return fmt.Errorf("product %d not found", movements[i].ProductID)
+				}
+				return fmt.Errorf("failed to get product cost: %w", err)
+			}
+			value += float64(movements[i].QuantityMoved) * movements[i].UnitCost
+		}
+		stockValue = &value
+	}
+
+	for i := range movements {
+		if err := applyStockMovement(ctx, tx, &movements[i], source == products.ReturnSourceStock); err != nil {
+			return fmt.Errorf("failed to create return movement for product %d: %w", movements[i].ProductID, err)
+		}
+	}
+
+	if err := insertDebitNote(ctx, tx, debitNote, stockValue); err != nil {
+		return err
+	}
+
+	if _, err := applyDebitNote(ctx, tx, debitNote.DebitNoteID, approvedBy); err != nil {
+		return fmt.Errorf("failed to apply debit note: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateStatus updates the status of a purchase return
+func (r *PurchaseReturnRepository) UpdateStatus(ctx context.Context, id int, status products.ReturnStatus) error {
+	query := `UPDATE purchase_returns SET return_status = $1, updated_at = NOW() WHERE return_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, status, id)
+	if err != nil {
+		return fmt.Errorf("failed to update purchase return status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("purchase return not found")
+	}
+
+	return nil
+}
+
+// UpdateShipment updates the shipment tracking fields of a purchase return
+func (r *PurchaseReturnRepository) UpdateShipment(ctx context.Context, id int, purchaseReturn *products.PurchaseReturn) error {
+	query := `
+		UPDATE purchase_returns
+		SET return_status = $1, shipment_status = $2, carrier_name = $3,
+			tracking_number = $4, shipped_at = $5, delivered_at = $6, updated_at = NOW()
+		WHERE return_id = $7`
+
+	_, err := r.db.ExecContext(ctx, query,
+		purchaseReturn.ReturnStatus,
+		purchaseReturn.ShipmentStatus,
+		purchaseReturn.CarrierName,
+		purchaseReturn.TrackingNumber,
+		purchaseReturn.ShippedAt,
+		purchaseReturn.DeliveredAt,
+		id,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update purchase return shipment: %w", err)
+	}
+
+	return nil
+}
+
+// GetReturnedQuantity gets the quantity already returned for a goods receipt line
+func (r *PurchaseReturnRepository) GetReturnedQuantity(ctx context.Context, receiptDetailID int) (int, error) {
+	query := `
+		SELECT COALESCE(SUM(prd.quantity_returned), 0)
+		FROM purchase_return_details prd
+		JOIN purchase_returns pr ON prd.return_id = pr.return_id
+		WHERE prd.receipt_detail_id = $1 AND pr.return_status != 'cancelled'`
+
+	var quantity int
+	err := r.db.QueryRowContext(ctx, query, receiptDetailID).Scan(&quantity)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get returned quantity: %w", err)
+	}
+
+	return quantity, nil
+}
+
+// GenerateNumber generates a new purchase return number
+func (r *PurchaseReturnRepository) GenerateNumber(ctx context.Context) (string, error) {
+	// Generate return number with format RTV-YYYYMMDD-XXXX
+	now := time.Now()
+	dateStr := now.Format("20060102")
+
+	query := `
+		SELECT COALESCE(MAX(
+			CAST(SUBSTRING(return_number FROM 'RTV-\d{8}-(\d+)') AS INTEGER)
+		), 0) + 1
+		FROM purchase_returns
+		WHERE return_number LIKE $1`
+
+	prefix := "RTV-" + dateStr + "-%"
+	var nextNumber int
+	err := r.db.QueryRowContext(ctx, query, prefix).Scan(&nextNumber)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate return number: %w", err)
+	}
+
+	return fmt.Sprintf("RTV-%s-%04d", dateStr, nextNumber), nil
+}
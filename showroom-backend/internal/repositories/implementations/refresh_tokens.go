@@ -0,0 +1,114 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+type refreshTokenRepository struct {
+	db *sql.DB
+}
+
+// NewRefreshTokenRepository creates a new refresh token repository
+func NewRefreshTokenRepository(db *sql.DB) interfaces.RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+// Create stores a new refresh token of a session
+func (r *refreshTokenRepository) Create(ctx context.Context, token *user.RefreshToken) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO user_refresh_tokens (session_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+		RETURNING token_id, issued_at`,
+		token.SessionID, token.TokenHash, token.ExpiresAt,
+	).Scan(&token.TokenID, &token.IssuedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// Rotate exchanges the refresh token with the given hash for next, which takes over its session
+// and expiry. Presenting a token that was already rotated revokes its session and returns
+// user.ErrRefreshTokenReused. check runs on the locked session and whether its user is active
+// before anything changes; its error leaves the token unrotated.
+func (r *refreshTokenRepository) Rotate(ctx context.Context, tokenHash string, next *user.RefreshToken, check func(session *user.UserSession, userActive bool) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var current user.RefreshToken
+	var session user.UserSession
+	var sessionActive, userActive bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT rt.token_id, rt.session_id, rt.issued_at, rt.expires_at, rt.rotated_at, COALESCE(s.is_active, FALSE),
+			   s.user_id, s.last_activity_at, u.is_active
+		FROM user_refresh_tokens rt
+		JOIN user_sessions s ON s.session_id = rt.session_id
+		JOIN users u ON u.user_id = s.user_id
+		WHERE rt.token_hash = $1
+		FOR UPDATE OF rt, s`, tokenHash,
+	).Scan(&current.TokenID, &current.SessionID, &current.IssuedAt, &current.ExpiresAt, &current.RotatedAt, &sessionActive,
+		&session.UserID, &session.LastActivityAt, &userActive)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return user.ErrRefreshTokenInvalid
+		}
+		return fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	if err := current.Check(sessionActive, time.Now()); err != nil {
+		if !errors.Is(err, user.ErrRefreshTokenReused) {
+			return err
+		}
+
+		_, revokeErr := tx.ExecContext(ctx, `
+			UPDATE user_sessions
+			SET logout_at = NOW(), is_active = FALSE
+			WHERE session_id = $1 AND is_active = TRUE`, current.SessionID)
+		if revokeErr != nil {
+			return fmt.Errorf("failed to revoke session: %w", revokeErr)
+		}
+		if commitErr := tx.Commit(); commitErr != nil {
+			return fmt.Errorf("failed to commit transaction: %w", commitErr)
+		}
+		return err
+	}
+
+	session.SessionID = current.SessionID
+	session.IsActive = sessionActive
+	if err := check(&session, userActive); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE user_refresh_tokens SET rotated_at = NOW() WHERE token_id = $1", current.TokenID); err != nil {
+		return fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	next.SessionID = current.SessionID
+	next.ExpiresAt = current.ExpiresAt
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO user_refresh_tokens (session_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+		RETURNING token_id, issued_at`,
+		next.SessionID, next.TokenHash, next.ExpiresAt,
+	).Scan(&next.TokenID, &next.IssuedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
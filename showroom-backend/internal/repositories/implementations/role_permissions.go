@@ -0,0 +1,91 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+type rolePermissionRepository struct {
+	db *sql.DB
+}
+
+// NewRolePermissionRepository creates a new role permission repository
+func NewRolePermissionRepository(db *sql.DB) interfaces.RolePermissionRepository {
+	return &rolePermissionRepository{db: db}
+}
+
+// GetByRole retrieves the permission codes granted to a role
+func (r *rolePermissionRepository) GetByRole(ctx context.Context, role string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT permission_code FROM role_permissions
+		WHERE role = $1
+		ORDER BY permission_code ASC`, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role permissions: %w", err)
+	}
+	defer rows.Close()
+
+	permissions := []string{}
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, fmt.Errorf("failed to scan role permission: %w", err)
+		}
+		permissions = append(permissions, code)
+	}
+
+	return permissions, rows.Err()
+}
+
+// ListAll retrieves the permission codes granted to every role, keyed by role
+func (r *rolePermissionRepository) ListAll(ctx context.Context) (map[string][]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT role, permission_code FROM role_permissions
+		ORDER BY role ASC, permission_code ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role permissions: %w", err)
+	}
+	defer rows.Close()
+
+	permissions := map[string][]string{}
+	for rows.Next() {
+		var role, code string
+		if err := rows.Scan(&role, &code); err != nil {
+			return nil, fmt.Errorf("failed to scan role permission: %w", err)
+		}
+		permissions[role] = append(permissions[role], code)
+	}
+
+	return permissions, rows.Err()
+}
+
+// ReplaceForRole replaces the permissions granted to a role
+func (r *rolePermissionRepository) ReplaceForRole(ctx context.Context, role string, permissions []string, grantedBy int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM role_permissions WHERE role = $1", role); err != nil {
+		return fmt.Errorf("failed to clear role permissions: %w", err)
+	}
+
+	for _, code := range permissions {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO role_permissions (role, permission_code, granted_by)
+			VALUES ($1, $2, $3)`, role, code, grantedBy)
+		if err != nil {
+			return fmt.Errorf("failed to grant permission %s: %w", code, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
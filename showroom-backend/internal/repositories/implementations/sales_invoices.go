@@ -0,0 +1,675 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// SalesInvoiceRepository implements interfaces.SalesInvoiceRepository
+type SalesInvoiceRepository struct {
+	db *sql.DB
+}
+
+// NewSalesInvoiceRepository creates a new sales invoice repository
+func NewSalesInvoiceRepository(db *sql.DB) interfaces.SalesInvoiceRepository {
+	return &SalesInvoiceRepository{db: db}
+}
+
+// salesInvoicePaidExpr is the amount of a sales invoice aliased si settled by posted customer receipts
+var salesInvoicePaidExpr = salesInvoicePaidAsOf("")
+
+// salesInvoicePaidAsOf returns the amount of a sales invoice aliased si settled by posted customer
+// receipts dated up to the date placeholder asOfArg, or by all of them when asOfArg is empty
+func salesInvoicePaidAsOf(asOfArg string) string {
+	condition := ""
+	if asOfArg != "" {
+		condition = " AND cr.receipt_date <= " + asOfArg
+	}
+	return `COALESCE((
+	SELECT SUM(ra.allocated_amount)
+	FROM customer_receipt_allocations ra
+	JOIN customer_receipts cr ON cr.receipt_id = ra.receipt_id
+	WHERE ra.invoice_id = si.invoice_id AND cr.receipt_status = 'posted'` + condition + `
+), 0)`
+}
+
+var salesInvoiceSelectFields = salesInvoiceSelectFieldsWith(salesInvoicePaidExpr)
+
+// salesInvoiceSelectFieldsWith returns the columns scanned by scanSalesInvoice with the paid
+// and outstanding amounts derived from paidExpr
+func salesInvoiceSelectFieldsWith(paidExpr string) string {
+	return `
+	si.invoice_id, si.invoice_number, si.customer_id, c.customer_name, c.tax_number,
+	c.address || ', ' || c.city, si.invoice_date, si.payment_type, si.due_date, si.prices_include_tax,
+	si.subtotal, si.tax_amount, si.total_amount, ` + paidExpr + `, si.total_amount - ` + paidExpr + `,
+	si.tax_invoice_number, si.invoice_status, si.notes, si.created_by,
+	si.voided_by, si.voided_at, si.created_at, si.updated_at`
+}
+
+const salesInvoiceLineSelectFields = `
+	l.line_id, l.invoice_id, l.product_id, p.product_code, p.product_name, l.quantity,
+	l.unit_price, l.discount_amount, l.tax_code_id, l.tax_rate, l.tax_base, l.tax_amount,
+	l.line_total, l.unit_cost`
+
+// Create issues a sales invoice: the goods are taken out of stock at their current cost
+// and the invoice is posted to the general ledger, all in one transaction. Credit sales
+// are checked against the customer's credit limit with the customer locked, so concurrent
+// sales cannot both pass the check.
+func (r *SalesInvoiceRepository) Create(ctx context.Context, invoice *products.SalesInvoice) (*products.SalesInvoice, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if invoice.PaymentType == products.SalesPaymentTypeCredit {
+		if err := checkCustomerCredit(ctx, tx, invoice.CustomerID, invoice.TotalAmount); err != nil {
+			return nil, err
+		}
+	}
+
+	// Lock the products in a fixed order so concurrent sales cannot oversell or deadlock
+	requested := make(map[int]int)
+	for _, line := range invoice.Lines {
+		requested[line.ProductID] += line.Quantity
+	}
+	productIDs := make([]int, 0, len(requested))
+	for id := range requested {
+		productIDs = append(productIDs, id)
+	}
+	sort.Ints(productIDs)
+
+	type productStock struct {
+		code, name string
+		stock      int
+		cost       float64
+	}
+	stocks := make(map[int]*productStock, len(productIDs))
+	for _, id := range productIDs {
+		stock := &productStock{}
+		err := tx.QueryRowContext(ctx, `
+			SELECT product_code, product_name, stock_quantity, cost_price
+			FROM products_spare_parts
+			WHERE product_id = $1
+			FOR UPDATE`, id).Scan(&stock.code, &stock.name, &stock.stock, &stock.cost)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, fmt.Errorf("product %d not found", id)
+			}
+			return nil, fmt.Errorf("failed to lock product: %w", err)
+		}
+		if stock.stock < requested[id] {
+			return nil, fmt.Errorf("insufficient stock for product %s: available %d, requested %d",
+				stock.code, stock.stock, requested[id])
+		}
+		stocks[id] = stock
+	}
+
+	for i := range invoice.Lines {
+		stock := stocks[invoice.Lines[i].ProductID]
+		invoice.Lines[i].ProductCode = stock.code
+		invoice.Lines[i].ProductName = stock.name
+		invoice.Lines[i].UnitCost = stock.cost
+	}
+
+	invoice.InvoiceNumber, err = generateSalesInvoiceNumber(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	invoice.InvoiceStatus = products.SalesInvoiceStatusIssued
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO sales_invoices (
+			invoice_number, customer_id, invoice_date, prices_include_tax, subtotal,
+			tax_amount, total_amount, invoice_status, notes, created_by,
+			payment_type, due_date
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING invoice_id, created_at, updated_at`,
+		invoice.InvoiceNumber,
+		invoice.CustomerID,
+		invoice.InvoiceDate,
+		invoice.PricesIncludeTax,
+		invoice.Subtotal,
+		invoice.TaxAmount,
+		invoice.TotalAmount,
+		invoice.InvoiceStatus,
+		invoice.Notes,
+		invoice.CreatedBy,
+		invoice.PaymentType,
+		invoice.DueDate,
+	).Scan(&invoice.InvoiceID, &invoice.CreatedAt, &invoice.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sales invoice: %w", err)
+	}
+	invoice.OutstandingAmount = invoice.TotalAmount
+
+	reason := "Sales invoice " + invoice.InvoiceNumber
+	for i := range invoice.Lines {
+		line := &invoice.Lines[i]
+		line.InvoiceID = invoice.InvoiceID
+
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO sales_invoice_lines (
+				invoice_id, product_id, quantity, unit_price, discount_amount, tax_code_id,
+				tax_rate, tax_base, tax_amount, line_total, unit_cost
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			RETURNING line_id`,
+			line.InvoiceID,
+			line.ProductID,
+			line.Quantity,
+			line.UnitPrice,
+			line.DiscountAmount,
+			line.TaxCodeID,
+			line.TaxRate,
+			line.TaxBase,
+			line.TaxAmount,
+			line.LineTotal,
+			line.UnitCost,
+		).Scan(&line.LineID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create sales invoice line: %w", err)
+		}
+
+		stock := stocks[line.ProductID]
+		err = moveSalesInvoiceStock(ctx, tx, line, stock.stock, -line.Quantity, products.MovementTypeOut,
+			invoice.InvoiceDate, invoice.CreatedBy, reason)
+		if err != nil {
+			return nil, err
+		}
+		stock.stock -= line.Quantity
+	}
+
+	if err := postJournalEntry(ctx, tx, products.SalesInvoiceJournal(invoice)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return invoice, nil
+}
+
+// checkCustomerCredit locks the customer and checks that a credit sale of amount stays within
+// their credit limit given the receivables still open
+func checkCustomerCredit(ctx context.Context, tx *sql.Tx, customerID int, amount float64) error {
+	var customerCode string
+	var creditLimit float64
+	err := tx.QueryRowContext(ctx,
+		"SELECT customer_code, credit_limit FROM customers WHERE customer_id = $1 FOR UPDATE",
+		customerID).Scan(&customerCode, &creditLimit)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("customer not found")
+		}
+		return fmt.Errorf("failed to lock customer: %w", err)
+	}
+
+	var outstanding float64
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(si.total_amount - `+salesInvoicePaidExpr+`), 0)
+		FROM sales_invoices si
+		WHERE si.customer_id = $1 AND si.invoice_status = 'issued'`, customerID).Scan(&outstanding)
+	if err != nil {
+		return fmt.Errorf("failed to get customer outstanding balance: %w", err)
+	}
+
+	return products.CheckCreditLimit(customerCode, creditLimit, outstanding, amount)
+}
+
+// moveSalesInvoiceStock changes the stock of a sold product and records the movement
+func moveSalesInvoiceStock(ctx context.Context, tx *sql.Tx, line *products.SalesInvoiceLine, quantityBefore, quantityMoved int,
+	movementType products.MovementType, movementDate time.Time, processedBy int, reason string) error {
+	quantityAfter := quantityBefore + quantityMoved
+
+	_, err := tx.ExecContext(ctx,
+		"UPDATE products_spare_parts SET stock_quantity = $2, updated_at = NOW() WHERE product_id = $1",
+		line.ProductID, quantityAfter)
+	if err != nil {
+		return fmt.Errorf("failed to update stock: %w", err)
+	}
+
+	quantity := quantityMoved
+	if quantity < 0 {
+		quantity = -quantity
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO stock_movements (
+			product_id, movement_type, reference_type, reference_id, quantity_before,
+			quantity_moved, quantity_after, unit_cost, total_value, movement_date,
+			processed_by, movement_reason
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		line.ProductID,
+		movementType,
+		products.ReferenceTypeSales,
+		line.InvoiceID,
+		quantityBefore,
+		quantityMoved,
+		quantityAfter,
+		line.UnitCost,
+		float64(quantity)*line.UnitCost,
+		movementDate,
+		processedBy,
+		reason,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create stock movement: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a sales invoice with its lines
+func (r *SalesInvoiceRepository) GetByID(ctx context.Context, id int) (*products.SalesInvoice, error) {
+	query := "SELECT " + salesInvoiceSelectFields + `
+		FROM sales_invoices si
+		JOIN customers c ON c.customer_id = si.customer_id
+		WHERE si.invoice_id = $1`
+
+	invoice, err := scanSalesInvoice(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("sales invoice not found")
+		}
+		return nil, fmt.Errorf("failed to get sales invoice: %w", err)
+	}
+
+	lines, err := r.queryLines(ctx, "l.invoice_id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	invoice.Lines = lines[id]
+
+	return invoice, nil
+}
+
+// List retrieves sales invoices with pagination
+func (r *SalesInvoiceRepository) List(ctx context.Context, params *products.SalesInvoiceFilterParams) (*common.PaginatedResponse, error) {
+	baseQuery := `FROM sales_invoices si JOIN customers c ON c.customer_id = si.customer_id WHERE 1=1`
+
+	args := []interface{}{}
+	whereConditions := []string{}
+	argIndex := 1
+
+	// Add filters
+	if params.CustomerID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("si.customer_id = $%d", argIndex))
+		args = append(args, *params.CustomerID)
+		argIndex++
+	}
+
+	if params.InvoiceStatus != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("si.invoice_status = $%d", argIndex))
+		args = append(args, *params.InvoiceStatus)
+		argIndex++
+	}
+
+	if params.PaymentType != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("si.payment_type = $%d", argIndex))
+		args = append(args, *params.PaymentType)
+		argIndex++
+	}
+
+	if params.HasOutstanding != nil {
+		if *params.HasOutstanding {
+			whereConditions = append(whereConditions, "si.invoice_status = 'issued' AND si.total_amount > "+salesInvoicePaidExpr)
+		} else {
+			whereConditions = append(whereConditions, "(si.invoice_status = 'void' OR si.total_amount <= "+salesInvoicePaidExpr+")")
+		}
+	}
+
+	if params.DateFrom != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("si.invoice_date >= $%d", argIndex))
+		args = append(args, *params.DateFrom)
+		argIndex++
+	}
+
+	if params.DateTo != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("si.invoice_date <= $%d", argIndex))
+		args = append(args, *params.DateTo)
+		argIndex++
+	}
+
+	if params.Search != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("(si.invoice_number ILIKE $%d OR si.tax_invoice_number ILIKE $%d OR c.customer_name ILIKE $%d)", argIndex, argIndex, argIndex))
+		args = append(args, "%"+params.Search+"%")
+		argIndex++
+	}
+
+	if len(whereConditions) > 0 {
+		baseQuery += " AND " + strings.Join(whereConditions, " AND ")
+	}
+
+	// Count query
+	countQuery := "SELECT COUNT(*) " + baseQuery
+	var total int64
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count sales invoices: %w", err)
+	}
+
+	// Calculate pagination
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	// Main query
+	mainQuery := "SELECT " + salesInvoiceSelectFields + " " + baseQuery +
+		" ORDER BY si.invoice_date DESC, si.invoice_id DESC LIMIT $" + fmt.Sprintf("%d", argIndex) +
+		" OFFSET $" + fmt.Sprintf("%d", argIndex+1)
+
+	args = append(args, params.Limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, mainQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sales invoices: %w", err)
+	}
+	defer rows.Close()
+
+	var invoices []products.SalesInvoice
+	for rows.Next() {
+		invoice, err := scanSalesInvoice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sales invoice: %w", err)
+		}
+		invoices = append(invoices, *invoice)
+	}
+
+	totalPages := (total + int64(params.Limit) - 1) / int64(params.Limit)
+
+	return &common.PaginatedResponse{
+		Data:       invoices,
+		Total:      int(total),
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: int(totalPages),
+		HasMore:    params.Page < int(totalPages),
+	}, nil
+}
+
+// SetTaxInvoiceNumber records the tax invoice number issued for a sales invoice
+func (r *SalesInvoiceRepository) SetTaxInvoiceNumber(ctx context.Context, id int, taxInvoiceNumber string) error {
+	query := `
+		UPDATE sales_invoices SET tax_invoice_number = $2, updated_at = NOW()
+		WHERE invoice_id = $1 AND invoice_status = 'issued'`
+
+	result, err := r.db.ExecContext(ctx, query, id, taxInvoiceNumber)
+	if err != nil {
+		return fmt.Errorf("failed to set tax invoice number: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("sales invoice not found or void")
+	}
+
+	return nil
+}
+
+// Void cancels an issued sales invoice, returning its goods to stock and reversing its journal
+func (r *SalesInvoiceRepository) Void(ctx context.Context, id int, voidedBy int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var invoiceNumber string
+	var paid float64
+	err = tx.QueryRowContext(ctx, `
+		SELECT si.invoice_number, `+salesInvoicePaidExpr+`
+		FROM sales_invoices si
+		WHERE si.invoice_id = $1
+		FOR UPDATE`, id).Scan(&invoiceNumber, &paid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("sales invoice not found")
+		}
+		return fmt.Errorf("failed to lock sales invoice: %w", err)
+	}
+	if paid > 0 {
+		return fmt.Errorf("sales invoice %s has receipts allocated, void them first", invoiceNumber)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE sales_invoices
+		SET invoice_status = 'void', voided_by = $2, voided_at = NOW(), updated_at = NOW()
+		WHERE invoice_id = $1 AND invoice_status = 'issued'
+		RETURNING invoice_number`, id, voidedBy).Scan(&invoiceNumber)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("sales invoice not found or already void")
+		}
+		return fmt.Errorf("failed to void sales invoice: %w", err)
+	}
+
+	lines, err := r.queryLinesWith(ctx, tx, "l.invoice_id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	reason := "Void of sales invoice " + invoiceNumber
+	for i := range lines[id] {
+		line := &lines[id][i]
+
+		var stock int
+		err := tx.QueryRowContext(ctx,
+			"SELECT stock_quantity FROM products_spare_parts WHERE product_id = $1 FOR UPDATE",
+			line.ProductID).Scan(&stock)
+		if err != nil {
+			return fmt.Errorf("failed to lock product: %w", err)
+		}
+
+		err = moveSalesInvoiceStock(ctx, tx, line, stock, line.Quantity, products.MovementTypeIn, now, voidedBy, reason)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := reverseSourceJournals(ctx, tx, products.JournalSourceSalesInvoice, id, now, &voidedBy); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ListForEFaktur retrieves the issued sales invoices carrying VAT dated in the month starting
+// at periodStart, with their lines
+func (r *SalesInvoiceRepository) ListForEFaktur(ctx context.Context, periodStart time.Time) ([]products.SalesInvoice, error) {
+	condition := "si.invoice_status = 'issued' AND si.tax_amount > 0 AND si.invoice_date BETWEEN $1 AND $2"
+	periodEnd := products.PeriodEnd(periodStart)
+
+	query := "SELECT " + salesInvoiceSelectFields + `
+		FROM sales_invoices si
+		JOIN customers c ON c.customer_id = si.customer_id
+		WHERE ` + condition + `
+		ORDER BY si.invoice_date, si.invoice_id`
+
+	rows, err := r.db.QueryContext(ctx, query, periodStart, periodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sales invoices: %w", err)
+	}
+	defer rows.Close()
+
+	var invoices []products.SalesInvoice
+	for rows.Next() {
+		invoice, err := scanSalesInvoice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sales invoice: %w", err)
+		}
+		invoices = append(invoices, *invoice)
+	}
+
+	lines, err := r.queryLines(ctx, "l.invoice_id IN (SELECT si.invoice_id FROM sales_invoices si WHERE "+condition+")",
+		periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+	for i := range invoices {
+		invoices[i].Lines = lines[invoices[i].InvoiceID]
+	}
+
+	return invoices, nil
+}
+
+// GetOpenInvoices retrieves the issued invoices of a customer dated up to asOf that still have an
+// outstanding balance counting only the receipts dated up to asOf, oldest due date first
+func (r *SalesInvoiceRepository) GetOpenInvoices(ctx context.Context, customerID int, asOf time.Time) ([]products.SalesInvoice, error) {
+	paidExpr := salesInvoicePaidAsOf("$2")
+	query := "SELECT " + salesInvoiceSelectFieldsWith(paidExpr) + `
+		FROM sales_invoices si
+		JOIN customers c ON c.customer_id = si.customer_id
+		WHERE si.customer_id = $1
+		AND si.invoice_status = 'issued'
+		AND si.invoice_date <= $2
+		AND si.total_amount > ` + paidExpr + `
+		ORDER BY si.due_date ASC, si.invoice_id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, customerID, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open sales invoices: %w", err)
+	}
+	defer rows.Close()
+
+	invoices := []products.SalesInvoice{}
+	for rows.Next() {
+		invoice, err := scanSalesInvoice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sales invoice: %w", err)
+		}
+		invoices = append(invoices, *invoice)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate open sales invoices: %w", err)
+	}
+
+	return invoices, nil
+}
+
+// GenerateNumber generates a unique sales invoice number
+func (r *SalesInvoiceRepository) GenerateNumber(ctx context.Context) (string, error) {
+	return generateSalesInvoiceNumber(ctx, r.db)
+}
+
+// generateSalesInvoiceNumber generates the next invoice number with format SI-YYYYMMDD-XXXX
+func generateSalesInvoiceNumber(ctx context.Context, db sqlRowQuerier) (string, error) {
+	dateStr := time.Now().Format("20060102")
+
+	query := `
+		SELECT COALESCE(MAX(
+			CAST(SUBSTRING(invoice_number FROM 'SI-\d{8}-(\d+)') AS INTEGER)
+		), 0) + 1
+		FROM sales_invoices
+		WHERE invoice_number LIKE $1`
+
+	var nextNumber int
+	err := db.QueryRowContext(ctx, query, "SI-"+dateStr+"-%").Scan(&nextNumber)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate sales invoice number: %w", err)
+	}
+
+	return fmt.Sprintf("SI-%s-%04d", dateStr, nextNumber), nil
+}
+
+func (r *SalesInvoiceRepository) queryLines(ctx context.Context, condition string, args ...interface{}) (map[int][]products.SalesInvoiceLine, error) {
+	return r.queryLinesWith(ctx, r.db, condition, args...)
+}
+
+// queryLinesWith loads sales invoice lines matching condition keyed by invoice ID
+func (r *SalesInvoiceRepository) queryLinesWith(ctx context.Context, db sqlQuerier, condition string, args ...interface{}) (map[int][]products.SalesInvoiceLine, error) {
+	query := "SELECT " + salesInvoiceLineSelectFields + `
+		FROM sales_invoice_lines l
+		JOIN products_spare_parts p ON p.product_id = l.product_id
+		WHERE ` + condition + `
+		ORDER BY l.invoice_id, l.line_id`
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sales invoice lines: %w", err)
+	}
+	defer rows.Close()
+
+	lines := make(map[int][]products.SalesInvoiceLine)
+	for rows.Next() {
+		var line products.SalesInvoiceLine
+		err := rows.Scan(
+			&line.LineID,
+			&line.InvoiceID,
+			&line.ProductID,
+			&line.ProductCode,
+			&line.ProductName,
+			&line.Quantity,
+			&line.UnitPrice,
+			&line.DiscountAmount,
+			&line.TaxCodeID,
+			&line.TaxRate,
+			&line.TaxBase,
+			&line.TaxAmount,
+			&line.LineTotal,
+			&line.UnitCost,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sales invoice line: %w", err)
+		}
+		lines[line.InvoiceID] = append(lines[line.InvoiceID], line)
+	}
+
+	return lines, nil
+}
+
+func scanSalesInvoice(row rowScanner) (*products.SalesInvoice, error) {
+	invoice := &products.SalesInvoice{}
+	err := row.Scan(
+		&invoice.InvoiceID,
+		&invoice.InvoiceNumber,
+		&invoice.CustomerID,
+		&invoice.CustomerName,
+		&invoice.CustomerTaxNumber,
+		&invoice.CustomerAddress,
+		&invoice.InvoiceDate,
+		&invoice.PaymentType,
+		&invoice.DueDate,
+		&invoice.PricesIncludeTax,
+		&invoice.Subtotal,
+		&invoice.TaxAmount,
+		&invoice.TotalAmount,
+		&invoice.PaidAmount,
+		&invoice.OutstandingAmount,
+		&invoice.TaxInvoiceNumber,
+		&invoice.InvoiceStatus,
+		&invoice.Notes,
+		&invoice.CreatedBy,
+		&invoice.VoidedBy,
+		&invoice.VoidedAt,
+		&invoice.CreatedAt,
+		&invoice.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
@@ -0,0 +1,88 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+type signingKeyRepository struct {
+	db *sql.DB
+}
+
+// NewSigningKeyRepository creates a new signing key repository
+func NewSigningKeyRepository(db *sql.DB) interfaces.SigningKeyRepository {
+	return &signingKeyRepository{db: db}
+}
+
+// ListUsable retrieves the keys that have not retired, the signing key first
+func (r *signingKeyRepository) ListUsable(ctx context.Context) ([]user.SigningKey, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT kid, algorithm, private_key, created_at, rotated_at, retires_at
+		FROM jwt_signing_keys
+		WHERE retires_at IS NULL OR retires_at > NOW()
+		ORDER BY rotated_at IS NULL DESC, created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list signing keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []user.SigningKey{}
+	for rows.Next() {
+		var key user.SigningKey
+		if err := rows.Scan(&key.KeyID, &key.Algorithm, &key.PrivateKey, &key.CreatedAt, &key.RotatedAt, &key.RetiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan signing key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Rotate stores a new signing key. The key it replaces stops signing and retires after
+// retireAfter.
+func (r *signingKeyRepository) Rotate(ctx context.Context, key *user.SigningKey, retireAfter time.Duration) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE jwt_signing_keys
+		SET rotated_at = NOW(), retires_at = NOW() + $1 * INTERVAL '1 second'
+		WHERE rotated_at IS NULL`, int64(retireAfter.Seconds()))
+	if err != nil {
+		return fmt.Errorf("failed to rotate signing keys: %w", err)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO jwt_signing_keys (kid, algorithm, private_key)
+		VALUES ($1, $2, $3)
+		RETURNING created_at`,
+		key.KeyID, key.Algorithm, key.PrivateKey,
+	).Scan(&key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create signing key: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteRetired deletes keys that retired before a moment
+func (r *signingKeyRepository) DeleteRetired(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM jwt_signing_keys WHERE retires_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete retired signing keys: %w", err)
+	}
+
+	return result.RowsAffected()
+}
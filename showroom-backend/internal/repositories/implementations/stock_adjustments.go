@@ -432,7 +432,7 @@ func (r *StockAdjustmentRepository) GetPendingApproval(ctx context.Context, para
 	}, nil
 }
 
-// Approve approves a stock adjustment and creates stock movement
+// Approve approves a stock adjustment and posts its cost impact to the general ledger
 func (r *StockAdjustmentRepository) Approve(ctx context.Context, id int, approvedBy int) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -442,12 +442,23 @@ func (r *StockAdjustmentRepository) Approve(ctx context.Context, id int, approve
 
 	// Update approval status
 	now := time.Now()
-	query := `UPDATE stock_adjustments SET approved_by = $1, approved_at = $2 WHERE adjustment_id = $3`
-	_, err = tx.ExecContext(ctx, query, approvedBy, now, id)
+	adjustment := &products.StockAdjustment{AdjustmentID: id}
+	query := `
+		UPDATE stock_adjustments SET approved_by = $1, approved_at = $2
+		WHERE adjustment_id = $3 AND approved_by IS NULL
+		RETURNING adjustment_type, cost_impact`
+	err = tx.QueryRowContext(ctx, query, approvedBy, now, id).Scan(&adjustment.AdjustmentType, &adjustment.CostImpact)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("stock adjustment not found or already approved")
+		}
 		return fmt.Errorf("failed to approve adjustment: %w", err)
 	}
 
+	if err := postJournalEntry(ctx, tx, products.StockAdjustmentJournal(adjustment, now, approvedBy)); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -39,6 +39,22 @@ func (r *StockMovementRepository) Create(ctx context.Context, movement *products
 		movement.QuantityAfter = movement.QuantityBefore - movement.QuantityMoved
 	}
 
+	if err := insertStockMovement(ctx, r.db, movement); err != nil {
+		return nil, err
+	}
+
+	// Update product stock quantity
+	updateStockQuery := `UPDATE products_spare_parts SET stock_quantity = $1 WHERE product_id = $2`
+	_, err = r.db.ExecContext(ctx, updateStockQuery, movement.QuantityAfter, movement.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update product stock: %w", err)
+	}
+
+	return movement, nil
+}
+
+// insertStockMovement writes the movement record without touching product stock
+func insertStockMovement(ctx context.Context, db sqlRowQuerier, movement *products.StockMovement) error {
 	// Calculate total value
 	movement.TotalValue = float64(movement.QuantityMoved) * movement.UnitCost
 
@@ -56,7 +72,7 @@ func (r *StockMovementRepository) Create(ctx context.Context, movement *products
 		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 		RETURNING movement_id, created_at`
 
-	err = r.db.QueryRowContext(ctx, query,
+	err := db.QueryRowContext(ctx, query,
 		movement.ProductID,
 		movement.MovementType,
 		movement.ReferenceType,
@@ -75,17 +91,43 @@ func (r *StockMovementRepository) Create(ctx context.Context, movement *products
 	).Scan(&movement.MovementID, &movement.CreatedAt)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to create stock movement: %w", err)
+		return fmt.Errorf("failed to create stock movement: %w", err)
 	}
 
-	// Update product stock quantity
-	updateStockQuery := `UPDATE products_spare_parts SET stock_quantity = $1 WHERE product_id = $2`
-	_, err = r.db.ExecContext(ctx, updateStockQuery, movement.QuantityAfter, movement.ProductID)
+	return nil
+}
+
+// applyStockMovement records a movement inside a document's transaction. The product row is locked so
+// the quantities before and after reflect concurrent movements; movements that do not change stock are
+// recorded at the current quantity.
+func applyStockMovement(ctx context.Context, tx *sql.Tx, movement *products.StockMovement, changesStock bool) error {
+	err := tx.QueryRowContext(ctx,
+		"SELECT stock_quantity FROM products_spare_parts WHERE product_id = $1 FOR UPDATE",
+		movement.ProductID).Scan(&movement.QuantityBefore)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update product stock: %w", err)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("product %d not found", movement.ProductID)
+		}
+		return fmt.Errorf("failed to get current stock: %w", err)
 	}
 
-	return movement, nil
+	movement.QuantityAfter = movement.QuantityBefore
+	if changesStock {
+		if movement.MovementType == products.MovementTypeIn {
+			movement.QuantityAfter += movement.QuantityMoved
+		} else {
+			movement.QuantityAfter -= movement.QuantityMoved
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"UPDATE products_spare_parts SET stock_quantity = $1 WHERE product_id = $2",
+			movement.QuantityAfter, movement.ProductID)
+		if err != nil {
+			return fmt.Errorf("failed to update product stock: %w", err)
+		}
+	}
+
+	return insertStockMovement(ctx, tx, movement)
 }
 
 // GetByID retrieves a stock movement by ID
@@ -367,6 +409,61 @@ func (r *StockMovementRepository) CreateMovementForAdjustment(ctx context.Contex
 	return err
 }
 
+// CreateMovementForReturn creates a stock movement for goods returned to a supplier.
+// Rejected receipt lines never entered stock, so they are recorded without changing the stock quantity.
+func (r *StockMovementRepository) CreateMovementForReturn(ctx context.Context, productID int, quantity int, unitCost float64, returnID int, processedBy int, fromStock bool) error {
+	movement := &products.StockMovement{
+		ProductID:      productID,
+		MovementType:   products.MovementTypeReturn,
+		ReferenceType:  products.ReferenceTypeReturn,
+		ReferenceID:    returnID,
+		QuantityMoved:  quantity,
+		UnitCost:       unitCost,
+		ProcessedBy:    processedBy,
+		MovementReason: stringPtr("Return to vendor"),
+	}
+
+	if fromStock {
+		_, err := r.Create(ctx, movement)
+		return err
+	}
+
+	currentStock, err := r.GetCurrentStock(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("failed to get current stock: %w", err)
+	}
+
+	movement.QuantityBefore = currentStock
+	movement.QuantityAfter = currentStock
+	movement.MovementReason = stringPtr("Return to vendor (rejected on receipt)")
+
+	return insertStockMovement(ctx, r.db, movement)
+}
+
+// CreateMovementForLandedCost records landed cost added to stock that was already received.
+// Only the valuation changes, so the stock quantity is left untouched.
+func (r *StockMovementRepository) CreateMovementForLandedCost(ctx context.Context, productID int, quantity int, unitLandedCost float64, voucherID int, processedBy int) error {
+	currentStock, err := r.GetCurrentStock(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("failed to get current stock: %w", err)
+	}
+
+	movement := &products.StockMovement{
+		ProductID:      productID,
+		MovementType:   products.MovementTypeAdjustment,
+		ReferenceType:  products.ReferenceTypeLandedCost,
+		ReferenceID:    voucherID,
+		QuantityBefore: currentStock,
+		QuantityMoved:  quantity,
+		QuantityAfter:  currentStock,
+		UnitCost:       unitLandedCost,
+		ProcessedBy:    processedBy,
+		MovementReason: stringPtr("Landed cost allocation"),
+	}
+
+	return insertStockMovement(ctx, r.db, movement)
+}
+
 // GetMovementHistory gets recent stock movements for a product
 func (r *StockMovementRepository) GetMovementHistory(ctx context.Context, productID int, limit int) ([]products.StockMovement, error) {
 	query := `
@@ -25,8 +25,8 @@ func NewSupplierRepository(db *sql.DB) interfaces.SupplierRepository {
 // Create creates a new supplier
 func (r *SupplierRepository) Create(ctx context.Context, supplier *master.Supplier) (*master.Supplier, error) {
 	query := `
-		INSERT INTO suppliers (supplier_code, supplier_name, supplier_type, phone, email, address, city, postal_code, tax_number, contact_person, bank_account, payment_terms, notes, created_by)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		INSERT INTO suppliers (supplier_code, supplier_name, supplier_type, phone, email, address, city, postal_code, tax_number, contact_person, bank_account, payment_terms, payment_term_id, notes, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 		RETURNING supplier_id, created_at, updated_at`
 
 	err := r.db.QueryRowContext(ctx, query,
@@ -42,6 +42,7 @@ func (r *SupplierRepository) Create(ctx context.Context, supplier *master.Suppli
 		supplier.ContactPerson,
 		supplier.BankAccount,
 		supplier.PaymentTerms,
+		supplier.PaymentTermID,
 		supplier.Notes,
 		supplier.CreatedBy,
 	).Scan(&supplier.SupplierID, &supplier.CreatedAt, &supplier.UpdatedAt)
@@ -57,7 +58,7 @@ func (r *SupplierRepository) Create(ctx context.Context, supplier *master.Suppli
 // GetByID retrieves a supplier by ID
 func (r *SupplierRepository) GetByID(ctx context.Context, id int) (*master.Supplier, error) {
 	query := `
-		SELECT supplier_id, supplier_code, supplier_name, supplier_type, phone, email, address, city, postal_code, tax_number, contact_person, bank_account, payment_terms, notes, is_active, created_at, updated_at, created_by
+		SELECT supplier_id, supplier_code, supplier_name, supplier_type, phone, email, address, city, postal_code, tax_number, contact_person, bank_account, payment_terms, payment_term_id, notes, is_active, created_at, updated_at, created_by
 		FROM suppliers
 		WHERE supplier_id = $1`
 
@@ -76,6 +77,7 @@ func (r *SupplierRepository) GetByID(ctx context.Context, id int) (*master.Suppl
 		&supplier.ContactPerson,
 		&supplier.BankAccount,
 		&supplier.PaymentTerms,
+		&supplier.PaymentTermID,
 		&supplier.Notes,
 		&supplier.IsActive,
 		&supplier.CreatedAt,
@@ -96,7 +98,7 @@ func (r *SupplierRepository) GetByID(ctx context.Context, id int) (*master.Suppl
 // GetByCode retrieves a supplier by code
 func (r *SupplierRepository) GetByCode(ctx context.Context, code string) (*master.Supplier, error) {
 	query := `
-		SELECT supplier_id, supplier_code, supplier_name, supplier_type, phone, email, address, city, postal_code, tax_number, contact_person, bank_account, payment_terms, notes, is_active, created_at, updated_at, created_by
+		SELECT supplier_id, supplier_code, supplier_name, supplier_type, phone, email, address, city, postal_code, tax_number, contact_person, bank_account, payment_terms, payment_term_id, notes, is_active, created_at, updated_at, created_by
 		FROM suppliers
 		WHERE supplier_code = $1`
 
@@ -115,6 +117,7 @@ func (r *SupplierRepository) GetByCode(ctx context.Context, code string) (*maste
 		&supplier.ContactPerson,
 		&supplier.BankAccount,
 		&supplier.PaymentTerms,
+		&supplier.PaymentTermID,
 		&supplier.Notes,
 		&supplier.IsActive,
 		&supplier.CreatedAt,
@@ -136,8 +139,8 @@ func (r *SupplierRepository) GetByCode(ctx context.Context, code string) (*maste
 func (r *SupplierRepository) Update(ctx context.Context, id int, supplier *master.Supplier) (*master.Supplier, error) {
 	query := `
 		UPDATE suppliers
-		SET supplier_name = $1, supplier_type = $2, phone = $3, email = $4, address = $5, city = $6, postal_code = $7, tax_number = $8, contact_person = $9, bank_account = $10, payment_terms = $11, notes = $12, is_active = $13, updated_at = NOW()
-		WHERE supplier_id = $14
+		SET supplier_name = $1, supplier_type = $2, phone = $3, email = $4, address = $5, city = $6, postal_code = $7, tax_number = $8, contact_person = $9, bank_account = $10, payment_terms = $11, payment_term_id = $12, notes = $13, is_active = $14, updated_at = NOW()
+		WHERE supplier_id = $15
 		RETURNING updated_at`
 
 	err := r.db.QueryRowContext(ctx, query,
@@ -152,6 +155,7 @@ func (r *SupplierRepository) Update(ctx context.Context, id int, supplier *maste
 		supplier.ContactPerson,
 		supplier.BankAccount,
 		supplier.PaymentTerms,
+		supplier.PaymentTermID,
 		supplier.Notes,
 		supplier.IsActive,
 		id,
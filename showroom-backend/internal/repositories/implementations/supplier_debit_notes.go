@@ -0,0 +1,458 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// SupplierDebitNoteRepository implements interfaces.SupplierDebitNoteRepository
+type SupplierDebitNoteRepository struct {
+	db *sql.DB
+}
+
+// NewSupplierDebitNoteRepository creates a new supplier debit note repository
+func NewSupplierDebitNoteRepository(db *sql.DB) interfaces.SupplierDebitNoteRepository {
+	return &SupplierDebitNoteRepository{db: db}
+}
+
+// Create creates a new supplier debit note and books it against accounts payable
+func (r *SupplierDebitNoteRepository) Create(ctx context.Context, debitNote *products.SupplierDebitNote) (*products.SupplierDebitNote, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertDebitNote(ctx, tx, debitNote, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return debitNote, nil
+}
+
+// insertDebitNote records a debit note and posts its journal. Debit notes for rejected receipt lines
+// clear goods received not invoiced; all others take the goods out of inventory, at stockValue when
+// the returned goods' carrying cost is known.
+func insertDebitNote(ctx context.Context, tx *sql.Tx, debitNote *products.SupplierDebitNote, stockValue *float64) error {
+	if debitNote.DebitNoteDate.IsZero() {
+		debitNote.DebitNoteDate = time.Now()
+	}
+	debitNote.UpdateStatus()
+
+	query := `
+		INSERT INTO supplier_debit_notes (
+			debit_note_number, supplier_id, return_id, debit_note_date,
+			debit_amount, applied_amount, debit_note_status, notes, created_by,
+			currency_code, exchange_rate
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING debit_note_id, created_at, updated_at`
+
+	err := tx.QueryRowContext(ctx, query,
+		debitNote.DebitNoteNumber,
+		debitNote.SupplierID,
+		debitNote.ReturnID,
+		debitNote.DebitNoteDate,
+		debitNote.DebitAmount,
+		debitNote.AppliedAmount,
+		debitNote.DebitNoteStatus,
+		debitNote.Notes,
+		debitNote.CreatedBy,
+		debitNote.CurrencyCode,
+		debitNote.ExchangeRate,
+	).Scan(&debitNote.DebitNoteID, &debitNote.CreatedAt, &debitNote.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create supplier debit note: %w", err)
+	}
+
+	source := products.ReturnSourceStock
+	if debitNote.ReturnID != nil {
+		err = tx.QueryRowContext(ctx, "SELECT return_source FROM purchase_returns WHERE return_id = $1", *debitNote.ReturnID).Scan(&source)
+		if err != nil {
+			return fmt.Errorf("failed to get purchase return source: %w", err)
+		}
+	}
+
+	return postJournalEntry(ctx, tx, products.SupplierDebitNoteJournal(debitNote, source, stockValue))
+}
+
+// GetByID retrieves a supplier debit note by ID
+func (r *SupplierDebitNoteRepository) GetByID(ctx context.Context, id int) (*products.SupplierDebitNote, error) {
+	return r.getOne(ctx, "debit_note_id = $1", id)
+}
+
+// GetByReturnID retrieves the supplier debit note issued for a purchase return
+func (r *SupplierDebitNoteRepository) GetByReturnID(ctx context.Context, returnID int) (*products.SupplierDebitNote, error) {
+	return r.getOne(ctx, "return_id = $1", returnID)
+}
+
+func (r *SupplierDebitNoteRepository) getOne(ctx context.Context, condition string, arg interface{}) (*products.SupplierDebitNote, error) {
+	query := `
+		SELECT debit_note_id, debit_note_number, supplier_id, return_id,
+			   debit_note_date, currency_code, exchange_rate, debit_amount, applied_amount, debit_note_status,
+			   notes, created_by, created_at, updated_at
+		FROM supplier_debit_notes
+		WHERE ` + condition
+
+	debitNote := &products.SupplierDebitNote{}
+	err := r.db.QueryRowContext(ctx, query, arg).Scan(
+		&debitNote.DebitNoteID,
+		&debitNote.DebitNoteNumber,
+		&debitNote.SupplierID,
+		&debitNote.ReturnID,
+		&debitNote.DebitNoteDate,
+		&debitNote.CurrencyCode,
+		&debitNote.ExchangeRate,
+		&debitNote.DebitAmount,
+		&debitNote.AppliedAmount,
+		&debitNote.DebitNoteStatus,
+		&debitNote.Notes,
+		&debitNote.CreatedBy,
+		&debitNote.CreatedAt,
+		&debitNote.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("supplier debit note not found")
+		}
+		return nil, fmt.Errorf("failed to get supplier debit note: %w", err)
+	}
+
+	return debitNote, nil
+}
+
+// List retrieves supplier debit notes with pagination
+func (r *SupplierDebitNoteRepository) List(ctx context.Context, params *products.SupplierDebitNoteFilterParams) (*common.PaginatedResponse, error) {
+	baseQuery := `
+		FROM supplier_debit_notes dn
+		WHERE 1=1`
+
+	args := []interface{}{}
+	whereConditions := []string{}
+	argIndex := 1
+
+	if params.SupplierID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("dn.supplier_id = $%d", argIndex))
+		args = append(args, *params.SupplierID)
+		argIndex++
+	}
+
+	if params.ReturnID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("dn.return_id = $%d", argIndex))
+		args = append(args, *params.ReturnID)
+		argIndex++
+	}
+
+	if params.DebitNoteStatus != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("dn.debit_note_status = $%d", argIndex))
+		args = append(args, *params.DebitNoteStatus)
+		argIndex++
+	}
+
+	if params.DateFrom != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("dn.debit_note_date >= $%d", argIndex))
+		args = append(args, *params.DateFrom)
+		argIndex++
+	}
+
+	if params.DateTo != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("dn.debit_note_date <= $%d", argIndex))
+		args = append(args, *params.DateTo)
+		argIndex++
+	}
+
+	if len(whereConditions) > 0 {
+		baseQuery += " AND " + strings.Join(whereConditions, " AND ")
+	}
+
+	// Count query
+	countQuery := "SELECT COUNT(*) " + baseQuery
+	var total int64
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count supplier debit notes: %w", err)
+	}
+
+	// Calculate pagination
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	// Main query
+	selectFields := `
+		dn.debit_note_id, dn.debit_note_number, dn.supplier_id, dn.return_id,
+		dn.debit_note_date, dn.currency_code, dn.exchange_rate, dn.debit_amount, dn.applied_amount, dn.debit_note_status,
+		dn.notes, dn.created_by, dn.created_at, dn.updated_at`
+
+	mainQuery := "SELECT " + selectFields + " " + baseQuery +
+		" ORDER BY dn.debit_note_date DESC, dn.debit_note_id DESC LIMIT $" + fmt.Sprintf("%d", argIndex) +
+		" OFFSET $" + fmt.Sprintf("%d", argIndex+1)
+
+	args = append(args, params.Limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, mainQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query supplier debit notes: %w", err)
+	}
+	defer rows.Close()
+
+	var debitNotes []products.SupplierDebitNote
+	for rows.Next() {
+		var debitNote products.SupplierDebitNote
+		err := rows.Scan(
+			&debitNote.DebitNoteID,
+			&debitNote.DebitNoteNumber,
+			&debitNote.SupplierID,
+			&debitNote.ReturnID,
+			&debitNote.DebitNoteDate,
+			&debitNote.CurrencyCode,
+			&debitNote.ExchangeRate,
+			&debitNote.DebitAmount,
+			&debitNote.AppliedAmount,
+			&debitNote.DebitNoteStatus,
+			&debitNote.Notes,
+			&debitNote.CreatedBy,
+			&debitNote.CreatedAt,
+			&debitNote.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan supplier debit note: %w", err)
+		}
+		debitNotes = append(debitNotes, debitNote)
+	}
+
+	totalPages := (total + int64(params.Limit) - 1) / int64(params.Limit)
+
+	return &common.PaginatedResponse{
+		Data:       debitNotes,
+		Total:      int(total),
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: int(totalPages),
+		HasMore:    params.Page < int(totalPages),
+	}, nil
+}
+
+// GetApplications retrieves the invoice applications of a debit note
+func (r *SupplierDebitNoteRepository) GetApplications(ctx context.Context, debitNoteID int) ([]products.SupplierDebitNoteApplication, error) {
+	query := `
+		SELECT application_id, debit_note_id, invoice_id, applied_amount, applied_at, applied_by
+		FROM supplier_debit_note_applications
+		WHERE debit_note_id = $1
+		ORDER BY application_id`
+
+	rows, err := r.db.QueryContext(ctx, query, debitNoteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query debit note applications: %w", err)
+	}
+	defer rows.Close()
+
+	var applications []products.SupplierDebitNoteApplication
+	for rows.Next() {
+		var application products.SupplierDebitNoteApplication
+		err := rows.Scan(
+			&application.ApplicationID,
+			&application.DebitNoteID,
+			&application.InvoiceID,
+			&application.AppliedAmount,
+			&application.AppliedAt,
+			&application.AppliedBy,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan debit note application: %w", err)
+		}
+		applications = append(applications, application)
+	}
+
+	return applications, nil
+}
+
+// ApplyToOutstanding applies the unapplied balance of a debit note against the supplier's
+// open invoices, starting with invoices of the returned purchase order and then by due date
+func (r *SupplierDebitNoteRepository) ApplyToOutstanding(ctx context.Context, id int, appliedBy int) ([]products.SupplierDebitNoteApplication, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	applications, err := applyDebitNote(ctx, tx, id, appliedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return applications, nil
+}
+
+// applyDebitNote credits the unapplied balance of a locked debit note to the supplier's open invoices
+// and books the realized exchange difference
+func applyDebitNote(ctx context.Context, tx *sql.Tx, id int, appliedBy int) ([]products.SupplierDebitNoteApplication, error) {
+	debitNote := &products.SupplierDebitNote{}
+	err := tx.QueryRowContext(ctx, `
+		SELECT debit_note_id, debit_note_number, supplier_id, return_id, currency_code, exchange_rate,
+			   debit_amount, applied_amount, debit_note_status
+		FROM supplier_debit_notes
+		WHERE debit_note_id = $1
+		FOR UPDATE`, id).Scan(
+		&debitNote.DebitNoteID,
+		&debitNote.DebitNoteNumber,
+		&debitNote.SupplierID,
+		&debitNote.ReturnID,
+		&debitNote.CurrencyCode,
+		&debitNote.ExchangeRate,
+		&debitNote.DebitAmount,
+		&debitNote.AppliedAmount,
+		&debitNote.DebitNoteStatus,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("supplier debit note not found")
+		}
+		return nil, fmt.Errorf("failed to get supplier debit note: %w", err)
+	}
+
+	if debitNote.DebitNoteStatus == products.DebitNoteStatusCancelled {
+		return nil, fmt.Errorf("cannot apply a cancelled debit note")
+	}
+
+	remaining := debitNote.GetRemainingAmount()
+	if remaining <= 0 {
+		return []products.SupplierDebitNoteApplication{}, nil
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT si.invoice_id, si.exchange_rate, `+supplierInvoiceOutstandingExpr+`
+		FROM supplier_invoices si`+supplierInvoiceSettlementJoin+`
+		WHERE si.supplier_id = $1
+		AND si.currency_code = $3
+		AND si.invoice_status NOT IN ('paid', 'disputed')
+		AND `+supplierInvoiceOutstandingExpr+` > 0
+		ORDER BY CASE WHEN si.po_id = (SELECT po_id FROM purchase_returns WHERE return_id = $2) THEN 0 ELSE 1 END,
+			si.due_date ASC, si.invoice_id ASC
+		FOR UPDATE OF si`, debitNote.SupplierID, debitNote.ReturnID, debitNote.CurrencyCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outstanding invoices: %w", err)
+	}
+
+	type openInvoice struct {
+		invoiceID    int
+		exchangeRate float64
+		outstanding  float64
+	}
+	var invoices []openInvoice
+	for rows.Next() {
+		var invoice openInvoice
+		if err := rows.Scan(&invoice.invoiceID, &invoice.exchangeRate, &invoice.outstanding); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan outstanding invoice: %w", err)
+		}
+		invoices = append(invoices, invoice)
+	}
+	rows.Close()
+
+	var difference float64
+	applications := []products.SupplierDebitNoteApplication{}
+	for _, invoice := range invoices {
+		if remaining <= 0 {
+			break
+		}
+
+		amount := math.Min(remaining, invoice.outstanding)
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE supplier_invoices
+			SET credit_amount = credit_amount + $1, updated_at = NOW()
+			WHERE invoice_id = $2`, amount, invoice.invoiceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply credit to supplier invoice: %w", err)
+		}
+
+		if _, err := refreshSupplierInvoiceStatuses(ctx, tx, "i.invoice_id = $1", invoice.invoiceID); err != nil {
+			return nil, err
+		}
+
+		application := products.SupplierDebitNoteApplication{
+			DebitNoteID:   id,
+			InvoiceID:     invoice.invoiceID,
+			AppliedAmount: amount,
+			AppliedBy:     appliedBy,
+		}
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO supplier_debit_note_applications (debit_note_id, invoice_id, applied_amount, applied_by)
+			VALUES ($1, $2, $3, $4)
+			RETURNING application_id, applied_at`,
+			application.DebitNoteID,
+			application.InvoiceID,
+			application.AppliedAmount,
+			application.AppliedBy,
+		).Scan(&application.ApplicationID, &application.AppliedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record debit note application: %w", err)
+		}
+
+		applications = append(applications, application)
+		debitNote.AppliedAmount += amount
+		remaining -= amount
+		difference += products.ExchangeDifference(amount, invoice.exchangeRate, debitNote.ExchangeRate)
+	}
+
+	debitNote.UpdateStatus()
+	_, err = tx.ExecContext(ctx, `
+		UPDATE supplier_debit_notes
+		SET applied_amount = $1, debit_note_status = $2, updated_at = NOW()
+		WHERE debit_note_id = $3`, debitNote.AppliedAmount, debitNote.DebitNoteStatus, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update supplier debit note: %w", err)
+	}
+
+	if err := postJournalEntry(ctx, tx, products.DebitNoteApplicationJournal(debitNote, difference, time.Now(), appliedBy)); err != nil {
+		return nil, err
+	}
+
+	return applications, nil
+}
+
+// GenerateNumber generates a new debit note number
+func (r *SupplierDebitNoteRepository) GenerateNumber(ctx context.Context) (string, error) {
+	// Generate debit note number with format DN-YYYYMMDD-XXXX
+	now := time.Now()
+	dateStr := now.Format("20060102")
+
+	query := `
+		SELECT COALESCE(MAX(
+			CAST(SUBSTRING(debit_note_number FROM 'DN-\d{8}-(\d+)') AS INTEGER)
+		), 0) + 1
+		FROM supplier_debit_notes
+		WHERE debit_note_number LIKE $1`
+
+	prefix := "DN-" + dateStr + "-%"
+	var nextNumber int
+	err := r.db.QueryRowContext(ctx, query, prefix).Scan(&nextNumber)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate debit note number: %w", err)
+	}
+
+	return fmt.Sprintf("DN-%s-%04d", dateStr, nextNumber), nil
+}
@@ -0,0 +1,882 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// supplierInvoiceSettlementJoin derives the paid and discounted amounts of an invoice aliased si
+// from the allocations of posted payment vouchers
+const supplierInvoiceSettlementJoin = `
+	LEFT JOIN LATERAL (
+		SELECT COALESCE(SUM(pa.allocated_amount), 0) AS paid_amount,
+			   COALESCE(SUM(pa.discount_amount), 0) AS discount_taken
+		FROM supplier_payment_allocations pa
+		JOIN supplier_payment_vouchers pv ON pv.voucher_id = pa.voucher_id
+		WHERE pa.invoice_id = si.invoice_id AND pv.voucher_status = 'posted'
+	) settled ON TRUE`
+
+// supplierInvoiceOutstandingExpr is the outstanding amount of an invoice aliased si joined with supplierInvoiceSettlementJoin
+const supplierInvoiceOutstandingExpr = `GREATEST(si.invoice_amount - settled.paid_amount - settled.discount_taken - si.credit_amount, 0)`
+
+const supplierInvoiceSelectFields = `
+	si.invoice_id, si.supplier_id, si.po_id, si.invoice_number, si.invoice_date,
+	si.due_date, si.currency_code, si.exchange_rate, si.invoice_amount, si.tax_amount, si.tax_invoice_number,
+	si.payment_term_id, si.discount_percent, si.discount_due_date, settled.paid_amount, settled.discount_taken,
+	si.credit_amount, ` + supplierInvoiceOutstandingExpr + `, si.invoice_status,
+	si.penalty_amount, si.notes, si.created_by, si.created_at, si.updated_at`
+
+// sqlExecer is satisfied by both *sql.DB and *sql.Tx
+type sqlExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// sqlQuerier is satisfied by both *sql.DB and *sql.Tx
+type sqlQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// matchedReceiptValue returns the base currency value of the processed receipts of the invoice's purchase
+// order that carry its invoice number, the amount the invoice clears from goods received not invoiced.
+// Rejected quantities count too, as the debit notes raised for them clear their share. It returns nil
+// when no receipt is matched.
+func matchedReceiptValue(ctx context.Context, db sqlQuerier, invoice *products.SupplierInvoice) (*float64, error) {
+	if invoice.POID == nil {
+		return nil, nil
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT gr.exchange_rate, d.quantity_accepted + d.quantity_rejected, d.unit_cost
+		FROM goods_receipts gr
+		JOIN goods_receipt_details d ON d.receipt_id = gr.receipt_id
+		WHERE gr.po_id = $1 AND gr.supplier_invoice_number = $2 AND gr.receipt_status != 'partial'`,
+		*invoice.POID, invoice.InvoiceNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query matched goods receipts: %w", err)
+	}
+	defer rows.Close()
+
+	matched := false
+	var value float64
+	for rows.Next() {
+		var receipt products.GoodsReceipt
+		var quantity int
+		var unitCost float64
+		if err := rows.Scan(&receipt.ExchangeRate, &quantity, &unitCost); err != nil {
+			return nil, fmt.Errorf("failed to scan matched goods receipt: %w", err)
+		}
+		matched = true
+		value += float64(quantity) * receipt.BaseUnitCost(unitCost)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read matched goods receipts: %w", err)
+	}
+
+	if !matched {
+		return nil, nil
+	}
+	return &value, nil
+}
+
+// refreshSupplierInvoiceStatuses re-derives the status of the invoices matching the condition on alias i
+// from their settlements and due dates. Disputed invoices are left untouched.
+func refreshSupplierInvoiceStatuses(ctx context.Context, db sqlExecer, condition string, args ...interface{}) (int64, error) {
+	query := `
+		UPDATE supplier_invoices si
+		SET invoice_status = CASE
+				WHEN si.invoice_amount - s.settled_amount - si.credit_amount <= 0 THEN 'paid'
+				WHEN si.due_date < NOW() THEN 'overdue'
+				WHEN s.settled_amount + si.credit_amount > 0 THEN 'partial'
+				ELSE 'pending'
+			END,
+			updated_at = NOW()
+		FROM (
+			SELECT i.invoice_id,
+				   COALESCE(SUM(pa.allocated_amount + pa.discount_amount) FILTER (WHERE pv.voucher_status = 'posted'), 0) AS settled_amount
+			FROM supplier_invoices i
+			LEFT JOIN supplier_payment_allocations pa ON pa.invoice_id = i.invoice_id
+			LEFT JOIN supplier_payment_vouchers pv ON pv.voucher_id = pa.voucher_id
+			WHERE ` + condition + `
+			GROUP BY i.invoice_id
+		) s
+		WHERE si.invoice_id = s.invoice_id
+		AND si.invoice_status != 'disputed'
+		AND si.invoice_status != CASE
+				WHEN si.invoice_amount - s.settled_amount - si.credit_amount <= 0 THEN 'paid'
+				WHEN si.due_date < NOW() THEN 'overdue'
+				WHEN s.settled_amount + si.credit_amount > 0 THEN 'partial'
+				ELSE 'pending'
+			END`
+
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to refresh supplier invoice status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// getSupplierInvoices loads invoices with their derived amounts keyed by invoice ID.
+// Pass a transaction and forUpdate to lock the invoices while allocating against them.
+func getSupplierInvoices(ctx context.Context, db sqlQuerier, ids []int, forUpdate bool) (map[int]*products.SupplierInvoice, error) {
+	invoices := make(map[int]*products.SupplierInvoice, len(ids))
+	if len(ids) == 0 {
+		return invoices, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+
+	query := "SELECT " + supplierInvoiceSelectFields + `
+		FROM supplier_invoices si` + supplierInvoiceSettlementJoin + `
+		WHERE si.invoice_id IN (` + strings.Join(placeholders, ", ") + `)`
+	if forUpdate {
+		query += " FOR UPDATE OF si"
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query supplier invoices: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	for rows.Next() {
+		invoice, err := scanSupplierInvoice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan supplier invoice: %w", err)
+		}
+		invoice.UpdateDaysOverdue(now)
+		invoices[invoice.InvoiceID] = invoice
+	}
+
+	return invoices, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSupplierInvoice(row rowScanner) (*products.SupplierInvoice, error) {
+	invoice := &products.SupplierInvoice{}
+	err := row.Scan(
+		&invoice.InvoiceID,
+		&invoice.SupplierID,
+		&invoice.POID,
+		&invoice.InvoiceNumber,
+		&invoice.InvoiceDate,
+		&invoice.DueDate,
+		&invoice.CurrencyCode,
+		&invoice.ExchangeRate,
+		&invoice.InvoiceAmount,
+		&invoice.TaxAmount,
+		&invoice.TaxInvoiceNumber,
+		&invoice.PaymentTermID,
+		&invoice.DiscountPercent,
+		&invoice.DiscountDueDate,
+		&invoice.PaidAmount,
+		&invoice.DiscountTaken,
+		&invoice.CreditAmount,
+		&invoice.OutstandingAmount,
+		&invoice.InvoiceStatus,
+		&invoice.PenaltyAmount,
+		&invoice.Notes,
+		&invoice.CreatedBy,
+		&invoice.CreatedAt,
+		&invoice.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// SupplierInvoiceRepository implements interfaces.SupplierInvoiceRepository
+type SupplierInvoiceRepository struct {
+	db *sql.DB
+}
+
+// NewSupplierInvoiceRepository creates a new supplier invoice repository
+func NewSupplierInvoiceRepository(db *sql.DB) interfaces.SupplierInvoiceRepository {
+	return &SupplierInvoiceRepository{db: db}
+}
+
+// Create creates a new supplier invoice and books it to accounts payable
+func (r *SupplierInvoiceRepository) Create(ctx context.Context, invoice *products.SupplierInvoice) (*products.SupplierInvoice, error) {
+	invoice.UpdateStatus(time.Now())
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO supplier_invoices (
+			supplier_id, po_id, invoice_number, invoice_date, due_date, invoice_amount,
+			tax_amount, tax_invoice_number, payment_term_id, discount_percent, discount_due_date,
+			invoice_status, notes, created_by, currency_code, exchange_rate
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		RETURNING invoice_id, created_at, updated_at`
+
+	err = tx.QueryRowContext(ctx, query,
+		invoice.SupplierID,
+		invoice.POID,
+		invoice.InvoiceNumber,
+		invoice.InvoiceDate,
+		invoice.DueDate,
+		invoice.InvoiceAmount,
+		invoice.TaxAmount,
+		invoice.TaxInvoiceNumber,
+		invoice.PaymentTermID,
+		invoice.DiscountPercent,
+		invoice.DiscountDueDate,
+		invoice.InvoiceStatus,
+		invoice.Notes,
+		invoice.CreatedBy,
+		invoice.CurrencyCode,
+		invoice.ExchangeRate,
+	).Scan(&invoice.InvoiceID, &invoice.CreatedAt, &invoice.UpdatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create supplier invoice: %w", err)
+	}
+
+	receiptValue, err := matchedReceiptValue(ctx, tx, invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := postJournalEntry(ctx, tx, products.SupplierInvoiceJournal(invoice, receiptValue, &invoice.CreatedBy)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return invoice, nil
+}
+
+// GetByID retrieves a supplier invoice by ID with its derived amounts
+func (r *SupplierInvoiceRepository) GetByID(ctx context.Context, id int) (*products.SupplierInvoice, error) {
+	query := "SELECT " + supplierInvoiceSelectFields + `
+		FROM supplier_invoices si` + supplierInvoiceSettlementJoin + `
+		WHERE si.invoice_id = $1`
+
+	invoice, err := scanSupplierInvoice(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("supplier invoice not found")
+		}
+		return nil, fmt.Errorf("failed to get supplier invoice: %w", err)
+	}
+
+	invoice.UpdateDaysOverdue(time.Now())
+	return invoice, nil
+}
+
+// GetByIDs retrieves supplier invoices keyed by invoice ID
+func (r *SupplierInvoiceRepository) GetByIDs(ctx context.Context, ids []int) (map[int]*products.SupplierInvoice, error) {
+	return getSupplierInvoices(ctx, r.db, ids, false)
+}
+
+// Update updates a supplier invoice and re-derives its status. A changed number, amount, tax, date or exchange
+// rate rebooks the invoice: its journal entries are reversed and it is posted again on the new invoice date.
+func (r *SupplierInvoiceRepository) Update(ctx context.Context, id int, invoice *products.SupplierInvoice) (*products.SupplierInvoice, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var bookedNumber string
+	var bookedAmount, bookedTax, bookedRate float64
+	var bookedDate time.Time
+	var poID *int
+	err = tx.QueryRowContext(ctx,
+		"SELECT invoice_number, invoice_amount, tax_amount, exchange_rate, invoice_date, po_id FROM supplier_invoices WHERE invoice_id = $1 FOR UPDATE", id,
+	).Scan(&bookedNumber, &bookedAmount, &bookedTax, &bookedRate, &bookedDate, &poID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("supplier invoice not found")
+		}
+		return nil, fmt.Errorf("failed to lock supplier invoice: %w", err)
+	}
+
+	query := `
+		UPDATE supplier_invoices SET
+			invoice_number = $1, invoice_date = $2, due_date = $3, invoice_amount = $4,
+			tax_amount = $5, tax_invoice_number = $6, payment_term_id = $7, discount_percent = $8,
+			discount_due_date = $9, notes = $10, exchange_rate = $11, updated_at = NOW()
+		WHERE invoice_id = $12`
+
+	result, err := tx.ExecContext(ctx, query,
+		invoice.InvoiceNumber,
+		invoice.InvoiceDate,
+		invoice.DueDate,
+		invoice.InvoiceAmount,
+		invoice.TaxAmount,
+		invoice.TaxInvoiceNumber,
+		invoice.PaymentTermID,
+		invoice.DiscountPercent,
+		invoice.DiscountDueDate,
+		invoice.Notes,
+		invoice.ExchangeRate,
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update supplier invoice: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("supplier invoice not found")
+	}
+
+	if _, err := refreshSupplierInvoiceStatuses(ctx, tx, "i.invoice_id = $1", id); err != nil {
+		return nil, err
+	}
+
+	if bookedNumber != invoice.InvoiceNumber || bookedAmount != invoice.InvoiceAmount || bookedTax != invoice.TaxAmount ||
+		bookedRate != invoice.ExchangeRate || !bookedDate.Equal(invoice.InvoiceDate) {
+		if err := reverseSourceJournals(ctx, tx, products.JournalSourceSupplierInvoice, id, invoice.InvoiceDate, nil); err != nil {
+			return nil, err
+		}
+		invoice.InvoiceID = id
+		invoice.POID = poID
+		receiptValue, err := matchedReceiptValue(ctx, tx, invoice)
+		if err != nil {
+			return nil, err
+		}
+		if err := postJournalEntry(ctx, tx, products.SupplierInvoiceJournal(invoice, receiptValue, nil)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// Delete deletes a supplier invoice that has no payments or credits applied and reverses its journal entries
+func (r *SupplierInvoiceRepository) Delete(ctx context.Context, id int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		DELETE FROM supplier_invoices si
+		WHERE si.invoice_id = $1
+		AND si.credit_amount = 0
+		AND NOT EXISTS (SELECT 1 FROM supplier_payment_allocations pa WHERE pa.invoice_id = si.invoice_id)
+		AND NOT EXISTS (SELECT 1 FROM supplier_debit_note_applications da WHERE da.invoice_id = si.invoice_id)`
+
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete supplier invoice: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("supplier invoice not found or already has payments or credits applied")
+	}
+
+	if err := reverseSourceJournals(ctx, tx, products.JournalSourceSupplierInvoice, id, time.Now(), nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves supplier invoices with pagination
+func (r *SupplierInvoiceRepository) List(ctx context.Context, params *products.SupplierInvoiceFilterParams) (*common.PaginatedResponse, error) {
+	baseQuery := `
+		FROM supplier_invoices si
+		LEFT JOIN suppliers s ON si.supplier_id = s.supplier_id` + supplierInvoiceSettlementJoin + `
+		WHERE 1=1`
+
+	args := []interface{}{}
+	whereConditions := []string{}
+	argIndex := 1
+
+	// Add filters
+	if params.SupplierID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("si.supplier_id = $%d", argIndex))
+		args = append(args, *params.SupplierID)
+		argIndex++
+	}
+
+	if params.POID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("si.po_id = $%d", argIndex))
+		args = append(args, *params.POID)
+		argIndex++
+	}
+
+	if params.InvoiceStatus != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("si.invoice_status = $%d", argIndex))
+		args = append(args, *params.InvoiceStatus)
+		argIndex++
+	}
+
+	if params.DateFrom != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("si.invoice_date >= $%d", argIndex))
+		args = append(args, *params.DateFrom)
+		argIndex++
+	}
+
+	if params.DateTo != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("si.invoice_date <= $%d", argIndex))
+		args = append(args, *params.DateTo)
+		argIndex++
+	}
+
+	if params.IsOverdue != nil && *params.IsOverdue {
+		whereConditions = append(whereConditions, "si.due_date < NOW() AND "+supplierInvoiceOutstandingExpr+" > 0")
+	}
+
+	if params.HasOutstanding != nil {
+		if *params.HasOutstanding {
+			whereConditions = append(whereConditions, supplierInvoiceOutstandingExpr+" > 0")
+		} else {
+			whereConditions = append(whereConditions, supplierInvoiceOutstandingExpr+" = 0")
+		}
+	}
+
+	if params.Search != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("(si.invoice_number ILIKE $%d OR s.supplier_name ILIKE $%d)", argIndex, argIndex))
+		args = append(args, "%"+params.Search+"%")
+		argIndex++
+	}
+
+	if len(whereConditions) > 0 {
+		baseQuery += " AND " + strings.Join(whereConditions, " AND ")
+	}
+
+	// Count query
+	countQuery := "SELECT COUNT(*) " + baseQuery
+	var total int64
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count supplier invoices: %w", err)
+	}
+
+	// Calculate pagination
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	// Main query
+	selectFields := `
+		si.invoice_id, si.supplier_id, COALESCE(s.supplier_name, ''), si.po_id,
+		si.invoice_number, si.invoice_date, si.due_date, si.currency_code, si.invoice_amount,
+		` + supplierInvoiceOutstandingExpr + `, si.invoice_status`
+
+	mainQuery := "SELECT " + selectFields + " " + baseQuery +
+		" ORDER BY si.due_date ASC, si.invoice_id ASC LIMIT $" + fmt.Sprintf("%d", argIndex) +
+		" OFFSET $" + fmt.Sprintf("%d", argIndex+1)
+
+	args = append(args, params.Limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, mainQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query supplier invoices: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	var invoices []products.SupplierInvoiceListItem
+	for rows.Next() {
+		var invoice products.SupplierInvoiceListItem
+		err := rows.Scan(
+			&invoice.InvoiceID,
+			&invoice.SupplierID,
+			&invoice.SupplierName,
+			&invoice.POID,
+			&invoice.InvoiceNumber,
+			&invoice.InvoiceDate,
+			&invoice.DueDate,
+			&invoice.CurrencyCode,
+			&invoice.InvoiceAmount,
+			&invoice.OutstandingAmount,
+			&invoice.InvoiceStatus,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan supplier invoice: %w", err)
+		}
+		if invoice.OutstandingAmount > 0 && now.After(invoice.DueDate) {
+			invoice.DaysOverdue = int(now.Sub(invoice.DueDate).Hours() / 24)
+		}
+		invoices = append(invoices, invoice)
+	}
+
+	totalPages := (total + int64(params.Limit) - 1) / int64(params.Limit)
+
+	return &common.PaginatedResponse{
+		Data:       invoices,
+		Total:      int(total),
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: int(totalPages),
+		HasMore:    params.Page < int(totalPages),
+	}, nil
+}
+
+// GetAllocations retrieves the payment installments allocated to an invoice
+func (r *SupplierInvoiceRepository) GetAllocations(ctx context.Context, invoiceID int) ([]products.PaymentAllocation, error) {
+	query := `
+		SELECT pa.allocation_id, pa.voucher_id, pa.invoice_id, pa.allocated_amount,
+			   pa.discount_amount, pa.fx_gain_loss, pa.created_at, pv.voucher_number, si.invoice_number,
+			   pv.payment_date, pv.payment_method, pv.payment_reference, pv.voucher_status
+		FROM supplier_payment_allocations pa
+		JOIN supplier_payment_vouchers pv ON pv.voucher_id = pa.voucher_id
+		JOIN supplier_invoices si ON si.invoice_id = pa.invoice_id
+		WHERE pa.invoice_id = $1
+		ORDER BY pv.payment_date ASC, pa.allocation_id ASC`
+
+	return queryPaymentAllocations(ctx, r.db, query, invoiceID)
+}
+
+// queryPaymentAllocations runs an allocation query selecting the columns scanned below
+func queryPaymentAllocations(ctx context.Context, db sqlQuerier, query string, args ...interface{}) ([]products.PaymentAllocation, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query payment allocations: %w", err)
+	}
+	defer rows.Close()
+
+	allocations := []products.PaymentAllocation{}
+	for rows.Next() {
+		var allocation products.PaymentAllocation
+		err := rows.Scan(
+			&allocation.AllocationID,
+			&allocation.VoucherID,
+			&allocation.InvoiceID,
+			&allocation.AllocatedAmount,
+			&allocation.DiscountAmount,
+			&allocation.FXGainLoss,
+			&allocation.CreatedAt,
+			&allocation.VoucherNumber,
+			&allocation.InvoiceNumber,
+			&allocation.PaymentDate,
+			&allocation.PaymentMethod,
+			&allocation.PaymentReference,
+			&allocation.VoucherStatus,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan payment allocation: %w", err)
+		}
+		allocations = append(allocations, allocation)
+	}
+
+	return allocations, nil
+}
+
+// IsInvoiceNumberExists checks if a supplier already sent an invoice with the same number
+func (r *SupplierInvoiceRepository) IsInvoiceNumberExists(ctx context.Context, supplierID int, invoiceNumber string, excludeID int) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM supplier_invoices
+			WHERE supplier_id = $1 AND invoice_number = $2 AND invoice_id != $3
+		)`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, supplierID, invoiceNumber, excludeID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check invoice number existence: %w", err)
+	}
+
+	return exists, nil
+}
+
+// UpdateStatus sets the status of an invoice. Setting a status other than disputed
+// re-derives it from the settlements, so this is used to raise and resolve disputes.
+func (r *SupplierInvoiceRepository) UpdateStatus(ctx context.Context, id int, status products.PaymentStatus) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE supplier_invoices
+		SET invoice_status = $1, updated_at = NOW()
+		WHERE invoice_id = $2`, status, id)
+	if err != nil {
+		return fmt.Errorf("failed to update supplier invoice status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("supplier invoice not found")
+	}
+
+	if status != products.PaymentStatusDisputed {
+		if _, err := refreshSupplierInvoiceStatuses(ctx, tx, "i.invoice_id = $1", id); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RefreshStatus re-derives the status of an invoice from its settlements and due date
+func (r *SupplierInvoiceRepository) RefreshStatus(ctx context.Context, id int) error {
+	_, err := refreshSupplierInvoiceStatuses(ctx, r.db, "i.invoice_id = $1", id)
+	return err
+}
+
+// UpdateOverdueStatus marks open invoices past their due date as overdue and returns how many changed
+func (r *SupplierInvoiceRepository) UpdateOverdueStatus(ctx context.Context) (int64, error) {
+	return refreshSupplierInvoiceStatuses(ctx, r.db, "i.invoice_status IN ('pending', 'partial', 'overdue')")
+}
+
+// AccruePenalties recalculates the late penalty of open overdue invoices at a daily rate in percent
+// of the outstanding amount. Penalties stop changing once an invoice is paid or disputed.
+func (r *SupplierInvoiceRepository) AccruePenalties(ctx context.Context, dailyRate float64) (int64, error) {
+	query := `
+		UPDATE supplier_invoices t
+		SET penalty_amount = p.penalty_amount, updated_at = NOW()
+		FROM (
+			SELECT si.invoice_id,
+				   ROUND(` + supplierInvoiceOutstandingExpr + ` * $1 / 100 *
+					   GREATEST(FLOOR(EXTRACT(EPOCH FROM NOW() - si.due_date) / 86400), 0), 2) AS penalty_amount
+			FROM supplier_invoices si` + supplierInvoiceSettlementJoin + `
+			WHERE si.invoice_status IN ('pending', 'partial', 'overdue')
+		) p
+		WHERE t.invoice_id = p.invoice_id
+		AND t.penalty_amount != p.penalty_amount`
+
+	result, err := r.db.ExecContext(ctx, query, dailyRate)
+	if err != nil {
+		return 0, fmt.Errorf("failed to accrue supplier invoice penalties: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// GetSummary gets the invoiced, paid and outstanding totals for a supplier or all suppliers.
+// Foreign currency invoices are converted to the base currency at their own exchange rate.
+func (r *SupplierInvoiceRepository) GetSummary(ctx context.Context, supplierID *int) (map[string]interface{}, error) {
+	baseQuery := `
+		SELECT
+			COUNT(*) as total_invoices,
+			COALESCE(ROUND(SUM(si.invoice_amount * si.exchange_rate), 2), 0) as total_invoiced,
+			COALESCE(ROUND(SUM(settled.paid_amount * si.exchange_rate), 2), 0) as total_paid,
+			COALESCE(ROUND(SUM(settled.discount_taken * si.exchange_rate), 2), 0) as total_discount,
+			COALESCE(ROUND(SUM(si.credit_amount * si.exchange_rate), 2), 0) as total_credited,
+			COALESCE(ROUND(SUM(` + supplierInvoiceOutstandingExpr + ` * si.exchange_rate), 2), 0) as total_outstanding,
+			COUNT(CASE WHEN si.due_date < NOW() AND ` + supplierInvoiceOutstandingExpr + ` > 0 THEN 1 END) as overdue_count,
+			COALESCE(ROUND(SUM(CASE WHEN si.due_date < NOW() THEN ` + supplierInvoiceOutstandingExpr + ` * si.exchange_rate ELSE 0 END), 2), 0) as overdue_amount
+		FROM supplier_invoices si` + supplierInvoiceSettlementJoin + `
+		WHERE 1=1`
+
+	args := []interface{}{}
+	if supplierID != nil {
+		baseQuery += " AND si.supplier_id = $1"
+		args = append(args, *supplierID)
+	}
+
+	var totalInvoices, overdueCount int
+	var totalInvoiced, totalPaid, totalDiscount, totalCredited, totalOutstanding, overdueAmount float64
+
+	err := r.db.QueryRowContext(ctx, baseQuery, args...).Scan(
+		&totalInvoices,
+		&totalInvoiced,
+		&totalPaid,
+		&totalDiscount,
+		&totalCredited,
+		&totalOutstanding,
+		&overdueCount,
+		&overdueAmount,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get supplier invoice summary: %w", err)
+	}
+
+	paymentRate := 0.0
+	if totalInvoiced > 0 {
+		paymentRate = totalPaid / totalInvoiced * 100
+	}
+
+	return map[string]interface{}{
+		"total_invoices":    totalInvoices,
+		"total_invoiced":    totalInvoiced,
+		"total_paid":        totalPaid,
+		"total_discount":    totalDiscount,
+		"total_credited":    totalCredited,
+		"total_outstanding": totalOutstanding,
+		"overdue_count":     overdueCount,
+		"overdue_amount":    overdueAmount,
+		"payment_rate":      paymentRate,
+	}, nil
+}
+
+// GetAgingInvoices retrieves invoices with a balance outstanding at the end of the as-of date.
+// Only payments and credits recorded up to that date reduce the balance, so past dates
+// reproduce the aging as it stood then.
+func (r *SupplierInvoiceRepository) GetAgingInvoices(ctx context.Context, asOf time.Time, supplierID *int) ([]products.APAgingInvoice, error) {
+	asOfEnd := time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, asOf.Location()).AddDate(0, 0, 1)
+
+	query := `
+		SELECT * FROM (
+			SELECT si.invoice_id, si.invoice_number, si.supplier_id, s.supplier_code, s.supplier_name,
+				   si.po_id, si.invoice_date, si.due_date, si.currency_code, si.exchange_rate,
+				   si.invoice_amount, si.invoice_amount - COALESCE((
+					   SELECT SUM(pa.allocated_amount + pa.discount_amount)
+					   FROM supplier_payment_allocations pa
+					   JOIN supplier_payment_vouchers pv ON pv.voucher_id = pa.voucher_id
+					   WHERE pa.invoice_id = si.invoice_id
+					   AND pv.voucher_status = 'posted'
+					   AND pv.payment_date < $1
+				   ), 0) - COALESCE((
+					   SELECT SUM(da.applied_amount)
+					   FROM supplier_debit_note_applications da
+					   WHERE da.invoice_id = si.invoice_id AND da.applied_at < $1
+				   ), 0) AS outstanding_amount
+			FROM supplier_invoices si
+			JOIN suppliers s ON si.supplier_id = s.supplier_id
+			WHERE si.invoice_date < $1`
+
+	args := []interface{}{asOfEnd}
+	if supplierID != nil {
+		query += " AND si.supplier_id = $2"
+		args = append(args, *supplierID)
+	}
+
+	query += `
+		) aged
+		WHERE aged.outstanding_amount > 0.005
+		ORDER BY aged.supplier_name, aged.due_date, aged.invoice_id`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query aging invoices: %w", err)
+	}
+	defer rows.Close()
+
+	var invoices []products.APAgingInvoice
+	for rows.Next() {
+		var invoice products.APAgingInvoice
+		err := rows.Scan(
+			&invoice.InvoiceID,
+			&invoice.InvoiceNumber,
+			&invoice.SupplierID,
+			&invoice.SupplierCode,
+			&invoice.SupplierName,
+			&invoice.POID,
+			&invoice.InvoiceDate,
+			&invoice.DueDate,
+			&invoice.CurrencyCode,
+			&invoice.ExchangeRate,
+			&invoice.InvoiceAmount,
+			&invoice.OutstandingAmount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan aging invoice: %w", err)
+		}
+		invoices = append(invoices, invoice)
+	}
+
+	return invoices, nil
+}
+
+// trailingColumnsScanner scans extra columns selected after the ones a scan function knows about
+type trailingColumnsScanner struct {
+	rowScanner
+	trailing []interface{}
+}
+
+func (s trailingColumnsScanner) Scan(dest ...interface{}) error {
+	return s.rowScanner.Scan(append(dest, s.trailing...)...)
+}
+
+// GetDiscountOpportunities retrieves open invoices whose early payment discount window is still open on asOf
+func (r *SupplierInvoiceRepository) GetDiscountOpportunities(ctx context.Context, asOf time.Time, supplierID *int) ([]products.EarlyPaymentDiscountOpportunity, error) {
+	asOfDay := time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, asOf.Location())
+
+	query := "SELECT " + supplierInvoiceSelectFields + `, s.supplier_name
+		FROM supplier_invoices si` + supplierInvoiceSettlementJoin + `
+		JOIN suppliers s ON si.supplier_id = s.supplier_id
+		WHERE si.discount_percent > 0
+		AND si.discount_due_date >= $1
+		AND si.invoice_status NOT IN ('paid', 'disputed')
+		AND ` + supplierInvoiceOutstandingExpr + ` > 0`
+
+	args := []interface{}{asOfDay}
+	if supplierID != nil {
+		query += " AND si.supplier_id = $2"
+		args = append(args, *supplierID)
+	}
+	query += " ORDER BY si.discount_due_date, si.invoice_id"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query discount opportunities: %w", err)
+	}
+	defer rows.Close()
+
+	var opportunities []products.EarlyPaymentDiscountOpportunity
+	for rows.Next() {
+		var supplierName string
+		invoice, err := scanSupplierInvoice(trailingColumnsScanner{rowScanner: rows, trailing: []interface{}{&supplierName}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan discount opportunity: %w", err)
+		}
+		if opportunity, ok := products.NewEarlyPaymentDiscountOpportunity(invoice, supplierName, asOf); ok {
+			opportunities = append(opportunities, opportunity)
+		}
+	}
+
+	return opportunities, nil
+}
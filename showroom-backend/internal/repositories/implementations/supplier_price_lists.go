@@ -0,0 +1,548 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// SupplierPriceListRepository implements interfaces.SupplierPriceListRepository
+type SupplierPriceListRepository struct {
+	db *sql.DB
+}
+
+// NewSupplierPriceListRepository creates a new supplier price list repository
+func NewSupplierPriceListRepository(db *sql.DB) interfaces.SupplierPriceListRepository {
+	return &SupplierPriceListRepository{db: db}
+}
+
+// Create creates a new supplier price list entry together with its price breaks
+func (r *SupplierPriceListRepository) Create(ctx context.Context, priceList *products.SupplierPriceList) (*products.SupplierPriceList, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO supplier_price_lists (
+			supplier_id, product_id, currency, unit_price, minimum_order_quantity,
+			valid_from, valid_to, is_active, notes, created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING price_list_id, created_at, updated_at`
+
+	err = tx.QueryRowContext(ctx, query,
+		priceList.SupplierID,
+		priceList.ProductID,
+		priceList.Currency,
+		priceList.UnitPrice,
+		priceList.MinimumOrderQuantity,
+		priceList.ValidFrom,
+		priceList.ValidTo,
+		priceList.IsActive,
+		priceList.Notes,
+		priceList.CreatedBy,
+	).Scan(&priceList.PriceListID, &priceList.CreatedAt, &priceList.UpdatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create supplier price list: %w", err)
+	}
+
+	if err := r.insertPriceBreaks(ctx, tx, priceList); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return priceList, nil
+}
+
+// insertPriceBreaks writes the price breaks of a price list inside the given transaction
+func (r *SupplierPriceListRepository) insertPriceBreaks(ctx context.Context, tx *sql.Tx, priceList *products.SupplierPriceList) error {
+	query := `
+		INSERT INTO supplier_price_breaks (price_list_id, min_quantity, unit_price)
+		VALUES ($1, $2, $3)
+		RETURNING price_break_id`
+
+	for i := range priceList.PriceBreaks {
+		priceList.PriceBreaks[i].PriceListID = priceList.PriceListID
+		err := tx.QueryRowContext(ctx, query,
+			priceList.PriceBreaks[i].PriceListID,
+			priceList.PriceBreaks[i].MinQuantity,
+			priceList.PriceBreaks[i].UnitPrice,
+		).Scan(&priceList.PriceBreaks[i].PriceBreakID)
+		if err != nil {
+			return fmt.Errorf("failed to create price break: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetByID retrieves a supplier price list entry by ID together with its price breaks
+func (r *SupplierPriceListRepository) GetByID(ctx context.Context, id int) (*products.SupplierPriceList, error) {
+	query := `
+		SELECT price_list_id, supplier_id, product_id, currency, unit_price,
+			   minimum_order_quantity, valid_from, valid_to, is_active, notes,
+			   created_by, created_at, updated_at
+		FROM supplier_price_lists
+		WHERE price_list_id = $1`
+
+	priceList, err := r.scanPriceList(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("supplier price list not found")
+		}
+		return nil, fmt.Errorf("failed to get supplier price list: %w", err)
+	}
+
+	if err := r.loadPriceBreaks(ctx, priceList); err != nil {
+		return nil, err
+	}
+
+	return priceList, nil
+}
+
+func (r *SupplierPriceListRepository) scanPriceList(row *sql.Row) (*products.SupplierPriceList, error) {
+	priceList := &products.SupplierPriceList{}
+	err := row.Scan(
+		&priceList.PriceListID,
+		&priceList.SupplierID,
+		&priceList.ProductID,
+		&priceList.Currency,
+		&priceList.UnitPrice,
+		&priceList.MinimumOrderQuantity,
+		&priceList.ValidFrom,
+		&priceList.ValidTo,
+		&priceList.IsActive,
+		&priceList.Notes,
+		&priceList.CreatedBy,
+		&priceList.CreatedAt,
+		&priceList.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return priceList, nil
+}
+
+func (r *SupplierPriceListRepository) loadPriceBreaks(ctx context.Context, priceList *products.SupplierPriceList) error {
+	query := `
+		SELECT price_break_id, price_list_id, min_quantity, unit_price
+		FROM supplier_price_breaks
+		WHERE price_list_id = $1
+		ORDER BY min_quantity`
+
+	rows, err := r.db.QueryContext(ctx, query, priceList.PriceListID)
+	if err != nil {
+		return fmt.Errorf("failed to query price breaks: %w", err)
+	}
+	defer rows.Close()
+
+	priceList.PriceBreaks = nil
+	for rows.Next() {
+		var priceBreak products.SupplierPriceBreak
+		err := rows.Scan(
+			&priceBreak.PriceBreakID,
+			&priceBreak.PriceListID,
+			&priceBreak.MinQuantity,
+			&priceBreak.UnitPrice,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan price break: %w", err)
+		}
+		priceList.PriceBreaks = append(priceList.PriceBreaks, priceBreak)
+	}
+
+	return nil
+}
+
+// Update updates a supplier price list entry and replaces its price breaks
+func (r *SupplierPriceListRepository) Update(ctx context.Context, id int, priceList *products.SupplierPriceList) (*products.SupplierPriceList, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE supplier_price_lists SET
+			unit_price = $1, minimum_order_quantity = $2, valid_from = $3,
+			valid_to = $4, is_active = $5, notes = $6, updated_at = NOW()
+		WHERE price_list_id = $7
+		RETURNING updated_at`
+
+	err = tx.QueryRowContext(ctx, query,
+		priceList.UnitPrice,
+		priceList.MinimumOrderQuantity,
+		priceList.ValidFrom,
+		priceList.ValidTo,
+		priceList.IsActive,
+		priceList.Notes,
+		id,
+	).Scan(&priceList.UpdatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("supplier price list not found")
+		}
+		return nil, fmt.Errorf("failed to update supplier price list: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM supplier_price_breaks WHERE price_list_id = $1`, id); err != nil {
+		return nil, fmt.Errorf("failed to clear price breaks: %w", err)
+	}
+
+	priceList.PriceListID = id
+	if err := r.insertPriceBreaks(ctx, tx, priceList); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return priceList, nil
+}
+
+// Delete deletes a supplier price list entry
+func (r *SupplierPriceListRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM supplier_price_lists WHERE price_list_id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete supplier price list: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("supplier price list not found")
+	}
+
+	return nil
+}
+
+// List retrieves supplier price list entries with pagination
+func (r *SupplierPriceListRepository) List(ctx context.Context, params *products.SupplierPriceListFilterParams) (*common.PaginatedResponse, error) {
+	baseQuery := `
+		FROM supplier_price_lists pl
+		LEFT JOIN suppliers s ON pl.supplier_id = s.supplier_id
+		LEFT JOIN products_spare_parts p ON pl.product_id = p.product_id
+		WHERE 1=1`
+
+	args := []interface{}{}
+	whereConditions := []string{}
+	argIndex := 1
+
+	if params.SupplierID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pl.supplier_id = $%d", argIndex))
+		args = append(args, *params.SupplierID)
+		argIndex++
+	}
+
+	if params.ProductID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pl.product_id = $%d", argIndex))
+		args = append(args, *params.ProductID)
+		argIndex++
+	}
+
+	if params.Currency != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("pl.currency = $%d", argIndex))
+		args = append(args, strings.ToUpper(params.Currency))
+		argIndex++
+	}
+
+	if params.IsActive != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pl.is_active = $%d", argIndex))
+		args = append(args, *params.IsActive)
+		argIndex++
+	}
+
+	if params.ValidOn != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("pl.valid_from <= $%d AND (pl.valid_to IS NULL OR pl.valid_to >= $%d)", argIndex, argIndex))
+		args = append(args, *params.ValidOn)
+		argIndex++
+	}
+
+	if params.Search != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("(s.supplier_name ILIKE $%d OR p.product_name ILIKE $%d OR p.product_code ILIKE $%d)", argIndex, argIndex, argIndex))
+		args = append(args, "%"+params.Search+"%")
+		argIndex++
+	}
+
+	if len(whereConditions) > 0 {
+		baseQuery += " AND " + strings.Join(whereConditions, " AND ")
+	}
+
+	// Count query
+	countQuery := "SELECT COUNT(*) " + baseQuery
+	var total int64
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count supplier price lists: %w", err)
+	}
+
+	// Calculate pagination
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	// Main query
+	selectFields := `
+		pl.price_list_id, pl.supplier_id, COALESCE(s.supplier_name, ''),
+		pl.product_id, COALESCE(p.product_name, ''), pl.currency, pl.unit_price,
+		pl.minimum_order_quantity, pl.valid_from, pl.valid_to, pl.is_active,
+		(SELECT COUNT(*) FROM supplier_price_breaks pb WHERE pb.price_list_id = pl.price_list_id)`
+
+	mainQuery := "SELECT " + selectFields + " " + baseQuery +
+		" ORDER BY pl.valid_from DESC, pl.price_list_id DESC LIMIT $" + fmt.Sprintf("%d", argIndex) +
+		" OFFSET $" + fmt.Sprintf("%d", argIndex+1)
+
+	args = append(args, params.Limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, mainQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query supplier price lists: %w", err)
+	}
+	defer rows.Close()
+
+	var priceLists []products.SupplierPriceListListItem
+	for rows.Next() {
+		var item products.SupplierPriceListListItem
+		err := rows.Scan(
+			&item.PriceListID,
+			&item.SupplierID,
+			&item.SupplierName,
+			&item.ProductID,
+			&item.ProductName,
+			&item.Currency,
+			&item.UnitPrice,
+			&item.MinimumOrderQuantity,
+			&item.ValidFrom,
+			&item.ValidTo,
+			&item.IsActive,
+			&item.PriceBreakCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan supplier price list: %w", err)
+		}
+		priceLists = append(priceLists, item)
+	}
+
+	totalPages := (total + int64(params.Limit) - 1) / int64(params.Limit)
+
+	return &common.PaginatedResponse{
+		Data:       priceLists,
+		Total:      int(total),
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: int(totalPages),
+		HasMore:    params.Page < int(totalPages),
+	}, nil
+}
+
+// FindActive retrieves the active price list for a supplier and product at the given time.
+// It returns nil without an error when the supplier has no applicable price list.
+func (r *SupplierPriceListRepository) FindActive(ctx context.Context, supplierID, productID int, currency string, at time.Time) (*products.SupplierPriceList, error) {
+	query := `
+		SELECT price_list_id, supplier_id, product_id, currency, unit_price,
+			   minimum_order_quantity, valid_from, valid_to, is_active, notes,
+			   created_by, created_at, updated_at
+		FROM supplier_price_lists
+		WHERE supplier_id = $1 AND product_id = $2 AND currency = $3
+		  AND is_active = TRUE
+		  AND valid_from <= $4 AND (valid_to IS NULL OR valid_to >= $4)
+		ORDER BY valid_from DESC, price_list_id DESC
+		LIMIT 1`
+
+	priceList, err := r.scanPriceList(r.db.QueryRowContext(ctx, query, supplierID, productID, currency, at))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find active supplier price list: %w", err)
+	}
+
+	if err := r.loadPriceBreaks(ctx, priceList); err != nil {
+		return nil, err
+	}
+
+	return priceList, nil
+}
+
+// HasOverlap checks if another active price list for the same supplier, product and currency overlaps the validity period
+func (r *SupplierPriceListRepository) HasOverlap(ctx context.Context, priceList *products.SupplierPriceList) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM supplier_price_lists
+			WHERE supplier_id = $1 AND product_id = $2 AND currency = $3
+			  AND is_active = TRUE AND price_list_id != $4
+			  AND (valid_to IS NULL OR valid_to >= $5)
+			  AND ($6::timestamp IS NULL OR valid_from <= $6)
+		)`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query,
+		priceList.SupplierID,
+		priceList.ProductID,
+		priceList.Currency,
+		priceList.PriceListID,
+		priceList.ValidFrom,
+		priceList.ValidTo,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check price list overlap: %w", err)
+	}
+
+	return exists, nil
+}
+
+// RecordPriceHistory records a quoted or paid supplier price
+func (r *SupplierPriceListRepository) RecordPriceHistory(ctx context.Context, history *products.SupplierPriceHistory) error {
+	if history.RecordedAt.IsZero() {
+		history.RecordedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO supplier_price_history (
+			supplier_id, product_id, currency, unit_price, quantity,
+			source, reference_id, recorded_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING history_id`
+
+	err := r.db.QueryRowContext(ctx, query,
+		history.SupplierID,
+		history.ProductID,
+		history.Currency,
+		history.UnitPrice,
+		history.Quantity,
+		history.Source,
+		history.ReferenceID,
+		history.RecordedAt,
+	).Scan(&history.HistoryID)
+
+	if err != nil {
+		return fmt.Errorf("failed to record supplier price history: %w", err)
+	}
+
+	return nil
+}
+
+// GetPriceHistory retrieves the price history of a supplier with pagination
+func (r *SupplierPriceListRepository) GetPriceHistory(ctx context.Context, params *products.SupplierPriceHistoryFilterParams) (*common.PaginatedResponse, error) {
+	baseQuery := `
+		FROM supplier_price_history
+		WHERE supplier_id = $1`
+
+	args := []interface{}{params.SupplierID}
+	whereConditions := []string{}
+	argIndex := 2
+
+	if params.ProductID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("product_id = $%d", argIndex))
+		args = append(args, *params.ProductID)
+		argIndex++
+	}
+
+	if params.Source != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("source = $%d", argIndex))
+		args = append(args, *params.Source)
+		argIndex++
+	}
+
+	if params.DateFrom != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("recorded_at >= $%d", argIndex))
+		args = append(args, *params.DateFrom)
+		argIndex++
+	}
+
+	if params.DateTo != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("recorded_at <= $%d", argIndex))
+		args = append(args, *params.DateTo)
+		argIndex++
+	}
+
+	if len(whereConditions) > 0 {
+		baseQuery += " AND " + strings.Join(whereConditions, " AND ")
+	}
+
+	// Count query
+	countQuery := "SELECT COUNT(*) " + baseQuery
+	var total int64
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count supplier price history: %w", err)
+	}
+
+	// Calculate pagination
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	mainQuery := `
+		SELECT history_id, supplier_id, product_id, currency, unit_price, quantity,
+			   source, reference_id, recorded_at ` + baseQuery +
+		" ORDER BY recorded_at DESC, history_id DESC LIMIT $" + fmt.Sprintf("%d", argIndex) +
+		" OFFSET $" + fmt.Sprintf("%d", argIndex+1)
+
+	args = append(args, params.Limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, mainQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query supplier price history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []products.SupplierPriceHistory
+	for rows.Next() {
+		var entry products.SupplierPriceHistory
+		err := rows.Scan(
+			&entry.HistoryID,
+			&entry.SupplierID,
+			&entry.ProductID,
+			&entry.Currency,
+			&entry.UnitPrice,
+			&entry.Quantity,
+			&entry.Source,
+			&entry.ReferenceID,
+			&entry.RecordedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan supplier price history: %w", err)
+		}
+		history = append(history, entry)
+	}
+
+	totalPages := (total + int64(params.Limit) - 1) / int64(params.Limit)
+
+	return &common.PaginatedResponse{
+		Data:       history,
+		Total:      int(total),
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: int(totalPages),
+		HasMore:    params.Page < int(totalPages),
+	}, nil
+}
@@ -0,0 +1,145 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// SupplierScorecardRepository implements interfaces.SupplierScorecardRepository
+type SupplierScorecardRepository struct {
+	db *sql.DB
+}
+
+// NewSupplierScorecardRepository creates a new supplier scorecard repository
+func NewSupplierScorecardRepository(db *sql.DB) interfaces.SupplierScorecardRepository {
+	return &SupplierScorecardRepository{db: db}
+}
+
+// GetPerformanceMetrics aggregates delivery, fill and quality counts per supplier over the period.
+// Only suppliers with purchase orders or receipts in the period are returned, optionally
+// restricted to the given supplier IDs. Draft, cancelled, blanket and contract orders are
+// left out because they are never received against directly.
+func (r *SupplierScorecardRepository) GetPerformanceMetrics(ctx context.Context, params *products.SupplierScorecardParams, supplierIDs []int) ([]products.SupplierPerformanceMetrics, error) {
+	query := `
+		WITH po_scope AS (
+			SELECT po.po_id, po.supplier_id
+			FROM purchase_orders_parts po
+			WHERE po.status NOT IN ('draft', 'cancelled')
+			  AND po.po_type NOT IN ('blanket', 'contract')
+			  AND ($1::timestamp IS NULL OR po.po_date >= $1)
+			  AND ($2::timestamp IS NULL OR po.po_date < $2::timestamp + INTERVAL '1 day')
+		),
+		po_stats AS (
+			SELECT ps.supplier_id,
+				   COUNT(DISTINCT ps.po_id) AS purchase_order_count,
+				   COALESCE(SUM(d.quantity_ordered), 0) AS quantity_ordered,
+				   COALESCE(SUM(LEAST(d.quantity_received, d.quantity_ordered)), 0) AS quantity_filled
+			FROM po_scope ps
+			LEFT JOIN purchase_order_details d ON d.po_id = ps.po_id AND d.line_status != 'cancelled'
+			GROUP BY ps.supplier_id
+		),
+		receipt_scope AS (
+			SELECT gr.receipt_id, gr.receipt_date, gr.total_received_value,
+				   po.po_id, po.supplier_id, po.po_date, po.expected_delivery_date
+			FROM goods_receipts gr
+			JOIN purchase_orders_parts po ON po.po_id = gr.po_id
+			WHERE ($1::timestamp IS NULL OR gr.receipt_date >= $1)
+			  AND ($2::timestamp IS NULL OR gr.receipt_date < $2::timestamp + INTERVAL '1 day')
+		),
+		receipt_stats AS (
+			SELECT supplier_id,
+				   COUNT(*) AS receipt_count,
+				   COUNT(expected_delivery_date) AS scheduled_receipts,
+				   COUNT(*) FILTER (WHERE receipt_date::date <= expected_delivery_date::date) AS on_time_receipts,
+				   COALESCE(SUM(total_received_value), 0) AS total_received_value
+			FROM receipt_scope
+			GROUP BY supplier_id
+		),
+		quality_stats AS (
+			SELECT rs.supplier_id,
+				   COALESCE(SUM(grd.quantity_received), 0) AS quantity_received,
+				   COALESCE(SUM(grd.quantity_rejected), 0) AS quantity_rejected,
+				   COUNT(*) FILTER (WHERE grd.condition_received != 'good') AS defective_lines
+			FROM receipt_scope rs
+			JOIN goods_receipt_details grd ON grd.receipt_id = rs.receipt_id
+			GROUP BY rs.supplier_id
+		),
+		lead_times AS (
+			SELECT supplier_id,
+				   COUNT(*) AS lead_time_samples,
+				   SUM(EXTRACT(EPOCH FROM (first_receipt - po_date)) / 86400) AS total_lead_time_days
+			FROM (
+				SELECT supplier_id, po_id, po_date, MIN(receipt_date) AS first_receipt
+				FROM receipt_scope
+				GROUP BY supplier_id, po_id, po_date
+			) first_receipts
+			GROUP BY supplier_id
+		)
+		SELECT s.supplier_id, s.supplier_code, s.supplier_name,
+			   COALESCE(ps.purchase_order_count, 0), COALESCE(rst.receipt_count, 0),
+			   COALESCE(rst.scheduled_receipts, 0), COALESCE(rst.on_time_receipts, 0),
+			   COALESCE(ps.quantity_ordered, 0), COALESCE(ps.quantity_filled, 0),
+			   COALESCE(qs.quantity_received, 0), COALESCE(qs.quantity_rejected, 0),
+			   COALESCE(qs.defective_lines, 0), COALESCE(lt.lead_time_samples, 0),
+			   COALESCE(lt.total_lead_time_days, 0), COALESCE(rst.total_received_value, 0)
+		FROM suppliers s
+		LEFT JOIN po_stats ps ON ps.supplier_id = s.supplier_id
+		LEFT JOIN receipt_stats rst ON rst.supplier_id = s.supplier_id
+		LEFT JOIN quality_stats qs ON qs.supplier_id = s.supplier_id
+		LEFT JOIN lead_times lt ON lt.supplier_id = s.supplier_id
+		WHERE (ps.supplier_id IS NOT NULL OR rst.supplier_id IS NOT NULL)`
+
+	args := []interface{}{params.DateFrom, params.DateTo}
+	argIndex := 3
+
+	if len(supplierIDs) > 0 {
+		placeholders := make([]string, len(supplierIDs))
+		for i, supplierID := range supplierIDs {
+			placeholders[i] = fmt.Sprintf("$%d", argIndex)
+			args = append(args, supplierID)
+			argIndex++
+		}
+		query += " AND s.supplier_id IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	query += " ORDER BY s.supplier_name"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query supplier performance: %w", err)
+	}
+	defer rows.Close()
+
+	var metrics []products.SupplierPerformanceMetrics
+	for rows.Next() {
+		var m products.SupplierPerformanceMetrics
+		err := rows.Scan(
+			&m.SupplierID,
+			&m.SupplierCode,
+			&m.SupplierName,
+			&m.PurchaseOrderCount,
+			&m.ReceiptCount,
+			&m.ScheduledReceipts,
+			&m.OnTimeReceipts,
+			&m.QuantityOrdered,
+			&m.QuantityFilled,
+			&m.QuantityReceived,
+			&m.QuantityRejected,
+			&m.DefectiveLines,
+			&m.LeadTimeSamples,
+			&m.TotalLeadTimeDays,
+			&m.TotalReceivedValue,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan supplier performance: %w", err)
+		}
+		metrics = append(metrics, m)
+	}
+
+	return metrics, nil
+}
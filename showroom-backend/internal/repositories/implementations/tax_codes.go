@@ -0,0 +1,262 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// TaxCodeRepository implements interfaces.TaxCodeRepository
+type TaxCodeRepository struct {
+	db *sql.DB
+}
+
+// NewTaxCodeRepository creates a new tax code repository
+func NewTaxCodeRepository(db *sql.DB) interfaces.TaxCodeRepository {
+	return &TaxCodeRepository{db: db}
+}
+
+// Create creates a new tax code
+func (r *TaxCodeRepository) Create(ctx context.Context, taxCode *products.TaxCode) (*products.TaxCode, error) {
+	query := `
+		INSERT INTO tax_codes (code, name, rate)
+		VALUES ($1, $2, $3)
+		RETURNING tax_code_id, is_active, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		taxCode.Code,
+		taxCode.Name,
+		taxCode.Rate,
+	).Scan(&taxCode.TaxCodeID, &taxCode.IsActive, &taxCode.CreatedAt, &taxCode.UpdatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tax code: %w", err)
+	}
+
+	return taxCode, nil
+}
+
+// GetByID retrieves a tax code by ID
+func (r *TaxCodeRepository) GetByID(ctx context.Context, id int) (*products.TaxCode, error) {
+	query := `
+		SELECT tax_code_id, code, name, rate, is_active, created_at, updated_at
+		FROM tax_codes
+		WHERE tax_code_id = $1`
+
+	taxCode := &products.TaxCode{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&taxCode.TaxCodeID,
+		&taxCode.Code,
+		&taxCode.Name,
+		&taxCode.Rate,
+		&taxCode.IsActive,
+		&taxCode.CreatedAt,
+		&taxCode.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("tax code not found")
+		}
+		return nil, fmt.Errorf("failed to get tax code: %w", err)
+	}
+
+	return taxCode, nil
+}
+
+// Update updates a tax code. Document lines keep the rate they were created with.
+func (r *TaxCodeRepository) Update(ctx context.Context, id int, taxCode *products.TaxCode) (*products.TaxCode, error) {
+	query := `
+		UPDATE tax_codes SET
+			name = $1, rate = $2, is_active = $3, updated_at = NOW()
+		WHERE tax_code_id = $4
+		RETURNING updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		taxCode.Name,
+		taxCode.Rate,
+		taxCode.IsActive,
+		id,
+	).Scan(&taxCode.UpdatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("tax code not found")
+		}
+		return nil, fmt.Errorf("failed to update tax code: %w", err)
+	}
+
+	taxCode.TaxCodeID = id
+	return taxCode, nil
+}
+
+// List retrieves tax codes with pagination
+func (r *TaxCodeRepository) List(ctx context.Context, params *products.TaxCodeFilterParams) (*common.PaginatedResponse, error) {
+	baseQuery := `FROM tax_codes tc WHERE 1=1`
+
+	args := []interface{}{}
+	whereConditions := []string{}
+	argIndex := 1
+
+	// Add filters
+	if params.IsActive != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("tc.is_active = $%d", argIndex))
+		args = append(args, *params.IsActive)
+		argIndex++
+	}
+
+	if params.Search != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("(tc.code ILIKE $%d OR tc.name ILIKE $%d)", argIndex, argIndex))
+		args = append(args, "%"+params.Search+"%")
+		argIndex++
+	}
+
+	if len(whereConditions) > 0 {
+		baseQuery += " AND " + strings.Join(whereConditions, " AND ")
+	}
+
+	// Count query
+	countQuery := "SELECT COUNT(*) " + baseQuery
+	var total int64
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tax codes: %w", err)
+	}
+
+	// Calculate pagination
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	// Main query
+	selectFields := `
+		tc.tax_code_id, tc.code, tc.name, tc.rate, tc.is_active, tc.created_at, tc.updated_at`
+
+	mainQuery := "SELECT " + selectFields + " " + baseQuery +
+		" ORDER BY tc.code ASC LIMIT $" + fmt.Sprintf("%d", argIndex) +
+		" OFFSET $" + fmt.Sprintf("%d", argIndex+1)
+
+	args = append(args, params.Limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, mainQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tax codes: %w", err)
+	}
+	defer rows.Close()
+
+	var taxCodes []products.TaxCode
+	for rows.Next() {
+		var taxCode products.TaxCode
+		err := rows.Scan(
+			&taxCode.TaxCodeID,
+			&taxCode.Code,
+			&taxCode.Name,
+			&taxCode.Rate,
+			&taxCode.IsActive,
+			&taxCode.CreatedAt,
+			&taxCode.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tax code: %w", err)
+		}
+		taxCodes = append(taxCodes, taxCode)
+	}
+
+	totalPages := (total + int64(params.Limit) - 1) / int64(params.Limit)
+
+	return &common.PaginatedResponse{
+		Data:       taxCodes,
+		Total:      int(total),
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: int(totalPages),
+		HasMore:    params.Page < int(totalPages),
+	}, nil
+}
+
+// IsCodeExists checks if a tax code is already used
+func (r *TaxCodeRepository) IsCodeExists(ctx context.Context, code string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM tax_codes WHERE code = $1)`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, code).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check tax code existence: %w", err)
+	}
+
+	return exists, nil
+}
+
+// GetVATReport retrieves the input VAT of supplier invoices and the output VAT of issued
+// sales invoices dated in the month starting at periodStart
+func (r *TaxCodeRepository) GetVATReport(ctx context.Context, periodStart time.Time) (*products.VATReport, error) {
+	periodEnd := products.PeriodEnd(periodStart)
+
+	inputQuery := `
+		SELECT si.invoice_id, si.invoice_number, si.tax_invoice_number, si.invoice_date,
+			   s.supplier_name, s.tax_number, si.invoice_amount - si.tax_amount, si.tax_amount
+		FROM supplier_invoices si
+		JOIN suppliers s ON s.supplier_id = si.supplier_id
+		WHERE si.tax_amount > 0 AND si.invoice_date BETWEEN $1 AND $2
+		ORDER BY si.invoice_date, si.invoice_id`
+
+	inputLines, err := r.queryVATLines(ctx, inputQuery, periodStart, periodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query input VAT: %w", err)
+	}
+
+	outputQuery := `
+		SELECT si.invoice_id, si.invoice_number, si.tax_invoice_number, si.invoice_date,
+			   c.customer_name, c.tax_number, si.subtotal, si.tax_amount
+		FROM sales_invoices si
+		JOIN customers c ON c.customer_id = si.customer_id
+		WHERE si.invoice_status = 'issued' AND si.tax_amount > 0 AND si.invoice_date BETWEEN $1 AND $2
+		ORDER BY si.invoice_date, si.invoice_id`
+
+	outputLines, err := r.queryVATLines(ctx, outputQuery, periodStart, periodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query output VAT: %w", err)
+	}
+
+	return products.NewVATReport(periodStart, inputLines, outputLines), nil
+}
+
+func (r *TaxCodeRepository) queryVATLines(ctx context.Context, query string, args ...interface{}) ([]products.VATReportLine, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []products.VATReportLine
+	for rows.Next() {
+		var line products.VATReportLine
+		err := rows.Scan(
+			&line.DocumentID,
+			&line.DocumentNumber,
+			&line.TaxInvoiceNumber,
+			&line.DocumentDate,
+			&line.PartyName,
+			&line.PartyTaxNumber,
+			&line.TaxBase,
+			&line.TaxAmount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
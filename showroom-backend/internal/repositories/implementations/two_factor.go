@@ -0,0 +1,216 @@
+package implementations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+type twoFactorRepository struct {
+	db *sql.DB
+}
+
+// NewTwoFactorRepository creates a new two-factor repository
+func NewTwoFactorRepository(db *sql.DB) interfaces.TwoFactorRepository {
+	return &twoFactorRepository{db: db}
+}
+
+// GetByUserID retrieves the TOTP enrollment of a user, returning user.ErrTwoFactorNotEnrolled when there is none
+func (r *twoFactorRepository) GetByUserID(ctx context.Context, userID int) (*user.UserTwoFactor, error) {
+	var twoFactor user.UserTwoFactor
+	err := r.db.QueryRowContext(ctx, `
+		SELECT user_id, secret, enabled_at, last_used_step, created_at
+		FROM user_two_factor
+		WHERE user_id = $1`, userID,
+	).Scan(&twoFactor.UserID, &twoFactor.Secret, &twoFactor.EnabledAt, &twoFactor.LastUsedStep, &twoFactor.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, user.ErrTwoFactorNotEnrolled
+		}
+		return nil, fmt.Errorf("failed to get two-factor enrollment: %w", err)
+	}
+
+	return &twoFactor, nil
+}
+
+// SavePending stores a new secret awaiting confirmation, replacing an earlier unconfirmed one.
+// A confirmed enrollment is never overwritten.
+func (r *twoFactorRepository) SavePending(ctx context.Context, userID int, secret string) error {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_two_factor (user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_used_step = 0, created_at = NOW()
+		WHERE user_two_factor.enabled_at IS NULL`, userID, secret)
+	if err != nil {
+		return fmt.Errorf("failed to save two-factor secret: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return user.ErrTwoFactorAlreadyEnabled
+	}
+
+	return nil
+}
+
+// Enable confirms a pending enrollment, recording the step of the confirming code and replacing
+// the recovery codes
+func (r *twoFactorRepository) Enable(ctx context.Context, userID int, step int64, recoveryCodeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE user_two_factor
+		SET enabled_at = NOW(), last_used_step = $2
+		WHERE user_id = $1 AND enabled_at IS NULL`, userID, step)
+	if err != nil {
+		return fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return user.ErrTwoFactorAlreadyEnabled
+	}
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, recoveryCodeHashes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// MarkStepUsed records that the code of a time step was accepted. It reports false when that
+// step or a later one was already used, which means the code is being replayed.
+func (r *twoFactorRepository) MarkStepUsed(ctx context.Context, userID int, step int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE user_two_factor
+		SET last_used_step = $2
+		WHERE user_id = $1 AND last_used_step < $2`, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to record two-factor code: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// Delete removes the enrollment and recovery codes of a user
+func (r *twoFactorRepository) Delete(ctx context.Context, userID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_recovery_codes WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_two_factor WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("failed to delete two-factor enrollment: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ListUnusedRecoveryCodes retrieves the recovery codes of a user that have not been used
+func (r *twoFactorRepository) ListUnusedRecoveryCodes(ctx context.Context, userID int) ([]user.RecoveryCode, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT code_id, user_id, code_hash, used_at
+		FROM user_recovery_codes
+		WHERE user_id = $1 AND used_at IS NULL
+		ORDER BY code_id ASC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recovery codes: %w", err)
+	}
+	defer rows.Close()
+
+	codes := []user.RecoveryCode{}
+	for rows.Next() {
+		var code user.RecoveryCode
+		if err := rows.Scan(&code.CodeID, &code.UserID, &code.CodeHash, &code.UsedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan recovery code: %w", err)
+		}
+		codes = append(codes, code)
+	}
+
+	return codes, rows.Err()
+}
+
+// UseRecoveryCode marks a recovery code used, reporting false when it already was
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, codeID int) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE user_recovery_codes
+		SET used_at = NOW()
+		WHERE code_id = $1 AND used_at IS NULL`, codeID)
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// ReplaceRecoveryCodes discards the recovery codes of a user and stores new ones
+func (r *twoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// replaceRecoveryCodes swaps the recovery codes of a user within a transaction
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID int, codeHashes []string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_recovery_codes WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	for _, codeHash := range codeHashes {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO user_recovery_codes (user_id, code_hash) VALUES ($1, $2)", userID, codeHash,
+		); err != nil {
+			return fmt.Errorf("failed to create recovery code: %w", err)
+		}
+	}
+
+	return nil
+}
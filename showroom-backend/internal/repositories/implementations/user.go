@@ -25,12 +25,12 @@ func (r *userRepository) Create(ctx context.Context, u *user.User) (*user.User,
 	query := `
 		INSERT INTO users (username, email, password_hash, full_name, phone, address, role, salary, hire_date, created_by, profile_image, notes)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-		RETURNING user_id, created_at, updated_at`
+		RETURNING user_id, created_at, updated_at, password_changed_at`
 
 	err := r.db.QueryRowContext(ctx, query,
 		u.Username, u.Email, u.PasswordHash, u.FullName, u.Phone, u.Address,
 		u.Role, u.Salary, u.HireDate, u.CreatedBy, u.ProfileImage, u.Notes,
-	).Scan(&u.UserID, &u.CreatedAt, &u.UpdatedAt)
+	).Scan(&u.UserID, &u.CreatedAt, &u.UpdatedAt, &u.PasswordChangedAt)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
@@ -44,7 +44,7 @@ func (r *userRepository) GetByID(ctx context.Context, id int) (*user.User, error
 	query := `
 		SELECT u.user_id, u.username, u.email, u.password_hash, u.full_name, u.phone, u.address,
 		       u.role, u.salary, u.hire_date, u.created_at, u.updated_at, u.created_by,
-		       u.is_active, u.profile_image, u.notes,
+		       u.is_active, u.profile_image, u.notes, u.password_changed_at, u.must_change_password,
 		       c.user_id as creator_user_id, c.username as creator_username, c.full_name as creator_full_name
 		FROM users u
 		LEFT JOIN users c ON u.created_by = c.user_id
@@ -58,7 +58,7 @@ func (r *userRepository) GetByID(ctx context.Context, id int) (*user.User, error
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&u.UserID, &u.Username, &u.Email, &u.PasswordHash, &u.FullName, &u.Phone, &u.Address,
 		&u.Role, &u.Salary, &u.HireDate, &u.CreatedAt, &u.UpdatedAt, &u.CreatedBy,
-		&u.IsActive, &u.ProfileImage, &u.Notes,
+		&u.IsActive, &u.ProfileImage, &u.Notes, &u.PasswordChangedAt, &u.MustChangePassword,
 		&creatorUserID, &creatorUsername, &creatorFullName,
 	)
 
@@ -85,7 +85,7 @@ func (r *userRepository) GetByUsername(ctx context.Context, username string) (*u
 	query := `
 		SELECT user_id, username, email, password_hash, full_name, phone, address,
 		       role, salary, hire_date, created_at, updated_at, created_by,
-		       is_active, profile_image, notes
+		       is_active, profile_image, notes, password_changed_at, must_change_password
 		FROM users
 		WHERE username = $1`
 
@@ -93,7 +93,7 @@ func (r *userRepository) GetByUsername(ctx context.Context, username string) (*u
 	err := r.db.QueryRowContext(ctx, query, username).Scan(
 		&u.UserID, &u.Username, &u.Email, &u.PasswordHash, &u.FullName, &u.Phone, &u.Address,
 		&u.Role, &u.Salary, &u.HireDate, &u.CreatedAt, &u.UpdatedAt, &u.CreatedBy,
-		&u.IsActive, &u.ProfileImage, &u.Notes,
+		&u.IsActive, &u.ProfileImage, &u.Notes, &u.PasswordChangedAt, &u.MustChangePassword,
 	)
 
 	if err != nil {
@@ -111,7 +111,7 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*user.Us
 	query := `
 		SELECT user_id, username, email, password_hash, full_name, phone, address,
 		       role, salary, hire_date, created_at, updated_at, created_by,
-		       is_active, profile_image, notes
+		       is_active, profile_image, notes, password_changed_at, must_change_password
 		FROM users
 		WHERE email = $1`
 
@@ -119,7 +119,7 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*user.Us
 	err := r.db.QueryRowContext(ctx, query, email).Scan(
 		&u.UserID, &u.Username, &u.Email, &u.PasswordHash, &u.FullName, &u.Phone, &u.Address,
 		&u.Role, &u.Salary, &u.HireDate, &u.CreatedAt, &u.UpdatedAt, &u.CreatedBy,
-		&u.IsActive, &u.ProfileImage, &u.Notes,
+		&u.IsActive, &u.ProfileImage, &u.Notes, &u.PasswordChangedAt, &u.MustChangePassword,
 	)
 
 	if err != nil {
@@ -155,6 +155,92 @@ func (r *userRepository) Update(ctx context.Context, id int, u *user.User) (*use
 	return u, nil
 }
 
+// UpdatePassword replaces the password hash of a user, keeping the old hash in the password
+// history. It restarts the password age and clears a forced password change.
+func (r *userRepository) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var oldHash string
+	err = tx.QueryRowContext(ctx, `SELECT password_hash FROM users WHERE user_id = $1 FOR UPDATE`, id).Scan(&oldHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("user not found")
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO password_history (user_id, password_hash) VALUES ($1, $2)`, id, oldHash)
+	if err != nil {
+		return fmt.Errorf("failed to record password history: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE users
+		SET password_hash = $1, password_changed_at = NOW(), must_change_password = FALSE, updated_at = NOW()
+		WHERE user_id = $2`, passwordHash, id)
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ListPasswordHistory retrieves the hashes of a user's previous passwords, newest first
+func (r *userRepository) ListPasswordHistory(ctx context.Context, id int, limit int) ([]string, error) {
+	query := `
+		SELECT password_hash
+		FROM password_history
+		WHERE user_id = $1
+		ORDER BY created_at DESC, history_id DESC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, id, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get password history: %w", err)
+	}
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("failed to scan password history: %w", err)
+		}
+		hashes = append(hashes, hash)
+	}
+
+	return hashes, rows.Err()
+}
+
+// SetMustChangePassword sets whether a user has to change their password at the next login
+func (r *userRepository) SetMustChangePassword(ctx context.Context, id int, mustChange bool) error {
+	query := `UPDATE users SET must_change_password = $1, updated_at = NOW() WHERE user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, mustChange, id)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
 // Delete soft deletes a user
 func (r *userRepository) Delete(ctx context.Context, id int) error {
 	query := `UPDATE users SET is_active = FALSE, updated_at = NOW() WHERE user_id = $1`
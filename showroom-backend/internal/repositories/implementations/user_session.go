@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
@@ -21,13 +22,13 @@ func NewUserSessionRepository(db *sql.DB) interfaces.UserSessionRepository {
 // Create creates a new user session
 func (r *userSessionRepository) Create(ctx context.Context, session *user.UserSession) (*user.UserSession, error) {
 	query := `
-		INSERT INTO user_sessions (user_id, session_token, ip_address, user_agent)
-		VALUES ($1, $2, $3, $4)
-		RETURNING session_id, login_at`
+		INSERT INTO user_sessions (user_id, session_token, ip_address, user_agent, password_change_required)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING session_id, login_at, last_activity_at`
 
 	err := r.db.QueryRowContext(ctx, query,
-		session.UserID, session.SessionToken, session.IPAddress, session.UserAgent,
-	).Scan(&session.SessionID, &session.LoginAt)
+		session.UserID, session.SessionToken, session.IPAddress, session.UserAgent, session.PasswordChangeRequired,
+	).Scan(&session.SessionID, &session.LoginAt, &session.LastActivityAt)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
@@ -40,7 +41,8 @@ func (r *userSessionRepository) Create(ctx context.Context, session *user.UserSe
 // GetByToken retrieves a session by token
 func (r *userSessionRepository) GetByToken(ctx context.Context, token string) (*user.UserSession, error) {
 	query := `
-		SELECT session_id, user_id, session_token, login_at, logout_at, ip_address, user_agent, is_active
+		SELECT session_id, user_id, session_token, login_at, logout_at, ip_address, user_agent, is_active,
+		       password_change_required, last_activity_at
 		FROM user_sessions
 		WHERE session_token = $1 AND is_active = TRUE`
 
@@ -48,7 +50,8 @@ func (r *userSessionRepository) GetByToken(ctx context.Context, token string) (*
 	err := r.db.QueryRowContext(ctx, query, token).Scan(
 		&session.SessionID, &session.UserID, &session.SessionToken,
 		&session.LoginAt, &session.LogoutAt, &session.IPAddress,
-		&session.UserAgent, &session.IsActive,
+		&session.UserAgent, &session.IsActive, &session.PasswordChangeRequired,
+		&session.LastActivityAt,
 	)
 
 	if err != nil {
@@ -65,7 +68,8 @@ func (r *userSessionRepository) GetByToken(ctx context.Context, token string) (*
 // GetByID retrieves a session by ID
 func (r *userSessionRepository) GetByID(ctx context.Context, id int) (*user.UserSession, error) {
 	query := `
-		SELECT session_id, user_id, session_token, login_at, logout_at, ip_address, user_agent, is_active
+		SELECT session_id, user_id, session_token, login_at, logout_at, ip_address, user_agent, is_active,
+		       password_change_required, last_activity_at
 		FROM user_sessions
 		WHERE session_id = $1`
 
@@ -73,7 +77,8 @@ func (r *userSessionRepository) GetByID(ctx context.Context, id int) (*user.User
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&session.SessionID, &session.UserID, &session.SessionToken,
 		&session.LoginAt, &session.LogoutAt, &session.IPAddress,
-		&session.UserAgent, &session.IsActive,
+		&session.UserAgent, &session.IsActive, &session.PasswordChangeRequired,
+		&session.LastActivityAt,
 	)
 
 	if err != nil {
@@ -90,7 +95,8 @@ func (r *userSessionRepository) GetByID(ctx context.Context, id int) (*user.User
 // GetActiveByUserID retrieves active sessions for a user
 func (r *userSessionRepository) GetActiveByUserID(ctx context.Context, userID int) ([]user.UserSession, error) {
 	query := `
-		SELECT session_id, user_id, session_token, login_at, logout_at, ip_address, user_agent, is_active
+		SELECT session_id, user_id, session_token, login_at, logout_at, ip_address, user_agent, is_active,
+		       password_change_required, last_activity_at
 		FROM user_sessions
 		WHERE user_id = $1 AND is_active = TRUE
 		ORDER BY login_at DESC`
@@ -107,7 +113,8 @@ func (r *userSessionRepository) GetActiveByUserID(ctx context.Context, userID in
 		err := rows.Scan(
 			&session.SessionID, &session.UserID, &session.SessionToken,
 			&session.LoginAt, &session.LogoutAt, &session.IPAddress,
-			&session.UserAgent, &session.IsActive,
+			&session.UserAgent, &session.IsActive, &session.PasswordChangeRequired,
+			&session.LastActivityAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan session: %w", err)
@@ -122,7 +129,8 @@ func (r *userSessionRepository) GetActiveByUserID(ctx context.Context, userID in
 // GetRecentByUserID retrieves recent sessions for a user
 func (r *userSessionRepository) GetRecentByUserID(ctx context.Context, userID int, limit int) ([]user.UserSession, error) {
 	query := `
-		SELECT session_id, user_id, session_token, login_at, logout_at, ip_address, user_agent, is_active
+		SELECT session_id, user_id, session_token, login_at, logout_at, ip_address, user_agent, is_active,
+		       password_change_required, last_activity_at
 		FROM user_sessions
 		WHERE user_id = $1
 		ORDER BY login_at DESC
@@ -140,7 +148,8 @@ func (r *userSessionRepository) GetRecentByUserID(ctx context.Context, userID in
 		err := rows.Scan(
 			&session.SessionID, &session.UserID, &session.SessionToken,
 			&session.LoginAt, &session.LogoutAt, &session.IPAddress,
-			&session.UserAgent, &session.IsActive,
+			&session.UserAgent, &session.IsActive, &session.PasswordChangeRequired,
+			&session.LastActivityAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan session: %w", err)
@@ -176,6 +185,43 @@ func (r *userSessionRepository) UpdateLogout(ctx context.Context, sessionID int)
 	return nil
 }
 
+// Touch records that a session made a request now
+func (r *userSessionRepository) Touch(ctx context.Context, sessionID int) error {
+	query := `UPDATE user_sessions SET last_activity_at = NOW() WHERE session_id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to update session activity: %w", err)
+	}
+
+	return nil
+}
+
+// EndOldestSessions ends the active sessions of a user beyond their keep most recent ones
+func (r *userSessionRepository) EndOldestSessions(ctx context.Context, userID int, keep int) (int64, error) {
+	query := `
+		UPDATE user_sessions
+		SET logout_at = NOW(), is_active = FALSE
+		WHERE session_id IN (
+			SELECT session_id FROM user_sessions
+			WHERE user_id = $1 AND is_active = TRUE
+			ORDER BY login_at DESC, session_id DESC
+			OFFSET $2
+		)`
+
+	result, err := r.db.ExecContext(ctx, query, userID, keep)
+	if err != nil {
+		return 0, fmt.Errorf("failed to end oldest sessions: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
 // RevokeAllUserSessions revokes all active sessions for a user
 func (r *userSessionRepository) RevokeAllUserSessions(ctx context.Context, userID int) error {
 	query := `
@@ -191,6 +237,22 @@ func (r *userSessionRepository) RevokeAllUserSessions(ctx context.Context, userI
 	return nil
 }
 
+// SetPasswordChangeRequired limits, or stops limiting, the active sessions of a user to changing
+// their password
+func (r *userSessionRepository) SetPasswordChangeRequired(ctx context.Context, userID int, required bool) error {
+	query := `
+		UPDATE user_sessions
+		SET password_change_required = $2
+		WHERE user_id = $1 AND is_active = TRUE`
+
+	_, err := r.db.ExecContext(ctx, query, userID, required)
+	if err != nil {
+		return fmt.Errorf("failed to update user sessions: %w", err)
+	}
+
+	return nil
+}
+
 // ListByUserID retrieves sessions for a user with pagination
 func (r *userSessionRepository) ListByUserID(ctx context.Context, userID int, page, limit int) ([]user.UserSession, int, error) {
 	// Count total sessions
@@ -215,7 +277,8 @@ func (r *userSessionRepository) ListByUserID(ctx context.Context, userID int, pa
 
 	// Get sessions with pagination
 	query := `
-		SELECT session_id, user_id, session_token, login_at, logout_at, ip_address, user_agent, is_active
+		SELECT session_id, user_id, session_token, login_at, logout_at, ip_address, user_agent, is_active,
+		       password_change_required, last_activity_at
 		FROM user_sessions
 		WHERE user_id = $1
 		ORDER BY login_at DESC
@@ -233,7 +296,8 @@ func (r *userSessionRepository) ListByUserID(ctx context.Context, userID int, pa
 		err := rows.Scan(
 			&session.SessionID, &session.UserID, &session.SessionToken,
 			&session.LoginAt, &session.LogoutAt, &session.IPAddress,
-			&session.UserAgent, &session.IsActive,
+			&session.UserAgent, &session.IsActive, &session.PasswordChangeRequired,
+			&session.LastActivityAt,
 		)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan session: %w", err)
@@ -245,6 +309,49 @@ func (r *userSessionRepository) ListByUserID(ctx context.Context, userID int, pa
 	return sessions, total, nil
 }
 
+// ExpireStaleSessions deactivates sessions whose token has outlived maxAge, logging them out at expiry
+func (r *userSessionRepository) ExpireStaleSessions(ctx context.Context, maxAge time.Duration) (int64, error) {
+	query := `
+		UPDATE user_sessions
+		SET is_active = FALSE, logout_at = login_at + $1 * INTERVAL '1 second'
+		WHERE is_active = TRUE
+		AND login_at < NOW() - $1 * INTERVAL '1 second'`
+
+	result, err := r.db.ExecContext(ctx, query, int64(maxAge.Seconds()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire stale sessions: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// ExpireIdleSessions deactivates sessions that made no request for idleTimeout, logging them out
+// when they became idle
+func (r *userSessionRepository) ExpireIdleSessions(ctx context.Context, idleTimeout time.Duration) (int64, error) {
+	query := `
+		UPDATE user_sessions
+		SET is_active = FALSE, logout_at = last_activity_at + $1 * INTERVAL '1 second'
+		WHERE is_active = TRUE
+		AND last_activity_at < NOW() - $1 * INTERVAL '1 second'`
+
+	result, err := r.db.ExecContext(ctx, query, int64(idleTimeout.Seconds()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire idle sessions: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
 // DeleteExpiredSessions deletes expired sessions
 func (r *userSessionRepository) DeleteExpiredSessions(ctx context.Context) error {
 	// Delete sessions that have been inactive for more than 30 days
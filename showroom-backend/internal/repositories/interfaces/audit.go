@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/audit"
+)
+
+// AuditLogRepository defines the interface for reading the audit log. Entries are written by
+// database triggers and cannot be changed, so there is no way to create or modify them here.
+type AuditLogRepository interface {
+	GetByID(ctx context.Context, id int64) (*audit.AuditLogEntry, error)
+	List(ctx context.Context, params *audit.AuditLogFilterParams) ([]audit.AuditLogEntry, int, error)
+}
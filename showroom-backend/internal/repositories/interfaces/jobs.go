@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/jobs"
+)
+
+// JobRunRepository defines the interface for background job run history
+type JobRunRepository interface {
+	Create(ctx context.Context, run *jobs.JobRun) (*jobs.JobRun, error)
+	ClaimScheduled(ctx context.Context, run *jobs.JobRun) (bool, error)
+	Finish(ctx context.Context, run *jobs.JobRun) error
+	FailInterrupted(ctx context.Context, jobName string) (int64, error)
+	List(ctx context.Context, params *jobs.JobRunFilterParams) (*common.PaginatedResponse, error)
+	GetLatestRuns(ctx context.Context) (map[string]jobs.JobRun, error)
+}
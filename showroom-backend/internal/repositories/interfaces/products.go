@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
@@ -74,6 +75,7 @@ type GoodsReceiptRepository interface {
 	GenerateNumber(ctx context.Context) (string, error)
 	IsNumberExists(ctx context.Context, number string) (bool, error)
 	UpdateStatus(ctx context.Context, id int, status products.ReceiptStatus) error
+	Process(ctx context.Context, receipt *products.GoodsReceipt, movements []products.StockMovement, entry *products.JournalEntry) error
 }
 
 // GoodsReceiptDetailRepository defines the interface for goods receipt detail data operations
@@ -97,6 +99,8 @@ type StockMovementRepository interface {
 	GetByReferenceID(ctx context.Context, referenceType products.ReferenceType, referenceID int) ([]products.StockMovement, error)
 	CreateMovementForReceipt(ctx context.Context, productID int, quantity int, unitCost float64, receiptID int, processedBy int) error
 	CreateMovementForAdjustment(ctx context.Context, productID int, quantityChange int, unitCost float64, adjustmentID int, processedBy int) error
+	CreateMovementForReturn(ctx context.Context, productID int, quantity int, unitCost float64, returnID int, processedBy int, fromStock bool) error
+	CreateMovementForLandedCost(ctx context.Context, productID int, quantity int, unitLandedCost float64, voucherID int, processedBy int) error
 	GetMovementHistory(ctx context.Context, productID int, limit int) ([]products.StockMovement, error)
 	GetCurrentStock(ctx context.Context, productID int) (int, error)
 	BulkCreateMovements(ctx context.Context, movements []products.StockMovement) error
@@ -115,21 +119,200 @@ type StockAdjustmentRepository interface {
 	GetVarianceReport(ctx context.Context, params *products.StockAdjustmentFilterParams) (*common.PaginatedResponse, error)
 }
 
-// SupplierPaymentRepository defines the interface for supplier payment data operations
-type SupplierPaymentRepository interface {
-	Create(ctx context.Context, payment *products.SupplierPayment) (*products.SupplierPayment, error)
-	GetByID(ctx context.Context, id int) (*products.SupplierPayment, error)
-	GetByNumber(ctx context.Context, number string) (*products.SupplierPayment, error)
-	Update(ctx context.Context, id int, payment *products.SupplierPayment) (*products.SupplierPayment, error)
+// PaymentTermRepository defines the interface for payment term data operations
+type PaymentTermRepository interface {
+	Create(ctx context.Context, term *products.PaymentTerm) (*products.PaymentTerm, error)
+	GetByID(ctx context.Context, id int) (*products.PaymentTerm, error)
+	Update(ctx context.Context, id int, term *products.PaymentTerm) (*products.PaymentTerm, error)
 	Delete(ctx context.Context, id int) error
-	List(ctx context.Context, params *products.SupplierPaymentFilterParams) (*common.PaginatedResponse, error)
-	GetBySupplierID(ctx context.Context, supplierID int, params *products.SupplierPaymentFilterParams) (*common.PaginatedResponse, error)
-	GetByPOID(ctx context.Context, poID int, params *products.SupplierPaymentFilterParams) (*common.PaginatedResponse, error)
-	GetOverduePayments(ctx context.Context, params *products.SupplierPaymentFilterParams) (*common.PaginatedResponse, error)
-	UpdatePaymentStatus(ctx context.Context, id int, status products.PaymentStatus) error
-	AddPayment(ctx context.Context, id int, amount float64, method products.PaymentMethod, reference *string) error
+	List(ctx context.Context, params *products.PaymentTermFilterParams) (*common.PaginatedResponse, error)
+	IsCodeExists(ctx context.Context, code string, excludeID int) (bool, error)
+}
+
+// SupplierInvoiceRepository defines the interface for supplier invoice data operations
+type SupplierInvoiceRepository interface {
+	Create(ctx context.Context, invoice *products.SupplierInvoice) (*products.SupplierInvoice, error)
+	GetByID(ctx context.Context, id int) (*products.SupplierInvoice, error)
+	GetByIDs(ctx context.Context, ids []int) (map[int]*products.SupplierInvoice, error)
+	Update(ctx context.Context, id int, invoice *products.SupplierInvoice) (*products.SupplierInvoice, error)
+	Delete(ctx context.Context, id int) error
+	List(ctx context.Context, params *products.SupplierInvoiceFilterParams) (*common.PaginatedResponse, error)
+	GetAllocations(ctx context.Context, invoiceID int) ([]products.PaymentAllocation, error)
+	IsInvoiceNumberExists(ctx context.Context, supplierID int, invoiceNumber string, excludeID int) (bool, error)
+	UpdateStatus(ctx context.Context, id int, status products.PaymentStatus) error
+	RefreshStatus(ctx context.Context, id int) error
+	UpdateOverdueStatus(ctx context.Context) (int64, error)
+	AccruePenalties(ctx context.Context, dailyRate float64) (int64, error)
+	GetSummary(ctx context.Context, supplierID *int) (map[string]interface{}, error)
+	GetAgingInvoices(ctx context.Context, asOf time.Time, supplierID *int) ([]products.APAgingInvoice, error)
+	GetDiscountOpportunities(ctx context.Context, asOf time.Time, supplierID *int) ([]products.EarlyPaymentDiscountOpportunity, error)
+}
+
+// PaymentVoucherRepository defines the interface for supplier payment voucher data operations
+type PaymentVoucherRepository interface {
+	Create(ctx context.Context, voucher *products.PaymentVoucher, allocations []products.PaymentAllocationRequest) (*products.PaymentVoucher, error)
+	GetByID(ctx context.Context, id int) (*products.PaymentVoucher, error)
+	Update(ctx context.Context, id int, voucher *products.PaymentVoucher) (*products.PaymentVoucher, error)
+	List(ctx context.Context, params *products.PaymentVoucherFilterParams) (*common.PaginatedResponse, error)
+	Allocate(ctx context.Context, id int, allocations []products.PaymentAllocationRequest) (*products.PaymentVoucher, error)
+	Void(ctx context.Context, id int) error
 	GenerateNumber(ctx context.Context) (string, error)
-	IsNumberExists(ctx context.Context, number string) (bool, error)
-	UpdateOverdueStatus(ctx context.Context) error
-	GetPaymentSummary(ctx context.Context, supplierID *int) (map[string]interface{}, error)
-}
\ No newline at end of file
+}
+
+// PaymentRunRepository defines the interface for supplier payment run data operations
+type PaymentRunRepository interface {
+	GetCandidates(ctx context.Context, dueBefore time.Time, currencyCode string, supplierID *int) ([]products.PaymentRunCandidate, error)
+	Create(ctx context.Context, run *products.PaymentRun, lines []products.PaymentRunLine) (*products.PaymentRun, error)
+	GetByID(ctx context.Context, id int) (*products.PaymentRun, error)
+	List(ctx context.Context, params *products.PaymentRunFilterParams) (*common.PaginatedResponse, error)
+	RemoveLine(ctx context.Context, runID, lineID int) error
+	Approve(ctx context.Context, id int, approvedBy int) error
+	Cancel(ctx context.Context, id int) error
+	Execute(ctx context.Context, id int, executedBy int) error
+	FailLine(ctx context.Context, runID, lineID int, reason string) error
+	GenerateNumber(ctx context.Context) (string, error)
+}
+
+// BankStatementRepository defines the interface for bank statement and reconciliation data operations
+type BankStatementRepository interface {
+	Import(ctx context.Context, statement *products.BankStatement, lines []products.BankStatementLine) (*products.BankStatement, error)
+	GetByID(ctx context.Context, id int, params *products.BankStatementLineFilterParams) (*products.BankStatement, error)
+	List(ctx context.Context, params *products.BankStatementFilterParams) (*common.PaginatedResponse, error)
+	Delete(ctx context.Context, id int) error
+	GetLine(ctx context.Context, lineID int) (*products.BankStatementLine, error)
+	GetMatchCandidates(ctx context.Context, dateFrom, dateTo time.Time) ([]products.BankMatchCandidate, error)
+	GetMatchCandidate(ctx context.Context, voucherID int) (*products.BankMatchCandidate, error)
+	GetReceiptMatchCandidate(ctx context.Context, receiptID int) (*products.BankMatchCandidate, error)
+	ApplyMatches(ctx context.Context, matches []products.BankStatementMatch, method products.BankMatchMethod, matchedBy int) (int, error)
+	Unmatch(ctx context.Context, lineID int) error
+	GetReconciliation(ctx context.Context, params *products.BankReconciliationParams) (*products.BankReconciliationReport, error)
+}
+
+// GLAccountRepository defines the interface for chart of accounts and posting rule data operations
+type GLAccountRepository interface {
+	Create(ctx context.Context, account *products.GLAccount) (*products.GLAccount, error)
+	GetByID(ctx context.Context, id int) (*products.GLAccount, error)
+	Update(ctx context.Context, id int, account *products.GLAccount) (*products.GLAccount, error)
+	List(ctx context.Context, params *products.GLAccountFilterParams) (*common.PaginatedResponse, error)
+	IsCodeExists(ctx context.Context, code string) (bool, error)
+	IsPostingRuleAccount(ctx context.Context, id int) (bool, error)
+	GetPostingRules(ctx context.Context) ([]products.GLPostingRule, error)
+	UpdatePostingRule(ctx context.Context, rule products.PostingRule, accountID int) error
+}
+
+// JournalRepository defines the interface for general ledger journal and period data operations
+type JournalRepository interface {
+	Post(ctx context.Context, entry *products.JournalEntry) (*products.JournalEntry, error)
+	Reverse(ctx context.Context, id int, entryDate time.Time, postedBy int) (*products.JournalEntry, error)
+	GetByID(ctx context.Context, id int) (*products.JournalEntry, error)
+	List(ctx context.Context, params *products.JournalEntryFilterParams) (*common.PaginatedResponse, error)
+	GetTrialBalance(ctx context.Context, params *products.TrialBalanceParams) (*products.TrialBalance, error)
+	CheckPeriodOpen(ctx context.Context, date time.Time) error
+	ListPeriods(ctx context.Context, year int) ([]products.AccountingPeriod, error)
+	SetPeriodStatus(ctx context.Context, periodStart time.Time, status products.AccountingPeriodStatus, changedBy int) error
+}
+
+// TaxCodeRepository defines the interface for tax code data operations and VAT reporting
+type TaxCodeRepository interface {
+	Create(ctx context.Context, taxCode *products.TaxCode) (*products.TaxCode, error)
+	GetByID(ctx context.Context, id int) (*products.TaxCode, error)
+	Update(ctx context.Context, id int, taxCode *products.TaxCode) (*products.TaxCode, error)
+	List(ctx context.Context, params *products.TaxCodeFilterParams) (*common.PaginatedResponse, error)
+	IsCodeExists(ctx context.Context, code string) (bool, error)
+	GetVATReport(ctx context.Context, periodStart time.Time) (*products.VATReport, error)
+}
+
+// ExchangeRateRepository defines the interface for dated exchange rate data operations
+type ExchangeRateRepository interface {
+	Upsert(ctx context.Context, rate *products.ExchangeRate) (*products.ExchangeRate, error)
+	BulkUpsert(ctx context.Context, rates []products.ExchangeRate, createdBy int) error
+	List(ctx context.Context, params *products.ExchangeRateFilterParams) (*common.PaginatedResponse, error)
+	GetRate(ctx context.Context, currencyCode string, date time.Time) (float64, error)
+}
+
+// SalesInvoiceRepository defines the interface for sales invoice data operations
+type SalesInvoiceRepository interface {
+	Create(ctx context.Context, invoice *products.SalesInvoice) (*products.SalesInvoice, error)
+	GetByID(ctx context.Context, id int) (*products.SalesInvoice, error)
+	List(ctx context.Context, params *products.SalesInvoiceFilterParams) (*common.PaginatedResponse, error)
+	SetTaxInvoiceNumber(ctx context.Context, id int, taxInvoiceNumber string) error
+	Void(ctx context.Context, id int, voidedBy int) error
+	ListForEFaktur(ctx context.Context, periodStart time.Time) ([]products.SalesInvoice, error)
+	GetOpenInvoices(ctx context.Context, customerID int, asOf time.Time) ([]products.SalesInvoice, error)
+	GenerateNumber(ctx context.Context) (string, error)
+}
+
+// CustomerReceiptRepository defines the interface for customer receipt data operations
+type CustomerReceiptRepository interface {
+	Create(ctx context.Context, receipt *products.CustomerReceipt, allocations []products.CustomerReceiptAllocationRequest) (*products.CustomerReceipt, error)
+	GetByID(ctx context.Context, id int) (*products.CustomerReceipt, error)
+	List(ctx context.Context, params *products.CustomerReceiptFilterParams) (*common.PaginatedResponse, error)
+	Allocate(ctx context.Context, id int, allocations []products.CustomerReceiptAllocationRequest) (*products.CustomerReceipt, error)
+	Void(ctx context.Context, id int, voidedBy int) error
+	GetStatementActivity(ctx context.Context, customerID int, periodStart, periodEnd time.Time) (float64, []products.CustomerStatementLine, error)
+}
+
+// PurchaseReturnRepository defines the interface for purchase return data operations
+type PurchaseReturnRepository interface {
+	Create(ctx context.Context, purchaseReturn *products.PurchaseReturn, details []products.PurchaseReturnDetail) (*products.PurchaseReturn, error)
+	GetByID(ctx context.Context, id int) (*products.PurchaseReturn, error)
+	GetByNumber(ctx context.Context, number string) (*products.PurchaseReturn, error)
+	GetDetails(ctx context.Context, returnID int) ([]products.PurchaseReturnDetail, error)
+	List(ctx context.Context, params *products.PurchaseReturnFilterParams) (*common.PaginatedResponse, error)
+	Approve(ctx context.Context, id int, approvedBy int, movements []products.StockMovement, debitNote *products.SupplierDebitNote) error
+	UpdateStatus(ctx context.Context, id int, status products.ReturnStatus) error
+	UpdateShipment(ctx context.Context, id int, purchaseReturn *products.PurchaseReturn) error
+	GetReturnedQuantity(ctx context.Context, receiptDetailID int) (int, error)
+	GenerateNumber(ctx context.Context) (string, error)
+}
+
+// SupplierDebitNoteRepository defines the interface for supplier debit note data operations
+type SupplierDebitNoteRepository interface {
+	Create(ctx context.Context, debitNote *products.SupplierDebitNote) (*products.SupplierDebitNote, error)
+	GetByID(ctx context.Context, id int) (*products.SupplierDebitNote, error)
+	GetByReturnID(ctx context.Context, returnID int) (*products.SupplierDebitNote, error)
+	List(ctx context.Context, params *products.SupplierDebitNoteFilterParams) (*common.PaginatedResponse, error)
+	GetApplications(ctx context.Context, debitNoteID int) ([]products.SupplierDebitNoteApplication, error)
+	ApplyToOutstanding(ctx context.Context, id int, appliedBy int) ([]products.SupplierDebitNoteApplication, error)
+	GenerateNumber(ctx context.Context) (string, error)
+}
+
+// LandedCostRepository defines the interface for landed cost voucher data operations
+type LandedCostRepository interface {
+	Create(ctx context.Context, voucher *products.LandedCostVoucher) (*products.LandedCostVoucher, error)
+	GetByID(ctx context.Context, id int) (*products.LandedCostVoucher, error)
+	List(ctx context.Context, params *products.LandedCostVoucherFilterParams) (*common.PaginatedResponse, error)
+	GetAllocations(ctx context.Context, voucherID int) ([]products.LandedCostAllocation, error)
+	Post(ctx context.Context, id int, allocations []products.LandedCostAllocation, postedBy int) error
+	UpdateStatus(ctx context.Context, id int, status products.LandedCostStatus) error
+	GetUnitLandedCosts(ctx context.Context, receiptID int) (map[int]float64, error)
+	GenerateNumber(ctx context.Context) (string, error)
+}
+
+// BlanketOrderRepository defines the interface for blanket order terms and release data operations
+type BlanketOrderRepository interface {
+	CreateTerms(ctx context.Context, terms *products.BlanketOrderTerms) (*products.BlanketOrderTerms, error)
+	GetTerms(ctx context.Context, poID int) (*products.BlanketOrderTerms, error)
+	GetLineBalances(ctx context.Context, blanketPOID int) ([]products.BlanketLineBalance, error)
+	CreateRelease(ctx context.Context, release *products.BlanketRelease, releasePO *products.PurchaseOrderParts, details []products.PurchaseOrderDetail) (*products.BlanketRelease, error)
+	CancelRelease(ctx context.Context, releasePOID int) (bool, error)
+	ListReleases(ctx context.Context, blanketPOID int) ([]products.BlanketRelease, error)
+}
+
+// SupplierPriceListRepository defines the interface for supplier price list data operations
+type SupplierPriceListRepository interface {
+	Create(ctx context.Context, priceList *products.SupplierPriceList) (*products.SupplierPriceList, error)
+	GetByID(ctx context.Context, id int) (*products.SupplierPriceList, error)
+	Update(ctx context.Context, id int, priceList *products.SupplierPriceList) (*products.SupplierPriceList, error)
+	Delete(ctx context.Context, id int) error
+	List(ctx context.Context, params *products.SupplierPriceListFilterParams) (*common.PaginatedResponse, error)
+	FindActive(ctx context.Context, supplierID, productID int, currency string, at time.Time) (*products.SupplierPriceList, error)
+	HasOverlap(ctx context.Context, priceList *products.SupplierPriceList) (bool, error)
+	RecordPriceHistory(ctx context.Context, history *products.SupplierPriceHistory) error
+	GetPriceHistory(ctx context.Context, params *products.SupplierPriceHistoryFilterParams) (*common.PaginatedResponse, error)
+}
+
+// SupplierScorecardRepository defines the interface for supplier performance data
+type SupplierScorecardRepository interface {
+	GetPerformanceMetrics(ctx context.Context, params *products.SupplierScorecardParams, supplierIDs []int) ([]products.SupplierPerformanceMetrics, error)
+}
@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
 )
@@ -14,6 +15,9 @@ type UserRepository interface {
 	GetByUsername(ctx context.Context, username string) (*user.User, error)
 	GetByEmail(ctx context.Context, email string) (*user.User, error)
 	Update(ctx context.Context, id int, user *user.User) (*user.User, error)
+	UpdatePassword(ctx context.Context, id int, passwordHash string) error
+	ListPasswordHistory(ctx context.Context, id int, limit int) ([]string, error)
+	SetMustChangePassword(ctx context.Context, id int, mustChange bool) error
 	Delete(ctx context.Context, id int) error
 	
 	// List and filtering operations
@@ -40,11 +44,86 @@ type UserSessionRepository interface {
 	GetRecentByUserID(ctx context.Context, userID int, limit int) ([]user.UserSession, error)
 	UpdateLogout(ctx context.Context, sessionID int) error
 	RevokeAllUserSessions(ctx context.Context, userID int) error
+	SetPasswordChangeRequired(ctx context.Context, userID int, required bool) error
+	Touch(ctx context.Context, sessionID int) error
+	EndOldestSessions(ctx context.Context, userID int, keep int) (int64, error)
 	
 	// List operations with pagination
 	ListByUserID(ctx context.Context, userID int, page, limit int) ([]user.UserSession, int, error)
 	
 	// Cleanup operations
+	ExpireStaleSessions(ctx context.Context, maxAge time.Duration) (int64, error)
+	ExpireIdleSessions(ctx context.Context, idleTimeout time.Duration) (int64, error)
 	DeleteExpiredSessions(ctx context.Context) error
 	DeleteInactiveSessions(ctx context.Context, days int) error
-}
\ No newline at end of file
+}
+// RolePermissionRepository defines the interface for the permissions granted to roles
+type RolePermissionRepository interface {
+	GetByRole(ctx context.Context, role string) ([]string, error)
+	ListAll(ctx context.Context) (map[string][]string, error)
+	ReplaceForRole(ctx context.Context, role string, permissions []string, grantedBy int) error
+}
+
+// RefreshTokenRepository defines the interface for session refresh token operations
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *user.RefreshToken) error
+	Rotate(ctx context.Context, tokenHash string, next *user.RefreshToken, check func(session *user.UserSession, userActive bool) error) error
+}
+
+// TwoFactorRepository defines the interface for TOTP enrollments and recovery codes
+type TwoFactorRepository interface {
+	GetByUserID(ctx context.Context, userID int) (*user.UserTwoFactor, error)
+	SavePending(ctx context.Context, userID int, secret string) error
+	Enable(ctx context.Context, userID int, step int64, recoveryCodeHashes []string) error
+	MarkStepUsed(ctx context.Context, userID int, step int64) (bool, error)
+	Delete(ctx context.Context, userID int) error
+
+	ListUnusedRecoveryCodes(ctx context.Context, userID int) ([]user.RecoveryCode, error)
+	UseRecoveryCode(ctx context.Context, codeID int) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error
+}
+
+// LoginChallengeRepository defines the interface for logins waiting for a second factor
+type LoginChallengeRepository interface {
+	Create(ctx context.Context, challenge *user.LoginChallenge) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*user.LoginChallenge, error)
+	RecordFailedAttempt(ctx context.Context, challengeID int) error
+	Consume(ctx context.Context, challengeID int) (bool, error)
+}
+
+// LoginAttemptRepository defines the interface for the login attempts log and account lockout
+type LoginAttemptRepository interface {
+	Create(ctx context.Context, attempt *user.LoginAttempt) error
+	List(ctx context.Context, params *user.LoginAttemptFilterParams) ([]user.LoginAttempt, int, error)
+	CountIPFailures(ctx context.Context, ipAddress string, since time.Time) (int, *time.Time, error)
+	CountPasswordResetRequests(ctx context.Context, username, ipAddress string, since time.Time) (*user.PasswordResetRequests, error)
+
+	GetLock(ctx context.Context, userID int) (*user.LoginLock, error)
+	RecordFailure(ctx context.Context, userID int, lockAfter int, lockFor time.Duration) (*user.LoginLock, error)
+	ResetLock(ctx context.Context, userID int) error
+}
+
+// PasswordResetRepository defines the interface for self-service password reset tokens
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *user.PasswordResetToken) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*user.PasswordResetToken, error)
+	Consume(ctx context.Context, tokenID int) (bool, error)
+	CountSince(ctx context.Context, userID int, since time.Time) (int, error)
+}
+
+// APIKeyRepository defines the interface for integration API key operations
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *user.APIKey) error
+	GetByID(ctx context.Context, id int) (*user.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*user.APIKey, error)
+	List(ctx context.Context, params *user.APIKeyFilterParams) ([]user.APIKey, int, error)
+	Revoke(ctx context.Context, id int, revokedBy int) error
+	TouchLastUsed(ctx context.Context, id int, ipAddress string) error
+}
+
+// SigningKeyRepository defines the interface for access token signing key operations
+type SigningKeyRepository interface {
+	ListUsable(ctx context.Context) ([]user.SigningKey, error)
+	Rotate(ctx context.Context, key *user.SigningKey, retireAfter time.Duration) error
+	DeleteRetired(ctx context.Context, before time.Time) (int64, error)
+}
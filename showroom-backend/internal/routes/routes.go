@@ -11,6 +11,7 @@ import (
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/handlers/auth"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/handlers/products"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/middleware"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
 )
@@ -29,7 +30,31 @@ type Router struct {
 	goodsReceiptHandler       *products.GoodsReceiptHandler
 	stockMovementHandler      *products.StockMovementHandler
 	stockAdjustmentHandler    *products.StockAdjustmentHandler
-	supplierPaymentHandler    *products.SupplierPaymentHandler
+	supplierInvoiceHandler    *products.SupplierInvoiceHandler
+	paymentVoucherHandler     *products.PaymentVoucherHandler
+	purchaseReturnHandler     *products.PurchaseReturnHandler
+	landedCostHandler         *products.LandedCostHandler
+	supplierPriceListHandler  *products.SupplierPriceListHandler
+	supplierScorecardHandler  *products.SupplierScorecardHandler
+	paymentTermHandler        *products.PaymentTermHandler
+	jobHandler                *admin.JobHandler
+	paymentRunHandler         *products.PaymentRunHandler
+	bankStatementHandler      *products.BankStatementHandler
+	generalLedgerHandler      *products.GeneralLedgerHandler
+	taxHandler                *products.TaxHandler
+	salesInvoiceHandler       *products.SalesInvoiceHandler
+	exchangeRateHandler       *products.ExchangeRateHandler
+	customerAccountHandler    *products.CustomerAccountHandler
+	roleHandler               *admin.RoleHandler
+	permissionChecker         middleware.PermissionChecker
+	apiKeys                   middleware.APIKeyAuthenticator
+	twoFactorHandler          *auth.TwoFactorHandler
+	loginAttemptHandler       *admin.LoginAttemptHandler
+	passwordResetHandler      *auth.PasswordResetHandler
+	passwordPolicyHandler     *auth.PasswordPolicyHandler
+	apiKeyHandler             *admin.APIKeyHandler
+	auditHandler              *admin.AuditHandler
+	sessionHandler            *auth.SessionHandler
 	jwtManager                *utils.JWTManager
 	sessionRepo               interfaces.UserSessionRepository
 	config                    *config.Config
@@ -49,7 +74,31 @@ func NewRouter(
 	goodsReceiptHandler *products.GoodsReceiptHandler,
 	stockMovementHandler *products.StockMovementHandler,
 	stockAdjustmentHandler *products.StockAdjustmentHandler,
-	supplierPaymentHandler *products.SupplierPaymentHandler,
+	supplierInvoiceHandler *products.SupplierInvoiceHandler,
+	paymentVoucherHandler *products.PaymentVoucherHandler,
+	purchaseReturnHandler *products.PurchaseReturnHandler,
+	landedCostHandler *products.LandedCostHandler,
+	supplierPriceListHandler *products.SupplierPriceListHandler,
+	supplierScorecardHandler *products.SupplierScorecardHandler,
+	paymentTermHandler *products.PaymentTermHandler,
+	jobHandler *admin.JobHandler,
+	paymentRunHandler *products.PaymentRunHandler,
+	bankStatementHandler *products.BankStatementHandler,
+	generalLedgerHandler *products.GeneralLedgerHandler,
+	taxHandler *products.TaxHandler,
+	salesInvoiceHandler *products.SalesInvoiceHandler,
+	exchangeRateHandler *products.ExchangeRateHandler,
+	customerAccountHandler *products.CustomerAccountHandler,
+	roleHandler *admin.RoleHandler,
+	twoFactorHandler *auth.TwoFactorHandler,
+	loginAttemptHandler *admin.LoginAttemptHandler,
+	passwordResetHandler *auth.PasswordResetHandler,
+	passwordPolicyHandler *auth.PasswordPolicyHandler,
+	apiKeyHandler *admin.APIKeyHandler,
+	auditHandler *admin.AuditHandler,
+	sessionHandler *auth.SessionHandler,
+	permissionChecker middleware.PermissionChecker,
+	apiKeys middleware.APIKeyAuthenticator,
 	jwtManager *utils.JWTManager,
 	sessionRepo interfaces.UserSessionRepository,
 	config *config.Config,
@@ -67,7 +116,31 @@ func NewRouter(
 		goodsReceiptHandler:       goodsReceiptHandler,
 		stockMovementHandler:      stockMovementHandler,
 		stockAdjustmentHandler:    stockAdjustmentHandler,
-		supplierPaymentHandler:    supplierPaymentHandler,
+		supplierInvoiceHandler:    supplierInvoiceHandler,
+		paymentVoucherHandler:     paymentVoucherHandler,
+		purchaseReturnHandler:     purchaseReturnHandler,
+		landedCostHandler:         landedCostHandler,
+		supplierPriceListHandler:  supplierPriceListHandler,
+		supplierScorecardHandler:  supplierScorecardHandler,
+		paymentTermHandler:        paymentTermHandler,
+		jobHandler:                jobHandler,
+		paymentRunHandler:         paymentRunHandler,
+		bankStatementHandler:      bankStatementHandler,
+		generalLedgerHandler:      generalLedgerHandler,
+		taxHandler:                taxHandler,
+		salesInvoiceHandler:       salesInvoiceHandler,
+		exchangeRateHandler:       exchangeRateHandler,
+		customerAccountHandler:    customerAccountHandler,
+		roleHandler:               roleHandler,
+		permissionChecker:         permissionChecker,
+		apiKeys:                   apiKeys,
+		twoFactorHandler:          twoFactorHandler,
+		loginAttemptHandler:       loginAttemptHandler,
+		passwordResetHandler:      passwordResetHandler,
+		passwordPolicyHandler:     passwordPolicyHandler,
+		apiKeyHandler:             apiKeyHandler,
+		auditHandler:              auditHandler,
+		sessionHandler:            sessionHandler,
 		jwtManager:                jwtManager,
 		sessionRepo:               sessionRepo,
 		config:                    config,
@@ -88,202 +161,439 @@ func (r *Router) SetupRoutes() *gin.Engine {
 	router.Use(middleware.ErrorHandlerMiddleware())
 	router.Use(middleware.CORSMiddleware())
 	router.Use(middleware.SecurityHeadersMiddleware())
+	router.Use(middleware.AuditContextMiddleware())
 
 	// Health check endpoint (no auth required)
 	router.GET("/api/v1/health", r.healthCheck)
 
+	// Public keys other services verify access tokens with (no auth required)
+	router.GET("/.well-known/jwks.json", r.jwks)
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
+	idleTimeout := r.config.Auth.GetSessionIdleTimeout()
 
 	// Authentication routes (no auth required)
 	authGroup := v1.Group("/auth")
 	{
 		authGroup.POST("/login", r.authHandler.Login)
+		authGroup.POST("/login/verify", r.authHandler.VerifyLogin)
+		authGroup.POST("/login/enroll", r.authHandler.EnrollLogin)
+		authGroup.POST("/refresh", r.authHandler.RefreshToken)
+		authGroup.POST("/forgot-password", r.passwordResetHandler.ForgotPassword)
+		authGroup.POST("/reset-password", r.passwordResetHandler.ResetPassword)
+		authGroup.GET("/password-policy", r.passwordPolicyHandler.Policy)
 		
-		// Protected auth routes
-		authProtected := authGroup.Use(middleware.AuthMiddleware(r.jwtManager, r.sessionRepo))
+		// Protected auth routes. A session that must change its password can only log out,
+		// read the current user and change the password.
+		authProtected := authGroup.Use(middleware.AuthMiddleware(r.jwtManager, r.sessionRepo, nil, idleTimeout))
+		current := middleware.RequireCurrentPassword()
 		{
 			authProtected.POST("/logout", r.authHandler.Logout)
 			authProtected.GET("/me", r.authHandler.Me)
-			authProtected.GET("/profile", r.authHandler.Profile)
+			authProtected.GET("/profile", current, r.authHandler.Profile)
 			authProtected.POST("/change-password", r.authHandler.ChangePassword)
-			authProtected.POST("/refresh", r.authHandler.RefreshToken)
+			authProtected.GET("/permissions", current, r.roleHandler.MyPermissions)
+
+			// Devices the user is logged in on
+			authProtected.GET("/sessions", current, r.sessionHandler.ListSessions)
+			authProtected.DELETE("/sessions/:id", current, r.sessionHandler.EndSession)
+
+			// Two-factor authentication
+			authProtected.GET("/2fa", current, r.twoFactorHandler.Status)
+			authProtected.POST("/2fa/enroll", current, r.twoFactorHandler.Enroll)
+			authProtected.POST("/2fa/confirm", current, r.twoFactorHandler.Confirm)
+			authProtected.POST("/2fa/disable", current, r.twoFactorHandler.Disable)
+			authProtected.POST("/2fa/recovery-codes", current, r.twoFactorHandler.RegenerateRecoveryCodes)
 		}
 	}
 
-	// Admin routes (admin role required)
+	// Business routes. Each route requires a permission; the admin role holds every
+	// permission and other roles are granted theirs through /admin/roles. Integrations can
+	// call these routes with an API key, limited to the key's scopes.
+	can := func(permission string) gin.HandlerFunc {
+		return middleware.RequirePermission(r.permissionChecker, permission)
+	}
+
 	adminGroup := v1.Group("/admin")
-	adminGroup.Use(middleware.AuthMiddleware(r.jwtManager, r.sessionRepo))
-	adminGroup.Use(middleware.RequireRole("admin"))
+	adminGroup.Use(middleware.AuthMiddleware(r.jwtManager, r.sessionRepo, r.apiKeys, idleTimeout), middleware.RequireCurrentPassword())
 	{
 		// User management
 		userGroup := adminGroup.Group("/users")
 		{
-			userGroup.POST("", r.adminHandler.CreateUser)
-			userGroup.GET("", r.adminHandler.GetUsers)
-			userGroup.GET("/:id", r.adminHandler.GetUser)
-			userGroup.PUT("/:id", r.adminHandler.UpdateUser)
-			userGroup.DELETE("/:id", r.adminHandler.DeleteUser)
-			userGroup.GET("/role/:role", r.adminHandler.GetUsersByRole)
-			userGroup.GET("/:id/sessions", r.adminHandler.GetUserSessions)
-			userGroup.DELETE("/:id/sessions", r.adminHandler.RevokeUserSessions)
+			userGroup.POST("", can(user.PermUserManage), r.adminHandler.CreateUser)
+			userGroup.GET("", can(user.PermUserManage), r.adminHandler.GetUsers)
+			userGroup.GET("/:id", can(user.PermUserManage), r.adminHandler.GetUser)
+			userGroup.PUT("/:id", can(user.PermUserManage), r.adminHandler.UpdateUser)
+			userGroup.DELETE("/:id", can(user.PermUserManage), r.adminHandler.DeleteUser)
+			userGroup.GET("/role/:role", can(user.PermUserManage), r.adminHandler.GetUsersByRole)
+			userGroup.GET("/:id/sessions", can(user.PermUserManage), r.adminHandler.GetUserSessions)
+			userGroup.DELETE("/:id/sessions", can(user.PermUserManage), r.adminHandler.RevokeUserSessions)
+			userGroup.DELETE("/:id/2fa", can(user.PermUserManage), r.twoFactorHandler.ResetUser)
+			userGroup.GET("/:id/login-lock", can(user.PermUserManage), r.loginAttemptHandler.GetLock)
+			userGroup.POST("/:id/unlock", can(user.PermUserManage), r.loginAttemptHandler.Unlock)
+			userGroup.POST("/:id/force-password-change", can(user.PermUserManage), r.passwordPolicyHandler.ForceChange)
+		}
+
+		// Login attempts log
+		loginAttemptGroup := adminGroup.Group("/login-attempts")
+		{
+			loginAttemptGroup.GET("", can(user.PermUserManage), r.loginAttemptHandler.ListAttempts)
+		}
+
+		// Roles and permissions
+		roleGroup := adminGroup.Group("/roles")
+		{
+			roleGroup.GET("", can(user.PermRoleManage), r.roleHandler.ListRoles)
+			roleGroup.GET("/:role", can(user.PermRoleManage), r.roleHandler.GetRole)
+			roleGroup.PUT("/:role/permissions", can(user.PermRoleManage), r.roleHandler.UpdateRolePermissions)
+		}
+		adminGroup.GET("/permissions", can(user.PermRoleManage), r.roleHandler.ListPermissions)
+
+		// API keys for integrations
+		apiKeyGroup := adminGroup.Group("/api-keys")
+		{
+			apiKeyGroup.POST("", can(user.PermAPIKeyManage), r.apiKeyHandler.CreateAPIKey)
+			apiKeyGroup.GET("", can(user.PermAPIKeyManage), r.apiKeyHandler.GetAPIKeys)
+			apiKeyGroup.GET("/:id", can(user.PermAPIKeyManage), r.apiKeyHandler.GetAPIKey)
+			apiKeyGroup.DELETE("/:id", can(user.PermAPIKeyManage), r.apiKeyHandler.RevokeAPIKey)
+		}
+
+		// Audit log of data changes
+		auditGroup := adminGroup.Group("/audit-logs")
+		{
+			auditGroup.GET("", can(user.PermAuditRead), r.auditHandler.GetAuditLogs)
+			auditGroup.GET("/:id", can(user.PermAuditRead), r.auditHandler.GetAuditLog)
 		}
 
 		// Customer management
 		customerGroup := adminGroup.Group("/customers")
 		{
-			customerGroup.POST("", r.customerHandler.CreateCustomer)
-			customerGroup.GET("", r.customerHandler.GetCustomers)
-			customerGroup.GET("/:id", r.customerHandler.GetCustomer)
-			customerGroup.PUT("/:id", r.customerHandler.UpdateCustomer)
-			customerGroup.DELETE("/:id", r.customerHandler.DeleteCustomer)
+			customerGroup.POST("", can(user.PermCustomerWrite), r.customerHandler.CreateCustomer)
+			customerGroup.GET("", can(user.PermCustomerRead), r.customerHandler.GetCustomers)
+			customerGroup.GET("/:id", can(user.PermCustomerRead), r.customerHandler.GetCustomer)
+			customerGroup.PUT("/:id", can(user.PermCustomerWrite), r.customerHandler.UpdateCustomer)
+			customerGroup.DELETE("/:id", can(user.PermCustomerWrite), r.customerHandler.DeleteCustomer)
 		}
 
 		// Supplier management
 		supplierGroup := adminGroup.Group("/suppliers")
 		{
-			supplierGroup.POST("", r.supplierHandler.CreateSupplier)
-			supplierGroup.GET("", r.supplierHandler.GetSuppliers)
-			supplierGroup.GET("/:id", r.supplierHandler.GetSupplier)
-			supplierGroup.PUT("/:id", r.supplierHandler.UpdateSupplier)
-			supplierGroup.DELETE("/:id", r.supplierHandler.DeleteSupplier)
+			supplierGroup.POST("", can(user.PermSupplierWrite), r.supplierHandler.CreateSupplier)
+			supplierGroup.GET("", can(user.PermSupplierRead), r.supplierHandler.GetSuppliers)
+			supplierGroup.GET("/:id", can(user.PermSupplierRead), r.supplierHandler.GetSupplier)
+			supplierGroup.PUT("/:id", can(user.PermSupplierWrite), r.supplierHandler.UpdateSupplier)
+			supplierGroup.DELETE("/:id", can(user.PermSupplierWrite), r.supplierHandler.DeleteSupplier)
+			supplierGroup.GET("/:id/scorecard", can(user.PermSupplierRead), r.supplierScorecardHandler.GetScorecard)
+			supplierGroup.GET("/scorecards/compare", can(user.PermSupplierRead), r.supplierScorecardHandler.CompareSuppliers)
 		}
 
 		// Vehicle brand management
 		vehicleBrandGroup := adminGroup.Group("/vehicle-brands")
 		{
-			vehicleBrandGroup.POST("", r.vehicleMasterHandler.CreateVehicleBrand)
-			vehicleBrandGroup.GET("", r.vehicleMasterHandler.GetVehicleBrands)
-			vehicleBrandGroup.GET("/:id", r.vehicleMasterHandler.GetVehicleBrand)
-			vehicleBrandGroup.PUT("/:id", r.vehicleMasterHandler.UpdateVehicleBrand)
-			vehicleBrandGroup.DELETE("/:id", r.vehicleMasterHandler.DeleteVehicleBrand)
+			vehicleBrandGroup.POST("", can(user.PermVehicleWrite), r.vehicleMasterHandler.CreateVehicleBrand)
+			vehicleBrandGroup.GET("", can(user.PermVehicleRead), r.vehicleMasterHandler.GetVehicleBrands)
+			vehicleBrandGroup.GET("/:id", can(user.PermVehicleRead), r.vehicleMasterHandler.GetVehicleBrand)
+			vehicleBrandGroup.PUT("/:id", can(user.PermVehicleWrite), r.vehicleMasterHandler.UpdateVehicleBrand)
+			vehicleBrandGroup.DELETE("/:id", can(user.PermVehicleWrite), r.vehicleMasterHandler.DeleteVehicleBrand)
 		}
 
 		// Vehicle category management
 		vehicleCategoryGroup := adminGroup.Group("/vehicle-categories")
 		{
-			vehicleCategoryGroup.POST("", r.vehicleMasterHandler.CreateVehicleCategory)
-			vehicleCategoryGroup.GET("", r.vehicleMasterHandler.GetVehicleCategories)
-			vehicleCategoryGroup.GET("/:id", r.vehicleMasterHandler.GetVehicleCategory)
-			vehicleCategoryGroup.PUT("/:id", r.vehicleMasterHandler.UpdateVehicleCategory)
-			vehicleCategoryGroup.DELETE("/:id", r.vehicleMasterHandler.DeleteVehicleCategory)
+			vehicleCategoryGroup.POST("", can(user.PermVehicleWrite), r.vehicleMasterHandler.CreateVehicleCategory)
+			vehicleCategoryGroup.GET("", can(user.PermVehicleRead), r.vehicleMasterHandler.GetVehicleCategories)
+			vehicleCategoryGroup.GET("/:id", can(user.PermVehicleRead), r.vehicleMasterHandler.GetVehicleCategory)
+			vehicleCategoryGroup.PUT("/:id", can(user.PermVehicleWrite), r.vehicleMasterHandler.UpdateVehicleCategory)
+			vehicleCategoryGroup.DELETE("/:id", can(user.PermVehicleWrite), r.vehicleMasterHandler.DeleteVehicleCategory)
 		}
 
 		// Vehicle model management
 		vehicleModelGroup := adminGroup.Group("/vehicle-models")
 		{
-			vehicleModelGroup.POST("", r.vehicleMasterHandler.CreateVehicleModel)
-			vehicleModelGroup.GET("", r.vehicleMasterHandler.GetVehicleModels)
-			vehicleModelGroup.GET("/:id", r.vehicleMasterHandler.GetVehicleModel)
-			vehicleModelGroup.PUT("/:id", r.vehicleMasterHandler.UpdateVehicleModel)
-			vehicleModelGroup.DELETE("/:id", r.vehicleMasterHandler.DeleteVehicleModel)
+			vehicleModelGroup.POST("", can(user.PermVehicleWrite), r.vehicleMasterHandler.CreateVehicleModel)
+			vehicleModelGroup.GET("", can(user.PermVehicleRead), r.vehicleMasterHandler.GetVehicleModels)
+			vehicleModelGroup.GET("/:id", can(user.PermVehicleRead), r.vehicleMasterHandler.GetVehicleModel)
+			vehicleModelGroup.PUT("/:id", can(user.PermVehicleWrite), r.vehicleMasterHandler.UpdateVehicleModel)
+			vehicleModelGroup.DELETE("/:id", can(user.PermVehicleWrite), r.vehicleMasterHandler.DeleteVehicleModel)
 		}
 
 		// Product category management
 		productCategoryGroup := adminGroup.Group("/product-categories")
 		{
-			productCategoryGroup.POST("", r.productCategoryHandler.CreateProductCategory)
-			productCategoryGroup.GET("", r.productCategoryHandler.GetProductCategories)
-			productCategoryGroup.GET("/:id", r.productCategoryHandler.GetProductCategory)
-			productCategoryGroup.PUT("/:id", r.productCategoryHandler.UpdateProductCategory)
-			productCategoryGroup.DELETE("/:id", r.productCategoryHandler.DeleteProductCategory)
-			productCategoryGroup.GET("/tree", r.productCategoryHandler.GetProductCategoryTree)
-			productCategoryGroup.GET("/:id/children", r.productCategoryHandler.GetProductCategoryChildren)
+			productCategoryGroup.POST("", can(user.PermProductWrite), r.productCategoryHandler.CreateProductCategory)
+			productCategoryGroup.GET("", can(user.PermProductRead), r.productCategoryHandler.GetProductCategories)
+			productCategoryGroup.GET("/:id", can(user.PermProductRead), r.productCategoryHandler.GetProductCategory)
+			productCategoryGroup.PUT("/:id", can(user.PermProductWrite), r.productCategoryHandler.UpdateProductCategory)
+			productCategoryGroup.DELETE("/:id", can(user.PermProductWrite), r.productCategoryHandler.DeleteProductCategory)
+			productCategoryGroup.GET("/tree", can(user.PermProductRead), r.productCategoryHandler.GetProductCategoryTree)
+			productCategoryGroup.GET("/:id/children", can(user.PermProductRead), r.productCategoryHandler.GetProductCategoryChildren)
 		}
 
 		// Product management
 		productGroup := adminGroup.Group("/products")
 		{
-			productGroup.POST("", r.productHandler.CreateProduct)
-			productGroup.GET("", r.productHandler.GetProducts)
-			productGroup.GET("/:id", r.productHandler.GetProduct)
-			productGroup.PUT("/:id", r.productHandler.UpdateProduct)
-			productGroup.DELETE("/:id", r.productHandler.DeleteProduct)
-			productGroup.GET("/low-stock", r.productHandler.GetLowStockProducts)
-			productGroup.GET("/:id/stock-movements", r.stockMovementHandler.GetProductStockMovements)
-			productGroup.GET("/:id/stock-history", r.stockMovementHandler.GetProductStockHistory)
-			productGroup.GET("/:id/current-stock", r.stockMovementHandler.GetCurrentStock)
-			productGroup.GET("/:id/adjustments", r.stockAdjustmentHandler.GetProductStockAdjustments)
+			productGroup.POST("", can(user.PermProductWrite), r.productHandler.CreateProduct)
+			productGroup.GET("", can(user.PermProductRead), r.productHandler.GetProducts)
+			productGroup.GET("/:id", can(user.PermProductRead), r.productHandler.GetProduct)
+			productGroup.PUT("/:id", can(user.PermProductWrite), r.productHandler.UpdateProduct)
+			productGroup.DELETE("/:id", can(user.PermProductWrite), r.productHandler.DeleteProduct)
+			productGroup.GET("/low-stock", can(user.PermProductRead), r.productHandler.GetLowStockProducts)
+			productGroup.GET("/:id/stock-movements", can(user.PermStockRead), r.stockMovementHandler.GetProductStockMovements)
+			productGroup.GET("/:id/stock-history", can(user.PermStockRead), r.stockMovementHandler.GetProductStockHistory)
+			productGroup.GET("/:id/current-stock", can(user.PermProductRead), r.stockMovementHandler.GetCurrentStock)
+			productGroup.GET("/:id/adjustments", can(user.PermStockRead), r.stockAdjustmentHandler.GetProductStockAdjustments)
 		}
 
 		// Purchase Order management
 		purchaseOrderGroup := adminGroup.Group("/purchase-orders")
 		{
-			purchaseOrderGroup.POST("", r.purchaseOrderHandler.CreatePurchaseOrder)
-			purchaseOrderGroup.GET("", r.purchaseOrderHandler.GetPurchaseOrders)
-			purchaseOrderGroup.GET("/:id", r.purchaseOrderHandler.GetPurchaseOrder)
-			purchaseOrderGroup.PUT("/:id", r.purchaseOrderHandler.UpdatePurchaseOrder)
-			purchaseOrderGroup.POST("/:id/approve", r.purchaseOrderHandler.ApprovePurchaseOrder)
-			purchaseOrderGroup.POST("/:id/cancel", r.purchaseOrderHandler.CancelPurchaseOrder)
-			purchaseOrderGroup.GET("/pending-approval", r.purchaseOrderHandler.GetPendingApproval)
+			purchaseOrderGroup.POST("", can(user.PermPurchaseOrderWrite), r.purchaseOrderHandler.CreatePurchaseOrder)
+			purchaseOrderGroup.GET("", can(user.PermPurchaseOrderRead), r.purchaseOrderHandler.GetPurchaseOrders)
+			purchaseOrderGroup.GET("/:id", can(user.PermPurchaseOrderRead), r.purchaseOrderHandler.GetPurchaseOrder)
+			purchaseOrderGroup.PUT("/:id", can(user.PermPurchaseOrderWrite), r.purchaseOrderHandler.UpdatePurchaseOrder)
+			purchaseOrderGroup.POST("/:id/approve", can(user.PermPurchaseOrderApprove), r.purchaseOrderHandler.ApprovePurchaseOrder)
+			purchaseOrderGroup.POST("/:id/cancel", can(user.PermPurchaseOrderApprove), r.purchaseOrderHandler.CancelPurchaseOrder)
+			purchaseOrderGroup.GET("/pending-approval", can(user.PermPurchaseOrderRead), r.purchaseOrderHandler.GetPendingApproval)
+			purchaseOrderGroup.POST("/:id/releases", can(user.PermPurchaseOrderWrite), r.purchaseOrderHandler.CreateBlanketRelease)
+			purchaseOrderGroup.GET("/:id/blanket-balance", can(user.PermPurchaseOrderRead), r.purchaseOrderHandler.GetBlanketBalance)
 			
 			// Purchase Order Details
-			purchaseOrderGroup.POST("/:id/details", r.poDetailHandler.CreatePODetail)
-			purchaseOrderGroup.GET("/:id/details", r.poDetailHandler.GetPODetails)
-			purchaseOrderGroup.GET("/:id/pending-receipt-items", r.poDetailHandler.GetPendingReceiptItems)
-			purchaseOrderGroup.POST("/:id/bulk-details", r.poDetailHandler.BulkCreatePODetails)
+			purchaseOrderGroup.POST("/:id/details", can(user.PermPurchaseOrderWrite), r.purchaseOrderHandler.AddLineItem)
+			purchaseOrderGroup.GET("/:id/details", can(user.PermPurchaseOrderRead), r.poDetailHandler.GetPODetails)
+			purchaseOrderGroup.GET("/:id/pending-receipt-items", can(user.PermPurchaseOrderRead), r.poDetailHandler.GetPendingReceiptItems)
+			purchaseOrderGroup.POST("/:id/bulk-details", can(user.PermPurchaseOrderWrite), r.purchaseOrderHandler.BulkAddLineItems)
 		}
 
 		// Purchase Order Details management
 		poDetailGroup := adminGroup.Group("/purchase-order-details")
 		{
-			poDetailGroup.GET("/:id", r.poDetailHandler.GetPODetail)
-			poDetailGroup.PUT("/:id", r.poDetailHandler.UpdatePODetail)
-			poDetailGroup.DELETE("/:id", r.poDetailHandler.DeletePODetail)
+			poDetailGroup.GET("/:id", can(user.PermPurchaseOrderRead), r.poDetailHandler.GetPODetail)
+			poDetailGroup.PUT("/:id", can(user.PermPurchaseOrderWrite), r.poDetailHandler.UpdatePODetail)
+			poDetailGroup.DELETE("/:id", can(user.PermPurchaseOrderWrite), r.poDetailHandler.DeletePODetail)
 		}
 
 		// Goods Receipt management
 		goodsReceiptGroup := adminGroup.Group("/goods-receipts")
 		{
-			goodsReceiptGroup.POST("", r.goodsReceiptHandler.CreateGoodsReceipt)
-			goodsReceiptGroup.GET("", r.goodsReceiptHandler.ListGoodsReceipts)
-			goodsReceiptGroup.GET("/:id", r.goodsReceiptHandler.GetGoodsReceipt)
-			goodsReceiptGroup.PUT("/:id", r.goodsReceiptHandler.UpdateGoodsReceipt)
-			goodsReceiptGroup.DELETE("/:id", r.goodsReceiptHandler.DeleteGoodsReceipt)
-			goodsReceiptGroup.POST("/:id/process", r.goodsReceiptHandler.ProcessGoodsReceipt)
-			goodsReceiptGroup.POST("/:id/details", r.goodsReceiptHandler.AddReceiptDetail)
-			goodsReceiptGroup.GET("/:id/details", r.goodsReceiptHandler.GetReceiptDetails)
-			goodsReceiptGroup.POST("/:id/bulk-receive", r.goodsReceiptHandler.BulkReceiveItems)
+			goodsReceiptGroup.POST("", can(user.PermGoodsReceiptWrite), r.goodsReceiptHandler.CreateGoodsReceipt)
+			goodsReceiptGroup.GET("", can(user.PermGoodsReceiptRead), r.goodsReceiptHandler.ListGoodsReceipts)
+			goodsReceiptGroup.GET("/:id", can(user.PermGoodsReceiptRead), r.goodsReceiptHandler.GetGoodsReceipt)
+			goodsReceiptGroup.PUT("/:id", can(user.PermGoodsReceiptWrite), r.goodsReceiptHandler.UpdateGoodsReceipt)
+			goodsReceiptGroup.DELETE("/:id", can(user.PermGoodsReceiptWrite), r.goodsReceiptHandler.DeleteGoodsReceipt)
+			goodsReceiptGroup.POST("/:id/process", can(user.PermGoodsReceiptWrite), r.goodsReceiptHandler.ProcessGoodsReceipt)
+			goodsReceiptGroup.POST("/:id/details", can(user.PermGoodsReceiptWrite), r.goodsReceiptHandler.AddReceiptDetail)
+			goodsReceiptGroup.GET("/:id/details", can(user.PermGoodsReceiptRead), r.goodsReceiptHandler.GetReceiptDetails)
+			goodsReceiptGroup.POST("/:id/bulk-receive", can(user.PermGoodsReceiptWrite), r.goodsReceiptHandler.BulkReceiveItems)
+			goodsReceiptGroup.POST("/:id/return-rejected", can(user.PermPurchaseReturnWrite), r.purchaseReturnHandler.CreateReturnFromReceipt)
 		}
 
 		// Stock Movement management
 		stockMovementGroup := adminGroup.Group("/stock-movements")
 		{
-			stockMovementGroup.POST("", r.stockMovementHandler.CreateStockMovement)
-			stockMovementGroup.GET("", r.stockMovementHandler.ListStockMovements)
-			stockMovementGroup.GET("/:id", r.stockMovementHandler.GetStockMovement)
-			stockMovementGroup.POST("/transfer", r.stockMovementHandler.TransferStock)
+			stockMovementGroup.POST("", can(user.PermStockMove), r.stockMovementHandler.CreateStockMovement)
+			stockMovementGroup.GET("", can(user.PermStockRead), r.stockMovementHandler.ListStockMovements)
+			stockMovementGroup.GET("/:id", can(user.PermStockRead), r.stockMovementHandler.GetStockMovement)
+			stockMovementGroup.POST("/transfer", can(user.PermStockMove), r.stockMovementHandler.TransferStock)
 		}
 
 		// Stock Adjustment management
 		stockAdjustmentGroup := adminGroup.Group("/stock-adjustments")
 		{
-			stockAdjustmentGroup.POST("", r.stockAdjustmentHandler.CreateStockAdjustment)
-			stockAdjustmentGroup.GET("", r.stockAdjustmentHandler.ListStockAdjustments)
-			stockAdjustmentGroup.GET("/:id", r.stockAdjustmentHandler.GetStockAdjustment)
-			stockAdjustmentGroup.PUT("/:id", r.stockAdjustmentHandler.UpdateStockAdjustment)
-			stockAdjustmentGroup.DELETE("/:id", r.stockAdjustmentHandler.DeleteStockAdjustment)
-			stockAdjustmentGroup.GET("/pending", r.stockAdjustmentHandler.GetPendingAdjustments)
-			stockAdjustmentGroup.POST("/:id/approve", r.stockAdjustmentHandler.ApproveStockAdjustment)
-			stockAdjustmentGroup.GET("/variance-report", r.stockAdjustmentHandler.GetVarianceReport)
-			stockAdjustmentGroup.POST("/physical-count", r.stockAdjustmentHandler.CreatePhysicalCountAdjustments)
-			stockAdjustmentGroup.POST("/bulk-approve", r.stockAdjustmentHandler.BulkApproveAdjustments)
+			stockAdjustmentGroup.POST("", can(user.PermStockAdjust), r.stockAdjustmentHandler.CreateStockAdjustment)
+			stockAdjustmentGroup.GET("", can(user.PermStockRead), r.stockAdjustmentHandler.ListStockAdjustments)
+			stockAdjustmentGroup.GET("/:id", can(user.PermStockRead), r.stockAdjustmentHandler.GetStockAdjustment)
+			stockAdjustmentGroup.PUT("/:id", can(user.PermStockAdjust), r.stockAdjustmentHandler.UpdateStockAdjustment)
+			stockAdjustmentGroup.DELETE("/:id", can(user.PermStockAdjust), r.stockAdjustmentHandler.DeleteStockAdjustment)
+			stockAdjustmentGroup.GET("/pending", can(user.PermStockRead), r.stockAdjustmentHandler.GetPendingAdjustments)
+			stockAdjustmentGroup.POST("/:id/approve", can(user.PermStockApproveAdjust), r.stockAdjustmentHandler.ApproveStockAdjustment)
+			stockAdjustmentGroup.GET("/variance-report", can(user.PermStockRead), r.stockAdjustmentHandler.GetVarianceReport)
+			stockAdjustmentGroup.POST("/physical-count", can(user.PermStockAdjust), r.stockAdjustmentHandler.CreatePhysicalCountAdjustments)
+			stockAdjustmentGroup.POST("/bulk-approve", can(user.PermStockApproveAdjust), r.stockAdjustmentHandler.BulkApproveAdjustments)
 		}
 
-		// Supplier Payment management
+		// Background jobs
+		jobGroup := adminGroup.Group("/jobs")
+		{
+			jobGroup.GET("", can(user.PermJobManage), r.jobHandler.ListJobs)
+			jobGroup.GET("/runs", can(user.PermJobManage), r.jobHandler.ListJobRuns)
+			jobGroup.POST("/:name/run", can(user.PermJobManage), r.jobHandler.TriggerJob)
+		}
+
+		// Payment terms management
+		paymentTermGroup := adminGroup.Group("/payment-terms")
+		{
+			paymentTermGroup.POST("", can(user.PermPaymentTermWrite), r.paymentTermHandler.CreatePaymentTerm)
+			paymentTermGroup.GET("", can(user.PermPaymentTermRead), r.paymentTermHandler.ListPaymentTerms)
+			paymentTermGroup.GET("/:id", can(user.PermPaymentTermRead), r.paymentTermHandler.GetPaymentTerm)
+			paymentTermGroup.PUT("/:id", can(user.PermPaymentTermWrite), r.paymentTermHandler.UpdatePaymentTerm)
+			paymentTermGroup.DELETE("/:id", can(user.PermPaymentTermWrite), r.paymentTermHandler.DeletePaymentTerm)
+		}
+
+		// Supplier invoice management
+		supplierInvoiceGroup := adminGroup.Group("/supplier-invoices")
+		{
+			supplierInvoiceGroup.POST("", can(user.PermSupplierInvoiceWrite), r.supplierInvoiceHandler.CreateInvoice)
+			supplierInvoiceGroup.GET("", can(user.PermSupplierInvoiceRead), r.supplierInvoiceHandler.ListInvoices)
+			supplierInvoiceGroup.GET("/overdue", can(user.PermSupplierInvoiceRead), r.supplierInvoiceHandler.GetOverdueInvoices)
+			supplierInvoiceGroup.GET("/summary", can(user.PermSupplierInvoiceRead), r.supplierInvoiceHandler.GetInvoiceSummary)
+			supplierInvoiceGroup.POST("/update-overdue", can(user.PermSupplierInvoiceWrite), r.supplierInvoiceHandler.UpdateOverdueInvoices)
+			supplierInvoiceGroup.POST("/calculate-terms", can(user.PermSupplierInvoiceWrite), r.supplierInvoiceHandler.CalculatePaymentTerms)
+			supplierInvoiceGroup.GET("/aging", can(user.PermSupplierInvoiceRead), r.supplierInvoiceHandler.GetAgingReport)
+			supplierInvoiceGroup.GET("/aging/suppliers/:id", can(user.PermSupplierInvoiceRead), r.supplierInvoiceHandler.GetSupplierAging)
+			supplierInvoiceGroup.GET("/discount-opportunities", can(user.PermSupplierInvoiceRead), r.supplierInvoiceHandler.GetDiscountOpportunities)
+			supplierInvoiceGroup.GET("/:id", can(user.PermSupplierInvoiceRead), r.supplierInvoiceHandler.GetInvoice)
+			supplierInvoiceGroup.PUT("/:id", can(user.PermSupplierInvoiceWrite), r.supplierInvoiceHandler.UpdateInvoice)
+			supplierInvoiceGroup.DELETE("/:id", can(user.PermSupplierInvoiceWrite), r.supplierInvoiceHandler.DeleteInvoice)
+			supplierInvoiceGroup.PUT("/:id/status", can(user.PermSupplierInvoiceWrite), r.supplierInvoiceHandler.UpdateInvoiceStatus)
+			supplierInvoiceGroup.GET("/:id/allocations", can(user.PermSupplierInvoiceRead), r.supplierInvoiceHandler.GetInvoiceAllocations)
+		}
+
+		// Supplier Payment management (payment vouchers allocated to invoices)
 		supplierPaymentGroup := adminGroup.Group("/supplier-payments")
 		{
-			supplierPaymentGroup.POST("", r.supplierPaymentHandler.CreateSupplierPayment)
-			supplierPaymentGroup.GET("", r.supplierPaymentHandler.ListSupplierPayments)
-			supplierPaymentGroup.GET("/:id", r.supplierPaymentHandler.GetSupplierPayment)
-			supplierPaymentGroup.PUT("/:id", r.supplierPaymentHandler.UpdateSupplierPayment)
-			supplierPaymentGroup.DELETE("/:id", r.supplierPaymentHandler.DeleteSupplierPayment)
-			supplierPaymentGroup.POST("/:id/process", r.supplierPaymentHandler.ProcessPayment)
-			supplierPaymentGroup.PUT("/:id/status", r.supplierPaymentHandler.UpdatePaymentStatus)
-			supplierPaymentGroup.GET("/overdue", r.supplierPaymentHandler.GetOverduePayments)
-			supplierPaymentGroup.GET("/summary", r.supplierPaymentHandler.GetPaymentSummary)
-			supplierPaymentGroup.POST("/update-overdue", r.supplierPaymentHandler.UpdateOverduePayments)
-			supplierPaymentGroup.POST("/calculate-terms", r.supplierPaymentHandler.CalculatePaymentTerms)
+			supplierPaymentGroup.POST("", can(user.PermSupplierPaymentWrite), r.paymentVoucherHandler.CreateVoucher)
+			supplierPaymentGroup.GET("", can(user.PermSupplierPaymentRead), r.paymentVoucherHandler.ListVouchers)
+			supplierPaymentGroup.GET("/:id", can(user.PermSupplierPaymentRead), r.paymentVoucherHandler.GetVoucher)
+			supplierPaymentGroup.PUT("/:id", can(user.PermSupplierPaymentWrite), r.paymentVoucherHandler.UpdateVoucher)
+			supplierPaymentGroup.POST("/:id/allocate", can(user.PermSupplierPaymentWrite), r.paymentVoucherHandler.AllocateVoucher)
+			supplierPaymentGroup.POST("/:id/void", can(user.PermSupplierPaymentWrite), r.paymentVoucherHandler.VoidVoucher)
+		}
+
+		// Payment run management (batch supplier payments with bank transfer files)
+		paymentRunGroup := adminGroup.Group("/payment-runs")
+		{
+			paymentRunGroup.POST("", can(user.PermPaymentRunWrite), r.paymentRunHandler.CreateRun)
+			paymentRunGroup.GET("", can(user.PermPaymentRunRead), r.paymentRunHandler.ListRuns)
+			paymentRunGroup.GET("/:id", can(user.PermPaymentRunRead), r.paymentRunHandler.GetRun)
+			paymentRunGroup.DELETE("/:id/lines/:lineId", can(user.PermPaymentRunWrite), r.paymentRunHandler.RemoveLine)
+			paymentRunGroup.POST("/:id/approve", can(user.PermPaymentRunApprove), r.paymentRunHandler.ApproveRun)
+			paymentRunGroup.POST("/:id/cancel", can(user.PermPaymentRunApprove), r.paymentRunHandler.CancelRun)
+			paymentRunGroup.GET("/:id/bank-file", can(user.PermPaymentRunRead), r.paymentRunHandler.DownloadBankFile)
+			paymentRunGroup.POST("/:id/execute", can(user.PermPaymentRunWrite), r.paymentRunHandler.ExecuteRun)
+			paymentRunGroup.POST("/:id/lines/:lineId/fail", can(user.PermPaymentRunWrite), r.paymentRunHandler.FailLine)
+		}
+
+		// Bank statement reconciliation
+		bankStatementGroup := adminGroup.Group("/bank-statements")
+		{
+			bankStatementGroup.POST("/import", can(user.PermBankReconcile), r.bankStatementHandler.ImportStatement)
+			bankStatementGroup.GET("", can(user.PermBankStatementRead), r.bankStatementHandler.ListStatements)
+			bankStatementGroup.GET("/reconciliation", can(user.PermBankStatementRead), r.bankStatementHandler.GetReconciliation)
+			bankStatementGroup.GET("/:id", can(user.PermBankStatementRead), r.bankStatementHandler.GetStatement)
+			bankStatementGroup.DELETE("/:id", can(user.PermBankReconcile), r.bankStatementHandler.DeleteStatement)
+			bankStatementGroup.POST("/:id/auto-match", can(user.PermBankReconcile), r.bankStatementHandler.AutoMatch)
+			bankStatementGroup.POST("/lines/:lineId/match", can(user.PermBankReconcile), r.bankStatementHandler.MatchLine)
+			bankStatementGroup.POST("/lines/:lineId/unmatch", can(user.PermBankReconcile), r.bankStatementHandler.UnmatchLine)
+		}
+
+		// General ledger
+		glGroup := adminGroup.Group("/gl")
+		{
+			glGroup.POST("/accounts", can(user.PermGLManage), r.generalLedgerHandler.CreateAccount)
+			glGroup.GET("/accounts", can(user.PermGLRead), r.generalLedgerHandler.ListAccounts)
+			glGroup.GET("/accounts/:id", can(user.PermGLRead), r.generalLedgerHandler.GetAccount)
+			glGroup.PUT("/accounts/:id", can(user.PermGLManage), r.generalLedgerHandler.UpdateAccount)
+			glGroup.GET("/posting-rules", can(user.PermGLRead), r.generalLedgerHandler.GetPostingRules)
+			glGroup.PUT("/posting-rules/:rule", can(user.PermGLManage), r.generalLedgerHandler.UpdatePostingRule)
+			glGroup.POST("/journals", can(user.PermGLPost), r.generalLedgerHandler.PostJournal)
+			glGroup.GET("/journals", can(user.PermGLRead), r.generalLedgerHandler.ListJournals)
+			glGroup.GET("/journals/:id", can(user.PermGLRead), r.generalLedgerHandler.GetJournal)
+			glGroup.POST("/journals/:id/reverse", can(user.PermGLPost), r.generalLedgerHandler.ReverseJournal)
+			glGroup.GET("/trial-balance", can(user.PermGLRead), r.generalLedgerHandler.GetTrialBalance)
+			glGroup.GET("/periods", can(user.PermGLRead), r.generalLedgerHandler.ListPeriods)
+			glGroup.POST("/periods/close", can(user.PermGLManage), r.generalLedgerHandler.ClosePeriod)
+			glGroup.POST("/periods/reopen", can(user.PermGLManage), r.generalLedgerHandler.ReopenPeriod)
+		}
+
+		// Tax codes and VAT reporting
+		taxGroup := adminGroup.Group("/tax")
+		{
+			taxGroup.POST("/codes", can(user.PermTaxManage), r.taxHandler.CreateTaxCode)
+			taxGroup.GET("/codes", can(user.PermTaxRead), r.taxHandler.ListTaxCodes)
+			taxGroup.GET("/codes/:id", can(user.PermTaxRead), r.taxHandler.GetTaxCode)
+			taxGroup.PUT("/codes/:id", can(user.PermTaxManage), r.taxHandler.UpdateTaxCode)
+			taxGroup.GET("/vat-report", can(user.PermTaxRead), r.taxHandler.GetVATReport)
+			taxGroup.GET("/efaktur-export", can(user.PermTaxRead), r.taxHandler.ExportEFaktur)
+		}
+
+		// Sales invoices
+		salesInvoiceGroup := adminGroup.Group("/sales-invoices")
+		{
+			salesInvoiceGroup.POST("", can(user.PermSalesInvoiceCreate), r.salesInvoiceHandler.CreateInvoice)
+			salesInvoiceGroup.GET("", can(user.PermSalesInvoiceRead), r.salesInvoiceHandler.ListInvoices)
+			salesInvoiceGroup.GET("/:id", can(user.PermSalesInvoiceRead), r.salesInvoiceHandler.GetInvoice)
+			salesInvoiceGroup.PUT("/:id/tax-invoice-number", can(user.PermSalesInvoiceCreate), r.salesInvoiceHandler.SetTaxInvoiceNumber)
+			salesInvoiceGroup.POST("/:id/void", can(user.PermSalesInvoiceVoid), r.salesInvoiceHandler.VoidInvoice)
+		}
+
+		// Exchange rates for foreign currency purchasing
+		exchangeRateGroup := adminGroup.Group("/exchange-rates")
+		{
+			exchangeRateGroup.POST("", can(user.PermExchangeRateWrite), r.exchangeRateHandler.CreateRate)
+			exchangeRateGroup.GET("", can(user.PermExchangeRateRead), r.exchangeRateHandler.ListRates)
+			exchangeRateGroup.GET("/lookup", can(user.PermExchangeRateRead), r.exchangeRateHandler.LookupRate)
+			exchangeRateGroup.POST("/import", can(user.PermExchangeRateWrite), r.exchangeRateHandler.ImportRates)
+		}
+
+		// Customer receivables: receipts, credit status and statements of account
+		customerReceiptGroup := adminGroup.Group("/customer-receipts")
+		{
+			customerReceiptGroup.POST("", can(user.PermCustomerReceiptWrite), r.customerAccountHandler.CreateReceipt)
+			customerReceiptGroup.GET("", can(user.PermCustomerReceiptRead), r.customerAccountHandler.ListReceipts)
+			customerReceiptGroup.GET("/:id", can(user.PermCustomerReceiptRead), r.customerAccountHandler.GetReceipt)
+			customerReceiptGroup.POST("/:id/allocate", can(user.PermCustomerReceiptWrite), r.customerAccountHandler.AllocateReceipt)
+			customerReceiptGroup.POST("/:id/void", can(user.PermCustomerReceiptWrite), r.customerAccountHandler.VoidReceipt)
+		}
+
+		customerAccountGroup := adminGroup.Group("/customer-accounts")
+		{
+			customerAccountGroup.GET("/:id", can(user.PermCustomerAccountRead), r.customerAccountHandler.GetCreditStatus)
+			customerAccountGroup.GET("/:id/statement", can(user.PermCustomerAccountRead), r.customerAccountHandler.GetStatement)
+		}
+
+		// Purchase Return (return to vendor) management
+		purchaseReturnGroup := adminGroup.Group("/purchase-returns")
+		{
+			purchaseReturnGroup.POST("", can(user.PermPurchaseReturnWrite), r.purchaseReturnHandler.CreatePurchaseReturn)
+			purchaseReturnGroup.GET("", can(user.PermPurchaseReturnRead), r.purchaseReturnHandler.ListPurchaseReturns)
+			purchaseReturnGroup.GET("/:id", can(user.PermPurchaseReturnRead), r.purchaseReturnHandler.GetPurchaseReturn)
+			purchaseReturnGroup.GET("/:id/details", can(user.PermPurchaseReturnRead), r.purchaseReturnHandler.GetReturnDetails)
+			purchaseReturnGroup.POST("/:id/approve", can(user.PermPurchaseReturnApprove), r.purchaseReturnHandler.ApprovePurchaseReturn)
+			purchaseReturnGroup.PUT("/:id/shipment", can(user.PermPurchaseReturnWrite), r.purchaseReturnHandler.UpdateShipment)
+			purchaseReturnGroup.POST("/:id/cancel", can(user.PermPurchaseReturnApprove), r.purchaseReturnHandler.CancelPurchaseReturn)
+		}
+
+		// Supplier Debit Note management
+		debitNoteGroup := adminGroup.Group("/supplier-debit-notes")
+		{
+			debitNoteGroup.GET("", can(user.PermPurchaseReturnRead), r.purchaseReturnHandler.ListDebitNotes)
+			debitNoteGroup.GET("/:id", can(user.PermPurchaseReturnRead), r.purchaseReturnHandler.GetDebitNote)
+			debitNoteGroup.GET("/:id/applications", can(user.PermPurchaseReturnRead), r.purchaseReturnHandler.GetDebitNoteApplications)
+			debitNoteGroup.POST("/:id/apply", can(user.PermPurchaseReturnWrite), r.purchaseReturnHandler.ApplyDebitNote)
+		}
+
+		// Landed Cost Voucher management
+		landedCostGroup := adminGroup.Group("/landed-costs")
+		{
+			landedCostGroup.POST("", can(user.PermLandedCostWrite), r.landedCostHandler.CreateVoucher)
+			landedCostGroup.GET("", can(user.PermLandedCostRead), r.landedCostHandler.ListVouchers)
+			landedCostGroup.GET("/:id", can(user.PermLandedCostRead), r.landedCostHandler.GetVoucher)
+			landedCostGroup.GET("/:id/allocations", can(user.PermLandedCostRead), r.landedCostHandler.GetAllocations)
+			landedCostGroup.POST("/:id/post", can(user.PermLandedCostWrite), r.landedCostHandler.PostVoucher)
+			landedCostGroup.POST("/:id/cancel", can(user.PermLandedCostWrite), r.landedCostHandler.CancelVoucher)
+		}
+
+		// Supplier price list management
+		supplierPriceListGroup := adminGroup.Group("/supplier-price-lists")
+		{
+			supplierPriceListGroup.POST("", can(user.PermPriceListWrite), r.supplierPriceListHandler.CreatePriceList)
+			supplierPriceListGroup.GET("", can(user.PermPriceListRead), r.supplierPriceListHandler.ListPriceLists)
+			supplierPriceListGroup.GET("/lookup", can(user.PermPriceListRead), r.supplierPriceListHandler.LookupPrice)
+			supplierPriceListGroup.GET("/history", can(user.PermPriceListRead), r.supplierPriceListHandler.GetPriceHistory)
+			supplierPriceListGroup.GET("/:id", can(user.PermPriceListRead), r.supplierPriceListHandler.GetPriceList)
+			supplierPriceListGroup.PUT("/:id", can(user.PermPriceListWrite), r.supplierPriceListHandler.UpdatePriceList)
+			supplierPriceListGroup.DELETE("/:id", can(user.PermPriceListWrite), r.supplierPriceListHandler.DeletePriceList)
 		}
 	}
 
@@ -298,4 +608,17 @@ func (r *Router) healthCheck(c *gin.Context) {
 		Version:   r.config.App.Version,
 	}
 	c.JSON(http.StatusOK, response)
+}
+
+// jwks serves the public keys that validate access tokens as a JSON Web Key Set
+func (r *Router) jwks(c *gin.Context) {
+	set, err := r.jwtManager.JWKS()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.NewErrorResponse(
+			"Failed to get signing keys", err.Error(),
+		))
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, set)
 }
\ No newline at end of file
@@ -0,0 +1,117 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/config"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services"
+	productService "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
+)
+
+// Names of the built-in background jobs
+const (
+	JobSupplierInvoiceOverdue   = "supplier-invoice-overdue"
+	JobSupplierInvoicePenalties = "supplier-invoice-penalties"
+	JobSessionCleanup           = "session-cleanup"
+	JobAPAgingReport            = "ap-aging-report"
+	JobJWTKeyRotation           = "jwt-key-rotation"
+)
+
+// RegisterDefaultJobs registers the built-in jobs on their configured schedules. The signing key
+// rotation job is only registered when tokens are signed with rotating keys, i.e. signingKeys is
+// not nil.
+func RegisterDefaultJobs(
+	s *Scheduler,
+	cfg config.JobsConfig,
+	sessionMaxAge time.Duration,
+	sessionIdleTimeout time.Duration,
+	invoiceService *productService.SupplierInvoiceService,
+	sessionRepo interfaces.UserSessionRepository,
+	signingKeys *services.SigningKeyService,
+	jwtManager *utils.JWTManager,
+) error {
+	if err := s.Register(JobSupplierInvoiceOverdue, cfg.OverdueSchedule,
+		"Marks open supplier invoices past their due date as overdue",
+		func(ctx context.Context) (string, error) {
+			updated, err := invoiceService.UpdateOverdueInvoices(ctx)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d supplier invoice statuses updated", updated), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := s.Register(JobSupplierInvoicePenalties, cfg.PenaltySchedule,
+		"Accrues daily late penalties on overdue supplier invoices",
+		func(ctx context.Context) (string, error) {
+			if cfg.PenaltyDailyRate <= 0 {
+				return "penalty accrual disabled, SUPPLIER_PENALTY_DAILY_RATE is not set", nil
+			}
+			updated, err := invoiceService.AccrueLatePenalties(ctx, cfg.PenaltyDailyRate)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("penalties updated on %d supplier invoices at %g%% per day", updated, cfg.PenaltyDailyRate), nil
+		}); err != nil {
+		return err
+	}
+
+	if err := s.Register(JobSessionCleanup, cfg.SessionCleanupSchedule,
+		"Ends sessions whose token has expired or that were left idle, and deletes old ended sessions",
+		func(ctx context.Context) (string, error) {
+			expired, err := sessionRepo.ExpireStaleSessions(ctx, sessionMaxAge)
+			if err != nil {
+				return "", err
+			}
+			var idle int64
+			if sessionIdleTimeout > 0 {
+				if idle, err = sessionRepo.ExpireIdleSessions(ctx, sessionIdleTimeout); err != nil {
+					return "", err
+				}
+			}
+			if err := sessionRepo.DeleteExpiredSessions(ctx); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d expired and %d idle sessions ended", expired, idle), nil
+		}); err != nil {
+		return err
+	}
+
+	if signingKeys != nil {
+		if err := s.Register(JobJWTKeyRotation, cfg.KeyRotationSchedule,
+			"Rotates the access token signing key when due and deletes retired keys",
+			func(ctx context.Context) (string, error) {
+				summary, err := signingKeys.RotateIfDue(ctx, time.Now())
+				if err != nil {
+					return "", err
+				}
+				if err := jwtManager.Reload(ctx); err != nil {
+					return "", err
+				}
+				return summary, nil
+			}); err != nil {
+			return err
+		}
+	}
+
+	return s.Register(JobAPAgingReport, cfg.APAgingSchedule,
+		"Generates the accounts payable aging report and records its totals",
+		func(ctx context.Context) (string, error) {
+			report, err := invoiceService.GetAgingReport(ctx, &products.APAgingParams{})
+			if err != nil {
+				return "", err
+			}
+			totals := report.Totals
+			return fmt.Sprintf(
+				"%d suppliers, outstanding %.2f (current %.2f, 1-30 %.2f, 31-60 %.2f, 61-90 %.2f, over 90 %.2f)",
+				len(report.Suppliers), totals.Total, totals.Current, totals.Days1To30,
+				totals.Days31To60, totals.Days61To90, totals.Over90,
+			), nil
+		})
+}
@@ -0,0 +1,339 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/jobs"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// jobTimeout bounds a single job run so a stuck query cannot hold the job lock forever
+const jobTimeout = 30 * time.Minute
+
+var (
+	// ErrJobNotFound is returned when triggering a job that is not registered
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobRunning is returned when a job is already running in this or another instance
+	ErrJobRunning = errors.New("job is already running")
+)
+
+// JobFunc runs a background job and returns a short summary of what it did
+type JobFunc func(ctx context.Context) (string, error)
+
+type job struct {
+	name        string
+	description string
+	schedule    *Schedule
+	run         JobFunc
+}
+
+// Scheduler runs registered jobs on their cron schedules inside the API process.
+// Each run takes a Postgres advisory lock on the job name, so when several instances
+// are deployed only one of them runs a given job at a time. Runs are recorded in the job run history,
+// where scheduled runs also claim their tick so an instance reaching a tick late does not run it again.
+type Scheduler struct {
+	db       *sql.DB
+	runRepo  interfaces.JobRunRepository
+	location *time.Location
+
+	mu     sync.Mutex
+	jobs   map[string]*job
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewScheduler creates a scheduler evaluating cron expressions in the given location
+func NewScheduler(db *sql.DB, runRepo interfaces.JobRunRepository, location *time.Location) *Scheduler {
+	if location == nil {
+		location = time.Local
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		db:       db,
+		runRepo:  runRepo,
+		location: location,
+		jobs:     make(map[string]*job),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Register adds a job running on the given cron schedule
+func (s *Scheduler) Register(name, spec, description string, run JobFunc) error {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return fmt.Errorf("invalid schedule for job %s: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("job %s is already registered", name)
+	}
+	s.jobs[name] = &job{name: name, description: description, schedule: schedule, run: run}
+	return nil
+}
+
+// Start runs the scheduling loop in the background until Stop is called
+func (s *Scheduler) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.loop()
+	}()
+	log.Printf("Job scheduler started with %d jobs", len(s.registeredJobs()))
+}
+
+// Stop stops scheduling new runs and waits for running jobs until ctx is done
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for running jobs: %w", ctx.Err())
+	}
+}
+
+// loop wakes at the start of every minute and starts the jobs due in that minute
+func (s *Scheduler) loop() {
+	for {
+		now := time.Now().In(s.location)
+		next := now.Truncate(time.Minute).Add(time.Minute)
+		timer := time.NewTimer(next.Sub(now))
+
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			// Use the minute the timer was set for, not its firing time, so every instance claims the same tick
+			tick := next
+			for _, j := range s.registeredJobs() {
+				if j.schedule.Matches(tick) {
+					s.runScheduled(j, tick)
+				}
+			}
+		}
+	}
+}
+
+// runScheduled runs a job for a schedule tick unless another instance is running the job
+// or has already claimed the tick
+func (s *Scheduler) runScheduled(j *job, tick time.Time) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		release, err := s.lock(s.ctx, j.name)
+		if err != nil {
+			if !errors.Is(err, ErrJobRunning) {
+				log.Printf("Job %s: %v", j.name, err)
+			}
+			return
+		}
+		defer release()
+
+		if _, err := s.runRepo.FailInterrupted(s.ctx, j.name); err != nil {
+			log.Printf("Job %s: %v", j.name, err)
+			return
+		}
+
+		run := &jobs.JobRun{
+			JobName:     j.name,
+			Trigger:     jobs.JobTriggerSchedule,
+			Status:      jobs.JobRunStatusRunning,
+			ScheduledAt: &tick,
+			StartedAt:   time.Now(),
+		}
+		claimed, err := s.runRepo.ClaimScheduled(s.ctx, run)
+		if err != nil {
+			log.Printf("Job %s: %v", j.name, err)
+			return
+		}
+		if !claimed {
+			return
+		}
+		s.execute(j, run)
+	}()
+}
+
+// Trigger starts a job immediately. It returns once the run is recorded; the job itself runs in the background.
+func (s *Scheduler) Trigger(ctx context.Context, name string, triggeredBy int) (*jobs.JobRun, error) {
+	s.mu.Lock()
+	j, ok := s.jobs[name]
+	s.mu.Unlock()
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	release, err := s.lock(ctx, j.name)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := s.startRun(ctx, j, jobs.JobTriggerManual, &triggeredBy)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	// Copy the run before it is updated by the background execution
+	started := *run
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer release()
+		s.execute(j, run)
+	}()
+
+	return &started, nil
+}
+
+// Jobs lists the registered jobs with their next scheduled run and latest recorded run
+func (s *Scheduler) Jobs(ctx context.Context) ([]jobs.JobInfo, error) {
+	latest, err := s.runRepo.GetLatestRuns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().In(s.location)
+	registered := s.registeredJobs()
+	infos := make([]jobs.JobInfo, 0, len(registered))
+	for _, j := range registered {
+		info := jobs.JobInfo{
+			Name:        j.name,
+			Description: j.description,
+			Schedule:    j.schedule.String(),
+			NextRunAt:   j.schedule.Next(now),
+		}
+		if run, ok := latest[j.name]; ok {
+			info.LastRun = &run
+			info.IsRunning = run.Status == jobs.JobRunStatusRunning
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// ListRuns retrieves the job run history
+func (s *Scheduler) ListRuns(ctx context.Context, params *jobs.JobRunFilterParams) (*common.PaginatedResponse, error) {
+	if params.Status != nil && !params.Status.IsValid() {
+		return nil, fmt.Errorf("invalid job run status: %s", *params.Status)
+	}
+	if params.Trigger != nil && !params.Trigger.IsValid() {
+		return nil, fmt.Errorf("invalid job trigger: %s", *params.Trigger)
+	}
+	return s.runRepo.List(ctx, params)
+}
+
+func (s *Scheduler) registeredJobs() []*job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	registered := make([]*job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		registered = append(registered, j)
+	}
+	sort.Slice(registered, func(a, b int) bool { return registered[a].name < registered[b].name })
+	return registered
+}
+
+// startRun records a new run. Runs still marked running belong to an instance that died while
+// holding the lock we now own, so they are closed as interrupted first.
+func (s *Scheduler) startRun(ctx context.Context, j *job, trigger jobs.JobTrigger, triggeredBy *int) (*jobs.JobRun, error) {
+	if _, err := s.runRepo.FailInterrupted(ctx, j.name); err != nil {
+		return nil, err
+	}
+
+	return s.runRepo.Create(ctx, &jobs.JobRun{
+		JobName:     j.name,
+		Trigger:     trigger,
+		TriggeredBy: triggeredBy,
+		Status:      jobs.JobRunStatusRunning,
+		StartedAt:   time.Now(),
+	})
+}
+
+// execute runs the job and records its outcome, turning a panic into a failed run
+func (s *Scheduler) execute(j *job, run *jobs.JobRun) {
+	ctx, cancel := context.WithTimeout(s.ctx, jobTimeout)
+	defer cancel()
+
+	var result string
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("job panicked: %v", r)
+			}
+		}()
+		result, err = j.run(ctx)
+	}()
+
+	run.Finish(time.Now(), result, err)
+	if err != nil {
+		log.Printf("Job %s failed: %v", j.name, err)
+	}
+
+	// Record the outcome even when the scheduler is stopping
+	finishCtx, finishCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer finishCancel()
+	if err := s.runRepo.Finish(finishCtx, run); err != nil {
+		log.Printf("Job %s: %v", j.name, err)
+	}
+}
+
+// lock takes the advisory lock of a job on a dedicated connection, since advisory locks
+// belong to the database session that took them. The returned func releases the lock.
+func (s *Scheduler) lock(ctx context.Context, name string) (func(), error) {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lock connection: %w", err)
+	}
+
+	key := jobLockKey(name)
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to acquire job lock: %w", err)
+	}
+	if !acquired {
+		conn.Close()
+		return nil, ErrJobRunning
+	}
+
+	return func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key); err != nil {
+			log.Printf("Job %s: failed to release job lock: %v", name, err)
+		}
+		conn.Close()
+	}, nil
+}
+
+// jobLockKey derives a stable advisory lock key from the job name
+func jobLockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("scheduled-job:" + name))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
+)
+
+// APIKeyService handles API keys, which let integrations such as barcode scanners or reporting
+// tools call the API without a login session. A key acts for the user who created it and can
+// only use the permissions in its scopes that the user's role also holds.
+type APIKeyService struct {
+	apiKeyRepo  interfaces.APIKeyRepository
+	userRepo    interfaces.UserRepository
+	permissions *PermissionService
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(
+	apiKeyRepo interfaces.APIKeyRepository,
+	userRepo interfaces.UserRepository,
+	permissions *PermissionService,
+) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo:  apiKeyRepo,
+		userRepo:    userRepo,
+		permissions: permissions,
+	}
+}
+
+// CreateKey creates an API key for the current user. Every scope must be a permission their
+// role holds. The key is returned only here.
+func (s *APIKeyService) CreateKey(ctx context.Context, req *user.APIKeyCreateRequest, createdBy int, role string) (*user.APIKeyCreateResponse, error) {
+	scopes, err := req.Validate(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, scope := range scopes {
+		allowed, err := s.permissions.HasPermission(ctx, role, scope)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, fmt.Errorf("cannot give scope %s, your role does not have it", scope)
+		}
+	}
+
+	key, prefix, err := utils.GenerateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	allowedIPs := req.AllowedIPs
+	if allowedIPs == nil {
+		allowedIPs = []string{}
+	}
+
+	apiKey := user.APIKey{
+		Name:       req.Name,
+		Prefix:     prefix,
+		KeyHash:    utils.HashToken(key),
+		Scopes:     scopes,
+		AllowedIPs: allowedIPs,
+		CreatedBy:  createdBy,
+		ExpiresAt:  req.ExpiresAt,
+	}
+	if err := s.apiKeyRepo.Create(ctx, &apiKey); err != nil {
+		return nil, err
+	}
+
+	return &user.APIKeyCreateResponse{APIKey: apiKey, Key: key}, nil
+}
+
+// GetKey retrieves an API key by ID
+func (s *APIKeyService) GetKey(ctx context.Context, id int) (*user.APIKey, error) {
+	return s.apiKeyRepo.GetByID(ctx, id)
+}
+
+// ListKeys retrieves API keys with filtering and pagination
+func (s *APIKeyService) ListKeys(ctx context.Context, params *user.APIKeyFilterParams) (*common.PaginatedResponse, error) {
+	params.Validate()
+
+	keys, total, err := s.apiKeyRepo.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+
+	return &common.PaginatedResponse{
+		Data:       keys,
+		Total:      total,
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: params.GetTotalPages(total),
+		HasMore:    params.GetHasMore(total),
+	}, nil
+}
+
+// RevokeKey revokes an API key. Revoked keys stop working at once.
+func (s *APIKeyService) RevokeKey(ctx context.Context, id int, revokedBy int) error {
+	return s.apiKeyRepo.Revoke(ctx, id, revokedBy)
+}
+
+// Authenticate resolves an API key sent from an IP address to the key and the user it acts for.
+// Keys of deactivated users stop working.
+func (s *APIKeyService) Authenticate(ctx context.Context, key, ipAddress string) (*user.APIKey, *user.User, error) {
+	prefix, ok := utils.ParseAPIKeyPrefix(key)
+	if !ok {
+		return nil, nil, user.ErrAPIKeyInvalid
+	}
+
+	apiKey, err := s.apiKeyRepo.GetByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(apiKey.KeyHash), []byte(utils.HashToken(key))) != 1 {
+		return nil, nil, user.ErrAPIKeyInvalid
+	}
+	if err := apiKey.Check(time.Now(), ipAddress); err != nil {
+		return nil, nil, err
+	}
+
+	owner, err := s.userRepo.GetByID(ctx, apiKey.CreatedBy)
+	if err != nil || !owner.IsActive {
+		return nil, nil, user.ErrAPIKeyInvalid
+	}
+
+	if err := s.apiKeyRepo.TouchLastUsed(ctx, apiKey.KeyID, ipAddress); err != nil {
+		return nil, nil, err
+	}
+
+	return apiKey, owner, nil
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/audit"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// AuditService handles queries of the audit log. The log itself is written by the database as
+// rows change, with the actor the audit connector passes along with each statement.
+type AuditService struct {
+	auditLogRepo interfaces.AuditLogRepository
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(auditLogRepo interfaces.AuditLogRepository) *AuditService {
+	return &AuditService{
+		auditLogRepo: auditLogRepo,
+	}
+}
+
+// GetEntry retrieves an audit log entry by ID
+func (s *AuditService) GetEntry(ctx context.Context, id int64) (*audit.AuditLogEntry, error) {
+	return s.auditLogRepo.GetByID(ctx, id)
+}
+
+// ListEntries retrieves the audit log with filtering and pagination
+func (s *AuditService) ListEntries(ctx context.Context, params *audit.AuditLogFilterParams) (*common.PaginatedResponse, error) {
+	if params.Action != nil && !params.Action.IsValid() {
+		return nil, fmt.Errorf("invalid action: %s", *params.Action)
+	}
+	if params.DateFrom != nil && params.DateTo != nil && params.DateTo.Before(*params.DateFrom) {
+		return nil, fmt.Errorf("date_to must not be before date_from")
+	}
+
+	params.Validate()
+
+	entries, total, err := s.auditLogRepo.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+
+	return &common.PaginatedResponse{
+		Data:       entries,
+		Total:      total,
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: params.GetTotalPages(total),
+		HasMore:    params.GetHasMore(total),
+	}, nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,55 +14,263 @@ import (
 
 // AuthService handles authentication business logic
 type AuthService struct {
-	userRepo    interfaces.UserRepository
-	sessionRepo interfaces.UserSessionRepository
-	jwtManager  *utils.JWTManager
+	userRepo         interfaces.UserRepository
+	sessionRepo      interfaces.UserSessionRepository
+	refreshTokenRepo interfaces.RefreshTokenRepository
+	challengeRepo    interfaces.LoginChallengeRepository
+	twoFactorService *TwoFactorService
+	loginAttempts    *LoginAttemptService
+	passwords        *PasswordService
+	sessions         *SessionService
+	jwtManager       *utils.JWTManager
+	sessionLifetime  time.Duration
 }
 
-// NewAuthService creates a new authentication service
+// NewAuthService creates a new authentication service. Sessions, and the refresh tokens that
+// keep them alive, last sessionLifetime from login, unless the session policy ends them sooner.
 func NewAuthService(
 	userRepo interfaces.UserRepository,
 	sessionRepo interfaces.UserSessionRepository,
+	refreshTokenRepo interfaces.RefreshTokenRepository,
+	challengeRepo interfaces.LoginChallengeRepository,
+	twoFactorService *TwoFactorService,
+	loginAttempts *LoginAttemptService,
+	passwords *PasswordService,
+	sessions *SessionService,
 	jwtManager *utils.JWTManager,
+	sessionLifetime time.Duration,
 ) *AuthService {
 	return &AuthService{
-		userRepo:    userRepo,
-		sessionRepo: sessionRepo,
-		jwtManager:  jwtManager,
+		userRepo:         userRepo,
+		sessionRepo:      sessionRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		challengeRepo:    challengeRepo,
+		twoFactorService: twoFactorService,
+		loginAttempts:    loginAttempts,
+		passwords:        passwords,
+		sessions:         sessions,
+		jwtManager:       jwtManager,
+		sessionLifetime:  sessionLifetime,
 	}
 }
 
-// Login authenticates a user and creates a session
-func (s *AuthService) Login(ctx context.Context, req *auth.LoginRequest, ipAddress, userAgent string) (*auth.LoginResponse, error) {
+// Login checks a user's password. Users without two-factor authentication get a session right
+// away. Users who have it enabled, or whose role requires it, get a short-lived challenge instead,
+// answered with VerifyLogin once they provide a code. Every attempt is logged, and repeated
+// failures slow down or lock out further attempts with a *user.LoginBlockedError.
+func (s *AuthService) Login(ctx context.Context, req *auth.LoginRequest, ipAddress, userAgent string) (*auth.LoginResponse, *auth.LoginChallengeResponse, error) {
+	attempt := &user.LoginAttempt{Username: req.Username, IPAddress: ipAddress, UserAgent: userAgent}
+
 	// Get user by username
-	foundUser, err := s.userRepo.GetByUsername(ctx, req.Username)
-	if err != nil {
-		return nil, fmt.Errorf("invalid credentials")
+	foundUser, lookupErr := s.userRepo.GetByUsername(ctx, req.Username)
+	userID := 0
+	if lookupErr == nil {
+		userID = foundUser.UserID
+		attempt.UserID = &foundUser.UserID
+	}
+
+	// Refuse the attempt while earlier failures are being throttled
+	if err := s.loginAttempts.Check(ctx, userID, ipAddress); err != nil {
+		var blocked *user.LoginBlockedError
+		if errors.As(err, &blocked) {
+			return nil, nil, s.rejectLogin(ctx, attempt, blocked.Outcome, err)
+		}
+		return nil, nil, err
+	}
+
+	if lookupErr != nil {
+		return nil, nil, s.rejectLogin(ctx, attempt, user.LoginOutcomeFailed, fmt.Errorf("invalid credentials"))
 	}
 
 	// Check if user is active
 	if !foundUser.IsActive {
-		return nil, fmt.Errorf("account is deactivated")
+		return nil, nil, s.rejectLogin(ctx, attempt, user.LoginOutcomeInactive, fmt.Errorf("account is deactivated"))
 	}
 
 	// Verify password
 	if !utils.CheckPassword(req.Password, foundUser.PasswordHash) {
-		return nil, fmt.Errorf("invalid credentials")
+		return nil, nil, s.rejectLogin(ctx, attempt, user.LoginOutcomeFailed, fmt.Errorf("invalid credentials"))
+	}
+
+	// Ask for a second factor before creating the session
+	enabled, err := s.twoFactorService.IsEnabled(ctx, foundUser.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if enabled || s.twoFactorService.Requires(foundUser.Role) {
+		challenge, err := s.createChallenge(ctx, foundUser, !enabled, ipAddress, userAgent)
+		if err != nil {
+			return nil, nil, err
+		}
+		attempt.Outcome = user.LoginOutcomeTwoFactorRequired
+		if err := s.loginAttempts.Record(ctx, attempt); err != nil {
+			return nil, nil, err
+		}
+		return nil, challenge, nil
+	}
+
+	response, err := s.startSession(ctx, foundUser, ipAddress, userAgent)
+	if err != nil {
+		return nil, nil, err
+	}
+	attempt.Outcome = user.LoginOutcomeSuccess
+	if err := s.loginAttempts.Record(ctx, attempt); err != nil {
+		return nil, nil, err
+	}
+	return response, nil, nil
+}
+
+// EnrollLogin starts the TOTP enrollment of a user whose role requires two-factor authentication
+// but who has not set it up yet. The code from the authenticator is then sent to VerifyLogin.
+func (s *AuthService) EnrollLogin(ctx context.Context, req *auth.LoginEnrollRequest) (*user.TwoFactorEnrollment, error) {
+	challenge, err := s.getChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.Enrolling {
+		return nil, user.ErrLoginChallengeNotEnrolling
+	}
+
+	return s.twoFactorService.BeginEnrollment(ctx, challenge.UserID)
+}
+
+// VerifyLogin answers a login challenge with a TOTP or recovery code and creates the session.
+// When the challenge was enrolling the user, the code confirms the enrollment and the response
+// carries the new recovery codes.
+func (s *AuthService) VerifyLogin(ctx context.Context, req *auth.LoginVerifyRequest, ipAddress, userAgent string) (*auth.LoginResponse, error) {
+	challenge, err := s.getChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	foundUser, err := s.userRepo.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	if !foundUser.IsActive {
+		return nil, fmt.Errorf("account is deactivated")
+	}
+
+	attempt := &user.LoginAttempt{Username: foundUser.Username, UserID: &foundUser.UserID, IPAddress: ipAddress, UserAgent: userAgent}
+	if err := s.loginAttempts.Check(ctx, foundUser.UserID, ipAddress); err != nil {
+		var blocked *user.LoginBlockedError
+		if errors.As(err, &blocked) {
+			return nil, s.rejectLogin(ctx, attempt, blocked.Outcome, err)
+		}
+		return nil, err
+	}
+
+	var recoveryCodes []string
+	if challenge.Enrolling {
+		var confirmed *user.RecoveryCodesResponse
+		confirmed, err = s.twoFactorService.ConfirmEnrollment(ctx, foundUser.UserID, req.Code)
+		if confirmed != nil {
+			recoveryCodes = confirmed.RecoveryCodes
+		}
+	} else {
+		err = s.twoFactorService.VerifyCode(ctx, foundUser.UserID, req.Code)
+	}
+	if err != nil {
+		if errors.Is(err, user.ErrTwoFactorCodeInvalid) {
+			if recordErr := s.challengeRepo.RecordFailedAttempt(ctx, challenge.ChallengeID); recordErr != nil {
+				return nil, recordErr
+			}
+			return nil, s.rejectLogin(ctx, attempt, user.LoginOutcomeTwoFactorFailed, err)
+		}
+		return nil, err
+	}
+
+	consumed, err := s.challengeRepo.Consume(ctx, challenge.ChallengeID)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, user.ErrLoginChallengeInvalid
+	}
+
+	response, err := s.startSession(ctx, foundUser, ipAddress, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	attempt.Outcome = user.LoginOutcomeSuccess
+	if err := s.loginAttempts.Record(ctx, attempt); err != nil {
+		return nil, err
+	}
+	response.RecoveryCodes = recoveryCodes
+	return response, nil
+}
+
+// rejectLogin logs a refused login attempt and returns the error to report for it
+func (s *AuthService) rejectLogin(ctx context.Context, attempt *user.LoginAttempt, outcome user.LoginOutcome, err error) error {
+	attempt.Outcome = outcome
+	if recordErr := s.loginAttempts.Record(ctx, attempt); recordErr != nil {
+		return recordErr
+	}
+	return err
+}
+
+// createChallenge stores a login challenge for a user whose password has been verified
+func (s *AuthService) createChallenge(ctx context.Context, foundUser *user.User, enrolling bool, ipAddress, userAgent string) (*auth.LoginChallengeResponse, error) {
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate challenge token: %w", err)
+	}
+
+	challenge := &user.LoginChallenge{
+		UserID:    foundUser.UserID,
+		TokenHash: utils.HashToken(token),
+		IPAddress: &ipAddress,
+		UserAgent: &userAgent,
+		Enrolling: enrolling,
+		ExpiresAt: time.Now().Add(user.LoginChallengeLifetime),
+	}
+	if err := s.challengeRepo.Create(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	message := "Two-factor code required"
+	if enrolling {
+		message = "Two-factor authentication must be set up"
+	}
+
+	return &auth.LoginChallengeResponse{
+		TwoFactorRequired:  true,
+		EnrollmentRequired: enrolling,
+		ChallengeToken:     token,
+		ExpiresIn:          int(user.LoginChallengeLifetime.Seconds()),
+		ExpiresAt:          challenge.ExpiresAt,
+		Message:            message,
+	}, nil
+}
+
+// getChallenge looks up a login challenge by its token and checks it can still be answered
+func (s *AuthService) getChallenge(ctx context.Context, token string) (*user.LoginChallenge, error) {
+	challenge, err := s.challengeRepo.GetByTokenHash(ctx, utils.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if err := challenge.Check(time.Now()); err != nil {
+		return nil, err
 	}
+	return challenge, nil
+}
 
+// startSession creates a session for an authenticated user and issues its first tokens
+func (s *AuthService) startSession(ctx context.Context, foundUser *user.User, ipAddress, userAgent string) (*auth.LoginResponse, error) {
 	// Generate session token
 	sessionToken, err := utils.GenerateSessionToken()
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate session token: %w", err)
 	}
 
-	// Create session
+	// Create session, limited to changing the password when it has expired or a change is forced
 	session := &user.UserSession{
-		UserID:       foundUser.UserID,
-		SessionToken: sessionToken,
-		IPAddress:    &ipAddress,
-		UserAgent:    &userAgent,
-		IsActive:     true,
+		UserID:                 foundUser.UserID,
+		SessionToken:           sessionToken,
+		IPAddress:              &ipAddress,
+		UserAgent:              &userAgent,
+		IsActive:               true,
+		PasswordChangeRequired: s.passwords.ChangeRequired(foundUser, time.Now()),
 	}
 
 	session, err = s.sessionRepo.Create(ctx, session)
@@ -69,6 +278,11 @@ func (s *AuthService) Login(ctx context.Context, req *auth.LoginRequest, ipAddre
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
+	// Log out the oldest devices beyond the role's concurrent session limit
+	if _, err := s.sessions.EnforceLimit(ctx, foundUser.UserID, foundUser.Role); err != nil {
+		return nil, err
+	}
+
 	// Generate JWT token
 	claims := &auth.TokenClaims{
 		UserID:    foundUser.UserID,
@@ -83,18 +297,36 @@ func (s *AuthService) Login(ctx context.Context, req *auth.LoginRequest, ipAddre
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
+	// Issue the first refresh token of the session
+	refreshToken, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	storedToken := &user.RefreshToken{
+		SessionID: session.SessionID,
+		TokenHash: utils.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.sessionLifetime),
+	}
+	if err := s.refreshTokenRepo.Create(ctx, storedToken); err != nil {
+		return nil, err
+	}
+
 	// Calculate expiration
 	duration := s.jwtManager.GetExpirationDuration()
 	expiresAt := time.Now().Add(duration)
 
 	return &auth.LoginResponse{
-		Token:     token,
-		TokenType: "Bearer",
-		ExpiresIn: int(duration.Seconds()),
-		ExpiresAt: expiresAt,
-		User:      auth.UserFromModel(foundUser),
-		Message:   "Login successful",
-		SessionID: session.SessionID,
+		Token:                 token,
+		TokenType:             "Bearer",
+		ExpiresIn:             int(duration.Seconds()),
+		ExpiresAt:             expiresAt,
+		User:                  auth.UserFromModel(foundUser),
+		Message:               "Login successful",
+		SessionID:             session.SessionID,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: storedToken.ExpiresAt,
+
+		PasswordChangeRequired: session.PasswordChangeRequired,
 	}, nil
 }
 
@@ -133,18 +365,14 @@ func (s *AuthService) GetProfile(ctx context.Context, userID int) (*auth.Profile
 	}, nil
 }
 
-// ChangePassword changes user password
+// ChangePassword changes user password. The new password must follow the password policy, and
+// changing it lifts a password change requirement from the user's sessions.
 func (s *AuthService) ChangePassword(ctx context.Context, userID int, req *auth.ChangePasswordRequest) error {
 	// Validate passwords match
 	if req.NewPassword != req.ConfirmPassword {
 		return fmt.Errorf("passwords do not match")
 	}
 
-	// Validate password strength
-	if err := utils.IsValidPassword(req.NewPassword); err != nil {
-		return err
-	}
-
 	// Get current user
 	foundUser, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -156,60 +384,63 @@ func (s *AuthService) ChangePassword(ctx context.Context, userID int, req *auth.
 		return fmt.Errorf("current password is incorrect")
 	}
 
-	// Hash new password
-	newHash, err := utils.HashPassword(req.NewPassword)
-	if err != nil {
-		return fmt.Errorf("failed to hash password: %w", err)
-	}
-
-	// Update password in user object
-	foundUser.PasswordHash = newHash
+	return s.passwords.SetPassword(ctx, foundUser, req.NewPassword)
+}
 
-	// Update user in database
-	_, err = s.userRepo.Update(ctx, userID, foundUser)
+// RefreshToken exchanges a refresh token for a new access token and a new refresh token. It does
+// not need a valid access token. Reusing a refresh token that was already exchanged revokes the
+// session it belongs to.
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*auth.RefreshTokenResponse, error) {
+	newRefreshToken, err := utils.GenerateSecureToken(32)
 	if err != nil {
-		return fmt.Errorf("failed to update password: %w", err)
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
-	return nil
-}
-
-// RefreshToken generates a new token from an existing one
-func (s *AuthService) RefreshToken(ctx context.Context, oldToken string) (*auth.RefreshTokenResponse, error) {
-	// Refresh the token
-	newToken, claims, err := s.jwtManager.RefreshToken(oldToken)
+	// Idle sessions and deactivated users are refused before the presented token is used up
+	storedToken := &user.RefreshToken{TokenHash: utils.HashToken(newRefreshToken)}
+	var userID int
+	err = s.refreshTokenRepo.Rotate(ctx, utils.HashToken(refreshToken), storedToken, func(session *user.UserSession, userActive bool) error {
+		if err := s.sessions.CheckActive(session, time.Now()); err != nil {
+			return err
+		}
+		if !userActive {
+			return fmt.Errorf("user account is deactivated")
+		}
+		userID = session.UserID
+		return nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to refresh token: %w", err)
 	}
 
-	// Verify session is still active
-	session, err := s.sessionRepo.GetByID(ctx, claims.SessionID)
-	if err != nil || !session.IsActive {
-		return nil, fmt.Errorf("session is no longer active")
-	}
-
 	// Get user info
-	foundUser, err := s.userRepo.GetByID(ctx, claims.UserID)
+	foundUser, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("user not found")
 	}
 
-	// Check if user is still active
-	if !foundUser.IsActive {
-		return nil, fmt.Errorf("user account is deactivated")
+	claims := &auth.TokenClaims{
+		UserID:    foundUser.UserID,
+		Username:  foundUser.Username,
+		Email:     foundUser.Email,
+		Role:      foundUser.Role,
+		SessionID: storedToken.SessionID,
 	}
 
-	// Calculate expiration
-	duration := s.jwtManager.GetExpirationDuration()
-	expiresAt := time.Now().Add(duration)
+	token, err := s.jwtManager.GenerateToken(claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
 
 	return &auth.RefreshTokenResponse{
-		Token:     newToken,
-		TokenType: "Bearer",
-		ExpiresIn: int(duration.Seconds()),
-		ExpiresAt: expiresAt,
-		User:      auth.UserFromModel(foundUser),
-		Message:   "Token refreshed successfully",
-		SessionID: session.SessionID,
+		Token:                 token,
+		TokenType:             "Bearer",
+		ExpiresIn:             int(s.jwtManager.GetExpirationDuration().Seconds()),
+		ExpiresAt:             claims.ExpiresAt,
+		User:                  auth.UserFromModel(foundUser),
+		Message:               "Token refreshed successfully",
+		SessionID:             storedToken.SessionID,
+		RefreshToken:          newRefreshToken,
+		RefreshTokenExpiresAt: storedToken.ExpiresAt,
 	}, nil
-}
\ No newline at end of file
+}
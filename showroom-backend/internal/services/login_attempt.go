@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// LoginAttemptService handles the login attempts log and brute-force protection: progressive
+// delays per user and per IP address, and temporary account lockout
+type LoginAttemptService struct {
+	attemptRepo interfaces.LoginAttemptRepository
+	userRepo    interfaces.UserRepository
+	policy      user.LoginThrottlePolicy
+}
+
+// NewLoginAttemptService creates a new login attempt service
+func NewLoginAttemptService(
+	attemptRepo interfaces.LoginAttemptRepository,
+	userRepo interfaces.UserRepository,
+	policy user.LoginThrottlePolicy,
+) *LoginAttemptService {
+	return &LoginAttemptService{
+		attemptRepo: attemptRepo,
+		userRepo:    userRepo,
+		policy:      policy,
+	}
+}
+
+// Check returns a *user.LoginBlockedError when a login for a user from an IP address must be
+// refused because of earlier failures. userID is zero for usernames that do not exist.
+func (s *LoginAttemptService) Check(ctx context.Context, userID int, ipAddress string) error {
+	now := time.Now()
+
+	var lock *user.LoginLock
+	if userID != 0 {
+		var err error
+		if lock, err = s.attemptRepo.GetLock(ctx, userID); err != nil {
+			return err
+		}
+	}
+
+	ipFailures, lastIPFailure, err := s.attemptRepo.CountIPFailures(ctx, ipAddress, now.Add(-s.policy.IPWindow))
+	if err != nil {
+		return err
+	}
+
+	if wait, outcome := s.policy.RetryAfter(lock, ipFailures, lastIPFailure, now); wait > 0 {
+		return &user.LoginBlockedError{Outcome: outcome, RetryAfter: wait}
+	}
+	return nil
+}
+
+// CheckPasswordReset returns a *user.LoginBlockedError when a password reset request for a username
+// from an IP address must be refused: the address is throttled for failed logins, or too many resets
+// were requested for the username or from the address. Accepted requests are logged so they count
+// towards the limits. The username is not looked up.
+func (s *LoginAttemptService) CheckPasswordReset(ctx context.Context, username, ipAddress, userAgent string) error {
+	if err := s.Check(ctx, 0, ipAddress); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	requests, err := s.attemptRepo.CountPasswordResetRequests(ctx, username, ipAddress, now.Add(-user.PasswordResetRequestWindow))
+	if err != nil {
+		return err
+	}
+	if wait := requests.RetryAfter(now); wait > 0 {
+		return &user.LoginBlockedError{Outcome: user.LoginOutcomeThrottled, RetryAfter: wait}
+	}
+
+	return s.attemptRepo.Create(ctx, &user.LoginAttempt{
+		Username:  username,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Outcome:   user.LoginOutcomePasswordReset,
+	})
+}
+
+// Record logs a login attempt. Failures of a known user count towards locking their account and
+// a successful login clears them.
+func (s *LoginAttemptService) Record(ctx context.Context, attempt *user.LoginAttempt) error {
+	if err := s.attemptRepo.Create(ctx, attempt); err != nil {
+		return err
+	}
+	if attempt.UserID == nil {
+		return nil
+	}
+
+	switch {
+	case attempt.Outcome.IsFailure():
+		if _, err := s.attemptRepo.RecordFailure(ctx, *attempt.UserID, s.policy.MaxFailures, s.policy.Lockout); err != nil {
+			return err
+		}
+	case attempt.Outcome == user.LoginOutcomeSuccess:
+		if err := s.attemptRepo.ResetLock(ctx, *attempt.UserID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Unlock clears the failed logins and lock of a user
+func (s *LoginAttemptService) Unlock(ctx context.Context, userID int) error {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return fmt.Errorf("user not found")
+	}
+
+	return s.attemptRepo.ResetLock(ctx, userID)
+}
+
+// GetLock returns the failed login state of a user
+func (s *LoginAttemptService) GetLock(ctx context.Context, userID int) (*user.LoginLock, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	return s.attemptRepo.GetLock(ctx, userID)
+}
+
+// ListAttempts retrieves the login attempts log with filtering and pagination
+func (s *LoginAttemptService) ListAttempts(ctx context.Context, params *user.LoginAttemptFilterParams) (*common.PaginatedResponse, error) {
+	if params.Outcome != nil && !params.Outcome.IsValid() {
+		return nil, fmt.Errorf("invalid outcome: %s", *params.Outcome)
+	}
+
+	params.Validate()
+
+	attempts, total, err := s.attemptRepo.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list login attempts: %w", err)
+	}
+
+	return &common.PaginatedResponse{
+		Data:       attempts,
+		Total:      total,
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: params.GetTotalPages(total),
+		HasMore:    params.GetHasMore(total),
+	}, nil
+}
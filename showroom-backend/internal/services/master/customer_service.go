@@ -68,6 +68,8 @@ func (s *CustomerService) CreateCustomer(ctx context.Context, req *master.Custom
 		TaxNumber:     req.TaxNumber,
 		ContactPerson: req.ContactPerson,
 		Notes:         req.Notes,
+		CreditLimit:   req.CreditLimit,
+		PaymentTermID: req.PaymentTermID,
 		CreatedBy:     createdBy,
 	}
 
@@ -138,6 +140,8 @@ func (s *CustomerService) UpdateCustomer(ctx context.Context, id int, req *maste
 		TaxNumber:    existing.TaxNumber,
 		ContactPerson: existing.ContactPerson,
 		Notes:        existing.Notes,
+		CreditLimit:  existing.CreditLimit,
+		PaymentTermID: existing.PaymentTermID,
 		IsActive:     existing.IsActive,
 		CreatedAt:    existing.CreatedAt,
 		CreatedBy:    existing.CreatedBy,
@@ -173,6 +177,12 @@ func (s *CustomerService) UpdateCustomer(ctx context.Context, id int, req *maste
 	if req.Notes != nil {
 		updatedCustomer.Notes = req.Notes
 	}
+	if req.CreditLimit != nil {
+		updatedCustomer.CreditLimit = *req.CreditLimit
+	}
+	if req.PaymentTermID != nil {
+		updatedCustomer.PaymentTermID = req.PaymentTermID
+	}
 	if req.IsActive != nil {
 		updatedCustomer.IsActive = *req.IsActive
 	}
@@ -69,6 +69,7 @@ func (s *SupplierService) CreateSupplier(ctx context.Context, req *master.Suppli
 		ContactPerson: strings.TrimSpace(req.ContactPerson),
 		BankAccount:   req.BankAccount,
 		PaymentTerms:  req.PaymentTerms,
+		PaymentTermID: req.PaymentTermID,
 		Notes:         req.Notes,
 		CreatedBy:     createdBy,
 	}
@@ -141,6 +142,7 @@ func (s *SupplierService) UpdateSupplier(ctx context.Context, id int, req *maste
 		ContactPerson: existing.ContactPerson,
 		BankAccount:   existing.BankAccount,
 		PaymentTerms:  existing.PaymentTerms,
+		PaymentTermID: existing.PaymentTermID,
 		Notes:         existing.Notes,
 		IsActive:      existing.IsActive,
 		CreatedAt:     existing.CreatedAt,
@@ -180,6 +182,9 @@ func (s *SupplierService) UpdateSupplier(ctx context.Context, id int, req *maste
 	if req.PaymentTerms != nil {
 		updatedSupplier.PaymentTerms = req.PaymentTerms
 	}
+	if req.PaymentTermID != nil {
+		updatedSupplier.PaymentTermID = req.PaymentTermID
+	}
 	if req.Notes != nil {
 		updatedSupplier.Notes = req.Notes
 	}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
+)
+
+// PasswordService enforces the password policy: the rules for new passwords, no reuse of recent
+// ones, the maximum password age and password changes forced by administrators
+type PasswordService struct {
+	userRepo    interfaces.UserRepository
+	sessionRepo interfaces.UserSessionRepository
+	policy      utils.PasswordPolicy
+}
+
+// NewPasswordService creates a new password service
+func NewPasswordService(
+	userRepo interfaces.UserRepository,
+	sessionRepo interfaces.UserSessionRepository,
+	policy utils.PasswordPolicy,
+) *PasswordService {
+	return &PasswordService{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		policy:      policy,
+	}
+}
+
+// GetPolicy returns the password policy, so clients can show the rules
+func (s *PasswordService) GetPolicy() utils.PasswordPolicy {
+	return s.policy
+}
+
+// Validate checks a new password against the policy and, for an existing user, against their
+// recent passwords. u is nil for a user who is being created.
+func (s *PasswordService) Validate(ctx context.Context, u *user.User, password string) error {
+	if err := s.policy.Validate(password); err != nil {
+		return err
+	}
+	if u == nil || s.policy.HistoryCount <= 0 {
+		return nil
+	}
+
+	hashes := []string{u.PasswordHash}
+	if s.policy.HistoryCount > 1 {
+		history, err := s.userRepo.ListPasswordHistory(ctx, u.UserID, s.policy.HistoryCount-1)
+		if err != nil {
+			return err
+		}
+		hashes = append(hashes, history...)
+	}
+
+	for _, hash := range hashes {
+		if utils.CheckPassword(password, hash) {
+			return fmt.Errorf("password must not be one of your last %d passwords", s.policy.HistoryCount)
+		}
+	}
+	return nil
+}
+
+// SetPassword validates and stores a new password for a user, then lifts the password change
+// requirement from their sessions
+func (s *PasswordService) SetPassword(ctx context.Context, u *user.User, password string) error {
+	if err := s.Validate(ctx, u, password); err != nil {
+		return err
+	}
+
+	return s.storePassword(ctx, u, password)
+}
+
+// storePassword stores a new password that has already been validated
+func (s *PasswordService) storePassword(ctx context.Context, u *user.User, password string) error {
+	newHash, err := utils.HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, u.UserID, newHash); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	return s.sessionRepo.SetPasswordChangeRequired(ctx, u.UserID, false)
+}
+
+// ChangeRequired reports whether a user must change their password before doing anything else,
+// because an administrator forced it or the password is older than the maximum age
+func (s *PasswordService) ChangeRequired(u *user.User, now time.Time) bool {
+	return u.MustChangePassword || s.policy.IsExpired(u.PasswordChangedAt, now)
+}
+
+// ForceChange makes a user change their password. Their current sessions are limited to changing
+// it right away, and so is every login until they do.
+func (s *PasswordService) ForceChange(ctx context.Context, userID int) error {
+	if err := s.userRepo.SetMustChangePassword(ctx, userID, true); err != nil {
+		return err
+	}
+
+	return s.sessionRepo.SetPasswordChangeRequired(ctx, userID, true)
+}
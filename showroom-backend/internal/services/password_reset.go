@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/auth"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/notify"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
+)
+
+// passwordResetSendTimeout bounds the background work of a forgot password request
+const passwordResetSendTimeout = 30 * time.Second
+
+// Forgot password requests are sent by a fixed pool of workers from a bounded queue, so a burst of
+// requests cannot start unbounded background work. Requests arriving while the queue is full are dropped.
+const (
+	passwordResetWorkers   = 4
+	passwordResetQueueSize = 100
+)
+
+// passwordResetRequest is a queued forgot password request
+type passwordResetRequest struct {
+	username  string
+	ipAddress string
+}
+
+// PasswordResetService handles self-service password resets through single-use, time-limited
+// tokens sent by the notifier
+type PasswordResetService struct {
+	userRepo      interfaces.UserRepository
+	sessionRepo   interfaces.UserSessionRepository
+	resetRepo     interfaces.PasswordResetRepository
+	loginAttempts *LoginAttemptService
+	passwords     *PasswordService
+	notifier      notify.Notifier
+	resetURL      string
+	tokenLifetime time.Duration
+	queue         chan passwordResetRequest
+}
+
+// NewPasswordResetService creates a new password reset service and starts its send workers. resetURL
+// is the page the emailed link opens, with the token added as its token query parameter.
+func NewPasswordResetService(
+	userRepo interfaces.UserRepository,
+	sessionRepo interfaces.UserSessionRepository,
+	resetRepo interfaces.PasswordResetRepository,
+	loginAttempts *LoginAttemptService,
+	passwords *PasswordService,
+	notifier notify.Notifier,
+	resetURL string,
+	tokenLifetime time.Duration,
+) *PasswordResetService {
+	s := &PasswordResetService{
+		userRepo:      userRepo,
+		sessionRepo:   sessionRepo,
+		resetRepo:     resetRepo,
+		loginAttempts: loginAttempts,
+		passwords:     passwords,
+		notifier:      notifier,
+		resetURL:      resetURL,
+		tokenLifetime: tokenLifetime,
+		queue:         make(chan passwordResetRequest, passwordResetQueueSize),
+	}
+	for i := 0; i < passwordResetWorkers; i++ {
+		go s.sendQueuedResetLinks()
+	}
+	return s
+}
+
+// ForgotPassword starts a password reset for a username. Requests are throttled per username and
+// IP address before any work is done, returning a *user.LoginBlockedError. Otherwise it returns at
+// once and behaves the same whether or not the username exists: the lookup, the token and the email
+// all happen in the background, so neither the response nor its timing tells callers anything.
+func (s *PasswordResetService) ForgotPassword(ctx context.Context, req *auth.ForgotPasswordRequest, ipAddress, userAgent string) error {
+	if err := s.loginAttempts.CheckPasswordReset(ctx, req.Username, ipAddress, userAgent); err != nil {
+		return err
+	}
+
+	select {
+	case s.queue <- passwordResetRequest{username: req.Username, ipAddress: ipAddress}:
+	default:
+		log.Printf("Password reset for %q dropped: the send queue is full", req.Username)
+	}
+	return nil
+}
+
+// sendQueuedResetLinks works through the queued forgot password requests
+func (s *PasswordResetService) sendQueuedResetLinks() {
+	for req := range s.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), passwordResetSendTimeout)
+		if err := s.sendResetLink(ctx, req.username, req.ipAddress); err != nil {
+			log.Printf("Password reset for %q: %v", req.username, err)
+		}
+		cancel()
+	}
+}
+
+// sendResetLink issues a reset token to an active user and emails it. Unknown usernames, inactive
+// users and users over the request cap are skipped without an error.
+func (s *PasswordResetService) sendResetLink(ctx context.Context, username, ipAddress string) error {
+	foundUser, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil || !foundUser.IsActive || foundUser.Email == "" {
+		return nil
+	}
+
+	recent, err := s.resetRepo.CountSince(ctx, foundUser.UserID, time.Now().Add(-user.PasswordResetRequestWindow))
+	if err != nil {
+		return err
+	}
+	if recent >= user.PasswordResetMaxRequests {
+		return nil
+	}
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return err
+	}
+
+	resetToken := &user.PasswordResetToken{
+		UserID:    foundUser.UserID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(s.tokenLifetime),
+	}
+	if ipAddress != "" {
+		resetToken.IPAddress = &ipAddress
+	}
+	if err := s.resetRepo.Create(ctx, resetToken); err != nil {
+		return err
+	}
+
+	return s.notifier.Send(ctx, notify.Message{
+		To:      foundUser.Email,
+		Subject: "Reset your password",
+		Body:    s.resetEmailBody(foundUser, token),
+	})
+}
+
+// resetEmailBody renders the text of the password reset email
+func (s *PasswordResetService) resetEmailBody(u *user.User, token string) string {
+	link := token
+	if resetURL, err := url.Parse(s.resetURL); err == nil && s.resetURL != "" {
+		query := resetURL.Query()
+		query.Set("token", token)
+		resetURL.RawQuery = query.Encode()
+		link = resetURL.String()
+	}
+
+	return fmt.Sprintf(
+		"Hello %s,\n\n"+
+			"We received a request to reset the password of your account %s. "+
+			"Use the link below to choose a new password:\n\n%s\n\n"+
+			"The link expires in %d minutes and can be used once. "+
+			"If you did not ask for a password reset, you can ignore this email.",
+		u.FullName, u.Username, link, int(s.tokenLifetime.Minutes()),
+	)
+}
+
+// ResetPassword sets a new password with a reset token. The password must follow the password
+// policy. The token is consumed, every session of the user is revoked and any login lockout is
+// cleared, since the user has proven they own the account's email address.
+func (s *PasswordResetService) ResetPassword(ctx context.Context, req *auth.ResetPasswordRequest) error {
+	// Validate passwords match
+	if req.NewPassword != req.ConfirmPassword {
+		return fmt.Errorf("passwords do not match")
+	}
+
+	resetToken, err := s.resetRepo.GetByTokenHash(ctx, utils.HashToken(req.Token))
+	if err != nil {
+		return err
+	}
+	if err := resetToken.Check(time.Now()); err != nil {
+		return err
+	}
+
+	foundUser, err := s.userRepo.GetByID(ctx, resetToken.UserID)
+	if err != nil || !foundUser.IsActive {
+		return user.ErrPasswordResetTokenInvalid
+	}
+
+	if err := s.passwords.Validate(ctx, foundUser, req.NewPassword); err != nil {
+		return err
+	}
+
+	consumed, err := s.resetRepo.Consume(ctx, resetToken.TokenID)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return user.ErrPasswordResetTokenInvalid
+	}
+
+	if err := s.passwords.storePassword(ctx, foundUser, req.NewPassword); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.RevokeAllUserSessions(ctx, foundUser.UserID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return s.loginAttempts.Unlock(ctx, foundUser.UserID)
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// permissionCacheTTL bounds how long another server instance can keep serving permissions
+// that were changed elsewhere
+const permissionCacheTTL = time.Minute
+
+// PermissionService handles the permissions granted to roles. Grants are cached per role
+// because every authorized request checks them.
+type PermissionService struct {
+	rolePermissionRepo interfaces.RolePermissionRepository
+
+	mu       sync.RWMutex
+	cache    map[string]map[string]bool
+	cachedAt map[string]time.Time
+}
+
+// NewPermissionService creates a new permission service
+func NewPermissionService(rolePermissionRepo interfaces.RolePermissionRepository) *PermissionService {
+	return &PermissionService{
+		rolePermissionRepo: rolePermissionRepo,
+		cache:              map[string]map[string]bool{},
+		cachedAt:           map[string]time.Time{},
+	}
+}
+
+// HasPermission reports whether a role has been granted a permission
+func (s *PermissionService) HasPermission(ctx context.Context, role, permission string) (bool, error) {
+	if user.RoleHasAllPermissions(common.UserRole(role)) {
+		return true, nil
+	}
+
+	granted, err := s.rolePermissions(ctx, role)
+	if err != nil {
+		return false, err
+	}
+	return granted[permission], nil
+}
+
+// ListPermissions returns every permission that can be granted
+func (s *PermissionService) ListPermissions() []user.PermissionDefinition {
+	return user.Permissions
+}
+
+// ListRoles returns the permissions granted to each role
+func (s *PermissionService) ListRoles(ctx context.Context) ([]user.RolePermissions, error) {
+	granted, err := s.rolePermissionRepo.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := []user.RolePermissions{}
+	for _, role := range []common.UserRole{common.RoleAdmin, common.RoleManager, common.RoleSales, common.RoleCashier, common.RoleMechanic} {
+		roles = append(roles, s.roleView(role, granted[role.String()]))
+	}
+	return roles, nil
+}
+
+// GetRolePermissions returns the permissions granted to a role
+func (s *PermissionService) GetRolePermissions(ctx context.Context, role common.UserRole) (*user.RolePermissions, error) {
+	if !role.IsValid() {
+		return nil, fmt.Errorf("invalid role: %s", role)
+	}
+
+	granted, err := s.rolePermissionRepo.GetByRole(ctx, role.String())
+	if err != nil {
+		return nil, err
+	}
+
+	view := s.roleView(role, granted)
+	return &view, nil
+}
+
+// UpdateRolePermissions replaces the permissions granted to a role. The admin role always has
+// every permission and cannot be edited.
+func (s *PermissionService) UpdateRolePermissions(ctx context.Context, role common.UserRole, req *user.RolePermissionsUpdateRequest, grantedBy int) (*user.RolePermissions, error) {
+	if !role.IsValid() {
+		return nil, fmt.Errorf("invalid role: %s", role)
+	}
+	if user.RoleHasAllPermissions(role) {
+		return nil, fmt.Errorf("role %s always has every permission and cannot be edited", role)
+	}
+
+	permissions, err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.rolePermissionRepo.ReplaceForRole(ctx, role.String(), permissions, grantedBy); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	delete(s.cache, role.String())
+	delete(s.cachedAt, role.String())
+	s.mu.Unlock()
+
+	return &user.RolePermissions{Role: role, Permissions: permissions}, nil
+}
+
+// roleView lists the permissions of a role, expanding roles that hold every permission
+func (s *PermissionService) roleView(role common.UserRole, granted []string) user.RolePermissions {
+	if user.RoleHasAllPermissions(role) {
+		return user.RolePermissions{Role: role, Permissions: user.AllPermissionCodes()}
+	}
+	if granted == nil {
+		granted = []string{}
+	}
+	return user.RolePermissions{Role: role, Permissions: granted}
+}
+
+// rolePermissions returns the cached grants of a role, loading them when missing or stale
+func (s *PermissionService) rolePermissions(ctx context.Context, role string) (map[string]bool, error) {
+	s.mu.RLock()
+	granted, ok := s.cache[role]
+	fresh := ok && time.Since(s.cachedAt[role]) < permissionCacheTTL
+	s.mu.RUnlock()
+	if fresh {
+		return granted, nil
+	}
+
+	codes, err := s.rolePermissionRepo.GetByRole(ctx, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load permissions of role %s: %w", role, err)
+	}
+
+	granted = make(map[string]bool, len(codes))
+	for _, code := range codes {
+		granted[code] = true
+	}
+
+	s.mu.Lock()
+	s.cache[role] = granted
+	s.cachedAt[role] = time.Now()
+	s.mu.Unlock()
+
+	return granted, nil
+}
@@ -0,0 +1,197 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// BankReconciliationService handles bank statement imports and their reconciliation against payments
+type BankReconciliationService struct {
+	statementRepo interfaces.BankStatementRepository
+}
+
+// NewBankReconciliationService creates a new bank reconciliation service
+func NewBankReconciliationService(statementRepo interfaces.BankStatementRepository) *BankReconciliationService {
+	return &BankReconciliationService{
+		statementRepo: statementRepo,
+	}
+}
+
+// ImportStatement parses a CSV bank statement with the column mapping, stores its new lines
+// and matches them to payments with the default date window
+func (s *BankReconciliationService) ImportStatement(ctx context.Context, bankAccount, fileName string, r io.Reader, mapping products.BankStatementColumnMapping, importedBy int) (*products.BankStatement, error) {
+	bankAccount = strings.TrimSpace(bankAccount)
+	if bankAccount == "" {
+		return nil, fmt.Errorf("bank account is required")
+	}
+
+	lines, err := products.ParseBankStatementCSV(r, mapping)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bank statement: %w", err)
+	}
+
+	statement := &products.BankStatement{
+		BankAccount: bankAccount,
+		FileName:    fileName,
+		ImportedBy:  importedBy,
+	}
+
+	imported, err := s.statementRepo.Import(ctx, statement, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.autoMatch(ctx, imported, products.DefaultBankMatchWindowDays, importedBy); err != nil {
+		return nil, err
+	}
+
+	statement, err = s.statementRepo.GetByID(ctx, imported.StatementID, &products.BankStatementLineFilterParams{})
+	if err != nil {
+		return nil, err
+	}
+	statement.SkippedLines = imported.SkippedLines
+	return statement, nil
+}
+
+// GetStatement retrieves a bank statement with its lines, optionally only those in one match status
+func (s *BankReconciliationService) GetStatement(ctx context.Context, id int, params *products.BankStatementLineFilterParams) (*products.BankStatement, error) {
+	if params.MatchStatus != nil && !params.MatchStatus.IsValid() {
+		return nil, fmt.Errorf("invalid match status: %s", *params.MatchStatus)
+	}
+
+	return s.statementRepo.GetByID(ctx, id, params)
+}
+
+// ListStatements retrieves imported bank statements with filtering and pagination
+func (s *BankReconciliationService) ListStatements(ctx context.Context, params *products.BankStatementFilterParams) (*common.PaginatedResponse, error) {
+	statements, err := s.statementRepo.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bank statements: %w", err)
+	}
+
+	return statements, nil
+}
+
+// DeleteStatement removes a statement imported by mistake. Matched lines must be unmatched first.
+func (s *BankReconciliationService) DeleteStatement(ctx context.Context, id int) error {
+	return s.statementRepo.Delete(ctx, id)
+}
+
+// AutoMatch matches the unmatched lines of a statement to payments of the same amount within the date window
+func (s *BankReconciliationService) AutoMatch(ctx context.Context, id int, req *products.BankAutoMatchRequest, matchedBy int) (*products.BankAutoMatchResult, error) {
+	statement, err := s.statementRepo.GetByID(ctx, id, &products.BankStatementLineFilterParams{})
+	if err != nil {
+		return nil, err
+	}
+
+	windowDays := products.DefaultBankMatchWindowDays
+	if req.DateWindowDays != nil {
+		windowDays = *req.DateWindowDays
+	}
+
+	matched, err := s.autoMatch(ctx, statement, windowDays, matchedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &products.BankAutoMatchResult{
+		StatementID:    statement.StatementID,
+		MatchedLines:   matched,
+		UnmatchedLines: statement.LineCount - statement.MatchedCount - matched,
+	}, nil
+}
+
+// MatchLine manually reconciles a debit statement line with a posted payment, or a credit line
+// with a posted customer receipt, of the same amount
+func (s *BankReconciliationService) MatchLine(ctx context.Context, lineID int, req *products.BankManualMatchRequest, matchedBy int) (*products.BankStatementLine, error) {
+	if (req.VoucherID == 0) == (req.ReceiptID == 0) {
+		return nil, fmt.Errorf("either voucher_id or receipt_id is required")
+	}
+
+	line, err := s.statementRepo.GetLine(ctx, lineID)
+	if err != nil {
+		return nil, err
+	}
+
+	if line.MatchStatus != products.BankMatchStatusUnmatched {
+		return nil, fmt.Errorf("bank statement line is already matched")
+	}
+
+	var candidate *products.BankMatchCandidate
+	if req.VoucherID != 0 {
+		if line.Amount >= 0 {
+			return nil, fmt.Errorf("only outgoing bank transactions can be matched to supplier payments")
+		}
+		candidate, err = s.statementRepo.GetMatchCandidate(ctx, req.VoucherID)
+	} else {
+		if line.Amount <= 0 {
+			return nil, fmt.Errorf("only incoming bank transactions can be matched to customer receipts")
+		}
+		candidate, err = s.statementRepo.GetReceiptMatchCandidate(ctx, req.ReceiptID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if math.Abs(math.Abs(line.Amount)-candidate.Amount) > 0.005 {
+		return nil, fmt.Errorf("%s amount %.2f does not match the bank transaction amount %.2f",
+			candidate.DocumentNumber, candidate.Amount, math.Abs(line.Amount))
+	}
+
+	matches := []products.BankStatementMatch{candidate.Match(line.LineID)}
+	applied, err := s.statementRepo.ApplyMatches(ctx, matches, products.BankMatchMethodManual, matchedBy)
+	if err != nil {
+		return nil, err
+	}
+	if applied == 0 {
+		return nil, fmt.Errorf("bank statement line or %s was reconciled in the meantime", candidate.DocumentNumber)
+	}
+
+	return s.statementRepo.GetLine(ctx, lineID)
+}
+
+// UnmatchLine clears the payment or receipt matched to a statement line
+func (s *BankReconciliationService) UnmatchLine(ctx context.Context, lineID int) (*products.BankStatementLine, error) {
+	if err := s.statementRepo.Unmatch(ctx, lineID); err != nil {
+		return nil, err
+	}
+
+	return s.statementRepo.GetLine(ctx, lineID)
+}
+
+// GetReconciliation summarizes matched and unmatched statement lines per bank account for a period
+func (s *BankReconciliationService) GetReconciliation(ctx context.Context, params *products.BankReconciliationParams) (*products.BankReconciliationReport, error) {
+	if params.DateFrom != nil && params.DateTo != nil && params.DateTo.Before(*params.DateFrom) {
+		return nil, fmt.Errorf("date_to must not be before date_from")
+	}
+
+	return s.statementRepo.GetReconciliation(ctx, params)
+}
+
+// autoMatch matches the unmatched lines of a loaded statement and returns how many were matched
+func (s *BankReconciliationService) autoMatch(ctx context.Context, statement *products.BankStatement, windowDays, matchedBy int) (int, error) {
+	if statement.PeriodStart == nil || statement.PeriodEnd == nil {
+		return 0, nil
+	}
+
+	dateFrom := statement.PeriodStart.AddDate(0, 0, -windowDays)
+	dateTo := statement.PeriodEnd.AddDate(0, 0, windowDays)
+	candidates, err := s.statementRepo.GetMatchCandidates(ctx, dateFrom, dateTo)
+	if err != nil {
+		return 0, err
+	}
+
+	matches := products.MatchBankStatementLines(statement.Lines, candidates, windowDays)
+	if len(matches) == 0 {
+		return 0, nil
+	}
+
+	return s.statementRepo.ApplyMatches(ctx, matches, products.BankMatchMethodAuto, matchedBy)
+}
@@ -0,0 +1,151 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// CustomerAccountService handles customer receivables: receipts and their allocation to sales
+// invoices, credit status and statements of account
+type CustomerAccountService struct {
+	receiptRepo  interfaces.CustomerReceiptRepository
+	invoiceRepo  interfaces.SalesInvoiceRepository
+	customerRepo interfaces.CustomerRepository
+}
+
+// NewCustomerAccountService creates a new customer account service
+func NewCustomerAccountService(
+	receiptRepo interfaces.CustomerReceiptRepository,
+	invoiceRepo interfaces.SalesInvoiceRepository,
+	customerRepo interfaces.CustomerRepository,
+) *CustomerAccountService {
+	return &CustomerAccountService{
+		receiptRepo:  receiptRepo,
+		invoiceRepo:  invoiceRepo,
+		customerRepo: customerRepo,
+	}
+}
+
+// CreateReceipt records money received from a customer and allocates it to their sales invoices
+func (s *CustomerAccountService) CreateReceipt(ctx context.Context, req *products.CustomerReceiptCreateRequest, receivedBy int) (*products.CustomerReceipt, error) {
+	if !req.PaymentMethod.IsValid() || req.PaymentMethod == products.PaymentMethodCredit {
+		return nil, fmt.Errorf("invalid payment method: %s", req.PaymentMethod)
+	}
+
+	if _, err := s.customerRepo.GetByID(ctx, req.CustomerID); err != nil {
+		return nil, fmt.Errorf("customer not found: %w", err)
+	}
+
+	receiptDate := time.Now()
+	if req.ReceiptDate != nil {
+		receiptDate = *req.ReceiptDate
+	}
+
+	receipt := &products.CustomerReceipt{
+		CustomerID:       req.CustomerID,
+		ReceiptDate:      receiptDate,
+		PaymentMethod:    req.PaymentMethod,
+		PaymentReference: req.PaymentReference,
+		Amount:           req.Amount,
+		ReceiptStatus:    products.CustomerReceiptStatusPosted,
+		Notes:            req.Notes,
+		ReceivedBy:       receivedBy,
+	}
+
+	allocations := req.Allocations
+	if len(allocations) == 0 && req.AutoAllocate {
+		openInvoices, err := s.invoiceRepo.GetOpenInvoices(ctx, req.CustomerID, receiptDate)
+		if err != nil {
+			return nil, err
+		}
+		allocations = receipt.AutoAllocate(openInvoices)
+	}
+
+	return s.receiptRepo.Create(ctx, receipt, allocations)
+}
+
+// GetReceipt retrieves a customer receipt with its allocations
+func (s *CustomerAccountService) GetReceipt(ctx context.Context, id int) (*products.CustomerReceipt, error) {
+	return s.receiptRepo.GetByID(ctx, id)
+}
+
+// ListReceipts retrieves customer receipts with filtering and pagination
+func (s *CustomerAccountService) ListReceipts(ctx context.Context, params *products.CustomerReceiptFilterParams) (*common.PaginatedResponse, error) {
+	if params.ReceiptStatus != nil && !params.ReceiptStatus.IsValid() {
+		return nil, fmt.Errorf("invalid receipt status: %s", *params.ReceiptStatus)
+	}
+	if params.PaymentMethod != nil && !params.PaymentMethod.IsValid() {
+		return nil, fmt.Errorf("invalid payment method: %s", *params.PaymentMethod)
+	}
+
+	receipts, err := s.receiptRepo.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customer receipts: %w", err)
+	}
+
+	return receipts, nil
+}
+
+// AllocateReceipt allocates the unallocated amount of a receipt to sales invoices
+func (s *CustomerAccountService) AllocateReceipt(ctx context.Context, id int, req *products.CustomerReceiptAllocateRequest) (*products.CustomerReceipt, error) {
+	return s.receiptRepo.Allocate(ctx, id, req.Allocations)
+}
+
+// VoidReceipt voids a receipt, reopening the invoices it settled
+func (s *CustomerAccountService) VoidReceipt(ctx context.Context, id int, voidedBy int) (*products.CustomerReceipt, error) {
+	if err := s.receiptRepo.Void(ctx, id, voidedBy); err != nil {
+		return nil, err
+	}
+
+	return s.receiptRepo.GetByID(ctx, id)
+}
+
+// GetCreditStatus returns the open receivables of a customer against their credit limit
+func (s *CustomerAccountService) GetCreditStatus(ctx context.Context, customerID int) (*products.CustomerCreditStatus, error) {
+	customer, err := s.customerRepo.GetByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	openInvoices, err := s.invoiceRepo.GetOpenInvoices(ctx, customerID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return products.NewCustomerCreditStatus(customer.CustomerID, customer.CustomerCode, customer.CustomerName,
+		customer.CreditLimit, customer.PaymentTermID, openInvoices, now), nil
+}
+
+// GetStatement builds the statement of account of a customer for a period, the previous
+// calendar month unless dates are given
+func (s *CustomerAccountService) GetStatement(ctx context.Context, customerID int, params *products.CustomerStatementParams) (*products.CustomerStatement, error) {
+	periodStart, periodEnd, err := params.Period(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.customerRepo.GetByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	openingBalance, lines, err := s.receiptRepo.GetStatementActivity(ctx, customerID, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	openInvoices, err := s.invoiceRepo.GetOpenInvoices(ctx, customerID, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	return products.NewCustomerStatement(customer.CustomerID, customer.CustomerCode, customer.CustomerName,
+		customer.Address+", "+customer.City, customer.CreditLimit, periodStart, periodEnd,
+		openingBalance, lines, openInvoices), nil
+}
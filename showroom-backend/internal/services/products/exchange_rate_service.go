@@ -0,0 +1,125 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// ExchangeRateService handles dated exchange rates entered manually or imported from CSV
+type ExchangeRateService struct {
+	exchangeRateRepo interfaces.ExchangeRateRepository
+}
+
+// NewExchangeRateService creates a new exchange rate service
+func NewExchangeRateService(exchangeRateRepo interfaces.ExchangeRateRepository) *ExchangeRateService {
+	return &ExchangeRateService{
+		exchangeRateRepo: exchangeRateRepo,
+	}
+}
+
+// CreateRate enters the rate of a foreign currency on a date, replacing any rate already entered for it
+func (s *ExchangeRateService) CreateRate(ctx context.Context, req *products.ExchangeRateCreateRequest, createdBy int) (*products.ExchangeRate, error) {
+	currencyCode, err := foreignCurrencyCode(req.CurrencyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	rate := &products.ExchangeRate{
+		CurrencyCode: currencyCode,
+		RateDate:     req.RateDate,
+		Rate:         req.Rate,
+		Source:       products.ExchangeRateSourceManual,
+		CreatedBy:    createdBy,
+	}
+
+	return s.exchangeRateRepo.Upsert(ctx, rate)
+}
+
+// ListRates retrieves exchange rates with filtering and pagination
+func (s *ExchangeRateService) ListRates(ctx context.Context, params *products.ExchangeRateFilterParams) (*common.PaginatedResponse, error) {
+	rates, err := s.exchangeRateRepo.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list exchange rates: %w", err)
+	}
+
+	return rates, nil
+}
+
+// GetRateOn returns the rate of a currency in effect on a date, today when no date is given
+func (s *ExchangeRateService) GetRateOn(ctx context.Context, params *products.ExchangeRateLookupParams) (*products.ExchangeRate, error) {
+	currencyCode, err := products.NormalizeCurrencyCode(params.CurrencyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	date := time.Now()
+	if params.Date != nil {
+		date = *params.Date
+	}
+
+	rate, err := s.exchangeRateRepo.GetRate(ctx, currencyCode, date)
+	if err != nil {
+		return nil, err
+	}
+
+	return &products.ExchangeRate{
+		CurrencyCode: currencyCode,
+		RateDate:     date,
+		Rate:         rate,
+	}, nil
+}
+
+// ImportCSV imports dated rates from a CSV file. The whole file is rejected when any row is invalid.
+func (s *ExchangeRateService) ImportCSV(ctx context.Context, r io.Reader, importedBy int) (*products.ExchangeRateImportResult, error) {
+	rates, err := products.ParseExchangeRateCSV(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exchange rate file: %w", err)
+	}
+
+	if err := s.exchangeRateRepo.BulkUpsert(ctx, rates, importedBy); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	result := &products.ExchangeRateImportResult{Imported: len(rates), Currencies: []string{}}
+	for _, rate := range rates {
+		if !seen[rate.CurrencyCode] {
+			seen[rate.CurrencyCode] = true
+			result.Currencies = append(result.Currencies, rate.CurrencyCode)
+		}
+	}
+	sort.Strings(result.Currencies)
+
+	return result, nil
+}
+
+// foreignCurrencyCode normalizes a currency code that must not be the base currency
+func foreignCurrencyCode(code string) (string, error) {
+	currencyCode, err := products.NormalizeCurrencyCode(code)
+	if err != nil {
+		return "", err
+	}
+	if currencyCode == products.BaseCurrency {
+		return "", fmt.Errorf("%s is the base currency and has no exchange rate", products.BaseCurrency)
+	}
+	return currencyCode, nil
+}
+
+// documentExchangeRate returns the exchange rate a document in currencyCode dated date is booked at:
+// the override when given, otherwise the rate in effect on the date. Base currency documents use 1.
+func documentExchangeRate(ctx context.Context, repo interfaces.ExchangeRateRepository, currencyCode string, date time.Time, override *float64) (float64, error) {
+	if currencyCode == products.BaseCurrency {
+		return 1, nil
+	}
+	if override != nil {
+		return *override, nil
+	}
+	return repo.GetRate(ctx, currencyCode, date)
+}
@@ -0,0 +1,203 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// GeneralLedgerService handles the chart of accounts, journal entries and accounting periods
+type GeneralLedgerService struct {
+	accountRepo interfaces.GLAccountRepository
+	journalRepo interfaces.JournalRepository
+}
+
+// NewGeneralLedgerService creates a new general ledger service
+func NewGeneralLedgerService(accountRepo interfaces.GLAccountRepository, journalRepo interfaces.JournalRepository) *GeneralLedgerService {
+	return &GeneralLedgerService{
+		accountRepo: accountRepo,
+		journalRepo: journalRepo,
+	}
+}
+
+// CreateAccount adds an account to the chart of accounts
+func (s *GeneralLedgerService) CreateAccount(ctx context.Context, req *products.GLAccountCreateRequest) (*products.GLAccount, error) {
+	if !req.AccountType.IsValid() {
+		return nil, fmt.Errorf("invalid account type: %s", req.AccountType)
+	}
+
+	code := strings.TrimSpace(req.AccountCode)
+	exists, err := s.accountRepo.IsCodeExists(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("account code %s already exists", code)
+	}
+
+	account := &products.GLAccount{
+		AccountCode: code,
+		AccountName: strings.TrimSpace(req.AccountName),
+		AccountType: req.AccountType,
+		Description: req.Description,
+	}
+
+	return s.accountRepo.Create(ctx, account)
+}
+
+// GetAccount retrieves an account by ID
+func (s *GeneralLedgerService) GetAccount(ctx context.Context, id int) (*products.GLAccount, error) {
+	return s.accountRepo.GetByID(ctx, id)
+}
+
+// UpdateAccount updates an account. Accounts that automatic postings book to cannot be deactivated.
+func (s *GeneralLedgerService) UpdateAccount(ctx context.Context, id int, req *products.GLAccountUpdateRequest) (*products.GLAccount, error) {
+	account, err := s.accountRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.AccountName != nil {
+		account.AccountName = strings.TrimSpace(*req.AccountName)
+	}
+	if req.Description != nil {
+		account.Description = req.Description
+	}
+	if req.IsActive != nil {
+		if account.IsActive && !*req.IsActive {
+			used, err := s.accountRepo.IsPostingRuleAccount(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if used {
+				return nil, fmt.Errorf("account %s is used by a posting rule and cannot be deactivated", account.AccountCode)
+			}
+		}
+		account.IsActive = *req.IsActive
+	}
+
+	return s.accountRepo.Update(ctx, id, account)
+}
+
+// ListAccounts retrieves the chart of accounts with filtering and pagination
+func (s *GeneralLedgerService) ListAccounts(ctx context.Context, params *products.GLAccountFilterParams) (*common.PaginatedResponse, error) {
+	if params.AccountType != nil && !params.AccountType.IsValid() {
+		return nil, fmt.Errorf("invalid account type: %s", *params.AccountType)
+	}
+
+	accounts, err := s.accountRepo.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// GetPostingRules retrieves the account each automatic posting rule books to
+func (s *GeneralLedgerService) GetPostingRules(ctx context.Context) ([]products.GLPostingRule, error) {
+	return s.accountRepo.GetPostingRules(ctx)
+}
+
+// UpdatePostingRule points a posting rule at another active account
+func (s *GeneralLedgerService) UpdatePostingRule(ctx context.Context, rule products.PostingRule, req *products.GLPostingRuleUpdateRequest) ([]products.GLPostingRule, error) {
+	if !rule.IsValid() {
+		return nil, fmt.Errorf("invalid posting rule: %s", rule)
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if !account.IsActive {
+		return nil, fmt.Errorf("account %s is inactive", account.AccountCode)
+	}
+
+	if err := s.accountRepo.UpdatePostingRule(ctx, rule, account.AccountID); err != nil {
+		return nil, err
+	}
+
+	return s.accountRepo.GetPostingRules(ctx)
+}
+
+// PostJournal posts a manual journal entry
+func (s *GeneralLedgerService) PostJournal(ctx context.Context, req *products.JournalEntryCreateRequest, postedBy int) (*products.JournalEntry, error) {
+	entry := products.NewManualJournalEntry(req, postedBy)
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.journalRepo.Post(ctx, entry)
+}
+
+// GetJournal retrieves a journal entry with its lines
+func (s *GeneralLedgerService) GetJournal(ctx context.Context, id int) (*products.JournalEntry, error) {
+	return s.journalRepo.GetByID(ctx, id)
+}
+
+// ListJournals retrieves journal entries with filtering and pagination
+func (s *GeneralLedgerService) ListJournals(ctx context.Context, params *products.JournalEntryFilterParams) (*common.PaginatedResponse, error) {
+	if params.SourceType != nil && !params.SourceType.IsValid() {
+		return nil, fmt.Errorf("invalid source type: %s", *params.SourceType)
+	}
+	if params.JournalStatus != nil && !params.JournalStatus.IsValid() {
+		return nil, fmt.Errorf("invalid journal status: %s", *params.JournalStatus)
+	}
+
+	journals, err := s.journalRepo.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journal entries: %w", err)
+	}
+
+	return journals, nil
+}
+
+// ReverseJournal posts the entry cancelling a posted journal entry, dated today unless a date is given
+func (s *GeneralLedgerService) ReverseJournal(ctx context.Context, id int, req *products.JournalReverseRequest, postedBy int) (*products.JournalEntry, error) {
+	entryDate := time.Now()
+	if req.EntryDate != nil {
+		entryDate = *req.EntryDate
+	}
+
+	return s.journalRepo.Reverse(ctx, id, entryDate, postedBy)
+}
+
+// GetTrialBalance retrieves the balance of every account for the entry dates in range
+func (s *GeneralLedgerService) GetTrialBalance(ctx context.Context, params *products.TrialBalanceParams) (*products.TrialBalance, error) {
+	if params.DateFrom != nil && params.DateTo != nil && params.DateTo.Before(*params.DateFrom) {
+		return nil, fmt.Errorf("date_to must not be before date_from")
+	}
+
+	return s.journalRepo.GetTrialBalance(ctx, params)
+}
+
+// ListPeriods retrieves the accounting periods of a year, defaulting to the current year
+func (s *GeneralLedgerService) ListPeriods(ctx context.Context, year int) ([]products.AccountingPeriod, error) {
+	if year == 0 {
+		year = time.Now().Year()
+	}
+	if year < 2000 || year > 2100 {
+		return nil, fmt.Errorf("invalid year: %d", year)
+	}
+
+	return s.journalRepo.ListPeriods(ctx, year)
+}
+
+// ClosePeriod locks a month that has ended so nothing more can be posted into it
+func (s *GeneralLedgerService) ClosePeriod(ctx context.Context, req *products.AccountingPeriodRequest, closedBy int) error {
+	start := req.Start()
+	if !time.Now().After(products.PeriodEnd(start).AddDate(0, 0, 1)) {
+		return fmt.Errorf("accounting period %s has not ended yet", start.Format("2006-01"))
+	}
+
+	return s.journalRepo.SetPeriodStatus(ctx, start, products.AccountingPeriodStatusClosed, closedBy)
+}
+
+// ReopenPeriod unlocks a closed month so corrections can be posted into it
+func (s *GeneralLedgerService) ReopenPeriod(ctx context.Context, req *products.AccountingPeriodRequest, reopenedBy int) error {
+	return s.journalRepo.SetPeriodStatus(ctx, req.Start(), products.AccountingPeriodStatusOpen, reopenedBy)
+}
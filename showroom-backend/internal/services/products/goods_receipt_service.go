@@ -13,30 +13,56 @@ import (
 type GoodsReceiptService struct {
 	goodsReceiptRepo       interfaces.GoodsReceiptRepository
 	goodsReceiptDetailRepo interfaces.GoodsReceiptDetailRepository
+	poRepo                 interfaces.PurchaseOrderPartsRepository
 	poDetailRepo           interfaces.PurchaseOrderDetailRepository
 	stockMovementRepo      interfaces.StockMovementRepository
 	productRepo            interfaces.ProductSparePartRepository
+	landedCostRepo         interfaces.LandedCostRepository
+	journalRepo            interfaces.JournalRepository
+	exchangeRateRepo       interfaces.ExchangeRateRepository
 }
 
 // NewGoodsReceiptService creates a new goods receipt service
 func NewGoodsReceiptService(
 	goodsReceiptRepo interfaces.GoodsReceiptRepository,
 	goodsReceiptDetailRepo interfaces.GoodsReceiptDetailRepository,
+	poRepo interfaces.PurchaseOrderPartsRepository,
 	poDetailRepo interfaces.PurchaseOrderDetailRepository,
 	stockMovementRepo interfaces.StockMovementRepository,
 	productRepo interfaces.ProductSparePartRepository,
+	landedCostRepo interfaces.LandedCostRepository,
+	journalRepo interfaces.JournalRepository,
+	exchangeRateRepo interfaces.ExchangeRateRepository,
 ) *GoodsReceiptService {
 	return &GoodsReceiptService{
 		goodsReceiptRepo:       goodsReceiptRepo,
 		goodsReceiptDetailRepo: goodsReceiptDetailRepo,
+		poRepo:                 poRepo,
 		poDetailRepo:           poDetailRepo,
 		stockMovementRepo:      stockMovementRepo,
 		productRepo:            productRepo,
+		landedCostRepo:         landedCostRepo,
+		journalRepo:            journalRepo,
+		exchangeRateRepo:       exchangeRateRepo,
 	}
 }
 
 // CreateGoodsReceipt creates a new goods receipt
 func (s *GoodsReceiptService) CreateGoodsReceipt(ctx context.Context, req *products.GoodsReceiptCreateRequest, receivedBy int) (*products.GoodsReceipt, error) {
+	// Goods are received in the PO currency and valued at the rate of the receipt date
+	po, err := s.poRepo.GetByID(ctx, req.POID)
+	if err != nil {
+		return nil, fmt.Errorf("purchase order not found: %w", err)
+	}
+	if err := checkReceivable(po); err != nil {
+		return nil, err
+	}
+
+	exchangeRate, err := documentExchangeRate(ctx, s.exchangeRateRepo, po.CurrencyCode, req.ReceiptDate, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate receipt number
 	receiptNumber, err := s.goodsReceiptRepo.GenerateNumber(ctx)
 	if err != nil {
@@ -53,6 +79,8 @@ func (s *GoodsReceiptService) CreateGoodsReceipt(ctx context.Context, req *produ
 		SupplierInvoiceNumber: req.SupplierInvoiceNumber,
 		ReceiptNotes:          req.ReceiptNotes,
 		ReceiptDocumentsJSON:  req.ReceiptDocumentsJSON,
+		CurrencyCode:          po.CurrencyCode,
+		ExchangeRate:          exchangeRate,
 	}
 
 	// Create the receipt
@@ -64,6 +92,15 @@ func (s *GoodsReceiptService) CreateGoodsReceipt(ctx context.Context, req *produ
 	return createdReceipt, nil
 }
 
+// checkReceivable rejects receipts against blanket and contract orders. Their goods are received
+// against release orders, which draw down the agreed balance.
+func checkReceivable(po *products.PurchaseOrderParts) error {
+	if po.POType.IsBlanketType() {
+		return fmt.Errorf("goods cannot be received against a %s purchase order; receive against its release orders", po.POType)
+	}
+	return nil
+}
+
 // GetGoodsReceipt retrieves a goods receipt by ID
 func (s *GoodsReceiptService) GetGoodsReceipt(ctx context.Context, id int) (*products.GoodsReceipt, error) {
 	receipt, err := s.goodsReceiptRepo.GetByID(ctx, id)
@@ -95,6 +132,10 @@ func (s *GoodsReceiptService) UpdateGoodsReceipt(ctx context.Context, id int, re
 	// Update fields if provided
 	if req.ReceiptDate != nil {
 		existing.ReceiptDate = *req.ReceiptDate
+		existing.ExchangeRate, err = documentExchangeRate(ctx, s.exchangeRateRepo, existing.CurrencyCode, existing.ReceiptDate, nil)
+		if err != nil {
+			return nil, err
+		}
 	}
 	if req.SupplierDeliveryNote != nil {
 		existing.SupplierDeliveryNote = req.SupplierDeliveryNote
@@ -169,6 +210,14 @@ func (s *GoodsReceiptService) AddReceiptDetail(ctx context.Context, receiptID in
 		return nil, fmt.Errorf("PO detail does not belong to the same PO as the receipt")
 	}
 
+	po, err := s.poRepo.GetByID(ctx, receipt.POID)
+	if err != nil {
+		return nil, fmt.Errorf("purchase order not found: %w", err)
+	}
+	if err := checkReceivable(po); err != nil {
+		return nil, err
+	}
+
 	// Validate product matches
 	if poDetail.ProductID != req.ProductID {
 		return nil, fmt.Errorf("product ID does not match PO detail")
@@ -229,6 +278,20 @@ func (s *GoodsReceiptService) GetReceiptDetails(ctx context.Context, receiptID i
 
 // ProcessGoodsReceipt processes a goods receipt and updates stock
 func (s *GoodsReceiptService) ProcessGoodsReceipt(ctx context.Context, receiptID int, processedBy int) error {
+	receipt, err := s.goodsReceiptRepo.GetByID(ctx, receiptID)
+	if err != nil {
+		return fmt.Errorf("failed to get receipt: %w", err)
+	}
+
+	if receipt.ReceiptStatus != products.ReceiptStatusPartial {
+		return fmt.Errorf("goods receipt %s has already been processed", receipt.ReceiptNumber)
+	}
+
+	// Receipts dated in a closed accounting period cannot be booked into inventory
+	if err := s.journalRepo.CheckPeriodOpen(ctx, receipt.ReceiptDate); err != nil {
+		return err
+	}
+
 	// Get receipt details
 	details, err := s.goodsReceiptDetailRepo.GetByReceiptID(ctx, receiptID)
 	if err != nil {
@@ -239,24 +302,31 @@ func (s *GoodsReceiptService) ProcessGoodsReceipt(ctx context.Context, receiptID
 		return fmt.Errorf("cannot process receipt without details")
 	}
 
+	// Landed cost vouchers posted before processing are folded into the movement unit cost,
+	// which is kept in the base currency
+	landedCosts, err := s.landedCostRepo.GetUnitLandedCosts(ctx, receiptID)
+	if err != nil {
+		return fmt.Errorf("failed to get landed costs: %w", err)
+	}
+
 	var totalValue float64
+	var movements []products.StockMovement
 	hasDiscrepancy := false
+	reason := "Goods receipt"
 
-	// Process each detail and create stock movements
 	for _, detail := range details {
-		// Only create stock movement for accepted quantities
+		// Only accepted quantities enter stock
 		if detail.QuantityAccepted > 0 {
-			err = s.stockMovementRepo.CreateMovementForReceipt(
-				ctx,
-				detail.ProductID,
-				detail.QuantityAccepted,
-				detail.UnitCost,
-				receiptID,
-				processedBy,
-			)
-			if err != nil {
-				return fmt.Errorf("failed to create stock movement for product %d: %w", detail.ProductID, err)
-			}
+			movements = append(movements, products.StockMovement{
+				ProductID:      detail.ProductID,
+				MovementType:   products.MovementTypeIn,
+				ReferenceType:  products.ReferenceTypePurchase,
+				ReferenceID:    receiptID,
+				QuantityMoved:  detail.QuantityAccepted,
+				UnitCost:       receipt.BaseUnitCost(detail.UnitCost) + landedCosts[detail.ReceiptDetailID],
+				ProcessedBy:    processedBy,
+				MovementReason: &reason,
+			})
 		}
 
 		// Check for discrepancies
@@ -267,29 +337,18 @@ func (s *GoodsReceiptService) ProcessGoodsReceipt(ctx context.Context, receiptID
 		totalValue += float64(detail.QuantityAccepted) * detail.UnitCost
 	}
 
-	// Update receipt status and total value
-	var status products.ReceiptStatus
 	if hasDiscrepancy {
-		status = products.ReceiptStatusWithDiscrepancy
+		receipt.ReceiptStatus = products.ReceiptStatusWithDiscrepancy
 	} else {
-		status = products.ReceiptStatusComplete
-	}
-
-	err = s.goodsReceiptRepo.UpdateStatus(ctx, receiptID, status)
-	if err != nil {
-		return fmt.Errorf("failed to update receipt status: %w", err)
-	}
-
-	// Update total received value
-	receipt, err := s.goodsReceiptRepo.GetByID(ctx, receiptID)
-	if err != nil {
-		return fmt.Errorf("failed to get receipt: %w", err)
+		receipt.ReceiptStatus = products.ReceiptStatusComplete
 	}
-
 	receipt.TotalReceivedValue = totalValue
-	_, err = s.goodsReceiptRepo.Update(ctx, receiptID, receipt)
-	if err != nil {
-		return fmt.Errorf("failed to update receipt total value: %w", err)
+
+	// The stock movements, status change and the journal booking the accepted goods into inventory
+	// against goods received not invoiced are committed together
+	entry := products.GoodsReceiptJournal(receipt, details, processedBy)
+	if err := s.goodsReceiptRepo.Process(ctx, receipt, movements, entry); err != nil {
+		return fmt.Errorf("failed to process receipt: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,253 @@
+package products
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// LandedCostService handles business logic for landed cost vouchers
+type LandedCostService struct {
+	landedCostRepo         interfaces.LandedCostRepository
+	goodsReceiptRepo       interfaces.GoodsReceiptRepository
+	goodsReceiptDetailRepo interfaces.GoodsReceiptDetailRepository
+	stockMovementRepo      interfaces.StockMovementRepository
+	productRepo            interfaces.ProductSparePartRepository
+}
+
+// NewLandedCostService creates a new landed cost service
+func NewLandedCostService(
+	landedCostRepo interfaces.LandedCostRepository,
+	goodsReceiptRepo interfaces.GoodsReceiptRepository,
+	goodsReceiptDetailRepo interfaces.GoodsReceiptDetailRepository,
+	stockMovementRepo interfaces.StockMovementRepository,
+	productRepo interfaces.ProductSparePartRepository,
+) *LandedCostService {
+	return &LandedCostService{
+		landedCostRepo:         landedCostRepo,
+		goodsReceiptRepo:       goodsReceiptRepo,
+		goodsReceiptDetailRepo: goodsReceiptDetailRepo,
+		stockMovementRepo:      stockMovementRepo,
+		productRepo:            productRepo,
+	}
+}
+
+// CreateVoucher creates a draft landed cost voucher for one or more goods receipts
+func (s *LandedCostService) CreateVoucher(ctx context.Context, req *products.LandedCostVoucherCreateRequest, createdBy int) (*products.LandedCostVoucher, error) {
+	if !req.AllocationMethod.IsValid() {
+		return nil, fmt.Errorf("invalid allocation method: %s", req.AllocationMethod)
+	}
+
+	seen := make(map[int]bool, len(req.ReceiptIDs))
+	var receiptIDs []int
+	for _, receiptID := range req.ReceiptIDs {
+		if seen[receiptID] {
+			continue
+		}
+		seen[receiptID] = true
+
+		if _, err := s.goodsReceiptRepo.GetByID(ctx, receiptID); err != nil {
+			return nil, fmt.Errorf("goods receipt %d not found: %w", receiptID, err)
+		}
+		receiptIDs = append(receiptIDs, receiptID)
+	}
+
+	voucherNumber, err := s.landedCostRepo.GenerateNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate voucher number: %w", err)
+	}
+
+	voucher := &products.LandedCostVoucher{
+		VoucherNumber:    voucherNumber,
+		SupplierID:       req.SupplierID,
+		ReferenceNumber:  req.ReferenceNumber,
+		AllocationMethod: req.AllocationMethod,
+		FreightAmount:    req.FreightAmount,
+		DutyAmount:       req.DutyAmount,
+		InsuranceAmount:  req.InsuranceAmount,
+		HandlingAmount:   req.HandlingAmount,
+		VoucherStatus:    products.LandedCostStatusDraft,
+		Notes:            req.Notes,
+		CreatedBy:        createdBy,
+		ReceiptIDs:       receiptIDs,
+	}
+	if req.VoucherDate != nil {
+		voucher.VoucherDate = *req.VoucherDate
+	}
+
+	voucher.CalculateTotalAmount()
+	if voucher.TotalAmount <= 0 {
+		return nil, fmt.Errorf("landed cost voucher must have a positive total amount")
+	}
+
+	createdVoucher, err := s.landedCostRepo.Create(ctx, voucher)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create landed cost voucher: %w", err)
+	}
+
+	return createdVoucher, nil
+}
+
+// GetVoucher retrieves a landed cost voucher by ID
+func (s *LandedCostService) GetVoucher(ctx context.Context, id int) (*products.LandedCostVoucher, error) {
+	voucher, err := s.landedCostRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get landed cost voucher: %w", err)
+	}
+
+	return voucher, nil
+}
+
+// ListVouchers retrieves landed cost vouchers with filtering and pagination
+func (s *LandedCostService) ListVouchers(ctx context.Context, params *products.LandedCostVoucherFilterParams) (*common.PaginatedResponse, error) {
+	result, err := s.landedCostRepo.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list landed cost vouchers: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetAllocations returns the stored allocation of a posted voucher, or a preview for a draft voucher
+func (s *LandedCostService) GetAllocations(ctx context.Context, id int) ([]products.LandedCostAllocation, error) {
+	voucher, err := s.landedCostRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get landed cost voucher: %w", err)
+	}
+
+	if voucher.VoucherStatus == products.LandedCostStatusPosted {
+		allocations, err := s.landedCostRepo.GetAllocations(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get landed cost allocations: %w", err)
+		}
+		return allocations, nil
+	}
+
+	return s.allocate(ctx, voucher)
+}
+
+// PostVoucher allocates a draft voucher over its receipt lines and adds the cost to inventory.
+// The voucher is journaled to inventory against accrued landed costs when it is posted. Receipts that
+// were already processed get a valuation movement per line; receipts processed later pick the landed
+// cost up in their goods receipt movements.
+func (s *LandedCostService) PostVoucher(ctx context.Context, id int, postedBy int) (*products.LandedCostVoucher, error) {
+	voucher, err := s.landedCostRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get landed cost voucher: %w", err)
+	}
+
+	if !voucher.CanPost() {
+		return nil, fmt.Errorf("landed cost voucher cannot be posted in current status: %s", voucher.VoucherStatus)
+	}
+
+	allocations, err := s.allocate(ctx, voucher)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.landedCostRepo.Post(ctx, id, allocations, postedBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to post landed cost voucher: %w", err)
+	}
+
+	processed := make(map[int]bool, len(voucher.ReceiptIDs))
+	for _, receiptID := range voucher.ReceiptIDs {
+		movements, err := s.stockMovementRepo.GetByReferenceID(ctx, products.ReferenceTypePurchase, receiptID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get receipt movements: %w", err)
+		}
+		processed[receiptID] = len(movements) > 0
+	}
+
+	for _, allocation := range allocations {
+		if !processed[allocation.ReceiptID] || allocation.AllocatedAmount <= 0 {
+			continue
+		}
+
+		err = s.stockMovementRepo.CreateMovementForLandedCost(
+			ctx,
+			allocation.ProductID,
+			allocation.Quantity,
+			allocation.UnitLandedCost,
+			id,
+			postedBy,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create landed cost movement for product %d: %w", allocation.ProductID, err)
+		}
+	}
+
+	return s.landedCostRepo.GetByID(ctx, id)
+}
+
+// CancelVoucher cancels a draft landed cost voucher
+func (s *LandedCostService) CancelVoucher(ctx context.Context, id int) error {
+	voucher, err := s.landedCostRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get landed cost voucher: %w", err)
+	}
+
+	if !voucher.CanCancel() {
+		return fmt.Errorf("landed cost voucher cannot be cancelled in current status: %s", voucher.VoucherStatus)
+	}
+
+	err = s.landedCostRepo.UpdateStatus(ctx, id, products.LandedCostStatusCancelled)
+	if err != nil {
+		return fmt.Errorf("failed to cancel landed cost voucher: %w", err)
+	}
+
+	return nil
+}
+
+// allocate builds allocation lines from the accepted quantities of the voucher's receipts.
+// Unit costs are converted to the base currency so value allocation compares like with like.
+func (s *LandedCostService) allocate(ctx context.Context, voucher *products.LandedCostVoucher) ([]products.LandedCostAllocation, error) {
+	var lines []products.LandedCostAllocation
+	for _, receiptID := range voucher.ReceiptIDs {
+		receipt, err := s.goodsReceiptRepo.GetByID(ctx, receiptID)
+		if err != nil {
+			return nil, fmt.Errorf("goods receipt %d not found: %w", receiptID, err)
+		}
+
+		details, err := s.goodsReceiptDetailRepo.GetByReceiptID(ctx, receiptID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get receipt details: %w", err)
+		}
+
+		for _, detail := range details {
+			if detail.QuantityAccepted <= 0 {
+				continue
+			}
+
+			line := products.LandedCostAllocation{
+				ReceiptID:       receiptID,
+				ReceiptDetailID: detail.ReceiptDetailID,
+				ProductID:       detail.ProductID,
+				Quantity:        detail.QuantityAccepted,
+				UnitCost:        receipt.BaseUnitCost(detail.UnitCost),
+			}
+
+			if voucher.AllocationMethod == products.AllocationMethodWeight {
+				product, err := s.productRepo.GetByID(ctx, detail.ProductID)
+				if err != nil {
+					return nil, fmt.Errorf("product %d not found: %w", detail.ProductID, err)
+				}
+				if product.Weight == nil || *product.Weight <= 0 {
+					return nil, fmt.Errorf("product %d has no weight; cannot allocate by weight", detail.ProductID)
+				}
+				line.UnitWeight = *product.Weight
+			}
+
+			lines = append(lines, line)
+		}
+	}
+
+	if err := voucher.Allocate(lines); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
@@ -0,0 +1,244 @@
+package products
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
+)
+
+// PaymentRunService handles business logic for batch supplier payment runs
+type PaymentRunService struct {
+	runRepo          interfaces.PaymentRunRepository
+	supplierRepo     interfaces.SupplierRepository
+	currency         string
+	fixedWidthLayout []utils.FixedWidthField
+}
+
+// NewPaymentRunService creates a new payment run service writing bank files in the given
+// currency, with fixedWidthLayout describing the fixed-width bank file format
+func NewPaymentRunService(
+	runRepo interfaces.PaymentRunRepository,
+	supplierRepo interfaces.SupplierRepository,
+	currency string,
+	fixedWidthLayout []utils.FixedWidthField,
+) *PaymentRunService {
+	return &PaymentRunService{
+		runRepo:          runRepo,
+		supplierRepo:     supplierRepo,
+		currency:         currency,
+		fixedWidthLayout: fixedWidthLayout,
+	}
+}
+
+// CreateRun selects the invoices due by the requested date into a draft payment run for review.
+// Only invoices in the bank file currency are paid by a run.
+func (s *PaymentRunService) CreateRun(ctx context.Context, req *products.PaymentRunCreateRequest, createdBy int) (*products.PaymentRun, error) {
+	priority := req.Priority
+	if priority == "" {
+		priority = products.PaymentRunPriorityDueDate
+	}
+	if !priority.IsValid() {
+		return nil, fmt.Errorf("invalid payment run priority: %s", priority)
+	}
+
+	if req.SupplierID != nil {
+		if _, err := s.supplierRepo.GetByID(ctx, *req.SupplierID); err != nil {
+			return nil, fmt.Errorf("supplier not found: %w", err)
+		}
+	}
+
+	paymentDate := time.Now()
+	if req.PaymentDate != nil {
+		paymentDate = *req.PaymentDate
+	}
+	dueBefore := paymentDate
+	if req.DueBefore != nil {
+		dueBefore = *req.DueBefore
+	}
+
+	candidates, err := s.runRepo.GetCandidates(ctx, dueBefore, s.currency, req.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := products.BuildPaymentRunLines(candidates, priority, paymentDate, req.TakeDiscounts, req.MaxAmount)
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("no open %s supplier invoices due by %s to pay", s.currency, dueBefore.Format("2006-01-02"))
+	}
+
+	runNumber, err := s.runRepo.GenerateNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &products.PaymentRun{
+		RunNumber:   runNumber,
+		PaymentDate: paymentDate,
+		DueBefore:   dueBefore,
+		SupplierID:  req.SupplierID,
+		Priority:    priority,
+		MaxAmount:   req.MaxAmount,
+		RunStatus:   products.PaymentRunStatusDraft,
+		Notes:       req.Notes,
+		CreatedBy:   createdBy,
+	}
+
+	return s.runRepo.Create(ctx, run, lines)
+}
+
+// GetRun retrieves a payment run with its lines
+func (s *PaymentRunService) GetRun(ctx context.Context, id int) (*products.PaymentRun, error) {
+	return s.runRepo.GetByID(ctx, id)
+}
+
+// ListRuns retrieves payment runs with filtering and pagination
+func (s *PaymentRunService) ListRuns(ctx context.Context, params *products.PaymentRunFilterParams) (*common.PaginatedResponse, error) {
+	if params.RunStatus != nil && !params.RunStatus.IsValid() {
+		return nil, fmt.Errorf("invalid payment run status: %s", *params.RunStatus)
+	}
+
+	runs, err := s.runRepo.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// RemoveLine removes an invoice from a draft payment run during review
+func (s *PaymentRunService) RemoveLine(ctx context.Context, runID, lineID int) (*products.PaymentRun, error) {
+	run, err := s.runRepo.GetByID(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !run.CanEdit() {
+		return nil, fmt.Errorf("payment run cannot be changed in %s status", run.RunStatus)
+	}
+
+	if err := s.runRepo.RemoveLine(ctx, runID, lineID); err != nil {
+		return nil, err
+	}
+
+	return s.runRepo.GetByID(ctx, runID)
+}
+
+// ApproveRun approves a reviewed draft run so its bank file can be generated
+func (s *PaymentRunService) ApproveRun(ctx context.Context, id int, approvedBy int) (*products.PaymentRun, error) {
+	run, err := s.runRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !run.CanApprove() {
+		return nil, fmt.Errorf("payment run cannot be approved in %s status", run.RunStatus)
+	}
+
+	if len(run.ActiveLines()) == 0 {
+		return nil, fmt.Errorf("payment run has no lines to pay")
+	}
+
+	if missing := run.MissingBankAccounts(); len(missing) > 0 {
+		return nil, fmt.Errorf("suppliers without a bank account: %s", strings.Join(missing, ", "))
+	}
+
+	if err := s.runRepo.Approve(ctx, id, approvedBy); err != nil {
+		return nil, err
+	}
+
+	return s.runRepo.GetByID(ctx, id)
+}
+
+// CancelRun cancels a payment run that has not been executed
+func (s *PaymentRunService) CancelRun(ctx context.Context, id int) (*products.PaymentRun, error) {
+	run, err := s.runRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !run.CanCancel() {
+		return nil, fmt.Errorf("payment run cannot be cancelled in %s status", run.RunStatus)
+	}
+
+	if err := s.runRepo.Cancel(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.runRepo.GetByID(ctx, id)
+}
+
+// GenerateBankFile renders the transfers of an approved or executed run as a CSV or
+// fixed-width bulk transfer file and returns the file name with its contents
+func (s *PaymentRunService) GenerateBankFile(ctx context.Context, id int, format string) (string, []byte, error) {
+	run, err := s.runRepo.GetByID(ctx, id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if !run.CanExport() {
+		return "", nil, fmt.Errorf("bank file cannot be generated for a payment run in %s status", run.RunStatus)
+	}
+
+	var buf bytes.Buffer
+	var filename string
+	switch format {
+	case "", products.BankFileFormatCSV:
+		header, rows := run.BankFileTable(s.currency)
+		err = utils.WriteCSV(&buf, header, rows)
+		filename = run.RunNumber + ".csv"
+	case products.BankFileFormatFixedWidth:
+		err = utils.WriteFixedWidth(&buf, s.fixedWidthLayout, run.BankFileRecords(s.currency))
+		filename = run.RunNumber + ".txt"
+	default:
+		return "", nil, fmt.Errorf("invalid bank file format: %s", format)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate bank file: %w", err)
+	}
+
+	return filename, buf.Bytes(), nil
+}
+
+// ExecuteRun posts the payments of an approved run once the bank has processed the transfers
+func (s *PaymentRunService) ExecuteRun(ctx context.Context, id int, executedBy int) (*products.PaymentRun, error) {
+	run, err := s.runRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !run.CanExecute() {
+		return nil, fmt.Errorf("payment run cannot be executed in %s status", run.RunStatus)
+	}
+
+	if err := s.runRepo.Execute(ctx, id, executedBy); err != nil {
+		return nil, err
+	}
+
+	return s.runRepo.GetByID(ctx, id)
+}
+
+// FailLine records a transfer the bank rejected. On an executed run its payment is voided
+// and the invoice reopened; on an approved run the line is left out of the execution.
+func (s *PaymentRunService) FailLine(ctx context.Context, runID, lineID int, req *products.PaymentRunFailLineRequest) (*products.PaymentRun, error) {
+	run, err := s.runRepo.GetByID(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !run.CanFailLine() {
+		return nil, fmt.Errorf("payment run lines cannot fail in %s status", run.RunStatus)
+	}
+
+	if err := s.runRepo.FailLine(ctx, runID, lineID, req.Reason); err != nil {
+		return nil, err
+	}
+
+	return s.runRepo.GetByID(ctx, runID)
+}
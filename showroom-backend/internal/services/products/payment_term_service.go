@@ -0,0 +1,106 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// PaymentTermService handles business logic for supplier payment terms
+type PaymentTermService struct {
+	paymentTermRepo interfaces.PaymentTermRepository
+}
+
+// NewPaymentTermService creates a new payment term service
+func NewPaymentTermService(paymentTermRepo interfaces.PaymentTermRepository) *PaymentTermService {
+	return &PaymentTermService{
+		paymentTermRepo: paymentTermRepo,
+	}
+}
+
+// CreatePaymentTerm creates new payment terms
+func (s *PaymentTermService) CreatePaymentTerm(ctx context.Context, req *products.PaymentTermCreateRequest) (*products.PaymentTerm, error) {
+	term := &products.PaymentTerm{
+		TermCode:        strings.ToUpper(strings.TrimSpace(req.TermCode)),
+		TermName:        strings.TrimSpace(req.TermName),
+		NetDays:         req.NetDays,
+		DiscountPercent: req.DiscountPercent,
+		DiscountDays:    req.DiscountDays,
+	}
+
+	if err := term.Validate(); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.paymentTermRepo.IsCodeExists(ctx, term.TermCode, 0)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("payment term code %s already exists", term.TermCode)
+	}
+
+	createdTerm, err := s.paymentTermRepo.Create(ctx, term)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment term: %w", err)
+	}
+
+	return createdTerm, nil
+}
+
+// GetPaymentTerm retrieves payment terms by ID
+func (s *PaymentTermService) GetPaymentTerm(ctx context.Context, id int) (*products.PaymentTerm, error) {
+	return s.paymentTermRepo.GetByID(ctx, id)
+}
+
+// ListPaymentTerms retrieves payment terms with filtering and pagination
+func (s *PaymentTermService) ListPaymentTerms(ctx context.Context, params *products.PaymentTermFilterParams) (*common.PaginatedResponse, error) {
+	terms, err := s.paymentTermRepo.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment terms: %w", err)
+	}
+
+	return terms, nil
+}
+
+// UpdatePaymentTerm updates payment terms. Existing invoices keep the terms they were created with.
+func (s *PaymentTermService) UpdatePaymentTerm(ctx context.Context, id int, req *products.PaymentTermUpdateRequest) (*products.PaymentTerm, error) {
+	term, err := s.paymentTermRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.TermName != nil {
+		term.TermName = strings.TrimSpace(*req.TermName)
+	}
+	if req.NetDays != nil {
+		term.NetDays = *req.NetDays
+	}
+	if req.DiscountPercent != nil {
+		term.DiscountPercent = *req.DiscountPercent
+	}
+	if req.DiscountDays != nil {
+		term.DiscountDays = *req.DiscountDays
+	}
+	if req.IsActive != nil {
+		term.IsActive = *req.IsActive
+	}
+
+	if err := term.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.paymentTermRepo.Update(ctx, id, term)
+}
+
+// DeletePaymentTerm deletes payment terms that are not in use
+func (s *PaymentTermService) DeletePaymentTerm(ctx context.Context, id int) error {
+	if _, err := s.paymentTermRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return s.paymentTermRepo.Delete(ctx, id)
+}
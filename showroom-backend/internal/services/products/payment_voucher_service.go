@@ -0,0 +1,189 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// PaymentVoucherService handles business logic for supplier payment vouchers
+type PaymentVoucherService struct {
+	voucherRepo      interfaces.PaymentVoucherRepository
+	invoiceRepo      interfaces.SupplierInvoiceRepository
+	supplierRepo     interfaces.SupplierRepository
+	exchangeRateRepo interfaces.ExchangeRateRepository
+}
+
+// NewPaymentVoucherService creates a new payment voucher service
+func NewPaymentVoucherService(
+	voucherRepo interfaces.PaymentVoucherRepository,
+	invoiceRepo interfaces.SupplierInvoiceRepository,
+	supplierRepo interfaces.SupplierRepository,
+	exchangeRateRepo interfaces.ExchangeRateRepository,
+) *PaymentVoucherService {
+	return &PaymentVoucherService{
+		voucherRepo:      voucherRepo,
+		invoiceRepo:      invoiceRepo,
+		supplierRepo:     supplierRepo,
+		exchangeRateRepo: exchangeRateRepo,
+	}
+}
+
+// CreateVoucher records a payment to a supplier and allocates it across the supplier's invoices
+func (s *PaymentVoucherService) CreateVoucher(ctx context.Context, req *products.PaymentVoucherCreateRequest, processedBy int) (*products.PaymentVoucher, error) {
+	if !req.PaymentMethod.IsValid() {
+		return nil, fmt.Errorf("invalid payment method: %s", req.PaymentMethod)
+	}
+
+	if _, err := s.supplierRepo.GetByID(ctx, req.SupplierID); err != nil {
+		return nil, fmt.Errorf("supplier not found: %w", err)
+	}
+
+	paymentDate := time.Now()
+	if req.PaymentDate != nil {
+		paymentDate = *req.PaymentDate
+	}
+
+	// The payment rate against the invoice rates gives the realized exchange gain or loss
+	currencyCode, err := products.NormalizeCurrencyCode(req.CurrencyCode)
+	if err != nil {
+		return nil, err
+	}
+	exchangeRate, err := documentExchangeRate(ctx, s.exchangeRateRepo, currencyCode, paymentDate, req.ExchangeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	voucherNumber, err := s.voucherRepo.GenerateNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	voucher := &products.PaymentVoucher{
+		VoucherNumber:    voucherNumber,
+		SupplierID:       req.SupplierID,
+		PaymentDate:      paymentDate,
+		PaymentMethod:    req.PaymentMethod,
+		PaymentReference: req.PaymentReference,
+		CurrencyCode:     currencyCode,
+		ExchangeRate:     exchangeRate,
+		Amount:           req.Amount,
+		VoucherStatus:    products.PaymentVoucherStatusPosted,
+		Notes:            req.Notes,
+		ProcessedBy:      processedBy,
+	}
+
+	if err := s.validateAllocations(ctx, voucher, req.Allocations); err != nil {
+		return nil, err
+	}
+
+	return s.voucherRepo.Create(ctx, voucher, req.Allocations)
+}
+
+// GetVoucher retrieves a payment voucher with its allocations
+func (s *PaymentVoucherService) GetVoucher(ctx context.Context, id int) (*products.PaymentVoucher, error) {
+	return s.voucherRepo.GetByID(ctx, id)
+}
+
+// ListVouchers retrieves payment vouchers with filtering and pagination
+func (s *PaymentVoucherService) ListVouchers(ctx context.Context, params *products.PaymentVoucherFilterParams) (*common.PaginatedResponse, error) {
+	if params.VoucherStatus != nil && !params.VoucherStatus.IsValid() {
+		return nil, fmt.Errorf("invalid voucher status: %s", *params.VoucherStatus)
+	}
+
+	vouchers, err := s.voucherRepo.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment vouchers: %w", err)
+	}
+
+	return vouchers, nil
+}
+
+// UpdateVoucher updates the date, method, reference and notes of a posted payment voucher
+func (s *PaymentVoucherService) UpdateVoucher(ctx context.Context, id int, req *products.PaymentVoucherUpdateRequest) (*products.PaymentVoucher, error) {
+	voucher, err := s.voucherRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if voucher.IsVoid() {
+		return nil, fmt.Errorf("cannot update a void payment voucher")
+	}
+
+	if req.PaymentDate != nil {
+		voucher.PaymentDate = *req.PaymentDate
+	}
+	if req.PaymentMethod != nil {
+		if !req.PaymentMethod.IsValid() {
+			return nil, fmt.Errorf("invalid payment method: %s", *req.PaymentMethod)
+		}
+		voucher.PaymentMethod = *req.PaymentMethod
+	}
+	if req.PaymentReference != nil {
+		voucher.PaymentReference = req.PaymentReference
+	}
+	if req.Notes != nil {
+		voucher.Notes = req.Notes
+	}
+
+	return s.voucherRepo.Update(ctx, id, voucher)
+}
+
+// AllocateVoucher allocates the unallocated amount of a payment voucher to supplier invoices
+func (s *PaymentVoucherService) AllocateVoucher(ctx context.Context, id int, req *products.PaymentVoucherAllocateRequest) (*products.PaymentVoucher, error) {
+	voucher, err := s.voucherRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateAllocations(ctx, voucher, req.Allocations); err != nil {
+		return nil, err
+	}
+
+	return s.voucherRepo.Allocate(ctx, id, req.Allocations)
+}
+
+// VoidVoucher voids a payment voucher and reopens the invoices it settled
+func (s *PaymentVoucherService) VoidVoucher(ctx context.Context, id int) (*products.PaymentVoucher, error) {
+	voucher, err := s.voucherRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if voucher.IsVoid() {
+		return nil, fmt.Errorf("payment voucher is already void")
+	}
+
+	if err := s.voucherRepo.Void(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.voucherRepo.GetByID(ctx, id)
+}
+
+// validateAllocations checks allocations against the current invoice balances before writing.
+// The repository validates again under lock so concurrent payments cannot over-allocate.
+func (s *PaymentVoucherService) validateAllocations(ctx context.Context, voucher *products.PaymentVoucher, allocations []products.PaymentAllocationRequest) error {
+	if len(allocations) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(allocations))
+	for _, allocation := range allocations {
+		ids = append(ids, allocation.InvoiceID)
+	}
+
+	invoices, err := s.invoiceRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	// Work on a copy so the repository applies discounts to the invoice balances it locks
+	preview := append([]products.PaymentAllocationRequest(nil), allocations...)
+	voucher.ApplyEarlyPaymentDiscounts(preview, invoices)
+	return voucher.ValidateAllocations(preview, invoices)
+}
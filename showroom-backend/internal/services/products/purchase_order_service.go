@@ -12,11 +12,15 @@ import (
 
 // PurchaseOrderService handles business logic for purchase orders
 type PurchaseOrderService struct {
-	poRepo         interfaces.PurchaseOrderPartsRepository
-	poDetailRepo   interfaces.PurchaseOrderDetailRepository
-	productRepo    interfaces.ProductSparePartRepository
-	receiptRepo    interfaces.GoodsReceiptRepository
-	stockRepo      interfaces.StockMovementRepository
+	poRepo           interfaces.PurchaseOrderPartsRepository
+	poDetailRepo     interfaces.PurchaseOrderDetailRepository
+	productRepo      interfaces.ProductSparePartRepository
+	receiptRepo      interfaces.GoodsReceiptRepository
+	stockRepo        interfaces.StockMovementRepository
+	blanketRepo      interfaces.BlanketOrderRepository
+	priceListRepo    interfaces.SupplierPriceListRepository
+	taxCodeRepo      interfaces.TaxCodeRepository
+	exchangeRateRepo interfaces.ExchangeRateRepository
 }
 
 // NewPurchaseOrderService creates a new purchase order service
@@ -26,18 +30,47 @@ func NewPurchaseOrderService(
 	productRepo interfaces.ProductSparePartRepository,
 	receiptRepo interfaces.GoodsReceiptRepository,
 	stockRepo interfaces.StockMovementRepository,
+	blanketRepo interfaces.BlanketOrderRepository,
+	priceListRepo interfaces.SupplierPriceListRepository,
+	taxCodeRepo interfaces.TaxCodeRepository,
+	exchangeRateRepo interfaces.ExchangeRateRepository,
 ) *PurchaseOrderService {
 	return &PurchaseOrderService{
-		poRepo:       poRepo,
-		poDetailRepo: poDetailRepo,
-		productRepo:  productRepo,
-		receiptRepo:  receiptRepo,
-		stockRepo:    stockRepo,
+		poRepo:           poRepo,
+		poDetailRepo:     poDetailRepo,
+		productRepo:      productRepo,
+		receiptRepo:      receiptRepo,
+		stockRepo:        stockRepo,
+		blanketRepo:      blanketRepo,
+		priceListRepo:    priceListRepo,
+		taxCodeRepo:      taxCodeRepo,
+		exchangeRateRepo: exchangeRateRepo,
 	}
 }
 
 // CreatePurchaseOrder creates a new purchase order with auto-generated number
 func (s *PurchaseOrderService) CreatePurchaseOrder(ctx context.Context, req *products.PurchaseOrderPartsCreateRequest, createdBy int) (*products.PurchaseOrderParts, error) {
+	// Blanket and contract orders need agreed terms to draw releases against
+	if req.POType.IsBlanketType() {
+		if req.BlanketTerms == nil {
+			return nil, fmt.Errorf("%s purchase orders require blanket terms", req.POType)
+		}
+		if err := req.BlanketTerms.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Foreign currency orders are valued at the rate in effect on the PO date
+	poDate := time.Now()
+	currencyCode, err := products.NormalizeCurrencyCode(req.CurrencyCode)
+	if err != nil {
+		return nil, err
+	}
+	exchangeRate, err := documentExchangeRate(ctx, s.exchangeRateRepo, currencyCode, poDate, req.ExchangeRate)
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate PO number
 	poNumber, err := s.poRepo.GenerateNumber(ctx)
 	if err != nil {
@@ -48,7 +81,7 @@ func (s *PurchaseOrderService) CreatePurchaseOrder(ctx context.Context, req *pro
 	po := &products.PurchaseOrderParts{
 		PONumber:             poNumber,
 		SupplierID:           req.SupplierID,
-		PODate:               time.Now(),
+		PODate:               poDate,
 		RequiredDate:         req.RequiredDate,
 		ExpectedDeliveryDate: req.ExpectedDeliveryDate,
 		POType:               req.POType,
@@ -63,6 +96,9 @@ func (s *PurchaseOrderService) CreatePurchaseOrder(ctx context.Context, req *pro
 		DeliveryAddress:      req.DeliveryAddress,
 		PONotes:              req.PONotes,
 		TermsAndConditions:   req.TermsAndConditions,
+		PricesIncludeTax:     req.PricesIncludeTax,
+		CurrencyCode:         currencyCode,
+		ExchangeRate:         exchangeRate,
 	}
 
 	// Set payment due date based on terms
@@ -74,11 +110,28 @@ func (s *PurchaseOrderService) CreatePurchaseOrder(ctx context.Context, req *pro
 		return nil, fmt.Errorf("failed to create purchase order: %w", err)
 	}
 
+	if req.POType.IsBlanketType() {
+		terms := &products.BlanketOrderTerms{
+			POID:           createdPO.POID,
+			AgreedQuantity: req.BlanketTerms.AgreedQuantity,
+			AgreedValue:    req.BlanketTerms.AgreedValue,
+			ValidFrom:      req.BlanketTerms.ValidFrom,
+			ValidTo:        req.BlanketTerms.ValidTo,
+		}
+		if _, err := s.blanketRepo.CreateTerms(ctx, terms); err != nil {
+			s.poRepo.Delete(ctx, createdPO.POID)
+			return nil, fmt.Errorf("failed to create blanket terms: %w", err)
+		}
+	}
+
 	return createdPO, nil
 }
 
-// AddLineItem adds a line item to a purchase order
-func (s *PurchaseOrderService) AddLineItem(ctx context.Context, poID int, req *products.PurchaseOrderDetailCreateRequest) (*products.PurchaseOrderDetail, error) {
+// AddLineItem adds a line item to a purchase order.
+// The unit cost defaults from the supplier's active price list when omitted, and the result
+// carries warnings when the entered cost exceeds the list price or the quantity is below
+// the supplier's minimum order quantity.
+func (s *PurchaseOrderService) AddLineItem(ctx context.Context, poID int, req *products.PurchaseOrderDetailCreateRequest) (*products.PurchaseOrderLineItemResult, error) {
 	// Verify PO exists and is editable
 	po, err := s.poRepo.GetByID(ctx, poID)
 	if err != nil {
@@ -89,28 +142,110 @@ func (s *PurchaseOrderService) AddLineItem(ctx context.Context, poID int, req *p
 		return nil, fmt.Errorf("purchase order cannot be edited in current status: %s", po.Status)
 	}
 
+	result, err := s.addLineItem(ctx, po, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Recalculate PO totals
+	_, err = s.poRepo.CalculateTotals(ctx, poID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recalculate totals: %w", err)
+	}
+
+	return result, nil
+}
+
+// AddLineItems adds several line items to a purchase order, applying the same price list
+// defaulting and checks as AddLineItem to each line
+func (s *PurchaseOrderService) AddLineItems(ctx context.Context, poID int, reqs []products.PurchaseOrderDetailCreateRequest) ([]*products.PurchaseOrderLineItemResult, error) {
+	po, err := s.poRepo.GetByID(ctx, poID)
+	if err != nil {
+		return nil, fmt.Errorf("purchase order not found: %w", err)
+	}
+
+	if !po.CanEdit() {
+		return nil, fmt.Errorf("purchase order cannot be edited in current status: %s", po.Status)
+	}
+
+	var results []*products.PurchaseOrderLineItemResult
+	for i := range reqs {
+		result, err := s.addLineItem(ctx, po, &reqs[i])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		results = append(results, result)
+	}
+
+	_, err = s.poRepo.CalculateTotals(ctx, poID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recalculate totals: %w", err)
+	}
+
+	return results, nil
+}
+
+// addLineItem prices and creates a single line item on an editable purchase order
+func (s *PurchaseOrderService) addLineItem(ctx context.Context, po *products.PurchaseOrderParts, req *products.PurchaseOrderDetailCreateRequest) (*products.PurchaseOrderLineItemResult, error) {
 	// Verify product exists
 	product, err := s.productRepo.GetByID(ctx, req.ProductID)
 	if err != nil {
 		return nil, fmt.Errorf("product not found: %w", err)
 	}
 
+	taxCode, err := activeTaxCode(ctx, s.taxCodeRepo, req.TaxCodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	priceList, err := s.priceListRepo.FindActive(ctx, po.SupplierID, req.ProductID, po.CurrencyCode, po.PODate)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &products.PurchaseOrderLineItemResult{}
+	var unitCost float64
+	if priceList != nil {
+		listUnitCost := priceList.GetUnitPrice(req.QuantityOrdered)
+		result.PriceListID = &priceList.PriceListID
+		result.ListUnitCost = &listUnitCost
+		unitCost = listUnitCost
+
+		if req.QuantityOrdered < priceList.MinimumOrderQuantity {
+			result.Warnings = append(result.Warnings, fmt.Sprintf(
+				"quantity %d is below the supplier minimum order quantity of %d",
+				req.QuantityOrdered, priceList.MinimumOrderQuantity))
+		}
+	}
+
+	if req.UnitCost != nil {
+		unitCost = *req.UnitCost
+		if result.ListUnitCost != nil && unitCost > *result.ListUnitCost {
+			result.Warnings = append(result.Warnings, fmt.Sprintf(
+				"unit cost %.2f exceeds the supplier price list cost of %.2f",
+				unitCost, *result.ListUnitCost))
+		}
+	} else if priceList == nil {
+		return nil, fmt.Errorf("unit cost is required: no active price list for product %d from this supplier", req.ProductID)
+	}
+
 	// Create line item
 	detail := &products.PurchaseOrderDetail{
-		POID:            poID,
+		POID:            po.POID,
 		ProductID:       req.ProductID,
 		ItemDescription: req.ItemDescription,
 		QuantityOrdered: req.QuantityOrdered,
 		QuantityReceived: 0,
 		QuantityPending: req.QuantityOrdered,
-		UnitCost:        req.UnitCost,
+		UnitCost:        unitCost,
 		ExpectedDate:    req.ExpectedDate,
 		LineStatus:      products.LineStatusPending,
 		ItemNotes:       req.ItemNotes,
 	}
 
-	// Calculate total cost
+	// Calculate total cost and tax
 	detail.CalculateTotalCost()
+	detail.ApplyTax(taxCode, po.PricesIncludeTax)
 
 	// Create detail
 	createdDetail, err := s.poDetailRepo.Create(ctx, detail)
@@ -118,10 +253,19 @@ func (s *PurchaseOrderService) AddLineItem(ctx context.Context, poID int, req *p
 		return nil, fmt.Errorf("failed to create line item: %w", err)
 	}
 
-	// Recalculate PO totals
-	_, err = s.poRepo.CalculateTotals(ctx, poID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to recalculate totals: %w", err)
+	// Keep the paid price for negotiation history
+	quantity := createdDetail.QuantityOrdered
+	history := &products.SupplierPriceHistory{
+		SupplierID:  po.SupplierID,
+		ProductID:   createdDetail.ProductID,
+		Currency:    po.CurrencyCode,
+		UnitPrice:   createdDetail.UnitCost,
+		Quantity:    &quantity,
+		Source:      products.PriceHistorySourcePurchaseOrder,
+		ReferenceID: createdDetail.PODetailID,
+	}
+	if err := s.priceListRepo.RecordPriceHistory(ctx, history); err != nil {
+		return nil, err
 	}
 
 	// Use product name as description if not provided
@@ -129,7 +273,8 @@ func (s *PurchaseOrderService) AddLineItem(ctx context.Context, poID int, req *p
 		createdDetail.ItemDescription = &product.ProductName
 	}
 
-	return createdDetail, nil
+	result.Detail = createdDetail
+	return result, nil
 }
 
 // GetPurchaseOrder retrieves a purchase order by ID
@@ -227,6 +372,12 @@ func (s *PurchaseOrderService) CancelPurchaseOrder(ctx context.Context, id int)
 		return fmt.Errorf("failed to cancel purchase order: %w", err)
 	}
 
+	// Return the balance of a cancelled release to its blanket order
+	_, err = s.blanketRepo.CancelRelease(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to cancel blanket release: %w", err)
+	}
+
 	return nil
 }
 
@@ -242,6 +393,10 @@ func (s *PurchaseOrderService) CreateGoodsReceipt(ctx context.Context, req *prod
 		return nil, fmt.Errorf("purchase order status does not allow goods receipt: %s", po.Status)
 	}
 
+	if po.POType.IsBlanketType() {
+		return nil, fmt.Errorf("goods cannot be received against a %s purchase order; receive against its release orders", po.POType)
+	}
+
 	// Generate receipt number
 	receiptNumber, err := s.receiptRepo.GenerateNumber(ctx)
 	if err != nil {
@@ -410,8 +565,12 @@ func (s *PurchaseOrderService) UpdatePurchaseOrder(ctx context.Context, id int,
 		po.ExpectedDeliveryDate = req.ExpectedDeliveryDate
 	}
 	if req.POType != nil {
+		if req.POType.IsBlanketType() != po.POType.IsBlanketType() {
+			return nil, fmt.Errorf("purchase order type cannot be changed between blanket and non-blanket")
+		}
 		po.POType = *req.POType
 	}
+	// A manual tax amount only applies while no line carries a tax code
 	if req.TaxAmount != nil {
 		po.TaxAmount = *req.TaxAmount
 	}
@@ -435,14 +594,157 @@ func (s *PurchaseOrderService) UpdatePurchaseOrder(ctx context.Context, id int,
 		po.TermsAndConditions = req.TermsAndConditions
 	}
 
-	// Recalculate totals
-	po.CalculateTotals()
-
 	// Update PO
-	updatedPO, err := s.poRepo.Update(ctx, id, po)
+	_, err = s.poRepo.Update(ctx, id, po)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update purchase order: %w", err)
 	}
 
-	return updatedPO, nil
-}
\ No newline at end of file
+	// Recalculate totals, taking tax from the lines once they carry tax codes
+	return s.poRepo.CalculateTotals(ctx, id)
+}
+
+// CreateBlanketRelease draws a release order against a blanket or contract purchase order.
+// Lines are priced at the blanket's fixed unit costs and the release is blocked once the
+// blanket is expired or its agreed quantity or value is exhausted. The balances are checked
+// and consumed in the same transaction that creates the release order.
+func (s *PurchaseOrderService) CreateBlanketRelease(ctx context.Context, blanketPOID int, req *products.BlanketReleaseCreateRequest, createdBy int) (*products.PurchaseOrderParts, error) {
+	blanket, err := s.poRepo.GetByID(ctx, blanketPOID)
+	if err != nil {
+		return nil, fmt.Errorf("purchase order not found: %w", err)
+	}
+
+	if !blanket.POType.IsBlanketType() {
+		return nil, fmt.Errorf("purchase order %s is not a blanket or contract order", blanket.PONumber)
+	}
+
+	if !blanket.IsApproved() || (blanket.Status != products.POStatusSent && blanket.Status != products.POStatusAcknowledged && blanket.Status != products.POStatusPartialReceived) {
+		return nil, fmt.Errorf("blanket order is not open for releases in current status: %s", blanket.Status)
+	}
+
+	lines, err := s.blanketRepo.GetLineBalances(ctx, blanketPOID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blanket lines: %w", err)
+	}
+
+	linesByProduct := make(map[int]products.BlanketLineBalance, len(lines))
+	for _, line := range lines {
+		linesByProduct[line.ProductID] = line
+	}
+
+	// Build release lines at the blanket's fixed prices
+	var details []products.PurchaseOrderDetail
+	var releaseQuantity int
+	var releaseValue float64
+	for _, item := range req.Items {
+		line, ok := linesByProduct[item.ProductID]
+		if !ok {
+			return nil, fmt.Errorf("product %d is not on blanket order %s", item.ProductID, blanket.PONumber)
+		}
+
+		detail := products.PurchaseOrderDetail{
+			ProductID:       item.ProductID,
+			ItemDescription: line.ItemDescription,
+			QuantityOrdered: item.Quantity,
+			QuantityPending: item.Quantity,
+			UnitCost:        line.UnitCost,
+			TaxCodeID:       line.TaxCodeID,
+			TaxRate:         line.TaxRate,
+			ExpectedDate:    req.ExpectedDeliveryDate,
+			LineStatus:      products.LineStatusPending,
+			ItemNotes:       item.ItemNotes,
+		}
+		detail.CalculateTotalCost()
+		details = append(details, detail)
+
+		releaseQuantity += item.Quantity
+		releaseValue += detail.TotalCost
+	}
+
+	// Releases are raised in the blanket order currency at the rate of the release date
+	releaseDate := time.Now()
+	exchangeRate, err := documentExchangeRate(ctx, s.exchangeRateRepo, blanket.CurrencyCode, releaseDate, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	poNumber, err := s.poRepo.GenerateNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate PO number: %w", err)
+	}
+
+	deliveryAddress := req.DeliveryAddress
+	if deliveryAddress == nil {
+		deliveryAddress = blanket.DeliveryAddress
+	}
+
+	release := &products.PurchaseOrderParts{
+		PONumber:             poNumber,
+		SupplierID:           blanket.SupplierID,
+		PODate:               releaseDate,
+		RequiredDate:         req.RequiredDate,
+		ExpectedDeliveryDate: req.ExpectedDeliveryDate,
+		POType:               products.POTypeRegular,
+		Status:               products.POStatusDraft,
+		PaymentTerms:         blanket.PaymentTerms,
+		CreatedBy:            createdBy,
+		DeliveryAddress:      deliveryAddress,
+		PONotes:              req.PONotes,
+		TermsAndConditions:   blanket.TermsAndConditions,
+		PricesIncludeTax:     blanket.PricesIncludeTax,
+		CurrencyCode:         blanket.CurrencyCode,
+		ExchangeRate:         exchangeRate,
+	}
+	release.SetPaymentDueDate()
+
+	_, err = s.blanketRepo.CreateRelease(ctx, &products.BlanketRelease{
+		BlanketPOID:     blanketPOID,
+		ReleaseQuantity: releaseQuantity,
+		ReleaseValue:    releaseValue,
+		ReleasedBy:      createdBy,
+	}, release, details)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release order: %w", err)
+	}
+
+	return s.poRepo.CalculateTotals(ctx, release.POID)
+}
+
+// GetBlanketBalance retrieves the consumed and remaining balance of a blanket or contract purchase order
+func (s *PurchaseOrderService) GetBlanketBalance(ctx context.Context, poID int) (*products.BlanketOrderBalance, error) {
+	po, err := s.poRepo.GetByID(ctx, poID)
+	if err != nil {
+		return nil, fmt.Errorf("purchase order not found: %w", err)
+	}
+
+	if !po.POType.IsBlanketType() {
+		return nil, fmt.Errorf("purchase order %s is not a blanket or contract order", po.PONumber)
+	}
+
+	terms, err := s.blanketRepo.GetTerms(ctx, poID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blanket terms: %w", err)
+	}
+
+	lines, err := s.blanketRepo.GetLineBalances(ctx, poID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blanket lines: %w", err)
+	}
+
+	releases, err := s.blanketRepo.ListReleases(ctx, poID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blanket releases: %w", err)
+	}
+
+	return &products.BlanketOrderBalance{
+		POID:              po.POID,
+		PONumber:          po.PONumber,
+		Terms:             terms,
+		RemainingQuantity: terms.GetRemainingQuantity(),
+		RemainingValue:    terms.GetRemainingValue(),
+		IsExpired:         terms.IsExpired(time.Now()),
+		IsExhausted:       terms.IsExhausted(),
+		Lines:             lines,
+		Releases:          releases,
+	}, nil
+}
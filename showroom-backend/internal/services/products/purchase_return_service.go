@@ -0,0 +1,381 @@
+package products
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// PurchaseReturnService handles business logic for returns to vendor and supplier debit notes
+type PurchaseReturnService struct {
+	purchaseReturnRepo     interfaces.PurchaseReturnRepository
+	debitNoteRepo          interfaces.SupplierDebitNoteRepository
+	goodsReceiptRepo       interfaces.GoodsReceiptRepository
+	goodsReceiptDetailRepo interfaces.GoodsReceiptDetailRepository
+	poRepo                 interfaces.PurchaseOrderPartsRepository
+	stockMovementRepo      interfaces.StockMovementRepository
+	productRepo            interfaces.ProductSparePartRepository
+}
+
+// NewPurchaseReturnService creates a new purchase return service
+func NewPurchaseReturnService(
+	purchaseReturnRepo interfaces.PurchaseReturnRepository,
+	debitNoteRepo interfaces.SupplierDebitNoteRepository,
+	goodsReceiptRepo interfaces.GoodsReceiptRepository,
+	goodsReceiptDetailRepo interfaces.GoodsReceiptDetailRepository,
+	poRepo interfaces.PurchaseOrderPartsRepository,
+	stockMovementRepo interfaces.StockMovementRepository,
+	productRepo interfaces.ProductSparePartRepository,
+) *PurchaseReturnService {
+	return &PurchaseReturnService{
+		purchaseReturnRepo:     purchaseReturnRepo,
+		debitNoteRepo:          debitNoteRepo,
+		goodsReceiptRepo:       goodsReceiptRepo,
+		goodsReceiptDetailRepo: goodsReceiptDetailRepo,
+		poRepo:                 poRepo,
+		stockMovementRepo:      stockMovementRepo,
+		productRepo:            productRepo,
+	}
+}
+
+// CreateReturnFromStock creates a return-to-vendor document for goods taken out of stock
+func (s *PurchaseReturnService) CreateReturnFromStock(ctx context.Context, req *products.PurchaseReturnCreateRequest, createdBy int) (*products.PurchaseReturn, error) {
+	if req.POID != nil {
+		po, err := s.poRepo.GetByID(ctx, *req.POID)
+		if err != nil {
+			return nil, fmt.Errorf("purchase order not found: %w", err)
+		}
+		if po.SupplierID != req.SupplierID {
+			return nil, fmt.Errorf("purchase order does not belong to supplier")
+		}
+	}
+
+	var details []products.PurchaseReturnDetail
+	for _, item := range req.Items {
+		product, err := s.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("product %d not found: %w", item.ProductID, err)
+		}
+
+		if product.StockQuantity < item.QuantityReturned {
+			return nil, fmt.Errorf("insufficient stock for product %d: available %d, requested %d",
+				item.ProductID, product.StockQuantity, item.QuantityReturned)
+		}
+
+		// The line is priced for the debit note; the goods leave inventory at their cost on approval
+		unitCost := product.CostPrice
+		if item.UnitCost != nil {
+			unitCost = *item.UnitCost
+		}
+
+		details = append(details, products.PurchaseReturnDetail{
+			ProductID:        item.ProductID,
+			QuantityReturned: item.QuantityReturned,
+			UnitCost:         unitCost,
+			ReturnReason:     item.ReturnReason,
+		})
+	}
+
+	returnNumber, err := s.purchaseReturnRepo.GenerateNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate return number: %w", err)
+	}
+
+	purchaseReturn := &products.PurchaseReturn{
+		ReturnNumber:   returnNumber,
+		SupplierID:     req.SupplierID,
+		POID:           req.POID,
+		ReturnSource:   products.ReturnSourceStock,
+		ReturnStatus:   products.ReturnStatusDraft,
+		ShipmentStatus: products.ShipmentStatusPending,
+		ReturnReason:   req.ReturnReason,
+		ReturnNotes:    req.ReturnNotes,
+		CreatedBy:      createdBy,
+	}
+	if req.ReturnDate != nil {
+		purchaseReturn.ReturnDate = *req.ReturnDate
+	}
+
+	createdReturn, err := s.purchaseReturnRepo.Create(ctx, purchaseReturn, details)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create purchase return: %w", err)
+	}
+
+	return createdReturn, nil
+}
+
+// CreateReturnFromReceipt creates a return-to-vendor document for the rejected lines of a goods receipt
+func (s *PurchaseReturnService) CreateReturnFromReceipt(ctx context.Context, receiptID int, req *products.PurchaseReturnFromReceiptRequest, createdBy int) (*products.PurchaseReturn, error) {
+	receipt, err := s.goodsReceiptRepo.GetByID(ctx, receiptID)
+	if err != nil {
+		return nil, fmt.Errorf("goods receipt not found: %w", err)
+	}
+
+	po, err := s.poRepo.GetByID(ctx, receipt.POID)
+	if err != nil {
+		return nil, fmt.Errorf("purchase order not found: %w", err)
+	}
+
+	receiptDetails, err := s.goodsReceiptDetailRepo.GetByReceiptID(ctx, receiptID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipt details: %w", err)
+	}
+
+	selected := make(map[int]bool, len(req.ReceiptDetailIDs))
+	for _, id := range req.ReceiptDetailIDs {
+		selected[id] = true
+	}
+
+	var details []products.PurchaseReturnDetail
+	for _, receiptDetail := range receiptDetails {
+		if len(selected) > 0 && !selected[receiptDetail.ReceiptDetailID] {
+			continue
+		}
+		if receiptDetail.QuantityRejected <= 0 {
+			continue
+		}
+
+		alreadyReturned, err := s.purchaseReturnRepo.GetReturnedQuantity(ctx, receiptDetail.ReceiptDetailID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get returned quantity: %w", err)
+		}
+
+		quantity := receiptDetail.QuantityRejected - alreadyReturned
+		if quantity <= 0 {
+			continue
+		}
+
+		receiptDetailID := receiptDetail.ReceiptDetailID
+		details = append(details, products.PurchaseReturnDetail{
+			ProductID:        receiptDetail.ProductID,
+			ReceiptDetailID:  &receiptDetailID,
+			QuantityReturned: quantity,
+			UnitCost:         receiptDetail.UnitCost,
+			ReturnReason:     receiptDetail.RejectionReason,
+		})
+	}
+
+	if len(details) == 0 {
+		return nil, fmt.Errorf("goods receipt has no rejected quantities left to return")
+	}
+
+	returnNumber, err := s.purchaseReturnRepo.GenerateNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate return number: %w", err)
+	}
+
+	poID := receipt.POID
+	purchaseReturn := &products.PurchaseReturn{
+		ReturnNumber:   returnNumber,
+		SupplierID:     po.SupplierID,
+		ReceiptID:      &receiptID,
+		POID:           &poID,
+		ReturnSource:   products.ReturnSourceReceipt,
+		ReturnStatus:   products.ReturnStatusDraft,
+		ShipmentStatus: products.ShipmentStatusPending,
+		ReturnReason:   fmt.Sprintf("Rejected on goods receipt %s", receipt.ReceiptNumber),
+		ReturnNotes:    req.ReturnNotes,
+		CreatedBy:      createdBy,
+	}
+	if req.ReturnDate != nil {
+		purchaseReturn.ReturnDate = *req.ReturnDate
+	}
+
+	createdReturn, err := s.purchaseReturnRepo.Create(ctx, purchaseReturn, details)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create purchase return: %w", err)
+	}
+
+	return createdReturn, nil
+}
+
+// GetPurchaseReturn retrieves a purchase return by ID
+func (s *PurchaseReturnService) GetPurchaseReturn(ctx context.Context, id int) (*products.PurchaseReturn, error) {
+	purchaseReturn, err := s.purchaseReturnRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase return: %w", err)
+	}
+
+	return purchaseReturn, nil
+}
+
+// GetReturnDetails retrieves the line items of a purchase return
+func (s *PurchaseReturnService) GetReturnDetails(ctx context.Context, id int) ([]products.PurchaseReturnDetail, error) {
+	details, err := s.purchaseReturnRepo.GetDetails(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase return details: %w", err)
+	}
+
+	return details, nil
+}
+
+// ListPurchaseReturns retrieves purchase returns with pagination
+func (s *PurchaseReturnService) ListPurchaseReturns(ctx context.Context, params *products.PurchaseReturnFilterParams) (*common.PaginatedResponse, error) {
+	returns, err := s.purchaseReturnRepo.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list purchase returns: %w", err)
+	}
+
+	return returns, nil
+}
+
+// ApprovePurchaseReturn approves a return, posts the return stock movements and issues
+// a supplier debit note that is applied against the supplier's outstanding invoices
+func (s *PurchaseReturnService) ApprovePurchaseReturn(ctx context.Context, id int, approvedBy int) (*products.SupplierDebitNote, error) {
+	purchaseReturn, err := s.purchaseReturnRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase return: %w", err)
+	}
+
+	if !purchaseReturn.CanApprove() {
+		return nil, fmt.Errorf("purchase return cannot be approved in %s status", purchaseReturn.ReturnStatus)
+	}
+
+	details, err := s.purchaseReturnRepo.GetDetails(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase return details: %w", err)
+	}
+
+	// Rejected receipt lines are priced in the receipt currency; returns from stock are at base cost
+	currencyCode, exchangeRate := products.BaseCurrency, 1.0
+	if purchaseReturn.ReceiptID != nil {
+		receipt, err := s.goodsReceiptRepo.GetByID(ctx, *purchaseReturn.ReceiptID)
+		if err != nil {
+			return nil, fmt.Errorf("goods receipt not found: %w", err)
+		}
+		currencyCode, exchangeRate = receipt.CurrencyCode, receipt.ExchangeRate
+	}
+
+	reason := "Return to vendor"
+	if purchaseReturn.ReturnSource == products.ReturnSourceReceipt {
+		reason = "Return to vendor (rejected on receipt)"
+	}
+
+	movements := make([]products.StockMovement, 0, len(details))
+	for _, detail := range details {
+		movements = append(movements, products.StockMovement{
+			ProductID:      detail.ProductID,
+			MovementType:   products.MovementTypeReturn,
+			ReferenceType:  products.ReferenceTypeReturn,
+			ReferenceID:    id,
+			QuantityMoved:  detail.QuantityReturned,
+			UnitCost:       products.ToBaseCurrency(detail.UnitCost, exchangeRate),
+			ProcessedBy:    approvedBy,
+			MovementReason: &reason,
+		})
+	}
+
+	debitNoteNumber, err := s.debitNoteRepo.GenerateNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate debit note number: %w", err)
+	}
+
+	notes := fmt.Sprintf("Debit note for return %s", purchaseReturn.ReturnNumber)
+	debitNote := &products.SupplierDebitNote{
+		DebitNoteNumber: debitNoteNumber,
+		SupplierID:      purchaseReturn.SupplierID,
+		ReturnID:        &id,
+		CurrencyCode:    currencyCode,
+		ExchangeRate:    exchangeRate,
+		DebitAmount:     purchaseReturn.TotalReturnValue,
+		Notes:           &notes,
+		CreatedBy:       approvedBy,
+	}
+
+	// The approval, return movements and the debit note with its application are committed together
+	if err := s.purchaseReturnRepo.Approve(ctx, id, approvedBy, movements, debitNote); err != nil {
+		return nil, fmt.Errorf("failed to approve purchase return: %w", err)
+	}
+
+	return s.debitNoteRepo.GetByID(ctx, debitNote.DebitNoteID)
+}
+
+// UpdateShipment updates the shipment tracking of an approved purchase return
+func (s *PurchaseReturnService) UpdateShipment(ctx context.Context, id int, req *products.PurchaseReturnShipmentRequest) (*products.PurchaseReturn, error) {
+	if !req.ShipmentStatus.IsValid() {
+		return nil, fmt.Errorf("invalid shipment status: %s", req.ShipmentStatus)
+	}
+
+	purchaseReturn, err := s.purchaseReturnRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase return: %w", err)
+	}
+
+	if !purchaseReturn.CanShip() {
+		return nil, fmt.Errorf("purchase return must be approved before shipping")
+	}
+
+	if req.CarrierName != nil {
+		purchaseReturn.CarrierName = req.CarrierName
+	}
+	if req.TrackingNumber != nil {
+		purchaseReturn.TrackingNumber = req.TrackingNumber
+	}
+	purchaseReturn.ApplyShipmentStatus(req.ShipmentStatus)
+
+	if err := s.purchaseReturnRepo.UpdateShipment(ctx, id, purchaseReturn); err != nil {
+		return nil, fmt.Errorf("failed to update shipment: %w", err)
+	}
+
+	return s.purchaseReturnRepo.GetByID(ctx, id)
+}
+
+// CancelPurchaseReturn cancels a draft purchase return
+func (s *PurchaseReturnService) CancelPurchaseReturn(ctx context.Context, id int) error {
+	purchaseReturn, err := s.purchaseReturnRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get purchase return: %w", err)
+	}
+
+	if !purchaseReturn.CanCancel() {
+		return fmt.Errorf("only draft purchase returns can be cancelled")
+	}
+
+	if err := s.purchaseReturnRepo.UpdateStatus(ctx, id, products.ReturnStatusCancelled); err != nil {
+		return fmt.Errorf("failed to cancel purchase return: %w", err)
+	}
+
+	return nil
+}
+
+// GetDebitNote retrieves a supplier debit note by ID
+func (s *PurchaseReturnService) GetDebitNote(ctx context.Context, id int) (*products.SupplierDebitNote, error) {
+	debitNote, err := s.debitNoteRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get supplier debit note: %w", err)
+	}
+
+	return debitNote, nil
+}
+
+// ListDebitNotes retrieves supplier debit notes with pagination
+func (s *PurchaseReturnService) ListDebitNotes(ctx context.Context, params *products.SupplierDebitNoteFilterParams) (*common.PaginatedResponse, error) {
+	debitNotes, err := s.debitNoteRepo.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list supplier debit notes: %w", err)
+	}
+
+	return debitNotes, nil
+}
+
+// GetDebitNoteApplications retrieves the invoices a debit note was applied to
+func (s *PurchaseReturnService) GetDebitNoteApplications(ctx context.Context, id int) ([]products.SupplierDebitNoteApplication, error) {
+	applications, err := s.debitNoteRepo.GetApplications(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get debit note applications: %w", err)
+	}
+
+	return applications, nil
+}
+
+// ApplyDebitNote applies any unapplied balance of a debit note to the supplier's outstanding invoices
+func (s *PurchaseReturnService) ApplyDebitNote(ctx context.Context, id int, appliedBy int) (*products.SupplierDebitNote, error) {
+	if _, err := s.debitNoteRepo.ApplyToOutstanding(ctx, id, appliedBy); err != nil {
+		return nil, fmt.Errorf("failed to apply debit note: %w", err)
+	}
+
+	return s.debitNoteRepo.GetByID(ctx, id)
+}
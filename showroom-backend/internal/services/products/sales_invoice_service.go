@@ -0,0 +1,181 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// SalesInvoiceService handles business logic for spare part sales invoices
+type SalesInvoiceService struct {
+	invoiceRepo     interfaces.SalesInvoiceRepository
+	customerRepo    interfaces.CustomerRepository
+	productRepo     interfaces.ProductSparePartRepository
+	taxCodeRepo     interfaces.TaxCodeRepository
+	paymentTermRepo interfaces.PaymentTermRepository
+}
+
+// NewSalesInvoiceService creates a new sales invoice service
+func NewSalesInvoiceService(
+	invoiceRepo interfaces.SalesInvoiceRepository,
+	customerRepo interfaces.CustomerRepository,
+	productRepo interfaces.ProductSparePartRepository,
+	taxCodeRepo interfaces.TaxCodeRepository,
+	paymentTermRepo interfaces.PaymentTermRepository,
+) *SalesInvoiceService {
+	return &SalesInvoiceService{
+		invoiceRepo:     invoiceRepo,
+		customerRepo:    customerRepo,
+		productRepo:     productRepo,
+		taxCodeRepo:     taxCodeRepo,
+		paymentTermRepo: paymentTermRepo,
+	}
+}
+
+// CreateInvoice issues a sales invoice. Lines are priced at the product's selling price unless
+// a unit price is given and taxed at the rate of their tax code. Credit sales fall due on the
+// customer's payment term and must fit within their credit limit.
+func (s *SalesInvoiceService) CreateInvoice(ctx context.Context, req *products.SalesInvoiceCreateRequest, createdBy int) (*products.SalesInvoice, error) {
+	paymentType := req.PaymentType
+	if paymentType == "" {
+		paymentType = products.SalesPaymentTypeCash
+	}
+	if !paymentType.IsValid() {
+		return nil, fmt.Errorf("invalid payment type: %s", paymentType)
+	}
+
+	customer, err := s.customerRepo.GetByID(ctx, req.CustomerID)
+	if err != nil {
+		return nil, fmt.Errorf("customer not found: %w", err)
+	}
+	if !customer.IsActive {
+		return nil, fmt.Errorf("customer %s is inactive", customer.CustomerCode)
+	}
+
+	invoiceDate := time.Now()
+	if req.InvoiceDate != nil {
+		invoiceDate = *req.InvoiceDate
+	}
+
+	dueDate := invoiceDate
+	if paymentType == products.SalesPaymentTypeCredit && customer.PaymentTermID != nil {
+		term, err := s.paymentTermRepo.GetByID(ctx, *customer.PaymentTermID)
+		if err != nil {
+			return nil, fmt.Errorf("payment term of customer %s: %w", customer.CustomerCode, err)
+		}
+		dueDate = term.DueDate(invoiceDate)
+	}
+
+	invoice := &products.SalesInvoice{
+		CustomerID:        customer.CustomerID,
+		CustomerName:      customer.CustomerName,
+		CustomerTaxNumber: customer.TaxNumber,
+		CustomerAddress:   customer.Address + ", " + customer.City,
+		InvoiceDate:       invoiceDate,
+		PaymentType:       paymentType,
+		DueDate:           dueDate,
+		PricesIncludeTax:  req.PricesIncludeTax,
+		Notes:             req.Notes,
+		CreatedBy:         createdBy,
+	}
+
+	for i, lineReq := range req.Lines {
+		product, err := s.productRepo.GetByID(ctx, lineReq.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: product not found: %w", i+1, err)
+		}
+		if !product.IsActive {
+			return nil, fmt.Errorf("line %d: product %s is inactive", i+1, product.ProductCode)
+		}
+
+		taxCode, err := activeTaxCode(ctx, s.taxCodeRepo, lineReq.TaxCodeID)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		line := products.SalesInvoiceLine{
+			ProductID:      product.ProductID,
+			Quantity:       lineReq.Quantity,
+			UnitPrice:      product.SellingPrice,
+			DiscountAmount: lineReq.DiscountAmount,
+		}
+		if lineReq.UnitPrice != nil {
+			line.UnitPrice = *lineReq.UnitPrice
+		}
+		if taxCode != nil {
+			line.TaxCodeID = &taxCode.TaxCodeID
+			line.TaxRate = taxCode.Rate
+		}
+		invoice.Lines = append(invoice.Lines, line)
+	}
+
+	if err := invoice.CalculateTotals(); err != nil {
+		return nil, err
+	}
+
+	return s.invoiceRepo.Create(ctx, invoice)
+}
+
+// GetInvoice retrieves a sales invoice with its lines
+func (s *SalesInvoiceService) GetInvoice(ctx context.Context, id int) (*products.SalesInvoice, error) {
+	return s.invoiceRepo.GetByID(ctx, id)
+}
+
+// ListInvoices retrieves sales invoices with filtering and pagination
+func (s *SalesInvoiceService) ListInvoices(ctx context.Context, params *products.SalesInvoiceFilterParams) (*common.PaginatedResponse, error) {
+	if params.InvoiceStatus != nil && !params.InvoiceStatus.IsValid() {
+		return nil, fmt.Errorf("invalid invoice status: %s", *params.InvoiceStatus)
+	}
+	if params.PaymentType != nil && !params.PaymentType.IsValid() {
+		return nil, fmt.Errorf("invalid payment type: %s", *params.PaymentType)
+	}
+
+	invoices, err := s.invoiceRepo.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sales invoices: %w", err)
+	}
+
+	return invoices, nil
+}
+
+// SetTaxInvoiceNumber records the tax invoice number issued for a sales invoice
+func (s *SalesInvoiceService) SetTaxInvoiceNumber(ctx context.Context, id int, req *products.SalesInvoiceTaxNumberRequest) (*products.SalesInvoice, error) {
+	invoice, err := s.invoiceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.TaxAmount <= 0 {
+		return nil, fmt.Errorf("sales invoice %s carries no VAT", invoice.InvoiceNumber)
+	}
+
+	if err := s.invoiceRepo.SetTaxInvoiceNumber(ctx, id, strings.TrimSpace(req.TaxInvoiceNumber)); err != nil {
+		return nil, err
+	}
+
+	return s.invoiceRepo.GetByID(ctx, id)
+}
+
+// VoidInvoice cancels a sales invoice, returning its goods to stock and reversing its journal
+func (s *SalesInvoiceService) VoidInvoice(ctx context.Context, id int, voidedBy int) (*products.SalesInvoice, error) {
+	invoice, err := s.invoiceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !invoice.CanVoid() {
+		if invoice.PaidAmount > 0 {
+			return nil, fmt.Errorf("sales invoice %s has receipts allocated, void them first", invoice.InvoiceNumber)
+		}
+		return nil, fmt.Errorf("sales invoice %s is already void", invoice.InvoiceNumber)
+	}
+
+	if err := s.invoiceRepo.Void(ctx, id, voidedBy); err != nil {
+		return nil, err
+	}
+
+	return s.invoiceRepo.GetByID(ctx, id)
+}
@@ -0,0 +1,464 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// defaultInvoiceTermsDays is used for the due date of invoices without payment terms or a purchase order due date
+const defaultInvoiceTermsDays = 30
+
+// SupplierInvoiceService handles business logic for supplier invoices
+type SupplierInvoiceService struct {
+	invoiceRepo      interfaces.SupplierInvoiceRepository
+	poRepo           interfaces.PurchaseOrderPartsRepository
+	supplierRepo     interfaces.SupplierRepository
+	paymentTermRepo  interfaces.PaymentTermRepository
+	exchangeRateRepo interfaces.ExchangeRateRepository
+}
+
+// NewSupplierInvoiceService creates a new supplier invoice service
+func NewSupplierInvoiceService(
+	invoiceRepo interfaces.SupplierInvoiceRepository,
+	poRepo interfaces.PurchaseOrderPartsRepository,
+	supplierRepo interfaces.SupplierRepository,
+	paymentTermRepo interfaces.PaymentTermRepository,
+	exchangeRateRepo interfaces.ExchangeRateRepository,
+) *SupplierInvoiceService {
+	return &SupplierInvoiceService{
+		invoiceRepo:      invoiceRepo,
+		poRepo:           poRepo,
+		supplierRepo:     supplierRepo,
+		paymentTermRepo:  paymentTermRepo,
+		exchangeRateRepo: exchangeRateRepo,
+	}
+}
+
+// CreateInvoice records an invoice received from a supplier
+func (s *SupplierInvoiceService) CreateInvoice(ctx context.Context, req *products.SupplierInvoiceCreateRequest, createdBy int) (*products.SupplierInvoice, error) {
+	supplier, err := s.supplierRepo.GetByID(ctx, req.SupplierID)
+	if err != nil {
+		return nil, fmt.Errorf("supplier not found: %w", err)
+	}
+
+	currencyCode, err := products.NormalizeCurrencyCode(req.CurrencyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := &products.SupplierInvoice{
+		SupplierID:       req.SupplierID,
+		POID:             req.POID,
+		InvoiceNumber:    req.InvoiceNumber,
+		InvoiceDate:      req.InvoiceDate,
+		InvoiceAmount:    req.InvoiceAmount,
+		TaxAmount:        req.TaxAmount,
+		TaxInvoiceNumber: req.TaxInvoiceNumber,
+		Notes:            req.Notes,
+		CreatedBy:        createdBy,
+	}
+
+	var poDueDate *time.Time
+	if req.POID != nil {
+		po, err := s.poRepo.GetByID(ctx, *req.POID)
+		if err != nil {
+			return nil, fmt.Errorf("purchase order not found: %w", err)
+		}
+		if po.SupplierID != req.SupplierID {
+			return nil, fmt.Errorf("purchase order %s does not belong to this supplier", po.PONumber)
+		}
+		poDueDate = po.PaymentDueDate
+
+		// Invoices against a purchase order are billed in its currency
+		if req.CurrencyCode == "" {
+			currencyCode = po.CurrencyCode
+		} else if currencyCode != po.CurrencyCode {
+			return nil, fmt.Errorf("purchase order %s is in %s, not %s", po.PONumber, po.CurrencyCode, currencyCode)
+		}
+
+		// Default the invoice amount and its VAT to the PO totals
+		if invoice.InvoiceAmount <= 0 {
+			poWithTotals, err := s.poRepo.CalculateTotals(ctx, *req.POID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate PO totals: %w", err)
+			}
+			invoice.InvoiceAmount = poWithTotals.TotalAmount
+			if invoice.TaxAmount == 0 {
+				invoice.TaxAmount = poWithTotals.TaxAmount
+			}
+		}
+	}
+
+	if invoice.InvoiceAmount <= 0 {
+		return nil, fmt.Errorf("invoice amount must be greater than zero")
+	}
+
+	invoice.CurrencyCode = currencyCode
+	invoice.ExchangeRate, err = documentExchangeRate(ctx, s.exchangeRateRepo, currencyCode, invoice.InvoiceDate, req.ExchangeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice.TaxAmount > invoice.InvoiceAmount {
+		return nil, fmt.Errorf("tax amount cannot exceed the invoice amount")
+	}
+
+	// Explicit payment terms win over the supplier's default terms
+	term, err := s.resolvePaymentTerm(ctx, req.PaymentTermID, supplier.PaymentTermID)
+	if err != nil {
+		return nil, err
+	}
+	if term != nil {
+		invoice.ApplyPaymentTerm(term)
+	}
+
+	switch {
+	case req.DueDate != nil:
+		invoice.DueDate = *req.DueDate
+	case term != nil:
+		// Due date already set from the payment terms
+	case poDueDate != nil:
+		invoice.DueDate = *poDueDate
+	default:
+		invoice.DueDate = req.InvoiceDate.AddDate(0, 0, defaultInvoiceTermsDays)
+	}
+
+	if invoice.DueDate.Before(invoice.InvoiceDate) {
+		return nil, fmt.Errorf("due date cannot be before invoice date")
+	}
+
+	if err := s.checkInvoiceNumber(ctx, invoice.SupplierID, invoice.InvoiceNumber, 0); err != nil {
+		return nil, err
+	}
+
+	createdInvoice, err := s.invoiceRepo.Create(ctx, invoice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create supplier invoice: %w", err)
+	}
+
+	return createdInvoice, nil
+}
+
+// GetInvoice retrieves a supplier invoice with the payments allocated to it
+func (s *SupplierInvoiceService) GetInvoice(ctx context.Context, id int) (*products.SupplierInvoice, error) {
+	invoice, err := s.invoiceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.Allocations, err = s.invoiceRepo.GetAllocations(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// GetInvoiceAllocations retrieves the payment installments allocated to a supplier invoice
+func (s *SupplierInvoiceService) GetInvoiceAllocations(ctx context.Context, id int) ([]products.PaymentAllocation, error) {
+	if _, err := s.invoiceRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.invoiceRepo.GetAllocations(ctx, id)
+}
+
+// ListInvoices retrieves supplier invoices with filtering and pagination
+func (s *SupplierInvoiceService) ListInvoices(ctx context.Context, params *products.SupplierInvoiceFilterParams) (*common.PaginatedResponse, error) {
+	if params.InvoiceStatus != nil && !params.InvoiceStatus.IsValid() {
+		return nil, fmt.Errorf("invalid invoice status: %s", *params.InvoiceStatus)
+	}
+
+	invoices, err := s.invoiceRepo.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list supplier invoices: %w", err)
+	}
+
+	return invoices, nil
+}
+
+// GetOverdueInvoices retrieves supplier invoices past their due date with an outstanding balance
+func (s *SupplierInvoiceService) GetOverdueInvoices(ctx context.Context, params *products.SupplierInvoiceFilterParams) (*common.PaginatedResponse, error) {
+	isOverdue := true
+	params.IsOverdue = &isOverdue
+	return s.ListInvoices(ctx, params)
+}
+
+// UpdateInvoice updates a supplier invoice
+func (s *SupplierInvoiceService) UpdateInvoice(ctx context.Context, id int, req *products.SupplierInvoiceUpdateRequest) (*products.SupplierInvoice, error) {
+	existing, err := s.invoiceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.InvoiceNumber != nil && *req.InvoiceNumber != existing.InvoiceNumber {
+		if err := s.checkInvoiceNumber(ctx, existing.SupplierID, *req.InvoiceNumber, id); err != nil {
+			return nil, err
+		}
+		existing.InvoiceNumber = *req.InvoiceNumber
+	}
+	if req.InvoiceDate != nil {
+		existing.InvoiceDate = *req.InvoiceDate
+	}
+
+	// Re-derive the due date and discount window when the terms or the invoice date change
+	if req.PaymentTermID != nil || (req.InvoiceDate != nil && existing.PaymentTermID != nil) {
+		termID := existing.PaymentTermID
+		if req.PaymentTermID != nil {
+			termID = req.PaymentTermID
+		}
+		term, err := s.resolvePaymentTerm(ctx, termID, nil)
+		if err != nil {
+			return nil, err
+		}
+		existing.ApplyPaymentTerm(term)
+	}
+
+	// The rate is re-read for a new invoice date unless one is given. Once payments or credits
+	// are applied their exchange differences depend on it, so it can no longer change.
+	if req.ExchangeRate != nil || req.InvoiceDate != nil {
+		rate, err := documentExchangeRate(ctx, s.exchangeRateRepo, existing.CurrencyCode, existing.InvoiceDate, req.ExchangeRate)
+		if err != nil {
+			return nil, err
+		}
+		if rate != existing.ExchangeRate && existing.GetSettledAmount() > 0 {
+			return nil, fmt.Errorf("exchange rate cannot change after payments or credits are applied")
+		}
+		existing.ExchangeRate = rate
+	}
+
+	if req.DueDate != nil {
+		existing.DueDate = *req.DueDate
+	}
+	if req.InvoiceAmount != nil {
+		settled := existing.GetSettledAmount()
+		if *req.InvoiceAmount < settled {
+			return nil, fmt.Errorf("invoice amount cannot be less than the %.2f already paid and credited", settled)
+		}
+		existing.InvoiceAmount = *req.InvoiceAmount
+	}
+	if req.TaxAmount != nil {
+		existing.TaxAmount = *req.TaxAmount
+	}
+	if req.TaxInvoiceNumber != nil {
+		existing.TaxInvoiceNumber = req.TaxInvoiceNumber
+	}
+	if req.Notes != nil {
+		existing.Notes = req.Notes
+	}
+
+	if existing.DueDate.Before(existing.InvoiceDate) {
+		return nil, fmt.Errorf("due date cannot be before invoice date")
+	}
+
+	if existing.TaxAmount > existing.InvoiceAmount {
+		return nil, fmt.Errorf("tax amount cannot exceed the invoice amount")
+	}
+
+	return s.invoiceRepo.Update(ctx, id, existing)
+}
+
+// DeleteInvoice deletes a supplier invoice that has not been paid or credited
+func (s *SupplierInvoiceService) DeleteInvoice(ctx context.Context, id int) error {
+	invoice, err := s.invoiceRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if invoice.GetSettledAmount() > 0 {
+		return fmt.Errorf("cannot delete a supplier invoice with payments or credits applied")
+	}
+
+	return s.invoiceRepo.Delete(ctx, id)
+}
+
+// UpdateInvoiceStatus disputes an invoice or resolves a dispute.
+// Other statuses are derived from payments and due dates and cannot be set directly.
+func (s *SupplierInvoiceService) UpdateInvoiceStatus(ctx context.Context, id int, status products.PaymentStatus) error {
+	if !status.IsValid() {
+		return fmt.Errorf("invalid invoice status: %s", status)
+	}
+
+	invoice, err := s.invoiceRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case status == products.PaymentStatusDisputed:
+		if invoice.IsFullyPaid() {
+			return fmt.Errorf("cannot dispute a fully paid supplier invoice")
+		}
+	case invoice.InvoiceStatus != products.PaymentStatusDisputed:
+		return fmt.Errorf("invoice status is derived from its payments; only disputes can be raised or resolved")
+	}
+
+	return s.invoiceRepo.UpdateStatus(ctx, id, status)
+}
+
+// UpdateOverdueInvoices refreshes the overdue status of all open supplier invoices
+func (s *SupplierInvoiceService) UpdateOverdueInvoices(ctx context.Context) (int64, error) {
+	updated, err := s.invoiceRepo.UpdateOverdueStatus(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update overdue invoices: %w", err)
+	}
+
+	return updated, nil
+}
+
+// AccrueLatePenalties recalculates the late penalties of overdue invoices at a daily rate in percent
+func (s *SupplierInvoiceService) AccrueLatePenalties(ctx context.Context, dailyRate float64) (int64, error) {
+	if dailyRate < 0 {
+		return 0, fmt.Errorf("daily penalty rate cannot be negative")
+	}
+
+	updated, err := s.invoiceRepo.AccruePenalties(ctx, dailyRate)
+	if err != nil {
+		return 0, fmt.Errorf("failed to accrue late penalties: %w", err)
+	}
+
+	return updated, nil
+}
+
+// GetInvoiceSummary gets invoiced, paid and outstanding totals for a supplier or all suppliers
+func (s *SupplierInvoiceService) GetInvoiceSummary(ctx context.Context, supplierID *int) (map[string]interface{}, error) {
+	summary, err := s.invoiceRepo.GetSummary(ctx, supplierID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get supplier invoice summary: %w", err)
+	}
+
+	return summary, nil
+}
+
+// GetAgingReport builds the accounts payable aging report as of a date, today by default
+func (s *SupplierInvoiceService) GetAgingReport(ctx context.Context, params *products.APAgingParams) (*products.APAgingReport, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	asOf := time.Now()
+	if params.AsOfDate != nil {
+		asOf = *params.AsOfDate
+	}
+
+	invoices, err := s.invoiceRepo.GetAgingInvoices(ctx, asOf, params.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	return products.NewAPAgingReport(asOf, invoices, params.IncludeInvoices), nil
+}
+
+// GetSupplierAging drills down into the aged open invoices of one supplier
+func (s *SupplierInvoiceService) GetSupplierAging(ctx context.Context, supplierID int, params *products.APAgingParams) (*products.APAgingReport, error) {
+	if _, err := s.supplierRepo.GetByID(ctx, supplierID); err != nil {
+		return nil, fmt.Errorf("supplier not found: %w", err)
+	}
+
+	params.SupplierID = &supplierID
+	params.IncludeInvoices = true
+	return s.GetAgingReport(ctx, params)
+}
+
+// GetDiscountOpportunities lists open invoices to pay now to capture their early payment discounts
+func (s *SupplierInvoiceService) GetDiscountOpportunities(ctx context.Context, params *products.EarlyPaymentDiscountParams) (*products.EarlyPaymentDiscountReport, error) {
+	asOf := time.Now()
+	if params.AsOfDate != nil {
+		asOf = *params.AsOfDate
+	}
+
+	opportunities, err := s.invoiceRepo.GetDiscountOpportunities(ctx, asOf, params.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	return products.NewEarlyPaymentDiscountReport(asOf, opportunities, params.WithinDays), nil
+}
+
+// CalculatePaymentTerms calculates the due date and early payment discount window of an invoice.
+// Stored payment terms take precedence over a plain number of net days.
+func (s *SupplierInvoiceService) CalculatePaymentTerms(ctx context.Context, invoiceDate time.Time, termsDays int, paymentTermID *int) (*PaymentTerms, error) {
+	if paymentTermID == nil {
+		if termsDays <= 0 {
+			termsDays = defaultInvoiceTermsDays
+		}
+		return &PaymentTerms{
+			DueDate:   invoiceDate.AddDate(0, 0, termsDays),
+			TermsDays: termsDays,
+		}, nil
+	}
+
+	term, err := s.resolvePaymentTerm(ctx, paymentTermID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := &PaymentTerms{
+		PaymentTermID: &term.TermID,
+		TermCode:      term.TermCode,
+		DueDate:       term.DueDate(invoiceDate),
+		TermsDays:     term.NetDays,
+	}
+	if term.HasDiscount() {
+		terms.EarlyPaymentDate = term.DiscountDueDate(invoiceDate)
+		terms.EarlyPaymentDiscount = term.DiscountPercent / 100
+		terms.AnnualizedReturn = products.AnnualizedDiscountRate(term.DiscountPercent, term.NetDays-term.DiscountDays)
+	}
+
+	return terms, nil
+}
+
+// resolvePaymentTerm loads the requested payment terms, falling back to the supplier's default.
+// A requested term must be active; an inactive supplier default is ignored.
+func (s *SupplierInvoiceService) resolvePaymentTerm(ctx context.Context, requestedID, supplierDefaultID *int) (*products.PaymentTerm, error) {
+	if requestedID != nil {
+		term, err := s.paymentTermRepo.GetByID(ctx, *requestedID)
+		if err != nil {
+			return nil, err
+		}
+		if !term.IsActive {
+			return nil, fmt.Errorf("payment term %s is inactive", term.TermCode)
+		}
+		return term, nil
+	}
+
+	if supplierDefaultID == nil {
+		return nil, nil
+	}
+
+	term, err := s.paymentTermRepo.GetByID(ctx, *supplierDefaultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get supplier payment terms: %w", err)
+	}
+	if !term.IsActive {
+		return nil, nil
+	}
+	return term, nil
+}
+
+// checkInvoiceNumber rejects an invoice number the supplier has already used
+func (s *SupplierInvoiceService) checkInvoiceNumber(ctx context.Context, supplierID int, invoiceNumber string, excludeID int) error {
+	exists, err := s.invoiceRepo.IsInvoiceNumberExists(ctx, supplierID, invoiceNumber, excludeID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("invoice number %s already exists for this supplier", invoiceNumber)
+	}
+	return nil
+}
+
+// PaymentTerms represents payment terms calculation result
+type PaymentTerms struct {
+	PaymentTermID        *int       `json:"payment_term_id,omitempty"`
+	TermCode             string     `json:"term_code,omitempty"`
+	DueDate              time.Time  `json:"due_date"`
+	EarlyPaymentDate     *time.Time `json:"early_payment_date,omitempty"`
+	EarlyPaymentDiscount float64    `json:"early_payment_discount"`
+	AnnualizedReturn     float64    `json:"annualized_return,omitempty"`
+	TermsDays            int        `json:"terms_days"`
+}
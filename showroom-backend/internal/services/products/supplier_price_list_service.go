@@ -0,0 +1,221 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// SupplierPriceListService handles business logic for supplier price lists
+type SupplierPriceListService struct {
+	priceListRepo interfaces.SupplierPriceListRepository
+	supplierRepo  interfaces.SupplierRepository
+	productRepo   interfaces.ProductSparePartRepository
+}
+
+// NewSupplierPriceListService creates a new supplier price list service
+func NewSupplierPriceListService(
+	priceListRepo interfaces.SupplierPriceListRepository,
+	supplierRepo interfaces.SupplierRepository,
+	productRepo interfaces.ProductSparePartRepository,
+) *SupplierPriceListService {
+	return &SupplierPriceListService{
+		priceListRepo: priceListRepo,
+		supplierRepo:  supplierRepo,
+		productRepo:   productRepo,
+	}
+}
+
+// CreatePriceList creates a supplier price list entry for a product
+func (s *SupplierPriceListService) CreatePriceList(ctx context.Context, req *products.SupplierPriceListCreateRequest, createdBy int) (*products.SupplierPriceList, error) {
+	if _, err := s.supplierRepo.GetByID(ctx, req.SupplierID); err != nil {
+		return nil, fmt.Errorf("supplier not found: %w", err)
+	}
+
+	if _, err := s.productRepo.GetByID(ctx, req.ProductID); err != nil {
+		return nil, fmt.Errorf("product not found: %w", err)
+	}
+
+	currency := strings.ToUpper(req.Currency)
+	if currency == "" {
+		currency = products.DefaultCurrency
+	}
+
+	priceList := &products.SupplierPriceList{
+		SupplierID:           req.SupplierID,
+		ProductID:            req.ProductID,
+		Currency:             currency,
+		UnitPrice:            req.UnitPrice,
+		MinimumOrderQuantity: req.MinimumOrderQuantity,
+		ValidFrom:            req.ValidFrom,
+		ValidTo:              req.ValidTo,
+		IsActive:             true,
+		Notes:                req.Notes,
+		CreatedBy:            createdBy,
+	}
+
+	if err := priceList.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := priceList.SetPriceBreaks(req.PriceBreaks); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOverlap(ctx, priceList); err != nil {
+		return nil, err
+	}
+
+	createdPriceList, err := s.priceListRepo.Create(ctx, priceList)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recordQuote(ctx, createdPriceList); err != nil {
+		return nil, err
+	}
+
+	return createdPriceList, nil
+}
+
+// GetPriceList retrieves a supplier price list entry by ID
+func (s *SupplierPriceListService) GetPriceList(ctx context.Context, id int) (*products.SupplierPriceList, error) {
+	return s.priceListRepo.GetByID(ctx, id)
+}
+
+// ListPriceLists retrieves supplier price list entries with filtering and pagination
+func (s *SupplierPriceListService) ListPriceLists(ctx context.Context, params *products.SupplierPriceListFilterParams) (*common.PaginatedResponse, error) {
+	return s.priceListRepo.List(ctx, params)
+}
+
+// UpdatePriceList updates a supplier price list entry
+func (s *SupplierPriceListService) UpdatePriceList(ctx context.Context, id int, req *products.SupplierPriceListUpdateRequest) (*products.SupplierPriceList, error) {
+	priceList, err := s.priceListRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	previousPrice := priceList.UnitPrice
+
+	if req.UnitPrice != nil {
+		priceList.UnitPrice = *req.UnitPrice
+	}
+	if req.MinimumOrderQuantity != nil {
+		priceList.MinimumOrderQuantity = *req.MinimumOrderQuantity
+	}
+	if req.ValidFrom != nil {
+		priceList.ValidFrom = *req.ValidFrom
+	}
+	if req.ValidTo != nil {
+		priceList.ValidTo = req.ValidTo
+	}
+	if req.IsActive != nil {
+		priceList.IsActive = *req.IsActive
+	}
+	if req.Notes != nil {
+		priceList.Notes = req.Notes
+	}
+	if req.PriceBreaks != nil {
+		if err := priceList.SetPriceBreaks(*req.PriceBreaks); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := priceList.Validate(); err != nil {
+		return nil, err
+	}
+
+	if priceList.IsActive {
+		if err := s.checkOverlap(ctx, priceList); err != nil {
+			return nil, err
+		}
+	}
+
+	updatedPriceList, err := s.priceListRepo.Update(ctx, id, priceList)
+	if err != nil {
+		return nil, err
+	}
+
+	if updatedPriceList.UnitPrice != previousPrice {
+		if err := s.recordQuote(ctx, updatedPriceList); err != nil {
+			return nil, err
+		}
+	}
+
+	return updatedPriceList, nil
+}
+
+// DeletePriceList deletes a supplier price list entry
+func (s *SupplierPriceListService) DeletePriceList(ctx context.Context, id int) error {
+	return s.priceListRepo.Delete(ctx, id)
+}
+
+// LookupPrice resolves the effective supplier price for a product and order quantity today
+func (s *SupplierPriceListService) LookupPrice(ctx context.Context, params *products.SupplierPriceLookupParams) (*products.SupplierPriceLookup, error) {
+	currency := strings.ToUpper(params.Currency)
+	if currency == "" {
+		currency = products.DefaultCurrency
+	}
+
+	quantity := params.Quantity
+	if quantity < 1 {
+		quantity = 1
+	}
+
+	priceList, err := s.priceListRepo.FindActive(ctx, params.SupplierID, params.ProductID, currency, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if priceList == nil {
+		return nil, fmt.Errorf("no active price list for product %d from supplier %d in %s",
+			params.ProductID, params.SupplierID, currency)
+	}
+
+	return &products.SupplierPriceLookup{
+		PriceListID:          priceList.PriceListID,
+		SupplierID:           priceList.SupplierID,
+		ProductID:            priceList.ProductID,
+		Currency:             priceList.Currency,
+		Quantity:             quantity,
+		UnitPrice:            priceList.GetUnitPrice(quantity),
+		MinimumOrderQuantity: priceList.MinimumOrderQuantity,
+		BelowMinimum:         quantity < priceList.MinimumOrderQuantity,
+	}, nil
+}
+
+// GetPriceHistory retrieves quoted and paid prices of a supplier for negotiation
+func (s *SupplierPriceListService) GetPriceHistory(ctx context.Context, params *products.SupplierPriceHistoryFilterParams) (*common.PaginatedResponse, error) {
+	if params.Source != nil && !params.Source.IsValid() {
+		return nil, fmt.Errorf("invalid price history source: %s", *params.Source)
+	}
+	return s.priceListRepo.GetPriceHistory(ctx, params)
+}
+
+// checkOverlap rejects a price list whose validity overlaps another active list for the same supplier, product and currency
+func (s *SupplierPriceListService) checkOverlap(ctx context.Context, priceList *products.SupplierPriceList) error {
+	overlaps, err := s.priceListRepo.HasOverlap(ctx, priceList)
+	if err != nil {
+		return err
+	}
+	if overlaps {
+		return fmt.Errorf("another active price list for this supplier, product and currency overlaps the validity period")
+	}
+	return nil
+}
+
+// recordQuote adds the base price of a price list to the supplier price history
+func (s *SupplierPriceListService) recordQuote(ctx context.Context, priceList *products.SupplierPriceList) error {
+	return s.priceListRepo.RecordPriceHistory(ctx, &products.SupplierPriceHistory{
+		SupplierID:  priceList.SupplierID,
+		ProductID:   priceList.ProductID,
+		Currency:    priceList.Currency,
+		UnitPrice:   priceList.UnitPrice,
+		Source:      products.PriceHistorySourcePriceList,
+		ReferenceID: priceList.PriceListID,
+	})
+}
@@ -0,0 +1,123 @@
+package products
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// SupplierScorecardService computes supplier performance scorecards from receipts
+type SupplierScorecardService struct {
+	scorecardRepo interfaces.SupplierScorecardRepository
+	supplierRepo  interfaces.SupplierRepository
+}
+
+// NewSupplierScorecardService creates a new supplier scorecard service
+func NewSupplierScorecardService(
+	scorecardRepo interfaces.SupplierScorecardRepository,
+	supplierRepo interfaces.SupplierRepository,
+) *SupplierScorecardService {
+	return &SupplierScorecardService{
+		scorecardRepo: scorecardRepo,
+		supplierRepo:  supplierRepo,
+	}
+}
+
+// GetScorecard computes the scorecard of a supplier, ranked against all suppliers active in the period
+func (s *SupplierScorecardService) GetScorecard(ctx context.Context, supplierID int, params *products.SupplierScorecardParams) (*products.SupplierScorecard, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	supplier, err := s.supplierRepo.GetByID(ctx, supplierID)
+	if err != nil {
+		return nil, fmt.Errorf("supplier not found: %w", err)
+	}
+
+	metrics, err := s.scorecardRepo.GetPerformanceMetrics(ctx, params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	scorecards := buildScorecards(metrics, params)
+	for i := range scorecards {
+		if scorecards[i].SupplierID == supplierID {
+			return &scorecards[i], nil
+		}
+	}
+
+	// No activity in the period, so the supplier is unranked
+	scorecard := products.NewSupplierScorecard(products.SupplierPerformanceMetrics{
+		SupplierID:   supplier.SupplierID,
+		SupplierCode: supplier.SupplierCode,
+		SupplierName: supplier.SupplierName,
+	})
+	scorecard.PeriodFrom = params.DateFrom
+	scorecard.PeriodTo = params.DateTo
+	scorecard.RankedOutOf = len(scorecards)
+	return &scorecard, nil
+}
+
+// CompareSuppliers ranks suppliers over the same period.
+// All suppliers with activity are compared when no supplier IDs are given.
+func (s *SupplierScorecardService) CompareSuppliers(ctx context.Context, params *products.SupplierScorecardCompareParams) (*products.SupplierScorecardComparison, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var supplierIDs []int
+	seen := make(map[int]bool, len(params.SupplierIDs))
+	for _, supplierID := range params.SupplierIDs {
+		if seen[supplierID] {
+			continue
+		}
+		seen[supplierID] = true
+		supplierIDs = append(supplierIDs, supplierID)
+	}
+
+	metrics, err := s.scorecardRepo.GetPerformanceMetrics(ctx, &params.SupplierScorecardParams, supplierIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Requested suppliers without activity are still listed so they rank last
+	found := make(map[int]bool, len(metrics))
+	for _, m := range metrics {
+		found[m.SupplierID] = true
+	}
+	for _, supplierID := range supplierIDs {
+		if found[supplierID] {
+			continue
+		}
+		supplier, err := s.supplierRepo.GetByID(ctx, supplierID)
+		if err != nil {
+			return nil, fmt.Errorf("supplier %d not found: %w", supplierID, err)
+		}
+		metrics = append(metrics, products.SupplierPerformanceMetrics{
+			SupplierID:   supplier.SupplierID,
+			SupplierCode: supplier.SupplierCode,
+			SupplierName: supplier.SupplierName,
+		})
+	}
+
+	return &products.SupplierScorecardComparison{
+		PeriodFrom: params.DateFrom,
+		PeriodTo:   params.DateTo,
+		Scorecards: buildScorecards(metrics, &params.SupplierScorecardParams),
+	}, nil
+}
+
+// buildScorecards computes and ranks scorecards for the given metrics
+func buildScorecards(metrics []products.SupplierPerformanceMetrics, params *products.SupplierScorecardParams) []products.SupplierScorecard {
+	scorecards := make([]products.SupplierScorecard, 0, len(metrics))
+	for _, m := range metrics {
+		scorecard := products.NewSupplierScorecard(m)
+		scorecard.PeriodFrom = params.DateFrom
+		scorecard.PeriodTo = params.DateTo
+		scorecards = append(scorecards, scorecard)
+	}
+	products.RankSupplierScorecards(scorecards)
+	return scorecards
+}
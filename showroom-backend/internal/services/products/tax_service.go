@@ -0,0 +1,119 @@
+package products
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// TaxService handles tax codes, the monthly VAT report and the e-Faktur export
+type TaxService struct {
+	taxCodeRepo      interfaces.TaxCodeRepository
+	salesInvoiceRepo interfaces.SalesInvoiceRepository
+}
+
+// NewTaxService creates a new tax service
+func NewTaxService(taxCodeRepo interfaces.TaxCodeRepository, salesInvoiceRepo interfaces.SalesInvoiceRepository) *TaxService {
+	return &TaxService{
+		taxCodeRepo:      taxCodeRepo,
+		salesInvoiceRepo: salesInvoiceRepo,
+	}
+}
+
+// CreateTaxCode creates a new tax code
+func (s *TaxService) CreateTaxCode(ctx context.Context, req *products.TaxCodeCreateRequest) (*products.TaxCode, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	exists, err := s.taxCodeRepo.IsCodeExists(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("tax code %s already exists", code)
+	}
+
+	taxCode := &products.TaxCode{
+		Code: code,
+		Name: strings.TrimSpace(req.Name),
+		Rate: req.Rate,
+	}
+
+	return s.taxCodeRepo.Create(ctx, taxCode)
+}
+
+// GetTaxCode retrieves a tax code by ID
+func (s *TaxService) GetTaxCode(ctx context.Context, id int) (*products.TaxCode, error) {
+	return s.taxCodeRepo.GetByID(ctx, id)
+}
+
+// UpdateTaxCode updates a tax code. Lines already on documents keep the rate they were created with.
+func (s *TaxService) UpdateTaxCode(ctx context.Context, id int, req *products.TaxCodeUpdateRequest) (*products.TaxCode, error) {
+	taxCode, err := s.taxCodeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		taxCode.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Rate != nil {
+		taxCode.Rate = *req.Rate
+	}
+	if req.IsActive != nil {
+		taxCode.IsActive = *req.IsActive
+	}
+
+	return s.taxCodeRepo.Update(ctx, id, taxCode)
+}
+
+// ListTaxCodes retrieves tax codes with filtering and pagination
+func (s *TaxService) ListTaxCodes(ctx context.Context, params *products.TaxCodeFilterParams) (*common.PaginatedResponse, error) {
+	taxCodes, err := s.taxCodeRepo.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tax codes: %w", err)
+	}
+
+	return taxCodes, nil
+}
+
+// GetVATReport retrieves the input and output VAT of a month
+func (s *TaxService) GetVATReport(ctx context.Context, req *products.AccountingPeriodRequest) (*products.VATReport, error) {
+	return s.taxCodeRepo.GetVATReport(ctx, req.Start())
+}
+
+// ExportEFaktur renders the output tax invoices of a month as an e-Faktur import CSV
+// and returns the file name with its contents
+func (s *TaxService) ExportEFaktur(ctx context.Context, req *products.AccountingPeriodRequest) (string, []byte, error) {
+	invoices, err := s.salesInvoiceRepo.ListForEFaktur(ctx, req.Start())
+	if err != nil {
+		return "", nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := products.WriteEFakturCSV(&buf, invoices); err != nil {
+		return "", nil, fmt.Errorf("failed to generate e-Faktur file: %w", err)
+	}
+
+	return fmt.Sprintf("efaktur-%04d-%02d.csv", req.Year, req.Month), buf.Bytes(), nil
+}
+
+// activeTaxCode retrieves the tax code a document line refers to, or nil when the line is untaxed
+func activeTaxCode(ctx context.Context, taxCodeRepo interfaces.TaxCodeRepository, id *int) (*products.TaxCode, error) {
+	if id == nil {
+		return nil, nil
+	}
+
+	taxCode, err := taxCodeRepo.GetByID(ctx, *id)
+	if err != nil {
+		return nil, err
+	}
+	if !taxCode.IsActive {
+		return nil, fmt.Errorf("tax code %s is inactive", taxCode.Code)
+	}
+
+	return taxCode, nil
+}
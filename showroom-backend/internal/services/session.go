@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+)
+
+// SessionService applies the session policy and lets users manage the devices they are logged
+// in on
+type SessionService struct {
+	sessionRepo interfaces.UserSessionRepository
+	policy      user.SessionPolicy
+}
+
+// NewSessionService creates a new session service
+func NewSessionService(sessionRepo interfaces.UserSessionRepository, policy user.SessionPolicy) *SessionService {
+	return &SessionService{
+		sessionRepo: sessionRepo,
+		policy:      policy,
+	}
+}
+
+// IdleTimeout returns how long a session may go without requests before it ends
+func (s *SessionService) IdleTimeout() time.Duration {
+	return s.policy.IdleTimeout
+}
+
+// EnforceLimit ends the oldest active sessions of a user beyond the limit of their role. It is
+// called right after a login so that the new session is kept.
+func (s *SessionService) EnforceLimit(ctx context.Context, userID int, role common.UserRole) (int64, error) {
+	limit := s.policy.MaxSessionsFor(role)
+	if limit <= 0 {
+		return 0, nil
+	}
+	return s.sessionRepo.EndOldestSessions(ctx, userID, limit)
+}
+
+// CheckActive returns user.ErrSessionRevoked for sessions that have ended and user.ErrSessionIdle
+// for sessions that have been idle for the idle timeout
+func (s *SessionService) CheckActive(session *user.UserSession, now time.Time) error {
+	if !session.IsActive {
+		return user.ErrSessionRevoked
+	}
+	if session.IsIdle(s.policy.IdleTimeout, now) {
+		return user.ErrSessionIdle
+	}
+	return nil
+}
+
+// ListDevices lists the active sessions of a user, most recent login first, marking the session
+// making the request
+func (s *SessionService) ListDevices(ctx context.Context, userID, currentSessionID int) ([]user.UserSession, error) {
+	sessions, err := s.sessionRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	devices := []user.UserSession{}
+	for _, session := range sessions {
+		if session.IsIdle(s.policy.IdleTimeout, now) {
+			continue
+		}
+		session.SessionToken = ""
+		session.Current = session.SessionID == currentSessionID
+		devices = append(devices, session)
+	}
+
+	return devices, nil
+}
+
+// EndDevice ends one of a user's own active sessions
+func (s *SessionService) EndDevice(ctx context.Context, userID, sessionID int) error {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil || session.UserID != userID || !session.IsActive {
+		return fmt.Errorf("session not found")
+	}
+
+	return s.sessionRepo.UpdateLogout(ctx, sessionID)
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
+)
+
+// signingKeyRetireLeeway keeps a rotated key validating until the last token it can have signed has
+// expired. Other servers sign with the old key until their next key reload, so its tokens are issued
+// up to a reload interval after the rotation; the extra minute covers clock differences between servers.
+const signingKeyRetireLeeway = utils.KeyReloadInterval + time.Minute
+
+// SigningKeyService manages the asymmetric keys access tokens are signed with. It is the key
+// source of the JWT manager. Private keys are stored encrypted with the JWT secret.
+type SigningKeyService struct {
+	signingKeyRepo   interfaces.SigningKeyRepository
+	algorithm        string
+	secret           string
+	tokenLifetime    time.Duration
+	rotationInterval time.Duration
+}
+
+// NewSigningKeyService creates a new signing key service. New keys use algorithm and sign for
+// rotationInterval; rotated keys keep validating for tokenLifetime, the access token lifetime, plus
+// the time other servers take to pick up the new key.
+func NewSigningKeyService(
+	signingKeyRepo interfaces.SigningKeyRepository,
+	algorithm string,
+	secret string,
+	tokenLifetime time.Duration,
+	rotationInterval time.Duration,
+) *SigningKeyService {
+	return &SigningKeyService{
+		signingKeyRepo:   signingKeyRepo,
+		algorithm:        algorithm,
+		secret:           secret,
+		tokenLifetime:    tokenLifetime,
+		rotationInterval: rotationInterval,
+	}
+}
+
+// SigningKeys loads the keys that have not retired, the signing key first
+func (s *SigningKeyService) SigningKeys(ctx context.Context) ([]utils.SigningKey, error) {
+	stored, err := s.signingKeyRepo.ListUsable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]utils.SigningKey, 0, len(stored))
+	for _, key := range stored {
+		data, err := utils.DecryptWithSecret(key.PrivateKey, s.secret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt signing key %s, JWT_SECRET_KEY may have changed: %w", key.KeyID, err)
+		}
+		privateKey, err := utils.ParsePrivateKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse signing key %s: %w", key.KeyID, err)
+		}
+		keys = append(keys, utils.SigningKey{
+			KeyID:      key.KeyID,
+			Algorithm:  key.Algorithm,
+			PrivateKey: privateKey,
+			RetiresAt:  key.RetiresAt,
+		})
+	}
+
+	return keys, nil
+}
+
+// EnsureKey creates a signing key when there is none, or when the configured algorithm changed
+func (s *SigningKeyService) EnsureKey(ctx context.Context) error {
+	stored, err := s.signingKeyRepo.ListUsable(ctx)
+	if err != nil {
+		return err
+	}
+
+	current := currentSigningKey(stored)
+	if current == nil || current.Algorithm != s.algorithm {
+		_, err = s.Rotate(ctx)
+	}
+	return err
+}
+
+// RotateIfDue rotates the signing key once it has signed for the rotation interval, and deletes
+// retired keys. It returns a summary for the job log.
+func (s *SigningKeyService) RotateIfDue(ctx context.Context, now time.Time) (string, error) {
+	stored, err := s.signingKeyRepo.ListUsable(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	summary := "signing key is current"
+	current := currentSigningKey(stored)
+	reason := ""
+	switch {
+	case current == nil:
+		reason = "no signing key"
+	case current.Algorithm != s.algorithm:
+		reason = "algorithm changed to " + s.algorithm
+	case s.rotationInterval > 0 && !now.Before(current.CreatedAt.Add(s.rotationInterval)):
+		reason = fmt.Sprintf("key %s signed for %d days", current.KeyID, int(now.Sub(current.CreatedAt).Hours()/24))
+	}
+	if reason != "" {
+		key, err := s.Rotate(ctx)
+		if err != nil {
+			return "", err
+		}
+		summary = fmt.Sprintf("signing key rotated to %s, %s", key.KeyID, reason)
+	}
+
+	deleted, err := s.signingKeyRepo.DeleteRetired(ctx, now)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s, %d retired keys deleted", summary, deleted), nil
+}
+
+// Rotate creates a new signing key. The key it replaces keeps validating tokens until they
+// have all expired.
+func (s *SigningKeyService) Rotate(ctx context.Context) (*user.SigningKey, error) {
+	privateKey, err := utils.GenerateSigningKey(s.algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	data, err := utils.MarshalPrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signing key: %w", err)
+	}
+	encrypted, err := utils.EncryptWithSecret(data, s.secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt signing key: %w", err)
+	}
+	keyID, err := utils.GenerateSecureToken(8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key ID: %w", err)
+	}
+
+	key := &user.SigningKey{
+		KeyID:      keyID,
+		Algorithm:  s.algorithm,
+		PrivateKey: encrypted,
+	}
+	if err := s.signingKeyRepo.Rotate(ctx, key, s.tokenLifetime+signingKeyRetireLeeway); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// currentSigningKey returns the key that signs new tokens, if any
+func currentSigningKey(keys []user.SigningKey) *user.SigningKey {
+	for i := range keys {
+		if keys[i].RotatedAt == nil {
+			return &keys[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
+)
+
+// totpSkew is how many time steps either side of now a code is accepted for, absorbing clock drift
+const totpSkew = 1
+
+// TwoFactorService handles TOTP enrollment, recovery codes and second factor checks
+type TwoFactorService struct {
+	twoFactorRepo interfaces.TwoFactorRepository
+	userRepo      interfaces.UserRepository
+	policy        user.TwoFactorPolicy
+	issuer        string
+}
+
+// NewTwoFactorService creates a new two-factor service. The issuer names the system in
+// authenticator apps.
+func NewTwoFactorService(
+	twoFactorRepo interfaces.TwoFactorRepository,
+	userRepo interfaces.UserRepository,
+	policy user.TwoFactorPolicy,
+	issuer string,
+) *TwoFactorService {
+	return &TwoFactorService{
+		twoFactorRepo: twoFactorRepo,
+		userRepo:      userRepo,
+		policy:        policy,
+		issuer:        issuer,
+	}
+}
+
+// Requires reports whether users of a role must use two-factor authentication
+func (s *TwoFactorService) Requires(role common.UserRole) bool {
+	return s.policy.Requires(role)
+}
+
+// IsEnabled reports whether a user has confirmed a TOTP enrollment
+func (s *TwoFactorService) IsEnabled(ctx context.Context, userID int) (bool, error) {
+	twoFactor, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if errors.Is(err, user.ErrTwoFactorNotEnrolled) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return twoFactor.IsEnabled(), nil
+}
+
+// GetStatus returns the two-factor state of a user
+func (s *TwoFactorService) GetStatus(ctx context.Context, userID int) (*user.TwoFactorStatus, error) {
+	foundUser, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	status := &user.TwoFactorStatus{Required: s.policy.Requires(foundUser.Role)}
+
+	twoFactor, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if errors.Is(err, user.ErrTwoFactorNotEnrolled) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !twoFactor.IsEnabled() {
+		return status, nil
+	}
+
+	codes, err := s.twoFactorRepo.ListUnusedRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status.Enabled = true
+	status.EnabledAt = twoFactor.EnabledAt
+	status.RecoveryCodesRemaining = len(codes)
+	return status, nil
+}
+
+// BeginEnrollment generates a new TOTP secret for a user. It stays pending until a code from
+// the authenticator app is confirmed.
+func (s *TwoFactorService) BeginEnrollment(ctx context.Context, userID int) (*user.TwoFactorEnrollment, error) {
+	foundUser, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepo.SavePending(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+
+	uri := utils.TOTPProvisioningURI(s.issuer, foundUser.Username, secret)
+	return &user.TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: uri,
+		QRPayload:       uri,
+	}, nil
+}
+
+// ConfirmEnrollment enables two-factor authentication once the user proves their authenticator
+// produces valid codes, returning the first set of recovery codes
+func (s *TwoFactorService) ConfirmEnrollment(ctx context.Context, userID int, code string) (*user.RecoveryCodesResponse, error) {
+	twoFactor, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor.IsEnabled() {
+		return nil, user.ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := utils.VerifyTOTP(twoFactor.Secret, code, time.Now(), totpSkew)
+	if !ok {
+		return nil, user.ErrTwoFactorCodeInvalid
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepo.Enable(ctx, userID, step, hashes); err != nil {
+		return nil, err
+	}
+
+	return &user.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// VerifyCode checks a TOTP code or an unused recovery code of a user with two-factor enabled.
+// Each TOTP code and each recovery code is accepted only once.
+func (s *TwoFactorService) VerifyCode(ctx context.Context, userID int, code string) error {
+	twoFactor, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !twoFactor.IsEnabled() {
+		return user.ErrTwoFactorNotEnrolled
+	}
+
+	if step, ok := utils.VerifyTOTP(twoFactor.Secret, code, time.Now(), totpSkew); ok {
+		fresh, err := s.twoFactorRepo.MarkStepUsed(ctx, userID, step)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return user.ErrTwoFactorCodeInvalid
+		}
+		return nil
+	}
+
+	normalized := user.NormalizeRecoveryCode(code)
+	codes, err := s.twoFactorRepo.ListUnusedRecoveryCodes(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, recoveryCode := range codes {
+		if !utils.CheckPassword(normalized, recoveryCode.CodeHash) {
+			continue
+		}
+		used, err := s.twoFactorRepo.UseRecoveryCode(ctx, recoveryCode.CodeID)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return user.ErrTwoFactorCodeInvalid
+		}
+		return nil
+	}
+
+	return user.ErrTwoFactorCodeInvalid
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of a user after checking a current code
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID int, code string) (*user.RecoveryCodesResponse, error) {
+	if err := s.VerifyCode(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return &user.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable turns off two-factor authentication after checking a current code. Users whose role
+// requires two-factor authentication cannot turn it off.
+func (s *TwoFactorService) Disable(ctx context.Context, userID int, code string) error {
+	foundUser, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("user not found")
+	}
+	if s.policy.Requires(foundUser.Role) {
+		return user.ErrTwoFactorRequiredByRole
+	}
+
+	if err := s.VerifyCode(ctx, userID, code); err != nil {
+		return err
+	}
+
+	return s.twoFactorRepo.Delete(ctx, userID)
+}
+
+// Reset removes the enrollment of a user who lost both their authenticator and recovery codes.
+// If their role requires two-factor authentication they enroll again at their next login.
+func (s *TwoFactorService) Reset(ctx context.Context, userID int) error {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return fmt.Errorf("user not found")
+	}
+
+	return s.twoFactorRepo.Delete(ctx, userID)
+}
+
+// generateRecoveryCodes returns a new set of recovery codes for display together with their hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, user.RecoveryCodeCount)
+	hashes := make([]string, 0, user.RecoveryCodeCount)
+	for i := 0; i < user.RecoveryCodeCount; i++ {
+		code, err := utils.GenerateSecureToken(5)
+		if err != nil {
+			return nil, nil, err
+		}
+		hash, err := utils.HashPassword(code)
+		if err != nil {
+			return nil, nil, err
+		}
+		codes = append(codes, user.FormatRecoveryCode(code))
+		hashes = append(hashes, hash)
+	}
+	return codes, hashes, nil
+}
@@ -15,13 +15,15 @@ import (
 type UserService struct {
 	userRepo    interfaces.UserRepository
 	sessionRepo interfaces.UserSessionRepository
+	passwords   *PasswordService
 }
 
 // NewUserService creates a new user service
-func NewUserService(userRepo interfaces.UserRepository, sessionRepo interfaces.UserSessionRepository) *UserService {
+func NewUserService(userRepo interfaces.UserRepository, sessionRepo interfaces.UserSessionRepository, passwords *PasswordService) *UserService {
 	return &UserService{
 		userRepo:    userRepo,
 		sessionRepo: sessionRepo,
+		passwords:   passwords,
 	}
 }
 
@@ -50,8 +52,8 @@ func (s *UserService) CreateUser(ctx context.Context, req *user.UserCreateReques
 		return nil, fmt.Errorf("email already exists")
 	}
 
-	// Validate password
-	if err := utils.IsValidPassword(req.Password); err != nil {
+	// Validate password against the password policy
+	if err := s.passwords.Validate(ctx, nil, req.Password); err != nil {
 		return nil, err
 	}
 
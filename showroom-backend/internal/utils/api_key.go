@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"strings"
+)
+
+// apiKeyTag starts every API key so leaked keys are easy to recognize, e.g. by secret scanners
+const apiKeyTag = "sk"
+
+// GenerateAPIKey generates an API key of the form sk_<prefix>_<secret>. The prefix identifies the
+// key without revealing it; the secret carries 256 bits of entropy.
+func GenerateAPIKey() (key, prefix string, err error) {
+	prefix, err = GenerateSecureToken(4)
+	if err != nil {
+		return "", "", err
+	}
+	secret, err := GenerateSecureToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	return apiKeyTag + "_" + prefix + "_" + secret, prefix, nil
+}
+
+// ParseAPIKeyPrefix returns the prefix of an API key, reporting false when the key is malformed
+func ParseAPIKeyPrefix(key string) (string, bool) {
+	parts := strings.Split(key, "_")
+	if len(parts) != 3 || parts[0] != apiKeyTag || len(parts[1]) != 8 || len(parts[2]) != 64 {
+		return "", false
+	}
+	return parts[1], true
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FixedWidthField is one column of a fixed-width file layout
+type FixedWidthField struct {
+	Name       string
+	Width      int
+	AlignRight bool
+	Pad        rune
+}
+
+// ParseFixedWidthLayout parses a layout of comma separated name:width[:left|right[:pad]] fields,
+// e.g. "bank_account:20,supplier_name:35,amount_cents:15:right:0". Fields are left aligned
+// and padded with spaces unless told otherwise.
+func ParseFixedWidthLayout(spec string) ([]FixedWidthField, error) {
+	var layout []FixedWidthField
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		options := strings.Split(part, ":")
+		if len(options) < 2 || len(options) > 4 || options[0] == "" {
+			return nil, fmt.Errorf("invalid fixed-width field %q, expected name:width[:left|right[:pad]]", part)
+		}
+
+		width, err := strconv.Atoi(options[1])
+		if err != nil || width < 1 {
+			return nil, fmt.Errorf("invalid width in fixed-width field %q", part)
+		}
+
+		field := FixedWidthField{Name: options[0], Width: width, Pad: ' '}
+		if len(options) > 2 {
+			switch options[2] {
+			case "left":
+			case "right":
+				field.AlignRight = true
+			default:
+				return nil, fmt.Errorf("invalid alignment in fixed-width field %q", part)
+			}
+		}
+		if len(options) > 3 {
+			if utf8.RuneCountInString(options[3]) != 1 {
+				return nil, fmt.Errorf("pad of fixed-width field %q must be a single character", part)
+			}
+			field.Pad, _ = utf8.DecodeRuneInString(options[3])
+		}
+
+		layout = append(layout, field)
+	}
+
+	if len(layout) == 0 {
+		return nil, fmt.Errorf("fixed-width layout has no fields")
+	}
+	return layout, nil
+}
+
+// WriteFixedWidth writes one CRLF terminated line per record laid out by the fields.
+// Left aligned values that are too long are truncated; right aligned values are usually
+// amounts or numbers, so a value too long for them is an error instead.
+func WriteFixedWidth(w io.Writer, layout []FixedWidthField, records []map[string]string) error {
+	for i, record := range records {
+		var line strings.Builder
+		for _, field := range layout {
+			value := []rune(record[field.Name])
+			if len(value) > field.Width {
+				if field.AlignRight {
+					return fmt.Errorf("record %d: value %q does not fit field %s of width %d",
+						i+1, string(value), field.Name, field.Width)
+				}
+				value = value[:field.Width]
+			}
+
+			padding := strings.Repeat(string(field.Pad), field.Width-len(value))
+			if field.AlignRight {
+				line.WriteString(padding)
+				line.WriteString(string(value))
+			} else {
+				line.WriteString(string(value))
+				line.WriteString(padding)
+			}
+		}
+		line.WriteString("\r\n")
+
+		if _, err := io.WriteString(w, line.String()); err != nil {
+			return fmt.Errorf("failed to write fixed-width record: %w", err)
+		}
+	}
+	return nil
+}
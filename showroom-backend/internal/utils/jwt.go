@@ -1,8 +1,12 @@
 package utils
 
 import (
+	"context"
+	"crypto"
 	"errors"
 	"fmt"
+	"log"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -15,13 +19,43 @@ var (
 	ErrExpiredToken = errors.New("token expired")
 )
 
-// JWTManager handles JWT token operations
+const (
+	// KeyReloadInterval is how long loaded signing keys are trusted before they are reloaded,
+	// so keys rotated or retired by another server are picked up
+	KeyReloadInterval = 5 * time.Minute
+	// unknownKeyReloadInterval limits reloads caused by tokens with an unknown key ID
+	unknownKeyReloadInterval = 30 * time.Second
+)
+
+// SigningKey is an asymmetric key the JWT manager signs or validates tokens with. Keys are
+// identified in the kid header of the tokens they sign.
+type SigningKey struct {
+	KeyID      string
+	Algorithm  string
+	PrivateKey crypto.Signer
+	RetiresAt  *time.Time
+}
+
+// KeySource provides the signing keys of a JWT manager. The first key signs new tokens; every
+// key validates tokens until it retires.
+type KeySource interface {
+	SigningKeys(ctx context.Context) ([]SigningKey, error)
+}
+
+// JWTManager handles JWT token operations. It signs either with a shared secret (HS256) or with
+// the asymmetric keys of a KeySource.
 type JWTManager struct {
 	secretKey string
 	duration  time.Duration
+	source    KeySource
+
+	mu       sync.RWMutex
+	keys     []SigningKey
+	loadedAt time.Time
+	reloadMu sync.Mutex
 }
 
-// NewJWTManager creates a new JWT manager
+// NewJWTManager creates a new JWT manager that signs with a shared secret
 func NewJWTManager(secretKey string, duration time.Duration) *JWTManager {
 	return &JWTManager{
 		secretKey: secretKey,
@@ -29,6 +63,131 @@ func NewJWTManager(secretKey string, duration time.Duration) *JWTManager {
 	}
 }
 
+// NewKeySourceJWTManager creates a new JWT manager that signs with the keys of a source. Keys
+// must be loaded with Reload before tokens are issued.
+func NewKeySourceJWTManager(source KeySource, duration time.Duration) *JWTManager {
+	return &JWTManager{
+		duration: duration,
+		source:   source,
+	}
+}
+
+// Reload loads the signing keys from the key source
+func (manager *JWTManager) Reload(ctx context.Context) error {
+	if manager.source == nil {
+		return nil
+	}
+
+	manager.reloadMu.Lock()
+	defer manager.reloadMu.Unlock()
+	return manager.reload(ctx)
+}
+
+func (manager *JWTManager) reload(ctx context.Context) error {
+	keys, err := manager.source.SigningKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load signing keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return errors.New("no signing key available")
+	}
+
+	manager.mu.Lock()
+	manager.keys = keys
+	manager.loadedAt = time.Now()
+	manager.mu.Unlock()
+	return nil
+}
+
+// reloadIfDue reloads the signing keys when they are stale, or when a token names an unknown
+// key and they have not been reloaded recently. Only one request reloads at a time; the others
+// carry on with the loaded keys.
+func (manager *JWTManager) reloadIfDue(unknownKey bool) {
+	if manager.source == nil {
+		return
+	}
+
+	manager.mu.RLock()
+	age := time.Since(manager.loadedAt)
+	manager.mu.RUnlock()
+	if age < KeyReloadInterval && !(unknownKey && age >= unknownKeyReloadInterval) {
+		return
+	}
+
+	if !manager.reloadMu.TryLock() {
+		return
+	}
+	defer manager.reloadMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := manager.reload(ctx); err != nil {
+		log.Printf("JWT manager: %v", err)
+	}
+}
+
+// findKey returns the loaded key with an ID that has not retired
+func (manager *JWTManager) findKey(keyID string) (SigningKey, bool) {
+	manager.mu.RLock()
+	defer manager.mu.RUnlock()
+	for _, key := range manager.keys {
+		if key.KeyID == keyID {
+			if key.RetiresAt != nil && !time.Now().Before(*key.RetiresAt) {
+				return SigningKey{}, false
+			}
+			return key, true
+		}
+	}
+	return SigningKey{}, false
+}
+
+// validationKey returns the key that verifies a token
+func (manager *JWTManager) validationKey(token *jwt.Token) (interface{}, error) {
+	if manager.source == nil {
+		// Validate signing method
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(manager.secretKey), nil
+	}
+
+	keyID, _ := token.Header["kid"].(string)
+	manager.reloadIfDue(false)
+	key, ok := manager.findKey(keyID)
+	if !ok {
+		manager.reloadIfDue(true)
+		if key, ok = manager.findKey(keyID); !ok {
+			return nil, fmt.Errorf("unknown signing key %q", keyID)
+		}
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.PrivateKey.Public(), nil
+}
+
+// JWKS returns the public keys that validate tokens, for other services to verify tokens
+// with. It is empty when tokens are signed with a shared secret.
+func (manager *JWTManager) JWKS() (*JWKSet, error) {
+	manager.reloadIfDue(false)
+
+	manager.mu.RLock()
+	defer manager.mu.RUnlock()
+	set := &JWKSet{Keys: []JWK{}}
+	now := time.Now()
+	for _, key := range manager.keys {
+		if key.RetiresAt != nil && !now.Before(*key.RetiresAt) {
+			continue
+		}
+		jwk, err := NewJWK(key.KeyID, key.Algorithm, key.PrivateKey.Public())
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
+
 // GenerateToken generates a new JWT token
 func (manager *JWTManager) GenerateToken(claims *auth.TokenClaims) (string, error) {
 	// Set standard claims
@@ -37,7 +196,24 @@ func (manager *JWTManager) GenerateToken(claims *auth.TokenClaims) (string, erro
 	claims.ExpiresAt = now.Add(manager.duration)
 
 	// Create token with claims
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	var method jwt.SigningMethod = jwt.SigningMethodHS256
+	var signingKey interface{} = []byte(manager.secretKey)
+	var keyID string
+	if manager.source != nil {
+		manager.mu.RLock()
+		if len(manager.keys) == 0 {
+			manager.mu.RUnlock()
+			return "", errors.New("failed to sign token: no signing key loaded")
+		}
+		current := manager.keys[0]
+		manager.mu.RUnlock()
+
+		method = jwt.GetSigningMethod(current.Algorithm)
+		signingKey = current.PrivateKey
+		keyID = current.KeyID
+	}
+
+	token := jwt.NewWithClaims(method, jwt.MapClaims{
 		"user_id":    claims.UserID,
 		"username":   claims.Username,
 		"email":      claims.Email,
@@ -47,8 +223,12 @@ func (manager *JWTManager) GenerateToken(claims *auth.TokenClaims) (string, erro
 		"exp":        claims.ExpiresAt.Unix(),
 	})
 
-	// Sign token with secret
-	tokenString, err := token.SignedString([]byte(manager.secretKey))
+	if keyID != "" {
+		token.Header["kid"] = keyID
+	}
+
+	// Sign token
+	tokenString, err := token.SignedString(signingKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
@@ -59,13 +239,7 @@ func (manager *JWTManager) GenerateToken(claims *auth.TokenClaims) (string, erro
 // ValidateToken validates and parses a JWT token
 func (manager *JWTManager) ValidateToken(tokenString string) (*auth.TokenClaims, error) {
 	// Parse token
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// Validate signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(manager.secretKey), nil
-	})
+	token, err := jwt.Parse(tokenString, manager.validationKey)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
@@ -123,54 +297,6 @@ func (manager *JWTManager) ValidateToken(tokenString string) (*auth.TokenClaims,
 	return tokenClaims, nil
 }
 
-// RefreshToken generates a new token with updated expiration
-func (manager *JWTManager) RefreshToken(oldTokenString string) (string, *auth.TokenClaims, error) {
-	// Validate old token (but allow expired tokens for refresh)
-	token, err := jwt.Parse(oldTokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(manager.secretKey), nil
-	})
-
-	if err != nil {
-		// For refresh, we allow expired tokens
-		return "", nil, fmt.Errorf("failed to parse token: %w", err)
-	}
-
-	// Extract claims
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return "", nil, ErrInvalidToken
-	}
-
-	// Create new claims
-	newClaims := &auth.TokenClaims{}
-	if userID, ok := claims["user_id"].(float64); ok {
-		newClaims.UserID = int(userID)
-	}
-	if username, ok := claims["username"].(string); ok {
-		newClaims.Username = username
-	}
-	if email, ok := claims["email"].(string); ok {
-		newClaims.Email = email
-	}
-	if role, ok := claims["role"].(string); ok {
-		newClaims.Role = common.UserRole(role)
-	}
-	if sessionID, ok := claims["session_id"].(float64); ok {
-		newClaims.SessionID = int(sessionID)
-	}
-
-	// Generate new token
-	newToken, err := manager.GenerateToken(newClaims)
-	if err != nil {
-		return "", nil, fmt.Errorf("failed to generate new token: %w", err)
-	}
-
-	return newToken, newClaims, nil
-}
-
 // GetExpirationDuration returns the token expiration duration
 func (manager *JWTManager) GetExpirationDuration() time.Duration {
 	return manager.duration
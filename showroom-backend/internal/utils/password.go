@@ -2,6 +2,7 @@ package utils
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 
@@ -37,6 +38,13 @@ func GenerateSessionToken() (string, error) {
 	return GenerateSecureToken(32) // 64 character hex string
 }
 
+// HashToken returns the SHA-256 hex digest of an opaque token. Tokens are random with full
+// entropy, so a fast unsalted hash is enough to keep them useless if the table leaks.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 // IsValidPassword checks if password meets security requirements
 func IsValidPassword(password string) error {
 	if len(password) < 6 {
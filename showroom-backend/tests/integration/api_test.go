@@ -13,6 +13,7 @@ import (
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/handlers/admin"
 	authHandlers "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/handlers/auth"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/handlers/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/routes"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services"
@@ -48,8 +49,9 @@ func setupTestRouter() http.Handler {
 			Version: "1.0.0",
 		},
 		JWT: config.JWTConfig{
-			SecretKey:      "test-secret-key",
-			ExpirationHour: 24,
+			SecretKey:          "test-secret-key",
+			ExpirationHour:     24,
+			AccessTokenMinutes: 15,
 		},
 		Server: config.ServerConfig{
 			Env: "test",
@@ -62,13 +64,22 @@ func setupTestRouter() http.Handler {
 	// Mock repositories for testing (nil for basic endpoint tests)
 	var userRepo interfaces.UserRepository
 	var sessionRepo interfaces.UserSessionRepository
+	var refreshTokenRepo interfaces.RefreshTokenRepository
+	var loginChallengeRepo interfaces.LoginChallengeRepository
+	var twoFactorRepo interfaces.TwoFactorRepository
+	var loginAttemptRepo interfaces.LoginAttemptRepository
 
 	// Initialize JWT manager
-	jwtManager := utils.NewJWTManager(cfg.JWT.SecretKey, cfg.JWT.GetExpiration())
+	jwtManager := utils.NewJWTManager(cfg.JWT.SecretKey, cfg.JWT.GetAccessTokenExpiration())
 
 	// Initialize services
-	authService := services.NewAuthService(userRepo, sessionRepo, jwtManager)
-	userService := services.NewUserService(userRepo, sessionRepo)
+	twoFactorService := services.NewTwoFactorService(twoFactorRepo, userRepo, user.TwoFactorPolicy{}, cfg.Auth.TwoFactorIssuer)
+	loginAttemptService := services.NewLoginAttemptService(loginAttemptRepo, userRepo, user.LoginThrottlePolicy{})
+	passwordService := services.NewPasswordService(userRepo, sessionRepo, utils.PasswordPolicy{})
+	sessionService := services.NewSessionService(sessionRepo, user.SessionPolicy{})
+	authService := services.NewAuthService(userRepo, sessionRepo, refreshTokenRepo, loginChallengeRepo, twoFactorService, loginAttemptService, passwordService, sessionService, jwtManager, cfg.JWT.GetExpiration())
+	userService := services.NewUserService(userRepo, sessionRepo, passwordService)
+	permissionService := services.NewPermissionService(nil)
 
 	// Initialize handlers
 	authHandler := authHandlers.NewHandler(authService)
@@ -87,7 +98,29 @@ func setupTestRouter() http.Handler {
 	goodsReceiptHandler := (*products.GoodsReceiptHandler)(nil)
 	stockMovementHandler := (*products.StockMovementHandler)(nil)
 	stockAdjustmentHandler := (*products.StockAdjustmentHandler)(nil)
-	supplierPaymentHandler := (*products.SupplierPaymentHandler)(nil)
+	supplierInvoiceHandler := (*products.SupplierInvoiceHandler)(nil)
+	paymentVoucherHandler := (*products.PaymentVoucherHandler)(nil)
+	purchaseReturnHandler := (*products.PurchaseReturnHandler)(nil)
+	landedCostHandler := (*products.LandedCostHandler)(nil)
+	supplierPriceListHandler := (*products.SupplierPriceListHandler)(nil)
+	supplierScorecardHandler := (*products.SupplierScorecardHandler)(nil)
+	paymentTermHandler := (*products.PaymentTermHandler)(nil)
+	jobHandler := (*admin.JobHandler)(nil)
+	paymentRunHandler := (*products.PaymentRunHandler)(nil)
+	bankStatementHandler := (*products.BankStatementHandler)(nil)
+	generalLedgerHandler := (*products.GeneralLedgerHandler)(nil)
+	taxHandler := (*products.TaxHandler)(nil)
+	salesInvoiceHandler := (*products.SalesInvoiceHandler)(nil)
+	exchangeRateHandler := (*products.ExchangeRateHandler)(nil)
+	customerAccountHandler := (*products.CustomerAccountHandler)(nil)
+	roleHandler := (*admin.RoleHandler)(nil)
+	twoFactorHandler := (*authHandlers.TwoFactorHandler)(nil)
+	loginAttemptHandler := (*admin.LoginAttemptHandler)(nil)
+	passwordResetHandler := (*authHandlers.PasswordResetHandler)(nil)
+	passwordPolicyHandler := (*authHandlers.PasswordPolicyHandler)(nil)
+	apiKeyHandler := (*admin.APIKeyHandler)(nil)
+	auditHandler := (*admin.AuditHandler)(nil)
+	sessionHandler := authHandlers.NewSessionHandler(sessionService)
 
 	// Initialize router
 	router := routes.NewRouter(
@@ -103,7 +136,31 @@ func setupTestRouter() http.Handler {
 		goodsReceiptHandler,
 		stockMovementHandler,
 		stockAdjustmentHandler,
-		supplierPaymentHandler,
+		supplierInvoiceHandler,
+		paymentVoucherHandler,
+		purchaseReturnHandler,
+		landedCostHandler,
+		supplierPriceListHandler,
+		supplierScorecardHandler,
+		paymentTermHandler,
+		jobHandler,
+		paymentRunHandler,
+		bankStatementHandler,
+		generalLedgerHandler,
+		taxHandler,
+		salesInvoiceHandler,
+		exchangeRateHandler,
+		customerAccountHandler,
+		roleHandler,
+		twoFactorHandler,
+		loginAttemptHandler,
+		passwordResetHandler,
+		passwordPolicyHandler,
+		apiKeyHandler,
+		auditHandler,
+		sessionHandler,
+		permissionService,
+		nil,
 		jwtManager, 
 		sessionRepo, 
 		cfg,
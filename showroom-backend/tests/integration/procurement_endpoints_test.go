@@ -0,0 +1,76 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProcurementEndpointsAccessibility tests that the procurement and payables endpoints are registered.
+// All endpoints require authentication, so we expect 401 responses
+func TestProcurementEndpointsAccessibility(t *testing.T) {
+	router := setupTestRouter()
+
+	endpoints := []struct {
+		method   string
+		path     string
+		category string
+	}{
+		// Blanket Order Releases
+		{"POST", "/api/v1/admin/purchase-orders/1/releases", "Blanket Orders"},
+		{"GET", "/api/v1/admin/purchase-orders/1/blanket-balance", "Blanket Orders"},
+
+		// Purchase Returns
+		{"POST", "/api/v1/admin/purchase-returns", "Purchase Returns"},
+		{"GET", "/api/v1/admin/purchase-returns", "Purchase Returns"},
+		{"GET", "/api/v1/admin/purchase-returns/1", "Purchase Returns"},
+		{"GET", "/api/v1/admin/purchase-returns/1/details", "Purchase Returns"},
+		{"POST", "/api/v1/admin/purchase-returns/1/approve", "Purchase Returns"},
+		{"PUT", "/api/v1/admin/purchase-returns/1/shipment", "Purchase Returns"},
+		{"POST", "/api/v1/admin/purchase-returns/1/cancel", "Purchase Returns"},
+		{"POST", "/api/v1/admin/goods-receipts/1/return-rejected", "Purchase Returns"},
+
+		// Supplier Debit Notes
+		{"GET", "/api/v1/admin/supplier-debit-notes", "Supplier Debit Notes"},
+		{"GET", "/api/v1/admin/supplier-debit-notes/1", "Supplier Debit Notes"},
+		{"GET", "/api/v1/admin/supplier-debit-notes/1/applications", "Supplier Debit Notes"},
+		{"POST", "/api/v1/admin/supplier-debit-notes/1/apply", "Supplier Debit Notes"},
+
+		// Landed Costs
+		{"POST", "/api/v1/admin/landed-costs", "Landed Costs"},
+		{"GET", "/api/v1/admin/landed-costs", "Landed Costs"},
+		{"GET", "/api/v1/admin/landed-costs/1", "Landed Costs"},
+		{"GET", "/api/v1/admin/landed-costs/1/allocations", "Landed Costs"},
+		{"POST", "/api/v1/admin/landed-costs/1/post", "Landed Costs"},
+		{"POST", "/api/v1/admin/landed-costs/1/cancel", "Landed Costs"},
+
+		// Supplier Price Lists
+		{"POST", "/api/v1/admin/supplier-price-lists", "Supplier Price Lists"},
+		{"GET", "/api/v1/admin/supplier-price-lists", "Supplier Price Lists"},
+		{"GET", "/api/v1/admin/supplier-price-lists/lookup", "Supplier Price Lists"},
+		{"GET", "/api/v1/admin/supplier-price-lists/history", "Supplier Price Lists"},
+		{"GET", "/api/v1/admin/supplier-price-lists/1", "Supplier Price Lists"},
+		{"PUT", "/api/v1/admin/supplier-price-lists/1", "Supplier Price Lists"},
+		{"DELETE", "/api/v1/admin/supplier-price-lists/1", "Supplier Price Lists"},
+		{"POST", "/api/v1/admin/purchase-orders/1/bulk-details", "Supplier Price Lists"},
+
+		// Supplier Scorecards
+		{"GET", "/api/v1/admin/suppliers/1/scorecard", "Supplier Scorecards"},
+		{"GET", "/api/v1/admin/suppliers/scorecards/compare", "Supplier Scorecards"},
+	}
+
+	for _, endpoint := range endpoints {
+		t.Run(fmt.Sprintf("%s_%s", endpoint.method, endpoint.path), func(t *testing.T) {
+			req, _ := http.NewRequest(endpoint.method, endpoint.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code,
+				"Endpoint %s %s should return 401 (auth required), not 404 (not found)",
+				endpoint.method, endpoint.path)
+		})
+	}
+}
@@ -62,18 +62,138 @@ func TestStockManagementEndpointsAccessibility(t *testing.T) {
 		{"POST", "/api/v1/admin/stock-adjustments/bulk-approve", "Stock Adjustments"},
 		{"GET", "/api/v1/admin/products/1/adjustments", "Stock Adjustments"},
 		
-		// Supplier Payments (11 endpoints)
+		// Supplier Invoices (14 endpoints)
+		{"POST", "/api/v1/admin/supplier-invoices", "Supplier Invoices"},
+		{"GET", "/api/v1/admin/supplier-invoices", "Supplier Invoices"},
+		{"GET", "/api/v1/admin/supplier-invoices/1", "Supplier Invoices"},
+		{"PUT", "/api/v1/admin/supplier-invoices/1", "Supplier Invoices"},
+		{"DELETE", "/api/v1/admin/supplier-invoices/1", "Supplier Invoices"},
+		{"PUT", "/api/v1/admin/supplier-invoices/1/status", "Supplier Invoices"},
+		{"GET", "/api/v1/admin/supplier-invoices/1/allocations", "Supplier Invoices"},
+		{"GET", "/api/v1/admin/supplier-invoices/overdue", "Supplier Invoices"},
+		{"GET", "/api/v1/admin/supplier-invoices/summary", "Supplier Invoices"},
+		{"POST", "/api/v1/admin/supplier-invoices/update-overdue", "Supplier Invoices"},
+		{"POST", "/api/v1/admin/supplier-invoices/calculate-terms", "Supplier Invoices"},
+		{"GET", "/api/v1/admin/supplier-invoices/aging", "Supplier Invoices"},
+		{"GET", "/api/v1/admin/supplier-invoices/aging/suppliers/1", "Supplier Invoices"},
+		{"GET", "/api/v1/admin/supplier-invoices/discount-opportunities", "Supplier Invoices"},
+
+		// Supplier Payments (6 endpoints)
 		{"POST", "/api/v1/admin/supplier-payments", "Supplier Payments"},
 		{"GET", "/api/v1/admin/supplier-payments", "Supplier Payments"},
 		{"GET", "/api/v1/admin/supplier-payments/1", "Supplier Payments"},
 		{"PUT", "/api/v1/admin/supplier-payments/1", "Supplier Payments"},
-		{"DELETE", "/api/v1/admin/supplier-payments/1", "Supplier Payments"},
-		{"POST", "/api/v1/admin/supplier-payments/1/process", "Supplier Payments"},
-		{"PUT", "/api/v1/admin/supplier-payments/1/status", "Supplier Payments"},
-		{"GET", "/api/v1/admin/supplier-payments/overdue", "Supplier Payments"},
-		{"GET", "/api/v1/admin/supplier-payments/summary", "Supplier Payments"},
-		{"POST", "/api/v1/admin/supplier-payments/update-overdue", "Supplier Payments"},
-		{"POST", "/api/v1/admin/supplier-payments/calculate-terms", "Supplier Payments"},
+		{"POST", "/api/v1/admin/supplier-payments/1/allocate", "Supplier Payments"},
+		{"POST", "/api/v1/admin/supplier-payments/1/void", "Supplier Payments"},
+
+		// Payment Runs (9 endpoints)
+		{"POST", "/api/v1/admin/payment-runs", "Payment Runs"},
+		{"GET", "/api/v1/admin/payment-runs", "Payment Runs"},
+		{"GET", "/api/v1/admin/payment-runs/1", "Payment Runs"},
+		{"DELETE", "/api/v1/admin/payment-runs/1/lines/1", "Payment Runs"},
+		{"POST", "/api/v1/admin/payment-runs/1/approve", "Payment Runs"},
+		{"POST", "/api/v1/admin/payment-runs/1/cancel", "Payment Runs"},
+		{"GET", "/api/v1/admin/payment-runs/1/bank-file", "Payment Runs"},
+		{"POST", "/api/v1/admin/payment-runs/1/execute", "Payment Runs"},
+		{"POST", "/api/v1/admin/payment-runs/1/lines/1/fail", "Payment Runs"},
+
+		// Bank Statements (8 endpoints)
+		{"POST", "/api/v1/admin/bank-statements/import", "Bank Statements"},
+		{"GET", "/api/v1/admin/bank-statements", "Bank Statements"},
+		{"GET", "/api/v1/admin/bank-statements/reconciliation", "Bank Statements"},
+		{"GET", "/api/v1/admin/bank-statements/1", "Bank Statements"},
+		{"DELETE", "/api/v1/admin/bank-statements/1", "Bank Statements"},
+		{"POST", "/api/v1/admin/bank-statements/1/auto-match", "Bank Statements"},
+		{"POST", "/api/v1/admin/bank-statements/lines/1/match", "Bank Statements"},
+		{"POST", "/api/v1/admin/bank-statements/lines/1/unmatch", "Bank Statements"},
+
+		// General Ledger (14 endpoints)
+		{"POST", "/api/v1/admin/gl/accounts", "General Ledger"},
+		{"GET", "/api/v1/admin/gl/accounts", "General Ledger"},
+		{"GET", "/api/v1/admin/gl/accounts/1", "General Ledger"},
+		{"PUT", "/api/v1/admin/gl/accounts/1", "General Ledger"},
+		{"GET", "/api/v1/admin/gl/posting-rules", "General Ledger"},
+		{"PUT", "/api/v1/admin/gl/posting-rules/inventory", "General Ledger"},
+		{"POST", "/api/v1/admin/gl/journals", "General Ledger"},
+		{"GET", "/api/v1/admin/gl/journals", "General Ledger"},
+		{"GET", "/api/v1/admin/gl/journals/1", "General Ledger"},
+		{"POST", "/api/v1/admin/gl/journals/1/reverse", "General Ledger"},
+		{"GET", "/api/v1/admin/gl/trial-balance", "General Ledger"},
+		{"GET", "/api/v1/admin/gl/periods", "General Ledger"},
+		{"POST", "/api/v1/admin/gl/periods/close", "General Ledger"},
+		{"POST", "/api/v1/admin/gl/periods/reopen", "General Ledger"},
+
+		// Tax (6 endpoints)
+		{"POST", "/api/v1/admin/tax/codes", "Tax"},
+		{"GET", "/api/v1/admin/tax/codes", "Tax"},
+		{"GET", "/api/v1/admin/tax/codes/1", "Tax"},
+		{"PUT", "/api/v1/admin/tax/codes/1", "Tax"},
+		{"GET", "/api/v1/admin/tax/vat-report", "Tax"},
+		{"GET", "/api/v1/admin/tax/efaktur-export", "Tax"},
+
+		// Sales Invoices (5 endpoints)
+		{"POST", "/api/v1/admin/sales-invoices", "Sales Invoices"},
+		{"GET", "/api/v1/admin/sales-invoices", "Sales Invoices"},
+		{"GET", "/api/v1/admin/sales-invoices/1", "Sales Invoices"},
+		{"PUT", "/api/v1/admin/sales-invoices/1/tax-invoice-number", "Sales Invoices"},
+		{"POST", "/api/v1/admin/sales-invoices/1/void", "Sales Invoices"},
+
+		// Exchange Rates (4 endpoints)
+		{"POST", "/api/v1/admin/exchange-rates", "Exchange Rates"},
+		{"GET", "/api/v1/admin/exchange-rates", "Exchange Rates"},
+		{"GET", "/api/v1/admin/exchange-rates/lookup", "Exchange Rates"},
+		{"POST", "/api/v1/admin/exchange-rates/import", "Exchange Rates"},
+
+		// Customer Receivables (7 endpoints)
+		{"POST", "/api/v1/admin/customer-receipts", "Customer Receivables"},
+		{"GET", "/api/v1/admin/customer-receipts", "Customer Receivables"},
+		{"GET", "/api/v1/admin/customer-receipts/1", "Customer Receivables"},
+		{"POST", "/api/v1/admin/customer-receipts/1/allocate", "Customer Receivables"},
+		{"POST", "/api/v1/admin/customer-receipts/1/void", "Customer Receivables"},
+		{"GET", "/api/v1/admin/customer-accounts/1", "Customer Receivables"},
+		{"GET", "/api/v1/admin/customer-accounts/1/statement", "Customer Receivables"},
+
+		// Roles and Permissions (5 endpoints)
+		{"GET", "/api/v1/admin/permissions", "Roles and Permissions"},
+		{"GET", "/api/v1/admin/roles", "Roles and Permissions"},
+		{"GET", "/api/v1/admin/roles/manager", "Roles and Permissions"},
+		{"PUT", "/api/v1/admin/roles/manager/permissions", "Roles and Permissions"},
+		{"GET", "/api/v1/auth/permissions", "Roles and Permissions"},
+
+		// Two-Factor Authentication (6 endpoints)
+		{"GET", "/api/v1/auth/2fa", "Two-Factor Authentication"},
+		{"POST", "/api/v1/auth/2fa/enroll", "Two-Factor Authentication"},
+		{"POST", "/api/v1/auth/2fa/confirm", "Two-Factor Authentication"},
+		{"POST", "/api/v1/auth/2fa/disable", "Two-Factor Authentication"},
+		{"POST", "/api/v1/auth/2fa/recovery-codes", "Two-Factor Authentication"},
+		{"DELETE", "/api/v1/admin/users/1/2fa", "Two-Factor Authentication"},
+
+		// Sessions (2 endpoints)
+		{"GET", "/api/v1/auth/sessions", "Sessions"},
+		{"DELETE", "/api/v1/auth/sessions/1", "Sessions"},
+
+		// Login Security (4 endpoints)
+		{"GET", "/api/v1/admin/login-attempts", "Login Security"},
+		{"GET", "/api/v1/admin/users/1/login-lock", "Login Security"},
+		{"POST", "/api/v1/admin/users/1/unlock", "Login Security"},
+		{"POST", "/api/v1/admin/users/1/force-password-change", "Login Security"},
+
+		// API Keys (4 endpoints)
+		{"POST", "/api/v1/admin/api-keys", "API Keys"},
+		{"GET", "/api/v1/admin/api-keys", "API Keys"},
+		{"GET", "/api/v1/admin/api-keys/1", "API Keys"},
+		{"DELETE", "/api/v1/admin/api-keys/1", "API Keys"},
+
+		// Audit Log (2 endpoints)
+		{"GET", "/api/v1/admin/audit-logs", "Audit Log"},
+		{"GET", "/api/v1/admin/audit-logs/1", "Audit Log"},
+
+		// Payment Terms (5 endpoints)
+		{"POST", "/api/v1/admin/payment-terms", "Payment Terms"},
+		{"GET", "/api/v1/admin/payment-terms", "Payment Terms"},
+		{"GET", "/api/v1/admin/payment-terms/1", "Payment Terms"},
+		{"PUT", "/api/v1/admin/payment-terms/1", "Payment Terms"},
+		{"DELETE", "/api/v1/admin/payment-terms/1", "Payment Terms"},
 	}
 
 	// Test each endpoint returns 401 (auth required) instead of 404 (not found)
@@ -113,7 +233,7 @@ func TestEndpointDocumentation(t *testing.T) {
 	t.Run("Stock Management API Documentation", func(t *testing.T) {
 		fmt.Println("\n=== STOCK MANAGEMENT API ENDPOINTS DOCUMENTATION ===")
 		fmt.Println("\nTotal Endpoints Implemented: 35")
-		fmt.Println("All endpoints require admin authentication and role")
+		fmt.Println("All endpoints require authentication and a permission granted to the user's role")
 		fmt.Println("Base URL: /api/v1/admin")
 		
 		fmt.Println("\n1. PURCHASE ORDER DETAILS (8 endpoints)")
@@ -158,18 +278,146 @@ func TestEndpointDocumentation(t *testing.T) {
 		fmt.Println("   POST   /stock-adjustments/bulk-approve            # Bulk approve adjustments")
 		fmt.Println("   GET    /products/:id/adjustments                  # Product adjustment history")
 		
-		fmt.Println("\n5. SUPPLIER PAYMENTS (11 endpoints)")
-		fmt.Println("   POST   /supplier-payments                         # Create payment")
-		fmt.Println("   GET    /supplier-payments                         # List payments with filters")
-		fmt.Println("   GET    /supplier-payments/:id                     # Get specific payment")
-		fmt.Println("   PUT    /supplier-payments/:id                     # Update payment")
-		fmt.Println("   DELETE /supplier-payments/:id                     # Delete payment")
-		fmt.Println("   POST   /supplier-payments/:id/process             # Process payment")
-		fmt.Println("   PUT    /supplier-payments/:id/status              # Update payment status")
-		fmt.Println("   GET    /supplier-payments/overdue                 # Get overdue payments")
-		fmt.Println("   GET    /supplier-payments/summary                 # Payment summary/analytics")
-		fmt.Println("   POST   /supplier-payments/update-overdue          # Update overdue status")
-		fmt.Println("   POST   /supplier-payments/calculate-terms         # Calculate payment terms")
+		fmt.Println("\n5. SUPPLIER INVOICES (14 endpoints)")
+		fmt.Println("   POST   /supplier-invoices                         # Record supplier invoice")
+		fmt.Println("   GET    /supplier-invoices                         # List invoices with filters")
+		fmt.Println("   GET    /supplier-invoices/:id                     # Get invoice with installments")
+		fmt.Println("   PUT    /supplier-invoices/:id                     # Update invoice")
+		fmt.Println("   DELETE /supplier-invoices/:id                     # Delete unpaid invoice")
+		fmt.Println("   PUT    /supplier-invoices/:id/status              # Dispute or resolve invoice")
+		fmt.Println("   GET    /supplier-invoices/:id/allocations         # Payment installments")
+		fmt.Println("   GET    /supplier-invoices/overdue                 # Get overdue invoices")
+		fmt.Println("   GET    /supplier-invoices/summary                 # Invoice summary/analytics")
+		fmt.Println("   POST   /supplier-invoices/update-overdue          # Update overdue status")
+		fmt.Println("   POST   /supplier-invoices/calculate-terms         # Calculate payment terms")
+		fmt.Println("   GET    /supplier-invoices/aging                   # AP aging report (JSON/CSV/XLSX)")
+		fmt.Println("   GET    /supplier-invoices/aging/suppliers/:id     # Supplier aging drilldown")
+		fmt.Println("   GET    /supplier-invoices/discount-opportunities  # Pay now to capture discounts")
+
+		fmt.Println("\n6. SUPPLIER PAYMENTS (6 endpoints)")
+		fmt.Println("   POST   /supplier-payments                         # Create payment voucher")
+		fmt.Println("   GET    /supplier-payments                         # List payment vouchers")
+		fmt.Println("   GET    /supplier-payments/:id                     # Get voucher with allocations")
+		fmt.Println("   PUT    /supplier-payments/:id                     # Update payment details")
+		fmt.Println("   POST   /supplier-payments/:id/allocate            # Allocate to invoices")
+		fmt.Println("   POST   /supplier-payments/:id/void                # Void payment voucher")
+
+		fmt.Println("\n7. PAYMENT TERMS (5 endpoints)")
+		fmt.Println("   POST   /payment-terms                             # Create terms (e.g. 2/10 net 30)")
+		fmt.Println("   GET    /payment-terms                             # List payment terms")
+		fmt.Println("   GET    /payment-terms/:id                         # Get payment terms")
+		fmt.Println("   PUT    /payment-terms/:id                         # Update or deactivate terms")
+		fmt.Println("   DELETE /payment-terms/:id                         # Delete unused terms")
+
+		fmt.Println("\n8. PAYMENT RUNS (9 endpoints)")
+		fmt.Println("   POST   /payment-runs                              # Select due invoices into a draft run")
+		fmt.Println("   GET    /payment-runs                              # List payment runs")
+		fmt.Println("   GET    /payment-runs/:id                          # Get run with lines")
+		fmt.Println("   DELETE /payment-runs/:id/lines/:lineId            # Remove invoice during review")
+		fmt.Println("   POST   /payment-runs/:id/approve                  # Approve run")
+		fmt.Println("   POST   /payment-runs/:id/cancel                   # Cancel run")
+		fmt.Println("   GET    /payment-runs/:id/bank-file                # Bank file (CSV/fixed-width)")
+		fmt.Println("   POST   /payment-runs/:id/execute                  # Post all payments")
+		fmt.Println("   POST   /payment-runs/:id/lines/:lineId/fail       # Reopen a rejected transfer")
+
+		fmt.Println("\n9. BANK STATEMENTS (8 endpoints)")
+		fmt.Println("   POST   /bank-statements/import                    # Import CSV with column mapping")
+		fmt.Println("   GET    /bank-statements                           # List imported statements")
+		fmt.Println("   GET    /bank-statements/reconciliation            # Summary per bank account")
+		fmt.Println("   GET    /bank-statements/:id                       # Get statement with lines")
+		fmt.Println("   DELETE /bank-statements/:id                       # Remove unmatched statement")
+		fmt.Println("   POST   /bank-statements/:id/auto-match            # Match lines to payments")
+		fmt.Println("   POST   /bank-statements/lines/:lineId/match       # Match line manually")
+		fmt.Println("   POST   /bank-statements/lines/:lineId/unmatch     # Clear a match")
+		
+		fmt.Println("\n10. GENERAL LEDGER (14 endpoints)")
+		fmt.Println("   POST   /gl/accounts                               # Add account to chart of accounts")
+		fmt.Println("   GET    /gl/accounts                               # List chart of accounts")
+		fmt.Println("   GET    /gl/accounts/:id                           # Get account")
+		fmt.Println("   PUT    /gl/accounts/:id                           # Update account")
+		fmt.Println("   GET    /gl/posting-rules                          # Accounts used by automatic postings")
+		fmt.Println("   PUT    /gl/posting-rules/:rule                    # Remap a posting rule")
+		fmt.Println("   POST   /gl/journals                               # Post manual journal entry")
+		fmt.Println("   GET    /gl/journals                               # List journal entries")
+		fmt.Println("   GET    /gl/journals/:id                           # Get journal entry with lines")
+		fmt.Println("   POST   /gl/journals/:id/reverse                   # Reverse journal entry")
+		fmt.Println("   GET    /gl/trial-balance                          # Trial balance for a date range")
+		fmt.Println("   GET    /gl/periods                                # Accounting periods of a year")
+		fmt.Println("   POST   /gl/periods/close                          # Close a month")
+		fmt.Println("   POST   /gl/periods/reopen                         # Reopen a month")
+
+		fmt.Println("\n11. TAX (6 endpoints)")
+		fmt.Println("   POST   /tax/codes                                 # Create tax code")
+		fmt.Println("   GET    /tax/codes                                 # List tax codes")
+		fmt.Println("   GET    /tax/codes/:id                             # Get tax code")
+		fmt.Println("   PUT    /tax/codes/:id                             # Update tax code")
+		fmt.Println("   GET    /tax/vat-report                            # Input and output VAT of a month")
+		fmt.Println("   GET    /tax/efaktur-export                        # e-Faktur CSV of output tax invoices")
+
+		fmt.Println("\n12. SALES INVOICES (5 endpoints)")
+		fmt.Println("   POST   /sales-invoices                            # Issue sales invoice")
+		fmt.Println("   GET    /sales-invoices                            # List sales invoices")
+		fmt.Println("   GET    /sales-invoices/:id                        # Get sales invoice with lines")
+		fmt.Println("   PUT    /sales-invoices/:id/tax-invoice-number     # Record tax invoice number")
+		fmt.Println("   POST   /sales-invoices/:id/void                   # Void sales invoice")
+
+		fmt.Println("\n13. EXCHANGE RATES (4 endpoints)")
+		fmt.Println("   POST   /exchange-rates                            # Enter rate of a currency on a date")
+		fmt.Println("   GET    /exchange-rates                            # List dated rates")
+		fmt.Println("   GET    /exchange-rates/lookup                     # Rate in effect on a date")
+		fmt.Println("   POST   /exchange-rates/import                     # Import rates from CSV")
+		
+		fmt.Println("\n14. CUSTOMER RECEIVABLES (7 endpoints)")
+		fmt.Println("   POST   /customer-receipts                         # Record receipt (manual or auto allocation)")
+		fmt.Println("   GET    /customer-receipts                         # List receipts")
+		fmt.Println("   GET    /customer-receipts/:id                     # Get receipt with allocations")
+		fmt.Println("   POST   /customer-receipts/:id/allocate            # Allocate unallocated amount")
+		fmt.Println("   POST   /customer-receipts/:id/void                # Void receipt")
+		fmt.Println("   GET    /customer-accounts/:id                     # Credit limit, outstanding and aging")
+		fmt.Println("   GET    /customer-accounts/:id/statement           # Statement of account (?format=pdf)")
+		
+		fmt.Println("\n15. ROLES AND PERMISSIONS (5 endpoints)")
+		fmt.Println("   GET    /permissions                               # Permissions that can be granted")
+		fmt.Println("   GET    /roles                                     # Roles with their permissions")
+		fmt.Println("   GET    /roles/:role                               # Permissions of a role")
+		fmt.Println("   PUT    /roles/:role/permissions                   # Replace permissions of a role")
+		fmt.Println("   GET    /api/v1/auth/permissions                   # Permissions of the current user")
+		
+		fmt.Println("\n16. TWO-FACTOR AUTHENTICATION (6 endpoints)")
+		fmt.Println("   GET    /api/v1/auth/2fa                           # Two-factor status of the current user")
+		fmt.Println("   POST   /api/v1/auth/2fa/enroll                    # New TOTP secret and QR payload")
+		fmt.Println("   POST   /api/v1/auth/2fa/confirm                   # Enable with a code, get recovery codes")
+		fmt.Println("   POST   /api/v1/auth/2fa/disable                   # Disable with a code")
+		fmt.Println("   POST   /api/v1/auth/2fa/recovery-codes            # Replace recovery codes")
+		fmt.Println("   DELETE /users/:id/2fa                             # Reset a user's two-factor")
+		
+		fmt.Println("\n17. LOGIN SECURITY (4 endpoints)")
+		fmt.Println("   GET    /login-attempts                            # Login attempts log with filters")
+		fmt.Println("   GET    /users/:id/login-lock                      # Failed logins and lock of a user")
+		fmt.Println("   POST   /users/:id/unlock                          # Unlock a locked account")
+		fmt.Println("   POST   /users/:id/force-password-change           # Force a password change")
+		
+		fmt.Println("\n18. PASSWORD RESET AND POLICY (3 endpoints)")
+		fmt.Println("   POST   /api/v1/auth/forgot-password               # Email a one-time reset link")
+		fmt.Println("   POST   /api/v1/auth/reset-password                # Set a new password, revoke sessions")
+		fmt.Println("   GET    /api/v1/auth/password-policy               # Rules new passwords must follow")
+		
+		fmt.Println("\n19. API KEYS (4 endpoints)")
+		fmt.Println("   POST   /api-keys                                  # Create a scoped key, shown once")
+		fmt.Println("   GET    /api-keys                                  # List keys with filters")
+		fmt.Println("   GET    /api-keys/:id                              # Get key details and last use")
+		fmt.Println("   DELETE /api-keys/:id                              # Revoke a key")
+		
+		fmt.Println("\n20. TOKEN SIGNING KEYS (1 endpoint)")
+		fmt.Println("   GET    /.well-known/jwks.json                     # Public keys that verify access tokens")
+		
+		fmt.Println("\n21. AUDIT LOG (2 endpoints)")
+		fmt.Println("   GET    /audit-logs                                # Data changes by entity, user and date")
+		fmt.Println("   GET    /audit-logs/:id                            # Get a change with its before/after diff")
+		
+		fmt.Println("\n22. SESSIONS (2 endpoints)")
+		fmt.Println("   GET    /api/v1/auth/sessions                      # Devices the current user is logged in on")
+		fmt.Println("   DELETE /api/v1/auth/sessions/:id                  # Log out of one device")
 		
 		fmt.Println("\n=== KEY FEATURES ===")
 		fmt.Println("✓ Comprehensive CRUD operations")
@@ -0,0 +1,39 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSystemEndpointsAccessibility tests that the system administration endpoints are registered.
+// All endpoints require authentication, so we expect 401 responses
+func TestSystemEndpointsAccessibility(t *testing.T) {
+	router := setupTestRouter()
+
+	endpoints := []struct {
+		method   string
+		path     string
+		category string
+	}{
+		// Background Jobs
+		{"GET", "/api/v1/admin/jobs", "Background Jobs"},
+		{"GET", "/api/v1/admin/jobs/runs", "Background Jobs"},
+		{"POST", "/api/v1/admin/jobs/session-cleanup/run", "Background Jobs"},
+	}
+
+	for _, endpoint := range endpoints {
+		t.Run(fmt.Sprintf("%s_%s", endpoint.method, endpoint.path), func(t *testing.T) {
+			req, _ := http.NewRequest(endpoint.method, endpoint.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code,
+				"Endpoint %s %s should return 401 (auth required), not 404 (not found)",
+				endpoint.method, endpoint.path)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package audit_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConn records the statements it executes
+type fakeConn struct {
+	statements []string
+	actors     []string
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.statements = append(c.statements, "BEGIN")
+	return &fakeTx{conn: c}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.statements = append(c.statements, query)
+	if len(args) == 1 {
+		c.actors = append(c.actors, args[0].Value.(string))
+	}
+	return driver.RowsAffected(1), nil
+}
+
+type fakeTx struct {
+	conn *fakeConn
+}
+
+func (t *fakeTx) Commit() error {
+	t.conn.statements = append(t.conn.statements, "COMMIT")
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	t.conn.statements = append(t.conn.statements, "ROLLBACK")
+	return nil
+}
+
+type fakeConnector struct {
+	conn *fakeConn
+}
+
+func (f *fakeConnector) Connect(ctx context.Context) (driver.Conn, error) { return f.conn, nil }
+
+func (f *fakeConnector) Driver() driver.Driver { return nil }
+
+func openDB(t *testing.T) (*sql.DB, *fakeConn) {
+	conn := &fakeConn{}
+	db := sql.OpenDB(audit.WrapConnector(&fakeConnector{conn: conn}))
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db, conn
+}
+
+func TestConnector_SetsActorOncePerChange(t *testing.T) {
+	db, conn := openDB(t)
+	alice := audit.WithActor(context.Background(), audit.Actor{UserID: 1, SessionID: 7, IPAddress: "10.0.0.1"})
+	bob := audit.WithActor(context.Background(), audit.Actor{UserID: 2, APIKeyID: 3})
+
+	for _, ctx := range []context.Context{alice, alice, bob, context.Background()} {
+		_, err := db.ExecContext(ctx, "UPDATE customers SET name = 'x'")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{
+		`{"user_id":1,"session_id":7,"ip_address":"10.0.0.1"}`,
+		`{"user_id":2,"api_key_id":3}`,
+		"",
+	}, conn.actors, "the actor is only sent when it changes, and cleared without one")
+	assert.Len(t, conn.statements, 7)
+}
+
+func TestConnector_ResendsActorAfterRollback(t *testing.T) {
+	db, conn := openDB(t)
+	ctx := audit.WithActor(context.Background(), audit.Actor{UserID: 1})
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	_, err = tx.ExecContext(ctx, "DELETE FROM customers")
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+
+	_, err = db.ExecContext(ctx, "DELETE FROM customers")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{`{"user_id":1}`, `{"user_id":1}`}, conn.actors,
+		"a rolled back transaction may have undone the setting")
+}
+
+func TestActorFromContext(t *testing.T) {
+	_, ok := audit.ActorFromContext(context.Background())
+	assert.False(t, ok)
+
+	actor, ok := audit.ActorFromContext(audit.WithActor(context.Background(), audit.Actor{UserID: 5}))
+	assert.True(t, ok)
+	assert.Equal(t, 5, actor.UserID)
+}
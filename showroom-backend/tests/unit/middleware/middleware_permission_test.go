@@ -0,0 +1,178 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/dto/auth"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/middleware"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeChecker struct {
+	grants map[string][]string
+	err    error
+}
+
+func (f *fakeChecker) HasPermission(ctx context.Context, role, permission string) (bool, error) {
+	if f.err != nil {
+		return false, f.err
+	}
+	for _, granted := range f.grants[role] {
+		if granted == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func serveWithRole(role interface{}, handlers ...gin.HandlerFunc) int {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	chain := []gin.HandlerFunc{func(c *gin.Context) {
+		if role != nil {
+			c.Set("role", role)
+		}
+		c.Next()
+	}}
+	chain = append(chain, handlers...)
+	chain = append(chain, func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/", chain...)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRequirePermission(t *testing.T) {
+	checker := &fakeChecker{grants: map[string][]string{"cashier": {"sales_invoice.create"}}}
+
+	assert.Equal(t, http.StatusOK, serveWithRole(common.RoleCashier, middleware.RequirePermission(checker, "sales_invoice.create")))
+	assert.Equal(t, http.StatusForbidden, serveWithRole(common.RoleCashier, middleware.RequirePermission(checker, "sales_invoice.void")))
+	assert.Equal(t, http.StatusForbidden, serveWithRole(nil, middleware.RequirePermission(checker, "sales_invoice.create")))
+
+	failing := &fakeChecker{err: errors.New("database unavailable")}
+	assert.Equal(t, http.StatusInternalServerError, serveWithRole(common.RoleCashier, middleware.RequirePermission(failing, "sales_invoice.create")))
+}
+
+func TestRequireRole(t *testing.T) {
+	assert.Equal(t, http.StatusOK, serveWithRole(common.RoleAdmin, middleware.RequireRole("admin")))
+	assert.Equal(t, http.StatusOK, serveWithRole("admin", middleware.RequireRole("admin")))
+	assert.Equal(t, http.StatusForbidden, serveWithRole(common.RoleSales, middleware.RequireRole("admin")))
+}
+
+func TestRequireCurrentPassword(t *testing.T) {
+	setFlag := func(required bool) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			c.Set("password_change_required", required)
+			c.Next()
+		}
+	}
+
+	assert.Equal(t, http.StatusOK, serveWithRole(nil, setFlag(false), middleware.RequireCurrentPassword()))
+	assert.Equal(t, http.StatusForbidden, serveWithRole(nil, setFlag(true), middleware.RequireCurrentPassword()))
+	assert.Equal(t, http.StatusOK, serveWithRole(nil, middleware.RequireCurrentPassword()), "no flag means no restriction")
+}
+
+type fakeAPIKeys struct {
+	key   string
+	scope string
+}
+
+func (f *fakeAPIKeys) Authenticate(ctx context.Context, key, ipAddress string) (*user.APIKey, *user.User, error) {
+	if key != f.key {
+		return nil, nil, user.ErrAPIKeyInvalid
+	}
+	return &user.APIKey{KeyID: 7, Scopes: []string{f.scope}}, &user.User{UserID: 3, Role: common.RoleCashier}, nil
+}
+
+func serveWithAPIKey(key string, handlers ...gin.HandlerFunc) int {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handlers = append(handlers, func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/", handlers...)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(middleware.APIKeyHeader, key)
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestAuthMiddleware_APIKey(t *testing.T) {
+	checker := &fakeChecker{grants: map[string][]string{"cashier": {"sales_invoice.create", "sales_invoice.read"}}}
+	apiKeys := &fakeAPIKeys{key: "sk_valid", scope: "sales_invoice.read"}
+	authenticate := middleware.AuthMiddleware(nil, nil, apiKeys, 0)
+
+	assert.Equal(t, http.StatusOK, serveWithAPIKey("sk_valid", authenticate, middleware.RequirePermission(checker, "sales_invoice.read")))
+	assert.Equal(t, http.StatusForbidden, serveWithAPIKey("sk_valid", authenticate, middleware.RequirePermission(checker, "sales_invoice.create")),
+		"the role holds the permission but the key is not scoped for it")
+	assert.Equal(t, http.StatusUnauthorized, serveWithAPIKey("sk_other", authenticate))
+	assert.Equal(t, http.StatusUnauthorized, serveWithAPIKey("sk_valid", middleware.AuthMiddleware(nil, nil, nil, 0)),
+		"API keys are refused where no authenticator is given")
+
+	apiKeys.scope = "stock.read"
+	assert.Equal(t, http.StatusForbidden, serveWithAPIKey("sk_valid", authenticate, middleware.RequirePermission(checker, "stock.read")),
+		"the key is scoped for the permission but the role does not hold it")
+}
+
+// fakeSessions serves one session and counts activity updates
+type fakeSessions struct {
+	interfaces.UserSessionRepository
+	session *user.UserSession
+	touched int
+}
+
+func (f *fakeSessions) GetByID(ctx context.Context, id int) (*user.UserSession, error) {
+	return f.session, nil
+}
+
+func (f *fakeSessions) Touch(ctx context.Context, sessionID int) error {
+	f.touched++
+	return nil
+}
+
+func TestAuthMiddleware_IdleTimeout(t *testing.T) {
+	jwtManager := utils.NewJWTManager("test-secret", time.Hour)
+	token, err := jwtManager.GenerateToken(&auth.TokenClaims{UserID: 1, Role: common.RoleCashier, SessionID: 9})
+	require.NoError(t, err)
+
+	serve := func(sessions *fakeSessions, idleTimeout time.Duration) int {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.GET("/", middleware.AuthMiddleware(jwtManager, sessions, nil, idleTimeout), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	sessionActiveAt := func(lastActivity time.Time) *fakeSessions {
+		return &fakeSessions{session: &user.UserSession{SessionID: 9, UserID: 1, IsActive: true, LastActivityAt: lastActivity}}
+	}
+
+	recent := sessionActiveAt(time.Now())
+	assert.Equal(t, http.StatusOK, serve(recent, 30*time.Minute))
+	assert.Zero(t, recent.touched, "activity is not written on every request")
+
+	earlier := sessionActiveAt(time.Now().Add(-10 * time.Minute))
+	assert.Equal(t, http.StatusOK, serve(earlier, 30*time.Minute))
+	assert.Equal(t, 1, earlier.touched)
+
+	idle := sessionActiveAt(time.Now().Add(-31 * time.Minute))
+	assert.Equal(t, http.StatusUnauthorized, serve(idle, 30*time.Minute))
+	assert.Zero(t, idle.touched)
+	assert.Equal(t, http.StatusOK, serve(idle, 0), "a zero idle timeout never expires sessions")
+}
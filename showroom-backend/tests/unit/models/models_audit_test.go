@@ -0,0 +1,15 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/audit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditAction_IsValid(t *testing.T) {
+	assert.True(t, audit.ActionCreate.IsValid())
+	assert.True(t, audit.ActionUpdate.IsValid())
+	assert.True(t, audit.ActionDelete.IsValid())
+	assert.False(t, audit.Action("truncate").IsValid())
+}
@@ -0,0 +1,1277 @@
+package models_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func landedCostLines() []products.LandedCostAllocation {
+	return []products.LandedCostAllocation{
+		{ReceiptDetailID: 1, ProductID: 10, Quantity: 10, UnitCost: 100, UnitWeight: 1},
+		{ReceiptDetailID: 2, ProductID: 11, Quantity: 5, UnitCost: 400, UnitWeight: 4},
+		{ReceiptDetailID: 3, ProductID: 12, Quantity: 5, UnitCost: 200, UnitWeight: 2},
+	}
+}
+
+func TestLandedCostVoucher_Allocate(t *testing.T) {
+	tests := []struct {
+		method    products.AllocationMethod
+		allocated []float64
+	}{
+		// Basis: 1000, 2000, 1000
+		{products.AllocationMethodValue, []float64{250, 500, 250}},
+		// Basis: 10, 5, 5
+		{products.AllocationMethodQuantity, []float64{500, 250, 250}},
+		// Basis: 10, 20, 10
+		{products.AllocationMethodWeight, []float64{250, 500, 250}},
+	}
+
+	for _, test := range tests {
+		voucher := &products.LandedCostVoucher{
+			AllocationMethod: test.method,
+			FreightAmount:    600,
+			DutyAmount:       300,
+			InsuranceAmount:  100,
+		}
+		voucher.CalculateTotalAmount()
+		assert.Equal(t, 1000.0, voucher.TotalAmount)
+
+		lines := landedCostLines()
+		err := voucher.Allocate(lines)
+		assert.NoError(t, err)
+
+		for i, line := range lines {
+			assert.InDelta(t, test.allocated[i], line.AllocatedAmount, 0.001, "Method %s line %d", test.method, i)
+			assert.InDelta(t, test.allocated[i]/float64(line.Quantity), line.UnitLandedCost, 0.0001)
+		}
+	}
+}
+
+func TestLandedCostVoucher_Allocate_RoundingGoesToLastLine(t *testing.T) {
+	voucher := &products.LandedCostVoucher{
+		AllocationMethod: products.AllocationMethodQuantity,
+		TotalAmount:      100,
+	}
+	lines := []products.LandedCostAllocation{
+		{Quantity: 1}, {Quantity: 1}, {Quantity: 1},
+	}
+
+	err := voucher.Allocate(lines)
+	assert.NoError(t, err)
+	assert.Equal(t, 33.33, lines[0].AllocatedAmount)
+	assert.Equal(t, 33.33, lines[1].AllocatedAmount)
+	assert.Equal(t, 33.34, lines[2].AllocatedAmount)
+}
+
+func TestLandedCostVoucher_Allocate_Errors(t *testing.T) {
+	voucher := &products.LandedCostVoucher{AllocationMethod: products.AllocationMethodWeight, TotalAmount: 100}
+	assert.Error(t, voucher.Allocate(nil))
+	assert.Error(t, voucher.Allocate([]products.LandedCostAllocation{{Quantity: 5}}))
+
+	voucher.AllocationMethod = products.AllocationMethod("invalid")
+	assert.Error(t, voucher.Allocate(landedCostLines()))
+}
+
+func TestBlanketOrderTerms_CanRelease(t *testing.T) {
+	now := time.Now()
+	quantity := 100
+	value := 1000.0
+	terms := &products.BlanketOrderTerms{
+		AgreedQuantity:   &quantity,
+		AgreedValue:      &value,
+		ValidFrom:        now.AddDate(0, -1, 0),
+		ValidTo:          now.AddDate(0, 1, 0),
+		ConsumedQuantity: 60,
+		ConsumedValue:    600,
+	}
+
+	assert.Equal(t, 40, *terms.GetRemainingQuantity())
+	assert.Equal(t, 400.0, *terms.GetRemainingValue())
+	assert.NoError(t, terms.CanRelease(40, 400, now))
+	assert.Error(t, terms.CanRelease(41, 100, now), "quantity over the remaining balance should be blocked")
+	assert.Error(t, terms.CanRelease(10, 401, now), "value over the remaining balance should be blocked")
+	assert.Error(t, terms.CanRelease(1, 1, now.AddDate(0, 2, 0)), "expired blanket should be blocked")
+	assert.Error(t, terms.CanRelease(1, 1, now.AddDate(0, -2, 0)), "blanket not yet valid should be blocked")
+
+	// A date-only end date keeps the blanket open for the whole last day
+	lastDay := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	terms.ValidTo = lastDay
+	terms.ValidFrom = lastDay.AddDate(0, -1, 0)
+	assert.NoError(t, terms.CanRelease(1, 1, lastDay.Add(17*time.Hour)))
+	assert.False(t, terms.IsExpired(lastDay.Add(23*time.Hour)))
+	assert.True(t, terms.IsExpired(lastDay.AddDate(0, 0, 1)))
+
+	terms.ConsumedQuantity = 100
+	assert.True(t, terms.IsExhausted())
+	assert.Error(t, terms.CanRelease(1, 1, now))
+}
+
+func TestCheckBlanketReleaseLines(t *testing.T) {
+	lines := []products.BlanketLineBalance{
+		{PODetailID: 1, ProductID: 10, AgreedQuantity: 50, ReleasedQuantity: 45, RemainingQuantity: 5},
+		{PODetailID: 2, ProductID: 20, AgreedQuantity: 20, RemainingQuantity: 20},
+	}
+
+	assert.NoError(t, products.CheckBlanketReleaseLines(lines, []products.PurchaseOrderDetail{
+		{ProductID: 10, QuantityOrdered: 5}, {ProductID: 20, QuantityOrdered: 20},
+	}))
+
+	err := products.CheckBlanketReleaseLines(lines, []products.PurchaseOrderDetail{
+		{ProductID: 10, QuantityOrdered: 3}, {ProductID: 10, QuantityOrdered: 3},
+	})
+	assert.ErrorContains(t, err, "exceeds remaining blanket line quantity 5", "repeated products add up")
+
+	err = products.CheckBlanketReleaseLines(lines, []products.PurchaseOrderDetail{{ProductID: 30, QuantityOrdered: 1}})
+	assert.ErrorContains(t, err, "not on the blanket order")
+}
+
+func TestBlanketOrderTermsRequest_Validate(t *testing.T) {
+	now := time.Now()
+	quantity := 10
+
+	assert.Error(t, (&products.BlanketOrderTermsRequest{ValidFrom: now, ValidTo: now.AddDate(0, 1, 0)}).Validate())
+	assert.Error(t, (&products.BlanketOrderTermsRequest{AgreedQuantity: &quantity, ValidFrom: now, ValidTo: now}).Validate())
+	assert.NoError(t, (&products.BlanketOrderTermsRequest{AgreedQuantity: &quantity, ValidFrom: now, ValidTo: now.AddDate(0, 1, 0)}).Validate())
+}
+
+func TestSupplierPriceList_GetUnitPrice(t *testing.T) {
+	priceList := &products.SupplierPriceList{UnitPrice: 100}
+	err := priceList.SetPriceBreaks([]products.SupplierPriceBreakRequest{
+		{MinQuantity: 50, UnitPrice: 80},
+		{MinQuantity: 10, UnitPrice: 90},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 10, priceList.PriceBreaks[0].MinQuantity, "breaks should be ordered by quantity")
+
+	assert.Equal(t, 100.0, priceList.GetUnitPrice(1))
+	assert.Equal(t, 100.0, priceList.GetUnitPrice(9))
+	assert.Equal(t, 90.0, priceList.GetUnitPrice(10))
+	assert.Equal(t, 90.0, priceList.GetUnitPrice(49))
+	assert.Equal(t, 80.0, priceList.GetUnitPrice(500))
+
+	assert.Error(t, priceList.SetPriceBreaks([]products.SupplierPriceBreakRequest{
+		{MinQuantity: 10, UnitPrice: 90},
+		{MinQuantity: 10, UnitPrice: 85},
+	}))
+}
+
+func TestSupplierPriceList_IsValidOn(t *testing.T) {
+	now := time.Now()
+	validTo := now.AddDate(0, 1, 0)
+	priceList := &products.SupplierPriceList{
+		ValidFrom: now.AddDate(0, -1, 0),
+		ValidTo:   &validTo,
+		IsActive:  true,
+	}
+
+	assert.True(t, priceList.IsValidOn(now))
+	assert.False(t, priceList.IsValidOn(now.AddDate(0, -2, 0)))
+	assert.False(t, priceList.IsValidOn(now.AddDate(0, 2, 0)))
+	assert.NoError(t, priceList.Validate())
+
+	priceList.ValidTo = nil
+	assert.True(t, priceList.IsValidOn(now.AddDate(5, 0, 0)), "open-ended price list should stay valid")
+
+	priceList.IsActive = false
+	assert.False(t, priceList.IsValidOn(now))
+
+	invalidTo := now.AddDate(0, -2, 0)
+	priceList.ValidTo = &invalidTo
+	assert.Error(t, priceList.Validate())
+}
+
+func TestNewSupplierScorecard(t *testing.T) {
+	scorecard := products.NewSupplierScorecard(products.SupplierPerformanceMetrics{
+		SupplierID:        1,
+		ScheduledReceipts: 4,
+		OnTimeReceipts:    3,
+		QuantityOrdered:   100,
+		QuantityFilled:    90,
+		QuantityReceived:  90,
+		QuantityRejected:  9,
+		LeadTimeSamples:   2,
+		TotalLeadTimeDays: 15,
+	})
+
+	assert.Equal(t, 0.75, *scorecard.OnTimeDeliveryRate)
+	assert.Equal(t, 0.9, *scorecard.FillRate)
+	assert.Equal(t, 0.1, *scorecard.QualityRejectionRate)
+	assert.Equal(t, 7.5, *scorecard.AverageLeadTimeDays)
+	// 0.75*0.4 + 0.9*0.3 + 0.9*0.3 = 0.84
+	assert.Equal(t, 84.0, scorecard.OverallScore)
+
+	empty := products.NewSupplierScorecard(products.SupplierPerformanceMetrics{SupplierID: 2})
+	assert.Nil(t, empty.OnTimeDeliveryRate)
+	assert.Nil(t, empty.FillRate)
+	assert.Equal(t, 0.0, empty.OverallScore)
+}
+
+func TestRankSupplierScorecards(t *testing.T) {
+	scorecards := []products.SupplierScorecard{
+		products.NewSupplierScorecard(products.SupplierPerformanceMetrics{SupplierID: 1, SupplierName: "A", ScheduledReceipts: 2, OnTimeReceipts: 1}),
+		products.NewSupplierScorecard(products.SupplierPerformanceMetrics{SupplierID: 2, SupplierName: "B", ScheduledReceipts: 2, OnTimeReceipts: 2}),
+		products.NewSupplierScorecard(products.SupplierPerformanceMetrics{SupplierID: 3, SupplierName: "C", ScheduledReceipts: 4, OnTimeReceipts: 2}),
+	}
+
+	products.RankSupplierScorecards(scorecards)
+
+	assert.Equal(t, 2, scorecards[0].SupplierID)
+	assert.Equal(t, 1, scorecards[0].Rank)
+	assert.Equal(t, 2, scorecards[1].Rank)
+	assert.Equal(t, 2, scorecards[2].Rank, "equal scores should share a rank")
+	assert.Equal(t, 3, scorecards[2].RankedOutOf)
+}
+
+func TestSupplierInvoice_UpdateStatus(t *testing.T) {
+	invoiceDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dueDate := invoiceDate.AddDate(0, 0, 30)
+
+	invoice := &products.SupplierInvoice{InvoiceDate: invoiceDate, DueDate: dueDate, InvoiceAmount: 1000}
+	invoice.UpdateStatus(invoiceDate)
+	assert.Equal(t, products.PaymentStatusPending, invoice.InvoiceStatus)
+	assert.Equal(t, 1000.0, invoice.OutstandingAmount)
+
+	invoice.PaidAmount = 400
+	invoice.CreditAmount = 100
+	invoice.UpdateStatus(invoiceDate)
+	assert.Equal(t, products.PaymentStatusPartial, invoice.InvoiceStatus)
+	assert.Equal(t, 500.0, invoice.OutstandingAmount)
+
+	invoice.UpdateStatus(dueDate.AddDate(0, 0, 5))
+	assert.Equal(t, products.PaymentStatusOverdue, invoice.InvoiceStatus)
+	assert.Equal(t, 5, invoice.DaysOverdue)
+
+	invoice.PaidAmount = 880
+	invoice.DiscountTaken = 20
+	invoice.UpdateStatus(dueDate.AddDate(0, 0, 5))
+	assert.Equal(t, products.PaymentStatusPaid, invoice.InvoiceStatus)
+	assert.Equal(t, 0, invoice.DaysOverdue)
+
+	disputed := &products.SupplierInvoice{DueDate: dueDate, InvoiceAmount: 1000, InvoiceStatus: products.PaymentStatusDisputed}
+	disputed.UpdateStatus(dueDate.AddDate(0, 0, 5))
+	assert.Equal(t, products.PaymentStatusDisputed, disputed.InvoiceStatus)
+}
+
+func TestPaymentVoucher_ValidateAllocations(t *testing.T) {
+	paymentDate := time.Date(2024, 6, 5, 0, 0, 0, 0, time.UTC)
+	discountDueDate := paymentDate.AddDate(0, 0, 5)
+	invoices := map[int]*products.SupplierInvoice{
+		1: {InvoiceID: 1, SupplierID: 7, InvoiceNumber: "INV-1", InvoiceAmount: 600, OutstandingAmount: 600, DiscountPercent: 2, DiscountDueDate: &discountDueDate, InvoiceStatus: products.PaymentStatusPending},
+		2: {InvoiceID: 2, SupplierID: 7, InvoiceNumber: "INV-2", OutstandingAmount: 300, InvoiceStatus: products.PaymentStatusPartial},
+		3: {InvoiceID: 3, SupplierID: 8, InvoiceNumber: "INV-3", OutstandingAmount: 100, InvoiceStatus: products.PaymentStatusPending},
+		4: {InvoiceID: 4, SupplierID: 7, InvoiceNumber: "INV-4", OutstandingAmount: 100, InvoiceStatus: products.PaymentStatusDisputed},
+	}
+	voucher := &products.PaymentVoucher{SupplierID: 7, PaymentDate: paymentDate, Amount: 1000, AllocatedAmount: 200, VoucherStatus: products.PaymentVoucherStatusPosted}
+
+	// One payment split across two invoices, with an early payment discount on the first
+	assert.NoError(t, voucher.ValidateAllocations([]products.PaymentAllocationRequest{
+		{InvoiceID: 1, Amount: 588, DiscountAmount: 12},
+		{InvoiceID: 2, Amount: 200},
+	}, invoices))
+
+	assert.Error(t, voucher.ValidateAllocations([]products.PaymentAllocationRequest{
+		{InvoiceID: 1, Amount: 500},
+		{InvoiceID: 2, Amount: 300},
+		{InvoiceID: 1, Amount: 1},
+	}, invoices), "allocations exceed the unallocated voucher amount")
+
+	assert.Error(t, voucher.ValidateAllocations([]products.PaymentAllocationRequest{{InvoiceID: 2, Amount: 301}}, invoices))
+	assert.Error(t, voucher.ValidateAllocations([]products.PaymentAllocationRequest{{InvoiceID: 3, Amount: 50}}, invoices))
+	assert.Error(t, voucher.ValidateAllocations([]products.PaymentAllocationRequest{{InvoiceID: 4, Amount: 50}}, invoices))
+	assert.Error(t, voucher.ValidateAllocations([]products.PaymentAllocationRequest{{InvoiceID: 9, Amount: 50}}, invoices))
+	assert.Error(t, voucher.ValidateAllocations([]products.PaymentAllocationRequest{{InvoiceID: 1}}, invoices))
+
+	// Discounts are limited to the payment terms and lapse after the discount window
+	assert.Error(t, voucher.ValidateAllocations([]products.PaymentAllocationRequest{{InvoiceID: 2, Amount: 290, DiscountAmount: 10}}, invoices))
+	late := *voucher
+	late.PaymentDate = discountDueDate.AddDate(0, 0, 1)
+	assert.Error(t, late.ValidateAllocations([]products.PaymentAllocationRequest{{InvoiceID: 1, Amount: 588, DiscountAmount: 12}}, invoices))
+
+	voucher.VoucherStatus = products.PaymentVoucherStatusVoid
+	assert.Error(t, voucher.ValidateAllocations([]products.PaymentAllocationRequest{{InvoiceID: 1, Amount: 50}}, invoices))
+}
+
+func TestPaymentTerm_Terms(t *testing.T) {
+	invoiceDate := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	term := &products.PaymentTerm{TermID: 4, TermCode: "2/10NET30", NetDays: 30, DiscountPercent: 2, DiscountDays: 10}
+
+	assert.NoError(t, term.Validate())
+	assert.True(t, term.HasDiscount())
+	assert.Equal(t, "2/10 net 30", term.Describe())
+	assert.Equal(t, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), term.DueDate(invoiceDate))
+	assert.Equal(t, time.Date(2024, 6, 11, 0, 0, 0, 0, time.UTC), *term.DiscountDueDate(invoiceDate))
+	assert.Equal(t, 37.24, products.AnnualizedDiscountRate(term.DiscountPercent, term.NetDays-term.DiscountDays))
+
+	net := &products.PaymentTerm{NetDays: 60}
+	assert.NoError(t, net.Validate())
+	assert.Nil(t, net.DiscountDueDate(invoiceDate))
+	assert.Equal(t, "net 60", net.Describe())
+	assert.Equal(t, "due on receipt", (&products.PaymentTerm{}).Describe())
+
+	assert.Error(t, (&products.PaymentTerm{NetDays: 10, DiscountPercent: 2, DiscountDays: 15}).Validate())
+	assert.Error(t, (&products.PaymentTerm{NetDays: 30, DiscountPercent: 2}).Validate())
+	assert.Error(t, (&products.PaymentTerm{NetDays: 30, DiscountPercent: 100, DiscountDays: 10}).Validate())
+}
+
+func TestSupplierInvoice_AvailableDiscount(t *testing.T) {
+	invoice := &products.SupplierInvoice{InvoiceID: 1, InvoiceDate: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), InvoiceAmount: 1000, OutstandingAmount: 1000}
+	invoice.ApplyPaymentTerm(&products.PaymentTerm{TermID: 4, NetDays: 30, DiscountPercent: 2, DiscountDays: 10})
+
+	assert.Equal(t, 4, *invoice.PaymentTermID)
+	assert.Equal(t, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), invoice.DueDate)
+	assert.Equal(t, 20.0, invoice.AvailableDiscount(time.Date(2024, 6, 5, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 20.0, invoice.AvailableDiscount(time.Date(2024, 6, 11, 17, 0, 0, 0, time.UTC)), "the whole discount due date counts")
+	assert.Equal(t, 0.0, invoice.AvailableDiscount(time.Date(2024, 6, 12, 0, 0, 0, 0, time.UTC)))
+
+	// Discount already taken on an earlier installment is not offered again
+	invoice.DiscountTaken = 5
+	invoice.OutstandingAmount = 745
+	assert.Equal(t, 15.0, invoice.AvailableDiscount(time.Date(2024, 6, 5, 0, 0, 0, 0, time.UTC)))
+
+	// Net-only terms clear the discount window
+	invoice.ApplyPaymentTerm(&products.PaymentTerm{TermID: 2, NetDays: 30})
+	assert.Nil(t, invoice.DiscountDueDate)
+	assert.Equal(t, 0.0, invoice.AvailableDiscount(time.Date(2024, 6, 5, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestPaymentVoucher_ApplyEarlyPaymentDiscounts(t *testing.T) {
+	discountDueDate := time.Date(2024, 6, 11, 0, 0, 0, 0, time.UTC)
+	invoices := map[int]*products.SupplierInvoice{
+		1: {InvoiceID: 1, SupplierID: 7, InvoiceAmount: 1000, OutstandingAmount: 1000, DiscountPercent: 2, DiscountDueDate: &discountDueDate, InvoiceStatus: products.PaymentStatusPending},
+	}
+	voucher := &products.PaymentVoucher{SupplierID: 7, PaymentDate: time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC), Amount: 980, VoucherStatus: products.PaymentVoucherStatusPosted}
+
+	allocations := []products.PaymentAllocationRequest{{InvoiceID: 1, TakeDiscount: true}}
+	voucher.ApplyEarlyPaymentDiscounts(allocations, invoices)
+
+	assert.Equal(t, 20.0, allocations[0].DiscountAmount)
+	assert.Equal(t, 980.0, allocations[0].Amount)
+	assert.NoError(t, voucher.ValidateAllocations(allocations, invoices))
+}
+
+func TestNewEarlyPaymentDiscountReport(t *testing.T) {
+	asOf := time.Date(2024, 6, 5, 9, 0, 0, 0, time.UTC)
+	invoiceDate := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	term := &products.PaymentTerm{TermID: 4, NetDays: 30, DiscountPercent: 2, DiscountDays: 10}
+
+	soon := &products.SupplierInvoice{InvoiceID: 1, InvoiceDate: invoiceDate.AddDate(0, 0, -3), InvoiceAmount: 500, OutstandingAmount: 500, InvoiceStatus: products.PaymentStatusPending}
+	soon.ApplyPaymentTerm(term)
+	later := &products.SupplierInvoice{InvoiceID: 2, InvoiceDate: invoiceDate, InvoiceAmount: 1000, OutstandingAmount: 1000, InvoiceStatus: products.PaymentStatusPending}
+	later.ApplyPaymentTerm(term)
+	lapsed := &products.SupplierInvoice{InvoiceID: 3, InvoiceDate: invoiceDate.AddDate(0, 0, -20), InvoiceAmount: 1000, OutstandingAmount: 1000, InvoiceStatus: products.PaymentStatusPending}
+	lapsed.ApplyPaymentTerm(term)
+
+	var opportunities []products.EarlyPaymentDiscountOpportunity
+	for _, invoice := range []*products.SupplierInvoice{later, soon, lapsed} {
+		if opportunity, ok := products.NewEarlyPaymentDiscountOpportunity(invoice, "Alpha Motor", asOf); ok {
+			opportunities = append(opportunities, opportunity)
+		}
+	}
+	assert.Len(t, opportunities, 2)
+
+	report := products.NewEarlyPaymentDiscountReport(asOf, opportunities, nil)
+	assert.Equal(t, 1, report.Opportunities[0].InvoiceID, "windows closing soonest come first")
+	assert.Equal(t, 3, report.Opportunities[0].DaysLeft)
+	assert.Equal(t, 490.0, report.Opportunities[0].NetPaymentAmount)
+	assert.Equal(t, 30.0, report.TotalDiscount)
+	assert.Equal(t, 1470.0, report.TotalNetPayment)
+
+	withinDays := 3
+	report = products.NewEarlyPaymentDiscountReport(asOf, opportunities, &withinDays)
+	assert.Len(t, report.Opportunities, 1)
+	assert.Equal(t, 10.0, report.TotalDiscount)
+}
+
+func TestAgingBucketFor(t *testing.T) {
+	assert.Equal(t, products.AgingBucketCurrent, products.AgingBucketFor(-5))
+	assert.Equal(t, products.AgingBucketCurrent, products.AgingBucketFor(0))
+	assert.Equal(t, products.AgingBucket1To30, products.AgingBucketFor(1))
+	assert.Equal(t, products.AgingBucket1To30, products.AgingBucketFor(30))
+	assert.Equal(t, products.AgingBucket31To60, products.AgingBucketFor(31))
+	assert.Equal(t, products.AgingBucket61To90, products.AgingBucketFor(90))
+	assert.Equal(t, products.AgingBucketOver90, products.AgingBucketFor(91))
+}
+
+func TestNewAPAgingReport(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 15, 0, 0, 0, time.UTC)
+	invoices := []products.APAgingInvoice{
+		{InvoiceID: 1, SupplierID: 2, SupplierName: "Zeta Parts", DueDate: time.Date(2024, 7, 10, 0, 0, 0, 0, time.UTC), OutstandingAmount: 100},
+		{InvoiceID: 2, SupplierID: 1, SupplierName: "Alpha Motor", DueDate: time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC), OutstandingAmount: 200},
+		{InvoiceID: 3, SupplierID: 1, SupplierName: "Alpha Motor", DueDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), OutstandingAmount: 300},
+	}
+
+	report := products.NewAPAgingReport(asOf, invoices, true)
+
+	assert.Len(t, report.Suppliers, 2)
+	alpha := report.Suppliers[0]
+	assert.Equal(t, "Alpha Motor", alpha.SupplierName)
+	assert.Equal(t, 2, alpha.InvoiceCount)
+	assert.Equal(t, 200.0, alpha.Days1To30)
+	assert.Equal(t, 300.0, alpha.Over90)
+	assert.Equal(t, 500.0, alpha.Total)
+	assert.Equal(t, 15, alpha.Invoices[0].DaysPastDue)
+	assert.Equal(t, 121, alpha.Invoices[1].DaysPastDue)
+
+	assert.Equal(t, 100.0, report.Totals.Current)
+	assert.Equal(t, 600.0, report.Totals.Total)
+
+	header, rows := report.Table()
+	assert.Len(t, header, 12)
+	// two supplier rows, three invoice rows and the total row
+	assert.Len(t, rows, 6)
+	assert.Equal(t, "Total", rows[5][1])
+	assert.Equal(t, 600.0, rows[5][11])
+
+	summary := products.NewAPAgingReport(asOf, invoices, false)
+	assert.Empty(t, summary.Suppliers[0].Invoices)
+}
+
+func paymentRunCandidates() []products.PaymentRunCandidate {
+	account := "BCA 1234567890"
+	discountDueDate := time.Date(2024, 6, 20, 0, 0, 0, 0, time.UTC)
+	return []products.PaymentRunCandidate{
+		{Invoice: products.SupplierInvoice{InvoiceID: 1, SupplierID: 7, InvoiceNumber: "INV-1", DueDate: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), InvoiceAmount: 500, OutstandingAmount: 500, InvoiceStatus: products.PaymentStatusOverdue}, SupplierName: "Alpha", BankAccount: &account},
+		{Invoice: products.SupplierInvoice{InvoiceID: 2, SupplierID: 8, InvoiceNumber: "INV-2", DueDate: time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC), InvoiceAmount: 1000, OutstandingAmount: 1000, DiscountPercent: 2, DiscountDueDate: &discountDueDate, InvoiceStatus: products.PaymentStatusPending}, SupplierName: "Beta"},
+		{Invoice: products.SupplierInvoice{InvoiceID: 3, SupplierID: 7, InvoiceNumber: "INV-3", DueDate: time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC), InvoiceAmount: 300, OutstandingAmount: 300, InvoiceStatus: products.PaymentStatusDisputed}, SupplierName: "Alpha", BankAccount: &account},
+	}
+}
+
+func TestBuildPaymentRunLines(t *testing.T) {
+	paymentDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	lines := products.BuildPaymentRunLines(paymentRunCandidates(), products.PaymentRunPriorityDueDate, paymentDate, false, nil)
+	assert.Len(t, lines, 2, "disputed invoices are not paid")
+	assert.Equal(t, 1, lines[0].InvoiceID)
+	assert.Equal(t, 2, lines[1].InvoiceID)
+	assert.Equal(t, 1000.0, lines[1].Amount)
+	assert.Equal(t, products.PaymentRunLineStatusPending, lines[1].LineStatus)
+
+	lines = products.BuildPaymentRunLines(paymentRunCandidates(), products.PaymentRunPriorityDiscount, paymentDate, true, nil)
+	assert.Equal(t, 2, lines[0].InvoiceID, "the open discount window comes first")
+	assert.Equal(t, 20.0, lines[0].DiscountAmount)
+	assert.Equal(t, 980.0, lines[0].Amount)
+
+	// A budget skips what no longer fits and keeps filling with smaller invoices
+	budget := 600.0
+	lines = products.BuildPaymentRunLines(paymentRunCandidates(), products.PaymentRunPriorityAmount, paymentDate, false, &budget)
+	assert.Len(t, lines, 1)
+	assert.Equal(t, 1, lines[0].InvoiceID)
+}
+
+func TestPaymentRun_BankFile(t *testing.T) {
+	account := " BCA 1234567890 "
+	run := &products.PaymentRun{
+		RunNumber:   "PRUN-20240615-0001",
+		PaymentDate: time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC),
+		RunStatus:   products.PaymentRunStatusDraft,
+		Lines: []products.PaymentRunLine{
+			{InvoiceID: 1, SupplierID: 7, SupplierCode: "SUP-7", SupplierName: "Alpha", InvoiceNumber: "INV-1", Amount: 1250.5, BankAccount: &account, LineStatus: products.PaymentRunLineStatusPending},
+			{InvoiceID: 2, SupplierID: 8, SupplierName: "Beta", InvoiceNumber: "INV-2", Amount: 10, LineStatus: products.PaymentRunLineStatusFailed},
+			{InvoiceID: 3, SupplierID: 9, SupplierName: "Gamma", InvoiceNumber: "INV-3", Amount: 20, LineStatus: products.PaymentRunLineStatusPending},
+		},
+	}
+
+	assert.True(t, run.CanApprove())
+	assert.False(t, run.CanExport())
+	assert.Equal(t, []string{"Gamma"}, run.MissingBankAccounts(), "failed lines are left out")
+
+	records := run.BankFileRecords("IDR")
+	assert.Len(t, records, 2)
+	assert.Equal(t, "BCA 1234567890", records[0]["bank_account"])
+	assert.Equal(t, "1250.50", records[0]["amount"])
+	assert.Equal(t, "125050", records[0]["amount_cents"])
+	assert.Equal(t, "20240615", records[0]["payment_date"])
+	assert.Equal(t, "2", records[1]["line_number"])
+
+	header, rows := run.BankFileTable("IDR")
+	assert.Len(t, header, 8)
+	assert.Equal(t, []interface{}{"1", "BCA 1234567890", "SUP-7", "Alpha", "1250.50", "IDR", "INV-1", "20240615"}, rows[0])
+}
+
+func TestParseBankStatementCSV(t *testing.T) {
+	t.Run("signed amount column", func(t *testing.T) {
+		csv := "\ufeffDate,Description,Reference,Amount\n" +
+			"2024-06-15,TRF PV-20240614-0001 Alpha,REF1,\"-1,250.50\"\n" +
+			"\n" +
+			"2024-06-16,Customer deposit,,500\n"
+
+		lines, err := products.ParseBankStatementCSV(strings.NewReader(csv), products.DefaultBankStatementColumnMapping())
+		assert.NoError(t, err)
+		assert.Len(t, lines, 2, "blank rows are skipped")
+		assert.Equal(t, -1250.5, lines[0].Amount)
+		assert.Equal(t, "REF1", *lines[0].Reference)
+		assert.Nil(t, lines[1].Reference)
+		assert.Equal(t, 2, lines[1].LineNumber)
+		assert.Equal(t, products.BankMatchStatusUnmatched, lines[1].MatchStatus)
+		assert.NotEqual(t, lines[0].Fingerprint, lines[1].Fingerprint)
+	})
+
+	t.Run("debit and credit columns with decimal comma", func(t *testing.T) {
+		csv := "Account statement\n" +
+			"Tanggal;Keterangan;Debet;Kredit;Saldo\n" +
+			"15/06/2024;Biaya admin;10,00;;990,00\n" +
+			"15/06/2024;Biaya admin;10,00;;980,00\n" +
+			"16/06/2024;Setoran;;1.500,25;2.480,25\n"
+
+		mapping := products.BankStatementColumnMapping{
+			Date: "tanggal", Description: "keterangan", Debit: "debet", Credit: "kredit", Balance: "saldo",
+			DateFormat: "02/01/2006", Delimiter: ";", DecimalComma: true, SkipRows: 1,
+		}
+		lines, err := products.ParseBankStatementCSV(strings.NewReader(csv), mapping)
+		assert.NoError(t, err)
+		assert.Len(t, lines, 3)
+		assert.Equal(t, -10.0, lines[0].Amount)
+		assert.Equal(t, 1500.25, lines[2].Amount)
+		assert.Equal(t, 2480.25, *lines[2].Balance)
+		assert.Equal(t, time.Date(2024, 6, 16, 0, 0, 0, 0, time.UTC), lines[2].TransactionDate)
+		assert.NotEqual(t, lines[0].Fingerprint, lines[1].Fingerprint, "identical transactions stay distinct")
+	})
+
+	t.Run("debit suffix", func(t *testing.T) {
+		csv := "date,description,amount\n2024-06-15,Transfer,1.000.000 DB\n"
+		mapping := products.DefaultBankStatementColumnMapping()
+		mapping.Reference = ""
+		mapping.DecimalComma = true
+		lines, err := products.ParseBankStatementCSV(strings.NewReader(csv), mapping)
+		assert.NoError(t, err)
+		assert.Equal(t, -1000000.0, lines[0].Amount)
+	})
+
+	errorTests := []struct {
+		name    string
+		csv     string
+		mapping products.BankStatementColumnMapping
+		errMsg  string
+	}{
+		{"missing column", "date,description\n2024-06-15,x\n", products.DefaultBankStatementColumnMapping(), `column "reference" not found`},
+		{"invalid date", "date,description,reference,amount\n15-06-2024,x,,1\n", products.DefaultBankStatementColumnMapping(), "row 2: invalid date"},
+		{"invalid amount", "date,description,reference,amount\n2024-06-15,x,,abc\n", products.DefaultBankStatementColumnMapping(), "row 2: invalid amount"},
+		{"no transactions", "date,description,reference,amount\n", products.DefaultBankStatementColumnMapping(), "no transactions"},
+		{"no amount mapping", "date,description\n", products.BankStatementColumnMapping{Date: "date", Description: "description"}, "needs an amount column"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := products.ParseBankStatementCSV(strings.NewReader(tt.csv), tt.mapping)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestMatchBankStatementLines(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 6, d, 0, 0, 0, 0, time.UTC) }
+	reference := "INV-77"
+	candidates := []products.BankMatchCandidate{
+		{VoucherID: 1, DocumentNumber: "PV-20240614-0001", PaymentDate: day(14), Amount: 500},
+		{VoucherID: 2, DocumentNumber: "PV-20240614-0002", PaymentDate: day(14), Amount: 500},
+		{VoucherID: 3, DocumentNumber: "PV-20240610-0001", PaymentDate: day(10), PaymentReference: &reference, Amount: 800},
+		{VoucherID: 4, DocumentNumber: "PV-20240601-0001", PaymentDate: day(1), Amount: 300},
+	}
+	lines := []products.BankStatementLine{
+		{LineID: 10, TransactionDate: day(15), Description: "TRF ALPHA", Amount: -500, MatchStatus: products.BankMatchStatusUnmatched},
+		{LineID: 11, TransactionDate: day(15), Description: "TRF pv-20240614-0002", Amount: -500, MatchStatus: products.BankMatchStatusUnmatched},
+		{LineID: 12, TransactionDate: day(12), Description: "TRF GAMMA INV-77", Amount: -800, MatchStatus: products.BankMatchStatusUnmatched},
+		{LineID: 13, TransactionDate: day(12), Description: "TRF DELTA", Amount: -300, MatchStatus: products.BankMatchStatusUnmatched},
+		{LineID: 14, TransactionDate: day(15), Description: "DEPOSIT", Amount: 500, MatchStatus: products.BankMatchStatusUnmatched},
+	}
+
+	matches := products.MatchBankStatementLines(lines, candidates, 3)
+	assert.ElementsMatch(t, []products.BankStatementMatch{
+		{LineID: 11, VoucherID: 2},
+		{LineID: 10, VoucherID: 1},
+		{LineID: 12, VoucherID: 3},
+	}, matches, "the reference claims voucher 2 first, outside the window and credits are left")
+
+	// Without a reference both equal payments fit the line equally well
+	matches = products.MatchBankStatementLines(lines[:1], candidates, 3)
+	assert.Empty(t, matches, "ambiguous lines are left for manual matching")
+
+	matches = products.MatchBankStatementLines(lines[3:4], candidates, 11)
+	assert.Equal(t, []products.BankStatementMatch{{LineID: 13, VoucherID: 4}}, matches)
+
+	// Credits are matched to customer receipts only, and a receipt never to a debit
+	receipts := append(candidates,
+		products.BankMatchCandidate{ReceiptID: 7, DocumentNumber: "CR-20240615-0001", PaymentDate: day(15), Amount: 500},
+		products.BankMatchCandidate{ReceiptID: 8, DocumentNumber: "CR-20240613-0001", PaymentDate: day(13), Amount: 300},
+	)
+	credits := []products.BankStatementLine{
+		lines[4],
+		{LineID: 15, TransactionDate: day(12), Description: "TRF DELTA", Amount: -300, MatchStatus: products.BankMatchStatusUnmatched},
+		{LineID: 16, TransactionDate: day(18), Description: "DEPOSIT CR-20240613-0001", Amount: 300, MatchStatus: products.BankMatchStatusUnmatched},
+	}
+	matches = products.MatchBankStatementLines(credits, receipts, 5)
+	assert.ElementsMatch(t, []products.BankStatementMatch{
+		{LineID: 14, ReceiptID: 7},
+		{LineID: 16, ReceiptID: 8},
+	}, matches, "voucher 4 is too early for the debit line and receipt 8 only fits the credit")
+}
+
+func TestJournalEntry_Validate(t *testing.T) {
+	inventory := products.PostingRuleInventory
+
+	tests := []struct {
+		name   string
+		lines  []products.JournalLine
+		errMsg string
+	}{
+		{"balanced", []products.JournalLine{{AccountID: 1, Debit: 100}, {AccountID: 2, Credit: 60}, {AccountID: 3, Credit: 40}}, ""},
+		{"rule instead of account", []products.JournalLine{{Rule: &inventory, Debit: 10}, {AccountID: 2, Credit: 10}}, ""},
+		{"single line", []products.JournalLine{{AccountID: 1, Debit: 100}}, "at least two lines"},
+		{"both sides", []products.JournalLine{{AccountID: 1, Debit: 100, Credit: 100}, {AccountID: 2, Credit: 0.01}}, "either a debit or a credit"},
+		{"empty line", []products.JournalLine{{AccountID: 1, Debit: 100}, {AccountID: 2}}, "either a debit or a credit"},
+		{"negative", []products.JournalLine{{AccountID: 1, Debit: -5}, {AccountID: 2, Credit: 5}}, "negative"},
+		{"no account", []products.JournalLine{{Debit: 5}, {AccountID: 2, Credit: 5}}, "account is required"},
+		{"unbalanced", []products.JournalLine{{AccountID: 1, Debit: 100}, {AccountID: 2, Credit: 99.99}}, "not balanced"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := &products.JournalEntry{Lines: tt.lines}
+			err := entry.Validate()
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestJournalEntry_Reversal(t *testing.T) {
+	sourceID := 7
+	entry := &products.JournalEntry{
+		JournalID:     3,
+		JournalNumber: "JV-20240601-0003",
+		SourceType:    products.JournalSourceSupplierInvoice,
+		SourceID:      &sourceID,
+		Description:   "Supplier invoice INV-1",
+		JournalStatus: products.JournalStatusPosted,
+		Lines: []products.JournalLine{
+			{AccountID: 5, Debit: 250},
+			{AccountID: 4, Credit: 250},
+		},
+	}
+	assert.True(t, entry.CanReverse())
+
+	postedBy := 2
+	reversal := entry.Reversal(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), &postedBy)
+	assert.Equal(t, 3, *reversal.ReversalOfID)
+	assert.Equal(t, products.JournalSourceSupplierInvoice, reversal.SourceType)
+	assert.Equal(t, []products.JournalLine{{AccountID: 5, Credit: 250}, {AccountID: 4, Debit: 250}}, reversal.Lines)
+	assert.NoError(t, reversal.Validate())
+	assert.False(t, reversal.CanReverse(), "a reversal is not reversed again")
+
+	entry.JournalStatus = products.JournalStatusReversed
+	assert.False(t, entry.CanReverse())
+}
+
+func TestDocumentJournals(t *testing.T) {
+	ruleAmounts := func(entry *products.JournalEntry) map[products.PostingRule][2]float64 {
+		amounts := map[products.PostingRule][2]float64{}
+		for _, line := range entry.Lines {
+			amounts[*line.Rule] = [2]float64{line.Debit, line.Credit}
+		}
+		return amounts
+	}
+
+	t.Run("payment with early payment discount", func(t *testing.T) {
+		voucher := &products.PaymentVoucher{VoucherID: 1, VoucherNumber: "PV-1", PaymentMethod: products.PaymentMethodTransfer, Amount: 980}
+		allocations := []products.PaymentAllocationRequest{{InvoiceID: 1, Amount: 980, DiscountAmount: 20}}
+
+		entry := products.PaymentVoucherJournal(voucher, allocations)
+		assert.NoError(t, entry.Validate())
+		assert.Equal(t, map[products.PostingRule][2]float64{
+			products.PostingRuleAccountsPayable:  {1000, 0},
+			products.PostingRuleBank:             {0, 980},
+			products.PostingRulePurchaseDiscount: {0, 20},
+		}, ruleAmounts(entry))
+	})
+
+	t.Run("cash payment without discount", func(t *testing.T) {
+		voucher := &products.PaymentVoucher{VoucherID: 2, VoucherNumber: "PV-2", PaymentMethod: products.PaymentMethodCash, Amount: 150}
+
+		entry := products.PaymentVoucherJournal(voucher, nil)
+		assert.Len(t, entry.Lines, 2, "zero discount lines are dropped")
+		assert.Equal(t, [2]float64{0, 150}, ruleAmounts(entry)[products.PostingRuleCash])
+		assert.Nil(t, products.PaymentAllocationJournal(voucher, nil, time.Now()))
+	})
+
+	t.Run("stock adjustment sign", func(t *testing.T) {
+		shrinkage := products.StockAdjustmentJournal(&products.StockAdjustment{AdjustmentID: 4, CostImpact: -75}, time.Now(), 1)
+		assert.Equal(t, map[products.PostingRule][2]float64{
+			products.PostingRuleInventoryAdjustment: {75, 0},
+			products.PostingRuleInventory:           {0, 75},
+		}, ruleAmounts(shrinkage))
+
+		surplus := products.StockAdjustmentJournal(&products.StockAdjustment{AdjustmentID: 5, CostImpact: 30}, time.Now(), 1)
+		assert.Equal(t, map[products.PostingRule][2]float64{
+			products.PostingRuleInventory:           {30, 0},
+			products.PostingRuleInventoryAdjustment: {0, 30},
+		}, ruleAmounts(surplus))
+
+		assert.Nil(t, products.StockAdjustmentJournal(&products.StockAdjustment{AdjustmentID: 6}, time.Now(), 1))
+	})
+
+	t.Run("goods receipt values accepted quantity", func(t *testing.T) {
+		receipt := &products.GoodsReceipt{ReceiptID: 9, ReceiptNumber: "GR-9"}
+		details := []products.GoodsReceiptDetail{
+			{QuantityAccepted: 3, QuantityRejected: 1, UnitCost: 12.5},
+			{QuantityAccepted: 0, QuantityRejected: 2, UnitCost: 40},
+		}
+
+		entry := products.GoodsReceiptJournal(receipt, details, 1)
+		debit, credit := entry.Totals()
+		assert.Equal(t, 37.5, debit)
+		assert.Equal(t, 37.5, credit)
+		assert.Equal(t, [2]float64{0, 37.5}, ruleAmounts(entry)[products.PostingRuleGRNI])
+
+		assert.Nil(t, products.GoodsReceiptJournal(receipt, details[1:], 1), "fully rejected receipts post nothing")
+	})
+
+	t.Run("debit note credits the account the goods came from", func(t *testing.T) {
+		debitNote := &products.SupplierDebitNote{DebitNoteID: 8, DebitNoteNumber: "DN-8", ExchangeRate: 1, DebitAmount: 250}
+
+		fromStock := products.SupplierDebitNoteJournal(debitNote, products.ReturnSourceStock, nil)
+		assert.NoError(t, fromStock.Validate())
+		assert.Equal(t, map[products.PostingRule][2]float64{
+			products.PostingRuleAccountsPayable: {250, 0},
+			products.PostingRuleInventory:       {0, 250},
+		}, ruleAmounts(fromStock))
+
+		stockValue := 220.0
+		atCost := products.SupplierDebitNoteJournal(debitNote, products.ReturnSourceStock, &stockValue)
+		assert.NoError(t, atCost.Validate())
+		assert.Equal(t, map[products.PostingRule][2]float64{
+			products.PostingRuleAccountsPayable: {250, 0},
+			products.PostingRuleInventory:       {0, 220},
+			products.PostingRulePriceVariance:   {0, 30},
+		}, ruleAmounts(atCost), "inventory is relieved at its carrying cost")
+
+		rejected := products.SupplierDebitNoteJournal(debitNote, products.ReturnSourceReceipt, &stockValue)
+		assert.Equal(t, map[products.PostingRule][2]float64{
+			products.PostingRuleAccountsPayable: {250, 0},
+			products.PostingRuleGRNI:            {0, 250},
+		}, ruleAmounts(rejected), "rejected receipt lines never entered inventory")
+	})
+
+	t.Run("landed cost voucher is capitalized into inventory", func(t *testing.T) {
+		voucher := &products.LandedCostVoucher{VoucherID: 2, VoucherNumber: "LC-2", TotalAmount: 120}
+
+		entry := products.LandedCostJournal(voucher, 1)
+		assert.NoError(t, entry.Validate())
+		assert.Equal(t, map[products.PostingRule][2]float64{
+			products.PostingRuleInventory:         {120, 0},
+			products.PostingRuleAccruedLandedCost: {0, 120},
+		}, ruleAmounts(entry))
+	})
+
+	t.Run("supplier invoice splits input VAT", func(t *testing.T) {
+		invoice := &products.SupplierInvoice{InvoiceID: 3, InvoiceNumber: "INV-3", InvoiceAmount: 1110, TaxAmount: 110}
+
+		entry := products.SupplierInvoiceJournal(invoice, nil, nil)
+		assert.NoError(t, entry.Validate())
+		assert.Equal(t, map[products.PostingRule][2]float64{
+			products.PostingRuleGRNI:            {1000, 0},
+			products.PostingRuleVATInput:        {110, 0},
+			products.PostingRuleAccountsPayable: {0, 1110},
+		}, ruleAmounts(entry))
+	})
+
+	t.Run("supplier invoice clears GRNI at the matched receipt value", func(t *testing.T) {
+		invoice := &products.SupplierInvoice{InvoiceID: 4, InvoiceNumber: "INV-4", InvoiceAmount: 1110, TaxAmount: 110}
+
+		receiptValue := 960.0
+		entry := products.SupplierInvoiceJournal(invoice, &receiptValue, nil)
+		assert.NoError(t, entry.Validate())
+		assert.Equal(t, map[products.PostingRule][2]float64{
+			products.PostingRuleGRNI:            {960, 0},
+			products.PostingRulePriceVariance:   {40, 0},
+			products.PostingRuleVATInput:        {110, 0},
+			products.PostingRuleAccountsPayable: {0, 1110},
+		}, ruleAmounts(entry), "a price increase is an unfavourable variance")
+
+		receiptValue = 1025
+		assert.Equal(t, [2]float64{0, 25}, ruleAmounts(products.SupplierInvoiceJournal(invoice, &receiptValue, nil))[products.PostingRulePriceVariance],
+			"a price decrease is a favourable variance")
+	})
+
+	t.Run("sales invoice books revenue, output VAT and cost of sales", func(t *testing.T) {
+		invoice := &products.SalesInvoice{InvoiceID: 7, InvoiceNumber: "SI-7", CreatedBy: 1,
+			Subtotal: 151, TaxAmount: 12.21, TotalAmount: 163.21,
+			Lines: []products.SalesInvoiceLine{{Quantity: 2, UnitCost: 30}, {Quantity: 1, UnitCost: 20}}}
+
+		entry := products.SalesInvoiceJournal(invoice)
+		assert.NoError(t, entry.Validate())
+		assert.Equal(t, map[products.PostingRule][2]float64{
+			products.PostingRuleAccountsReceivable: {163.21, 0},
+			products.PostingRuleSalesRevenue:       {0, 151},
+			products.PostingRuleVATOutput:          {0, 12.21},
+			products.PostingRuleCostOfGoodsSold:    {80, 0},
+			products.PostingRuleInventory:          {0, 80},
+		}, ruleAmounts(entry))
+	})
+}
+
+func TestNewTrialBalance(t *testing.T) {
+	rows := []products.TrialBalanceRow{
+		{AccountID: 4, AccountCode: "2100", AccountType: products.GLAccountTypeLiability, Debit: 980, Credit: 1000},
+		{AccountID: 3, AccountCode: "1300", AccountType: products.GLAccountTypeAsset, Debit: 1000},
+		{AccountID: 2, AccountCode: "1110", AccountType: products.GLAccountTypeAsset, Credit: 980},
+	}
+
+	tb := products.NewTrialBalance(&products.TrialBalanceParams{}, rows)
+	assert.Equal(t, []string{"1110", "1300", "2100"}, []string{tb.Rows[0].AccountCode, tb.Rows[1].AccountCode, tb.Rows[2].AccountCode})
+	assert.Equal(t, 980.0, tb.Rows[0].BalanceCredit)
+	assert.Equal(t, 1000.0, tb.Rows[1].BalanceDebit)
+	assert.Equal(t, 20.0, tb.Rows[2].BalanceCredit)
+	assert.Equal(t, 1980.0, tb.TotalDebit)
+	assert.Equal(t, 1000.0, tb.TotalBalanceDebit)
+	assert.True(t, tb.IsBalanced)
+
+	rows[1].Debit = 999
+	assert.False(t, products.NewTrialBalance(&products.TrialBalanceParams{}, rows).IsBalanced)
+}
+
+func TestCalculateTax(t *testing.T) {
+	base, tax := products.CalculateTax(100, 11, false)
+	assert.Equal(t, 100.0, base)
+	assert.Equal(t, 11.0, tax)
+
+	base, tax = products.CalculateTax(111, 11, true)
+	assert.Equal(t, 100.0, base)
+	assert.Equal(t, 11.0, tax)
+
+	base, tax = products.CalculateTax(1000, 12, true)
+	assert.Equal(t, 892.86, base)
+	assert.Equal(t, 107.14, tax, "inclusive tax is the remainder so base and tax add up to the amount")
+
+	base, tax = products.CalculateTax(250, 0, true)
+	assert.Equal(t, 250.0, base)
+	assert.Equal(t, 0.0, tax)
+}
+
+func TestPurchaseOrderTax(t *testing.T) {
+	detail := &products.PurchaseOrderDetail{QuantityOrdered: 4, UnitCost: 50}
+	detail.CalculateTotalCost()
+	detail.ApplyTax(&products.TaxCode{TaxCodeID: 1, Rate: 11}, false)
+	assert.Equal(t, 1, *detail.TaxCodeID)
+	assert.Equal(t, 22.0, detail.TaxAmount)
+
+	detail.ApplyTax(nil, false)
+	assert.Nil(t, detail.TaxCodeID)
+	assert.Equal(t, 0.0, detail.TaxAmount)
+
+	t.Run("manual tax applies while no line is taxed", func(t *testing.T) {
+		po := &products.PurchaseOrderParts{TaxAmount: 50, ShippingCost: 10}
+		po.ApplyLineTotals(1000, 0, 0)
+		assert.Equal(t, 1000.0, po.Subtotal)
+		assert.Equal(t, 50.0, po.TaxAmount)
+		assert.Equal(t, 1060.0, po.TotalAmount)
+	})
+
+	t.Run("tax inclusive lines", func(t *testing.T) {
+		po := &products.PurchaseOrderParts{PricesIncludeTax: true, TaxAmount: 50, ShippingCost: 10}
+		po.ApplyLineTotals(1110, 110, 2)
+		assert.Equal(t, 1000.0, po.Subtotal)
+		assert.Equal(t, 110.0, po.TaxAmount)
+		assert.Equal(t, 1120.0, po.TotalAmount)
+	})
+}
+
+func TestSalesInvoice_CalculateTotals(t *testing.T) {
+	taxCodeID := 1
+	invoice := &products.SalesInvoice{Lines: []products.SalesInvoiceLine{
+		{ProductID: 1, Quantity: 2, UnitPrice: 55.5, TaxCodeID: &taxCodeID, TaxRate: 11},
+		{ProductID: 2, Quantity: 1, UnitPrice: 50, DiscountAmount: 10},
+	}}
+
+	assert.NoError(t, invoice.CalculateTotals())
+	assert.Equal(t, 12.21, invoice.Lines[0].TaxAmount)
+	assert.Equal(t, 40.0, invoice.Lines[1].LineTotal)
+	assert.Equal(t, 151.0, invoice.Subtotal)
+	assert.Equal(t, 12.21, invoice.TaxAmount)
+	assert.Equal(t, 163.21, invoice.TotalAmount)
+
+	invoice.PricesIncludeTax = true
+	assert.NoError(t, invoice.CalculateTotals())
+	assert.Equal(t, 100.0, invoice.Lines[0].TaxBase)
+	assert.Equal(t, 151.0, invoice.TotalAmount, "inclusive prices do not add tax on top")
+
+	invoice.Lines[1].DiscountAmount = 60
+	assert.Error(t, invoice.CalculateTotals())
+}
+
+func TestWriteEFakturCSV(t *testing.T) {
+	taxInvoiceNumber := "010.000-24.00000001"
+	customerTaxNumber := "01.234.567.8-901.000"
+	invoice := products.SalesInvoice{
+		InvoiceNumber:     "SI-20260305-0001",
+		CustomerName:      "Bengkel Maju",
+		CustomerTaxNumber: &customerTaxNumber,
+		CustomerAddress:   "Jl. Merdeka 1, Bandung",
+		InvoiceDate:       time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC),
+		PricesIncludeTax:  true,
+		TaxInvoiceNumber:  &taxInvoiceNumber,
+		InvoiceStatus:     products.SalesInvoiceStatusIssued,
+		Lines: []products.SalesInvoiceLine{
+			{ProductCode: "PRD-001", ProductName: "Oil filter", Quantity: 2, UnitPrice: 111, DiscountAmount: 22.2, TaxRate: 11},
+		},
+	}
+	assert.NoError(t, invoice.CalculateTotals())
+	untaxed := products.SalesInvoice{InvoiceNumber: "SI-20260305-0002", InvoiceStatus: products.SalesInvoiceStatusIssued}
+
+	var b strings.Builder
+	assert.NoError(t, products.WriteEFakturCSV(&b, []products.SalesInvoice{invoice, untaxed}))
+
+	rows := strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Len(t, rows, 5, "three header rows, one FK and one OF row")
+	assert.True(t, strings.HasPrefix(rows[0], "FK,KD_JENIS_TRANSAKSI,"))
+	assert.Equal(t, `FK,01,0,0002400000001,3,2026,05/03/2026,012345678901000,Bengkel Maju,"Jl. Merdeka 1, Bandung",180,19,0,,0,0,0,0,SI-20260305-0001,`, rows[3])
+	assert.Equal(t, "OF,PRD-001,Oil filter,100,2,200,20,180,19.8,0,0", rows[4])
+
+	invoice.TaxInvoiceNumber = nil
+	err := products.WriteEFakturCSV(&b, []products.SalesInvoice{invoice})
+	assert.ErrorContains(t, err, "SI-20260305-0001")
+}
+
+func TestNewVATReport(t *testing.T) {
+	start := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	report := products.NewVATReport(start,
+		[]products.VATReportLine{{TaxBase: 1000, TaxAmount: 110}},
+		[]products.VATReportLine{{TaxBase: 3000, TaxAmount: 330}, {TaxBase: 100, TaxAmount: 11}},
+	)
+
+	assert.Equal(t, time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC), report.PeriodEnd)
+	assert.Equal(t, 3100.0, report.TotalOutputBase)
+	assert.Equal(t, 341.0, report.TotalOutputVAT)
+	assert.Equal(t, 231.0, report.NetVATPayable)
+
+	credit := products.NewVATReport(start, []products.VATReportLine{{TaxBase: 1000, TaxAmount: 110}}, nil)
+	assert.Equal(t, -110.0, credit.NetVATPayable, "excess input VAT carries forward as a credit")
+	assert.NotNil(t, credit.OutputLines)
+}
+
+func TestNormalizeCurrencyCode(t *testing.T) {
+	code, err := products.NormalizeCurrencyCode(" usd ")
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", code)
+
+	code, err = products.NormalizeCurrencyCode("")
+	assert.NoError(t, err)
+	assert.Equal(t, products.BaseCurrency, code)
+
+	for _, invalid := range []string{"US", "USDT", "U5D"} {
+		_, err := products.NormalizeCurrencyCode(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestExchangeDifference(t *testing.T) {
+	assert.Equal(t, 1550000.0, products.ToBaseCurrency(100, 15500))
+	assert.Equal(t, 12.35, products.ToBaseCurrency(12.345, 0), "documents without a rate are in the base currency")
+
+	assert.Equal(t, -50000.0, products.ExchangeDifference(100, 15000, 15500), "paying at a higher rate is a loss")
+	assert.Equal(t, 30000.0, products.ExchangeDifference(100, 15500, 15200))
+	assert.Equal(t, 0.0, products.ExchangeDifference(100, 1, 1))
+}
+
+func TestParseExchangeRateCSV(t *testing.T) {
+	csv := "\ufeffCurrency_Code,rate_date,rate\n" +
+		"usd,2024-06-03,\"15,850.50\"\n" +
+		"\n" +
+		"SGD,2024-06-03,11720\n"
+
+	rates, err := products.ParseExchangeRateCSV(strings.NewReader(csv))
+	assert.NoError(t, err)
+	assert.Len(t, rates, 2)
+	assert.Equal(t, "USD", rates[0].CurrencyCode)
+	assert.Equal(t, 15850.5, rates[0].Rate)
+	assert.Equal(t, time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC), rates[0].RateDate)
+	assert.Equal(t, products.ExchangeRateSourceImport, rates[1].Source)
+
+	_, err = products.ParseExchangeRateCSV(strings.NewReader("currency_code,rate\nUSD,15000\n"))
+	assert.EqualError(t, err, `column "rate_date" not found in header`)
+
+	_, err = products.ParseExchangeRateCSV(strings.NewReader("currency_code,rate_date,rate\nIDR,2024-06-03,1\n"))
+	assert.EqualError(t, err, "row 2: IDR is the base currency")
+
+	_, err = products.ParseExchangeRateCSV(strings.NewReader("currency_code,rate_date,rate\nUSD,03/06/2024,15000\n"))
+	assert.EqualError(t, err, `row 2: invalid date "03/06/2024"`)
+
+	_, err = products.ParseExchangeRateCSV(strings.NewReader("currency_code,rate_date,rate\nUSD,2024-06-03,0\n"))
+	assert.EqualError(t, err, `row 2: invalid rate "0"`)
+
+	_, err = products.ParseExchangeRateCSV(strings.NewReader("currency_code,rate_date,rate\n"))
+	assert.Error(t, err)
+}
+
+func TestForeignCurrencyJournals(t *testing.T) {
+	ruleAmounts := func(entry *products.JournalEntry) map[products.PostingRule][2]float64 {
+		amounts := map[products.PostingRule][2]float64{}
+		for _, line := range entry.Lines {
+			amounts[*line.Rule] = [2]float64{line.Debit, line.Credit}
+		}
+		return amounts
+	}
+
+	t.Run("invoice is booked at its rate", func(t *testing.T) {
+		invoice := &products.SupplierInvoice{InvoiceID: 1, InvoiceNumber: "INV-1", CurrencyCode: "USD", ExchangeRate: 15000, InvoiceAmount: 111, TaxAmount: 11}
+
+		entry := products.SupplierInvoiceJournal(invoice, nil, nil)
+		assert.NoError(t, entry.Validate())
+		assert.Equal(t, map[products.PostingRule][2]float64{
+			products.PostingRuleGRNI:            {1500000, 0},
+			products.PostingRuleVATInput:        {165000, 0},
+			products.PostingRuleAccountsPayable: {0, 1665000},
+		}, ruleAmounts(entry))
+	})
+
+	t.Run("payment at a higher rate realizes a loss", func(t *testing.T) {
+		voucher := &products.PaymentVoucher{VoucherID: 2, VoucherNumber: "PV-2", PaymentMethod: products.PaymentMethodTransfer,
+			CurrencyCode: "USD", ExchangeRate: 15500, Amount: 100}
+		allocations := []products.PaymentAllocationRequest{{InvoiceID: 1, Amount: 100}}
+		voucher.ApplyInvoiceRates(allocations, map[int]*products.SupplierInvoice{1: {InvoiceID: 1, ExchangeRate: 15000}})
+		assert.Equal(t, -50000.0, allocations[0].ExchangeDifference(voucher.ExchangeRate))
+
+		entry := products.PaymentVoucherJournal(voucher, allocations)
+		assert.NoError(t, entry.Validate())
+		assert.Equal(t, map[products.PostingRule][2]float64{
+			products.PostingRuleAccountsPayable: {1500000, 0},
+			products.PostingRuleBank:            {0, 1550000},
+			products.PostingRuleRealizedFX:      {50000, 0},
+		}, ruleAmounts(entry))
+	})
+
+	t.Run("payment at a lower rate realizes a gain and converts discounts at the invoice rate", func(t *testing.T) {
+		voucher := &products.PaymentVoucher{VoucherID: 3, VoucherNumber: "PV-3", PaymentMethod: products.PaymentMethodTransfer,
+			CurrencyCode: "USD", ExchangeRate: 14800, Amount: 98}
+		allocations := []products.PaymentAllocationRequest{{InvoiceID: 1, Amount: 98, DiscountAmount: 2, InvoiceRate: 15000}}
+
+		entry := products.PaymentVoucherJournal(voucher, allocations)
+		assert.NoError(t, entry.Validate())
+		assert.Equal(t, map[products.PostingRule][2]float64{
+			products.PostingRuleAccountsPayable:  {1500000, 0},
+			products.PostingRuleBank:             {0, 1450400},
+			products.PostingRulePurchaseDiscount: {0, 30000},
+			products.PostingRuleRealizedFX:       {0, 19600},
+		}, ruleAmounts(entry))
+	})
+
+	t.Run("later allocation books only the difference", func(t *testing.T) {
+		voucher := &products.PaymentVoucher{VoucherID: 4, VoucherNumber: "PV-4", CurrencyCode: "USD", ExchangeRate: 15500, Amount: 100}
+		allocations := []products.PaymentAllocationRequest{{InvoiceID: 1, Amount: 100, InvoiceRate: 15000}}
+
+		entry := products.PaymentAllocationJournal(voucher, allocations, time.Now())
+		assert.NoError(t, entry.Validate())
+		assert.Equal(t, map[products.PostingRule][2]float64{
+			products.PostingRuleAccountsPayable: {0, 50000},
+			products.PostingRuleRealizedFX:      {50000, 0},
+		}, ruleAmounts(entry))
+
+		same := []products.PaymentAllocationRequest{{InvoiceID: 1, Amount: 100, InvoiceRate: 15500}}
+		assert.Nil(t, products.PaymentAllocationJournal(voucher, same, time.Now()))
+	})
+
+	t.Run("debit note application difference", func(t *testing.T) {
+		debitNote := &products.SupplierDebitNote{DebitNoteID: 5, DebitNoteNumber: "DN-5", CurrencyCode: "USD", ExchangeRate: 15200}
+
+		entry := products.DebitNoteApplicationJournal(debitNote, products.ExchangeDifference(10, 15000, 15200), time.Now(), 1)
+		assert.NoError(t, entry.Validate())
+		assert.Equal(t, map[products.PostingRule][2]float64{
+			products.PostingRuleAccountsPayable: {0, 2000},
+			products.PostingRuleRealizedFX:      {2000, 0},
+		}, ruleAmounts(entry))
+		assert.Nil(t, products.DebitNoteApplicationJournal(debitNote, 0, time.Now(), 1))
+	})
+
+	t.Run("receipt is valued at its rate", func(t *testing.T) {
+		receipt := &products.GoodsReceipt{ReceiptID: 6, ReceiptNumber: "GR-6", CurrencyCode: "USD", ExchangeRate: 15000}
+		assert.Equal(t, 187500.0, receipt.BaseUnitCost(12.5))
+
+		entry := products.GoodsReceiptJournal(receipt, []products.GoodsReceiptDetail{{QuantityAccepted: 2, UnitCost: 12.5}}, 1)
+		assert.Equal(t, [2]float64{375000, 0}, ruleAmounts(entry)[products.PostingRuleInventory])
+	})
+}
+
+func TestPaymentVoucher_ValidateAllocationsCurrency(t *testing.T) {
+	voucher := &products.PaymentVoucher{SupplierID: 1, CurrencyCode: "USD", ExchangeRate: 15500, Amount: 100}
+	invoices := map[int]*products.SupplierInvoice{
+		1: {InvoiceID: 1, InvoiceNumber: "INV-1", SupplierID: 1, CurrencyCode: products.BaseCurrency, ExchangeRate: 1,
+			InvoiceAmount: 100, OutstandingAmount: 100, InvoiceStatus: products.PaymentStatusPending},
+	}
+
+	err := voucher.ValidateAllocations([]products.PaymentAllocationRequest{{InvoiceID: 1, Amount: 100}}, invoices)
+	assert.EqualError(t, err, "supplier invoice INV-1 is in IDR, not the voucher currency USD")
+}
+
+func TestNewAPAgingReport_ForeignCurrency(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	invoices := []products.APAgingInvoice{
+		{InvoiceID: 1, SupplierID: 1, SupplierName: "Alpha Motor", DueDate: asOf.AddDate(0, 0, 5), CurrencyCode: "USD", ExchangeRate: 15000, OutstandingAmount: 10},
+		{InvoiceID: 2, SupplierID: 1, SupplierName: "Alpha Motor", DueDate: asOf.AddDate(0, 0, -5), CurrencyCode: products.BaseCurrency, ExchangeRate: 1, OutstandingAmount: 50000},
+	}
+
+	report := products.NewAPAgingReport(asOf, invoices, true)
+	assert.Equal(t, 150000.0, report.Totals.Current)
+	assert.Equal(t, 50000.0, report.Totals.Days1To30)
+	assert.Equal(t, 200000.0, report.Totals.Total)
+	assert.Equal(t, 10.0, report.Suppliers[0].Invoices[0].OutstandingAmount, "invoices keep their document currency amount")
+	assert.Equal(t, 150000.0, report.Suppliers[0].Invoices[0].BaseOutstandingAmount)
+}
+
+func TestCheckCreditLimit(t *testing.T) {
+	assert.NoError(t, products.CheckCreditLimit("CUS-1", 1000000, 400000, 600000))
+	assert.EqualError(t, products.CheckCreditLimit("CUS-1", 1000000, 400000, 600000.01),
+		"credit limit of customer CUS-1 exceeded: outstanding 400000.00 plus 600000.01 is above the limit of 1000000.00")
+	assert.EqualError(t, products.CheckCreditLimit("CUS-2", 0, 0, 1),
+		"customer CUS-2 has no credit limit, credit sales are not allowed")
+}
+
+func TestCustomerReceipt_Allocations(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 6, d, 0, 0, 0, 0, time.UTC) }
+	open := []products.SalesInvoice{
+		{InvoiceID: 3, InvoiceNumber: "SI-3", CustomerID: 1, InvoiceStatus: products.SalesInvoiceStatusIssued, DueDate: day(20), OutstandingAmount: 300},
+		{InvoiceID: 1, InvoiceNumber: "SI-1", CustomerID: 1, InvoiceStatus: products.SalesInvoiceStatusIssued, DueDate: day(5), OutstandingAmount: 100},
+		{InvoiceID: 2, InvoiceNumber: "SI-2", CustomerID: 1, InvoiceStatus: products.SalesInvoiceStatusIssued, DueDate: day(10), OutstandingAmount: 200},
+		{InvoiceID: 4, InvoiceNumber: "SI-4", CustomerID: 2, InvoiceStatus: products.SalesInvoiceStatusIssued, DueDate: day(1), OutstandingAmount: 50},
+	}
+
+	t.Run("auto allocation settles the oldest due first", func(t *testing.T) {
+		receipt := &products.CustomerReceipt{CustomerID: 1, Amount: 350, ReceiptStatus: products.CustomerReceiptStatusPosted}
+		assert.Equal(t, []products.CustomerReceiptAllocationRequest{
+			{InvoiceID: 1, Amount: 100},
+			{InvoiceID: 2, Amount: 200},
+			{InvoiceID: 3, Amount: 50},
+		}, receipt.AutoAllocate(open))
+
+		receipt.AllocatedAmount = 350
+		assert.Empty(t, receipt.AutoAllocate(open))
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		invoices := map[int]*products.SalesInvoice{}
+		for i := range open {
+			invoices[open[i].InvoiceID] = &open[i]
+		}
+		receipt := &products.CustomerReceipt{CustomerID: 1, Amount: 250, AllocatedAmount: 50, ReceiptStatus: products.CustomerReceiptStatusPosted}
+
+		assert.NoError(t, receipt.ValidateAllocations([]products.CustomerReceiptAllocationRequest{{InvoiceID: 1, Amount: 100}, {InvoiceID: 2, Amount: 100}}, invoices))
+		assert.EqualError(t, receipt.ValidateAllocations([]products.CustomerReceiptAllocationRequest{{InvoiceID: 2, Amount: 150}, {InvoiceID: 3, Amount: 100}}, invoices),
+			"allocations of 250.00 exceed the unallocated receipt amount of 200.00")
+		assert.EqualError(t, receipt.ValidateAllocations([]products.CustomerReceiptAllocationRequest{{InvoiceID: 1, Amount: 60}, {InvoiceID: 1, Amount: 60}}, invoices),
+			"allocation of 120.00 exceeds the outstanding 100.00 of sales invoice SI-1")
+		assert.EqualError(t, receipt.ValidateAllocations([]products.CustomerReceiptAllocationRequest{{InvoiceID: 4, Amount: 10}}, invoices),
+			"sales invoice SI-4 does not belong to the receipt customer")
+		assert.EqualError(t, receipt.ValidateAllocations([]products.CustomerReceiptAllocationRequest{{InvoiceID: 9, Amount: 10}}, invoices),
+			"sales invoice 9 not found")
+
+		receipt.ReceiptStatus = products.CustomerReceiptStatusVoid
+		assert.EqualError(t, receipt.ValidateAllocations(nil, invoices), "cannot allocate a void customer receipt")
+	})
+}
+
+func TestNewCustomerCreditStatus(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	termID := 2
+	open := []products.SalesInvoice{
+		{InvoiceID: 1, CustomerID: 1, InvoiceStatus: products.SalesInvoiceStatusIssued, DueDate: asOf.AddDate(0, 0, 10), OutstandingAmount: 400000},
+		{InvoiceID: 2, CustomerID: 1, InvoiceStatus: products.SalesInvoiceStatusIssued, DueDate: asOf.AddDate(0, 0, -45), OutstandingAmount: 700000},
+	}
+
+	status := products.NewCustomerCreditStatus(1, "CUS-1", "Budi", 1000000, &termID, open, asOf)
+	assert.Equal(t, 1100000.0, status.OutstandingAmount)
+	assert.Equal(t, 700000.0, status.OverdueAmount)
+	assert.Equal(t, 0.0, status.AvailableCredit, "available credit does not go negative")
+	assert.Equal(t, 400000.0, status.Aging.Current)
+	assert.Equal(t, 700000.0, status.Aging.Days31To60)
+	assert.Len(t, status.OpenInvoices, 2)
+}
+
+func TestNewCustomerStatement(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC) }
+	due := day(31)
+	reference := "TRF-889"
+	lines := []products.CustomerStatementLine{
+		{Date: day(20), DocumentType: products.StatementDocumentReceipt, DocumentNumber: "CR-20240520-0001", Reference: &reference, Credit: 500000},
+		{Date: day(20), DocumentType: products.StatementDocumentInvoice, DocumentNumber: "SI-20240520-0001", DueDate: &due, Debit: 300000},
+		{Date: day(2), DocumentType: products.StatementDocumentInvoice, DocumentNumber: "SI-20240502-0001", DueDate: &due, Debit: 450000},
+	}
+	open := []products.SalesInvoice{
+		{InvoiceID: 1, InvoiceStatus: products.SalesInvoiceStatusIssued, DueDate: day(31), OutstandingAmount: 250000},
+		{InvoiceID: 2, InvoiceStatus: products.SalesInvoiceStatusIssued, DueDate: day(1).AddDate(0, 0, -40), OutstandingAmount: 200000},
+	}
+
+	statement := products.NewCustomerStatement(1, "CUS-1", "Budi", "Jl. Merdeka 1, Bandung", 1000000,
+		day(1), day(31), 200000, lines, open)
+	require.Len(t, statement.Lines, 3)
+	assert.Equal(t, "SI-20240502-0001", statement.Lines[0].DocumentNumber)
+	assert.Equal(t, products.StatementDocumentInvoice, statement.Lines[1].DocumentType, "invoices come before receipts on the same day")
+	assert.Equal(t, []float64{650000, 950000, 450000},
+		[]float64{statement.Lines[0].Balance, statement.Lines[1].Balance, statement.Lines[2].Balance})
+	assert.Equal(t, 750000.0, statement.TotalInvoiced)
+	assert.Equal(t, 500000.0, statement.TotalReceived)
+	assert.Equal(t, 450000.0, statement.ClosingBalance)
+	assert.Equal(t, 250000.0, statement.Aging.Current)
+	assert.Equal(t, 200000.0, statement.Aging.Days61To90)
+
+	text := strings.Join(statement.Text(), "\n")
+	assert.Contains(t, text, "Customer : CUS-1 - Budi")
+	assert.Contains(t, text, "Period   : 2024-05-01 to 2024-05-31")
+	assert.Contains(t, text, "CR-20240520-0001")
+	assert.Contains(t, text, "Balance due as of 2024-05-31: 450000.00")
+}
+
+func TestCustomerStatementParams_Period(t *testing.T) {
+	today := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+
+	from, to, err := (&products.CustomerStatementParams{}).Period(today)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), to)
+
+	_, _, err = (&products.CustomerStatementParams{Format: "xlsx"}).Period(today)
+	assert.EqualError(t, err, "invalid report format: xlsx")
+
+	_, _, err = (&products.CustomerStatementParams{DateFrom: &today, DateTo: &from}).Period(today)
+	assert.EqualError(t, err, "date_to cannot be before date_from")
+}
+
+func TestCustomerReceiptJournal(t *testing.T) {
+	ruleAmounts := func(entry *products.JournalEntry) map[products.PostingRule][2]float64 {
+		amounts := map[products.PostingRule][2]float64{}
+		for _, line := range entry.Lines {
+			amounts[*line.Rule] = [2]float64{line.Debit, line.Credit}
+		}
+		return amounts
+	}
+
+	receipt := &products.CustomerReceipt{ReceiptID: 1, ReceiptNumber: "CR-1", PaymentMethod: products.PaymentMethodTransfer, Amount: 500000, ReceivedBy: 1}
+	entry := products.CustomerReceiptJournal(receipt)
+	assert.NoError(t, entry.Validate())
+	assert.Equal(t, map[products.PostingRule][2]float64{
+		products.PostingRuleBank:               {500000, 0},
+		products.PostingRuleAccountsReceivable: {0, 500000},
+	}, ruleAmounts(entry))
+
+	receipt.PaymentMethod = products.PaymentMethodCash
+	assert.Equal(t, [2]float64{500000, 0}, ruleAmounts(products.CustomerReceiptJournal(receipt))[products.PostingRuleCash])
+}
+
+func TestSalesInvoice_Receivable(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	invoice := &products.SalesInvoice{InvoiceStatus: products.SalesInvoiceStatusIssued, DueDate: asOf, TotalAmount: 100, OutstandingAmount: 100}
+
+	assert.True(t, invoice.CanVoid())
+	assert.False(t, invoice.IsOverdue(asOf))
+	assert.True(t, invoice.IsOverdue(asOf.AddDate(0, 0, 1)))
+
+	invoice.PaidAmount, invoice.OutstandingAmount = 100, 0
+	assert.False(t, invoice.CanVoid(), "invoices with receipts cannot be voided")
+	assert.False(t, invoice.IsOverdue(asOf.AddDate(0, 0, 1)))
+}
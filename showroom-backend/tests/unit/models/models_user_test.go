@@ -0,0 +1,248 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermissions_Catalogue(t *testing.T) {
+	seen := map[string]bool{}
+	for _, permission := range user.Permissions {
+		assert.False(t, seen[permission.Code], "duplicate permission %s", permission.Code)
+		assert.NotEmpty(t, permission.Description, permission.Code)
+		seen[permission.Code] = true
+	}
+
+	assert.True(t, user.IsValidPermission(user.PermPurchaseOrderApprove))
+	assert.False(t, user.IsValidPermission("purchase_order.delete"))
+	assert.Len(t, user.AllPermissionCodes(), len(user.Permissions))
+
+	assert.True(t, user.RoleHasAllPermissions(common.RoleAdmin))
+	assert.False(t, user.RoleHasAllPermissions(common.RoleManager))
+}
+
+func TestRolePermissionsUpdateRequest_Validate(t *testing.T) {
+	req := &user.RolePermissionsUpdateRequest{Permissions: []string{user.PermStockAdjust, user.PermProductRead, user.PermStockAdjust}}
+	codes, err := req.Validate()
+	require.NoError(t, err)
+	assert.Equal(t, []string{user.PermProductRead, user.PermStockAdjust}, codes)
+
+	codes, err = (&user.RolePermissionsUpdateRequest{Permissions: []string{}}).Validate()
+	require.NoError(t, err)
+	assert.Empty(t, codes, "a role can be stripped of every permission")
+
+	_, err = (&user.RolePermissionsUpdateRequest{Permissions: []string{user.PermProductRead, "stock.delete"}}).Validate()
+	assert.EqualError(t, err, "unknown permission: stock.delete")
+}
+
+func TestRefreshToken_Check(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	token := &user.RefreshToken{ExpiresAt: now.Add(time.Hour)}
+
+	assert.NoError(t, token.Check(true, now))
+	assert.ErrorIs(t, token.Check(false, now), user.ErrSessionRevoked)
+	assert.ErrorIs(t, token.Check(true, now.Add(time.Hour)), user.ErrRefreshTokenExpired)
+
+	rotatedAt := now.Add(-time.Minute)
+	token.RotatedAt = &rotatedAt
+	assert.ErrorIs(t, token.Check(true, now), user.ErrRefreshTokenReused)
+	assert.ErrorIs(t, token.Check(false, now.Add(2*time.Hour)), user.ErrRefreshTokenReused,
+		"a replayed token is reported even after its session ended")
+}
+
+func TestLoginChallenge_Check(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	challenge := &user.LoginChallenge{ExpiresAt: now.Add(user.LoginChallengeLifetime)}
+
+	assert.NoError(t, challenge.Check(now))
+	assert.ErrorIs(t, challenge.Check(challenge.ExpiresAt), user.ErrLoginChallengeExpired)
+
+	challenge.Attempts = user.LoginChallengeMaxAttempts
+	assert.ErrorIs(t, challenge.Check(now), user.ErrLoginChallengeExhausted)
+
+	usedAt := now
+	challenge.UsedAt = &usedAt
+	assert.ErrorIs(t, challenge.Check(now), user.ErrLoginChallengeInvalid)
+}
+
+func TestTwoFactorPolicy(t *testing.T) {
+	policy, err := user.NewTwoFactorPolicy([]string{"admin", "manager"})
+	require.NoError(t, err)
+	assert.True(t, policy.Requires(common.RoleAdmin))
+	assert.True(t, policy.Requires(common.RoleManager))
+	assert.False(t, policy.Requires(common.RoleCashier))
+
+	policy, err = user.NewTwoFactorPolicy(nil)
+	require.NoError(t, err)
+	assert.False(t, policy.Requires(common.RoleAdmin))
+
+	_, err = user.NewTwoFactorPolicy([]string{"admin", "owner"})
+	assert.EqualError(t, err, "invalid role: owner")
+}
+
+func TestRecoveryCodeFormatting(t *testing.T) {
+	assert.Equal(t, "a1b2c-3d4e5", user.FormatRecoveryCode("a1b2c3d4e5"))
+	assert.Equal(t, "a1b2c3d4e5", user.NormalizeRecoveryCode(" A1B2C-3D4E5 "))
+	assert.Equal(t, "a1b2c3d4e5", user.NormalizeRecoveryCode(user.FormatRecoveryCode("a1b2c3d4e5")))
+}
+
+func TestLoginThrottlePolicy_Delay(t *testing.T) {
+	policy := user.LoginThrottlePolicy{}
+
+	assert.Zero(t, policy.Delay(0))
+	assert.Zero(t, policy.Delay(2), "the first typos cost nothing")
+	assert.Equal(t, time.Second, policy.Delay(3))
+	assert.Equal(t, 2*time.Second, policy.Delay(4))
+	assert.Equal(t, 16*time.Second, policy.Delay(7))
+	assert.Equal(t, 30*time.Second, policy.Delay(50))
+}
+
+func TestLoginThrottlePolicy_RetryAfter(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	policy := user.LoginThrottlePolicy{MaxFailures: 5, Lockout: 15 * time.Minute, IPMaxFailures: 20, IPWindow: 15 * time.Minute}
+
+	wait, outcome := policy.RetryAfter(nil, 0, nil, now)
+	assert.Zero(t, wait)
+	assert.Empty(t, outcome)
+
+	lockedUntil := now.Add(10 * time.Minute)
+	wait, outcome = policy.RetryAfter(&user.LoginLock{FailedCount: 5, LockedUntil: &lockedUntil}, 0, nil, now)
+	assert.Equal(t, 10*time.Minute, wait)
+	assert.Equal(t, user.LoginOutcomeLocked, outcome)
+
+	lastFailed := now.Add(-time.Second)
+	wait, outcome = policy.RetryAfter(&user.LoginLock{FailedCount: 4, LastFailedAt: &lastFailed}, 0, nil, now)
+	assert.Equal(t, time.Second, wait, "the fourth failure costs two seconds, one has passed")
+	assert.Equal(t, user.LoginOutcomeThrottled, outcome)
+
+	expired := now.Add(-time.Minute)
+	wait, _ = policy.RetryAfter(&user.LoginLock{FailedCount: 5, LastFailedAt: &lastFailed, LockedUntil: &expired}, 0, nil, now.Add(time.Minute))
+	assert.Zero(t, wait, "an expired lock lets the user try again")
+
+	lastIPFailure := now.Add(-5 * time.Minute)
+	wait, outcome = policy.RetryAfter(nil, 20, &lastIPFailure, now)
+	assert.Equal(t, 10*time.Minute, wait, "an IP address over its limit waits out the window")
+	assert.Equal(t, user.LoginOutcomeThrottled, outcome)
+
+	wait, _ = policy.RetryAfter(nil, 3, &lastIPFailure, now)
+	assert.Zero(t, wait)
+}
+
+func TestLoginBlockedError(t *testing.T) {
+	err := &user.LoginBlockedError{Outcome: user.LoginOutcomeLocked, RetryAfter: 90*time.Second + time.Millisecond}
+	assert.Equal(t, 91, err.RetryAfterSeconds())
+	assert.EqualError(t, err, "account is locked after too many failed logins, try again in 91 seconds")
+
+	err = &user.LoginBlockedError{Outcome: user.LoginOutcomeThrottled, RetryAfter: 2 * time.Second}
+	assert.EqualError(t, err, "too many failed logins, try again in 2 seconds")
+
+	assert.True(t, user.LoginOutcomeFailed.IsFailure())
+	assert.True(t, user.LoginOutcomeTwoFactorFailed.IsFailure())
+	assert.False(t, user.LoginOutcomeThrottled.IsFailure(), "refused attempts do not extend the block")
+}
+
+func TestPasswordResetToken_Check(t *testing.T) {
+	now := time.Now()
+	token := &user.PasswordResetToken{ExpiresAt: now.Add(30 * time.Minute)}
+	assert.NoError(t, token.Check(now))
+
+	assert.ErrorIs(t, token.Check(now.Add(30*time.Minute)), user.ErrPasswordResetTokenInvalid)
+
+	usedAt := now.Add(-time.Minute)
+	token.UsedAt = &usedAt
+	assert.ErrorIs(t, token.Check(now), user.ErrPasswordResetTokenInvalid, "a token works only once")
+}
+
+func TestAPIKey_Check(t *testing.T) {
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+	key := &user.APIKey{ExpiresAt: &expiresAt, AllowedIPs: []string{"10.0.0.0/24", "192.168.1.7"}}
+
+	assert.NoError(t, key.Check(now, "10.0.0.42"))
+	assert.NoError(t, key.Check(now, "192.168.1.7"))
+	assert.ErrorIs(t, key.Check(now, "192.168.1.8"), user.ErrAPIKeyIPNotAllowed)
+	assert.ErrorIs(t, key.Check(now, "not-an-ip"), user.ErrAPIKeyIPNotAllowed)
+	assert.ErrorIs(t, key.Check(expiresAt, "10.0.0.42"), user.ErrAPIKeyExpired)
+
+	revokedAt := now.Add(-time.Minute)
+	key.RevokedAt = &revokedAt
+	assert.ErrorIs(t, key.Check(now, "10.0.0.42"), user.ErrAPIKeyRevoked)
+
+	open := &user.APIKey{}
+	assert.NoError(t, open.Check(now, "203.0.113.9"), "an empty allowlist allows any address")
+}
+
+func TestAPIKeyCreateRequest_Validate(t *testing.T) {
+	now := time.Now()
+	req := &user.APIKeyCreateRequest{
+		Name:       "Barcode scanner",
+		Scopes:     []string{user.PermStockRead, user.PermProductRead, user.PermStockRead},
+		AllowedIPs: []string{" 10.0.0.0/24 "},
+	}
+	scopes, err := req.Validate(now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{user.PermProductRead, user.PermStockRead}, scopes)
+	assert.Equal(t, []string{"10.0.0.0/24"}, req.AllowedIPs)
+
+	req.Scopes = []string{"stock.teleport"}
+	_, err = req.Validate(now)
+	assert.EqualError(t, err, "unknown scope: stock.teleport")
+
+	req.Scopes = []string{user.PermAPIKeyManage}
+	_, err = req.Validate(now)
+	assert.Error(t, err, "a key cannot mint other keys")
+
+	req.Scopes = []string{user.PermStockRead}
+	req.AllowedIPs = []string{"10.0.0.300"}
+	_, err = req.Validate(now)
+	assert.Error(t, err)
+
+	req.AllowedIPs = nil
+	past := now.Add(-time.Second)
+	req.ExpiresAt = &past
+	_, err = req.Validate(now)
+	assert.EqualError(t, err, "expiry must be in the future")
+}
+
+func TestSessionPolicy(t *testing.T) {
+	policy, err := user.NewSessionPolicy(30*time.Minute, 5, map[string]int{"cashier": 1, "admin": 0})
+	require.NoError(t, err)
+	assert.Equal(t, 1, policy.MaxSessionsFor(common.RoleCashier))
+	assert.Equal(t, 0, policy.MaxSessionsFor(common.RoleAdmin), "a role can be exempt from the default limit")
+	assert.Equal(t, 5, policy.MaxSessionsFor(common.RoleSales))
+
+	_, err = user.NewSessionPolicy(0, 5, map[string]int{"janitor": 1})
+	assert.EqualError(t, err, "invalid role: janitor")
+	_, err = user.NewSessionPolicy(0, -1, nil)
+	assert.Error(t, err)
+
+	now := time.Now()
+	session := &user.UserSession{LastActivityAt: now.Add(-30 * time.Minute)}
+	assert.True(t, session.IsIdle(policy.IdleTimeout, now))
+	assert.False(t, session.IsIdle(policy.IdleTimeout, now.Add(-time.Second)))
+	assert.False(t, session.IsIdle(0, now), "a zero timeout never expires sessions")
+}
+
+func TestPasswordResetRequests_RetryAfter(t *testing.T) {
+	now := time.Now()
+	oldest := now.Add(-40 * time.Minute)
+
+	under := &user.PasswordResetRequests{Username: user.PasswordResetMaxRequests - 1, UsernameOldest: &oldest}
+	assert.Zero(t, under.RetryAfter(now))
+
+	byUsername := &user.PasswordResetRequests{Username: user.PasswordResetMaxRequests, UsernameOldest: &oldest}
+	assert.Equal(t, 20*time.Minute, byUsername.RetryAfter(now))
+
+	ipOldest := now.Add(-10 * time.Minute)
+	byIP := &user.PasswordResetRequests{Username: 1, UsernameOldest: &oldest, IPAddress: user.PasswordResetIPMaxRequests, IPOldest: &ipOldest}
+	assert.Equal(t, 50*time.Minute, byIP.RetryAfter(now), "the longer wait wins")
+
+	expired := now.Add(-2 * user.PasswordResetRequestWindow)
+	stale := &user.PasswordResetRequests{Username: user.PasswordResetMaxRequests, UsernameOldest: &expired}
+	assert.Zero(t, stale.RetryAfter(now))
+}
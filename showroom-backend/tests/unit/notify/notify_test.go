@@ -0,0 +1,47 @@
+package notify_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/config"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileNotifier_Send(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail", "outbox.log")
+	notifier := notify.NewFileNotifier(path)
+
+	require.NoError(t, notifier.Send(context.Background(), notify.Message{
+		To: "alice@example.com", Subject: "Reset your password", Body: "first",
+	}))
+	require.NoError(t, notifier.Send(context.Background(), notify.Message{
+		To: "bob@example.com", Subject: "Reset your password", Body: "second",
+	}))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "To: alice@example.com\nSubject: Reset your password\n\nfirst\n")
+	assert.Contains(t, string(content), "To: bob@example.com\nSubject: Reset your password\n\nsecond\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, notifier.Send(ctx, notify.Message{To: "carol@example.com"}))
+}
+
+func TestNew(t *testing.T) {
+	notifier, err := notify.New(config.NotifierConfig{Driver: "file", FilePath: "mail.log"})
+	require.NoError(t, err)
+	assert.IsType(t, &notify.FileNotifier{}, notifier)
+
+	notifier, err = notify.New(config.NotifierConfig{Driver: "smtp", SMTPHost: "localhost", SMTPPort: "25"})
+	require.NoError(t, err)
+	assert.IsType(t, &notify.SMTPNotifier{}, notifier)
+
+	_, err = notify.New(config.NotifierConfig{Driver: "pigeon"})
+	assert.Error(t, err)
+}
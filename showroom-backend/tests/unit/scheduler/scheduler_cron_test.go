@@ -0,0 +1,83 @@
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1,,2 * * * *",
+		"@fortnightly",
+	} {
+		_, err := scheduler.ParseSchedule(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	from := time.Date(2024, 6, 14, 10, 7, 30, 0, time.UTC) // Friday
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, 6, 14, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 6, 14, 10, 15, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2024, 6, 14, 10, 25, 0, 0, time.UTC)},
+		{"0 1 * * *", time.Date(2024, 6, 15, 1, 0, 0, 0, time.UTC)},
+		{"30 9-17 * * 1-5", time.Date(2024, 6, 14, 10, 30, 0, 0, time.UTC)},
+		{"0 6 * * 1", time.Date(2024, 6, 17, 6, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 6, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		schedule, err := scheduler.ParseSchedule(tt.spec)
+		require.NoError(t, err, tt.spec)
+		assert.Equal(t, tt.want, schedule.Next(from), tt.spec)
+		assert.True(t, schedule.Matches(tt.want), tt.spec)
+	}
+}
+
+func TestSchedule_DayOfMonthOrWeekday(t *testing.T) {
+	// With both day fields restricted, either one matching is enough
+	schedule := scheduler.MustParseSchedule("0 0 13 * 5")
+
+	assert.True(t, schedule.Matches(time.Date(2024, 6, 13, 0, 0, 0, 0, time.UTC)))  // Thursday the 13th
+	assert.True(t, schedule.Matches(time.Date(2024, 6, 7, 0, 0, 0, 0, time.UTC)))   // Friday
+	assert.False(t, schedule.Matches(time.Date(2024, 6, 12, 0, 0, 0, 0, time.UTC))) // Wednesday the 12th
+	assert.Equal(t, time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC), schedule.Next(time.Date(2024, 6, 13, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestSchedule_NextKeepsLocation(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	schedule := scheduler.MustParseSchedule("0 1 * * *")
+
+	next := schedule.Next(time.Date(2024, 6, 14, 23, 0, 0, 0, jakarta))
+
+	assert.Equal(t, time.Date(2024, 6, 15, 1, 0, 0, 0, jakarta), next)
+	assert.Equal(t, "0 1 * * *", schedule.String())
+}
+
+func TestSchedule_NeverFires(t *testing.T) {
+	schedule := scheduler.MustParseSchedule("0 0 30 2 *")
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
@@ -0,0 +1,66 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/products"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+	productService "github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services/products"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePurchaseOrders struct {
+	interfaces.PurchaseOrderPartsRepository
+	po *products.PurchaseOrderParts
+}
+
+func (f *fakePurchaseOrders) GetByID(ctx context.Context, id int) (*products.PurchaseOrderParts, error) {
+	return f.po, nil
+}
+
+type fakePODetails struct {
+	interfaces.PurchaseOrderDetailRepository
+	detail *products.PurchaseOrderDetail
+}
+
+func (f *fakePODetails) GetByID(ctx context.Context, id int) (*products.PurchaseOrderDetail, error) {
+	return f.detail, nil
+}
+
+type fakeReceipts struct {
+	interfaces.GoodsReceiptRepository
+	receipt *products.GoodsReceipt
+}
+
+func (f *fakeReceipts) GetByID(ctx context.Context, id int) (*products.GoodsReceipt, error) {
+	return f.receipt, nil
+}
+
+func TestGoodsReceiptService_RejectsBlanketOrders(t *testing.T) {
+	for _, poType := range []products.POType{products.POTypeBlanket, products.POTypeContract} {
+		po := &products.PurchaseOrderParts{POID: 1, POType: poType}
+		receipts := &fakeReceipts{receipt: &products.GoodsReceipt{ReceiptID: 5, POID: 1}}
+		details := &fakePODetails{detail: &products.PurchaseOrderDetail{PODetailID: 9, POID: 1, ProductID: 3, QuantityOrdered: 10}}
+		service := productService.NewGoodsReceiptService(receipts, nil, &fakePurchaseOrders{po: po}, details, nil, nil, nil, nil, nil)
+
+		_, err := service.CreateGoodsReceipt(context.Background(), &products.GoodsReceiptCreateRequest{POID: 1, ReceiptDate: time.Now()}, 1)
+		assert.ErrorContains(t, err, "receive against its release orders", poType)
+
+		_, err = service.AddReceiptDetail(context.Background(), 5, &products.GoodsReceiptDetailCreateRequest{
+			PODetailID: 9, ProductID: 3, QuantityReceived: 2, QuantityAccepted: 2,
+		})
+		assert.ErrorContains(t, err, "receive against its release orders", poType)
+	}
+}
+
+func TestGoodsReceiptService_ProcessesReceiptOnce(t *testing.T) {
+	for _, status := range []products.ReceiptStatus{products.ReceiptStatusComplete, products.ReceiptStatusWithDiscrepancy} {
+		receipts := &fakeReceipts{receipt: &products.GoodsReceipt{ReceiptID: 5, ReceiptNumber: "GR-5", ReceiptStatus: status}}
+		service := productService.NewGoodsReceiptService(receipts, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		err := service.ProcessGoodsReceipt(context.Background(), 5, 1)
+		assert.ErrorContains(t, err, "already been processed", status)
+	}
+}
@@ -0,0 +1,34 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/user"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/repositories/interfaces"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/services"
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSigningKeys struct {
+	interfaces.SigningKeyRepository
+	retireAfter time.Duration
+}
+
+func (f *fakeSigningKeys) Rotate(ctx context.Context, key *user.SigningKey, retireAfter time.Duration) error {
+	f.retireAfter = retireAfter
+	return nil
+}
+
+func TestSigningKeyService_RotateOutlivesKeyReload(t *testing.T) {
+	repo := &fakeSigningKeys{}
+	service := services.NewSigningKeyService(repo, "EdDSA", "secret", 15*time.Minute, 30*24*time.Hour)
+
+	_, err := service.Rotate(context.Background())
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, repo.retireAfter, 15*time.Minute+utils.KeyReloadInterval,
+		"servers that have not reloaded keep signing with the rotated key")
+}
@@ -0,0 +1,31 @@
+package utils_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, err := utils.GenerateAPIKey()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, "sk_"+prefix+"_"))
+
+	parsed, ok := utils.ParseAPIKeyPrefix(key)
+	assert.True(t, ok)
+	assert.Equal(t, prefix, parsed)
+
+	other, _, err := utils.GenerateAPIKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+}
+
+func TestParseAPIKeyPrefix_Malformed(t *testing.T) {
+	for _, key := range []string{"", "sk_abc", "pk_0123abcd_" + strings.Repeat("a", 64), "sk_0123abcd_short"} {
+		_, ok := utils.ParseAPIKeyPrefix(key)
+		assert.False(t, ok, key)
+	}
+}
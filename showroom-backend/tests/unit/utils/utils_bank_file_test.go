@@ -0,0 +1,42 @@
+package utils_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFixedWidthLayout(t *testing.T) {
+	layout, err := utils.ParseFixedWidthLayout("bank_account:10, amount_cents:8:right:0 ,currency:3:left")
+	require.NoError(t, err)
+	assert.Equal(t, []utils.FixedWidthField{
+		{Name: "bank_account", Width: 10, Pad: ' '},
+		{Name: "amount_cents", Width: 8, AlignRight: true, Pad: '0'},
+		{Name: "currency", Width: 3, Pad: ' '},
+	}, layout)
+
+	for _, spec := range []string{"", "amount", "amount:0", "amount:x", "amount:5:center", "amount:5:right:00", ":5"} {
+		_, err := utils.ParseFixedWidthLayout(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestWriteFixedWidth(t *testing.T) {
+	layout, err := utils.ParseFixedWidthLayout("name:5,amount:6:right:0")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = utils.WriteFixedWidth(&buf, layout, []map[string]string{
+		{"name": "Alpha Motor", "amount": "1250"},
+		{"name": "Béta", "amount": "7"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Alpha001250\r\nBéta 000007\r\n", buf.String())
+
+	// Amounts are never truncated
+	err = utils.WriteFixedWidth(&buf, layout, []map[string]string{{"name": "A", "amount": "1234567"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,50 @@
+package utils_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := utils.WriteCSV(&buf, []string{"Name", "Amount"}, [][]interface{}{
+		{"Alpha, Inc", 1250.5},
+		{"Beta", 3},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Name,Amount\n\"Alpha, Inc\",1250.50\nBeta,3\n", buf.String())
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	err := utils.WriteXLSX(&buf, "AP Aging", []string{"Name", "Amount"}, [][]interface{}{
+		{"Alpha & Sons", 1250.5},
+	})
+	require.NoError(t, err)
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		reader.Close()
+		files[file.Name] = string(content)
+	}
+
+	assert.Contains(t, files, "[Content_Types].xml")
+	assert.Contains(t, files["xl/workbook.xml"], `name="AP Aging"`)
+	sheet := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c r="A2" t="inlineStr"><is><t>Alpha &amp; Sons</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="B2"><v>1250.50</v></c>`)
+}
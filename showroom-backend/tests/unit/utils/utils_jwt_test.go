@@ -1,6 +1,7 @@
 package utils_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/models/common"
 	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestJWTManager_GenerateToken(t *testing.T) {
@@ -74,4 +76,91 @@ func TestJWTManager_ValidateToken_ExpiredToken(t *testing.T) {
 	_, err = jwtManager.ValidateToken(token)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "expired")
+}
+
+type fakeKeySource struct {
+	keys []utils.SigningKey
+}
+
+func (f *fakeKeySource) SigningKeys(ctx context.Context) ([]utils.SigningKey, error) {
+	return f.keys, nil
+}
+
+func newSigningKey(t *testing.T, id, algorithm string) utils.SigningKey {
+	privateKey, err := utils.GenerateSigningKey(algorithm)
+	require.NoError(t, err)
+	return utils.SigningKey{KeyID: id, Algorithm: algorithm, PrivateKey: privateKey}
+}
+
+func TestJWTManager_KeySource(t *testing.T) {
+	claims := &auth.TokenClaims{UserID: 1, Username: "testuser", Role: common.RoleAdmin, SessionID: 123}
+
+	for _, algorithm := range []string{utils.AlgorithmRS256, utils.AlgorithmEdDSA} {
+		source := &fakeKeySource{keys: []utils.SigningKey{newSigningKey(t, "key-1", algorithm)}}
+		jwtManager := utils.NewKeySourceJWTManager(source, time.Hour)
+		require.NoError(t, jwtManager.Reload(context.Background()))
+
+		token, err := jwtManager.GenerateToken(claims)
+		require.NoError(t, err, algorithm)
+
+		validatedClaims, err := jwtManager.ValidateToken(token)
+		require.NoError(t, err, algorithm)
+		assert.Equal(t, claims.UserID, validatedClaims.UserID)
+		assert.Equal(t, claims.SessionID, validatedClaims.SessionID)
+
+		_, err = utils.NewJWTManager("test-secret", time.Hour).ValidateToken(token)
+		assert.Error(t, err, "a shared secret cannot validate %s tokens", algorithm)
+	}
+}
+
+func TestJWTManager_KeyRotation(t *testing.T) {
+	claims := &auth.TokenClaims{UserID: 1, SessionID: 123}
+	oldKey := newSigningKey(t, "key-1", utils.AlgorithmRS256)
+	source := &fakeKeySource{keys: []utils.SigningKey{oldKey}}
+	jwtManager := utils.NewKeySourceJWTManager(source, time.Hour)
+	require.NoError(t, jwtManager.Reload(context.Background()))
+
+	oldToken, err := jwtManager.GenerateToken(claims)
+	require.NoError(t, err)
+
+	// Rotate: the new key signs, the old one still validates
+	retiresAt := time.Now().Add(time.Hour)
+	oldKey.RetiresAt = &retiresAt
+	source.keys = []utils.SigningKey{newSigningKey(t, "key-2", utils.AlgorithmEdDSA), oldKey}
+	require.NoError(t, jwtManager.Reload(context.Background()))
+
+	newToken, err := jwtManager.GenerateToken(claims)
+	require.NoError(t, err)
+	_, err = jwtManager.ValidateToken(newToken)
+	assert.NoError(t, err)
+	_, err = jwtManager.ValidateToken(oldToken)
+	assert.NoError(t, err, "tokens of a rotated key stay valid until it retires")
+
+	jwks, err := jwtManager.JWKS()
+	require.NoError(t, err)
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "key-2", jwks.Keys[0].KeyID)
+	assert.Equal(t, "EdDSA", jwks.Keys[0].Algorithm)
+	assert.Equal(t, "key-1", jwks.Keys[1].KeyID)
+
+	// Retire the old key
+	retiresAt = time.Now().Add(-time.Second)
+	_, err = jwtManager.ValidateToken(oldToken)
+	assert.Error(t, err, "tokens of a retired key are refused")
+
+	jwks, err = jwtManager.JWKS()
+	require.NoError(t, err)
+	assert.Len(t, jwks.Keys, 1)
+}
+
+func TestJWTManager_KeySourceWithoutKeys(t *testing.T) {
+	jwtManager := utils.NewKeySourceJWTManager(&fakeKeySource{}, time.Hour)
+	assert.Error(t, jwtManager.Reload(context.Background()))
+
+	_, err := jwtManager.GenerateToken(&auth.TokenClaims{UserID: 1})
+	assert.Error(t, err)
+
+	jwks, err := utils.NewJWTManager("test-secret", time.Hour).JWKS()
+	require.NoError(t, err)
+	assert.Empty(t, jwks.Keys, "shared secrets are not published")
 }
\ No newline at end of file
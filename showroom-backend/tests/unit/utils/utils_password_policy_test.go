@@ -0,0 +1,59 @@
+package utils_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	policy := utils.PasswordPolicy{
+		MinLength:    10,
+		RequireUpper: true,
+		RequireLower: true,
+		RequireDigit: true,
+		RejectCommon: true,
+	}
+
+	assert.NoError(t, policy.Validate("Showroom-2026"))
+	assert.Error(t, policy.Validate("Ab1"), "the fixed bounds of IsValidPassword still apply")
+
+	err := policy.Validate("lowercase")
+	var policyErr *utils.PasswordPolicyError
+	require.True(t, errors.As(err, &policyErr))
+	assert.Equal(t, []string{
+		"must be at least 10 characters long",
+		"must contain an uppercase letter",
+		"must contain a digit",
+	}, policyErr.Violations)
+	assert.EqualError(t, err, "password must be at least 10 characters long, must contain an uppercase letter, must contain a digit")
+
+	policy = utils.PasswordPolicy{RejectCommon: true}
+	assert.Error(t, policy.Validate("Password123"), "common passwords are rejected whatever their case")
+	assert.NoError(t, utils.PasswordPolicy{}.Validate("Password123"))
+
+	symbols := utils.PasswordPolicy{RequireSymbol: true}
+	assert.Error(t, symbols.Validate("NoSymbols123"))
+	assert.NoError(t, symbols.Validate("With symbol!"))
+}
+
+func TestPasswordPolicy_IsExpired(t *testing.T) {
+	changedAt := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	policy := utils.PasswordPolicy{MaxAgeDays: 90}
+	assert.False(t, policy.IsExpired(changedAt, changedAt.AddDate(0, 0, 89)))
+	assert.True(t, policy.IsExpired(changedAt, changedAt.AddDate(0, 0, 90)))
+
+	assert.False(t, utils.PasswordPolicy{}.IsExpired(changedAt, changedAt.AddDate(10, 0, 0)), "zero disables expiry")
+}
+
+func TestIsCommonPassword(t *testing.T) {
+	assert.True(t, utils.IsCommonPassword("qwerty"))
+	assert.True(t, utils.IsCommonPassword("LetMeIn"))
+	assert.False(t, utils.IsCommonPassword("correct horse battery staple"))
+	assert.False(t, utils.IsCommonPassword("# Common passwords rejected by the password policy, one per line, compared case-insensitively"))
+}
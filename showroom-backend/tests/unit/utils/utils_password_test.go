@@ -39,6 +39,14 @@ func TestGenerateSecureToken(t *testing.T) {
 	assert.Equal(t, 64, len(token)) // 32 bytes = 64 hex characters
 }
 
+func TestHashToken(t *testing.T) {
+	hash := utils.HashToken("refresh-token")
+
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, utils.HashToken("refresh-token"))
+	assert.NotEqual(t, hash, utils.HashToken("other-token"))
+}
+
 func TestGenerateSessionToken(t *testing.T) {
 	token, err := utils.GenerateSessionToken()
 	
@@ -0,0 +1,38 @@
+package utils_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTextPDF(t *testing.T) {
+	lines := make([]string, utils.PDFLinesPerPage+5)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("Line %d (total)", i+1)
+	}
+	lines[0] = "Dü Café — ok"
+
+	var buf bytes.Buffer
+	require.NoError(t, utils.WriteTextPDF(&buf, "Statement", lines))
+	content := buf.String()
+
+	assert.True(t, strings.HasPrefix(content, "%PDF-1.4"))
+	assert.True(t, strings.HasSuffix(content, "%%EOF\n"))
+	assert.Contains(t, content, "/Count 2")
+	assert.Equal(t, 2, strings.Count(content, "/Type /Page "))
+	assert.Contains(t, content, `(Line 2 \(total\)) '`)
+	assert.Contains(t, content, "(D\xfc Caf\xe9 ? ok) '", "Latin-1 is kept and other characters are replaced")
+	assert.Contains(t, content, "(Statement - page 2 of 2) Tj")
+}
+
+func TestWriteTextPDF_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, utils.WriteTextPDF(&buf, "Empty", nil))
+	assert.Contains(t, buf.String(), "/Count 1")
+}
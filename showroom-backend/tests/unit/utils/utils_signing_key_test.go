@@ -0,0 +1,56 @@
+package utils_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningKey_MarshalAndEncrypt(t *testing.T) {
+	for _, algorithm := range []string{utils.AlgorithmRS256, utils.AlgorithmEdDSA} {
+		key, err := utils.GenerateSigningKey(algorithm)
+		require.NoError(t, err, algorithm)
+
+		data, err := utils.MarshalPrivateKey(key)
+		require.NoError(t, err)
+		encrypted, err := utils.EncryptWithSecret(data, "secret")
+		require.NoError(t, err)
+		assert.NotContains(t, encrypted, "PRIVATE KEY")
+
+		_, err = utils.DecryptWithSecret(encrypted, "another-secret")
+		assert.Error(t, err, "a different secret cannot decrypt the key")
+
+		decrypted, err := utils.DecryptWithSecret(encrypted, "secret")
+		require.NoError(t, err)
+		parsed, err := utils.ParsePrivateKey(decrypted)
+		require.NoError(t, err)
+		assert.Equal(t, key.Public(), parsed.Public(), algorithm)
+	}
+
+	_, err := utils.GenerateSigningKey(utils.AlgorithmHS256)
+	assert.Error(t, err)
+}
+
+func TestNewJWK(t *testing.T) {
+	rsaKey, err := utils.GenerateSigningKey(utils.AlgorithmRS256)
+	require.NoError(t, err)
+	jwk, err := utils.NewJWK("rsa-1", utils.AlgorithmRS256, rsaKey.Public())
+	require.NoError(t, err)
+	assert.Equal(t, "RSA", jwk.KeyType)
+	assert.Equal(t, "AQAB", jwk.E, "exponent 65537")
+	assert.NotEmpty(t, jwk.N)
+	assert.IsType(t, &rsa.PublicKey{}, rsaKey.Public())
+
+	edKey, err := utils.GenerateSigningKey(utils.AlgorithmEdDSA)
+	require.NoError(t, err)
+	jwk, err = utils.NewJWK("ed-1", utils.AlgorithmEdDSA, edKey.Public())
+	require.NoError(t, err)
+	assert.Equal(t, "OKP", jwk.KeyType)
+	assert.Equal(t, "Ed25519", jwk.Curve)
+	assert.Len(t, jwk.X, 43, "32 bytes in unpadded base64url")
+	assert.IsType(t, ed25519.PublicKey{}, edKey.Public())
+}
@@ -0,0 +1,75 @@
+package utils_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hafizd-kurniawan/point-of-sale-showroom/showroom-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the RFC 6238 SHA-1 test key "12345678901234567890" in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, expected := range vectors {
+		code, err := utils.TOTPCode(rfcSecret, utils.TOTPStep(time.Unix(unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, expected, code, "time %d", unix)
+	}
+
+	_, err := utils.TOTPCode("not base32!", 1)
+	assert.Error(t, err)
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	step := utils.TOTPStep(now)
+
+	matched, ok := utils.VerifyTOTP(rfcSecret, "081804", now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, step, matched)
+
+	previous, err := utils.TOTPCode(rfcSecret, step-1)
+	require.NoError(t, err)
+	matched, ok = utils.VerifyTOTP(rfcSecret, previous, now, 1)
+	assert.True(t, ok, "a code from the previous step is accepted for clock drift")
+	assert.Equal(t, step-1, matched)
+
+	stale, err := utils.TOTPCode(rfcSecret, step-2)
+	require.NoError(t, err)
+	_, ok = utils.VerifyTOTP(rfcSecret, stale, now, 1)
+	assert.False(t, ok)
+
+	_, ok = utils.VerifyTOTP(rfcSecret, "81804", now, 1)
+	assert.False(t, ok)
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := utils.GenerateTOTPSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32) // 20 bytes = 32 base32 characters
+
+	_, err = utils.TOTPCode(secret, 1)
+	assert.NoError(t, err)
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri, err := url.Parse(utils.TOTPProvisioningURI("Showroom", "admin", rfcSecret))
+	require.NoError(t, err)
+
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Showroom:admin", uri.Path)
+	assert.Equal(t, rfcSecret, uri.Query().Get("secret"))
+	assert.Equal(t, "Showroom", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+}